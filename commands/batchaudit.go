@@ -0,0 +1,258 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jfrog/build-info-go/utils/pythonutils"
+	"github.com/jfrog/frogbot/commands/utils"
+	coreconfig "github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	_go "github.com/jfrog/jfrog-cli-core/v2/xray/audit/go"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/audit/java"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/audit/npm"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/audit/nuget"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/audit/python"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/audit/yarn"
+	xraycommands "github.com/jfrog/jfrog-cli-core/v2/xray/commands"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+)
+
+// The ID of the root node, which groups the dependency trees of a batch into a single graph
+const batchRootId = "frogbot-scan-batch"
+
+// The dependency trees of all the modules of a single technology
+type technologyTrees struct {
+	technology coreutils.Technology
+	trees      []*services.GraphNode
+}
+
+// batchAudit audits the given working directories like audit.GenericAudit, but scans the dependency trees of up to batchSize modules
+// of the same technology in a single Xray graph scan, instead of a graph scan per module.
+func batchAudit(xrayScanParams services.XrayGraphScanParams, project *utils.Project, server *coreconfig.ServerDetails,
+	batchSize int, workDirs []string) (results []services.ScanResponse, isMultipleRoot bool, err error) {
+	techTrees, errorList := buildDependencyTrees(project, workDirs)
+	results, isMultipleRoot, scanErrors, err := scanDependencyTrees(xrayScanParams, server, batchSize, techTrees, project.ScanCache)
+	if err != nil {
+		return nil, false, err
+	}
+	errorList = append(errorList, scanErrors...)
+	if len(errorList) > 0 {
+		err = errors.New(strings.Join(errorList, "\n"))
+	}
+	return
+}
+
+// Scan the dependency trees of each technology, up to batchSize modules in a single Xray graph scan. The errors of the failed graph scans are returned in errorList.
+// If the scan cache isn't nil, the graphs whose results are cached aren't scanned again.
+func scanDependencyTrees(xrayScanParams services.XrayGraphScanParams, server *coreconfig.ServerDetails, batchSize int,
+	techTrees []*technologyTrees, cache *utils.ScanCache) (results []services.ScanResponse, isMultipleRoot bool, errorList []string, err error) {
+	var modulesCount, scansCount int
+	if len(techTrees) > 0 {
+		xrayVersion, e := getGraphScanXrayVersion(server)
+		if e != nil {
+			return nil, false, nil, e
+		}
+		for _, techTree := range techTrees {
+			isMultipleRoot = isMultipleRoot || len(techTree.trees) > 1
+			for _, batch := range splitToBatches(techTree.trees, batchSize) {
+				modulesCount += len(batch)
+				scansCount++
+				batchResults, e := scanBatch(xrayScanParams, server, xrayVersion, techTree.technology, batch, cache)
+				if e != nil {
+					errorList = append(errorList, fmt.Sprintf("'%s' audit command failed:\n%s", techTree.technology, e.Error()))
+					continue
+				}
+				results = append(results, *batchResults)
+			}
+		}
+	}
+	log.Info(fmt.Sprintf("Scanned %d modules in %d Xray graph scans, saving %d graph scans", modulesCount, scansCount, modulesCount-scansCount))
+	return
+}
+
+// Build the dependency trees of the technologies detected in the working directories, grouped by technology
+func buildDependencyTrees(project *utils.Project, workDirs []string) (techTrees []*technologyTrees, errorList []string) {
+	techIndexes := make(map[coreutils.Technology]int)
+	for _, wd := range workDirs {
+		restoreDir, err := utils.Chdir(wd)
+		if err != nil {
+			errorList = append(errorList, fmt.Sprintf("the audit command couldn't change the current working directory to the following path: %s\n%s", wd, err.Error()))
+			continue
+		}
+		log.Info("Auditing project: " + wd)
+		technologies, err := coreutils.DetectTechnologies(wd, false, false)
+		if err == nil && len(technologies) == 0 {
+			err = errors.New("could not determine the package manager / build tool used by this project")
+		}
+		if err != nil {
+			errorList = append(errorList, fmt.Sprintf("audit command in %s failed:\n%s", wd, err.Error()))
+		}
+		for _, tech := range coreutils.ToTechnologies(coreutils.DetectedTechnologiesToSlice(technologies)) {
+			if !project.IsEcosystemScanned(tech) {
+				log.Info(fmt.Sprintf("Skipping the %s dependencies of %s, since %s isn't in the ecosystems of the project", tech.ToFormal(), wd, tech))
+				continue
+			}
+			if tech == coreutils.Dotnet {
+				// The .NET CLI projects are detected, but their dependency trees can't be built, so they are skipped rather than failing the audit
+				log.Warn(fmt.Sprintf("Skipping the %s dependencies of %s, since the %s technology isn't supported", tech.ToFormal(), wd, tech.ToFormal()))
+				continue
+			}
+			trees, e := buildTechnologyTrees(project, tech)
+			if e != nil {
+				errorList = append(errorList, fmt.Sprintf("'%s' audit command in %s failed:\n%s", tech, wd, e.Error()))
+				continue
+			}
+			if len(trees) == 0 {
+				continue
+			}
+			index, exists := techIndexes[tech]
+			if !exists {
+				index = len(techTrees)
+				techIndexes[tech] = index
+				techTrees = append(techTrees, &technologyTrees{technology: tech})
+			}
+			techTrees[index].trees = append(techTrees[index].trees, trees...)
+		}
+		if err = restoreDir(); err != nil {
+			errorList = append(errorList, err.Error())
+		}
+	}
+	return
+}
+
+func buildTechnologyTrees(project *utils.Project, tech coreutils.Technology) ([]*services.GraphNode, error) {
+	switch tech {
+	case coreutils.Maven:
+		return java.BuildMvnDependencyTree(false, false)
+	case coreutils.Gradle:
+		return java.BuildGradleDependencyTree(false, project.UseWrapper, false)
+	case coreutils.Npm:
+		return npm.BuildDependencyTree(nil)
+	case coreutils.Yarn:
+		return yarn.BuildDependencyTree()
+	case coreutils.Go:
+		return buildGoDependencyTrees()
+	case coreutils.Pipenv, coreutils.Pip, coreutils.Poetry:
+		return python.BuildDependencyTree(pythonutils.PythonTool(tech), project.PipRequirementsFile)
+	case coreutils.Nuget:
+		return nuget.BuildDependencyTree()
+	default:
+		return nil, errors.New(string(tech) + " is currently not supported")
+	}
+}
+
+// Build the Go dependency trees of the current working directory, with the modules replaced by the replace directives of its go.mod file
+func buildGoDependencyTrees() ([]*services.GraphNode, error) {
+	trees, err := _go.BuildDependencyTree()
+	if err != nil {
+		return nil, err
+	}
+	replaces, err := getGoModReplaces(".")
+	if err != nil {
+		return nil, err
+	}
+	applyGoModReplaces(trees, replaces)
+	return trees, nil
+}
+
+// Return the version of Xray, after verifying that it supports graph scans
+func getGraphScanXrayVersion(server *coreconfig.ServerDetails) (string, error) {
+	xrayManager, err := utils.NewXrayServiceManager(server)
+	if err != nil {
+		return "", err
+	}
+	xrayVersion, err := xrayManager.GetVersion()
+	if err != nil {
+		return "", err
+	}
+	return xrayVersion, coreutils.ValidateMinimumVersion(coreutils.Xray, xrayVersion, xraycommands.GraphScanMinXrayVersion)
+}
+
+func splitToBatches(trees []*services.GraphNode, batchSize int) (batches [][]*services.GraphNode) {
+	for start := 0; start < len(trees); start += batchSize {
+		end := start + batchSize
+		if end > len(trees) {
+			end = len(trees)
+		}
+		batches = append(batches, trees[start:end])
+	}
+	return
+}
+
+// runScanGraph runs the graph scan like xraycommands.RunScanGraphAndGetResults, using an Xray client which sends the custom headers
+func runScanGraph(xrayScanParams services.XrayGraphScanParams, server *coreconfig.ServerDetails, xrayVersion string) (*services.ScanResponse, error) {
+	xrayManager, err := utils.NewXrayServiceManager(server)
+	if err != nil {
+		return nil, err
+	}
+	if coreutils.ValidateMinimumVersion(coreutils.Xray, xrayVersion, xraycommands.ScanTypeMinXrayVersion) != nil {
+		// The scan type isn't supported by older Xray versions
+		xrayScanParams.ScanType = ""
+	}
+	scanId, err := xrayManager.ScanGraph(xrayScanParams)
+	if err != nil {
+		return nil, err
+	}
+	return xrayManager.GetScanGraphResults(scanId, xrayScanParams.IncludeVulnerabilities, xrayScanParams.IncludeLicenses)
+}
+
+// Scan the dependency trees of the batch in a single Xray graph scan, unless the results of the graph are found in the scan cache.
+// The root node of the batch is removed from the impact paths, so the results are identical to the results of scanning each tree separately.
+func scanBatch(xrayScanParams services.XrayGraphScanParams, server *coreconfig.ServerDetails, xrayVersion string,
+	technology coreutils.Technology, batch []*services.GraphNode, cache *utils.ScanCache) (*services.ScanResponse, error) {
+	xrayScanParams.Graph = batch[0]
+	if len(batch) > 1 {
+		xrayScanParams.Graph = &services.GraphNode{Id: batchRootId, Nodes: batch}
+	}
+	scanResults, err := runCachedScanGraph(xrayScanParams, server, xrayVersion, cache)
+	if err != nil {
+		return nil, err
+	}
+	for i := range scanResults.Vulnerabilities {
+		scanResults.Vulnerabilities[i].Technology = technology.ToString()
+		removeBatchRoot(scanResults.Vulnerabilities[i].Components)
+	}
+	for i := range scanResults.Violations {
+		scanResults.Violations[i].Technology = technology.ToString()
+		removeBatchRoot(scanResults.Violations[i].Components)
+	}
+	for i := range scanResults.Licenses {
+		removeBatchRoot(scanResults.Licenses[i].Components)
+	}
+	return scanResults, nil
+}
+
+// Return the cached results of the graph scan, or run the graph scan and cache its results. The results are cached before they're modified by the caller.
+func runCachedScanGraph(xrayScanParams services.XrayGraphScanParams, server *coreconfig.ServerDetails, xrayVersion string, cache *utils.ScanCache) (*services.ScanResponse, error) {
+	if cache == nil {
+		return runScanGraph(xrayScanParams, server, xrayVersion)
+	}
+	cacheKey, err := utils.GetScanCacheKey(server, xrayScanParams)
+	if err != nil {
+		return nil, err
+	}
+	if scanResults := cache.Get(cacheKey); scanResults != nil {
+		log.Info("Using the cached results of the graph scan of", xrayScanParams.Graph.Id)
+		return scanResults, nil
+	}
+	scanResults, err := runScanGraph(xrayScanParams, server, xrayVersion)
+	if err != nil {
+		return nil, err
+	}
+	cache.Add(cacheKey, scanResults)
+	return scanResults, nil
+}
+
+func removeBatchRoot(components map[string]services.Component) {
+	for componentId, component := range components {
+		for i, impactPath := range component.ImpactPaths {
+			if len(impactPath) > 1 && impactPath[0].ComponentId == batchRootId {
+				component.ImpactPaths[i] = impactPath[1:]
+			}
+		}
+		components[componentId] = component
+	}
+}
@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	coreconfig "github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitToBatches(t *testing.T) {
+	trees := []*services.GraphNode{{Id: "npm://a:1.0.0"}, {Id: "npm://b:1.0.0"}, {Id: "npm://c:1.0.0"}}
+	batches := splitToBatches(trees, 2)
+	if assert.Len(t, batches, 2) {
+		assert.Equal(t, trees[:2], batches[0])
+		assert.Equal(t, trees[2:], batches[1])
+	}
+	assert.Len(t, splitToBatches(trees, 5), 1)
+	assert.Empty(t, splitToBatches(nil, 2))
+}
+
+func TestRemoveBatchRoot(t *testing.T) {
+	components := map[string]services.Component{
+		"npm://lodash:4.17.19": {ImpactPaths: [][]services.ImpactPathNode{
+			{{ComponentId: batchRootId}, {ComponentId: "npm://a:1.0.0"}, {ComponentId: "npm://lodash:4.17.19"}},
+			{{ComponentId: "npm://b:1.0.0"}, {ComponentId: "npm://lodash:4.17.19"}},
+		}},
+	}
+	removeBatchRoot(components)
+	assert.Equal(t, [][]services.ImpactPathNode{
+		{{ComponentId: "npm://a:1.0.0"}, {ComponentId: "npm://lodash:4.17.19"}},
+		{{ComponentId: "npm://b:1.0.0"}, {ComponentId: "npm://lodash:4.17.19"}},
+	}, components["npm://lodash:4.17.19"].ImpactPaths)
+}
+
+func TestScanBatchCached(t *testing.T) {
+	// Xray isn't reachable, so the results must be read from the cache
+	server := &coreconfig.ServerDetails{XrayUrl: "http://127.0.0.1:1/xray/"}
+	cache := &utils.ScanCache{Dir: t.TempDir(), Ttl: time.Hour, MaxEntries: 10}
+	batch := []*services.GraphNode{{Id: "npm://a:1.0.0"}, {Id: "npm://b:1.0.0"}}
+	xrayScanParams := services.XrayGraphScanParams{Graph: &services.GraphNode{Id: batchRootId, Nodes: batch}}
+	cacheKey, err := utils.GetScanCacheKey(server, xrayScanParams)
+	assert.NoError(t, err)
+	impactPath := []services.ImpactPathNode{{ComponentId: batchRootId}, {ComponentId: "npm://a:1.0.0"}}
+	cache.Add(cacheKey, &services.ScanResponse{Vulnerabilities: []services.Vulnerability{{IssueId: "XRAY-1", Components: map[string]services.Component{
+		"npm://a:1.0.0": {ImpactPaths: [][]services.ImpactPathNode{impactPath}},
+	}}}})
+
+	scanResults, err := scanBatch(services.XrayGraphScanParams{}, server, "3.60.0", coreutils.Npm, batch, cache)
+	assert.NoError(t, err)
+	if assert.Len(t, scanResults.Vulnerabilities, 1) {
+		assert.Equal(t, "npm", scanResults.Vulnerabilities[0].Technology)
+		assert.Equal(t, [][]services.ImpactPathNode{impactPath[1:]}, scanResults.Vulnerabilities[0].Components["npm://a:1.0.0"].ImpactPaths)
+	}
+	// The cached results aren't modified by the removal of the batch root
+	assert.Equal(t, impactPath, cache.Get(cacheKey).Vulnerabilities[0].Components["npm://a:1.0.0"].ImpactPaths[0])
+}
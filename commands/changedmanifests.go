@@ -0,0 +1,200 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+type manifestKind int
+
+const (
+	notManifest manifestKind = iota
+	// A manifest of a package manager whose modules are resolved separately, so only the modules with changed manifests need to be scanned
+	isolatedManifest
+	// A manifest of a package manager whose modules depend on each other, such as Maven parent and child modules, so a change may impact the whole working dir
+	nonIsolatedManifest
+)
+
+var manifestKinds = map[string]manifestKind{
+	"package.json":        isolatedManifest,
+	"package-lock.json":   isolatedManifest,
+	"npm-shrinkwrap.json": isolatedManifest,
+	"yarn.lock":           isolatedManifest,
+	"go.mod":              isolatedManifest,
+	"go.sum":              isolatedManifest,
+	"setup.py":            isolatedManifest,
+	"Pipfile":             isolatedManifest,
+	"Pipfile.lock":        isolatedManifest,
+	"pyproject.toml":      isolatedManifest,
+	"poetry.lock":         isolatedManifest,
+	"pom.xml":             nonIsolatedManifest,
+	"build.gradle":        nonIsolatedManifest,
+	"build.gradle.kts":    nonIsolatedManifest,
+	"settings.gradle":     nonIsolatedManifest,
+	"settings.gradle.kts": nonIsolatedManifest,
+	"packages.config":     nonIsolatedManifest,
+}
+
+func getManifestKind(fileName string) manifestKind {
+	if kind, exists := manifestKinds[fileName]; exists {
+		return kind
+	}
+	if strings.HasPrefix(fileName, "requirements") && strings.HasSuffix(fileName, ".txt") {
+		return isolatedManifest
+	}
+	if extension := filepath.Ext(fileName); extension == ".csproj" || extension == ".sln" {
+		return nonIsolatedManifest
+	}
+	return notManifest
+}
+
+// getChangedModulesWorkingDirs returns the working dirs to scan, out of the working dirs of the project, when only the changed manifests are scanned.
+// For each working dir, these are the dirs of the modules whose manifests were changed by the pull request, or the working dir itself if the impact of the changes can't be isolated to modules.
+// The working dirs with no changed manifests are omitted.
+func getChangedModulesWorkingDirs(targetBranch *targetBranchCheckout, project *utils.Project) (workingDirs []string, err error) {
+	sourceDir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	targetDir, err := targetBranch.getDir()
+	if err != nil {
+		return nil, err
+	}
+	return getChangedModulesDirs(project, sourceDir, targetDir)
+}
+
+func getChangedModulesDirs(project *utils.Project, sourceDir, targetDir string) (workingDirs []string, err error) {
+	projectWorkingDirs := project.WorkingDirs
+	if len(projectWorkingDirs) == 0 {
+		projectWorkingDirs = []string{utils.RootDir}
+	}
+	for _, workingDir := range projectWorkingDirs {
+		modulesDirs, err := getWorkingDirChangedModules(workingDir, sourceDir, targetDir)
+		if err != nil {
+			return nil, err
+		}
+		workingDirs = append(workingDirs, modulesDirs...)
+	}
+	return workingDirs, nil
+}
+
+// Return the dirs of the working dir to scan, relative to the root of the repository
+func getWorkingDirChangedModules(workingDir, sourceDir, targetDir string) ([]string, error) {
+	sourceWd, targetWd := filepath.Join(sourceDir, workingDir), filepath.Join(targetDir, workingDir)
+	changedManifests, err := getChangedManifests(sourceWd, targetWd)
+	if err != nil {
+		return nil, err
+	}
+	if len(changedManifests) == 0 {
+		log.Info(fmt.Sprintf("No manifests were changed in the working directory '%s'. Skipping its dependencies scan", workingDir))
+		return nil, nil
+	}
+	var modulesDirs []string
+	addedModules := make(map[string]bool)
+	for _, manifest := range changedManifests {
+		moduleDir := filepath.Dir(manifest)
+		if reason := getNonIsolatedChangeReason(manifest, moduleDir, sourceWd, targetWd); reason != "" {
+			log.Info(fmt.Sprintf("Scanning the whole working directory '%s', since %s", workingDir, reason))
+			return []string{workingDir}, nil
+		}
+		if !addedModules[moduleDir] {
+			addedModules[moduleDir] = true
+			modulesDirs = append(modulesDirs, filepath.Join(workingDir, moduleDir))
+		}
+	}
+	log.Info(fmt.Sprintf("Only the manifests of %d modules were changed in the working directory '%s'. Scanning only these modules: %s", len(modulesDirs), workingDir, strings.Join(modulesDirs, ", ")))
+	return modulesDirs, nil
+}
+
+// Return the reason the change of the manifest may impact other modules of the working dir, or an empty string if only its module is impacted
+func getNonIsolatedChangeReason(manifest, moduleDir, sourceWd, targetWd string) string {
+	if getManifestKind(filepath.Base(manifest)) == nonIsolatedManifest {
+		return fmt.Sprintf("the modules of %s can't be scanned separately", manifest)
+	}
+	if moduleDir == "." {
+		return fmt.Sprintf("the manifest %s at the root of the working directory changed", manifest)
+	}
+	if !isDir(filepath.Join(sourceWd, moduleDir)) || !isDir(filepath.Join(targetWd, moduleDir)) {
+		return fmt.Sprintf("the module %s was added or removed", moduleDir)
+	}
+	return ""
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// Return the sorted paths of the manifests which were added, removed or modified in sourceWd compared to targetWd, relative to the working dirs
+func getChangedManifests(sourceWd, targetWd string) (changedManifests []string, err error) {
+	sourceManifests, err := listManifests(sourceWd)
+	if err != nil {
+		return nil, err
+	}
+	targetManifests, err := listManifests(targetWd)
+	if err != nil {
+		return nil, err
+	}
+	for manifest := range sourceManifests {
+		if !targetManifests[manifest] {
+			changedManifests = append(changedManifests, manifest)
+			continue
+		}
+		sourceContent, err := os.ReadFile(filepath.Join(sourceWd, manifest))
+		if err != nil {
+			return nil, err
+		}
+		targetContent, err := os.ReadFile(filepath.Join(targetWd, manifest))
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(sourceContent, targetContent) {
+			changedManifests = append(changedManifests, manifest)
+		}
+	}
+	for manifest := range targetManifests {
+		if !sourceManifests[manifest] {
+			changedManifests = append(changedManifests, manifest)
+		}
+	}
+	sort.Strings(changedManifests)
+	return changedManifests, nil
+}
+
+// Return the paths of the manifests in the dir and its subdirs, relative to the dir. A dir which doesn't exist has no manifests.
+func listManifests(dir string) (manifests map[string]bool, err error) {
+	manifests = make(map[string]bool)
+	if !isDir(dir) {
+		return manifests, nil
+	}
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			// The dirs skipped by the secrets scan, such as node_modules, include the dependencies rather than the modules of the project
+			if path != dir && isSecretsSkippedDir(entry.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if getManifestKind(entry.Name()) == notManifest {
+			return nil
+		}
+		relativePath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		manifests[relativePath] = true
+		return nil
+	})
+	return
+}
@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetManifestKind(t *testing.T) {
+	assert.Equal(t, isolatedManifest, getManifestKind("package.json"))
+	assert.Equal(t, isolatedManifest, getManifestKind("go.sum"))
+	assert.Equal(t, isolatedManifest, getManifestKind("requirements-dev.txt"))
+	assert.Equal(t, nonIsolatedManifest, getManifestKind("pom.xml"))
+	assert.Equal(t, nonIsolatedManifest, getManifestKind("api.csproj"))
+	assert.Equal(t, notManifest, getManifestKind("main.go"))
+}
+
+func TestGetChangedModulesDirs(t *testing.T) {
+	targetFiles := map[string]string{
+		"package.json":                 "{}",
+		"services/payments/go.mod":     "module payments",
+		"services/payments/go.sum":     "",
+		"services/orders/package.json": `{"name": "orders"}`,
+		"services/users/package.json":  `{"name": "users"}`,
+		"java/pom.xml":                 "<project/>",
+		"java/core/pom.xml":            "<project/>",
+		"README.md":                    "",
+	}
+	testCases := []struct {
+		name         string
+		workingDirs  []string
+		changedFiles map[string]string
+		expectedDirs []string
+	}{
+		{
+			name:         "No changed manifests",
+			changedFiles: map[string]string{"README.md": "changed", "services/payments/main.go": "package main"},
+			expectedDirs: nil,
+		},
+		{
+			name:         "Changed modules",
+			changedFiles: map[string]string{"services/payments/go.sum": "changed", "services/orders/package.json": `{"name": "orders", "version": "2.0.0"}`},
+			expectedDirs: []string{filepath.Join("services", "orders"), filepath.Join("services", "payments")},
+		},
+		{
+			name:         "Changed manifests in the dependencies dirs",
+			changedFiles: map[string]string{"services/orders/node_modules/lodash/package.json": "{}"},
+			expectedDirs: nil,
+		},
+		{
+			name:         "Changed root manifest",
+			changedFiles: map[string]string{"package.json": `{"workspaces": ["services/*"]}`, "services/orders/package.json": "{}"},
+			expectedDirs: []string{utils.RootDir},
+		},
+		{
+			name:         "Changed maven module",
+			changedFiles: map[string]string{"java/core/pom.xml": "<project></project>", "services/orders/package.json": "{}"},
+			expectedDirs: []string{utils.RootDir},
+		},
+		{
+			name:         "Added module",
+			changedFiles: map[string]string{"services/inventory/package.json": "{}"},
+			expectedDirs: []string{utils.RootDir},
+		},
+		{
+			name:         "Multiple working dirs",
+			workingDirs:  []string{"services", "java"},
+			changedFiles: map[string]string{"services/users/package.json": "{}", "java/core/pom.xml": "<project></project>"},
+			expectedDirs: []string{filepath.Join("services", "users"), "java"},
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			sourceDir, targetDir := t.TempDir(), t.TempDir()
+			writeTestFiles(t, targetDir, targetFiles)
+			writeTestFiles(t, sourceDir, targetFiles)
+			writeTestFiles(t, sourceDir, test.changedFiles)
+			workingDirs, err := getChangedModulesDirs(&utils.Project{WorkingDirs: test.workingDirs}, sourceDir, targetDir)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedDirs, workingDirs)
+		})
+	}
+}
+
+func TestGetChangedManifestsRemoved(t *testing.T) {
+	sourceDir, targetDir := t.TempDir(), t.TempDir()
+	writeTestFiles(t, targetDir, map[string]string{"orders/package.json": "{}", "orders/yarn.lock": ""})
+	writeTestFiles(t, sourceDir, map[string]string{"orders/package.json": "{}"})
+	changedManifests, err := getChangedManifests(sourceDir, targetDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join("orders", "yarn.lock")}, changedManifests)
+
+	// A working dir which doesn't exist in the target branch has only added manifests
+	changedManifests, err = getChangedManifests(sourceDir, filepath.Join(targetDir, "missing"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join("orders", "package.json")}, changedManifests)
+}
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// isPullRequestClosed returns true if the scanned pull request is no longer open, such as when a CI event arrives after the pull request was merged,
+// and closed pull requests are skipped. The state isn't a part of the scan itself, so if it can't be read, the pull request is considered open.
+func isPullRequestClosed(repoConfig *utils.FrogbotRepoConfig) bool {
+	if !repoConfig.ShouldSkipClosedPRs() || repoConfig.PullRequestID == 0 {
+		return false
+	}
+	isOpen, err := isPullRequestOpen(&repoConfig.Git, repoConfig.PullRequestID)
+	if err != nil {
+		log.Warn("couldn't check whether pull request", repoConfig.PullRequestID, "is open, so it's considered open:", err.Error())
+		return false
+	}
+	return !isOpen
+}
+
+// The froggit-go VCS client doesn't return the state of a single pull request, and therefore the GitHub and GitLab APIs are used directly.
+// On the other Git providers, the pull requests are considered open.
+func isPullRequestOpen(git *utils.Git, pullRequestID int) (bool, error) {
+	switch git.GitProvider {
+	case vcsutils.GitHub:
+		client, err := newGitHubClient(git)
+		if err != nil {
+			return false, err
+		}
+		pullRequest, _, err := client.PullRequests.Get(context.Background(), git.RepoOwner, git.RepoName, pullRequestID)
+		if err != nil {
+			return false, err
+		}
+		return pullRequest.GetState() == "open", nil
+	case vcsutils.GitLab:
+		client, err := newGitLabClient(git)
+		if err != nil {
+			return false, err
+		}
+		mergeRequest, _, err := client.MergeRequests.GetMergeRequest(fmt.Sprintf("%s/%s", git.RepoOwner, git.RepoName), pullRequestID, nil)
+		if err != nil {
+			return false, err
+		}
+		return mergeRequest.State == "opened", nil
+	default:
+		return true, nil
+	}
+}
@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPullRequestClosed(t *testing.T) {
+	skip := false
+	tests := []struct {
+		name           string
+		provider       vcsutils.VcsProvider
+		response       string
+		status         int
+		skipClosedPRs  *bool
+		expectedClosed bool
+	}{
+		{name: "githubClosed", provider: vcsutils.GitHub, response: `{"number": 5, "state": "closed"}`, expectedClosed: true},
+		{name: "githubOpen", provider: vcsutils.GitHub, response: `{"number": 5, "state": "open"}`},
+		{name: "gitlabMerged", provider: vcsutils.GitLab, response: `{"iid": 5, "state": "merged"}`, expectedClosed: true},
+		{name: "gitlabOpen", provider: vcsutils.GitLab, response: `{"iid": 5, "state": "opened"}`},
+		{name: "skipClosedPRsDisabled", provider: vcsutils.GitHub, response: `{"number": 5, "state": "closed"}`, skipClosedPRs: &skip},
+		// The pull request is considered open if its state can't be read
+		{name: "error", provider: vcsutils.GitHub, response: `{}`, status: http.StatusForbidden},
+		{name: "unsupportedProvider", provider: vcsutils.BitbucketServer},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// The GitLab client sends a request to configure its rate limiter
+				if r.URL.Path == "/api/v4/" {
+					return
+				}
+				if test.status != 0 {
+					w.WriteHeader(test.status)
+				}
+				_, err := fmt.Fprint(w, test.response)
+				assert.NoError(t, err)
+			}))
+			defer server.Close()
+
+			repoConfig := &utils.FrogbotRepoConfig{Params: utils.Params{
+				Git:           utils.Git{GitProvider: test.provider, RepoOwner: "jfrog", RepoName: "frogbot", ApiEndpoint: server.URL, PullRequestID: 5},
+				SkipClosedPRs: test.skipClosedPRs,
+			}}
+			assert.Equal(t, test.expectedClosed, isPullRequestClosed(repoConfig))
+		})
+	}
+}
@@ -4,10 +4,49 @@ import (
 	"fmt"
 	"github.com/jfrog/frogbot/commands/utils"
 	"github.com/jfrog/froggit-go/vcsclient"
+	coreconfig "github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	clitool "github.com/urfave/cli/v2"
+	"strings"
+	"time"
 )
 
+const (
+	pathFlag   = "path"
+	formatFlag = "format"
+	outputFlag = "output"
+	sbomFlag   = "sbom-output"
+	lockFlag   = "lock-report"
+	configFlag = "config"
+
+	keepTempFlag = "keep-temp"
+	lockOnlyFlag = "lock-report-only"
+	profileFlag  = "profile"
+	setFlag      = "set"
+	metricsFlag  = "metrics-file"
+
+	configFromRepoFlag = "config-from-repo"
+)
+
+// The values of a repeatable flag, such as --set and --config. Unlike the string slice flags, the values aren't split on commas, since a value may be a list or a glob pattern.
+type repeatableFlag []string
+
+func (values *repeatableFlag) Set(value string) error {
+	*values = append(*values, value)
+	return nil
+}
+
+func (values *repeatableFlag) String() string {
+	return strings.Join(*values, " ")
+}
+
+func getRepeatableFlag(ctx *clitool.Context, name string) []string {
+	if values, ok := ctx.Generic(name).(*repeatableFlag); ok {
+		return *values
+	}
+	return nil
+}
+
 type FrogbotCommand interface {
 	// Run the command
 	Run(config utils.FrogbotConfigAggregator, client vcsclient.VcsClient) error
@@ -19,12 +58,29 @@ func Exec(command FrogbotCommand, name string) error {
 	if err != nil {
 		return err
 	}
+	return run(command, name, configAggregator, server, client)
+}
+
+// ExecWithoutVcs runs a command that works on the local file system only, and therefore doesn't require a VCS client
+func ExecWithoutVcs(command FrogbotCommand, name string) error {
+	configAggregator, server, err := utils.GetParamsWithoutVcs()
+	if err != nil {
+		return err
+	}
+	return run(command, name, configAggregator, server, nil)
+}
+
+func run(command FrogbotCommand, name string, configAggregator utils.FrogbotConfigAggregator, server *coreconfig.ServerDetails, client vcsclient.VcsClient) (err error) {
 	// Send usage report
 	usageReportSent := make(chan error)
 	go utils.ReportUsage(name, server, usageReportSent)
 	// Invoke the command interface
 	log.Info(fmt.Sprintf("Running Frogbot %q command ", name))
+	startTime := time.Now()
 	err = command.Run(configAggregator, client)
+	if metricsErr := scanMetrics.write(getMetricsLabels(name, configAggregator, client), time.Since(startTime), err); metricsErr != nil {
+		log.Warn(metricsErr.Error())
+	}
 	// Waits for the signal from the report usage to be done.
 	<-usageReportSent
 	if err == nil {
@@ -33,8 +89,23 @@ func Exec(command FrogbotCommand, name string) error {
 	return err
 }
 
+// The provider label is set only if the command works with the git provider, and the repository label only if a single repository is configured
+func getMetricsLabels(name string, configAggregator utils.FrogbotConfigAggregator, client vcsclient.VcsClient) metricsLabels {
+	labels := metricsLabels{command: name}
+	if len(configAggregator) == 0 {
+		return labels
+	}
+	if client != nil {
+		labels.provider = getProviderLabel(configAggregator[0].GitProvider)
+	}
+	if len(configAggregator) == 1 {
+		labels.repository = configAggregator[0].RepoName
+	}
+	return labels
+}
+
 func GetCommands() []*clitool.Command {
-	return []*clitool.Command{
+	cliCommands := []*clitool.Command{
 		{
 			Name:    "scan-pull-request",
 			Aliases: []string{"spr"},
@@ -42,7 +113,9 @@ func GetCommands() []*clitool.Command {
 			Action: func(ctx *clitool.Context) error {
 				return Exec(ScanPullRequestCmd{}, ctx.Command.Name)
 			},
-			Flags: []clitool.Flag{},
+			Flags: []clitool.Flag{
+				&clitool.BoolFlag{Name: configFromRepoFlag, Usage: "Merge the scan section of the .frogbot/frogbot-config.yml file of the scanned pull request over the configuration. Unless repoConfigCanRelaxGating is set, it can't relax the gating of the pull request"},
+			},
 		},
 		{
 			Name:    "create-fix-pull-requests",
@@ -60,6 +133,17 @@ func GetCommands() []*clitool.Command {
 			Action: func(ctx *clitool.Context) error {
 				return Exec(ScanAllPullRequestsCmd{}, ctx.Command.Name)
 			},
+			Flags: []clitool.Flag{
+				&clitool.BoolFlag{Name: configFromRepoFlag, Usage: "Merge the scan section of the .frogbot/frogbot-config.yml file of the scanned pull request over the configuration. Unless repoConfigCanRelaxGating is set, it can't relax the gating of the pull request"},
+			},
+		},
+		{
+			Name:    "pull-requests-digest",
+			Aliases: []string{"prd"},
+			Usage:   "Scans the open pull requests within a single or multiple repositories, and publishes a single digest with the new issues of each pull request, without commenting on the pull requests",
+			Action: func(ctx *clitool.Context) error {
+				return Exec(PullRequestsDigestCmd{}, ctx.Command.Name)
+			},
 			Flags: []clitool.Flag{},
 		},
 		{
@@ -71,5 +155,81 @@ func GetCommands() []*clitool.Command {
 			},
 			Flags: []clitool.Flag{},
 		},
+		{
+			Name:    "scan-local-directory",
+			Aliases: []string{"sld"},
+			Usage:   "Scans a local directory with JFrog Xray for security vulnerabilities, without any Git operation",
+			Action: func(ctx *clitool.Context) error {
+				return ExecWithoutVcs(ScanLocalDirectoryCmd{
+					Path:           ctx.String(pathFlag),
+					Format:         ctx.String(formatFlag),
+					OutputFile:     ctx.String(outputFlag),
+					SbomOutputFile: ctx.String(sbomFlag),
+				}, ctx.Command.Name)
+			},
+			Flags: []clitool.Flag{
+				&clitool.StringFlag{Name: pathFlag, Usage: "The path of the directory to scan. Default: current working directory"},
+				&clitool.StringFlag{Name: formatFlag, Value: MarkdownFormat, Usage: "The output format: " + MarkdownFormat + ", " + SimplifiedFormat + " or " + JsonFormat},
+				&clitool.StringFlag{Name: outputFlag, Usage: "A file to write the results to. Default: standard output"},
+				&clitool.StringFlag{Name: sbomFlag, Usage: "A file to write a CycloneDX 1.4 JSON SBOM of the scanned dependencies and their vulnerabilities to"},
+			},
+		},
+		{
+			Name:    "list-dependencies",
+			Aliases: []string{"ld"},
+			Usage:   "Lists all the resolved dependencies of a local directory, with the vulnerability status and the highest severity of each dependency, without any Git operation",
+			Action: func(ctx *clitool.Context) error {
+				return ExecWithoutVcs(ListDependenciesCmd{
+					Path:           ctx.String(pathFlag),
+					Format:         ctx.String(formatFlag),
+					OutputFile:     ctx.String(outputFlag),
+					LockReportFile: ctx.String(lockFlag),
+					LockReportOnly: ctx.Bool(lockOnlyFlag),
+				}, ctx.Command.Name)
+			},
+			Flags: []clitool.Flag{
+				&clitool.StringFlag{Name: pathFlag, Usage: "The path of the directory to list. Default: current working directory"},
+				&clitool.StringFlag{Name: formatFlag, Value: TableFormat, Usage: "The output format: " + TableFormat + ", " + JsonFormat + " or " + CsvFormat},
+				&clitool.StringFlag{Name: outputFlag, Usage: "A file to write the dependencies list to. Default: standard output"},
+				&clitool.StringFlag{Name: lockFlag, Usage: "A file to write a deterministic text report of the resolved dependencies to, which can be committed to review the dependency changes"},
+				&clitool.BoolFlag{Name: lockOnlyFlag, Usage: "Only write the lock report, without scanning the dependencies with Xray and without listing them"},
+			},
+		},
+		{
+			Name:    "validate-config",
+			Aliases: []string{"vc"},
+			Usage:   "Validates a frogbot-config file, and reports all the errors found in it",
+			Action: func(ctx *clitool.Context) error {
+				return ValidateConfigCmd{ConfigPaths: getRepeatableFlag(ctx, configFlag)}.Run()
+			},
+		},
+		{
+			Name:    "doctor",
+			Aliases: []string{"dr"},
+			Usage:   "Verifies the configuration, the access to the Git provider and to Xray, and the entitlements of the JFrog Platform, and prints a report of the checks",
+			Action: func(ctx *clitool.Context) error {
+				return DoctorCmd{}.Run()
+			},
+		},
+	}
+	// Common flags
+	for _, command := range cliCommands {
+		command.Flags = append(command.Flags,
+			&clitool.BoolFlag{Name: keepTempFlag, Usage: "Skip the removal of the temp directories, for troubleshooting"},
+			&clitool.StringFlag{Name: profileFlag, Usage: "The name of a profile from the frogbot-config file, which overrides the severity policy and fail behavior. Default: the " + utils.ProfileEnv + " environment variable"},
+			&clitool.GenericFlag{Name: configFlag, Value: &repeatableFlag{}, Usage: "The path of a frogbot-config file, a directory of frogbot-config files or a glob pattern, whose files are merged. May be repeated. Default: .frogbot/frogbot-config.yml"},
+			&clitool.GenericFlag{Name: setFlag, Value: &repeatableFlag{}, Usage: "Overrides a param of the frogbot-config file, in the key=value format, such as --set scan.minSeverity=High. May be repeated"},
+			&clitool.StringFlag{Name: metricsFlag, Usage: "The path of a file, to which the metrics of the run are written in the Prometheus textfile collector format"},
+		)
+		command.Before = func(ctx *clitool.Context) error {
+			utils.SetKeepTempDirs(ctx.Bool(keepTempFlag))
+			utils.SetProfile(ctx.String(profileFlag))
+			setMetricsFile(ctx.String(metricsFlag))
+			utils.SetConfigFromRepo(ctx.Bool(configFromRepoFlag))
+			utils.SetConfigPaths(getRepeatableFlag(ctx, configFlag))
+			utils.SetConfigOverrides(getRepeatableFlag(ctx, setFlag))
+			return nil
+		}
 	}
+	return cliCommands
 }
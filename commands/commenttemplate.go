@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The data passed to the comment template, out of which the pull request comment is created
+type commentTemplateData struct {
+	// The title of the comment, which is the banner of the git provider, or the clean scan message if no issues were found
+	Title string
+	// The issues tables, as created by the output writer of the git provider
+	Tables string
+	// The severity legend, the severity policy note and the notes which follow the tables
+	Notes string
+	// The vulnerabilities and violations found
+	Vulnerabilities []formats.VulnerabilityOrViolationRow
+	// The misconfigurations found by the Infrastructure as Code scan
+	Misconfigurations []utils.IacRow
+	// The number of secrets found in the lines added by the pull request
+	SecretsCount int
+	// The number of issues of each severity, out of the vulnerabilities and the misconfigurations
+	Summary severityBreakdown
+	// The number of issues, misconfigurations and secrets found
+	IssuesCount int
+	// True if an issue fails the scan, according to the severity policy
+	FailingIssuesFound bool
+}
+
+func createCommentTemplateData(repoConfig *utils.FrogbotRepoConfig, results *auditResults, notes string) *commentTemplateData {
+	writer := repoConfig.OutputWriter
+	issuesCount := results.issuesCount()
+	data := &commentTemplateData{
+		Title:              writer.VulnerabiltiesTitle(),
+		Tables:             createScanResultsTables(results, writer, repoConfig.GetSectionOrder()),
+		Notes:              createSeverityNotes(issuesCount > 0, &repoConfig.Scan, writer) + notes,
+		Vulnerabilities:    results.vulnerabilitiesRows,
+		Misconfigurations:  results.iacRows,
+		SecretsCount:       len(results.secrets),
+		IssuesCount:        issuesCount,
+		FailingIssuesFound: results.failingIssuesFound,
+	}
+	if issuesCount == 0 {
+		data.Title = writer.NoVulnerabilitiesTitle()
+		if repoConfig.CleanScanMessage != "" {
+			data.Title = createCleanScanMessage(repoConfig.CleanScanMessage, utils.GetHeadCommitSha("."), time.Now())
+		}
+	}
+	data.Summary = results.severitySummary()
+	return data
+}
+
+// The number of issues of each severity, out of the vulnerabilities and the misconfigurations
+func (results *auditResults) severitySummary() (summary severityBreakdown) {
+	for _, row := range results.vulnerabilitiesRows {
+		summary.addIssue(row.Severity)
+	}
+	for _, row := range results.iacRows {
+		summary.addIssue(row.Severity)
+	}
+	return
+}
+
+// Create the comment with all the scan results, using the configured comment template or the default comment template
+func createCommentMessage(repoConfig *utils.FrogbotRepoConfig, results *auditResults, notes string) string {
+	data := createCommentTemplateData(repoConfig, results, notes)
+	commentTemplate := repoConfig.CommentTemplate
+	if commentTemplate == nil {
+		commentTemplate = utils.DefaultCommentTemplate
+	}
+	message, err := executeCommentTemplate(commentTemplate, data)
+	if err != nil {
+		// The template was parsed when the config was loaded, but it may still fail on the data of this scan, such as on a missing field
+		log.Warn("couldn't create the comment using the comment template. Using the default comment instead:", err.Error())
+		if message, err = executeCommentTemplate(utils.DefaultCommentTemplate, data); err != nil {
+			log.Warn(err.Error())
+		}
+	}
+	return message
+}
+
+func executeCommentTemplate(commentTemplate *template.Template, data *commentTemplateData) (string, error) {
+	var message strings.Builder
+	if err := commentTemplate.Execute(&message, data); err != nil {
+		return "", err
+	}
+	return message.String(), nil
+}
@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"testing"
+	"text/template"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+var commentTemplateTestResults = &auditResults{
+	vulnerabilitiesRows: []formats.VulnerabilityOrViolationRow{
+		{Severity: "Critical", ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "1.2.5", Cves: []formats.CveRow{{Id: "CVE-2021-44906"}}},
+		{Severity: "Low", ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "4.17.19"},
+	},
+	iacRows:            []utils.IacRow{{Severity: "High", File: "main.tf", Finding: "Public bucket"}},
+	failingIssuesFound: true,
+}
+
+func createCommentTemplateTestConfig(commentTemplate string) *utils.FrogbotRepoConfig {
+	repoConfig := &utils.FrogbotRepoConfig{OutputWriter: &utils.StandardOutput{}}
+	if commentTemplate != "" {
+		repoConfig.CommentTemplate = template.Must(template.New("comment").Parse(commentTemplate))
+	}
+	return repoConfig
+}
+
+func TestCreateCommentMessageDefaultTemplate(t *testing.T) {
+	// The default template creates the default comment
+	repoConfig := createCommentTemplateTestConfig("")
+	writer := repoConfig.OutputWriter
+	expected := createScanResultsMessage(commentTemplateTestResults, writer) + createSeverityNotes(true, &repoConfig.Scan, writer) + "\n\nnotes"
+	assert.Equal(t, expected, createCommentMessage(repoConfig, commentTemplateTestResults, "\n\nnotes"))
+	assert.Equal(t, writer.NoVulnerabilitiesTitle(), createCommentMessage(repoConfig, &auditResults{}, ""))
+}
+
+func TestCreateCommentMessageCustomTemplate(t *testing.T) {
+	repoConfig := createCommentTemplateTestConfig(
+		"{{.IssuesCount}} issues ({{.Summary.Critical}} critical, {{.Summary.High}} high) " +
+			"{{range .Vulnerabilities}}[{{.ImpactedDependencyName}}]{{end}}{{if .FailingIssuesFound}} failing{{end}}{{.Notes}}")
+	message := createCommentMessage(repoConfig, commentTemplateTestResults, "\n\nnotes")
+	assert.Equal(t, "3 issues (1 critical, 1 high) [minimist][lodash] failing"+repoConfig.OutputWriter.SeverityLegend()+"\n\nnotes", message)
+}
+
+func TestCreateCommentMessageFailingTemplate(t *testing.T) {
+	// The default comment is created if the template fails on the data of the scan
+	repoConfig := createCommentTemplateTestConfig("{{.Missing}}")
+	assert.Equal(t, createCommentMessage(createCommentTemplateTestConfig(""), commentTemplateTestResults, ""),
+		createCommentMessage(repoConfig, commentTemplateTestResults, ""))
+}
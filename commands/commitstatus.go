@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	commitStatusPending = "Frogbot is scanning the pull request"
+	commitStatusPassed  = "No issues which fail the scan were found"
+	commitStatusFailed  = "%d issues were found, which fail the scan"
+	commitStatusError   = "The scan couldn't be completed"
+)
+
+// commitStatusReporter sets the status of the scanned commit, with the commitStatusContext as its name, so that it can be required by the branch protection rules.
+// The commit status is separate from the pull request comment, so a failure to set it is logged rather than failing the scan.
+type commitStatusReporter struct {
+	repoConfig *utils.FrogbotRepoConfig
+	client     vcsclient.VcsClient
+	commitSha  string
+	// The URL of the CI run, which the commit status links to
+	targetUrl string
+	// True if the final state of the scan was set
+	finished bool
+}
+
+// Return a reporter of the scanned commit status, or nil if commitStatusContext isn't set or the scanned commit can't be found.
+// The methods of a nil reporter do nothing.
+func newCommitStatusReporter(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient) *commitStatusReporter {
+	if repoConfig.CommitStatusContext == "" {
+		return nil
+	}
+	commitSha := getScannedCommitSha(repoConfig)
+	if commitSha == "" {
+		log.Warn("couldn't find the scanned commit, so the", "'"+repoConfig.CommitStatusContext+"'", "commit status isn't set")
+		return nil
+	}
+	return &commitStatusReporter{repoConfig: repoConfig, client: client, commitSha: commitSha, targetUrl: getCiRunUrl()}
+}
+
+// Return the head commit of the source branch of the pull request.
+// On GitHub, the checked out commit may be the merge commit of the pull request, so the head commit is read from the pull request details.
+func getScannedCommitSha(repoConfig *utils.FrogbotRepoConfig) string {
+	if repoConfig.HeadCommitSha != "" {
+		return repoConfig.HeadCommitSha
+	}
+	if repoConfig.GitProvider == vcsutils.GitHub {
+		head, err := getGitHubPullRequestHead(&repoConfig.Git, repoConfig.PullRequestID)
+		if err == nil && head.sha != "" {
+			return head.sha
+		}
+		if err != nil {
+			log.Warn("couldn't get the head commit of pull request", repoConfig.PullRequestID, "- using the checked out commit:", err.Error())
+		}
+	}
+	return utils.GetHeadCommitSha(".")
+}
+
+// Return the URL of the CI run, on the CI servers which provide it in the environment, or an empty string
+func getCiRunUrl() string {
+	if runId := os.Getenv("GITHUB_RUN_ID"); runId != "" && os.Getenv("GITHUB_SERVER_URL") != "" && os.Getenv("GITHUB_REPOSITORY") != "" {
+		return fmt.Sprintf("%s/%s/actions/runs/%s", os.Getenv("GITHUB_SERVER_URL"), os.Getenv("GITHUB_REPOSITORY"), runId)
+	}
+	if jobUrl := os.Getenv("CI_JOB_URL"); jobUrl != "" {
+		return jobUrl
+	}
+	if buildId := os.Getenv("BUILD_BUILDID"); buildId != "" && os.Getenv("SYSTEM_COLLECTIONURI") != "" && os.Getenv("SYSTEM_TEAMPROJECT") != "" {
+		return fmt.Sprintf("%s%s/_build/results?buildId=%s", strings.TrimSuffix(os.Getenv("SYSTEM_COLLECTIONURI"), "/")+"/", os.Getenv("SYSTEM_TEAMPROJECT"), buildId)
+	}
+	return os.Getenv("BUILD_URL")
+}
+
+func (reporter *commitStatusReporter) setPending() {
+	reporter.set(vcsclient.InProgress, commitStatusPending)
+}
+
+// Set the final state of the scan: failure if Frogbot fails the scan due to the issues found, and success otherwise
+func (reporter *commitStatusReporter) setScanResult(shouldFail bool, issuesCount int) {
+	if reporter == nil {
+		return
+	}
+	reporter.finished = true
+	if shouldFail {
+		reporter.set(vcsclient.Fail, fmt.Sprintf(commitStatusFailed, issuesCount))
+		return
+	}
+	reporter.set(vcsclient.Pass, commitStatusPassed)
+}
+
+// Set the error state, if the scan ended before its final state was set
+func (reporter *commitStatusReporter) setErrorIfUnfinished() {
+	if reporter == nil || reporter.finished {
+		return
+	}
+	reporter.finished = true
+	reporter.set(vcsclient.Error, commitStatusError)
+}
+
+func (reporter *commitStatusReporter) set(status vcsclient.CommitStatus, description string) {
+	if reporter == nil {
+		return
+	}
+	repoConfig := reporter.repoConfig
+	err := reporter.client.SetCommitStatus(context.Background(), status, repoConfig.RepoOwner, repoConfig.RepoName, reporter.commitSha, repoConfig.CommitStatusContext, description, reporter.targetUrl)
+	if err != nil {
+		log.Warn("couldn't set the", "'"+repoConfig.CommitStatusContext+"'", "commit status of commit", reporter.commitSha+":", err.Error())
+	}
+}
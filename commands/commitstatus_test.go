@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCommitStatusReporterDisabled(t *testing.T) {
+	reporter := newCommitStatusReporter(newTestRepoConfig(vcsutils.GitLab, ""), mockVcsClient(t))
+	assert.Nil(t, reporter)
+	// The methods of a disabled reporter do nothing
+	reporter.setPending()
+	reporter.setScanResult(true, 1)
+	reporter.setErrorIfUnfinished()
+}
+
+func TestCommitStatusReporterScanResult(t *testing.T) {
+	t.Setenv("GITHUB_RUN_ID", "")
+	t.Setenv("CI_JOB_URL", "https://gitlab.com/jfrog/frogbot/-/jobs/42")
+	client := mockVcsClient(t)
+	gomock.InOrder(
+		client.EXPECT().SetCommitStatus(gomock.Any(), vcsclient.InProgress, "jfrog", "frogbot", "abc123", "frogbot", commitStatusPending, "https://gitlab.com/jfrog/frogbot/-/jobs/42").Return(nil),
+		client.EXPECT().SetCommitStatus(gomock.Any(), vcsclient.Fail, "jfrog", "frogbot", "abc123", "frogbot", "3 issues were found, which fail the scan", "https://gitlab.com/jfrog/frogbot/-/jobs/42").Return(nil),
+	)
+	repoConfig := newTestRepoConfig(vcsutils.GitLab, "")
+	repoConfig.CommitStatusContext = "frogbot"
+	repoConfig.HeadCommitSha = "abc123"
+	reporter := newCommitStatusReporter(repoConfig, client)
+	reporter.setPending()
+	reporter.setScanResult(true, 3)
+	// The final state isn't replaced
+	reporter.setErrorIfUnfinished()
+}
+
+func TestCommitStatusReporterError(t *testing.T) {
+	client := mockVcsClient(t)
+	gomock.InOrder(
+		// A failure to set the status doesn't stop the scan
+		client.EXPECT().SetCommitStatus(gomock.Any(), vcsclient.InProgress, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("forbidden")),
+		client.EXPECT().SetCommitStatus(gomock.Any(), vcsclient.Error, "jfrog", "frogbot", "abc123", "frogbot", commitStatusError, gomock.Any()).Return(nil),
+	)
+	repoConfig := newTestRepoConfig(vcsutils.GitLab, "")
+	repoConfig.CommitStatusContext = "frogbot"
+	repoConfig.HeadCommitSha = "abc123"
+	reporter := newCommitStatusReporter(repoConfig, client)
+	reporter.setPending()
+	reporter.setErrorIfUnfinished()
+}
+
+func TestGetCiRunUrl(t *testing.T) {
+	for _, env := range []string{"GITHUB_RUN_ID", "GITHUB_SERVER_URL", "GITHUB_REPOSITORY", "CI_JOB_URL", "BUILD_BUILDID", "SYSTEM_COLLECTIONURI", "SYSTEM_TEAMPROJECT", "BUILD_URL"} {
+		t.Setenv(env, "")
+	}
+	assert.Empty(t, getCiRunUrl())
+
+	t.Setenv("BUILD_URL", "https://jenkins.example.com/job/frogbot/7/")
+	assert.Equal(t, "https://jenkins.example.com/job/frogbot/7/", getCiRunUrl())
+
+	t.Setenv("BUILD_BUILDID", "15")
+	t.Setenv("SYSTEM_COLLECTIONURI", "https://dev.azure.com/jfrog")
+	t.Setenv("SYSTEM_TEAMPROJECT", "frogbot")
+	assert.Equal(t, "https://dev.azure.com/jfrog/frogbot/_build/results?buildId=15", getCiRunUrl())
+
+	t.Setenv("GITHUB_RUN_ID", "1234")
+	t.Setenv("GITHUB_SERVER_URL", "https://github.com")
+	t.Setenv("GITHUB_REPOSITORY", "jfrog/frogbot")
+	assert.Equal(t, "https://github.com/jfrog/frogbot/actions/runs/1234", getCiRunUrl())
+}
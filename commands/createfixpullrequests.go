@@ -11,7 +11,6 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
 	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
-	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/jfrog/jfrog-client-go/xray/services"
 	"os"
@@ -19,8 +18,11 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 )
 
+const skippedFixPRBranchMessage = "The %s branch doesn't match the fixPRBranches patterns. Skipping the creation of fix pull requests for it"
+
 // Package names are case-insensitive with this prefix
 var pythonPackageRegexPrefix = "(?i)"
 
@@ -34,6 +36,12 @@ type CreateFixPullRequestsCmd struct {
 	dryRun bool
 	// When dryRun is enabled, dryRunRepoPath specifies the repository local path to clone
 	dryRunRepoPath string
+	// The source branches of the open pull requests to the scanned branch, used to avoid opening duplicate fix pull requests
+	openPullRequestsBranches map[string]bool
+	// The source branches and the dependencies of the fix pull requests which were declined within the cooldown window, whose fixes aren't opened again
+	declinedFixes map[string]bool
+	// The summary of all the scanned repositories, to which the issues of the scanned branch are added. Nil if no summary is configured.
+	orgSummary *orgSummary
 }
 
 func (cfp CreateFixPullRequestsCmd) Run(configAggregator utils.FrogbotConfigAggregator, client vcsclient.VcsClient) error {
@@ -42,6 +50,10 @@ func (cfp CreateFixPullRequestsCmd) Run(configAggregator utils.FrogbotConfigAggr
 	}
 	repoConfig := &configAggregator[0]
 	for _, branch := range repoConfig.Branches {
+		if !repoConfig.IsFixPRBranch(branch) {
+			log.Info(fmt.Sprintf(skippedFixPRBranchMessage, branch))
+			continue
+		}
 		err := cfp.scanAndFixRepository(repoConfig, client, branch)
 		if err != nil {
 			return err
@@ -56,13 +68,22 @@ func (cfp *CreateFixPullRequestsCmd) scanAndFixRepository(repoConfig *utils.Frog
 	if err != nil {
 		return err
 	}
-	xrayScanParams := createXrayScanParams(repoConfig.Watches, repoConfig.JFrogProjectKey)
+	xrayScanParams := createXrayScanParams(repoConfig.Watches, repoConfig.JFrogProjectKey, repoConfig.ScanMode)
+	results := &auditResults{onlyWithExploits: repoConfig.OnlyWithExploits, cvssPolicy: repoConfig.CvssPolicy, unknownSeverityAs: repoConfig.UnknownSeverityAs}
+	cfp.openPullRequestsBranches = getOpenPullRequestsBranches(repoConfig, client, branch)
+	cfp.declinedFixes = getDeclinedFixes(repoConfig, branch, time.Now())
+	// With a fixPRGrouping other than per-dependency, the fixes of all the working directories are collected, and then grouped into fix pull requests
+	var groupedFixes []dependencyFix
 	for projectIndex, project := range repoConfig.Projects {
 		projectFullPathWorkingDirs := getFullPathWorkingDirs(&repoConfig.Projects[projectIndex], baseWd)
 		for _, fullPathWd := range projectFullPathWorkingDirs {
 			scanResults, isMultipleRoots, err := cfp.scan(project, &repoConfig.Server, xrayScanParams, *repoConfig.FailOnSecurityIssues, fullPathWd)
 			if err != nil {
-				return err
+				if repoConfig.ShouldFailOnScanError() {
+					return &ScanExecutionError{Err: err}
+				}
+				log.Warn("the Xray scan of", fullPathWd, "failed, and its vulnerable dependencies won't be fixed:", err.Error())
+				continue
 			}
 
 			err = utils.UploadScanToGitProvider(scanResults, repoConfig, branch, client, isMultipleRoots)
@@ -70,14 +91,37 @@ func (cfp *CreateFixPullRequestsCmd) scanAndFixRepository(repoConfig *utils.Frog
 				log.Warn(err)
 			}
 
+			if repoConfig.ReportTarget == utils.IssueReportTarget || cfp.orgSummary != nil || scanMetrics != nil {
+				vulnerabilitiesRows, err := createAllIssuesRows(scanResults, isMultipleRoots)
+				if err != nil {
+					return err
+				}
+				results.addProjectIssues(&repoConfig.Projects[projectIndex], vulnerabilitiesRows)
+			}
+
 			// Fix and create PRs
 			relativeCurrentWd := utils.GetRelativeWd(fullPathWd, baseWd)
-			if err = cfp.fixImpactedPackagesAndCreatePRs(project, &repoConfig.Git, branch, client, scanResults, relativeCurrentWd, isMultipleRoots); err != nil {
+			if isGroupedFixPRs(repoConfig.FixPRGrouping) {
+				fixes, err := cfp.getDependencyFixes(&repoConfig.Projects[projectIndex], repoConfig, scanResults, relativeCurrentWd, isMultipleRoots)
+				if err != nil {
+					return err
+				}
+				groupedFixes = append(groupedFixes, fixes...)
+				continue
+			}
+			if err = cfp.fixImpactedPackagesAndCreatePRs(project, repoConfig, branch, client, scanResults, relativeCurrentWd, isMultipleRoots); err != nil {
 				return err
 			}
 		}
 	}
-	return nil
+	if isGroupedFixPRs(repoConfig.FixPRGrouping) {
+		if err = cfp.fixGroupsAndCreatePRs(repoConfig, branch, client, groupedFixes); err != nil {
+			return err
+		}
+	}
+	cfp.orgSummary.addBranchResults(repoConfig.RepoName, branch, results.vulnerabilitiesRows)
+	scanMetrics.addScanResults(repoConfig.RepoName, results)
+	return publishRepositoryReport(repoConfig, branch, results)
 }
 
 // Audit the dependencies of the current commit.
@@ -92,50 +136,25 @@ func (cfp *CreateFixPullRequestsCmd) scan(project utils.Project, server *corecon
 	return scanResults, isMultipleRoots, nil
 }
 
-func (cfp *CreateFixPullRequestsCmd) fixImpactedPackagesAndCreatePRs(project utils.Project, repoGitParams *utils.Git, branch string,
+func (cfp *CreateFixPullRequestsCmd) fixImpactedPackagesAndCreatePRs(project utils.Project, repoConfig *utils.FrogbotRepoConfig, branch string,
 	client vcsclient.VcsClient, scanResults []services.ScanResponse, currentWd string, isMultipleRoots bool) (err error) {
-	fixVersionsMap, err := cfp.createFixVersionsMap(&project, scanResults, isMultipleRoots)
+	fixVersionsMap, err := cfp.createFixVersionsMap(&project, scanResults, isMultipleRoots, repoConfig.UpgradeStrategy, repoConfig.AllowPrerelease)
 	if err != nil {
 		return err
 	}
 	// Nothing to fix, return
 	if len(fixVersionsMap) == 0 {
-		log.Info("Didn't find vulnerable dependencies with existing fix versions for", repoGitParams.RepoName)
+		log.Info("Didn't find vulnerable dependencies with existing fix versions for", repoConfig.RepoName)
 		return nil
 	}
 	log.Info("Found", len(fixVersionsMap), "vulnerable dependencies with fix versions")
 
-	// Create temp working directory
-	wd, err := fileutils.CreateTempDir()
+	gitManager, cleanup, err := cfp.cloneToTempDir(repoConfig, branch)
 	if err != nil {
 		return err
 	}
 	defer func() {
-		e := fileutils.RemoveTempDir(wd)
-		if err == nil {
-			err = e
-		}
-	}()
-	log.Debug("Created temp working directory:", wd)
-
-	// Clone the content of the repo to the new working directory
-	gitManager, err := utils.NewGitManager(cfp.dryRun, cfp.dryRunRepoPath, ".", "origin", repoGitParams.Token, repoGitParams.Username)
-	if err != nil {
-		return err
-	}
-
-	err = gitManager.Clone(wd, branch)
-	if err != nil {
-		return err
-	}
-
-	// 'CD' into the temp working directory
-	restoreDir, err := utils.Chdir(wd)
-	if err != nil {
-		return
-	}
-	defer func() {
-		e := restoreDir()
+		e := cleanup()
 		if err == nil {
 			err = e
 		}
@@ -145,7 +164,7 @@ func (cfp *CreateFixPullRequestsCmd) fixImpactedPackagesAndCreatePRs(project uti
 	for impactedPackage, fixVersionInfo := range fixVersionsMap {
 		log.Info("-----------------------------------------------------------------")
 		log.Info("Start fixing", impactedPackage, "with", fixVersionInfo.fixVersion)
-		err = cfp.fixSinglePackageAndCreatePR(impactedPackage, *fixVersionInfo, &project, branch, repoGitParams, client, gitManager, currentWd)
+		err = cfp.fixSinglePackageAndCreatePR(impactedPackage, *fixVersionInfo, &project, branch, repoConfig, client, gitManager, currentWd)
 		if err != nil {
 			log.Error("failed while trying to fix and create PR for:", impactedPackage, "with version:", fixVersionInfo.fixVersion, "with error:", err.Error())
 		}
@@ -160,8 +179,45 @@ func (cfp *CreateFixPullRequestsCmd) fixImpactedPackagesAndCreatePRs(project uti
 	return nil
 }
 
+// Clone the scanned branch of the repository into a new temp directory, and 'CD' into it.
+// The returned cleanup function restores the working directory and removes the temp directory.
+func (cfp *CreateFixPullRequestsCmd) cloneToTempDir(repoConfig *utils.FrogbotRepoConfig, branch string) (gitManager *utils.GitManager, cleanup func() error, err error) {
+	// Create temp working directory
+	wd, err := utils.CreateTempDir()
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Debug("Created temp working directory:", wd)
+	restoreDir := func() error { return nil }
+	cleanup = func() error {
+		e := restoreDir()
+		if removeErr := utils.RemoveTempDir(wd); e == nil {
+			e = removeErr
+		}
+		return e
+	}
+
+	// Clone the content of the repo to the new working directory
+	gitManager, err = utils.NewGitManager(cfp.dryRun, cfp.dryRunRepoPath, ".", "origin", repoConfig.Token, repoConfig.Username)
+	if err == nil {
+		err = gitManager.Clone(wd, branch)
+	}
+	// 'CD' into the temp working directory
+	if err == nil {
+		restoreDir, err = utils.Chdir(wd)
+	}
+	if err != nil {
+		if e := cleanup(); e != nil {
+			log.Warn("couldn't remove the temp working directory:", e.Error())
+		}
+		return nil, nil, err
+	}
+	return gitManager, cleanup, nil
+}
+
 // Create fixVersionMap - a map between impacted packages and their fix version
-func (cfp *CreateFixPullRequestsCmd) createFixVersionsMap(project *utils.Project, scanResults []services.ScanResponse, isMultipleRoots bool) (map[string]*FixVersionInfo, error) {
+func (cfp *CreateFixPullRequestsCmd) createFixVersionsMap(project *utils.Project, scanResults []services.ScanResponse, isMultipleRoots bool, upgradeStrategy string,
+	allowPrerelease bool) (map[string]*FixVersionInfo, error) {
 	fixVersionsMap := map[string]*FixVersionInfo{}
 	for _, scanResult := range scanResults {
 		if len(scanResult.Vulnerabilities) > 0 {
@@ -178,7 +234,7 @@ func (cfp *CreateFixPullRequestsCmd) createFixVersionsMap(project *utils.Project
 					if !fixVulnerability {
 						continue
 					}
-					vulnFixVersion := getMinimalFixVersion(vulnerability.ImpactedDependencyVersion, vulnerability.FixedVersions)
+					vulnFixVersion := getFixVersionByPolicy(upgradeStrategy, allowPrerelease, vulnerability.Technology, vulnerability.ImpactedDependencyVersion, vulnerability.FixedVersions)
 					if vulnFixVersion == "" {
 						continue
 					}
@@ -190,8 +246,11 @@ func (cfp *CreateFixPullRequestsCmd) createFixVersionsMap(project *utils.Project
 						fixVersionInfo.UpdateFixVersion(vulnFixVersion)
 					} else {
 						// First appearance of a version that fixes the current impacted package
-						fixVersionsMap[vulnerability.ImpactedDependencyName] = NewFixVersionInfo(vulnFixVersion, vulnerability.Technology)
+						fixVersionInfo = NewFixVersionInfo(vulnFixVersion, vulnerability.Technology)
+						fixVersionsMap[vulnerability.ImpactedDependencyName] = fixVersionInfo
 					}
+					fixVersionInfo.UpdateSeverity(vulnerability.Severity, vulnerability.SeverityNumValue)
+					fixVersionInfo.directDependency = fixVersionInfo.directDependency || isDirectDependency(vulnerability)
 				}
 			}
 		}
@@ -214,6 +273,36 @@ func getMinimalFixVersion(impactedPackageVersion string, fixVersions []string) s
 	return ""
 }
 
+// getFixVersion returns the version that fixes the current impactedPackage, according to the upgrade strategy.
+// With the minor strategy, the minimal fix version is returned if none of the fix versions is in the minor version of the impactedPackage.
+func getFixVersion(upgradeStrategy, impactedPackageVersion string, fixVersions []string) string {
+	fixVersion := getMinimalFixVersion(impactedPackageVersion, fixVersions)
+	if fixVersion == "" || upgradeStrategy == "" || upgradeStrategy == utils.MinimalUpgradeStrategy {
+		return fixVersion
+	}
+	currMinorVersion := getMinorVersion(strings.TrimPrefix(impactedPackageVersion, "v"))
+	for _, fixVersionCandidate := range fixVersions {
+		fixVersionCandidate = parseVersionChangeString(fixVersionCandidate)
+		if fixVersionCandidate == "" || version.NewVersion(fixVersion).Compare(fixVersionCandidate) <= 0 {
+			continue
+		}
+		if upgradeStrategy == utils.MinorUpgradeStrategy && getMinorVersion(fixVersionCandidate) != currMinorVersion {
+			continue
+		}
+		fixVersion = fixVersionCandidate
+	}
+	return fixVersion
+}
+
+// Return the major and minor parts of the version, for example 1.6 for 1.6.22
+func getMinorVersion(packageVersion string) string {
+	parts := strings.SplitN(packageVersion, ".", 3)
+	if len(parts) > 2 {
+		parts = parts[:2]
+	}
+	return strings.Join(parts, ".")
+}
+
 func (cfp *CreateFixPullRequestsCmd) shouldFixVulnerability(project *utils.Project, vulnerability formats.VulnerabilityOrViolationRow) (bool, error) {
 	// In Maven, fix only direct dependencies
 	if vulnerability.Technology == coreutils.Maven {
@@ -237,20 +326,14 @@ func (cfp *CreateFixPullRequestsCmd) shouldFixVulnerability(project *utils.Proje
 }
 
 func (cfp *CreateFixPullRequestsCmd) fixSinglePackageAndCreatePR(impactedPackage string, fixVersionInfo FixVersionInfo, project *utils.Project,
-	branch string, repoGitParams *utils.Git, client vcsclient.VcsClient, gitManager *utils.GitManager, currentWd string) (err error) {
+	branch string, repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient, gitManager *utils.GitManager, currentWd string) (err error) {
 	fixBranchName, err := generateFixBranchName(branch, impactedPackage, fixVersionInfo.fixVersion)
 	if err != nil {
 		return err
 	}
-
-	exists, err := gitManager.BranchExistsOnRemote(fixBranchName)
-	if err != nil {
+	if skip, err := cfp.shouldSkipFixBranch(fixBranchName, false, gitManager); err != nil || skip {
 		return err
 	}
-	if exists {
-		log.Info("Branch:", fixBranchName, "already exists on remote.")
-		return
-	}
 
 	log.Info("Creating branch:", fixBranchName)
 	err = gitManager.CreateBranchAndCheckout(fixBranchName)
@@ -258,7 +341,7 @@ func (cfp *CreateFixPullRequestsCmd) fixSinglePackageAndCreatePR(impactedPackage
 		return err
 	}
 
-	err = cfp.updatePackageToFixedVersion(fixVersionInfo.packageType, impactedPackage, fixVersionInfo.fixVersion, project.PipRequirementsFile, currentWd)
+	fixDescription, err := cfp.fixPackage(impactedPackage, fixVersionInfo, project, currentWd)
 	if err != nil {
 		return err
 	}
@@ -272,44 +355,125 @@ func (cfp *CreateFixPullRequestsCmd) fixSinglePackageAndCreatePR(impactedPackage
 		return fmt.Errorf("there were no changes to commit after fixing the package '%s'", impactedPackage)
 	}
 
+	commitString := "[🐸 Frogbot] " + fixDescription
+	prBody := commitString + "\n\n" + utils.WhatIsFrogbotMd
+	prTitle := generatePullRequestTitle(commitString, fixVersionInfo.severity, repoConfig)
+	return cfp.pushFixBranchAndCreatePR(fixBranchName, branch, commitString, prTitle, prBody, fixVersionInfo.severity, repoConfig, client, gitManager)
+}
+
+// Return true if the fix branch was already pushed, if a pull request from it is already open,
+// or if a fix pull request of the same dependency, or the same grouped fix pull request, was declined within the cooldown window, so that the fix is skipped
+func (cfp *CreateFixPullRequestsCmd) shouldSkipFixBranch(fixBranchName string, isGroup bool, gitManager *utils.GitManager) (bool, error) {
+	if cfp.openPullRequestsBranches[fixBranchName] {
+		log.Info("A pull request from branch", fixBranchName, "is already open. Skipping")
+		return true, nil
+	}
+	if cfp.declinedFixes[fixBranchName] || (!isGroup && cfp.declinedFixes[getFixBranchDependency(fixBranchName)]) {
+		log.Info("A fix pull request like the one from branch", fixBranchName, "was recently closed without being merged. Skipping")
+		return true, nil
+	}
+	exists, err := gitManager.BranchExistsOnRemote(fixBranchName)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		log.Info("Branch:", fixBranchName, "already exists on remote.")
+	}
+	return exists, nil
+}
+
+// Upgrade the impacted package to its fix version in the given working directory, and return the description of the fix for the commit message
+func (cfp *CreateFixPullRequestsCmd) fixPackage(impactedPackage string, fixVersionInfo FixVersionInfo, project *utils.Project, currentWd string) (string, error) {
+	if !fixVersionInfo.directDependency && isPackageOverrideSupported(fixVersionInfo.packageType) {
+		// Installing a transitive dependency would add it as a direct dependency, so its version is overridden instead
+		return fmt.Sprintf("Override the transitive dependency %s to %s", impactedPackage, fixVersionInfo.fixVersion),
+			overridePackageVersion(fixVersionInfo.packageType, impactedPackage, fixVersionInfo.fixVersion, currentWd)
+	}
+	return fmt.Sprintf("Upgrade %s to %s", impactedPackage, fixVersionInfo.fixVersion),
+		cfp.updatePackageToFixedVersion(fixVersionInfo.packageType, impactedPackage, fixVersionInfo.fixVersion, project.PipRequirementsFile, currentWd)
+}
+
+// Commit the changes of the fix branch, push it and create the fix pull request to the scanned branch.
+// The reviewers of the pull request are requested according to its severity, which is the highest severity it fixes.
+func (cfp *CreateFixPullRequestsCmd) pushFixBranchAndCreatePR(fixBranchName, branch, commitString, prTitle, prBody, severity string, repoConfig *utils.FrogbotRepoConfig,
+	client vcsclient.VcsClient, gitManager *utils.GitManager) error {
 	log.Info("Running git add all and commit")
-	commitString := fmt.Sprintf("[🐸 Frogbot] Upgrade %s to %s", impactedPackage, fixVersionInfo.fixVersion)
-	err = gitManager.AddAllAndCommit(commitString)
+	err := gitManager.AddAllAndCommit(commitString)
 	if err != nil {
 		return err
 	}
 	log.Info("Pushing fix branch:", fixBranchName)
 	err = gitManager.Push()
 	if err != nil {
+		// The branch may have been pushed by another Frogbot run since it was checked
+		if exists, e := gitManager.BranchExistsOnRemote(fixBranchName); e == nil && exists {
+			log.Info("Branch:", fixBranchName, "was created on remote by another run. Skipping")
+			return nil
+		}
 		return err
 	}
 	log.Info("Creating Pull Request form:", fixBranchName, " to:", branch)
-	prBody := commitString + "\n\n" + utils.WhatIsFrogbotMd
-	err = client.CreatePullRequest(context.Background(), repoGitParams.RepoOwner, repoGitParams.RepoName, fixBranchName, branch, commitString, prBody)
-	return
+	err = client.CreatePullRequest(context.Background(), repoConfig.RepoOwner, repoConfig.RepoName, fixBranchName, branch, prTitle, prBody)
+	if err != nil && getOpenPullRequestsBranches(repoConfig, client, branch)[fixBranchName] {
+		log.Info("A pull request from branch", fixBranchName, "was opened by another run. Skipping")
+		return nil
+	}
+	if err != nil {
+		return &VcsError{Err: err}
+	}
+	requestFixPRReviewers(repoConfig, fixBranchName, severity)
+	labelFixPR(repoConfig, fixBranchName)
+	return nil
+}
+
+// Return the source branches of the open pull requests to the given branch.
+// If the pull requests can't be listed, nil is returned, and the existing fix pull requests are detected by their branches on remote only.
+func getOpenPullRequestsBranches(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient, branch string) map[string]bool {
+	pullRequests, err := client.ListOpenPullRequests(context.Background(), repoConfig.RepoOwner, repoConfig.RepoName)
+	if err != nil {
+		log.Warn("couldn't list the open pull requests:", err.Error())
+		return nil
+	}
+	branches := make(map[string]bool)
+	for _, pullRequest := range pullRequests {
+		if getShortBranchName(pullRequest.Target.Name) == getShortBranchName(branch) {
+			branches[getShortBranchName(pullRequest.Source.Name)] = true
+		}
+	}
+	return branches
+}
+
+// Some git providers return the full branch names, such as refs/heads/master
+func getShortBranchName(branch string) string {
+	return strings.TrimPrefix(branch, "refs/heads/")
+}
+
+// generatePullRequestTitle returns the fix pull request title, prefixed by the severity badge if configured.
+// The title is truncated to the maximum length allowed by the git provider.
+func generatePullRequestTitle(title, severity string, repoConfig *utils.FrogbotRepoConfig) string {
+	if repoConfig.PullRequestTitleSeverityBadge {
+		if badge := utils.GetSeverityBadge(severity, repoConfig.SeverityColors); badge != "" {
+			title = badge + " " + title
+		}
+	}
+	return utils.TruncatePullRequestTitle(title, repoConfig.GitProvider)
 }
 
 func (cfp *CreateFixPullRequestsCmd) updatePackageToFixedVersion(packageType coreutils.Technology, impactedPackage, fixVersion, requirementsFile string, workingDir string) (err error) {
 	// 'CD' into the relevant working directory
 	if workingDir != "" {
-		restoreDir, err := utils.Chdir(workingDir)
-		if err != nil {
+		var restoreDir func() error
+		if restoreDir, err = utils.Chdir(workingDir); err != nil {
 			return err
 		}
 		defer func() {
-			e := restoreDir()
-			if err == nil {
-				err = e
-			} else if e != nil {
-				err = fmt.Errorf("%s\n%s", err.Error(), e.Error())
-			}
+			err = errors.Join(err, restoreDir())
 		}()
 	}
 
 	switch packageType {
 	case coreutils.Go:
-		commandArgs := []string{"get"}
-		err = fixPackageVersionGeneric(packageType.GetExecCommandName(), commandArgs, impactedPackage, fixVersion, "@v")
+		err = fixPackageVersionGo(impactedPackage, fixVersion)
 	case coreutils.Npm:
 		commandArgs := []string{"install"}
 		err = fixPackageVersionGeneric(packageType.GetExecCommandName(), commandArgs, impactedPackage, fixVersion, "@")
@@ -442,6 +606,7 @@ func generateFixBranchName(baseBranch, impactedPackage, fixVersion string) (stri
 // (1.0,)      --> 1.0 &lt; x
 // (1.0, 2.0)  --> 1.0 &lt; x &lt; 2.0
 // [1.0, 2.0]  --> 1.0 ≤ x ≤ 2.0
+// The 'v' prefix of the Go versions is trimmed, so that the fix versions are compared with the trimmed current version.
 func parseVersionChangeString(fixVersion string) string {
 	latestVersion := strings.Split(fixVersion, ",")[0]
 	if latestVersion[0] == '(' {
@@ -449,16 +614,31 @@ func parseVersionChangeString(fixVersion string) string {
 	}
 	latestVersion = strings.Trim(latestVersion, "[")
 	latestVersion = strings.Trim(latestVersion, "]")
-	return latestVersion
+	return strings.TrimPrefix(latestVersion, "v")
 }
 
 type FixVersionInfo struct {
 	fixVersion  string
 	packageType coreutils.Technology
+	// The highest severity among the vulnerabilities fixed by fixVersion
+	severity         string
+	severityNumValue int
+	// True if the package is a direct dependency of the project, in at least one of the vulnerabilities
+	directDependency bool
+}
+
+// Return true if the impacted package of the vulnerability is one of its direct dependencies
+func isDirectDependency(vulnerability formats.VulnerabilityOrViolationRow) bool {
+	for _, component := range vulnerability.Components {
+		if component.Name == vulnerability.ImpactedDependencyName {
+			return true
+		}
+	}
+	return false
 }
 
 func NewFixVersionInfo(newFixVersion string, packageType coreutils.Technology) *FixVersionInfo {
-	return &FixVersionInfo{newFixVersion, packageType}
+	return &FixVersionInfo{fixVersion: newFixVersion, packageType: packageType}
 }
 
 func (fvi *FixVersionInfo) UpdateFixVersion(newFixVersion string) {
@@ -467,3 +647,11 @@ func (fvi *FixVersionInfo) UpdateFixVersion(newFixVersion string) {
 		fvi.fixVersion = newFixVersion
 	}
 }
+
+func (fvi *FixVersionInfo) UpdateSeverity(severity string, severityNumValue int) {
+	// Keep the highest severity among all the vulnerabilities fixed by this version
+	if fvi.severity == "" || severityNumValue > fvi.severityNumValue {
+		fvi.severity = severity
+		fvi.severityNumValue = severityNumValue
+	}
+}
@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -8,7 +10,9 @@ import (
 
 	testdatautils "github.com/jfrog/build-info-go/build/testdata"
 	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsclient"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
 	"github.com/jfrog/jfrog-client-go/xray/services"
 	"github.com/stretchr/testify/assert"
 )
@@ -125,6 +129,7 @@ func TestParseVersionChangeString(t *testing.T) {
 		{"1.2.3", "1.2.3"},
 		{"[1.2.3]", "1.2.3"},
 		{"[1.2.3, 2.0.0]", "1.2.3"},
+		{"[v1.2.3]", "1.2.3"},
 
 		{"(,1.2.3]", ""},
 		{"(,1.2.3)", ""},
@@ -201,6 +206,51 @@ func TestGetMinimalFixVersion(t *testing.T) {
 	assert.Equal(t, "", getMinimalFixVersion(impactedVersionPackage, fixVersions))
 }
 
+func TestGetFixVersion(t *testing.T) {
+	fixVersions := []string{"[1.5.3]", "[1.6.1]", "[1.6.22]", "[1.6.30]", "[1.7.0]", "[2.0.1]"}
+	assert.Equal(t, "1.6.22", getFixVersion("", "1.6.2", fixVersions))
+	assert.Equal(t, "1.6.22", getFixVersion(utils.MinimalUpgradeStrategy, "1.6.2", fixVersions))
+	assert.Equal(t, "1.6.30", getFixVersion(utils.MinorUpgradeStrategy, "v1.6.2", fixVersions))
+	assert.Equal(t, "2.0.1", getFixVersion(utils.LatestUpgradeStrategy, "1.6.2", fixVersions))
+	// Without a fix version in the same minor version, the minimal fix version is chosen
+	assert.Equal(t, "1.7.0", getFixVersion(utils.MinorUpgradeStrategy, "1.6.31", fixVersions))
+	assert.Equal(t, "", getFixVersion(utils.LatestUpgradeStrategy, "2.1.0", fixVersions))
+
+	// The 'v' prefix of the Go versions is trimmed from both the current version and the fix versions
+	goFixVersions := []string{"[v1.6.1]", "[v1.6.22]", "[v1.6.30]", "[v1.7.0]"}
+	assert.Equal(t, "1.6.22", getFixVersion(utils.MinimalUpgradeStrategy, "v1.6.2", goFixVersions))
+	assert.Equal(t, "1.6.30", getFixVersion(utils.MinorUpgradeStrategy, "v1.6.2", goFixVersions))
+	assert.Equal(t, "1.7.0", getFixVersion(utils.LatestUpgradeStrategy, "1.6.2", goFixVersions))
+}
+
+func TestUpdateSeverity(t *testing.T) {
+	fixVersionInfo := NewFixVersionInfo("1.2.3", coreutils.Npm)
+	fixVersionInfo.UpdateSeverity("Medium", 2)
+	fixVersionInfo.UpdateSeverity("Critical", 4)
+	fixVersionInfo.UpdateSeverity("Low", 1)
+	assert.Equal(t, "Critical", fixVersionInfo.severity)
+}
+
+func TestIsDirectDependency(t *testing.T) {
+	vulnerability := formats.VulnerabilityOrViolationRow{
+		ImpactedDependencyName: "minimist",
+		Components:             []formats.ComponentRow{{Name: "mkdirp", Version: "0.5.1"}},
+	}
+	assert.False(t, isDirectDependency(vulnerability))
+	vulnerability.Components = append(vulnerability.Components, formats.ComponentRow{Name: "minimist", Version: "1.2.0"})
+	assert.True(t, isDirectDependency(vulnerability))
+}
+
+func TestGeneratePullRequestTitle(t *testing.T) {
+	repoConfig := &utils.FrogbotRepoConfig{}
+	title := "[🐸 Frogbot] Upgrade lodash to 4.17.21"
+	assert.Equal(t, title, generatePullRequestTitle(title, "High", repoConfig))
+
+	repoConfig.PullRequestTitleSeverityBadge = true
+	assert.Equal(t, "🟠 "+title, generatePullRequestTitle(title, "High", repoConfig))
+	assert.Equal(t, title, generatePullRequestTitle(title, "Unknown", repoConfig))
+}
+
 func verifyTechnologyNaming(t *testing.T, scanResponse []services.ScanResponse, expectedType coreutils.Technology) {
 	for _, resp := range scanResponse {
 		for _, vulnerability := range resp.Vulnerabilities {
@@ -208,3 +258,25 @@ func verifyTechnologyNaming(t *testing.T, scanResponse []services.ScanResponse,
 		}
 	}
 }
+
+func TestGetOpenPullRequestsBranches(t *testing.T) {
+	repoConfig := &utils.FrogbotRepoConfig{Params: utils.Params{Git: utils.Git{RepoOwner: "jfrog", RepoName: "frogbot"}}}
+	client := mockVcsClient(t)
+	client.EXPECT().ListOpenPullRequests(context.Background(), "jfrog", "frogbot").Return([]vcsclient.PullRequestInfo{
+		{ID: 1, Source: vcsclient.BranchInfo{Name: "frogbot-lodash-123"}, Target: vcsclient.BranchInfo{Name: "master"}},
+		{ID: 2, Source: vcsclient.BranchInfo{Name: "refs/heads/frogbot-minimist-456"}, Target: vcsclient.BranchInfo{Name: "refs/heads/master"}},
+		{ID: 3, Source: vcsclient.BranchInfo{Name: "frogbot-lodash-789"}, Target: vcsclient.BranchInfo{Name: "dev"}},
+	}, nil)
+	assert.Equal(t, map[string]bool{"frogbot-lodash-123": true, "frogbot-minimist-456": true}, getOpenPullRequestsBranches(repoConfig, client, "master"))
+
+	client.EXPECT().ListOpenPullRequests(context.Background(), "jfrog", "frogbot").Return(nil, errors.New("forbidden"))
+	assert.Nil(t, getOpenPullRequestsBranches(repoConfig, client, "master"))
+}
+
+func TestFixSinglePackageSkipsOpenPullRequest(t *testing.T) {
+	fixBranchName, err := generateFixBranchName("master", "lodash", "4.17.21")
+	assert.NoError(t, err)
+	cfp := CreateFixPullRequestsCmd{openPullRequestsBranches: map[string]bool{fixBranchName: true}}
+	// The fix is skipped before any git operation
+	assert.NoError(t, cfp.fixSinglePackageAndCreatePR("lodash", FixVersionInfo{fixVersion: "4.17.21"}, &utils.Project{}, "master", &utils.FrogbotRepoConfig{}, nil, nil, ""))
+}
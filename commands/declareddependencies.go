@@ -0,0 +1,345 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	mavenPomFileName = "pom.xml"
+	// The number of parents of a Maven module which are read, which stops the loops of invalid parents
+	maxMavenParents = 10
+	// The depth of the nested references to Maven properties which are resolved, which stops the loops of properties which reference each other
+	maxMavenPropertiesDepth = 10
+)
+
+// The references to the Maven properties, such as ${project.groupId}
+var mavenPropertyRegex = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// The names of the direct dependencies declared in the manifests of a project, by their technologies
+type declaredDependencies map[coreutils.Technology]map[string]bool
+
+// A manifest which declares the direct dependencies of its module
+type declaredDependenciesManifest struct {
+	technologies []coreutils.Technology
+	// Return the names of the declared dependencies, and the paths of the submodules, relative to the directory of the manifest.
+	// The path of the manifest is used to read the Maven parents.
+	parse func(manifestPath string, content []byte) (names, modules []string, err error)
+}
+
+var declaredDependenciesManifests = map[string]declaredDependenciesManifest{
+	packageJsonFileName: {technologies: []coreutils.Technology{coreutils.Npm, coreutils.Yarn}, parse: getNpmDeclaredDependencies},
+	mavenPomFileName:    {technologies: []coreutils.Technology{coreutils.Maven}, parse: getMavenDeclaredDependencies},
+	goModFileName:       {technologies: []coreutils.Technology{coreutils.Go}, parse: getGoDeclaredDependencies},
+}
+
+// setDeclaredDirectComponents sets the direct dependencies of the issues, shown in the direct dependencies column, to the dependencies declared in the manifests
+// of the working dirs, rather than to the children of the roots of the dependency graph. In an impact path, the direct dependency is the nearest dependency to the root,
+// which is declared in a manifest. The direct dependencies inferred from the graph are kept for the issues of other technologies, and for the issues with no declared
+// dependency in their impact paths. The rows are copied, so the rows of the caller aren't modified.
+// The declared dependencies are used only if the project sets them as its direct dependencies source.
+func setDeclaredDirectComponents(project *utils.Project, rows []formats.VulnerabilityOrViolationRow) []formats.VulnerabilityOrViolationRow {
+	if len(rows) == 0 || project.DirectDependencies != utils.ManifestDirectDependencies {
+		return rows
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		log.Debug("couldn't read the declared dependencies:", err.Error())
+		return rows
+	}
+	declared := getDeclaredDependencies(getFullPathWorkingDirs(project, wd))
+	if len(declared) == 0 {
+		return rows
+	}
+	rows = append([]formats.VulnerabilityOrViolationRow{}, rows...)
+	for index := range rows {
+		if components := getDeclaredDirectComponents(rows[index].ImpactPaths, declared[rows[index].Technology]); len(components) > 0 {
+			rows[index].Components = components
+		}
+	}
+	return rows
+}
+
+func getDeclaredDirectComponents(impactPaths [][]formats.ComponentRow, declaredNames map[string]bool) (components []formats.ComponentRow) {
+	if len(declaredNames) == 0 {
+		return nil
+	}
+	added := make(map[formats.ComponentRow]bool)
+	for _, impactPath := range impactPaths {
+		// The first component of the path is the root of the graph, which is the scanned module
+		for componentIndex := 1; componentIndex < len(impactPath); componentIndex++ {
+			component := impactPath[componentIndex]
+			if !declaredNames[component.Name] {
+				continue
+			}
+			if !added[component] {
+				added[component] = true
+				components = append(components, component)
+			}
+			break
+		}
+	}
+	return
+}
+
+// Read the dependencies declared in the manifests of the working dirs, and of their Maven submodules.
+// A manifest which can't be parsed is skipped, so the direct dependencies of its issues are inferred from the graph.
+func getDeclaredDependencies(workDirs []string) declaredDependencies {
+	declared := make(declaredDependencies)
+	visited := make(map[string]bool)
+	var addDir func(dir string)
+	addDir = func(dir string) {
+		if visited[dir] {
+			return
+		}
+		visited[dir] = true
+		for fileName, manifest := range declaredDependenciesManifests {
+			manifestPath := filepath.Join(dir, fileName)
+			content, err := os.ReadFile(manifestPath) // #nosec G304
+			if err != nil {
+				if !os.IsNotExist(err) {
+					log.Debug("couldn't read the manifest", manifestPath+":", err.Error())
+				}
+				continue
+			}
+			names, modules, err := manifest.parse(manifestPath, content)
+			if err != nil {
+				log.Debug("couldn't parse the declared dependencies of", manifestPath+":", err.Error())
+				continue
+			}
+			for _, tech := range manifest.technologies {
+				if declared[tech] == nil {
+					declared[tech] = make(map[string]bool)
+				}
+				for _, name := range names {
+					declared[tech][name] = true
+				}
+			}
+			for _, module := range modules {
+				addDir(filepath.Join(dir, module))
+			}
+		}
+	}
+	for _, workDir := range workDirs {
+		addDir(workDir)
+	}
+	return declared
+}
+
+func getNpmDeclaredDependencies(_ string, content []byte) (names, modules []string, err error) {
+	var packageJson struct {
+		Dependencies         map[string]string `json:"dependencies"`
+		DevDependencies      map[string]string `json:"devDependencies"`
+		OptionalDependencies map[string]string `json:"optionalDependencies"`
+		PeerDependencies     map[string]string `json:"peerDependencies"`
+	}
+	if err = json.Unmarshal(content, &packageJson); err != nil {
+		return nil, nil, err
+	}
+	for _, dependenciesMap := range []map[string]string{packageJson.Dependencies, packageJson.DevDependencies, packageJson.OptionalDependencies, packageJson.PeerDependencies} {
+		for name := range dependenciesMap {
+			names = append(names, name)
+		}
+	}
+	return
+}
+
+type mavenPom struct {
+	GroupId      string            `xml:"groupId"`
+	ArtifactId   string            `xml:"artifactId"`
+	Version      string            `xml:"version"`
+	Parent       *mavenParent      `xml:"parent"`
+	Properties   mavenProperties   `xml:"properties"`
+	Dependencies []mavenDependency `xml:"dependencies>dependency"`
+	Modules      []string          `xml:"modules>module"`
+}
+
+type mavenParent struct {
+	GroupId    string `xml:"groupId"`
+	ArtifactId string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	// Nil if the element is missing, in which case the parent is read from the parent directory
+	RelativePath *string `xml:"relativePath"`
+}
+
+type mavenDependency struct {
+	GroupId    string `xml:"groupId"`
+	ArtifactId string `xml:"artifactId"`
+}
+
+// The properties section of a pom.xml file, by the names of the properties
+type mavenProperties map[string]string
+
+func (mp *mavenProperties) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	properties := make(mavenProperties)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch element := token.(type) {
+		case xml.StartElement:
+			var value string
+			if err = decoder.DecodeElement(&value, &element); err != nil {
+				return err
+			}
+			properties[element.Name.Local] = strings.TrimSpace(value)
+		case xml.EndElement:
+			*mp = properties
+			return nil
+		}
+	}
+}
+
+// The groupId of the module, which is inherited from its parent if it isn't set
+func (pom *mavenPom) getGroupId() string {
+	if groupId := strings.TrimSpace(pom.GroupId); groupId != "" || pom.Parent == nil {
+		return groupId
+	}
+	return strings.TrimSpace(pom.Parent.GroupId)
+}
+
+// The dependencies of the project are declared in its dependencies section, and in the dependencies sections of its parents, which it inherits.
+// The dependency management section, including the imported BOMs, only sets the versions of the dependencies, and isn't parsed.
+// The ${} references to properties in the groupIds and artifactIds are resolved, as Maven does, after the dependencies are inherited.
+func getMavenDeclaredDependencies(manifestPath string, content []byte) (names, modules []string, err error) {
+	var pom mavenPom
+	if err = xml.Unmarshal(content, &pom); err != nil {
+		return nil, nil, err
+	}
+	lineage := append([]*mavenPom{&pom}, getMavenParentPoms(manifestPath, &pom)...)
+	properties := getMavenProperties(lineage)
+	for _, module := range lineage {
+		for _, dependency := range module.Dependencies {
+			names = append(names, resolveMavenProperties(dependency.GroupId, properties)+":"+resolveMavenProperties(dependency.ArtifactId, properties))
+		}
+	}
+	for _, module := range pom.Modules {
+		modules = append(modules, strings.TrimSpace(module))
+	}
+	return
+}
+
+// Return the parents of the Maven module which are in the repository, starting with its parent. Like Maven, the parent is read from its relativePath,
+// which defaults to the pom.xml file of the parent directory, and is used only if its groupId and artifactId are the ones declared by the module.
+// The parents which aren't in the repository aren't downloaded, so the dependencies they declare aren't read.
+func getMavenParentPoms(manifestPath string, pom *mavenPom) (parents []*mavenPom) {
+	for pom.Parent != nil && len(parents) < maxMavenParents {
+		relativePath := filepath.Join("..", mavenPomFileName)
+		if pom.Parent.RelativePath != nil {
+			relativePath = strings.TrimSpace(*pom.Parent.RelativePath)
+		}
+		if relativePath == "" {
+			// An empty relativePath sets the parent to be read from the Maven repositories
+			return
+		}
+		parentPath := filepath.Join(filepath.Dir(manifestPath), filepath.FromSlash(relativePath))
+		if info, err := os.Stat(parentPath); err == nil && info.IsDir() {
+			parentPath = filepath.Join(parentPath, mavenPomFileName)
+		}
+		content, err := os.ReadFile(parentPath) // #nosec G304
+		if err != nil {
+			if !os.IsNotExist(err) {
+				log.Debug("couldn't read the Maven parent", parentPath+":", err.Error())
+			}
+			return
+		}
+		parent := &mavenPom{}
+		if err = xml.Unmarshal(content, parent); err != nil {
+			log.Debug("couldn't parse the Maven parent", parentPath+":", err.Error())
+			return
+		}
+		if parent.getGroupId() != strings.TrimSpace(pom.Parent.GroupId) || strings.TrimSpace(parent.ArtifactId) != strings.TrimSpace(pom.Parent.ArtifactId) {
+			log.Debug(parentPath, "isn't the Maven parent", pom.Parent.GroupId+":"+pom.Parent.ArtifactId, "of", manifestPath)
+			return
+		}
+		parents = append(parents, parent)
+		manifestPath, pom = parentPath, parent
+	}
+	return
+}
+
+// Return the properties of the module, which are its properties section and the properties inherited from its parents, which it overrides,
+// and the project properties, such as project.groupId. The lineage starts with the module, followed by its parents.
+func getMavenProperties(lineage []*mavenPom) map[string]string {
+	properties := make(map[string]string)
+	for index := len(lineage) - 1; index >= 0; index-- {
+		for name, value := range lineage[index].Properties {
+			properties[name] = value
+		}
+	}
+	pom := lineage[0]
+	version := strings.TrimSpace(pom.Version)
+	if pom.Parent != nil {
+		properties["project.parent.groupId"] = strings.TrimSpace(pom.Parent.GroupId)
+		properties["project.parent.artifactId"] = strings.TrimSpace(pom.Parent.ArtifactId)
+		properties["project.parent.version"] = strings.TrimSpace(pom.Parent.Version)
+		if version == "" {
+			version = properties["project.parent.version"]
+		}
+	}
+	properties["project.groupId"] = pom.getGroupId()
+	properties["project.artifactId"] = strings.TrimSpace(pom.ArtifactId)
+	properties["project.version"] = version
+	return properties
+}
+
+// Replace the references to the properties of the value, including the references in the values of the properties.
+// The references to unknown properties are kept, as Maven does.
+func resolveMavenProperties(value string, properties map[string]string) string {
+	value = strings.TrimSpace(value)
+	for depth := 0; depth < maxMavenPropertiesDepth && strings.Contains(value, "${"); depth++ {
+		resolved := mavenPropertyRegex.ReplaceAllStringFunc(value, func(reference string) string {
+			if propertyValue, exists := properties[reference[2:len(reference)-1]]; exists {
+				return propertyValue
+			}
+			return reference
+		})
+		if resolved == value {
+			break
+		}
+		value = resolved
+	}
+	return value
+}
+
+// The modules required by the go.mod file, other than the modules marked as indirect
+func getGoDeclaredDependencies(_ string, content []byte) (names, modules []string, err error) {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	inRequireBlock := false
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		indirect := strings.HasSuffix(line, "// indirect")
+		if commentStart := strings.Index(line, "//"); commentStart >= 0 {
+			line = strings.TrimSpace(line[:commentStart])
+		}
+		var requirement string
+		switch {
+		case inRequireBlock && line == ")":
+			inRequireBlock = false
+			continue
+		case inRequireBlock:
+			requirement = line
+		case line == "require (":
+			inRequireBlock = true
+			continue
+		case strings.HasPrefix(line, "require "):
+			requirement = strings.TrimPrefix(line, "require ")
+		}
+		if fields := strings.Fields(requirement); len(fields) == 2 && !indirect {
+			names = append(names, fields[0])
+		}
+	}
+	return names, nil, scanner.Err()
+}
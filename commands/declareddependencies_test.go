@@ -0,0 +1,186 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+const declaredDependenciesTestPom = `<project>
+    <groupId>org.jfrog.test</groupId>
+    <artifactId>multi</artifactId>
+    <version>3.7</version>
+    <modules>
+        <module>multi1</module>
+    </modules>
+    <dependencyManagement>
+        <dependencies>
+            <dependency>
+                <groupId>org.springframework.boot</groupId>
+                <artifactId>spring-boot-dependencies</artifactId>
+                <version>2.7.0</version>
+                <type>pom</type>
+                <scope>import</scope>
+            </dependency>
+        </dependencies>
+    </dependencyManagement>
+    <dependencies>
+        <dependency>
+            <groupId>org.springframework.boot</groupId>
+            <artifactId>spring-boot-starter-web</artifactId>
+        </dependency>
+    </dependencies>
+</project>`
+
+const declaredDependenciesTestModulePom = `<project>
+    <parent>
+        <groupId>org.jfrog.test</groupId>
+        <artifactId>multi</artifactId>
+        <version>3.7</version>
+    </parent>
+    <artifactId>multi1</artifactId>
+    <properties>
+        <jackson.groupId>com.fasterxml.jackson.core</jackson.groupId>
+    </properties>
+    <dependencies>
+        <dependency>
+            <groupId>${jackson.groupId}</groupId>
+            <artifactId>jackson-databind</artifactId>
+        </dependency>
+    </dependencies>
+</project>`
+
+func TestGetDeclaredDependencies(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "pom.xml"), []byte(declaredDependenciesTestPom), 0600))
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "multi1"), 0700))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "multi1", "pom.xml"), []byte(declaredDependenciesTestModulePom), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"dependencies": {"lodash": "4.17.20"}, "devDependencies": {"jest": "^29.0.0"}}`), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte(`module github.com/jfrog/frogbot-test
+
+go 1.19
+
+require github.com/jfrog/froggit-go v1.6.1
+
+require (
+	github.com/google/go-github/v45 v45.2.0
+	golang.org/x/net v0.7.0 // indirect
+)
+`), 0600))
+
+	declared := getDeclaredDependencies([]string{dir})
+	// The BOM imported by the dependency management section isn't a direct dependency
+	assert.Equal(t, map[string]bool{"org.springframework.boot:spring-boot-starter-web": true, "com.fasterxml.jackson.core:jackson-databind": true}, declared[coreutils.Maven])
+	assert.Equal(t, map[string]bool{"lodash": true, "jest": true}, declared[coreutils.Npm])
+	assert.Equal(t, declared[coreutils.Npm], declared[coreutils.Yarn])
+	assert.Equal(t, map[string]bool{"github.com/jfrog/froggit-go": true, "github.com/google/go-github/v45": true}, declared[coreutils.Go])
+}
+
+func TestGetMavenDeclaredDependencies(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "pom.xml"), []byte(`<project>
+    <groupId>org.jfrog.test</groupId>
+    <artifactId>parent</artifactId>
+    <properties>
+        <logging.artifactId>log4j-core</logging.artifactId>
+        <logging.groupId>wrong</logging.groupId>
+    </properties>
+    <dependencies>
+        <dependency>
+            <groupId>org.apache.logging.log4j</groupId>
+            <artifactId>${logging.artifactId}</artifactId>
+        </dependency>
+    </dependencies>
+</project>`), 0600))
+	modulePath := filepath.Join(dir, "module", "pom.xml")
+	assert.NoError(t, os.Mkdir(filepath.Dir(modulePath), 0700))
+	modulePom := `<project>
+    <parent>
+        <groupId>org.jfrog.test</groupId>
+        <artifactId>%s</artifactId>
+        %s
+    </parent>
+    <artifactId>module</artifactId>
+    <properties>
+        <logging.groupId>org.slf4j</logging.groupId>
+        <api.artifactId>${logging.api}</api.artifactId>
+        <logging.api>slf4j-api</logging.api>
+    </properties>
+    <dependencies>
+        <dependency>
+            <groupId>${project.groupId}</groupId>
+            <artifactId>sibling</artifactId>
+        </dependency>
+        <dependency>
+            <groupId>${logging.groupId}</groupId>
+            <artifactId>${api.artifactId}</artifactId>
+        </dependency>
+        <dependency>
+            <groupId>org.unknown</groupId>
+            <artifactId>${unknown}</artifactId>
+        </dependency>
+    </dependencies>
+</project>`
+	moduleDependencies := []string{"org.jfrog.test:sibling", "org.slf4j:slf4j-api", "org.unknown:${unknown}"}
+
+	// The module inherits the groupId, the properties and the dependencies of its parent, and overrides its properties
+	names, _, err := getMavenDeclaredDependencies(modulePath, []byte(fmt.Sprintf(modulePom, "parent", "")))
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, append(moduleDependencies, "org.apache.logging.log4j:log4j-core"), names)
+
+	// The parent is read from its relative path
+	names, _, err = getMavenDeclaredDependencies(modulePath, []byte(fmt.Sprintf(modulePom, "parent", "<relativePath>..</relativePath>")))
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, append(moduleDependencies, "org.apache.logging.log4j:log4j-core"), names)
+
+	// The parent isn't read from the repository if its relative path is empty, or if the pom.xml file in its path is of another module
+	for _, parent := range [][]any{{"parent", "<relativePath/>"}, {"other", ""}} {
+		names, _, err = getMavenDeclaredDependencies(modulePath, []byte(fmt.Sprintf(modulePom, parent...)))
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, moduleDependencies, names)
+	}
+}
+
+func TestSetDeclaredDirectComponents(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "pom.xml"), []byte(declaredDependenciesTestPom), 0600))
+	restoreDir, err := utils.Chdir(dir)
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, restoreDir())
+	}()
+
+	root := formats.ComponentRow{Name: "org.jfrog.test:multi", Version: "3.7"}
+	bom := formats.ComponentRow{Name: "org.springframework.boot:spring-boot-dependencies", Version: "2.7.0"}
+	starter := formats.ComponentRow{Name: "org.springframework.boot:spring-boot-starter-web", Version: "2.7.0"}
+	snakeyaml := formats.ComponentRow{Name: "org.yaml:snakeyaml", Version: "1.30"}
+	rows := []formats.VulnerabilityOrViolationRow{
+		// The graph infers the BOM as the direct dependency, while the declared dependency is the starter
+		{Technology: coreutils.Maven, ImpactedDependencyName: snakeyaml.Name, Components: []formats.ComponentRow{bom}, ImpactPaths: [][]formats.ComponentRow{{root, bom, starter, snakeyaml}}},
+		// No declared dependency in the impact path
+		{Technology: coreutils.Maven, ImpactedDependencyName: "org.other:lib", Components: []formats.ComponentRow{bom}, ImpactPaths: [][]formats.ComponentRow{{root, bom, {Name: "org.other:lib"}}}},
+		// No manifest of the technology
+		{Technology: coreutils.Npm, ImpactedDependencyName: "minimist", Components: []formats.ComponentRow{{Name: "mkdirp"}}, ImpactPaths: [][]formats.ComponentRow{{{Name: "root"}, {Name: "mkdirp"}, {Name: "minimist"}}}},
+	}
+
+	// The declared dependencies aren't used by default
+	project := &utils.Project{}
+	assert.Equal(t, []formats.ComponentRow{bom}, setDeclaredDirectComponents(project, rows)[0].Components)
+
+	project.DirectDependencies = utils.ManifestDirectDependencies
+	declaredRows := setDeclaredDirectComponents(project, rows)
+	assert.Equal(t, []formats.ComponentRow{starter}, declaredRows[0].Components)
+	assert.Equal(t, []formats.ComponentRow{bom}, declaredRows[1].Components)
+	assert.Equal(t, []formats.ComponentRow{{Name: "mkdirp"}}, declaredRows[2].Components)
+	// The rows of the caller aren't modified
+	assert.Equal(t, []formats.ComponentRow{bom}, rows[0].Components)
+
+	project.DirectDependencies = utils.GraphDirectDependencies
+	assert.Equal(t, []formats.ComponentRow{bom}, setDeclaredDirectComponents(project, rows)[0].Components)
+}
@@ -0,0 +1,201 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	coreconfig "github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+)
+
+// The results of comparing the dependency trees of the source and the target branches, which replace the full scan of the target branch
+type deltaScanResults struct {
+	// The scans of the paths to the dependencies removed by the pull request, which are the only dependencies of the target branch whose issues aren't in the source branch scan
+	targetScans []services.ScanResponse
+	// The IDs of the dependencies added by the pull request
+	addedIds map[string]bool
+	// The direct dependencies of the target branch, against which the direct dependencies which introduced the new issues are found
+	targetDirectDependencies map[formats.ComponentRow]bool
+}
+
+// auditDelta builds the dependency trees of the project in the source and the target branches, and scans with Xray only the paths from the roots of the target trees
+// to the dependencies removed by the pull request, rather than fully scanning the target branch. The issues of a component are the same in both branches,
+// so the new issues of the pull request are the issues of the added dependencies in the source branch scan, selected by getNewIssuesRows.
+// If no dependency was removed, Xray isn't called at all. Returns nil if the trees of either branch can't be built, so that the target branch is fully scanned instead.
+func auditDelta(targetBranch *targetBranchCheckout, xrayScanParams services.XrayGraphScanParams, project utils.Project, repoConfig *utils.FrogbotRepoConfig) (*deltaScanResults, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	sourceTrees, err := buildDeltaScanTrees(&project, getFullPathWorkingDirs(&project, wd), true)
+	if err != nil || sourceTrees == nil {
+		return nil, err
+	}
+	var targetTrees []*technologyTrees
+	err = inTargetBranch(targetBranch, project, func(fullPathWds []string) (e error) {
+		targetTrees, e = buildDeltaScanTrees(&project, fullPathWds, false)
+		return
+	})
+	if err != nil || targetTrees == nil {
+		return nil, err
+	}
+
+	results := &deltaScanResults{targetDirectDependencies: getDirectDependenciesFromTrees(targetTrees)}
+	_, results.addedIds = pruneTrees(sourceTrees, targetTrees)
+	removedTrees, removedIds := pruneTrees(targetTrees, sourceTrees)
+	log.Info(fmt.Sprintf("The pull request adds %d dependencies and removes %d dependencies", len(results.addedIds), len(removedIds)))
+	if len(removedTrees) == 0 {
+		return results, nil
+	}
+	log.Info("Scanning the dependencies removed by the pull request, rather than the whole target branch")
+	results.targetScans, _, err = auditWithFailover(&repoConfig.Server, repoConfig.XrayFailoverUrls, func(server *coreconfig.ServerDetails) ([]services.ScanResponse, bool, error) {
+		return scanDeltaTrees(xrayScanParams, server, removedTrees)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Return the trees pruned to the paths to the dependencies which aren't in the other trees, and the IDs of these dependencies
+func pruneTrees(techTrees, otherTechTrees []*technologyTrees) (prunedTrees []*technologyTrees, prunedIds map[string]bool) {
+	otherIds := make(map[string]bool)
+	for _, techTree := range otherTechTrees {
+		collectNodesIds(techTree.trees, otherIds)
+	}
+	prunedIds = make(map[string]bool)
+	for _, techTree := range techTrees {
+		prunedTree := &technologyTrees{technology: techTree.technology}
+		for _, tree := range techTree.trees {
+			if pruned := pruneToAddedDependencies(tree, otherIds, prunedIds, true, map[string]bool{}); pruned != nil {
+				prunedTree.trees = append(prunedTree.trees, pruned)
+			}
+		}
+		if len(prunedTree.trees) > 0 {
+			prunedTrees = append(prunedTrees, prunedTree)
+		}
+	}
+	return
+}
+
+// Return the new issues of the pull request, which are the issues of the added dependencies in the source branch scan
+func (delta *deltaScanResults) getNewIssuesRows(currentScan []services.ScanResponse, isMultipleRoot bool) ([]formats.VulnerabilityOrViolationRow, error) {
+	currentIssuesRows, err := createNewIssuesRows(nil, currentScan, isMultipleRoot)
+	if err != nil {
+		return nil, err
+	}
+	return filterAddedDependenciesIssues(currentIssuesRows, delta.addedIds), nil
+}
+
+// Return the issues whose impacted dependencies are added
+func filterAddedDependenciesIssues(issuesRows []formats.VulnerabilityOrViolationRow, addedIds map[string]bool) (addedIssuesRows []formats.VulnerabilityOrViolationRow) {
+	addedComponents := make(map[formats.ComponentRow]bool)
+	for id := range addedIds {
+		addedComponents[getComponentRow(id)] = true
+	}
+	for _, row := range issuesRows {
+		if addedComponents[formats.ComponentRow{Name: row.ImpactedDependencyName, Version: row.ImpactedDependencyVersion}] {
+			addedIssuesRows = append(addedIssuesRows, row)
+		}
+	}
+	return
+}
+
+// Run the installation command of the project, and build the dependency trees of the working dirs.
+// Returns nil if the trees can't be built for all the technologies of the working dirs, so that the delta of the branches can't be isolated.
+func buildDeltaScanTrees(project *utils.Project, workDirs []string, failOnInstallationErrors bool) ([]*technologyTrees, error) {
+	for _, wd := range workDirs {
+		if err := runInstallIfNeeded(project, wd, failOnInstallationErrors); err != nil {
+			return nil, err
+		}
+		// The dependency trees of .NET projects aren't built separately from their audit
+		if technologies, err := coreutils.DetectTechnologies(wd, false, false); err == nil && technologies[coreutils.Dotnet] {
+			log.Info("Scanning both branches, since the .NET dependencies of", wd, "can't be compared")
+			return nil, nil
+		}
+	}
+	techTrees, errorList := buildDependencyTrees(project, workDirs)
+	if len(errorList) > 0 {
+		log.Info("Scanning both branches, since the dependency trees couldn't be built:\n" + strings.Join(errorList, "\n"))
+		return nil, nil
+	}
+	return techTrees, nil
+}
+
+// Scan the delta trees of each technology in a single Xray graph scan
+func scanDeltaTrees(xrayScanParams services.XrayGraphScanParams, server *coreconfig.ServerDetails, deltaTrees []*technologyTrees) (results []services.ScanResponse, isMultipleRoot bool, err error) {
+	xrayVersion, err := getGraphScanXrayVersion(server)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, techTree := range deltaTrees {
+		isMultipleRoot = isMultipleRoot || len(techTree.trees) > 1
+		// The delta trees are specific to the pull request, so they aren't cached
+		scanResults, err := scanBatch(xrayScanParams, server, xrayVersion, techTree.technology, techTree.trees, nil)
+		if err != nil {
+			return nil, false, fmt.Errorf("'%s' audit command failed:\n%s", techTree.technology, err.Error())
+		}
+		results = append(results, *scanResults)
+	}
+	return
+}
+
+func collectNodesIds(nodes []*services.GraphNode, ids map[string]bool) {
+	for _, node := range nodes {
+		if !ids[node.Id] {
+			ids[node.Id] = true
+			collectNodesIds(node.Nodes, ids)
+		}
+	}
+}
+
+// Return a copy of the tree which includes only the paths to the dependencies which aren't in targetIds, or nil if all the dependencies of the tree are in targetIds.
+// The added dependencies are added to addedIds. The root of the tree is the scanned module, rather than a dependency, and is kept if any of its dependencies was added.
+func pruneToAddedDependencies(node *services.GraphNode, targetIds, addedIds map[string]bool, isRoot bool, ancestorsIds map[string]bool) *services.GraphNode {
+	if ancestorsIds[node.Id] {
+		// A dependency cycle
+		return nil
+	}
+	ancestorsIds[node.Id] = true
+	defer delete(ancestorsIds, node.Id)
+	added := !isRoot && !targetIds[node.Id]
+	var prunedNodes []*services.GraphNode
+	for _, child := range node.Nodes {
+		if pruned := pruneToAddedDependencies(child, targetIds, addedIds, false, ancestorsIds); pruned != nil {
+			prunedNodes = append(prunedNodes, pruned)
+		}
+	}
+	if !added && len(prunedNodes) == 0 {
+		return nil
+	}
+	if added {
+		addedIds[node.Id] = true
+	}
+	pruned := *node
+	pruned.Nodes = prunedNodes
+	return &pruned
+}
+
+// Return the direct dependencies of all the trees, which are the children of their roots
+func getDirectDependenciesFromTrees(techTrees []*technologyTrees) map[formats.ComponentRow]bool {
+	directDependencies := make(map[formats.ComponentRow]bool)
+	for _, techTree := range techTrees {
+		for _, tree := range techTree.trees {
+			for _, node := range tree.Nodes {
+				directDependencies[getComponentRow(node.Id)] = true
+			}
+		}
+	}
+	return directDependencies
+}
+
+func getComponentRow(componentId string) formats.ComponentRow {
+	name, version, _ := xrayutils.SplitComponentId(componentId)
+	return formats.ComponentRow{Name: name, Version: version}
+}
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func createDeltaScanTestTree(lodashVersion string) *services.GraphNode {
+	return &services.GraphNode{Id: "npm://my-app:1.0.0", Nodes: []*services.GraphNode{
+		{Id: "npm://express:4.18.2", Nodes: []*services.GraphNode{{Id: "npm://qs:6.11.0"}}},
+		{Id: "npm://async:3.2.4", Nodes: []*services.GraphNode{{Id: "npm://lodash:" + lodashVersion}}},
+	}}
+}
+
+func TestPruneToAddedDependencies(t *testing.T) {
+	targetIds := make(map[string]bool)
+	collectNodesIds([]*services.GraphNode{createDeltaScanTestTree("4.17.21")}, targetIds)
+
+	// Only the paths to the added dependencies are kept
+	addedIds := make(map[string]bool)
+	pruned := pruneToAddedDependencies(createDeltaScanTestTree("4.17.15"), targetIds, addedIds, true, map[string]bool{})
+	assert.Equal(t, &services.GraphNode{Id: "npm://my-app:1.0.0", Nodes: []*services.GraphNode{
+		{Id: "npm://async:3.2.4", Nodes: []*services.GraphNode{{Id: "npm://lodash:4.17.15"}}},
+	}}, pruned)
+	assert.Equal(t, map[string]bool{"npm://lodash:4.17.15": true}, addedIds)
+
+	// A tree without added dependencies isn't scanned, even if its root changed
+	addedIds = make(map[string]bool)
+	tree := createDeltaScanTestTree("4.17.21")
+	tree.Id = "npm://my-app:1.1.0"
+	assert.Nil(t, pruneToAddedDependencies(tree, targetIds, addedIds, true, map[string]bool{}))
+	assert.Empty(t, addedIds)
+}
+
+func TestPruneToAddedDependenciesCycle(t *testing.T) {
+	cyclic := &services.GraphNode{Id: "npm://a:1.0.0"}
+	cyclic.Nodes = []*services.GraphNode{{Id: "npm://b:1.0.0", Nodes: []*services.GraphNode{cyclic}}}
+	root := &services.GraphNode{Id: "npm://my-app:1.0.0", Nodes: []*services.GraphNode{cyclic}}
+	addedIds := make(map[string]bool)
+	pruned := pruneToAddedDependencies(root, map[string]bool{}, addedIds, true, map[string]bool{})
+	assert.Equal(t, map[string]bool{"npm://a:1.0.0": true, "npm://b:1.0.0": true}, addedIds)
+	assert.Empty(t, pruned.Nodes[0].Nodes[0].Nodes)
+}
+
+func TestGetDirectDependenciesFromTrees(t *testing.T) {
+	directDependencies := getDirectDependenciesFromTrees([]*technologyTrees{{trees: []*services.GraphNode{createDeltaScanTestTree("4.17.21")}}})
+	assert.Equal(t, map[formats.ComponentRow]bool{{Name: "express", Version: "4.18.2"}: true, {Name: "async", Version: "3.2.4"}: true}, directDependencies)
+}
+
+func TestFilterAddedDependenciesIssues(t *testing.T) {
+	rows := []formats.VulnerabilityOrViolationRow{
+		{ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "4.17.15", IssueId: "XRAY-1"},
+		// An issue of a dependency on the path to the added dependency, which is in the target branch too
+		{ImpactedDependencyName: "async", ImpactedDependencyVersion: "3.2.4", IssueId: "XRAY-2"},
+	}
+	assert.Equal(t, rows[:1], filterAddedDependenciesIssues(rows, map[string]bool{"npm://lodash:4.17.15": true}))
+}
+
+func TestPruneTrees(t *testing.T) {
+	sourceTrees := []*technologyTrees{{trees: []*services.GraphNode{createDeltaScanTestTree("4.17.15")}}}
+	targetTrees := []*technologyTrees{{trees: []*services.GraphNode{createDeltaScanTestTree("4.17.21")}}}
+	_, addedIds := pruneTrees(sourceTrees, targetTrees)
+	assert.Equal(t, map[string]bool{"npm://lodash:4.17.15": true}, addedIds)
+
+	// The target trees are pruned to the paths to the removed dependencies, which are scanned instead of the whole target branch
+	removedTrees, removedIds := pruneTrees(targetTrees, sourceTrees)
+	assert.Equal(t, map[string]bool{"npm://lodash:4.17.21": true}, removedIds)
+	if assert.Len(t, removedTrees, 1) {
+		assert.Equal(t, []*services.GraphNode{{Id: "npm://my-app:1.0.0", Nodes: []*services.GraphNode{
+			{Id: "npm://async:3.2.4", Nodes: []*services.GraphNode{{Id: "npm://lodash:4.17.21"}}},
+		}}}, removedTrees[0].trees)
+	}
+
+	// Unchanged trees have nothing to scan
+	removedTrees, removedIds = pruneTrees(targetTrees, targetTrees)
+	assert.Empty(t, removedTrees)
+	assert.Empty(t, removedIds)
+}
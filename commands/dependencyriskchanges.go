@@ -0,0 +1,148 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+)
+
+const riskIncreasedTitle = "#### ⚠️ Dependencies updated to versions with new issues"
+
+// Display order of the severities in the risk change
+var riskChangeSeverities = []string{"Critical", "High", "Medium", "Low"}
+
+// The change in the issues of a dependency, which was updated by the pull request
+type dependencyRiskChange struct {
+	name             string
+	previousVersions []string
+	currentVersions  []string
+	// The number of added and removed issues per severity
+	addedIssues   map[string]int
+	removedIssues map[string]int
+}
+
+// The versions and issues of a single dependency in a scan
+type dependencyIssues struct {
+	versions map[string]bool
+	// Maps the issue ID to its severity
+	issues map[string]string
+}
+
+// getDependenciesRiskChanges compares the issues of each dependency found in both branches, and returns the dependencies which were updated
+// to a version with new issues, even if the update fixes other issues.
+func getDependenciesRiskChanges(previousRows, currentRows []formats.VulnerabilityOrViolationRow) []dependencyRiskChange {
+	previousDependencies := groupIssuesByDependency(previousRows)
+	currentDependencies := groupIssuesByDependency(currentRows)
+	var riskChanges []dependencyRiskChange
+	for name, current := range currentDependencies {
+		previous, exists := previousDependencies[name]
+		if !exists || equalVersions(previous.versions, current.versions) {
+			continue
+		}
+		riskChange := dependencyRiskChange{
+			name:             name,
+			previousVersions: sortedVersions(previous.versions),
+			currentVersions:  sortedVersions(current.versions),
+			addedIssues:      countMissingIssues(current.issues, previous.issues),
+			removedIssues:    countMissingIssues(previous.issues, current.issues),
+		}
+		if riskChange.highestAddedSeverity() != "" {
+			riskChanges = append(riskChanges, riskChange)
+		}
+	}
+	sort.Slice(riskChanges, func(i, j int) bool {
+		return riskChanges[i].name < riskChanges[j].name
+	})
+	return riskChanges
+}
+
+func groupIssuesByDependency(vulnerabilitiesRows []formats.VulnerabilityOrViolationRow) map[string]*dependencyIssues {
+	dependencies := make(map[string]*dependencyIssues)
+	for _, row := range vulnerabilitiesRows {
+		dependency, exists := dependencies[row.ImpactedDependencyName]
+		if !exists {
+			dependency = &dependencyIssues{versions: map[string]bool{}, issues: map[string]string{}}
+			dependencies[row.ImpactedDependencyName] = dependency
+		}
+		dependency.versions[row.ImpactedDependencyVersion] = true
+		dependency.issues[getIssueDisplayId(row)] = row.Severity
+	}
+	return dependencies
+}
+
+// Count, per severity, the issues which exist in issues but not in otherIssues
+func countMissingIssues(issues, otherIssues map[string]string) map[string]int {
+	counts := make(map[string]int)
+	for issueId, severity := range issues {
+		if _, exists := otherIssues[issueId]; !exists {
+			counts[severity]++
+		}
+	}
+	return counts
+}
+
+func equalVersions(versions, otherVersions map[string]bool) bool {
+	if len(versions) != len(otherVersions) {
+		return false
+	}
+	for version := range versions {
+		if !otherVersions[version] {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedVersions(versions map[string]bool) []string {
+	sorted := make([]string, 0, len(versions))
+	for version := range versions {
+		sorted = append(sorted, version)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// Format the change as "+1 High, -2 Medium"
+func (change *dependencyRiskChange) String() string {
+	var parts []string
+	for _, severity := range riskChangeSeverities {
+		if count := change.addedIssues[severity]; count > 0 {
+			parts = append(parts, fmt.Sprintf("+%d %s", count, severity))
+		}
+	}
+	for _, severity := range riskChangeSeverities {
+		if count := change.removedIssues[severity]; count > 0 {
+			parts = append(parts, fmt.Sprintf("-%d %s", count, severity))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Create notes which highlight the dependencies that were updated to riskier versions
+func createRiskChangesNotes(riskChanges []dependencyRiskChange, severityColors map[string]string) string {
+	if len(riskChanges) == 0 {
+		return ""
+	}
+	var notes strings.Builder
+	for _, change := range riskChanges {
+		notes.WriteString(fmt.Sprintf("- **%s** %s → %s: %s %s\n",
+			change.name,
+			strings.Join(change.previousVersions, ", "),
+			strings.Join(change.currentVersions, ", "),
+			utils.GetSeverityBadge(change.highestAddedSeverity(), severityColors),
+			change.String()))
+	}
+	return "\n\n" + riskIncreasedTitle + "\n\n" + notes.String()
+}
+
+func (change *dependencyRiskChange) highestAddedSeverity() string {
+	for _, severity := range riskChangeSeverities {
+		if change.addedIssues[severity] > 0 {
+			return severity
+		}
+	}
+	return ""
+}
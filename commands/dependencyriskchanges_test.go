@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDependenciesRiskChanges(t *testing.T) {
+	previousRows := []formats.VulnerabilityOrViolationRow{
+		{ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "4.17.19", Severity: "Medium", IssueId: "XRAY-1"},
+		{ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "4.17.19", Severity: "Medium", IssueId: "XRAY-2"},
+		{ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "4.17.19", Severity: "High", IssueId: "XRAY-3"},
+		{ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "1.2.5", Severity: "High", IssueId: "XRAY-4"},
+		{ImpactedDependencyName: "express", ImpactedDependencyVersion: "4.0.0", Severity: "Low", IssueId: "XRAY-5"},
+	}
+	currentRows := []formats.VulnerabilityOrViolationRow{
+		// Updated to a version which fixes two issues and introduces a new one
+		{ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "4.17.20", Severity: "High", IssueId: "XRAY-3"},
+		{ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "4.17.20", Severity: "High", IssueId: "XRAY-6"},
+		// Unchanged version
+		{ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "1.2.5", Severity: "High", IssueId: "XRAY-4"},
+		{ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "1.2.5", Severity: "Critical", IssueId: "XRAY-7"},
+		// Updated to a version with fewer issues
+		{ImpactedDependencyName: "express", ImpactedDependencyVersion: "4.1.0", Severity: "Low", IssueId: "XRAY-5"},
+		// New dependency
+		{ImpactedDependencyName: "axios", ImpactedDependencyVersion: "0.21.0", Severity: "High", IssueId: "XRAY-8"},
+	}
+	riskChanges := getDependenciesRiskChanges(previousRows, currentRows)
+	if assert.Len(t, riskChanges, 1) {
+		change := riskChanges[0]
+		assert.Equal(t, "lodash", change.name)
+		assert.Equal(t, []string{"4.17.19"}, change.previousVersions)
+		assert.Equal(t, []string{"4.17.20"}, change.currentVersions)
+		assert.Equal(t, "+1 High, -2 Medium", change.String())
+		assert.Equal(t, "High", change.highestAddedSeverity())
+	}
+}
+
+func TestCreateRiskChangesNotes(t *testing.T) {
+	assert.Empty(t, createRiskChangesNotes(nil, nil))
+	riskChanges := []dependencyRiskChange{{
+		name:             "lodash",
+		previousVersions: []string{"4.17.19"},
+		currentVersions:  []string{"4.17.20"},
+		addedIssues:      map[string]int{"Critical": 1},
+		removedIssues:    map[string]int{"Low": 3},
+	}}
+	assert.Equal(t, "\n\n"+riskIncreasedTitle+"\n\n- **lodash** 4.17.19 → 4.17.20: 🔴 +1 Critical, -3 Low\n", createRiskChangesNotes(riskChanges, nil))
+}
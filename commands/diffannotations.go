@@ -0,0 +1,229 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const diffAnnotationsTitle = "#### 📍 Introduced in the diff"
+
+// The lock files list the whole dependency tree, so the direct dependencies are annotated in the manifests which declare them
+var lockFiles = map[string]bool{
+	"package-lock.json":   true,
+	"npm-shrinkwrap.json": true,
+	"yarn.lock":           true,
+	"go.sum":              true,
+	"Pipfile.lock":        true,
+	"poetry.lock":         true,
+}
+
+// A line added by the pull request to a manifest, which adds or updates a direct dependency
+type diffAnnotation struct {
+	// The path of the manifest, relative to the root of the repository
+	file       string
+	line       int
+	dependency formats.ComponentRow
+}
+
+// annotatePullRequestDiff maps the new issues to the lines added by the pull request to the manifests, which add or update their direct dependencies.
+// The issues whose direct dependencies weren't changed by the pull request aren't annotated. The added lines are taken from the diff of the pull request,
+// so that they can be commented on. If the provider doesn't support review comments, they are found by comparing the manifests with the target branch.
+func annotatePullRequestDiff(repoConfig *utils.FrogbotRepoConfig, targetBranch *targetBranchCheckout, vulnerabilitiesRows []formats.VulnerabilityOrViolationRow) (map[string][]diffAnnotation, error) {
+	if len(vulnerabilitiesRows) == 0 {
+		return nil, nil
+	}
+	var addedManifestLines map[string][]addedLine
+	if isReviewCommentsSupported(repoConfig.GitProvider) {
+		addedLines, err := getPullRequestAddedLines(repoConfig)
+		if err != nil {
+			return nil, err
+		}
+		addedManifestLines = filterManifestsAddedLines(addedLines)
+	} else {
+		sourceDir, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		targetDir, err := targetBranch.getDir()
+		if err != nil {
+			return nil, err
+		}
+		if addedManifestLines, err = getAddedManifestLines(sourceDir, targetDir); err != nil {
+			return nil, err
+		}
+	}
+	return getDiffAnnotations(vulnerabilitiesRows, addedManifestLines), nil
+}
+
+// Return the added lines of the manifests, except for the lock files
+func filterManifestsAddedLines(addedLines map[string][]addedLine) map[string][]addedLine {
+	addedManifestLines := make(map[string][]addedLine)
+	for file, lines := range addedLines {
+		fileName := path.Base(file)
+		if getManifestKind(fileName) != notManifest && !lockFiles[fileName] {
+			addedManifestLines[file] = lines
+		}
+	}
+	return addedManifestLines
+}
+
+// Return the lines added by the pull request to each manifest, except for the lock files, by the path of the manifest relative to sourceDir
+func getAddedManifestLines(sourceDir, targetDir string) (map[string][]addedLine, error) {
+	manifests, err := listManifests(sourceDir)
+	if err != nil {
+		return nil, err
+	}
+	addedManifestLines := make(map[string][]addedLine)
+	for manifest := range manifests {
+		if lockFiles[filepath.Base(manifest)] {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(sourceDir, manifest))
+		if err != nil {
+			return nil, err
+		}
+		addedLines, err := getAddedLines(content, filepath.Join(targetDir, manifest))
+		if err != nil {
+			return nil, err
+		}
+		if len(addedLines) > 0 {
+			addedManifestLines[filepath.ToSlash(manifest)] = addedLines
+		}
+	}
+	return addedManifestLines, nil
+}
+
+func getDiffAnnotations(vulnerabilitiesRows []formats.VulnerabilityOrViolationRow, addedManifestLines map[string][]addedLine) map[string][]diffAnnotation {
+	// Iterate the manifests in a stable order, so that the annotations don't change between scans
+	var manifests []string
+	for manifest := range addedManifestLines {
+		manifests = append(manifests, manifest)
+	}
+	sort.Strings(manifests)
+	annotations := make(map[string][]diffAnnotation)
+	for _, row := range vulnerabilitiesRows {
+		for _, dependency := range getDirectDependencies(row) {
+			for _, manifest := range manifests {
+				if line, found := findDependencyLine(addedManifestLines[manifest], dependency.Name); found {
+					annotations[getUniqueID(row)] = append(annotations[getUniqueID(row)], diffAnnotation{file: manifest, line: line, dependency: dependency})
+					break
+				}
+			}
+		}
+	}
+	return annotations
+}
+
+// Return the unique direct dependencies through which the issue was introduced. The first node in the impact path is the scanned project, and the second one is the direct dependency.
+func getDirectDependencies(row formats.VulnerabilityOrViolationRow) (dependencies []formats.ComponentRow) {
+	found := make(map[formats.ComponentRow]bool)
+	for _, impactPath := range row.ImpactPaths {
+		if len(impactPath) < 2 || found[impactPath[1]] {
+			continue
+		}
+		found[impactPath[1]] = true
+		dependencies = append(dependencies, impactPath[1])
+	}
+	return
+}
+
+// Find the first added line which includes the name of the dependency, as a whole word.
+// Maven and Gradle dependencies are also found by their artifact ID, since the group ID and the artifact ID are usually declared in separate elements.
+func findDependencyLine(addedLines []addedLine, dependencyName string) (int, bool) {
+	patterns := []*regexp.Regexp{getDependencyNamePattern(dependencyName)}
+	if _, artifactId, found := strings.Cut(dependencyName, ":"); found {
+		patterns = append(patterns, regexp.MustCompile(`<artifactId>\s*`+regexp.QuoteMeta(artifactId)+`\s*</artifactId>`))
+	}
+	for _, pattern := range patterns {
+		for _, line := range addedLines {
+			if pattern.MatchString(line.text) {
+				return line.number, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func getDependencyNamePattern(dependencyName string) *regexp.Regexp {
+	const nameCharacters = `A-Za-z0-9_.\-/@`
+	return regexp.MustCompile(`(?:^|[^` + nameCharacters + `])` + regexp.QuoteMeta(dependencyName) + `(?:$|[^` + nameCharacters + `])`)
+}
+
+// Create a note with the lines added by the pull request, which introduced the direct dependencies of the new issues
+func createDiffAnnotationsNote(vulnerabilitiesRows []formats.VulnerabilityOrViolationRow, annotations map[string][]diffAnnotation) string {
+	var notes strings.Builder
+	for _, row := range vulnerabilitiesRows {
+		rowAnnotations, exists := annotations[getUniqueID(row)]
+		if !exists {
+			continue
+		}
+		var lines []string
+		for _, annotation := range rowAnnotations {
+			lines = append(lines, fmt.Sprintf("`%s:%d` (**%s %s**)", annotation.file, annotation.line, annotation.dependency.Name, annotation.dependency.Version))
+		}
+		notes.WriteString(fmt.Sprintf("- **%s %s** (%s) is introduced by %s\n", row.ImpactedDependencyName, row.ImpactedDependencyVersion, getIssueDisplayId(row), strings.Join(lines, ", ")))
+	}
+	if notes.Len() == 0 {
+		return ""
+	}
+	return "\n\n" + diffAnnotationsTitle + "\n\n" + notes.String()
+}
+
+// Create a review comment on each line added by the pull request, which introduced the direct dependency of new issues
+func createDiffReviewComments(vulnerabilitiesRows []formats.VulnerabilityOrViolationRow, annotations map[string][]diffAnnotation) []reviewComment {
+	var annotatedLines []diffAnnotation
+	issues := make(map[diffAnnotation][]string)
+	for _, row := range vulnerabilitiesRows {
+		for _, annotation := range annotations[getUniqueID(row)] {
+			if _, exists := issues[annotation]; !exists {
+				annotatedLines = append(annotatedLines, annotation)
+			}
+			issues[annotation] = append(issues[annotation], fmt.Sprintf("- **%s %s** (%s)", row.ImpactedDependencyName, row.ImpactedDependencyVersion, getIssueDisplayId(row)))
+		}
+	}
+	var comments []reviewComment
+	for _, annotation := range annotatedLines {
+		body := fmt.Sprintf("🐸 **%s %s** introduces:\n\n%s\n\n%s", annotation.dependency.Name, annotation.dependency.Version, strings.Join(issues[annotation], "\n"), diffAnnotationMarker)
+		comments = append(comments, reviewComment{file: annotation.file, line: annotation.line, body: body})
+	}
+	return comments
+}
+
+// addDiffAnnotations annotates the new issues with the lines of the pull request which introduced them.
+// The annotations are posted as review comments on these lines, or listed in the comment if the provider doesn't support review comments.
+// The annotations are an addition to the comment only, so a failure to find them doesn't fail the scan.
+func (results *auditResults) addDiffAnnotations(repoConfig *utils.FrogbotRepoConfig, targetBranch *targetBranchCheckout) {
+	annotations, err := annotatePullRequestDiff(repoConfig, targetBranch, results.vulnerabilitiesRows)
+	if err != nil {
+		log.Warn("couldn't find the lines of the pull request which introduced the issues:", err.Error())
+		return
+	}
+	if isReviewCommentsSupported(repoConfig.GitProvider) {
+		results.diffReviewComments = createDiffReviewComments(results.vulnerabilitiesRows, annotations)
+		return
+	}
+	results.diffAnnotations = annotations
+}
+
+// postDiffReviewComments posts the review comments of the diff annotations. If they can't be posted, they are logged instead.
+func (results *auditResults) postDiffReviewComments(repoConfig *utils.FrogbotRepoConfig) {
+	if len(results.diffReviewComments) == 0 {
+		return
+	}
+	log.Info(fmt.Sprintf("Commenting on the %d lines of the pull request which introduced the new issues", len(results.diffReviewComments)))
+	if err := postReviewComments(repoConfig, results.diffReviewComments); err != nil {
+		log.Warn("couldn't comment on the lines of the pull request which introduced the issues:", err.Error())
+		for _, comment := range results.diffReviewComments {
+			log.Info(fmt.Sprintf("%s:%d: %s", comment.file, comment.line, strings.TrimSuffix(comment.body, "\n\n"+diffAnnotationMarker)))
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAddedManifestLines(t *testing.T) {
+	sourceDir, targetDir := t.TempDir(), t.TempDir()
+	writeTestFiles(t, targetDir, map[string]string{
+		"package.json":      "{\n  \"dependencies\": {\n    \"express\": \"4.17.0\"\n  }\n}\n",
+		"package-lock.json": "{}\n",
+	})
+	writeTestFiles(t, sourceDir, map[string]string{
+		"package.json":      "{\n  \"dependencies\": {\n    \"express\": \"4.17.0\",\n    \"lodash\": \"4.17.20\"\n  }\n}\n",
+		"package-lock.json": "{\"lodash\": \"4.17.20\"}\n",
+		"server/go.mod":     "module github.com/jfrog/server\n\nrequire github.com/gin-gonic/gin v1.7.0\n",
+		"README.md":         "lodash\n",
+	})
+	addedManifestLines, err := getAddedManifestLines(sourceDir, targetDir)
+	assert.NoError(t, err)
+	// The lock files and the files which aren't manifests are ignored
+	assert.Equal(t, map[string][]addedLine{
+		"package.json": {{number: 3, text: `    "express": "4.17.0",`}, {number: 4, text: `    "lodash": "4.17.20"`}},
+		"server/go.mod": {
+			{number: 1, text: "module github.com/jfrog/server"},
+			{number: 2, text: ""},
+			{number: 3, text: "require github.com/gin-gonic/gin v1.7.0"},
+		},
+	}, addedManifestLines)
+}
+
+func TestFindDependencyLine(t *testing.T) {
+	addedLines := []addedLine{
+		{number: 3, text: `    "lodash.merge": "4.6.2",`},
+		{number: 4, text: `    "lodash": "4.17.20"`},
+		{number: 7, text: "\tgithub.com/gin-gonic/gin v1.7.0"},
+		{number: 9, text: "flask==2.0.1"},
+		{number: 12, text: "      <artifactId>jackson-databind</artifactId>"},
+		{number: 15, text: "    implementation 'org.apache.commons:commons-text:1.9'"},
+	}
+	for name, expectedLine := range map[string]int{
+		"lodash":                   4,
+		"lodash.merge":             3,
+		"github.com/gin-gonic/gin": 7,
+		"flask":                    9,
+		"com.fasterxml.jackson.core:jackson-databind": 12,
+		"org.apache.commons:commons-text":             15,
+	} {
+		line, found := findDependencyLine(addedLines, name)
+		assert.True(t, found, name)
+		assert.Equal(t, expectedLine, line, name)
+	}
+	for _, name := range []string{"lodash.get", "github.com/gin-gonic", "gin", "minimist"} {
+		_, found := findDependencyLine(addedLines, name)
+		assert.False(t, found, name)
+	}
+}
+
+func TestDiffAnnotations(t *testing.T) {
+	rows := []formats.VulnerabilityOrViolationRow{
+		{ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "1.2.5", IssueId: "XRAY-1", Cves: []formats.CveRow{{Id: "CVE-2021-44906"}},
+			ImpactPaths: [][]formats.ComponentRow{
+				{{Name: "frogbot"}, {Name: "mkdirp", Version: "0.5.5"}, {Name: "minimist", Version: "1.2.5"}},
+				{{Name: "frogbot"}, {Name: "mkdirp", Version: "0.5.5"}, {Name: "other", Version: "1.0.0"}, {Name: "minimist", Version: "1.2.5"}},
+			}},
+		// The dependency wasn't changed by the pull request
+		{ImpactedDependencyName: "express", ImpactedDependencyVersion: "4.17.0", IssueId: "XRAY-2",
+			ImpactPaths: [][]formats.ComponentRow{{{Name: "frogbot"}, {Name: "express", Version: "4.17.0"}}}},
+	}
+	addedManifestLines := map[string][]addedLine{"package.json": {{number: 5, text: `    "mkdirp": "0.5.5"`}}}
+	annotations := getDiffAnnotations(rows, addedManifestLines)
+	assert.Equal(t, map[string][]diffAnnotation{
+		"minimist1.2.5XRAY-1": {{file: "package.json", line: 5, dependency: formats.ComponentRow{Name: "mkdirp", Version: "0.5.5"}}},
+	}, annotations)
+
+	assert.Equal(t, "\n\n"+diffAnnotationsTitle+"\n\n- **minimist 1.2.5** (CVE-2021-44906) is introduced by `package.json:5` (**mkdirp 0.5.5**)\n",
+		createDiffAnnotationsNote(rows, annotations))
+	assert.Empty(t, createDiffAnnotationsNote(rows, nil))
+
+	// The annotations of the same line are posted in a single review comment
+	rows = append(rows, formats.VulnerabilityOrViolationRow{ImpactedDependencyName: "mkdirp", ImpactedDependencyVersion: "0.5.5", IssueId: "XRAY-3",
+		ImpactPaths: [][]formats.ComponentRow{{{Name: "frogbot"}, {Name: "mkdirp", Version: "0.5.5"}}}})
+	annotations = getDiffAnnotations(rows, addedManifestLines)
+	assert.Equal(t, []reviewComment{{file: "package.json", line: 5,
+		body: "🐸 **mkdirp 0.5.5** introduces:\n\n- **minimist 1.2.5** (CVE-2021-44906)\n- **mkdirp 0.5.5** (XRAY-3)\n\n" + diffAnnotationMarker}},
+		createDiffReviewComments(rows, annotations))
+	assert.Empty(t, createDiffReviewComments(rows, nil))
+}
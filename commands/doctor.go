@@ -0,0 +1,191 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsclient"
+	coreconfig "github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	xraycommands "github.com/jfrog/jfrog-cli-core/v2/xray/commands"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray"
+)
+
+const (
+	errDoctorChecksFailed = "%d of the doctor checks failed"
+	// The Xray API which returns whether the JFrog Platform is entitled to a feature
+	entitlementApi                 = "api/v1/entitlements/feature/"
+	contextualAnalysisFeatureId    = "contextual_analysis"
+	doctorPassedStatus             = "✅"
+	doctorWarningStatus            = "⚠️"
+	doctorFailedStatus             = "❌"
+	doctorSkippedStatus            = "⏭️"
+	doctorSkippedAfterConfigDetail = "skipped, since the configuration couldn't be loaded"
+	doctorSkippedAfterGitDetail    = "skipped, since the Git provider params couldn't be loaded"
+	doctorSkippedAfterServerDetail = "skipped, since the JFrog Platform params couldn't be loaded"
+)
+
+type DoctorCmd struct {
+}
+
+// The result of a single doctor check
+type doctorCheck struct {
+	name    string
+	status  string
+	details string
+}
+
+type doctorReport struct {
+	checks []doctorCheck
+}
+
+// Run verifies the configuration, the connection and the authentication to the Git provider and to Xray, and the entitlements of the JFrog Platform,
+// and prints a report of all the checks. Unlike the scan commands, a failed check doesn't stop the run, so that all the problems are reported at once.
+// An error is returned if any of the checks failed.
+func (cmd DoctorCmd) Run() error {
+	report := &doctorReport{}
+	params := utils.GetDoctorParams()
+	for _, err := range params.TransportErrs {
+		report.addResult("Network configuration", "", err)
+	}
+	if len(params.TransportErrs) == 0 {
+		report.addResult("Network configuration", "", nil)
+	}
+	report.addResult("JFrog Platform params", "", params.ServerErr)
+	report.addResult("Git provider params", "", params.GitErr)
+	if params.Client == nil {
+		report.add("Configuration", doctorSkippedStatus, doctorSkippedAfterGitDetail)
+	} else {
+		report.addResult("Configuration", fmt.Sprintf("%d repositories configured", len(params.ConfigAggregator)), params.ConfigErr)
+	}
+	// The Xray check is run after the configuration is loaded, since the frogbot-config file may set the CA certificates and the custom headers of Xray
+	if params.Server != nil {
+		report.checkXray(params.Server)
+	} else {
+		report.add("Xray connection", doctorSkippedStatus, doctorSkippedAfterServerDetail)
+	}
+	if params.Client == nil || params.ConfigErr != nil {
+		report.add("Git provider access", doctorSkippedStatus, doctorSkippedAfterConfigDetail)
+	} else {
+		for index := range params.ConfigAggregator {
+			report.checkRepository(&params.ConfigAggregator[index], params.Client)
+		}
+	}
+	log.Output(report.String())
+	if failed := report.failedCount(); failed > 0 {
+		return fmt.Errorf(errDoctorChecksFailed, failed)
+	}
+	return nil
+}
+
+func (report *doctorReport) add(name, status, details string) {
+	report.checks = append(report.checks, doctorCheck{name: name, status: status, details: details})
+}
+
+// Add a check, which failed if err isn't nil
+func (report *doctorReport) addResult(name, details string, err error) {
+	if err != nil {
+		report.add(name, doctorFailedStatus, err.Error())
+		return
+	}
+	report.add(name, doctorPassedStatus, details)
+}
+
+// Check the connection and the authentication to Xray, and the entitlements which the optional features depend on
+func (report *doctorReport) checkXray(server *coreconfig.ServerDetails) {
+	xrayManager, err := utils.NewXrayServiceManager(server)
+	if err != nil {
+		report.addResult("Xray connection", "", err)
+		return
+	}
+	xrayVersion, err := xrayManager.GetVersion()
+	if err == nil {
+		err = coreutils.ValidateMinimumVersion(coreutils.Xray, xrayVersion, xraycommands.GraphScanMinXrayVersion)
+	}
+	report.addResult("Xray connection", "Xray version "+xrayVersion, err)
+	if err != nil {
+		report.add("Contextual analysis entitlement", doctorSkippedStatus, "skipped, since Xray isn't available")
+		return
+	}
+	// A missing entitlement doesn't fail the scans, which run without the feature
+	entitled, err := isEntitled(xrayManager, contextualAnalysisFeatureId)
+	switch {
+	case err != nil:
+		report.add("Contextual analysis entitlement", doctorWarningStatus, "couldn't read the entitlement: "+err.Error())
+	case entitled:
+		report.add("Contextual analysis entitlement", doctorPassedStatus, "the contextual analysis is available")
+	default:
+		report.add("Contextual analysis entitlement", doctorWarningStatus, "the JFrog Platform isn't entitled to the contextual analysis, so the applicability of the vulnerabilities isn't shown")
+	}
+}
+
+// Check the access of the Git token to the repository, and the configuration of the 'frogbot' GitHub environment on GitHub Actions
+func (report *doctorReport) checkRepository(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient) {
+	repoName := fmt.Sprintf("%s/%s", repoConfig.RepoOwner, repoConfig.RepoName)
+	_, err := client.GetRepositoryInfo(context.Background(), repoConfig.RepoOwner, repoConfig.RepoName)
+	report.addResult("Git provider access to "+repoName, "the repository is accessible on "+repoConfig.GitProvider.String(), err)
+	if err != nil {
+		return
+	}
+	if _, exist := os.LookupEnv(utils.GitHubActionsEnv); exist {
+		report.addResult("The 'frogbot' GitHub environment of "+repoName, "the environment is configured", verifyGitHubFrogbotEnvironment(client, repoConfig))
+	}
+}
+
+func (report *doctorReport) failedCount() (count int) {
+	for _, check := range report.checks {
+		if check.status == doctorFailedStatus {
+			count++
+		}
+	}
+	return
+}
+
+func (report *doctorReport) String() string {
+	var output strings.Builder
+	output.WriteString("Frogbot doctor report:\n")
+	for _, check := range report.checks {
+		output.WriteString(fmt.Sprintf("%s %s", check.status, check.name))
+		if check.details != "" {
+			// Only the first line of the errors is shown, to keep the report readable
+			details, _, _ := strings.Cut(check.details, "\n")
+			output.WriteString(": " + details)
+		}
+		output.WriteString("\n")
+	}
+	if failed := report.failedCount(); failed > 0 {
+		output.WriteString(fmt.Sprintf("\n%d of the %d checks failed", failed, len(report.checks)))
+	} else {
+		output.WriteString(fmt.Sprintf("\nAll the %d checks passed", len(report.checks)))
+	}
+	return output.String()
+}
+
+type entitlementResponse struct {
+	Entitled bool `json:"entitled"`
+}
+
+// Return true if the JFrog Platform is entitled to the Xray feature
+func isEntitled(xrayManager *xray.XrayServicesManager, featureId string) (bool, error) {
+	xrayDetails := xrayManager.Config().GetServiceDetails()
+	httpDetails := xrayDetails.CreateHttpClientDetails()
+	resp, body, _, err := xrayManager.Client().SendGet(xrayDetails.GetUrl()+entitlementApi+featureId, true, &httpDetails)
+	if err != nil {
+		return false, err
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return false, err
+	}
+	var response entitlementResponse
+	if err = json.Unmarshal(body, &response); err != nil {
+		return false, errorutils.CheckError(err)
+	}
+	return response.Entitled, nil
+}
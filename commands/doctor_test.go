@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsclient"
+	coreconfig "github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoctorReport(t *testing.T) {
+	report := &doctorReport{}
+	report.addResult("Configuration", "1 repositories configured", nil)
+	report.add("Contextual analysis entitlement", doctorWarningStatus, "not entitled")
+	report.addResult("Xray connection", "", errors.New("401 Unauthorized\nthe full response body"))
+	assert.Equal(t, 1, report.failedCount())
+	assert.Equal(t, "Frogbot doctor report:\n"+
+		"✅ Configuration: 1 repositories configured\n"+
+		"⚠️ Contextual analysis entitlement: not entitled\n"+
+		"❌ Xray connection: 401 Unauthorized\n"+
+		"\n1 of the 3 checks failed", report.String())
+
+	report = &doctorReport{}
+	report.addResult("Configuration", "", nil)
+	assert.Zero(t, report.failedCount())
+	assert.Equal(t, "Frogbot doctor report:\n✅ Configuration\n\nAll the 1 checks passed", report.String())
+}
+
+func TestDoctorCheckXray(t *testing.T) {
+	testCases := []struct {
+		name             string
+		xrayVersion      string
+		entitled         bool
+		expectedStatuses []string
+	}{
+		{name: "entitled", xrayVersion: "3.70.0", entitled: true, expectedStatuses: []string{doctorPassedStatus, doctorPassedStatus}},
+		{name: "not entitled", xrayVersion: "3.70.0", expectedStatuses: []string{doctorPassedStatus, doctorWarningStatus}},
+		{name: "unsupported version", xrayVersion: "3.0.0", expectedStatuses: []string{doctorFailedStatus, doctorSkippedStatus}},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var err error
+				switch r.URL.Path {
+				case "/xray/api/v1/system/version":
+					_, err = fmt.Fprintf(w, `{"xray_version": "%s"}`, test.xrayVersion)
+				case "/xray/" + entitlementApi + contextualAnalysisFeatureId:
+					_, err = fmt.Fprintf(w, `{"entitled": %t}`, test.entitled)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+				assert.NoError(t, err)
+			}))
+			defer server.Close()
+
+			report := &doctorReport{}
+			report.checkXray(&coreconfig.ServerDetails{XrayUrl: server.URL + "/xray/"})
+			assert.Len(t, report.checks, 2)
+			for i, check := range report.checks {
+				assert.Equal(t, test.expectedStatuses[i], check.status, check.details)
+			}
+		})
+	}
+}
+
+func TestDoctorCheckXrayCustomHeaders(t *testing.T) {
+	var entitlementOrgIds []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		switch r.URL.Path {
+		case "/xray/api/v1/system/version":
+			_, err = w.Write([]byte(`{"xray_version": "3.70.0"}`))
+		case "/xray/" + entitlementApi + contextualAnalysisFeatureId:
+			entitlementOrgIds = append(entitlementOrgIds, r.Header.Get("X-Org-Id"))
+			_, err = w.Write([]byte(`{"entitled": true}`))
+		}
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	defaultTransport := http.DefaultTransport
+	defer func() {
+		http.DefaultTransport = defaultTransport
+	}()
+
+	serverDetails := &coreconfig.ServerDetails{XrayUrl: server.URL + "/xray/"}
+	utils.ConfigureClientsHosts(nil, serverDetails)
+	assert.NoError(t, utils.ConfigureCustomHeaders(map[string]string{"X-Org-Id": "1234"}))
+	report := &doctorReport{}
+	report.checkXray(serverDetails)
+	// The entitlement request is sent with the custom headers of Xray
+	assert.Equal(t, []string{"1234"}, entitlementOrgIds)
+}
+
+func TestDoctorCheckRepository(t *testing.T) {
+	// Setenv restores the variable at the end of the test
+	t.Setenv(utils.GitHubActionsEnv, "")
+	assert.NoError(t, os.Unsetenv(utils.GitHubActionsEnv))
+	client := mockVcsClient(t)
+	client.EXPECT().GetRepositoryInfo(context.Background(), gitParams.RepoOwner, gitParams.RepoName).Return(vcsclient.RepositoryInfo{}, nil)
+	report := &doctorReport{}
+	report.checkRepository(gitParams, client)
+	assert.Len(t, report.checks, 1)
+	assert.Equal(t, doctorPassedStatus, report.checks[0].status)
+
+	// The token has no access to the repository
+	client.EXPECT().GetRepositoryInfo(context.Background(), gitParams.RepoOwner, gitParams.RepoName).Return(vcsclient.RepositoryInfo{}, errors.New("404 Not Found"))
+	report = &doctorReport{}
+	report.checkRepository(gitParams, client)
+	assert.Equal(t, []doctorCheck{{name: "Git provider access to repo-owner/repo-name", status: doctorFailedStatus, details: "404 Not Found"}}, report.checks)
+}
@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// shouldSkipDraftPullRequest returns true if the pull request is a draft, and draft pull requests aren't scanned.
+// The draft status isn't a part of the scan itself, so if it can't be read, the pull request is scanned.
+func shouldSkipDraftPullRequest(repoConfig *utils.FrogbotRepoConfig) bool {
+	if repoConfig.ScanDrafts || repoConfig.PullRequestID == 0 {
+		return false
+	}
+	isDraft, err := isDraftPullRequest(&repoConfig.Git, repoConfig.PullRequestID)
+	if err != nil {
+		log.Warn("couldn't check whether pull request", repoConfig.PullRequestID, "is a draft, so it's scanned:", err.Error())
+		return false
+	}
+	if isDraft {
+		log.Info(fmt.Sprintf("Pull request %d is a draft, and scanDrafts isn't set. Skipping the scan", repoConfig.PullRequestID))
+	}
+	return isDraft
+}
+
+// The froggit-go VCS client doesn't return the draft status of the pull requests, and therefore the GitHub and GitLab APIs are used directly.
+// On the other Git providers, the pull requests aren't considered drafts.
+func isDraftPullRequest(git *utils.Git, pullRequestID int) (bool, error) {
+	switch git.GitProvider {
+	case vcsutils.GitHub:
+		client, err := newGitHubClient(git)
+		if err != nil {
+			return false, err
+		}
+		pullRequest, _, err := client.PullRequests.Get(context.Background(), git.RepoOwner, git.RepoName, pullRequestID)
+		if err != nil {
+			return false, err
+		}
+		return pullRequest.GetDraft(), nil
+	case vcsutils.GitLab:
+		client, err := newGitLabClient(git)
+		if err != nil {
+			return false, err
+		}
+		mergeRequest, _, err := client.MergeRequests.GetMergeRequest(fmt.Sprintf("%s/%s", git.RepoOwner, git.RepoName), pullRequestID, nil)
+		if err != nil {
+			return false, err
+		}
+		// The work in progress status is the draft status of the merge request
+		return mergeRequest.WorkInProgress, nil
+	default:
+		return false, nil
+	}
+}
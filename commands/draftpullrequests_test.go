@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldSkipDraftPullRequest(t *testing.T) {
+	tests := []struct {
+		name         string
+		provider     vcsutils.VcsProvider
+		expectedPath string
+		response     string
+		status       int
+		scanDrafts   bool
+		expectedSkip bool
+	}{
+		{name: "githubDraft", provider: vcsutils.GitHub, expectedPath: "/repos/jfrog/frogbot/pulls/5", response: `{"number": 5, "draft": true}`, expectedSkip: true},
+		{name: "githubReady", provider: vcsutils.GitHub, expectedPath: "/repos/jfrog/frogbot/pulls/5", response: `{"number": 5, "draft": false}`},
+		{name: "gitlabDraft", provider: vcsutils.GitLab, expectedPath: "/api/v4/projects/jfrog%2Ffrogbot/merge_requests/5", response: `{"iid": 5, "work_in_progress": true}`, expectedSkip: true},
+		{name: "gitlabReady", provider: vcsutils.GitLab, expectedPath: "/api/v4/projects/jfrog%2Ffrogbot/merge_requests/5", response: `{"iid": 5, "work_in_progress": false}`},
+		{name: "scanDrafts", provider: vcsutils.GitHub, response: `{"number": 5, "draft": true}`, scanDrafts: true},
+		// The pull request is scanned if its draft status can't be read
+		{name: "error", provider: vcsutils.GitHub, expectedPath: "/repos/jfrog/frogbot/pulls/5", response: `{}`, status: http.StatusForbidden},
+		{name: "unsupportedProvider", provider: vcsutils.BitbucketServer},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var requestPath string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// The GitLab client sends a request to configure its rate limiter
+				if r.URL.Path == "/api/v4/" {
+					return
+				}
+				requestPath = r.URL.EscapedPath()
+				if test.status != 0 {
+					w.WriteHeader(test.status)
+				}
+				_, err := fmt.Fprint(w, test.response)
+				assert.NoError(t, err)
+			}))
+			defer server.Close()
+
+			repoConfig := &utils.FrogbotRepoConfig{Params: utils.Params{
+				Git: utils.Git{
+					GitProvider:   test.provider,
+					RepoOwner:     "jfrog",
+					RepoName:      "frogbot",
+					Token:         "123456",
+					ApiEndpoint:   server.URL,
+					PullRequestID: 5,
+				},
+				ScanDrafts: test.scanDrafts,
+			}}
+			assert.Equal(t, test.expectedSkip, shouldSkipDraftPullRequest(repoConfig))
+			assert.Equal(t, test.expectedPath, requestPath)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package commands
+
+// The errors below are returned by the Frogbot commands, so that tools which run the commands can tell the failure modes apart using errors.As.
+// Their messages are the messages of the errors they replaced.
+
+// SecurityIssuesFoundError is returned when the scan found issues which fail the Frogbot task according to the severity policy
+type SecurityIssuesFoundError struct{}
+
+func (e *SecurityIssuesFoundError) Error() string {
+	return securityIssueFoundErr
+}
+
+// ScanExecutionError is returned when a scan itself failed, such as the Xray scan or the secrets scan, rather than found issues
+type ScanExecutionError struct {
+	Err error
+}
+
+func (e *ScanExecutionError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ScanExecutionError) Unwrap() error {
+	return e.Err
+}
+
+// VcsError is returned when a request to the git provider failed, such as adding a pull request comment or listing the open pull requests
+type VcsError struct {
+	Err error
+}
+
+func (e *VcsError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *VcsError) Unwrap() error {
+	return e.Err
+}
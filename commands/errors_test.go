@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurityIssuesFoundError(t *testing.T) {
+	var err error = &SecurityIssuesFoundError{}
+	assert.EqualError(t, err, securityIssueFoundErr)
+	var securityIssuesFoundErr *SecurityIssuesFoundError
+	assert.True(t, errors.As(err, &securityIssuesFoundErr))
+	var scanExecutionErr *ScanExecutionError
+	assert.False(t, errors.As(err, &scanExecutionErr))
+}
+
+func TestVcsError(t *testing.T) {
+	repoConfig := &utils.FrogbotRepoConfig{Params: utils.Params{Git: utils.Git{RepoOwner: "jfrog", RepoName: "frogbot", PullRequestID: 1}}}
+	vcsErr := errors.New("bad gateway")
+	client := mockVcsClient(t)
+	client.EXPECT().AddPullRequestComment(context.Background(), "jfrog", "frogbot", "message", 1).Return(vcsErr)
+
+	err := commentScanResults(repoConfig, client, 1, "message")
+	// The message of the error is kept, and the error of the git provider can still be inspected
+	assert.EqualError(t, err, "couldn't add pull request comment: bad gateway")
+	var vcsError *VcsError
+	assert.ErrorAs(t, err, &vcsError)
+	assert.ErrorIs(t, err, vcsErr)
+
+	client = mockVcsClient(t)
+	client.EXPECT().ListOpenPullRequests(context.Background(), "jfrog", "frogbot").Return(nil, vcsErr)
+	err = scanAllPullRequests(*repoConfig, client)
+	assert.EqualError(t, err, "bad gateway")
+	assert.ErrorAs(t, err, &vcsError)
+}
@@ -0,0 +1,255 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/gofrog/version"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	fixChainsTitle         = "#### 🔗 Fix chains"
+	defaultNpmRegistryUrl  = "https://registry.npmjs.org/"
+	npmConfigRegistryEnv   = "NPM_CONFIG_REGISTRY"
+	npmConfigRegistryLower = "npm_config_registry"
+	// The media type of the abbreviated packuments, which the npm CLI requests when it installs packages
+	npmAbbreviatedPackument = "application/vnd.npm.install-v1+json"
+	npmRegistryTimeout      = 30 * time.Second
+	maxPackumentSize        = 20 * 1024 * 1024
+)
+
+// fixChain is the upgrade of a direct dependency, whose new version pulls a fixed version of a vulnerable transitive dependency
+type fixChain struct {
+	directDependency formats.ComponentRow
+	upgradeVersion   string
+	// The version of the impacted dependency, which is resolved through the upgraded direct dependency
+	resolvedVersion string
+}
+
+// The versions of an npm package and their dependencies, as returned by the npm registry
+type npmPackument struct {
+	Versions map[string]struct {
+		Dependencies map[string]string `json:"dependencies"`
+	} `json:"versions"`
+}
+
+// npmRegistryClient reads the packuments of the npm packages from the registry. The packuments are cached, since the same packages appear in many impact paths.
+type npmRegistryClient struct {
+	config     *npmConfig
+	httpClient *http.Client
+	packuments map[string]*npmPackument
+}
+
+// newNpmRegistryClient creates a client of the registries and the credentials configured for npm in the current dir of the scanned project
+func newNpmRegistryClient() *npmRegistryClient {
+	projectDir, err := os.Getwd()
+	if err != nil {
+		log.Debug("couldn't get the current dir, so its .npmrc file isn't read:", err.Error())
+	}
+	return &npmRegistryClient{
+		config:     loadNpmConfig(projectDir),
+		httpClient: utils.NewExternalHttpClient(npmRegistryTimeout),
+		packuments: make(map[string]*npmPackument),
+	}
+}
+
+func (nrc *npmRegistryClient) getPackument(packageName string) (*npmPackument, error) {
+	if packument, exists := nrc.packuments[packageName]; exists {
+		return packument, nil
+	}
+	// The slash of a scoped package, such as @types/node, is escaped in the registry URL
+	registryUrl := nrc.config.getRegistryUrl(packageName)
+	request, err := http.NewRequest(http.MethodGet, registryUrl+url.PathEscape(packageName), nil)
+	if err != nil {
+		return nil, err
+	}
+	// The abbreviated packument has the dependencies of the versions, and is much smaller than the full one
+	request.Header.Set("Accept", npmAbbreviatedPackument)
+	if authorization := nrc.config.getAuthorization(registryUrl); authorization != "" {
+		request.Header.Set("Authorization", authorization)
+	}
+	response, err := nrc.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if e := response.Body.Close(); e != nil {
+			log.Warn(e)
+		}
+	}()
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxPackumentSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the npm registry responded with %d for the package %s", response.StatusCode, packageName)
+	}
+	if len(body) > maxPackumentSize {
+		return nil, fmt.Errorf("the npm registry response for the package %s exceeds %d bytes", packageName, maxPackumentSize)
+	}
+	packument := &npmPackument{}
+	if err = json.Unmarshal(body, packument); err != nil {
+		return nil, fmt.Errorf("failed to parse the npm registry response for the package %s: %s", packageName, err.Error())
+	}
+	nrc.packuments[packageName] = packument
+	return packument, nil
+}
+
+// Return the stable versions of the package, from the lowest
+func (nrc *npmRegistryClient) getStableVersions(packageName string) ([]string, error) {
+	packument, err := nrc.getPackument(packageName)
+	if err != nil {
+		return nil, err
+	}
+	var versions []string
+	for packageVersion := range packument.Versions {
+		if !isPrereleaseVersion(coreutils.Npm, packageVersion) {
+			versions = append(versions, packageVersion)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		// Compare returns 1 if the argument is greater than the version
+		return version.NewVersion(versions[i]).Compare(versions[j]) > 0
+	})
+	return versions, nil
+}
+
+// Return the dependency range of the dependent package version, or false if it doesn't depend on the dependency
+func (nrc *npmRegistryClient) getDependencyRange(dependent, dependentVersion, dependency string) (string, bool, error) {
+	packument, err := nrc.getPackument(dependent)
+	if err != nil {
+		return "", false, err
+	}
+	dependencyRange, exists := packument.Versions[dependentVersion].Dependencies[dependency]
+	if !exists {
+		return "", false, nil
+	}
+	if strings.TrimSpace(dependencyRange) == "" {
+		// An empty range matches all the versions
+		dependencyRange = "*"
+	}
+	return dependencyRange, true, nil
+}
+
+// Resolve the range to its highest stable version, like npm does when it installs a fresh dependency. An empty string is returned if no version matches.
+func (nrc *npmRegistryClient) resolveRange(packageName, dependencyRange string) (string, error) {
+	versions, err := nrc.getStableVersions(packageName)
+	if err != nil {
+		return "", err
+	}
+	for i := len(versions) - 1; i >= 0; i-- {
+		if inRange, _ := utils.IsVersionInRange(versions[i], dependencyRange); inRange {
+			return versions[i], nil
+		}
+	}
+	return "", nil
+}
+
+// Find the lowest version of the direct dependency in the impact path which pulls a fixed version of the impacted dependency.
+// The impact path starts with the project, followed by the direct dependency, and ends with the impacted dependency.
+// Nil is returned if none of the newer versions of the direct dependency resolves the fix.
+func (nrc *npmRegistryClient) findFixChain(impactPath []formats.ComponentRow, impactedVersion, minimalFixVersion string) (*fixChain, error) {
+	directDependency := impactPath[1]
+	candidates, err := nrc.getStableVersions(directDependency.Name)
+	if err != nil {
+		return nil, err
+	}
+	for _, candidate := range candidates {
+		// Compare returns 1 if the candidate is greater than the current version
+		if version.NewVersion(directDependency.Version).Compare(candidate) <= 0 {
+			continue
+		}
+		resolvedVersion, err := nrc.resolveImpactPath(impactPath[1:], candidate, impactedVersion)
+		if err != nil {
+			return nil, err
+		}
+		if resolvedVersion != "" && version.NewVersion(resolvedVersion).Compare(minimalFixVersion) <= 0 {
+			return &fixChain{directDependency: directDependency, upgradeVersion: candidate, resolvedVersion: resolvedVersion}, nil
+		}
+	}
+	return nil, nil
+}
+
+// Resolve the versions along the chain of dependencies, starting from the given version of the first one, and return the resolved version of the last one.
+// An empty string is returned if the chain breaks, or if the range of the last dependency still allows the impacted version,
+// since npm keeps the installed version of the dependency when it satisfies the range.
+func (nrc *npmRegistryClient) resolveImpactPath(chain []formats.ComponentRow, firstVersion, impactedVersion string) (string, error) {
+	currentVersion := firstVersion
+	for i := 1; i < len(chain); i++ {
+		dependencyRange, exists, err := nrc.getDependencyRange(chain[i-1].Name, currentVersion, chain[i].Name)
+		if err != nil || !exists {
+			return "", err
+		}
+		inRange, err := utils.IsVersionInRange(impactedVersion, dependencyRange)
+		if err != nil {
+			// Ranges which aren't semver ranges, such as git URLs and dist tags, can't be resolved
+			return "", nil
+		}
+		if i == len(chain)-1 && inRange {
+			return "", nil
+		}
+		if currentVersion, err = nrc.resolveRange(chain[i].Name, dependencyRange); err != nil || currentVersion == "" {
+			return "", err
+		}
+	}
+	return currentVersion, nil
+}
+
+// Add the fix chains of the fixable transitive npm issues. Each direct dependency through which an issue is introduced gets its own chain.
+func (results *auditResults) addFixChains(vulnerabilitiesRows []formats.VulnerabilityOrViolationRow, registryClient *npmRegistryClient) {
+	if results.fixChains == nil {
+		results.fixChains = make(map[string][]fixChain)
+	}
+	for _, row := range vulnerabilitiesRows {
+		if getEcosystemPackageType(row.Technology) != coreutils.Npm.GetPackageType() {
+			continue
+		}
+		minimalFixVersion := getMinimalFixVersion(row.ImpactedDependencyVersion, row.FixedVersions)
+		if minimalFixVersion == "" {
+			continue
+		}
+		checkedDependencies := make(map[string]bool)
+		for _, impactPath := range row.ImpactPaths {
+			// Direct dependencies are fixed by upgrading them, with no chain
+			if len(impactPath) < 3 || checkedDependencies[impactPath[1].Name] {
+				continue
+			}
+			checkedDependencies[impactPath[1].Name] = true
+			chain, err := registryClient.findFixChain(impactPath, row.ImpactedDependencyVersion, minimalFixVersion)
+			if err != nil {
+				// The fix chains are optional, so the scan continues without them
+				log.Warn(fmt.Sprintf("couldn't find the fix chain of %s through %s:", row.ImpactedDependencyName, impactPath[1].Name), err.Error())
+				continue
+			}
+			if chain != nil {
+				results.fixChains[getUniqueID(row)] = append(results.fixChains[getUniqueID(row)], *chain)
+			}
+		}
+	}
+}
+
+// Create a note with the upgrades of the direct dependencies which fix the transitive issues shown in the comment
+func createFixChainsNotes(vulnerabilitiesRows []formats.VulnerabilityOrViolationRow, fixChains map[string][]fixChain) string {
+	var notes strings.Builder
+	for _, row := range vulnerabilitiesRows {
+		for _, chain := range fixChains[getUniqueID(row)] {
+			notes.WriteString(fmt.Sprintf("- Upgrade **%s** from %s to **%s** to get **%s %s**, which fixes %s\n", chain.directDependency.Name, chain.directDependency.Version,
+				chain.upgradeVersion, row.ImpactedDependencyName, chain.resolvedVersion, getIssueDisplayId(row)))
+		}
+	}
+	if notes.Len() == 0 {
+		return ""
+	}
+	return "\n\n" + fixChainsTitle + "\n\n" + notes.String()
+}
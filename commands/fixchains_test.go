@@ -0,0 +1,180 @@
+package commands
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+var testNpmPackuments = map[string]string{
+	// express 4.17.2 still allows the vulnerable qs version, and express 4.18.0 pulls the fixed one
+	"/express": `{"versions": {
+		"4.17.1": {"dependencies": {"body-parser": "1.19.0"}},
+		"4.17.2": {"dependencies": {"body-parser": "~1.19.0"}},
+		"4.18.0": {"dependencies": {"body-parser": "1.20.0"}},
+		"5.0.0-beta.1": {"dependencies": {"body-parser": "2.0.0-beta.1"}}
+	}}`,
+	"/body-parser": `{"versions": {
+		"1.19.0": {"dependencies": {"qs": "6.7.0"}},
+		"1.19.1": {"dependencies": {"qs": "6.9.6"}},
+		"1.20.0": {"dependencies": {"qs": "6.10.3"}}
+	}}`,
+	"/qs":           `{"versions": {"6.7.0": {}, "6.9.6": {}, "6.10.3": {}}}`,
+	"/@scope%2Fcli": `{"versions": {"1.0.0": {"dependencies": {"minimist": "^1.2.0"}}, "2.0.0": {"dependencies": {"minimist": "git+https://github.com/minimistjs/minimist.git"}}}}`,
+	"/minimist":     `{"versions": {"1.2.0": {}, "1.2.6": {}}}`,
+}
+
+func createTestNpmRegistry(t *testing.T, requestedPaths map[string]int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPaths[r.URL.EscapedPath()]++
+		packument, exists := testNpmPackuments[r.URL.EscapedPath()]
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := w.Write([]byte(packument))
+		assert.NoError(t, err)
+	}))
+}
+
+func TestAddFixChains(t *testing.T) {
+	requestedPaths := make(map[string]int)
+	server := createTestNpmRegistry(t, requestedPaths)
+	defer server.Close()
+	t.Setenv(npmConfigRegistryEnv, server.URL)
+
+	rows := []formats.VulnerabilityOrViolationRow{
+		{
+			ImpactedDependencyName:    "qs",
+			ImpactedDependencyVersion: "6.7.0",
+			FixedVersions:             []string{"[6.9.7]"},
+			Cves:                      []formats.CveRow{{Id: "CVE-2022-24999"}},
+			Technology:                coreutils.Npm,
+			ImpactPaths: [][]formats.ComponentRow{
+				{{Name: "app"}, {Name: "express", Version: "4.17.1"}, {Name: "body-parser", Version: "1.19.0"}, {Name: "qs", Version: "6.7.0"}},
+				// The chain of a direct dependency is found only once
+				{{Name: "app"}, {Name: "express", Version: "4.17.1"}, {Name: "qs", Version: "6.7.0"}},
+			},
+		},
+		{
+			// The newer version of the direct dependency has a range which isn't a semver range
+			ImpactedDependencyName:    "minimist",
+			ImpactedDependencyVersion: "1.2.0",
+			FixedVersions:             []string{"[1.2.6]"},
+			IssueId:                   "XRAY-1",
+			Technology:                coreutils.Yarn,
+			ImpactPaths:               [][]formats.ComponentRow{{{Name: "app"}, {Name: "@scope/cli", Version: "1.0.0"}, {Name: "minimist", Version: "1.2.0"}}},
+		},
+		{
+			// A direct dependency
+			ImpactedDependencyName:    "qs",
+			ImpactedDependencyVersion: "6.7.0",
+			FixedVersions:             []string{"[6.9.7]"},
+			IssueId:                   "XRAY-2",
+			Technology:                coreutils.Npm,
+			ImpactPaths:               [][]formats.ComponentRow{{{Name: "app"}, {Name: "qs", Version: "6.7.0"}}},
+		},
+		{
+			// Not an npm dependency
+			ImpactedDependencyName:    "golang.org/x/net",
+			ImpactedDependencyVersion: "0.1.0",
+			FixedVersions:             []string{"[0.7.0]"},
+			Technology:                coreutils.Go,
+			ImpactPaths:               [][]formats.ComponentRow{{{Name: "app"}, {Name: "github.com/gin-gonic/gin"}, {Name: "golang.org/x/net"}}},
+		},
+	}
+	results := &auditResults{}
+	results.addFixChains(rows, newNpmRegistryClient())
+	expectedChain := fixChain{directDependency: formats.ComponentRow{Name: "express", Version: "4.17.1"}, upgradeVersion: "4.18.0", resolvedVersion: "6.10.3"}
+	assert.Equal(t, map[string][]fixChain{getUniqueID(rows[0]): {expectedChain}}, results.fixChains)
+	// The packuments are cached
+	assert.Equal(t, map[string]int{"/express": 1, "/body-parser": 1, "/qs": 1, "/@scope%2Fcli": 1}, requestedPaths)
+
+	expected := "\n\n" + fixChainsTitle + "\n\n- Upgrade **express** from 4.17.1 to **4.18.0** to get **qs 6.10.3**, which fixes CVE-2022-24999\n"
+	assert.Equal(t, expected, createFixChainsNotes(rows, results.fixChains))
+	assert.Empty(t, createFixChainsNotes(rows, nil))
+}
+
+func TestAddFixChainsRegistryError(t *testing.T) {
+	requestedPaths := make(map[string]int)
+	server := createTestNpmRegistry(t, requestedPaths)
+	defer server.Close()
+	t.Setenv(npmConfigRegistryEnv, server.URL+"/")
+
+	// The chains are optional, so a missing package is skipped
+	rows := []formats.VulnerabilityOrViolationRow{{
+		ImpactedDependencyName:    "qs",
+		ImpactedDependencyVersion: "6.7.0",
+		FixedVersions:             []string{"[6.9.7]"},
+		Technology:                coreutils.Npm,
+		ImpactPaths:               [][]formats.ComponentRow{{{Name: "app"}, {Name: "missing", Version: "1.0.0"}, {Name: "qs", Version: "6.7.0"}}},
+	}}
+	results := &auditResults{}
+	results.addFixChains(rows, newNpmRegistryClient())
+	assert.Empty(t, results.fixChains)
+	assert.Equal(t, map[string]int{"/missing": 1}, requestedPaths)
+}
+
+func TestLoadNpmConfig(t *testing.T) {
+	homeDir, projectDir := t.TempDir(), t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv(npmConfigRegistryEnv, "")
+	t.Setenv(npmConfigRegistryLower, "")
+	t.Setenv("NPM_TOKEN", "project-token")
+	assert.NoError(t, os.WriteFile(filepath.Join(homeDir, npmrcFileName), []byte("registry=https://home.example.com/npm/\n//home.example.com/npm/:_authToken=home-token\n"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(projectDir, npmrcFileName), []byte(`# The project registry overrides the user registry
+registry = https://artifactory.example.com/api/npm/npm
+@jfrog:registry=https://jfrog.example.com/npm
+//artifactory.example.com/api/npm/:_authToken=${NPM_TOKEN}
+//artifactory.example.com/api/npm/npm/:_auth="dXNlcjpwYXNz"
+; //jfrog.example.com/:_authToken=commented
+`), 0600))
+
+	config := loadNpmConfig(projectDir)
+	assert.Equal(t, "https://artifactory.example.com/api/npm/npm/", config.getRegistryUrl("lodash"))
+	assert.Equal(t, "https://jfrog.example.com/npm/", config.getRegistryUrl("@jfrog/frogbot"))
+	assert.Equal(t, "https://artifactory.example.com/api/npm/npm/", config.getRegistryUrl("@types/node"))
+	// The credentials of the most specific path are used
+	assert.Equal(t, "Basic dXNlcjpwYXNz", config.getAuthorization("https://artifactory.example.com/api/npm/npm/"))
+	assert.Equal(t, "Bearer project-token", config.getAuthorization("https://artifactory.example.com/api/npm/other-npm/"))
+	assert.Equal(t, "Bearer home-token", config.getAuthorization("https://home.example.com/npm/"))
+	assert.Empty(t, config.getAuthorization("https://jfrog.example.com/npm/"))
+
+	// The environment variable overrides the .npmrc files
+	t.Setenv(npmConfigRegistryLower, "https://env.example.com")
+	assert.Equal(t, "https://env.example.com/", loadNpmConfig(projectDir).registry)
+
+	// Without any configuration, the public registry is used
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(npmConfigRegistryLower, "")
+	assert.Equal(t, defaultNpmRegistryUrl, loadNpmConfig(t.TempDir()).getRegistryUrl("@jfrog/frogbot"))
+}
+
+func TestGetPackumentRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, npmAbbreviatedPackument, r.Header.Get("Accept"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		packument := `{"versions": {"1.0.0": {}}}`
+		if r.URL.Path == "/large" {
+			packument = `{"versions": {}, "readme": "` + strings.Repeat("a", maxPackumentSize) + `"}`
+		}
+		_, err := w.Write([]byte(packument))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	registryClient := newNpmRegistryClient()
+	registryClient.config = &npmConfig{registry: server.URL + "/", authTokens: map[string]string{"//" + strings.TrimPrefix(server.URL, "http://") + "/": "token"}}
+	versions, err := registryClient.getStableVersions("small")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1.0.0"}, versions)
+	_, err = registryClient.getPackument("large")
+	assert.ErrorContains(t, err, "exceeds")
+}
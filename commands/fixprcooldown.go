@@ -0,0 +1,173 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/xanzy/go-gitlab"
+)
+
+const (
+	// The label added to the fix pull requests, by which the declined fix pull requests are found
+	fixPRLabel = "frogbot-fix"
+	// The page size of the closed pull requests, in which the declined fix pull requests are searched
+	declinedFixPRsPageSize = 100
+)
+
+// Matches the fix branch names, such as 'frogbot-gopkg.in/yaml.v3-cedc1e5462e504fc992318d24e343e48', and captures the fixed dependency
+var fixBranchNameRegex = regexp.MustCompile(`^frogbot-(.+)-[0-9a-f]{32}$`)
+
+// Return the fixed dependency of the fix branch, or an empty string if the branch isn't a fix branch.
+// The fix branch names include the fix version, so a declined fix is matched to the fixes of the same dependency to other versions as well.
+// The grouped fix branches are matched by their full names only, since the name of the group is captured rather than a dependency.
+func getFixBranchDependency(fixBranchName string) string {
+	if match := fixBranchNameRegex.FindStringSubmatch(fixBranchName); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// labelFixPR labels the fix pull request from the fix branch, so that it is detected if it is declined.
+// The pull requests are labeled even if fixPRCooldownDays isn't set, so that the pull requests declined before it is set are detected too.
+// The pull request is already created, so if it can't be labeled, a warning is logged and the pull request is kept.
+func labelFixPR(repoConfig *utils.FrogbotRepoConfig, fixBranchName string) {
+	if repoConfig.GitProvider != vcsutils.GitHub && repoConfig.GitProvider != vcsutils.GitLab {
+		return
+	}
+	if err := labelPullRequest(&repoConfig.Git, fixBranchName, fixPRLabel); err != nil {
+		log.Warn("couldn't label the pull request from branch", fixBranchName+":", err.Error())
+	}
+}
+
+// The froggit-go VCS client doesn't support labeling pull requests when they are created, nor listing the closed pull requests,
+// and therefore the GitHub and GitLab APIs are used directly
+func labelPullRequest(git *utils.Git, sourceBranch, label string) error {
+	switch git.GitProvider {
+	case vcsutils.GitHub:
+		client, err := newGitHubClient(git)
+		if err != nil {
+			return err
+		}
+		pullRequestNumber, err := findGitHubPullRequest(client, git, sourceBranch)
+		if err != nil {
+			return err
+		}
+		_, _, err = client.Issues.AddLabelsToIssue(context.Background(), git.RepoOwner, git.RepoName, pullRequestNumber, []string{label})
+		return err
+	case vcsutils.GitLab:
+		client, err := newGitLabClient(git)
+		if err != nil {
+			return err
+		}
+		projectId := fmt.Sprintf("%s/%s", git.RepoOwner, git.RepoName)
+		mergeRequestIid, err := findGitLabMergeRequest(client, projectId, sourceBranch)
+		if err != nil {
+			return err
+		}
+		_, _, err = client.MergeRequests.UpdateMergeRequest(projectId, mergeRequestIid, &gitlab.UpdateMergeRequestOptions{AddLabels: gitlab.Labels{label}})
+		return err
+	default:
+		return fmt.Errorf("labeling pull requests isn't supported on %s", git.GitProvider.String())
+	}
+}
+
+// Return the source branches and the dependencies of the labeled fix pull requests to the given branch, which were closed without being merged within the last fixPRCooldownDays.
+// If the pull requests can't be listed, a warning is logged and nil is returned, so that the fixes aren't skipped.
+func getDeclinedFixes(repoConfig *utils.FrogbotRepoConfig, branch string, now time.Time) map[string]bool {
+	if repoConfig.FixPRCooldownDays <= 0 {
+		return nil
+	}
+	closedAfter := now.AddDate(0, 0, -repoConfig.FixPRCooldownDays)
+	sourceBranches, err := listDeclinedPullRequests(&repoConfig.Git, branch, closedAfter)
+	if err != nil {
+		log.Warn("couldn't list the declined fix pull requests, so the fixPRCooldownDays param is ignored:", err.Error())
+		return nil
+	}
+	declinedFixes := make(map[string]bool)
+	for _, sourceBranch := range sourceBranches {
+		declinedFixes[sourceBranch] = true
+		if dependency := getFixBranchDependency(sourceBranch); dependency != "" {
+			declinedFixes[dependency] = true
+		}
+	}
+	return declinedFixes
+}
+
+// Return the source branches of the pull requests to the target branch, labeled with the fix pull requests label, which were closed without being merged after the given time
+func listDeclinedPullRequests(git *utils.Git, targetBranch string, closedAfter time.Time) (sourceBranches []string, err error) {
+	switch git.GitProvider {
+	case vcsutils.GitHub:
+		return listDeclinedGitHubPullRequests(git, targetBranch, closedAfter)
+	case vcsutils.GitLab:
+		return listDeclinedGitLabMergeRequests(git, targetBranch, closedAfter)
+	default:
+		return nil, fmt.Errorf("listing the closed pull requests isn't supported on %s", git.GitProvider.String())
+	}
+}
+
+func listDeclinedGitHubPullRequests(git *utils.Git, targetBranch string, closedAfter time.Time) (sourceBranches []string, err error) {
+	client, err := newGitHubClient(git)
+	if err != nil {
+		return nil, err
+	}
+	options := &github.PullRequestListOptions{State: "closed", Base: targetBranch, Sort: "updated", Direction: "desc", ListOptions: github.ListOptions{PerPage: declinedFixPRsPageSize}}
+	for {
+		pullRequests, response, err := client.PullRequests.List(context.Background(), git.RepoOwner, git.RepoName, options)
+		if err != nil {
+			return nil, err
+		}
+		for _, pullRequest := range pullRequests {
+			if pullRequest.MergedAt != nil || pullRequest.ClosedAt == nil || pullRequest.ClosedAt.Before(closedAfter) || !hasGitHubLabel(pullRequest.Labels, fixPRLabel) {
+				continue
+			}
+			sourceBranches = append(sourceBranches, pullRequest.GetHead().GetRef())
+		}
+		// The pull requests are sorted by their last update, which is after they were closed, so the next pages were closed before the window
+		if response.NextPage == 0 || len(pullRequests) == 0 || pullRequests[len(pullRequests)-1].GetUpdatedAt().Before(closedAfter) {
+			return sourceBranches, nil
+		}
+		options.Page = response.NextPage
+	}
+}
+
+func hasGitHubLabel(labels []*github.Label, name string) bool {
+	for _, label := range labels {
+		if strings.EqualFold(label.GetName(), name) {
+			return true
+		}
+	}
+	return false
+}
+
+func listDeclinedGitLabMergeRequests(git *utils.Git, targetBranch string, closedAfter time.Time) (sourceBranches []string, err error) {
+	client, err := newGitLabClient(git)
+	if err != nil {
+		return nil, err
+	}
+	// The closed state of GitLab excludes the merged merge requests
+	options := &gitlab.ListProjectMergeRequestsOptions{State: gitlab.String("closed"), TargetBranch: gitlab.String(targetBranch), Labels: gitlab.Labels{fixPRLabel}, UpdatedAfter: &closedAfter,
+		ListOptions: gitlab.ListOptions{PerPage: declinedFixPRsPageSize}}
+	for {
+		mergeRequests, response, err := client.MergeRequests.ListProjectMergeRequests(fmt.Sprintf("%s/%s", git.RepoOwner, git.RepoName), options)
+		if err != nil {
+			return nil, err
+		}
+		for _, mergeRequest := range mergeRequests {
+			if mergeRequest.ClosedAt == nil || mergeRequest.ClosedAt.Before(closedAfter) {
+				continue
+			}
+			sourceBranches = append(sourceBranches, mergeRequest.SourceBranch)
+		}
+		if response.NextPage == 0 {
+			return sourceBranches, nil
+		}
+		options.Page = response.NextPage
+	}
+}
@@ -0,0 +1,142 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetFixBranchDependency(t *testing.T) {
+	fixBranchName, err := generateFixBranchName("master", "gopkg.in/yaml.v3", "3.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "gopkg.in/yaml.v3", getFixBranchDependency(fixBranchName))
+	assert.Equal(t, "npm", getFixBranchDependency("frogbot-npm-cedc1e5462e504fc992318d24e343e48"))
+	assert.Empty(t, getFixBranchDependency("feature/login"))
+	assert.Empty(t, getFixBranchDependency("frogbot-lodash-1234"))
+}
+
+func TestGetDeclinedFixesGitHub(t *testing.T) {
+	now := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	var requestedPages []string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/jfrog/frogbot/pulls", r.URL.Path)
+		assert.Equal(t, "closed", r.URL.Query().Get("state"))
+		assert.Equal(t, "master", r.URL.Query().Get("base"))
+		page := r.URL.Query().Get("page")
+		requestedPages = append(requestedPages, page)
+		var response string
+		switch page {
+		case "":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/jfrog/frogbot/pulls?page=2>; rel="next"`, server.URL))
+			response = `[{"closed_at": "2023-02-25T00:00:00Z", "updated_at": "2023-02-25T00:00:00Z", "labels": [{"name": "frogbot-fix"}], "head": {"ref": "frogbot-npm-cedc1e5462e504fc992318d24e343e48"}}]`
+		case "2":
+			// The last pull request of the page was updated before the window, so the next page isn't requested
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/jfrog/frogbot/pulls?page=3>; rel="next"`, server.URL))
+			response = `[
+			{"closed_at": "2023-02-20T00:00:00Z", "updated_at": "2023-02-20T00:00:00Z", "labels": [{"name": "frogbot-fix"}], "head": {"ref": "frogbot-lodash-cedc1e5462e504fc992318d24e343e48"}},
+			{"closed_at": "2023-02-20T00:00:00Z", "updated_at": "2023-02-20T00:00:00Z", "merged_at": "2023-02-20T00:00:00Z", "labels": [{"name": "frogbot-fix"}], "head": {"ref": "frogbot-minimist-cedc1e5462e504fc992318d24e343e48"}},
+			{"closed_at": "2023-02-20T00:00:00Z", "updated_at": "2023-02-20T00:00:00Z", "head": {"ref": "frogbot-express-cedc1e5462e504fc992318d24e343e48"}},
+			{"closed_at": "2023-01-01T00:00:00Z", "updated_at": "2023-01-01T00:00:00Z", "labels": [{"name": "frogbot-fix"}], "head": {"ref": "frogbot-axios-cedc1e5462e504fc992318d24e343e48"}}
+		]`
+		default:
+			t.Errorf("unexpected request of page %s", page)
+		}
+		_, err := fmt.Fprint(w, response)
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	// Only the labeled pull requests which were closed without being merged within the cooldown window are declined
+	repoConfig := newTestRepoConfig(vcsutils.GitHub, server.URL)
+	repoConfig.FixPRCooldownDays = 30
+	declinedFixes := getDeclinedFixes(repoConfig, "master", now)
+	assert.Equal(t, map[string]bool{"frogbot-npm-cedc1e5462e504fc992318d24e343e48": true, "npm": true, "frogbot-lodash-cedc1e5462e504fc992318d24e343e48": true, "lodash": true}, declinedFixes)
+	assert.Equal(t, []string{"", "2"}, requestedPages)
+
+	repoConfig.FixPRCooldownDays = 0
+	assert.Nil(t, getDeclinedFixes(repoConfig, "master", now))
+}
+
+func TestGetDeclinedFixesGitLab(t *testing.T) {
+	now := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		switch r.URL.EscapedPath() {
+		// The GitLab client sends a request to configure its rate limiter
+		case "/api/v4/":
+		case "/api/v4/projects/jfrog%2Ffrogbot/merge_requests":
+			assert.Equal(t, "closed", r.URL.Query().Get("state"))
+			assert.Equal(t, fixPRLabel, r.URL.Query().Get("labels"))
+			assert.Equal(t, "master", r.URL.Query().Get("target_branch"))
+			_, err = fmt.Fprint(w, `[{"closed_at": "2023-02-20T00:00:00Z", "source_branch": "frogbot-lodash-cedc1e5462e504fc992318d24e343e48"}]`)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.EscapedPath())
+		}
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	repoConfig := newTestRepoConfig(vcsutils.GitLab, server.URL)
+	repoConfig.FixPRCooldownDays = 30
+	declinedFixes := getDeclinedFixes(repoConfig, "master", now)
+	assert.Equal(t, map[string]bool{"frogbot-lodash-cedc1e5462e504fc992318d24e343e48": true, "lodash": true}, declinedFixes)
+}
+
+func TestGetDeclinedFixesUnsupportedProvider(t *testing.T) {
+	// The fixes aren't skipped if the closed pull requests can't be listed
+	repoConfig := newTestRepoConfig(vcsutils.BitbucketServer, "")
+	repoConfig.FixPRCooldownDays = 30
+	assert.Nil(t, getDeclinedFixes(repoConfig, "master", time.Now()))
+}
+
+func TestLabelFixPRGitHub(t *testing.T) {
+	var addedLabels []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/jfrog/frogbot/pulls":
+			_, err := fmt.Fprint(w, `[{"number": 12}]`)
+			assert.NoError(t, err)
+		case "/repos/jfrog/frogbot/issues/12/labels":
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.NoError(t, json.Unmarshal(body, &addedLabels))
+			_, err = fmt.Fprint(w, `[{"name": "frogbot-fix"}]`)
+			assert.NoError(t, err)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	// The fix pull requests are labeled even without a cooldown, so that they are detected once it is set
+	labelFixPR(newTestRepoConfig(vcsutils.GitHub, server.URL), "frogbot-lodash-1234")
+	assert.Equal(t, []string{fixPRLabel}, addedLabels)
+}
+
+func TestShouldSkipDeclinedFixBranch(t *testing.T) {
+	cfp := &CreateFixPullRequestsCmd{declinedFixes: map[string]bool{"lodash": true}}
+	fixBranchName, err := generateFixBranchName("master", "lodash", "4.17.21")
+	assert.NoError(t, err)
+	// The fix is skipped before the remote branches are checked
+	skip, err := cfp.shouldSkipFixBranch(fixBranchName, false, nil)
+	assert.NoError(t, err)
+	assert.True(t, skip)
+
+	// A grouped fix branch is skipped if the same group was declined
+	group := fixGroup{name: "npm", fixes: createGroupingTestFixes()[:1]}
+	groupBranchName, err := generateGroupFixBranchName("master", group)
+	assert.NoError(t, err)
+	cfp.declinedFixes = map[string]bool{groupBranchName: true}
+	skip, err = cfp.shouldSkipFixBranch(groupBranchName, true, nil)
+	assert.NoError(t, err)
+	assert.True(t, skip)
+
+}
@@ -0,0 +1,182 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+)
+
+// The name of the single group of fixes with the all grouping
+const allFixesGroupName = "all"
+
+// A fix of a vulnerable dependency in one of the working directories of the repository
+type dependencyFix struct {
+	project *utils.Project
+	// The working directory of the fix, relative to the root of the repository
+	workingDir      string
+	impactedPackage string
+	fixVersionInfo  FixVersionInfo
+}
+
+// A group of fixes, which are applied in a single fix branch and pull request
+type fixGroup struct {
+	// The package manager of the fixes with the per-ecosystem grouping, or allFixesGroupName with the all grouping
+	name  string
+	fixes []dependencyFix
+}
+
+// Return true if the fixes are grouped into fix pull requests of several dependencies, rather than a pull request for each dependency
+func isGroupedFixPRs(fixPRGrouping string) bool {
+	return fixPRGrouping == utils.PerEcosystemFixPRGrouping || fixPRGrouping == utils.AllFixPRGrouping
+}
+
+// Return the fixes of the vulnerable dependencies of a working directory, sorted by the impacted packages
+func (cfp *CreateFixPullRequestsCmd) getDependencyFixes(project *utils.Project, repoConfig *utils.FrogbotRepoConfig, scanResults []services.ScanResponse,
+	workingDir string, isMultipleRoots bool) ([]dependencyFix, error) {
+	fixVersionsMap, err := cfp.createFixVersionsMap(project, scanResults, isMultipleRoots, repoConfig.UpgradeStrategy, repoConfig.AllowPrerelease)
+	if err != nil {
+		return nil, err
+	}
+	var fixes []dependencyFix
+	for impactedPackage, fixVersionInfo := range fixVersionsMap {
+		fixes = append(fixes, dependencyFix{project: project, workingDir: workingDir, impactedPackage: impactedPackage, fixVersionInfo: *fixVersionInfo})
+	}
+	sort.Slice(fixes, func(i, j int) bool {
+		return fixes[i].impactedPackage < fixes[j].impactedPackage
+	})
+	return fixes, nil
+}
+
+// Group the fixes by their package managers with the per-ecosystem grouping, or into a single group with the all grouping.
+// The groups are sorted by their names, and the fixes of each group keep their order.
+func groupFixes(fixPRGrouping string, fixes []dependencyFix) []fixGroup {
+	groupsIndexes := make(map[string]int)
+	var groups []fixGroup
+	for _, fix := range fixes {
+		name := allFixesGroupName
+		if fixPRGrouping == utils.PerEcosystemFixPRGrouping {
+			name = fix.fixVersionInfo.packageType.ToString()
+		}
+		index, exists := groupsIndexes[name]
+		if !exists {
+			index = len(groups)
+			groupsIndexes[name] = index
+			groups = append(groups, fixGroup{name: name})
+		}
+		groups[index].fixes = append(groups[index].fixes, fix)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].name < groups[j].name
+	})
+	return groups
+}
+
+// Return the name of the fix branch of the group, which is unique to the fixes of the group.
+// fixBranchName example: 'frogbot-npm-cedc1e5462e504fc992318d24e343e48'
+func generateGroupFixBranchName(baseBranch string, group fixGroup) (string, error) {
+	var fixesIds []string
+	for _, fix := range group.fixes {
+		fixesIds = append(fixesIds, fix.workingDir+":"+fix.impactedPackage+":"+fix.fixVersionInfo.fixVersion)
+	}
+	sort.Strings(fixesIds)
+	uniqueString, err := utils.Md5Hash(append([]string{"frogbot", baseBranch, group.name}, fixesIds...)...)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s-%s-%s", "frogbot", strings.ToLower(group.name), uniqueString), nil
+}
+
+func generateGroupCommitString(group fixGroup) string {
+	if group.name == allFixesGroupName {
+		return "[🐸 Frogbot] Upgrade the vulnerable dependencies"
+	}
+	return fmt.Sprintf("[🐸 Frogbot] Upgrade the vulnerable %s dependencies", coreutils.Technology(group.name).ToFormal())
+}
+
+// Clone the scanned branch once, and create a fix pull request for each group of fixes
+func (cfp *CreateFixPullRequestsCmd) fixGroupsAndCreatePRs(repoConfig *utils.FrogbotRepoConfig, branch string, client vcsclient.VcsClient, fixes []dependencyFix) (err error) {
+	if len(fixes) == 0 {
+		log.Info("Didn't find vulnerable dependencies with existing fix versions for", repoConfig.RepoName)
+		return nil
+	}
+	groups := groupFixes(repoConfig.FixPRGrouping, fixes)
+	log.Info("Found", len(fixes), "vulnerable dependencies with fix versions, grouped into", len(groups), "fix pull requests")
+
+	gitManager, cleanup, err := cfp.cloneToTempDir(repoConfig, branch)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		e := cleanup()
+		if err == nil {
+			err = e
+		}
+	}()
+
+	for _, group := range groups {
+		log.Info("-----------------------------------------------------------------")
+		log.Info("Start fixing the", len(group.fixes), "dependencies of the", group.name, "group")
+		if err = cfp.fixGroupAndCreatePR(group, branch, repoConfig, client, gitManager); err != nil {
+			log.Error("failed while trying to fix and create PR for the", group.name, "group with error:", err.Error())
+		}
+		// After finishing to work on the current group we go back to the base branch to start the next group
+		log.Info("Running git checkout to base branch:", branch)
+		if err = gitManager.Checkout(branch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Apply all the fixes of the group in a single fix branch, and create a pull request which lists them.
+// A dependency which fails to be fixed aborts the group, since its partial changes, such as an updated manifest without its lock file, can't be told apart from the changes of the other dependencies.
+// The fix branch isn't committed, and its changes are discarded by the checkout of the scanned branch.
+func (cfp *CreateFixPullRequestsCmd) fixGroupAndCreatePR(group fixGroup, branch string, repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient, gitManager *utils.GitManager) error {
+	fixBranchName, err := generateGroupFixBranchName(branch, group)
+	if err != nil {
+		return err
+	}
+	if skip, err := cfp.shouldSkipFixBranch(fixBranchName, true, gitManager); err != nil || skip {
+		return err
+	}
+
+	log.Info("Creating branch:", fixBranchName)
+	if err = gitManager.CreateBranchAndCheckout(fixBranchName); err != nil {
+		return err
+	}
+
+	var fixDescriptions []string
+	// The highest severity fixed by the group
+	groupSeverity := FixVersionInfo{}
+	for _, fix := range group.fixes {
+		log.Info("Start fixing", fix.impactedPackage, "with", fix.fixVersionInfo.fixVersion)
+		fixDescription, err := cfp.fixPackage(fix.impactedPackage, fix.fixVersionInfo, fix.project, fix.workingDir)
+		if err != nil {
+			return fmt.Errorf("failed while trying to fix %s with version %s, so the '%s' group isn't created: %s", fix.impactedPackage, fix.fixVersionInfo.fixVersion, group.name, err.Error())
+		}
+		if fix.workingDir != "" {
+			fixDescription += fmt.Sprintf(" in `%s`", fix.workingDir)
+		}
+		fixDescriptions = append(fixDescriptions, fixDescription)
+		groupSeverity.UpdateSeverity(fix.fixVersionInfo.severity, fix.fixVersionInfo.severityNumValue)
+	}
+	log.Info("Checking if there are changes to commit")
+	isClean, err := gitManager.IsClean()
+	if err != nil {
+		return err
+	}
+	if isClean {
+		return fmt.Errorf("there were no changes to commit after fixing the dependencies of the '%s' group", group.name)
+	}
+
+	commitString := generateGroupCommitString(group)
+	prBody := commitString + "\n\n- " + strings.Join(fixDescriptions, "\n- ") + "\n\n" + utils.WhatIsFrogbotMd
+	prTitle := generatePullRequestTitle(commitString, groupSeverity.severity, repoConfig)
+	return cfp.pushFixBranchAndCreatePR(fixBranchName, branch, commitString, prTitle, prBody, groupSeverity.severity, repoConfig, client, gitManager)
+}
@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func createGroupingTestFixes() []dependencyFix {
+	return []dependencyFix{
+		{workingDir: "frontend", impactedPackage: "lodash", fixVersionInfo: FixVersionInfo{fixVersion: "4.17.21", packageType: coreutils.Npm}},
+		{workingDir: "backend", impactedPackage: "golang.org/x/net", fixVersionInfo: FixVersionInfo{fixVersion: "0.7.0", packageType: coreutils.Go}},
+		{workingDir: "frontend", impactedPackage: "minimist", fixVersionInfo: FixVersionInfo{fixVersion: "1.2.6", packageType: coreutils.Npm}},
+	}
+}
+
+func TestIsGroupedFixPRs(t *testing.T) {
+	assert.False(t, isGroupedFixPRs(""))
+	assert.False(t, isGroupedFixPRs(utils.PerDependencyFixPRGrouping))
+	assert.True(t, isGroupedFixPRs(utils.PerEcosystemFixPRGrouping))
+	assert.True(t, isGroupedFixPRs(utils.AllFixPRGrouping))
+}
+
+func TestGroupFixes(t *testing.T) {
+	fixes := createGroupingTestFixes()
+	groups := groupFixes(utils.PerEcosystemFixPRGrouping, fixes)
+	assert.Equal(t, []fixGroup{
+		{name: "go", fixes: []dependencyFix{fixes[1]}},
+		{name: "npm", fixes: []dependencyFix{fixes[0], fixes[2]}},
+	}, groups)
+
+	groups = groupFixes(utils.AllFixPRGrouping, fixes)
+	assert.Equal(t, []fixGroup{{name: allFixesGroupName, fixes: fixes}}, groups)
+}
+
+func TestGenerateGroupFixBranchName(t *testing.T) {
+	fixes := createGroupingTestFixes()
+	branchName, err := generateGroupFixBranchName("master", fixGroup{name: "npm", fixes: []dependencyFix{fixes[0], fixes[2]}})
+	assert.NoError(t, err)
+	assert.Regexp(t, "^frogbot-npm-[0-9a-f]{32}$", branchName)
+
+	// The branch name doesn't depend on the order of the fixes
+	reorderedBranchName, err := generateGroupFixBranchName("master", fixGroup{name: "npm", fixes: []dependencyFix{fixes[2], fixes[0]}})
+	assert.NoError(t, err)
+	assert.Equal(t, branchName, reorderedBranchName)
+
+	// A new branch is created when the fixes change
+	changedFixes := []dependencyFix{fixes[0]}
+	changedBranchName, err := generateGroupFixBranchName("master", fixGroup{name: "npm", fixes: changedFixes})
+	assert.NoError(t, err)
+	assert.NotEqual(t, branchName, changedBranchName)
+}
+
+func TestGenerateGroupCommitString(t *testing.T) {
+	assert.Equal(t, "[🐸 Frogbot] Upgrade the vulnerable dependencies", generateGroupCommitString(fixGroup{name: allFixesGroupName}))
+	assert.Equal(t, "[🐸 Frogbot] Upgrade the vulnerable Go dependencies", generateGroupCommitString(fixGroup{name: "go"}))
+}
+
+func TestFixGroupSkipsOpenPullRequest(t *testing.T) {
+	group := fixGroup{name: "npm", fixes: createGroupingTestFixes()[:1]}
+	fixBranchName, err := generateGroupFixBranchName("master", group)
+	assert.NoError(t, err)
+	cfp := CreateFixPullRequestsCmd{openPullRequestsBranches: map[string]bool{fixBranchName: true}}
+	// The group is skipped before any git operation
+	assert.NoError(t, cfp.fixGroupAndCreatePR(group, "master", &utils.FrogbotRepoConfig{}, nil, nil))
+}
@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/xanzy/go-gitlab"
+)
+
+// requestFixPRReviewers requests the reviewers of the fix pull request from the fix branch, according to its severity.
+// The pull request is already created, so if the reviewers can't be requested, a warning is logged and the pull request is kept.
+func requestFixPRReviewers(repoConfig *utils.FrogbotRepoConfig, fixBranchName, severity string) {
+	reviewers := repoConfig.GetFixPRReviewers(severity)
+	if len(reviewers) == 0 {
+		return
+	}
+	log.Info("Requesting the reviewers", strings.Join(reviewers, ", "), "on the pull request from branch", fixBranchName)
+	if err := requestPullRequestReviewers(&repoConfig.Git, fixBranchName, reviewers); err != nil {
+		log.Warn("couldn't request the reviewers of the pull request from branch", fixBranchName+":", err.Error())
+	}
+}
+
+// The froggit-go VCS client doesn't support reviewers, and therefore the GitHub and GitLab APIs are used directly
+func requestPullRequestReviewers(git *utils.Git, sourceBranch string, reviewers []string) error {
+	switch git.GitProvider {
+	case vcsutils.GitHub:
+		return requestGitHubReviewers(git, sourceBranch, reviewers)
+	case vcsutils.GitLab:
+		return requestGitLabReviewers(git, sourceBranch, reviewers)
+	default:
+		return fmt.Errorf("requesting reviewers isn't supported on %s", git.GitProvider.String())
+	}
+}
+
+func requestGitHubReviewers(git *utils.Git, sourceBranch string, reviewers []string) error {
+	client, err := newGitHubClient(git)
+	if err != nil {
+		return err
+	}
+	pullRequestNumber, err := findGitHubPullRequest(client, git, sourceBranch)
+	if err != nil {
+		return err
+	}
+	// Teams are set as org/team, and are requested by their slug
+	request := github.ReviewersRequest{}
+	for _, reviewer := range reviewers {
+		reviewer = strings.TrimPrefix(reviewer, "@")
+		if _, team, isTeam := strings.Cut(reviewer, "/"); isTeam {
+			request.TeamReviewers = append(request.TeamReviewers, team)
+		} else {
+			request.Reviewers = append(request.Reviewers, reviewer)
+		}
+	}
+	_, _, err = client.PullRequests.RequestReviewers(context.Background(), git.RepoOwner, git.RepoName, pullRequestNumber, request)
+	return err
+}
+
+// Return the number of the open pull request from the source branch
+func findGitHubPullRequest(client *github.Client, git *utils.Git, sourceBranch string) (int, error) {
+	pullRequests, _, err := client.PullRequests.List(context.Background(), git.RepoOwner, git.RepoName,
+		&github.PullRequestListOptions{State: "open", Head: git.RepoOwner + ":" + sourceBranch})
+	if err != nil {
+		return 0, err
+	}
+	if len(pullRequests) == 0 {
+		return 0, fmt.Errorf("no open pull request from branch %s was found", sourceBranch)
+	}
+	return pullRequests[0].GetNumber(), nil
+}
+
+func requestGitLabReviewers(git *utils.Git, sourceBranch string, reviewers []string) error {
+	client, err := newGitLabClient(git)
+	if err != nil {
+		return err
+	}
+	projectId := fmt.Sprintf("%s/%s", git.RepoOwner, git.RepoName)
+	mergeRequestIid, err := findGitLabMergeRequest(client, projectId, sourceBranch)
+	if err != nil {
+		return err
+	}
+	// GitLab merge requests are reviewed by users, which are set by their IDs
+	var reviewerIds []int
+	for _, reviewer := range reviewers {
+		if strings.Contains(reviewer, "/") {
+			log.Warn("groups can't be requested as reviewers on GitLab, so", reviewer, "won't be requested")
+			continue
+		}
+		users, _, err := client.Users.ListUsers(&gitlab.ListUsersOptions{Username: gitlab.String(strings.TrimPrefix(reviewer, "@"))})
+		if err != nil {
+			return err
+		}
+		if len(users) == 0 {
+			log.Warn("the GitLab user", reviewer, "wasn't found, and won't be requested as a reviewer")
+			continue
+		}
+		reviewerIds = append(reviewerIds, users[0].ID)
+	}
+	if len(reviewerIds) == 0 {
+		return nil
+	}
+	_, _, err = client.MergeRequests.UpdateMergeRequest(projectId, mergeRequestIid, &gitlab.UpdateMergeRequestOptions{ReviewerIDs: reviewerIds})
+	return err
+}
+
+// Return the IID of the open merge request from the source branch
+func findGitLabMergeRequest(client *gitlab.Client, projectId, sourceBranch string) (int, error) {
+	mergeRequests, _, err := client.MergeRequests.ListProjectMergeRequests(projectId,
+		&gitlab.ListProjectMergeRequestsOptions{State: gitlab.String("opened"), SourceBranch: gitlab.String(sourceBranch)})
+	if err != nil {
+		return 0, err
+	}
+	if len(mergeRequests) == 0 {
+		return 0, fmt.Errorf("no open merge request from branch %s was found", sourceBranch)
+	}
+	return mergeRequests[0].IID, nil
+}
@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestFixPRReviewersGitHub(t *testing.T) {
+	var requestedReviewers map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/jfrog/frogbot/pulls":
+			assert.Equal(t, "jfrog:frogbot-lodash-1234", r.URL.Query().Get("head"))
+			_, err := fmt.Fprint(w, `[{"number": 12}]`)
+			assert.NoError(t, err)
+		case "/repos/jfrog/frogbot/pulls/12/requested_reviewers":
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			requestedReviewers = nil
+			assert.NoError(t, json.Unmarshal(body, &requestedReviewers))
+			_, err = fmt.Fprint(w, `{"number": 12}`)
+			assert.NoError(t, err)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	repoConfig := newTestRepoConfig(vcsutils.GitHub, server.URL)
+	repoConfig.ReviewersBySeverity = map[string][]string{"Critical": {"my-org/security", "@alice"}}
+	repoConfig.FixPRReviewers = []string{"bob"}
+	requestFixPRReviewers(repoConfig, "frogbot-lodash-1234", "Critical")
+	assert.Equal(t, map[string][]string{"reviewers": {"alice"}, "team_reviewers": {"security"}}, requestedReviewers)
+
+	// The default reviewers are requested for the other severities
+	requestFixPRReviewers(repoConfig, "frogbot-lodash-1234", "Medium")
+	assert.Equal(t, map[string][]string{"reviewers": {"bob"}}, requestedReviewers)
+}
+
+func TestRequestFixPRReviewersGitLab(t *testing.T) {
+	var updatedMergeRequest map[string][]int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		switch r.URL.EscapedPath() {
+		// The GitLab client sends a request to configure its rate limiter
+		case "/api/v4/":
+		case "/api/v4/projects/jfrog%2Ffrogbot/merge_requests":
+			assert.Equal(t, "frogbot-lodash-1234", r.URL.Query().Get("source_branch"))
+			_, err = fmt.Fprint(w, `[{"iid": 7}]`)
+		case "/api/v4/users":
+			if r.URL.Query().Get("username") == "alice" {
+				_, err = fmt.Fprint(w, `[{"id": 42}]`)
+			} else {
+				_, err = fmt.Fprint(w, `[]`)
+			}
+		case "/api/v4/projects/jfrog%2Ffrogbot/merge_requests/7":
+			assert.Equal(t, http.MethodPut, r.Method)
+			body, e := io.ReadAll(r.Body)
+			assert.NoError(t, e)
+			assert.NoError(t, json.Unmarshal(body, &updatedMergeRequest))
+			_, err = fmt.Fprint(w, `{"iid": 7}`)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.EscapedPath())
+		}
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	// Users which aren't found, and groups, which can't review merge requests, are skipped
+	repoConfig := newTestRepoConfig(vcsutils.GitLab, server.URL)
+	repoConfig.ReviewersBySeverity = map[string][]string{"Critical": {"my-org/security", "@alice"}}
+	requestFixPRReviewers(repoConfig, "frogbot-lodash-1234", "Critical")
+	assert.Equal(t, map[string][]int{"reviewer_ids": {42}}, updatedMergeRequest)
+}
+
+func TestRequestPullRequestReviewersUnsupportedProvider(t *testing.T) {
+	repoConfig := newTestRepoConfig(vcsutils.BitbucketServer, "")
+	assert.EqualError(t, requestPullRequestReviewers(&repoConfig.Git, "frogbot-lodash-1234", []string{"bob"}), "requesting reviewers isn't supported on Bitbucket Server")
+}
@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"golang.org/x/oauth2"
+)
+
+// The head (source) branch of a pull request, which may belong to a fork of the base repository
+type pullRequestHead struct {
+	owner  string
+	repo   string
+	branch string
+	// The head commit of the pull request
+	sha string
+}
+
+func (head *pullRequestHead) isFork(git *utils.Git) bool {
+	return !strings.EqualFold(head.owner, git.RepoOwner) || !strings.EqualFold(head.repo, git.RepoName)
+}
+
+// The froggit-go VCS client doesn't expose the owner of the pull request head repository, and therefore the GitHub API is used directly.
+func getGitHubPullRequestHead(git *utils.Git, pullRequestID int) (*pullRequestHead, error) {
+	client, err := newGitHubClient(git)
+	if err != nil {
+		return nil, err
+	}
+	pullRequest, _, err := client.PullRequests.Get(context.Background(), git.RepoOwner, git.RepoName, pullRequestID)
+	if err != nil {
+		return nil, err
+	}
+	head := pullRequest.GetHead()
+	return &pullRequestHead{
+		owner:  head.GetRepo().GetOwner().GetLogin(),
+		repo:   head.GetRepo().GetName(),
+		branch: head.GetRef(),
+		sha:    head.GetSHA(),
+	}, nil
+}
+
+func newGitHubClient(git *utils.Git) (*github.Client, error) {
+	httpClient := &http.Client{}
+	if git.Token != "" {
+		httpClient = oauth2.NewClient(context.Background(), oauth2.StaticTokenSource(&oauth2.Token{AccessToken: git.Token}))
+	}
+	client := github.NewClient(httpClient)
+	if git.ApiEndpoint != "" {
+		baseUrl, err := url.Parse(strings.TrimSuffix(git.ApiEndpoint, "/") + "/")
+		if err != nil {
+			return nil, err
+		}
+		client.BaseURL = baseUrl
+	}
+	return client, nil
+}
+
+// When the pull request is from a fork, the checked out code may belong to the base repository (for example, in pull_request_target workflows).
+// checkoutForkPullRequestHead downloads the head branch of the fork and changes the working directory to it.
+// The returned cleanup function restores the working directory and removes the downloaded code.
+func checkoutForkPullRequestHead(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient) (cleanup func() error, err error) {
+	cleanup = func() error { return nil }
+	head, err := getGitHubPullRequestHead(&repoConfig.Git, repoConfig.PullRequestID)
+	if err != nil {
+		// The token may not have access to the pull request details. The checked out code is scanned.
+		log.Warn("couldn't get the details of pull request", repoConfig.PullRequestID, "- scanning the current working directory:", err.Error())
+		return cleanup, nil
+	}
+	if !head.isFork(&repoConfig.Git) {
+		return cleanup, nil
+	}
+	log.Info("Pull request", repoConfig.PullRequestID, "is from the fork", head.owner+"/"+head.repo+". Downloading branch", head.branch)
+	wd, removeDir, err := utils.DownloadRepoToTempDir(client, head.branch, &utils.Git{RepoOwner: head.owner, RepoName: head.repo})
+	if err != nil {
+		return nil, err
+	}
+	restoreDir, err := utils.Chdir(wd)
+	if err != nil {
+		if e := removeDir(); e != nil {
+			log.Warn(e)
+		}
+		return nil, err
+	}
+	return func() error {
+		err := restoreDir()
+		if e := removeDir(); err == nil {
+			err = e
+		}
+		return err
+	}, nil
+}
+
+// Tokens of pull requests from forks are usually read-only, and therefore can't be used to comment on the pull request
+func isPermissionError(err error) bool {
+	var errorResponse *github.ErrorResponse
+	if errors.As(err, &errorResponse) && errorResponse.Response != nil {
+		return errorResponse.Response.StatusCode == http.StatusForbidden
+	}
+	return strings.Contains(err.Error(), "Resource not accessible by integration")
+}
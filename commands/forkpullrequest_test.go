@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-github/v45/github"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func createPullRequestServer(t *testing.T, headOwner, headRepo string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/jfrog/frogbot/pulls/5", r.URL.Path)
+		_, err := fmt.Fprintf(w, `{"number": 5, "head": {"ref": "feature", "repo": {"name": "%s", "owner": {"login": "%s"}}}, "base": {"ref": "master", "repo": {"name": "frogbot", "owner": {"login": "jfrog"}}}}`, headRepo, headOwner)
+		assert.NoError(t, err)
+	}))
+}
+
+func TestCheckoutForkPullRequestHead(t *testing.T) {
+	server := createPullRequestServer(t, "contributor", "frogbot-fork")
+	defer server.Close()
+
+	// The code is downloaded from the fork
+	client := mockVcsClient(t)
+	client.EXPECT().DownloadRepository(gomock.Any(), "contributor", "frogbot-fork", "feature", gomock.Any()).
+		DoAndReturn(func(_ any, _, _, _, localPath string) error {
+			return os.WriteFile(filepath.Join(localPath, "package.json"), []byte("{}"), 0600)
+		})
+
+	originalWd, err := os.Getwd()
+	assert.NoError(t, err)
+	repoConfig := newTestRepoConfig(vcsutils.GitHub, server.URL)
+	repoConfig.PullRequestID = 5
+	cleanup, err := checkoutForkPullRequestHead(repoConfig, client)
+	assert.NoError(t, err)
+	assert.FileExists(t, "package.json")
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.NotEqual(t, originalWd, wd)
+
+	assert.NoError(t, cleanup())
+	restoredWd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.Equal(t, originalWd, restoredWd)
+	assert.NoDirExists(t, wd)
+}
+
+func TestCheckoutForkPullRequestHeadNotFork(t *testing.T) {
+	server := createPullRequestServer(t, "jfrog", "frogbot")
+	defer server.Close()
+
+	// No download is expected
+	client := mockVcsClient(t)
+	repoConfig := newTestRepoConfig(vcsutils.GitHub, server.URL)
+	repoConfig.PullRequestID = 5
+	cleanup, err := checkoutForkPullRequestHead(repoConfig, client)
+	assert.NoError(t, err)
+	assert.NoError(t, cleanup())
+}
+
+func TestCheckoutForkPullRequestHeadNoPermissions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	// The current working directory is scanned
+	client := mockVcsClient(t)
+	repoConfig := newTestRepoConfig(vcsutils.GitHub, server.URL)
+	repoConfig.PullRequestID = 5
+	cleanup, err := checkoutForkPullRequestHead(repoConfig, client)
+	assert.NoError(t, err)
+	assert.NoError(t, cleanup())
+}
+
+func TestIsPermissionError(t *testing.T) {
+	assert.True(t, isPermissionError(&github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusForbidden}}))
+	assert.False(t, isPermissionError(&github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusInternalServerError}}))
+	assert.True(t, isPermissionError(errors.New("403 Resource not accessible by integration")))
+	assert.False(t, isPermissionError(errors.New("connection refused")))
+}
@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/xanzy/go-gitlab"
+)
+
+// The froggit-go VCS client doesn't support merge request approvals, and therefore the GitLab API is used directly.
+// When the scan is clean, the merge request is approved by the user of the Git token. Otherwise, the approval is removed.
+func applyGitLabApprovalGate(repoConfig *utils.FrogbotRepoConfig, issuesFound bool) error {
+	client, err := newGitLabClient(&repoConfig.Git)
+	if err != nil {
+		return err
+	}
+	projectId := fmt.Sprintf("%s/%s", repoConfig.RepoOwner, repoConfig.RepoName)
+	if issuesFound {
+		log.Info("Removing the approval of merge request", repoConfig.PullRequestID)
+		response, err := client.MergeRequestApprovals.UnapproveMergeRequest(projectId, repoConfig.PullRequestID)
+		// GitLab responds with 404 if the merge request wasn't approved by the user
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		return err
+	}
+	log.Info("Approving merge request", repoConfig.PullRequestID)
+	_, response, err := client.MergeRequestApprovals.ApproveMergeRequest(projectId, repoConfig.PullRequestID, &gitlab.ApproveMergeRequestOptions{})
+	// GitLab responds with 401 if the merge request was already approved by the user
+	if response != nil && response.StatusCode == http.StatusUnauthorized {
+		log.Debug("Merge request", repoConfig.PullRequestID, "was already approved")
+		return nil
+	}
+	return err
+}
+
+func newGitLabClient(git *utils.Git) (*gitlab.Client, error) {
+	if git.ApiEndpoint != "" {
+		return gitlab.NewClient(git.Token, gitlab.WithBaseURL(git.ApiEndpoint))
+	}
+	return gitlab.NewClient(git.Token)
+}
@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyGitLabApprovalGate(t *testing.T) {
+	tests := []struct {
+		name           string
+		issuesFound    bool
+		responseStatus int
+		expectedPath   string
+		expectError    bool
+	}{
+		{name: "approve", issuesFound: false, responseStatus: http.StatusCreated, expectedPath: "/api/v4/projects/jfrog%2Ffrogbot/merge_requests/5/approve"},
+		{name: "alreadyApproved", issuesFound: false, responseStatus: http.StatusUnauthorized, expectedPath: "/api/v4/projects/jfrog%2Ffrogbot/merge_requests/5/approve"},
+		{name: "unapprove", issuesFound: true, responseStatus: http.StatusCreated, expectedPath: "/api/v4/projects/jfrog%2Ffrogbot/merge_requests/5/unapprove"},
+		{name: "notApproved", issuesFound: true, responseStatus: http.StatusNotFound, expectedPath: "/api/v4/projects/jfrog%2Ffrogbot/merge_requests/5/unapprove"},
+		{name: "error", issuesFound: true, responseStatus: http.StatusForbidden, expectedPath: "/api/v4/projects/jfrog%2Ffrogbot/merge_requests/5/unapprove", expectError: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var requestPath string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// The GitLab client sends a GET request to configure its rate limiter
+				if r.Method == http.MethodGet {
+					return
+				}
+				requestPath = r.URL.EscapedPath()
+				w.WriteHeader(test.responseStatus)
+				_, err := w.Write([]byte("{}"))
+				assert.NoError(t, err)
+			}))
+			defer server.Close()
+
+			repoConfig := &utils.FrogbotRepoConfig{Params: utils.Params{Git: utils.Git{
+				RepoOwner:     "jfrog",
+				RepoName:      "frogbot",
+				Token:         "123456",
+				ApiEndpoint:   server.URL,
+				PullRequestID: 5,
+			}}}
+			err := applyGitLabApprovalGate(repoConfig, test.issuesFound)
+			if test.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedPath, requestPath)
+		})
+	}
+}
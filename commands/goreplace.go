@@ -0,0 +1,197 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+)
+
+const (
+	goModFileName           = "go.mod"
+	goPackageTypeIdentifier = "go://"
+)
+
+type goModuleVersion struct {
+	Path    string
+	Version string
+}
+
+// A replace directive of a go.mod file. The version of Old is empty if all its versions are replaced, and the version of New is empty if it's a local directory.
+type goModReplace struct {
+	Old goModuleVersion
+	New goModuleVersion
+}
+
+func (replace *goModReplace) isLocal() bool {
+	return replace.New.Version == ""
+}
+
+func (replace *goModReplace) matches(path, version string) bool {
+	return replace.Old.Path == path && (replace.Old.Version == "" || replace.Old.Version == version)
+}
+
+// Return the replace directives of the go.mod file in the given directory, or nil if the directory has no go.mod file
+func getGoModReplaces(dir string) ([]goModReplace, error) {
+	if _, err := os.Stat(filepath.Join(dir, goModFileName)); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	cmd := exec.Command(coreutils.Go.GetExecCommandName(), "mod", "edit", "-json")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read the replace directives of %s: %s", filepath.Join(dir, goModFileName), err.Error())
+	}
+	var goMod struct {
+		Replace []goModReplace
+	}
+	if err = json.Unmarshal(output, &goMod); err != nil {
+		return nil, err
+	}
+	return goMod.Replace, nil
+}
+
+// Return true if one of the working directories is a Go module with replace directives
+func hasGoModReplaces(workDirs []string) (bool, error) {
+	for _, wd := range workDirs {
+		replaces, err := getGoModReplaces(wd)
+		if err != nil {
+			return false, err
+		}
+		if len(replaces) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// applyGoModReplaces updates the Go dependency trees, which are built using 'go mod graph' with the modules as they are required,
+// to the modules which are actually built.
+// Modules replaced by other modules or versions are scanned as their replacements.
+// Modules replaced by local directories are part of the project's source code, so they are removed from the trees, and their dependencies are kept.
+func applyGoModReplaces(trees []*services.GraphNode, replaces []goModReplace) {
+	if len(replaces) == 0 {
+		return
+	}
+	for _, tree := range trees {
+		tree.Nodes = applyGoModReplacesOnNodes(tree, replaces)
+	}
+}
+
+func applyGoModReplacesOnNodes(parent *services.GraphNode, replaces []goModReplace) (nodes []*services.GraphNode) {
+	for _, node := range parent.Nodes {
+		node.Nodes = applyGoModReplacesOnNodes(node, replaces)
+		replace := findGoModReplace(node.Id, replaces)
+		switch {
+		case replace == nil:
+			nodes = append(nodes, node)
+		case replace.isLocal():
+			log.Debug(fmt.Sprintf("'%s' is replaced by the local directory '%s'. Scanning its dependencies only", strings.TrimPrefix(node.Id, goPackageTypeIdentifier), replace.New.Path))
+			for _, child := range node.Nodes {
+				child.Parent = parent
+			}
+			nodes = append(nodes, node.Nodes...)
+		default:
+			node.Id = goPackageTypeIdentifier + replace.New.Path + ":" + replace.New.Version
+			nodes = append(nodes, node)
+		}
+	}
+	return
+}
+
+// Find the replace directive of a node with the "go://<path>:<version>" ID
+func findGoModReplace(nodeId string, replaces []goModReplace) *goModReplace {
+	path, version, found := strings.Cut(strings.TrimPrefix(nodeId, goPackageTypeIdentifier), ":")
+	if !found {
+		return nil
+	}
+	for i := range replaces {
+		if replaces[i].matches(path, version) {
+			return &replaces[i]
+		}
+	}
+	return nil
+}
+
+// Return the replace directive, which replaces a module by the given module, or nil if the module isn't a replacement of another module
+func findGoModReplacementOf(replacementPath string, replaces []goModReplace) *goModReplace {
+	for i := range replaces {
+		if !replaces[i].isLocal() && replaces[i].New.Path == replacementPath {
+			return &replaces[i]
+		}
+	}
+	return nil
+}
+
+// fixPackageVersionGo upgrades a Go module using 'go get'.
+// If the module is the replacement of another module, 'go get' would have no effect, since the replace directive takes precedence over the requirement.
+// In this case, the version of the replacement in the replace directive is upgraded instead, and the other replace directives are kept.
+func fixPackageVersionGo(impactedPackage, fixVersion string) error {
+	replaces, err := getGoModReplaces(".")
+	if err != nil {
+		return err
+	}
+	replace := findGoModReplacementOf(impactedPackage, replaces)
+	if replace == nil {
+		return fixPackageVersionGeneric(coreutils.Go.GetExecCommandName(), []string{"get"}, impactedPackage, fixVersion, "@v")
+	}
+	log.Info(fmt.Sprintf("%s is the replacement of %s. Upgrading the replace directive", impactedPackage, replace.Old.Path))
+	if err = updateGoModReplace(replace, "v"+strings.TrimPrefix(fixVersion, "v")); err != nil {
+		return err
+	}
+	return runPackageMangerCommand(coreutils.Go.GetExecCommandName(), []string{"mod", "tidy"})
+}
+
+// overrideGoModuleVersion pins a transitive Go module to its fix version, by replacing all its versions with the fix version in the go.mod file.
+// Unlike 'go get', the replace directive also applies to the builds of the module as a dependency of other modules.
+// If the module is already replaced by another module, or if it's the replacement of another module, the existing replace directive is upgraded instead.
+func overrideGoModuleVersion(impactedPackage, fixVersion string) error {
+	replaces, err := getGoModReplaces(".")
+	if err != nil {
+		return err
+	}
+	version := "v" + strings.TrimPrefix(fixVersion, "v")
+	replaced := false
+	for i := range replaces {
+		if replaces[i].Old.Path != impactedPackage {
+			continue
+		}
+		if replaces[i].isLocal() {
+			return fmt.Errorf("%s is replaced by the local directory '%s', so its version can't be overridden", impactedPackage, replaces[i].New.Path)
+		}
+		log.Info(fmt.Sprintf("%s is a transitive dependency, which is already replaced by %s. Upgrading the replace directive", impactedPackage, replaces[i].New.Path))
+		if err = updateGoModReplace(&replaces[i], version); err != nil {
+			return err
+		}
+		replaced = true
+	}
+	if replaced {
+		return runPackageMangerCommand(coreutils.Go.GetExecCommandName(), []string{"mod", "tidy"})
+	}
+	if findGoModReplacementOf(impactedPackage, replaces) != nil {
+		return fixPackageVersionGo(impactedPackage, fixVersion)
+	}
+	log.Info(fmt.Sprintf("%s is a transitive dependency. Overriding its version with a replace directive in %s", impactedPackage, goModFileName))
+	if err = runPackageMangerCommand(coreutils.Go.GetExecCommandName(), []string{"mod", "edit", "-replace", impactedPackage + "=" + impactedPackage + "@" + version}); err != nil {
+		return err
+	}
+	return runPackageMangerCommand(coreutils.Go.GetExecCommandName(), []string{"mod", "tidy"})
+}
+
+// Update the version of the replacement in the replace directive
+func updateGoModReplace(replace *goModReplace, version string) error {
+	old := replace.Old.Path
+	if replace.Old.Version != "" {
+		old += "@" + replace.Old.Version
+	}
+	return runPackageMangerCommand(coreutils.Go.GetExecCommandName(), []string{"mod", "edit", "-replace", old + "=" + replace.New.Path + "@" + version})
+}
@@ -0,0 +1,132 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const replacedGoMod = `module github.com/jfrog/replaced
+
+go 1.19
+
+require (
+	github.com/jfrog/forked v1.0.0
+	github.com/jfrog/local v1.0.0
+	github.com/jfrog/pinned v1.0.0
+)
+
+replace (
+	github.com/jfrog/forked => github.com/frogs/forked v1.1.0
+	github.com/jfrog/local => ../local
+	github.com/jfrog/pinned v1.0.0 => github.com/jfrog/pinned v1.0.2
+)
+`
+
+func TestGetGoModReplaces(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{goModFileName: replacedGoMod})
+	replaces, err := getGoModReplaces(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []goModReplace{
+		{Old: goModuleVersion{Path: "github.com/jfrog/forked"}, New: goModuleVersion{Path: "github.com/frogs/forked", Version: "v1.1.0"}},
+		{Old: goModuleVersion{Path: "github.com/jfrog/local"}, New: goModuleVersion{Path: "../local"}},
+		{Old: goModuleVersion{Path: "github.com/jfrog/pinned", Version: "v1.0.0"}, New: goModuleVersion{Path: "github.com/jfrog/pinned", Version: "v1.0.2"}},
+	}, replaces)
+
+	hasReplaces, err := hasGoModReplaces([]string{t.TempDir(), dir})
+	assert.NoError(t, err)
+	assert.True(t, hasReplaces)
+
+	// No go.mod
+	replaces, err = getGoModReplaces(t.TempDir())
+	assert.NoError(t, err)
+	assert.Empty(t, replaces)
+}
+
+func TestApplyGoModReplaces(t *testing.T) {
+	replaces := []goModReplace{
+		{Old: goModuleVersion{Path: "github.com/jfrog/forked"}, New: goModuleVersion{Path: "github.com/frogs/forked", Version: "v1.1.0"}},
+		{Old: goModuleVersion{Path: "github.com/jfrog/local"}, New: goModuleVersion{Path: "../local"}},
+		{Old: goModuleVersion{Path: "github.com/jfrog/pinned", Version: "v1.0.0"}, New: goModuleVersion{Path: "github.com/jfrog/pinned", Version: "v1.0.2"}},
+	}
+	root := &services.GraphNode{Id: "go://github.com/jfrog/replaced"}
+	local := &services.GraphNode{Id: "go://github.com/jfrog/local:v1.0.0", Parent: root}
+	localDependency := &services.GraphNode{Id: "go://github.com/jfrog/pinned:v1.0.0", Parent: local}
+	local.Nodes = []*services.GraphNode{localDependency}
+	forked := &services.GraphNode{Id: "go://github.com/jfrog/forked:v1.0.0", Parent: root}
+	// Only v1.0.0 of the pinned module is replaced
+	otherVersion := &services.GraphNode{Id: "go://github.com/jfrog/pinned:v0.9.0", Parent: root}
+	root.Nodes = []*services.GraphNode{local, forked, otherVersion}
+
+	applyGoModReplaces([]*services.GraphNode{root}, replaces)
+	if assert.Len(t, root.Nodes, 3) {
+		// The dependencies of the local module are kept
+		assert.Equal(t, "go://github.com/jfrog/pinned:v1.0.2", root.Nodes[0].Id)
+		assert.Equal(t, root, root.Nodes[0].Parent)
+		assert.Equal(t, "go://github.com/frogs/forked:v1.1.0", root.Nodes[1].Id)
+		assert.Equal(t, "go://github.com/jfrog/pinned:v0.9.0", root.Nodes[2].Id)
+	}
+}
+
+func TestFindGoModReplacementOf(t *testing.T) {
+	replaces := []goModReplace{
+		{Old: goModuleVersion{Path: "github.com/jfrog/forked"}, New: goModuleVersion{Path: "github.com/frogs/forked", Version: "v1.1.0"}},
+		{Old: goModuleVersion{Path: "github.com/jfrog/local"}, New: goModuleVersion{Path: "../local"}},
+	}
+	assert.Equal(t, &replaces[0], findGoModReplacementOf("github.com/frogs/forked", replaces))
+	assert.Nil(t, findGoModReplacementOf("github.com/jfrog/forked", replaces))
+	assert.Nil(t, findGoModReplacementOf("../local", replaces))
+}
+
+func TestUpdateGoModReplace(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{goModFileName: replacedGoMod})
+	restoreDir, err := utils.Chdir(dir)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, restoreDir())
+	}()
+	replaces, err := getGoModReplaces(".")
+	require.NoError(t, err)
+	require.NoError(t, updateGoModReplace(findGoModReplacementOf("github.com/frogs/forked", replaces), "v1.2.0"))
+	require.NoError(t, updateGoModReplace(findGoModReplacementOf("github.com/jfrog/pinned", replaces), "v1.0.3"))
+
+	// The other replace directives aren't changed
+	replaces, err = getGoModReplaces(".")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []goModReplace{
+		{Old: goModuleVersion{Path: "github.com/jfrog/forked"}, New: goModuleVersion{Path: "github.com/frogs/forked", Version: "v1.2.0"}},
+		{Old: goModuleVersion{Path: "github.com/jfrog/local"}, New: goModuleVersion{Path: "../local"}},
+		{Old: goModuleVersion{Path: "github.com/jfrog/pinned", Version: "v1.0.0"}, New: goModuleVersion{Path: "github.com/jfrog/pinned", Version: "v1.0.3"}},
+	}, replaces)
+}
+
+func TestOverrideGoModuleVersion(t *testing.T) {
+	// The modules aren't downloaded, since the test module has no requirements
+	t.Setenv("GOPROXY", "off")
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{goModFileName: "module github.com/jfrog/overridden\n\ngo 1.19\n"})
+	restoreDir, err := utils.Chdir(dir)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, restoreDir())
+	}()
+	require.NoError(t, overrideGoModuleVersion("github.com/jfrog/transitive", "1.2.0"))
+	replaces, err := getGoModReplaces(".")
+	require.NoError(t, err)
+	assert.Equal(t, []goModReplace{{Old: goModuleVersion{Path: "github.com/jfrog/transitive"}, New: goModuleVersion{Path: "github.com/jfrog/transitive", Version: "v1.2.0"}}}, replaces)
+
+	// An existing replace directive of the module is upgraded, rather than adding another directive
+	require.NoError(t, overrideGoModuleVersion("github.com/jfrog/transitive", "1.2.1"))
+	replaces, err = getGoModReplaces(".")
+	require.NoError(t, err)
+	assert.Equal(t, []goModReplace{{Old: goModuleVersion{Path: "github.com/jfrog/transitive"}, New: goModuleVersion{Path: "github.com/jfrog/transitive", Version: "v1.2.1"}}}, replaces)
+
+	// A module replaced by a local directory is part of the project's source code
+	writeTestFiles(t, dir, map[string]string{goModFileName: replacedGoMod})
+	assert.ErrorContains(t, overrideGoModuleVersion("github.com/jfrog/local", "1.2.0"), "is replaced by the local directory '../local'")
+}
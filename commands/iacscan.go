@@ -0,0 +1,285 @@
+package commands
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	coreconfig "github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	iacTitle = "\n\n#### 🏗️ Infrastructure"
+	// The JFrog Advanced Security analyzer manager is downloaded by JFrog CLI to this path, under the JFrog home directory
+	analyzerManagerDir     = "dependencies/analyzerManager"
+	analyzerManagerName    = "analyzerManager"
+	iacScanCommand         = "iac"
+	iacScanType            = "iac-scan-modules"
+	iacScanConfigFileName  = "iac-config.yaml"
+	iacScanOutputFileName  = "iac-results.sarif"
+	helmChartFileName      = "Chart.yaml"
+	analyzerManagerMissing = "the Infrastructure as Code scan requires the JFrog Advanced Security analyzer manager, which wasn't found in %s. " +
+		"Run an audit using JFrog CLI to download it, or set scanIaC to false"
+)
+
+// The directories skipped when looking for Helm charts and Kubernetes manifests
+var iacSkippedDirs = []string{".git", "node_modules", "vendor", "target"}
+
+// Stops walking the directory once a Helm chart or a Kubernetes manifest is found
+var errIacFileFound = errors.New("an IaC file was found")
+
+type iacScanConfig struct {
+	Scans []iacScanConfiguration `yaml:"scans"`
+}
+
+type iacScanConfiguration struct {
+	Type           string   `yaml:"type"`
+	Output         string   `yaml:"output"`
+	Roots          []string `yaml:"roots"`
+	SkippedFolders []string `yaml:"skipped-folders"`
+}
+
+// The parts of the SARIF output of the analyzer manager used by Frogbot
+type sarifReport struct {
+	Runs []struct {
+		Results []sarifResult `json:"results"`
+	} `json:"runs"`
+}
+
+type sarifResult struct {
+	RuleId  string `json:"ruleId"`
+	Level   string `json:"level"`
+	Message struct {
+		Text string `json:"text"`
+	} `json:"message"`
+	Locations []struct {
+		PhysicalLocation struct {
+			ArtifactLocation struct {
+				Uri string `json:"uri"`
+			} `json:"artifactLocation"`
+			Region struct {
+				StartLine int `json:"startLine"`
+			} `json:"region"`
+		} `json:"physicalLocation"`
+	} `json:"locations"`
+}
+
+// auditIac scans the Helm charts and Kubernetes manifests in the working directories of the project for misconfigurations.
+// If no Helm charts or Kubernetes manifests are found, the scan is skipped.
+func auditIac(server *coreconfig.ServerDetails, workDirs []string) ([]utils.IacRow, error) {
+	var roots []string
+	for _, wd := range workDirs {
+		found, err := containsIacFiles(wd)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			roots = append(roots, wd)
+		}
+	}
+	if len(roots) == 0 {
+		log.Info("No Helm charts or Kubernetes manifests were found. Skipping the Infrastructure as Code scan")
+		return nil, nil
+	}
+	log.Info("Running the Infrastructure as Code scan on:", strings.Join(roots, ", "))
+	return runIacScan(server, roots)
+}
+
+// containsIacFiles returns true if the directory contains a Helm chart or a Kubernetes manifest
+func containsIacFiles(dir string) (found bool, err error) {
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != dir && isIacSkippedDir(entry.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.Name() == helmChartFileName {
+			found = true
+		} else if ext := filepath.Ext(entry.Name()); ext == ".yaml" || ext == ".yml" {
+			if found, err = isKubernetesManifest(path); err != nil {
+				return err
+			}
+		}
+		if found {
+			return errIacFileFound
+		}
+		return nil
+	})
+	if errors.Is(err, errIacFileFound) {
+		err = nil
+	}
+	return
+}
+
+func isIacSkippedDir(name string) bool {
+	for _, skippedDir := range iacSkippedDirs {
+		if name == skippedDir {
+			return true
+		}
+	}
+	return false
+}
+
+// A Kubernetes manifest has the top level apiVersion and kind fields
+func isKubernetesManifest(path string) (isManifest bool, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		e := file.Close()
+		if err == nil {
+			err = e
+		}
+	}()
+	var apiVersionFound, kindFound bool
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		apiVersionFound = apiVersionFound || strings.HasPrefix(line, "apiVersion:")
+		kindFound = kindFound || strings.HasPrefix(line, "kind:")
+		if apiVersionFound && kindFound {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+// Run the IaC scan of the analyzer manager on the given roots, and parse its SARIF output
+func runIacScan(server *coreconfig.ServerDetails, roots []string) (iacRows []utils.IacRow, err error) {
+	analyzerManagerPath, err := getAnalyzerManagerPath()
+	if err != nil {
+		return nil, err
+	}
+	tempDir, err := utils.CreateTempDir()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		e := utils.RemoveTempDir(tempDir)
+		if err == nil {
+			err = e
+		}
+	}()
+	configPath := filepath.Join(tempDir, iacScanConfigFileName)
+	outputPath := filepath.Join(tempDir, iacScanOutputFileName)
+	config, err := yaml.Marshal(iacScanConfig{Scans: []iacScanConfiguration{{
+		Type:           iacScanType,
+		Output:         outputPath,
+		Roots:          roots,
+		SkippedFolders: iacSkippedDirs,
+	}}})
+	if err != nil {
+		return nil, err
+	}
+	if err = os.WriteFile(configPath, config, 0600); err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(analyzerManagerPath, iacScanCommand, configPath)
+	cmd.Env = append(os.Environ(), getAnalyzerManagerEnv(server)...)
+	if output, e := cmd.CombinedOutput(); e != nil {
+		return nil, fmt.Errorf("the Infrastructure as Code scan failed: %s\n%s", e.Error(), string(output))
+	}
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		return nil, err
+	}
+	return parseIacResults(content, roots)
+}
+
+func getAnalyzerManagerPath() (string, error) {
+	jfrogHomeDir, err := coreutils.GetJfrogHomeDir()
+	if err != nil {
+		return "", err
+	}
+	analyzerManagerPath := filepath.Join(jfrogHomeDir, analyzerManagerDir, analyzerManagerName)
+	if runtime.GOOS == "windows" {
+		analyzerManagerPath += ".exe"
+	}
+	if _, err = os.Stat(analyzerManagerPath); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf(analyzerManagerMissing, filepath.Dir(analyzerManagerPath))
+		}
+		return "", err
+	}
+	return analyzerManagerPath, nil
+}
+
+// The analyzer manager authenticates to the JFrog Platform using these environment variables
+func getAnalyzerManagerEnv(server *coreconfig.ServerDetails) []string {
+	env := []string{"JF_PLATFORM_URL=" + server.Url}
+	if server.AccessToken != "" {
+		return append(env, "JF_TOKEN="+server.AccessToken)
+	}
+	return append(env, "JF_USER="+server.User, "JF_PASS="+server.Password)
+}
+
+// Convert the SARIF results to IaC rows. The files are shown relative to the scanned roots.
+func parseIacResults(content []byte, roots []string) (iacRows []utils.IacRow, err error) {
+	var report sarifReport
+	if err = json.Unmarshal(content, &report); err != nil {
+		return nil, fmt.Errorf("couldn't parse the results of the Infrastructure as Code scan: %s", err.Error())
+	}
+	for _, run := range report.Runs {
+		for _, result := range run.Results {
+			iacRow := utils.IacRow{Severity: getIacSeverity(result.Level), Finding: result.Message.Text, RuleId: result.RuleId}
+			if len(result.Locations) > 0 {
+				location := result.Locations[0].PhysicalLocation
+				iacRow.FullPath = filepath.FromSlash(strings.TrimPrefix(location.ArtifactLocation.Uri, "file://"))
+				iacRow.File = getRelativeIacPath(location.ArtifactLocation.Uri, roots)
+				iacRow.Line = location.Region.StartLine
+			}
+			iacRows = append(iacRows, iacRow)
+		}
+	}
+	return
+}
+
+// Map the SARIF levels to the Xray severities
+func getIacSeverity(level string) string {
+	switch level {
+	case "error":
+		return "High"
+	case "note":
+		return "Low"
+	default:
+		return "Medium"
+	}
+}
+
+func getRelativeIacPath(uri string, roots []string) string {
+	path := filepath.FromSlash(strings.TrimPrefix(uri, "file://"))
+	for _, root := range roots {
+		if relativePath, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(relativePath, "..") {
+			return filepath.ToSlash(relativePath)
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+// Create the Infrastructure section of the comment, which lists the misconfigurations in a separate table
+func createIacContent(iacRows []utils.IacRow, writer utils.OutputWriter) string {
+	if len(iacRows) == 0 {
+		return ""
+	}
+	var tableContent strings.Builder
+	for _, iacRow := range iacRows {
+		tableContent.WriteString(writer.IacTableRow(iacRow))
+	}
+	return iacTitle + "\n" + writer.IacTableHeader() + tableContent.String()
+}
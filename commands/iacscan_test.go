@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainsIacFiles(t *testing.T) {
+	testCases := []struct {
+		name     string
+		files    map[string]string
+		expected bool
+	}{
+		{name: "helmChart", files: map[string]string{"chart/Chart.yaml": "name: app\n"}, expected: true},
+		{name: "kubernetesManifest", files: map[string]string{"k8s/deployment.yml": "apiVersion: apps/v1\nkind: Deployment\n"}, expected: true},
+		{name: "otherYaml", files: map[string]string{".frogbot/frogbot-config.yml": "- params:\n    git:\n      repoName: frogbot\n"}, expected: false},
+		{name: "skippedDir", files: map[string]string{"node_modules/chart/Chart.yaml": "name: app\n"}, expected: false},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTestFiles(t, dir, test.files)
+			found, err := containsIacFiles(dir)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, found)
+		})
+	}
+}
+
+func TestAuditIacWithoutIacFiles(t *testing.T) {
+	// The scan is skipped, so the analyzer manager isn't required
+	iacRows, err := auditIac(nil, []string{t.TempDir()})
+	assert.NoError(t, err)
+	assert.Empty(t, iacRows)
+}
+
+func TestParseIacResults(t *testing.T) {
+	root := filepath.Join(string(filepath.Separator)+"repo", "app")
+	content := `{"runs": [{"results": [
+		{"ruleId": "k8s-run-as-root", "level": "error", "message": {"text": "Container is running as root"},
+		 "locations": [{"physicalLocation": {"artifactLocation": {"uri": "file://` + filepath.ToSlash(filepath.Join(root, "chart", "templates", "deployment.yaml")) + `"}, "region": {"startLine": 12}}}]},
+		{"ruleId": "k8s-node-port", "level": "note", "message": {"text": "Service exposes a NodePort"}}
+	]}]}`
+	iacRows, err := parseIacResults([]byte(content), []string{root})
+	assert.NoError(t, err)
+	assert.Equal(t, []utils.IacRow{
+		{Severity: "High", File: "chart/templates/deployment.yaml", Line: 12, Finding: "Container is running as root", RuleId: "k8s-run-as-root",
+			FullPath: filepath.Join(root, "chart", "templates", "deployment.yaml")},
+		{Severity: "Low", Finding: "Service exposes a NodePort", RuleId: "k8s-node-port"},
+	}, iacRows)
+
+	_, err = parseIacResults([]byte("not sarif"), []string{root})
+	assert.Error(t, err)
+}
+
+func TestCreateScanResultsMessage(t *testing.T) {
+	writer := &utils.SimplifiedOutput{}
+	vulnerabilities := []formats.VulnerabilityOrViolationRow{{Severity: "High", ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "1.2.5"}}
+	iacRows := []utils.IacRow{{Severity: "High", File: "k8s/deployment.yaml", Line: 3, Finding: "Container is running as root"}}
+
+	// Without misconfigurations, the message is identical to the dependencies message
+	assert.Equal(t, createPullRequestMessage(vulnerabilities, writer), createScanResultsMessage(&auditResults{vulnerabilitiesRows: vulnerabilities}, writer))
+	assert.Equal(t, writer.NoVulnerabilitiesTitle(), createScanResultsMessage(&auditResults{}, writer))
+
+	// The misconfigurations are listed in a separate section, after the dependencies table
+	message := createScanResultsMessage(&auditResults{vulnerabilitiesRows: vulnerabilities, iacRows: iacRows}, writer)
+	assert.True(t, strings.HasPrefix(message, writer.VulnerabiltiesTitle()+writer.TableHeader()))
+	assert.True(t, strings.HasSuffix(message, iacTitle+"\n"+writer.IacTableHeader()+"\n| High | k8s/deployment.yaml:3 | Container is running as root |"))
+
+	// Misconfigurations without dependency issues aren't reported as a clean scan
+	message = createScanResultsMessage(&auditResults{iacRows: iacRows}, writer)
+	assert.Equal(t, writer.VulnerabiltiesTitle()+createIacContent(iacRows, writer), message)
+}
+
+func TestCreateScanResultsTablesSectionOrder(t *testing.T) {
+	writer := &utils.SimplifiedOutput{}
+	results := &auditResults{
+		vulnerabilitiesRows: []formats.VulnerabilityOrViolationRow{{Severity: "High", ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "1.2.5"}},
+		iacRows:             []utils.IacRow{{Severity: "High", File: "k8s/deployment.yaml", Line: 3, Finding: "Container is running as root"}},
+		secrets:             []secretRow{{file: "config.yml", line: 3, secretType: "AWS access key ID", redactedValue: "AKIA****"}},
+	}
+	dependenciesTable := writer.TableHeader() + getTableContent(results.vulnerabilitiesRows, writer)
+	iacContent := createIacContent(results.iacRows, writer)
+	secretsContent := createSecretsContent(results.secrets)
+
+	assert.Equal(t, dependenciesTable+iacContent+secretsContent, createScanResultsTables(results, writer, utils.DefaultSectionOrder))
+	assert.Equal(t, secretsContent+dependenciesTable+iacContent, createScanResultsTables(results, writer, []string{utils.SecretsSection, utils.SecuritySection, utils.IacSection}))
+	// The sections which aren't listed are hidden
+	assert.Equal(t, iacContent+dependenciesTable, createScanResultsTables(results, writer, []string{utils.IacSection, utils.SecuritySection}))
+}
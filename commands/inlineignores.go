@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const inlineIgnoresTitle = "#### 🙈 Issues ignored in the manifests"
+
+// filterInlineIgnoredIssues removes the issues ignored by the "frogbot:ignore" annotations in the manifests of the working dirs, if inlineIgnores is set,
+// and returns them separately. The ignored issues are logged with the reasons of their annotations.
+func filterInlineIgnoredIssues(project *utils.Project, rows []formats.VulnerabilityOrViolationRow) ([]formats.VulnerabilityOrViolationRow, []utils.SuppressedIssue) {
+	if !project.InlineIgnores || len(rows) == 0 {
+		return rows, nil
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		log.Warn("couldn't read the inline ignores:", err.Error())
+		return rows, nil
+	}
+	filteredRows, suppressedIssues := utils.FilterInlineIgnoredIssues(rows, project.GetInlineIgnores(getFullPathWorkingDirs(project, wd), wd), time.Now())
+	for _, issue := range suppressedIssues {
+		log.Info(fmt.Sprintf("Ignoring %s of %s:%s, as annotated in %s. Reason: %s", issue.InlineIgnore.Id, issue.ImpactedDependencyName, issue.ImpactedDependencyVersion,
+			issue.InlineIgnore.Location(), getInlineIgnoreReason(issue.InlineIgnore)))
+	}
+	return filteredRows, suppressedIssues
+}
+
+// The inline ignores of a pull request scan are read from the source branch, so the pull request could ignore the issues it adds.
+// They are honored only if repoConfigCanRelaxGating is set, like the other settings of the scanned branch which relax the gating.
+// The projects are copied, so the projects of the config aren't modified.
+func disableUntrustedInlineIgnores(repoConfig *utils.FrogbotRepoConfig, projects []utils.Project) []utils.Project {
+	if !repoConfig.InlineIgnores || repoConfig.RepoConfigCanRelaxGating {
+		return projects
+	}
+	log.Warn("The inline ignores aren't applied to the pull request scan, since they are read from the scanned branch and repoConfigCanRelaxGating isn't set")
+	projects = append([]utils.Project{}, projects...)
+	for index := range projects {
+		projects[index].InlineIgnores = false
+	}
+	return projects
+}
+
+func getInlineIgnoreReason(inlineIgnore *utils.InlineIgnore) string {
+	if inlineIgnore.Reason == "" {
+		return "not specified"
+	}
+	return inlineIgnore.Reason
+}
+
+// List the issues ignored by inline ignores, with the locations and the reasons of their annotations, so that the reviewers can see the ignores added by the pull request
+func createInlineIgnoresNote(suppressedIssues []utils.SuppressedIssue) string {
+	var note strings.Builder
+	for _, issue := range suppressedIssues {
+		if issue.InlineIgnore == nil {
+			continue
+		}
+		note.WriteString(fmt.Sprintf("\n- **%s** of `%s %s` is ignored by the annotation of `%s` in `%s`", issue.InlineIgnore.Id, issue.ImpactedDependencyName, issue.ImpactedDependencyVersion,
+			issue.InlineIgnore.Dependency, issue.InlineIgnore.Location()))
+		if issue.InlineIgnore.Until != "" {
+			note.WriteString(" until " + issue.InlineIgnore.Until)
+		}
+		note.WriteString(". Reason: " + getInlineIgnoreReason(issue.InlineIgnore))
+	}
+	if note.Len() == 0 {
+		return ""
+	}
+	return "\n\n" + inlineIgnoresTitle + "\n" + note.String()
+}
@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterInlineIgnoredIssues(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "requirements.txt"), []byte("requests==2.25.0 # frogbot:ignore CVE-2023-32681 the proxy credentials aren't used\n"), 0600))
+	restoreDir, err := utils.Chdir(dir)
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, restoreDir())
+	}()
+
+	rows := []formats.VulnerabilityOrViolationRow{
+		{IssueId: "XRAY-1", ImpactedDependencyName: "requests", ImpactedDependencyVersion: "2.25.0", Cves: []formats.CveRow{{Id: "CVE-2023-32681"}}},
+		{IssueId: "XRAY-2", ImpactedDependencyName: "urllib3", ImpactedDependencyVersion: "1.26.0"},
+		// The annotated issue of another dependency isn't ignored
+		{IssueId: "XRAY-3", ImpactedDependencyName: "idna", ImpactedDependencyVersion: "2.10", Cves: []formats.CveRow{{Id: "CVE-2023-32681"}}},
+	}
+	// The inline ignores are read only if inlineIgnores is set
+	filteredRows, suppressedIssues := filterInlineIgnoredIssues(&utils.Project{}, rows)
+	assert.Equal(t, rows, filteredRows)
+	assert.Empty(t, suppressedIssues)
+
+	filteredRows, suppressedIssues = filterInlineIgnoredIssues(&utils.Project{InlineIgnores: true}, rows)
+	assert.Equal(t, rows[1:], filteredRows)
+	if assert.Len(t, suppressedIssues, 1) {
+		assert.Equal(t, "requirements.txt:1", suppressedIssues[0].InlineIgnore.Location())
+		assert.Equal(t, "the proxy credentials aren't used", suppressedIssues[0].InlineIgnore.Reason)
+	}
+}
+
+func TestCreateInlineIgnoresNote(t *testing.T) {
+	assert.Empty(t, createInlineIgnoresNote(nil))
+	assert.Empty(t, createInlineIgnoresNote([]utils.SuppressedIssue{{IgnoredDependency: "lodash <4.17.21"}}))
+
+	suppressedIssues := []utils.SuppressedIssue{
+		{VulnerabilityOrViolationRow: formats.VulnerabilityOrViolationRow{ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "4.17.20"}, IgnoredDependency: "lodash <4.17.21"},
+		{VulnerabilityOrViolationRow: formats.VulnerabilityOrViolationRow{ImpactedDependencyName: "requests", ImpactedDependencyVersion: "2.25.0"},
+			InlineIgnore: &utils.InlineIgnore{Id: "CVE-2023-32681", Reason: "no proxies", Dependency: "requests", File: "api/requirements.txt", Line: 3}},
+		{VulnerabilityOrViolationRow: formats.VulnerabilityOrViolationRow{ImpactedDependencyName: "github.com/nats-io/nats-server/v2", ImpactedDependencyVersion: "2.7.0"},
+			InlineIgnore: &utils.InlineIgnore{Id: "CVE-2022-24450", Until: "2024-06-01", Dependency: "github.com/nats-io/nats-server/v2", File: "go.mod", Line: 7}},
+	}
+	assert.Equal(t, "\n\n#### 🙈 Issues ignored in the manifests\n"+
+		"\n- **CVE-2023-32681** of `requests 2.25.0` is ignored by the annotation of `requests` in `api/requirements.txt:3`. Reason: no proxies"+
+		"\n- **CVE-2022-24450** of `github.com/nats-io/nats-server/v2 2.7.0` is ignored by the annotation of `github.com/nats-io/nats-server/v2` in `go.mod:7` until 2024-06-01. Reason: not specified", createInlineIgnoresNote(suppressedIssues))
+}
+
+func TestDisableUntrustedInlineIgnores(t *testing.T) {
+	projects := []utils.Project{{InlineIgnores: true}, {InlineIgnores: true}}
+	repoConfig := &utils.FrogbotRepoConfig{}
+	repoConfig.InlineIgnores = true
+	// The inline ignores of the scanned branch are disabled, without modifying the projects of the config
+	trustedProjects := disableUntrustedInlineIgnores(repoConfig, projects)
+	assert.Equal(t, []utils.Project{{}, {}}, trustedProjects)
+	assert.True(t, projects[0].InlineIgnores)
+
+	repoConfig.RepoConfigCanRelaxGating = true
+	assert.Equal(t, projects, disableUntrustedInlineIgnores(repoConfig, projects))
+}
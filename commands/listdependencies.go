@@ -0,0 +1,384 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsclient"
+	coreconfig "github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+)
+
+const (
+	// Dependencies list output formats
+	TableFormat = "table"
+	CsvFormat   = "csv"
+
+	vulnerableStatus = "vulnerable"
+	cleanStatus      = "clean"
+
+	unsupportedListFormatErr = "the output format '%s' is not supported. The supported formats are: " + TableFormat + ", " + JsonFormat + " and " + CsvFormat
+
+	errLockReportOnlyWithoutFile = "the --" + lockOnlyFlag + " option requires the --" + lockFlag + " option"
+
+	lockReportHeader = "# Frogbot dependency lock report, generated by the list-dependencies command. The resolved dependencies are listed by their ecosystems, sorted by their names and versions."
+)
+
+var dependenciesListHeader = []string{"NAME", "VERSION", "ECOSYSTEM", "DIRECT", "STATUS", "SEVERITY", "ISSUES"}
+
+// ListDependenciesCmd lists all the resolved dependencies of a local directory, with the vulnerability status of each dependency, without any Git operation.
+type ListDependenciesCmd struct {
+	// The path of the directory to list. If empty, the current working directory is listed.
+	Path string
+	// The output format of the dependencies list
+	Format string
+	// The path of a file to write the dependencies list to. If empty, the list is printed to the standard output.
+	OutputFile string
+	// The path of a file to write the dependency lock report to. If empty, the report isn't created.
+	LockReportFile string
+	// If true, only the dependency lock report is created, and the dependencies aren't scanned with Xray
+	LockReportOnly bool
+}
+
+// The resolved dependency trees of a project
+type projectDependencyTrees struct {
+	project   *utils.Project
+	techTrees []*technologyTrees
+}
+
+// A resolved dependency, and the issues Xray found in it
+type dependencyStatus struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Ecosystem string `json:"ecosystem"`
+	// True if the dependency is a direct dependency of one of the scanned modules
+	Direct     bool `json:"direct"`
+	Vulnerable bool `json:"vulnerable"`
+	// The highest severity of the issues found in the dependency
+	Severity string `json:"severity,omitempty"`
+	// The CVE IDs of the issues, or their Xray issue IDs if they have no CVEs
+	Issues []string `json:"issues,omitempty"`
+}
+
+// The key of a dependency in the dependencies list
+type dependencyKey struct {
+	ecosystem coreutils.Technology
+	formats.ComponentRow
+}
+
+// Run the dependencies listing. The VCS client isn't used, and may be nil.
+// The list includes all the issues Xray found, regardless of the severity policy, and the command doesn't fail on security issues.
+func (cmd ListDependenciesCmd) Run(configAggregator utils.FrogbotConfigAggregator, _ vcsclient.VcsClient) (err error) {
+	if err = utils.ValidateSingleRepoConfiguration(&configAggregator); err != nil {
+		return err
+	}
+	if !isSupportedListFormat(cmd.Format) {
+		return fmt.Errorf(unsupportedListFormatErr, cmd.Format)
+	}
+	if cmd.LockReportOnly && cmd.LockReportFile == "" {
+		return errors.New(errLockReportOnlyWithoutFile)
+	}
+	// The output files paths are relative to the original working directory
+	if cmd.OutputFile != "" {
+		if cmd.OutputFile, err = filepath.Abs(cmd.OutputFile); err != nil {
+			return err
+		}
+	}
+	if cmd.LockReportFile != "" {
+		if cmd.LockReportFile, err = filepath.Abs(cmd.LockReportFile); err != nil {
+			return err
+		}
+	}
+	repoConfig := &configAggregator[0]
+	if cmd.Path != "" {
+		var restoreDir func() error
+		if restoreDir, err = utils.Chdir(cmd.Path); err != nil {
+			return err
+		}
+		defer func() {
+			e := restoreDir()
+			if err == nil {
+				err = e
+			}
+		}()
+	}
+
+	projectsTrees, err := resolveDependencyTrees(repoConfig)
+	if err != nil {
+		return &ScanExecutionError{Err: err}
+	}
+	// The lock report is created from the resolved trees only, before they are scanned
+	if cmd.LockReportFile != "" {
+		if err = writeCommandOutput(formatLockReport(getResolvedDependencies(projectsTrees)), cmd.LockReportFile, "dependency lock report"); err != nil {
+			return err
+		}
+	}
+	if cmd.LockReportOnly {
+		return nil
+	}
+	dependencies, err := listDependencies(repoConfig, projectsTrees)
+	if err != nil {
+		return &ScanExecutionError{Err: err}
+	}
+	output, err := formatDependencies(dependencies, cmd.Format)
+	if err != nil {
+		return err
+	}
+	return writeCommandOutput(output, cmd.OutputFile, "dependencies list")
+}
+
+// Install the projects if needed, and resolve their dependency trees
+func resolveDependencyTrees(repoConfig *utils.FrogbotRepoConfig) ([]projectDependencyTrees, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	var projectsTrees []projectDependencyTrees
+	for projectIndex := range repoConfig.Projects {
+		project := &repoConfig.Projects[projectIndex]
+		fullPathWds := getFullPathWorkingDirs(project, wd)
+		for _, fullPathWd := range fullPathWds {
+			if err = runInstallIfNeeded(project, fullPathWd, true); err != nil {
+				return nil, err
+			}
+		}
+		techTrees, errorList := buildDependencyTrees(project, fullPathWds)
+		if len(errorList) > 0 {
+			return nil, errors.New(strings.Join(errorList, "\n"))
+		}
+		projectsTrees = append(projectsTrees, projectDependencyTrees{project: project, techTrees: techTrees})
+	}
+	return projectsTrees, nil
+}
+
+// Return the dependencies of the resolved trees, without their vulnerability status, sorted by ecosystem, name and version
+func getResolvedDependencies(projectsTrees []projectDependencyTrees) []dependencyStatus {
+	dependencies := make(map[dependencyKey]*dependencyStatus)
+	for _, projectTrees := range projectsTrees {
+		for _, techTree := range projectTrees.techTrees {
+			addTreesDependencies(dependencies, techTree)
+		}
+	}
+	return sortDependencies(dependencies)
+}
+
+// Scan the resolved dependency trees with Xray, and return all the dependencies with their issues, sorted by ecosystem, name and version
+func listDependencies(repoConfig *utils.FrogbotRepoConfig, projectsTrees []projectDependencyTrees) ([]dependencyStatus, error) {
+	dependencies := make(map[dependencyKey]*dependencyStatus)
+	for _, projectTrees := range projectsTrees {
+		project, techTrees := projectTrees.project, projectTrees.techTrees
+		for _, techTree := range techTrees {
+			addTreesDependencies(dependencies, techTree)
+		}
+		xrayScanParams := createXrayScanParams(project.Watches, repoConfig.JFrogProjectKey, repoConfig.ScanMode)
+		batchSize := project.ScanBatchSize
+		if batchSize < 1 {
+			batchSize = 1
+		}
+		scans, isMultipleRoot, err := auditWithFailover(&repoConfig.Server, repoConfig.XrayFailoverUrls, func(server *coreconfig.ServerDetails) ([]services.ScanResponse, bool, error) {
+			results, isMultipleRoot, scanErrors, err := scanDependencyTrees(xrayScanParams, server, batchSize, techTrees, project.ScanCache)
+			if err == nil && len(scanErrors) > 0 {
+				err = errors.New(strings.Join(scanErrors, "\n"))
+			}
+			return results, isMultipleRoot, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		issuesRows, err := createAllIssuesRows(scans, isMultipleRoot)
+		if err != nil {
+			return nil, err
+		}
+		addDependenciesIssues(dependencies, issuesRows)
+	}
+	log.Info(fmt.Sprintf("Listed %d dependencies", len(dependencies)))
+	return sortDependencies(dependencies), nil
+}
+
+// Add the dependencies of the trees to the dependencies list. The roots of the trees are the scanned modules, rather than dependencies.
+func addTreesDependencies(dependencies map[dependencyKey]*dependencyStatus, techTree *technologyTrees) {
+	visited := make(map[string]bool)
+	var addNodes func(nodes []*services.GraphNode, direct bool)
+	addNodes = func(nodes []*services.GraphNode, direct bool) {
+		for _, node := range nodes {
+			key := dependencyKey{ecosystem: techTree.technology, ComponentRow: getComponentRow(node.Id)}
+			dependency, exists := dependencies[key]
+			if !exists {
+				dependency = &dependencyStatus{Name: key.Name, Version: key.Version, Ecosystem: techTree.technology.ToString()}
+				dependencies[key] = dependency
+			}
+			dependency.Direct = dependency.Direct || direct
+			// Each dependency is walked once, which also stops on dependency cycles
+			if !visited[node.Id] {
+				visited[node.Id] = true
+				addNodes(node.Nodes, false)
+			}
+		}
+	}
+	for _, tree := range techTree.trees {
+		addNodes(tree.Nodes, true)
+	}
+}
+
+// Add the issues to their impacted dependencies, and set the severity of each dependency to the highest severity of its issues
+func addDependenciesIssues(dependencies map[dependencyKey]*dependencyStatus, issuesRows []formats.VulnerabilityOrViolationRow) {
+	for _, row := range issuesRows {
+		key := dependencyKey{ecosystem: row.Technology, ComponentRow: formats.ComponentRow{Name: row.ImpactedDependencyName, Version: row.ImpactedDependencyVersion}}
+		dependency, exists := dependencies[key]
+		if !exists {
+			// The impacted dependency may be missing from the trees, such as a dependency whose ID is normalized by Xray
+			dependency = &dependencyStatus{Name: key.Name, Version: key.Version, Ecosystem: row.Technology.ToString()}
+			dependencies[key] = dependency
+		}
+		if !dependency.Vulnerable || utils.GetSeverityNumValue(row.Severity) > utils.GetSeverityNumValue(dependency.Severity) {
+			dependency.Severity = row.Severity
+		}
+		dependency.Vulnerable = true
+		for _, issueId := range getRowIssueIds(row) {
+			if !containsString(dependency.Issues, issueId) {
+				dependency.Issues = append(dependency.Issues, issueId)
+			}
+		}
+	}
+}
+
+func getRowIssueIds(row formats.VulnerabilityOrViolationRow) (issueIds []string) {
+	for _, cve := range row.Cves {
+		if cve.Id != "" {
+			issueIds = append(issueIds, cve.Id)
+		}
+	}
+	if len(issueIds) == 0 && row.IssueId != "" {
+		issueIds = append(issueIds, row.IssueId)
+	}
+	return
+}
+
+func containsString(values []string, value string) bool {
+	for _, existing := range values {
+		if existing == value {
+			return true
+		}
+	}
+	return false
+}
+
+func sortDependencies(dependencies map[dependencyKey]*dependencyStatus) []dependencyStatus {
+	sorted := make([]dependencyStatus, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		sort.Strings(dependency.Issues)
+		sorted = append(sorted, *dependency)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Ecosystem != sorted[j].Ecosystem {
+			return sorted[i].Ecosystem < sorted[j].Ecosystem
+		}
+		if sorted[i].Name != sorted[j].Name {
+			return sorted[i].Name < sorted[j].Name
+		}
+		return sorted[i].Version < sorted[j].Version
+	})
+	return sorted
+}
+
+// Format the resolved dependencies as a deterministic text report, with a name@version line per dependency under the section of its ecosystem,
+// so that the report can be committed and its changes reviewed in the diffs of the pull requests.
+// The report doesn't include the vulnerability status, which changes without any change of the dependencies.
+func formatLockReport(dependencies []dependencyStatus) string {
+	var report strings.Builder
+	report.WriteString(lockReportHeader + "\n")
+	var ecosystem string
+	for index, dependency := range dependencies {
+		if index == 0 || dependency.Ecosystem != ecosystem {
+			ecosystem = dependency.Ecosystem
+			report.WriteString(fmt.Sprintf("\n[%s]\n", ecosystem))
+		}
+		report.WriteString(dependency.Name + "@" + dependency.Version + "\n")
+	}
+	return report.String()
+}
+
+func isSupportedListFormat(format string) bool {
+	switch format {
+	case "", TableFormat, JsonFormat, CsvFormat:
+		return true
+	}
+	return false
+}
+
+func formatDependencies(dependencies []dependencyStatus, format string) (string, error) {
+	switch format {
+	case "", TableFormat:
+		return formatDependenciesTable(dependencies)
+	case JsonFormat:
+		content, err := json.MarshalIndent(dependencies, "", "  ")
+		return string(content), err
+	case CsvFormat:
+		return formatDependenciesCsv(dependencies)
+	}
+	return "", fmt.Errorf(unsupportedListFormatErr, format)
+}
+
+func (ds *dependencyStatus) toRecord() []string {
+	status := cleanStatus
+	if ds.Vulnerable {
+		status = vulnerableStatus
+	}
+	return []string{ds.Name, ds.Version, ds.Ecosystem, strconv.FormatBool(ds.Direct), status, ds.Severity, strings.Join(ds.Issues, " ")}
+}
+
+func formatDependenciesTable(dependencies []dependencyStatus) (string, error) {
+	var buffer bytes.Buffer
+	writer := tabwriter.NewWriter(&buffer, 0, 0, 2, ' ', 0)
+	records := [][]string{dependenciesListHeader}
+	for index := range dependencies {
+		records = append(records, dependencies[index].toRecord())
+	}
+	for _, record := range records {
+		if _, err := fmt.Fprintln(writer, strings.Join(record, "\t")); err != nil {
+			return "", err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return "", err
+	}
+	// The cells are padded, including the empty cells at the end of the lines
+	lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+	for index := range lines {
+		lines[index] = strings.TrimRight(lines[index], " ")
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// The issues of each dependency are separated by spaces, so that the records have a fixed number of columns
+func formatDependenciesCsv(dependencies []dependencyStatus) (string, error) {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	header := make([]string, len(dependenciesListHeader))
+	for index, column := range dependenciesListHeader {
+		header[index] = strings.ToLower(column)
+	}
+	if err := writer.Write(header); err != nil {
+		return "", err
+	}
+	for index := range dependencies {
+		if err := writer.Write(dependencies[index].toRecord()); err != nil {
+			return "", err
+		}
+	}
+	writer.Flush()
+	return strings.TrimSuffix(buffer.String(), "\n"), writer.Error()
+}
@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func createListDependenciesTestTree() *technologyTrees {
+	minimist := &services.GraphNode{Id: "npm://minimist:0.0.8"}
+	mkdirp := &services.GraphNode{Id: "npm://mkdirp:0.5.1", Nodes: []*services.GraphNode{minimist}}
+	// A dependency cycle
+	minimist.Nodes = []*services.GraphNode{mkdirp}
+	return &technologyTrees{technology: coreutils.Npm, trees: []*services.GraphNode{
+		{Id: "npm://frogbot-test:1.0.0", Nodes: []*services.GraphNode{mkdirp, {Id: "npm://lodash:4.17.15"}}},
+		{Id: "npm://frogbot-test-2:1.0.0", Nodes: []*services.GraphNode{minimist}},
+	}}
+}
+
+func TestListDependencies(t *testing.T) {
+	dependencies := make(map[dependencyKey]*dependencyStatus)
+	addTreesDependencies(dependencies, createListDependenciesTestTree())
+	addDependenciesIssues(dependencies, []formats.VulnerabilityOrViolationRow{
+		{Severity: "Medium", ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "0.0.8", Technology: coreutils.Npm, IssueId: "XRAY-1", Cves: []formats.CveRow{{Id: "CVE-2020-7598"}}},
+		{Severity: "Critical", ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "0.0.8", Technology: coreutils.Npm, IssueId: "XRAY-2", Cves: []formats.CveRow{{Id: "CVE-2021-44906"}}},
+		{Severity: "Low", ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "0.0.8", Technology: coreutils.Npm, IssueId: "XRAY-3"},
+		// The same issue in another scan
+		{Severity: "Medium", ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "0.0.8", Technology: coreutils.Npm, IssueId: "XRAY-1", Cves: []formats.CveRow{{Id: "CVE-2020-7598"}}},
+	})
+	// The roots of the trees are the scanned modules, and a dependency is direct if it's a direct dependency of any of the modules
+	assert.Equal(t, []dependencyStatus{
+		{Name: "lodash", Version: "4.17.15", Ecosystem: "npm", Direct: true},
+		{Name: "minimist", Version: "0.0.8", Ecosystem: "npm", Direct: true, Vulnerable: true, Severity: "Critical", Issues: []string{"CVE-2020-7598", "CVE-2021-44906", "XRAY-3"}},
+		{Name: "mkdirp", Version: "0.5.1", Ecosystem: "npm", Direct: true},
+	}, sortDependencies(dependencies))
+}
+
+func TestAddDependenciesIssuesTransitive(t *testing.T) {
+	dependencies := make(map[dependencyKey]*dependencyStatus)
+	addTreesDependencies(dependencies, &technologyTrees{technology: coreutils.Go, trees: []*services.GraphNode{
+		{Id: "go://frogbot:1.0.0", Nodes: []*services.GraphNode{{Id: "go://github.com/gin-gonic/gin:v1.7.0", Nodes: []*services.GraphNode{{Id: "go://golang.org/x/net:v0.0.1"}}}}},
+	}})
+	// An issue of another ecosystem with the same name and version doesn't match
+	addDependenciesIssues(dependencies, []formats.VulnerabilityOrViolationRow{
+		{Severity: "Unknown", ImpactedDependencyName: "golang.org/x/net", ImpactedDependencyVersion: "v0.0.1", Technology: coreutils.Go, IssueId: "XRAY-1"},
+		{Severity: "High", ImpactedDependencyName: "golang.org/x/net", ImpactedDependencyVersion: "v0.0.1", Technology: coreutils.Npm, IssueId: "XRAY-2"},
+	})
+	sorted := sortDependencies(dependencies)
+	if assert.Len(t, sorted, 3) {
+		assert.Equal(t, dependencyStatus{Name: "golang.org/x/net", Version: "v0.0.1", Ecosystem: "go", Vulnerable: true, Severity: "Unknown", Issues: []string{"XRAY-1"}}, sorted[1])
+		assert.Equal(t, "npm", sorted[2].Ecosystem)
+	}
+}
+
+func TestFormatDependencies(t *testing.T) {
+	dependencies := []dependencyStatus{
+		{Name: "lodash", Version: "4.17.15", Ecosystem: "npm", Direct: true, Vulnerable: true, Severity: "High", Issues: []string{"CVE-2020-8203", "CVE-2021-23337"}},
+		{Name: "minimist", Version: "1.2.8", Ecosystem: "npm"},
+	}
+	output, err := formatDependencies(dependencies, "")
+	assert.NoError(t, err)
+	assert.Equal(t, ""+
+		"NAME      VERSION  ECOSYSTEM  DIRECT  STATUS      SEVERITY  ISSUES\n"+
+		"lodash    4.17.15  npm        true    vulnerable  High      CVE-2020-8203 CVE-2021-23337\n"+
+		"minimist  1.2.8    npm        false   clean", output)
+
+	output, err = formatDependencies(dependencies, CsvFormat)
+	assert.NoError(t, err)
+	assert.Equal(t, ""+
+		"name,version,ecosystem,direct,status,severity,issues\n"+
+		"lodash,4.17.15,npm,true,vulnerable,High,CVE-2020-8203 CVE-2021-23337\n"+
+		"minimist,1.2.8,npm,false,clean,,", output)
+
+	output, err = formatDependencies(dependencies, JsonFormat)
+	assert.NoError(t, err)
+	var parsed []dependencyStatus
+	assert.NoError(t, json.Unmarshal([]byte(output), &parsed))
+	assert.Equal(t, dependencies, parsed)
+
+	output, err = formatDependencies(sortDependencies(nil), JsonFormat)
+	assert.NoError(t, err)
+	assert.Equal(t, "[]", output)
+}
+
+func TestListDependenciesUnsupportedFormat(t *testing.T) {
+	cmd := ListDependenciesCmd{Format: MarkdownFormat}
+	err := cmd.Run(utils.FrogbotConfigAggregator{{}}, nil)
+	assert.EqualError(t, err, "the output format 'markdown' is not supported. The supported formats are: table, json and csv")
+}
+
+func TestListDependenciesLockReportOnlyWithoutFile(t *testing.T) {
+	cmd := ListDependenciesCmd{LockReportOnly: true}
+	assert.EqualError(t, cmd.Run(utils.FrogbotConfigAggregator{{}}, nil), "the --lock-report-only option requires the --lock-report option")
+}
+
+func TestFormatLockReport(t *testing.T) {
+	goTree := &technologyTrees{technology: coreutils.Go, trees: []*services.GraphNode{
+		{Id: "go://github.com/jfrog/frogbot:1.0.0", Nodes: []*services.GraphNode{{Id: "go://golang.org/x/net:v0.7.0"}, {Id: "go://github.com/xanzy/go-gitlab:v0.52.2"}}},
+	}}
+	// The report is created from the resolved trees of all the projects, without scanning them
+	report := formatLockReport(getResolvedDependencies([]projectDependencyTrees{
+		{project: &utils.Project{}, techTrees: []*technologyTrees{createListDependenciesTestTree()}},
+		{project: &utils.Project{}, techTrees: []*technologyTrees{goTree}},
+	}))
+	assert.Equal(t, lockReportHeader+`
+
+[go]
+github.com/xanzy/go-gitlab@v0.52.2
+golang.org/x/net@v0.7.0
+
+[npm]
+lodash@4.17.15
+minimist@0.0.8
+mkdirp@0.5.1
+`, report)
+
+	// An empty dependencies list has the header only
+	assert.Equal(t, lockReportHeader+"\n", formatLockReport(nil))
+}
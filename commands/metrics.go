@@ -0,0 +1,181 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	metricsDurationName  = "frogbot_run_duration_seconds"
+	metricsPassedName    = "frogbot_run_passed"
+	metricsTimestampName = "frogbot_run_timestamp_seconds"
+	metricsIssuesName    = "frogbot_issues"
+	metricsSecretsName   = "frogbot_secrets"
+)
+
+// The metrics of the current run, or nil if the --metrics-file flag isn't set
+var scanMetrics *runMetrics
+
+func setMetricsFile(path string) {
+	scanMetrics = nil
+	if path != "" {
+		scanMetrics = &runMetrics{path: path, repositories: make(map[string]*repositoryMetrics)}
+	}
+}
+
+// The issues found in the scans of a single repository
+type repositoryMetrics struct {
+	issues  severityBreakdown
+	secrets int
+}
+
+// runMetrics collects the outcome of a Frogbot run, which is written to a file in the Prometheus textfile collector format when the run ends.
+// The repositories may be scanned in parallel, so the results are added under a lock.
+// A nil runMetrics, which is used if the metrics file isn't configured, ignores the results.
+type runMetrics struct {
+	mutex        sync.Mutex
+	path         string
+	repositories map[string]*repositoryMetrics
+}
+
+// Add the issues found in a scan of the repository. The issues of multiple scans of the same repository, such as of several pull requests, are added up.
+func (metrics *runMetrics) addScanResults(repository string, results *auditResults) {
+	if metrics == nil {
+		return
+	}
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+	repositoryResults, exists := metrics.repositories[repository]
+	if !exists {
+		repositoryResults = &repositoryMetrics{}
+		metrics.repositories[repository] = repositoryResults
+	}
+	for _, row := range results.vulnerabilitiesRows {
+		repositoryResults.issues.addIssue(row.Severity)
+	}
+	for _, row := range results.iacRows {
+		repositoryResults.issues.addIssue(row.Severity)
+	}
+	repositoryResults.secrets += len(results.secrets)
+}
+
+// The labels of the run metrics. The repository label is set only if a single repository is configured.
+type metricsLabels struct {
+	command    string
+	provider   string
+	repository string
+}
+
+func (labels metricsLabels) format(extraLabels ...string) string {
+	pairs := []string{"command", labels.command, "provider", labels.provider, "repository", labels.repository}
+	pairs = append(pairs, extraLabels...)
+	var formatted []string
+	for index := 0; index+1 < len(pairs); index += 2 {
+		if pairs[index+1] != "" {
+			formatted = append(formatted, fmt.Sprintf("%s=\"%s\"", pairs[index], escapeMetricsLabel(pairs[index+1])))
+		}
+	}
+	return "{" + strings.Join(formatted, ",") + "}"
+}
+
+func escapeMetricsLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// Return the provider as set in the JF_GIT_PROVIDER environment variable
+func getProviderLabel(provider vcsutils.VcsProvider) string {
+	switch provider {
+	case vcsutils.GitHub:
+		return string(utils.GitHub)
+	case vcsutils.GitLab:
+		return string(utils.GitLab)
+	case vcsutils.BitbucketServer:
+		return string(utils.BitbucketServer)
+	case vcsutils.AzureRepos:
+		return string(utils.AzureRepos)
+	default:
+		return ""
+	}
+}
+
+// Create the content of the metrics file. The run passed if it neither failed nor found issues which fail the Frogbot task.
+func (metrics *runMetrics) createContent(labels metricsLabels, duration time.Duration, runErr error, now time.Time) string {
+	passed := 0
+	if runErr == nil {
+		passed = 1
+	}
+	var content strings.Builder
+	writeMetric := func(name, help string) {
+		content.WriteString(fmt.Sprintf("# HELP %s %s\n# TYPE %s gauge\n", name, help, name))
+	}
+	writeMetric(metricsDurationName, "The duration of the Frogbot run.")
+	content.WriteString(fmt.Sprintf("%s%s %g\n", metricsDurationName, labels.format(), duration.Seconds()))
+	writeMetric(metricsPassedName, "1 if the Frogbot run passed, and 0 if it failed due to an error or due to the security issues found. The result label is passed, issues_found or error.")
+	content.WriteString(fmt.Sprintf("%s%s %d\n", metricsPassedName, labels.format("result", getRunResult(runErr)), passed))
+	writeMetric(metricsTimestampName, "The time the Frogbot run ended, in seconds since the Unix epoch.")
+	content.WriteString(fmt.Sprintf("%s%s %d\n", metricsTimestampName, labels.format(), now.Unix()))
+
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+	repositories := make([]string, 0, len(metrics.repositories))
+	for repository := range metrics.repositories {
+		repositories = append(repositories, repository)
+	}
+	sort.Strings(repositories)
+	if len(repositories) == 0 {
+		return content.String()
+	}
+	writeMetric(metricsIssuesName, "The number of issues found by the Frogbot run, by repository and severity.")
+	for _, repository := range repositories {
+		issues := metrics.repositories[repository].issues
+		repositoryLabels := metricsLabels{command: labels.command, provider: labels.provider, repository: repository}
+		for index, severity := range []string{"critical", "high", "medium", "low", "unknown"} {
+			content.WriteString(fmt.Sprintf("%s%s %d\n", metricsIssuesName, repositoryLabels.format("severity", severity), issues.counts()[index]))
+		}
+	}
+	writeMetric(metricsSecretsName, "The number of secrets found by the Frogbot run, by repository.")
+	for _, repository := range repositories {
+		repositoryLabels := metricsLabels{command: labels.command, provider: labels.provider, repository: repository}
+		content.WriteString(fmt.Sprintf("%s%s %d\n", metricsSecretsName, repositoryLabels.format(), metrics.repositories[repository].secrets))
+	}
+	return content.String()
+}
+
+func getRunResult(runErr error) string {
+	var securityIssuesFoundErr *SecurityIssuesFoundError
+	switch {
+	case runErr == nil:
+		return "passed"
+	case errors.As(runErr, &securityIssuesFoundErr):
+		return "issues_found"
+	default:
+		return "error"
+	}
+}
+
+// Write the metrics file. The file is written to a temp file and renamed, so that the textfile collector never reads a partially written file.
+func (metrics *runMetrics) write(labels metricsLabels, duration time.Duration, runErr error) error {
+	if metrics == nil {
+		return nil
+	}
+	content := metrics.createContent(labels, duration, runErr, time.Now())
+	tempFile := metrics.path + ".tmp"
+	if err := os.WriteFile(tempFile, []byte(content), 0644); err != nil {
+		return fmt.Errorf("couldn't write the metrics file %s: %s", metrics.path, err.Error())
+	}
+	if err := os.Rename(tempFile, metrics.path); err != nil {
+		return fmt.Errorf("couldn't write the metrics file %s: %s", metrics.path, err.Error())
+	}
+	log.Info("The run metrics were written to", filepath.Clean(metrics.path))
+	return nil
+}
@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsContent(t *testing.T) {
+	defer setMetricsFile("")
+	setMetricsFile(filepath.Join(t.TempDir(), "frogbot.prom"))
+	scanMetrics.addScanResults("frogbot", &auditResults{
+		vulnerabilitiesRows: []formats.VulnerabilityOrViolationRow{{Severity: "Critical"}, {Severity: "High"}},
+		iacRows:             []utils.IacRow{{Severity: "High"}},
+		secrets:             []secretRow{{}},
+	})
+	// The results of several scans of the same repository are added up
+	scanMetrics.addScanResults("frogbot", &auditResults{vulnerabilitiesRows: []formats.VulnerabilityOrViolationRow{{Severity: "Low"}}})
+
+	labels := metricsLabels{command: "scan-pull-request", provider: "github", repository: "frogbot"}
+	content := scanMetrics.createContent(labels, 1500*time.Millisecond, &SecurityIssuesFoundError{}, time.Unix(1700000000, 0))
+	expected := `# HELP frogbot_run_duration_seconds The duration of the Frogbot run.
+# TYPE frogbot_run_duration_seconds gauge
+frogbot_run_duration_seconds{command="scan-pull-request",provider="github",repository="frogbot"} 1.5
+# HELP frogbot_run_passed 1 if the Frogbot run passed, and 0 if it failed due to an error or due to the security issues found. The result label is passed, issues_found or error.
+# TYPE frogbot_run_passed gauge
+frogbot_run_passed{command="scan-pull-request",provider="github",repository="frogbot",result="issues_found"} 0
+# HELP frogbot_run_timestamp_seconds The time the Frogbot run ended, in seconds since the Unix epoch.
+# TYPE frogbot_run_timestamp_seconds gauge
+frogbot_run_timestamp_seconds{command="scan-pull-request",provider="github",repository="frogbot"} 1700000000
+# HELP frogbot_issues The number of issues found by the Frogbot run, by repository and severity.
+# TYPE frogbot_issues gauge
+frogbot_issues{command="scan-pull-request",provider="github",repository="frogbot",severity="critical"} 1
+frogbot_issues{command="scan-pull-request",provider="github",repository="frogbot",severity="high"} 2
+frogbot_issues{command="scan-pull-request",provider="github",repository="frogbot",severity="medium"} 0
+frogbot_issues{command="scan-pull-request",provider="github",repository="frogbot",severity="low"} 1
+frogbot_issues{command="scan-pull-request",provider="github",repository="frogbot",severity="unknown"} 0
+# HELP frogbot_secrets The number of secrets found by the Frogbot run, by repository.
+# TYPE frogbot_secrets gauge
+frogbot_secrets{command="scan-pull-request",provider="github",repository="frogbot"} 1
+`
+	assert.Equal(t, expected, content)
+}
+
+func TestMetricsRunResult(t *testing.T) {
+	assert.Equal(t, "passed", getRunResult(nil))
+	assert.Equal(t, "issues_found", getRunResult(&SecurityIssuesFoundError{}))
+	assert.Equal(t, "error", getRunResult(&ScanExecutionError{Err: errors.New("xray is unavailable")}))
+	// Empty labels are omitted, and the label values are escaped
+	assert.Equal(t, `{command="scan-local-directory",repository="my \"repo\""}`, metricsLabels{command: "scan-local-directory", repository: `my "repo"`}.format())
+}
+
+func TestWriteMetricsFile(t *testing.T) {
+	defer setMetricsFile("")
+	// Without the metrics file, the results are ignored
+	setMetricsFile("")
+	scanMetrics.addScanResults("frogbot", &auditResults{})
+	assert.NoError(t, scanMetrics.write(metricsLabels{}, time.Second, nil))
+
+	metricsFile := filepath.Join(t.TempDir(), "frogbot.prom")
+	setMetricsFile(metricsFile)
+	assert.NoError(t, scanMetrics.write(metricsLabels{command: "scan-pull-requests", provider: getProviderLabel(vcsutils.BitbucketServer)}, time.Second, nil))
+	content, err := os.ReadFile(metricsFile)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), `frogbot_run_passed{command="scan-pull-requests",provider="bitbucketServer",result="passed"} 1`)
+	assert.NotContains(t, string(content), "frogbot_issues")
+	assert.NoFileExists(t, metricsFile+".tmp")
+}
+
+func TestGetMetricsLabels(t *testing.T) {
+	configAggregator := utils.FrogbotConfigAggregator{{Params: utils.Params{Git: utils.Git{GitProvider: vcsutils.GitLab, RepoName: "frogbot"}}}}
+	assert.Equal(t, metricsLabels{command: "scan-local-directory", repository: "frogbot"}, getMetricsLabels("scan-local-directory", configAggregator, nil))
+	assert.Equal(t, metricsLabels{command: "scan-pull-request", provider: "gitlab", repository: "frogbot"}, getMetricsLabels("scan-pull-request", configAggregator, mockVcsClient(t)))
+	configAggregator = append(configAggregator, utils.FrogbotRepoConfig{})
+	assert.Equal(t, metricsLabels{command: "scan-and-fix-repos", provider: "gitlab"}, getMetricsLabels("scan-and-fix-repos", configAggregator, mockVcsClient(t)))
+}
@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	npmrcFileName     = ".npmrc"
+	npmAuthTokenKey   = ":_authToken"
+	npmBasicAuthKey   = ":_auth"
+	npmScopeRegistry  = ":registry"
+	npmRegistryConfig = "registry"
+)
+
+// npmConfig is the registry configuration of npm, taken from the user and the project .npmrc files and from the npm_config_registry environment variable,
+// with the same precedence as npm.
+type npmConfig struct {
+	registry string
+	// The registries of the scoped packages, by the scopes, such as @jfrog
+	scopeRegistries map[string]string
+	// The auth settings, by their keys without the setting name, such as //registry.example.com/api/npm/npm/
+	authTokens map[string]string
+	basicAuths map[string]string
+}
+
+func loadNpmConfig(projectDir string) *npmConfig {
+	config := &npmConfig{registry: defaultNpmRegistryUrl, scopeRegistries: make(map[string]string), authTokens: make(map[string]string), basicAuths: make(map[string]string)}
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		config.readNpmrc(filepath.Join(homeDir, npmrcFileName))
+	}
+	if projectDir != "" {
+		config.readNpmrc(filepath.Join(projectDir, npmrcFileName))
+	}
+	registryUrl := os.Getenv(npmConfigRegistryEnv)
+	if registryUrl == "" {
+		registryUrl = os.Getenv(npmConfigRegistryLower)
+	}
+	if registryUrl != "" {
+		config.registry = registryUrl
+	}
+	config.registry = strings.TrimSuffix(config.registry, "/") + "/"
+	return config
+}
+
+// Read the settings of a .npmrc file into the config. A missing file is skipped.
+func (nc *npmConfig) readNpmrc(path string) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Debug("couldn't read", path+":", err.Error())
+		}
+		return
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		// npm expands the ${VAR} references to environment variables
+		value = os.Expand(strings.Trim(strings.TrimSpace(value), `"'`), os.Getenv)
+		switch {
+		case key == npmRegistryConfig:
+			nc.registry = value
+		case strings.HasPrefix(key, "@") && strings.HasSuffix(key, npmScopeRegistry):
+			nc.scopeRegistries[strings.TrimSuffix(key, npmScopeRegistry)] = strings.TrimSuffix(value, "/") + "/"
+		case strings.HasPrefix(key, "//") && strings.HasSuffix(key, npmAuthTokenKey):
+			nc.authTokens[strings.TrimSuffix(key, npmAuthTokenKey)] = value
+		case strings.HasPrefix(key, "//") && strings.HasSuffix(key, npmBasicAuthKey):
+			nc.basicAuths[strings.TrimSuffix(key, npmBasicAuthKey)] = value
+		}
+	}
+}
+
+// Return the registry of the package, which is the registry of its scope if one is configured
+func (nc *npmConfig) getRegistryUrl(packageName string) string {
+	if strings.HasPrefix(packageName, "@") {
+		if scope, _, found := strings.Cut(packageName, "/"); found {
+			if registryUrl, exists := nc.scopeRegistries[scope]; exists {
+				return registryUrl
+			}
+		}
+	}
+	return nc.registry
+}
+
+// Return the value of the Authorization header of the registry, or an empty string if no credentials are configured for it.
+// Like npm, the credentials whose key is the longest prefix of the registry URL without its scheme are used.
+func (nc *npmConfig) getAuthorization(registryUrl string) string {
+	parsedUrl, err := url.Parse(registryUrl)
+	if err != nil {
+		return ""
+	}
+	nerfDart := "//" + parsedUrl.Host + strings.TrimSuffix(parsedUrl.Path, "/") + "/"
+	authorization, matchLength := "", 0
+	matchCredentials := func(credentials map[string]string, scheme string) {
+		for key, value := range credentials {
+			prefix := strings.TrimSuffix(key, "/") + "/"
+			if value != "" && strings.HasPrefix(nerfDart, prefix) && len(prefix) > matchLength {
+				authorization, matchLength = scheme+" "+value, len(prefix)
+			}
+		}
+	}
+	// A token takes precedence over basic auth of the same registry
+	matchCredentials(nc.authTokens, "Bearer")
+	matchCredentials(nc.basicAuths, "Basic")
+	return authorization
+}
@@ -0,0 +1,252 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	// The summary marker is a hidden markdown comment, which identifies the issue of the summary report
+	orgSummaryMarker       = "[//]: # (frogbot-org-summary)"
+	orgSummaryIssueTitle   = "Frogbot scan results summary"
+	orgSummaryTitle        = "## 🐸 Frogbot scan results summary\n\nFound %d issues in %d scanned branches of %d repositories."
+	orgSummaryMarkdownFile = "frogbot-summary.md"
+	orgSummaryJsonFile     = "frogbot-summary.json"
+	orgSummaryTableHeader  = "\n\n| REPOSITORY | BRANCH | CRITICAL | HIGH | MEDIUM | LOW | UNKNOWN | TOTAL |\n" + "-- | -- | :--: | :--: | :--: | :--: | :--: | :--:"
+	orgSummaryTableRow     = "\n| %s | %s | %d | %d | %d | %d | %d | %d |"
+	orgSummaryTotalRow     = "\n| **Total** | | **%d** | **%d** | **%d** | **%d** | **%d** | **%d** |"
+	orgSummaryFailedTitle  = "\n\n#### ❌ Failed repositories\n\n"
+)
+
+// The number of issues of each severity
+type severityBreakdown struct {
+	Critical int `json:"critical"`
+	High     int `json:"high"`
+	Medium   int `json:"medium"`
+	Low      int `json:"low"`
+	Unknown  int `json:"unknown"`
+	Total    int `json:"total"`
+}
+
+func (breakdown *severityBreakdown) addIssue(severity string) {
+	switch strings.ToLower(severity) {
+	case "critical":
+		breakdown.Critical++
+	case "high":
+		breakdown.High++
+	case "medium":
+		breakdown.Medium++
+	case "low":
+		breakdown.Low++
+	default:
+		breakdown.Unknown++
+	}
+	breakdown.Total++
+}
+
+func (breakdown *severityBreakdown) addBreakdown(other severityBreakdown) {
+	breakdown.Critical += other.Critical
+	breakdown.High += other.High
+	breakdown.Medium += other.Medium
+	breakdown.Low += other.Low
+	breakdown.Unknown += other.Unknown
+	breakdown.Total += other.Total
+}
+
+// Return the counts from the most severe, by which the branches are sorted
+func (breakdown *severityBreakdown) counts() []int {
+	return []int{breakdown.Critical, breakdown.High, breakdown.Medium, breakdown.Low, breakdown.Unknown}
+}
+
+// The issues found in a single scanned branch
+type orgSummaryBranch struct {
+	Repository string            `json:"repository"`
+	Branch     string            `json:"branch"`
+	Issues     severityBreakdown `json:"issues"`
+}
+
+type orgSummaryFailure struct {
+	Repository string `json:"repository"`
+	Error      string `json:"error"`
+}
+
+// The summary report of all the scanned repositories, written in the JSON file
+type orgSummaryReport struct {
+	// The scanned branches, sorted by their issues from the most severe
+	Branches []orgSummaryBranch `json:"branches"`
+	Total    severityBreakdown  `json:"total"`
+	// The repositories which failed, and their scan results may be partial or missing
+	FailedRepositories []orgSummaryFailure `json:"failedRepositories,omitempty"`
+}
+
+// orgSummary collects the scan results of all the repositories in the config aggregator.
+// The repositories may be scanned in parallel, so the results are added under a lock.
+// A nil summary, which is used if the summary isn't configured, ignores the results.
+type orgSummary struct {
+	mutex    sync.Mutex
+	branches []orgSummaryBranch
+	failures []orgSummaryFailure
+	// The directory the summary files are written to
+	dir string
+	// The repository, in which an issue with the summary is created or updated
+	issueRepo string
+	git       utils.Git
+}
+
+// Return the summary of the config aggregator, or nil if no summary is configured.
+// The summary params of the first repository are used.
+func newOrgSummary(configAggregator utils.FrogbotConfigAggregator) *orgSummary {
+	if len(configAggregator) == 0 {
+		return nil
+	}
+	params := &configAggregator[0].Params
+	if params.OrgSummaryDir == "" && params.OrgSummaryIssueRepo == "" {
+		return nil
+	}
+	return &orgSummary{dir: params.OrgSummaryDir, issueRepo: params.OrgSummaryIssueRepo, git: params.Git}
+}
+
+func (summary *orgSummary) addBranchResults(repository, branch string, vulnerabilitiesRows []formats.VulnerabilityOrViolationRow) {
+	if summary == nil {
+		return
+	}
+	branchSummary := orgSummaryBranch{Repository: repository, Branch: branch}
+	for _, row := range vulnerabilitiesRows {
+		branchSummary.Issues.addIssue(row.Severity)
+	}
+	summary.mutex.Lock()
+	defer summary.mutex.Unlock()
+	summary.branches = append(summary.branches, branchSummary)
+}
+
+func (summary *orgSummary) addRepositoryError(repository string, err error) {
+	if summary == nil || err == nil {
+		return
+	}
+	summary.mutex.Lock()
+	defer summary.mutex.Unlock()
+	summary.failures = append(summary.failures, orgSummaryFailure{Repository: repository, Error: err.Error()})
+}
+
+func (summary *orgSummary) createReport() *orgSummaryReport {
+	report := &orgSummaryReport{
+		Branches:           append([]orgSummaryBranch{}, summary.branches...),
+		FailedRepositories: append([]orgSummaryFailure{}, summary.failures...),
+	}
+	sort.SliceStable(report.Branches, func(i, j int) bool {
+		first, second := report.Branches[i], report.Branches[j]
+		firstCounts, secondCounts := first.Issues.counts(), second.Issues.counts()
+		for index := range firstCounts {
+			if firstCounts[index] != secondCounts[index] {
+				return firstCounts[index] > secondCounts[index]
+			}
+		}
+		if first.Repository != second.Repository {
+			return first.Repository < second.Repository
+		}
+		return first.Branch < second.Branch
+	})
+	sort.SliceStable(report.FailedRepositories, func(i, j int) bool {
+		return report.FailedRepositories[i].Repository < report.FailedRepositories[j].Repository
+	})
+	for _, branch := range report.Branches {
+		report.Total.addBreakdown(branch.Issues)
+	}
+	return report
+}
+
+func (report *orgSummaryReport) repositoriesCount() int {
+	repositories := make(map[string]bool)
+	for _, branch := range report.Branches {
+		repositories[branch.Repository] = true
+	}
+	return len(repositories)
+}
+
+// Create the markdown of the summary, with a table of the scanned branches sorted by their issues from the most severe
+func (report *orgSummaryReport) toMarkdown() string {
+	var markdown strings.Builder
+	markdown.WriteString(fmt.Sprintf(orgSummaryTitle, report.Total.Total, len(report.Branches), report.repositoriesCount()))
+	if len(report.Branches) > 0 {
+		markdown.WriteString(orgSummaryTableHeader)
+		for _, branch := range report.Branches {
+			issues := branch.Issues
+			markdown.WriteString(fmt.Sprintf(orgSummaryTableRow, branch.Repository, branch.Branch, issues.Critical, issues.High, issues.Medium, issues.Low, issues.Unknown, issues.Total))
+		}
+		total := report.Total
+		markdown.WriteString(fmt.Sprintf(orgSummaryTotalRow, total.Critical, total.High, total.Medium, total.Low, total.Unknown, total.Total))
+	}
+	if len(report.FailedRepositories) > 0 {
+		markdown.WriteString(orgSummaryFailedTitle)
+		for _, failure := range report.FailedRepositories {
+			// Only the first line of the error is shown, to keep the summary readable
+			errorLine, _, _ := strings.Cut(failure.Error, "\n")
+			markdown.WriteString(fmt.Sprintf("- **%s**: %s\n", failure.Repository, errorLine))
+		}
+	}
+	return markdown.String()
+}
+
+// Write the summary files and publish the summary issue, as configured
+func (summary *orgSummary) publish() error {
+	if summary == nil {
+		return nil
+	}
+	report := summary.createReport()
+	markdown := report.toMarkdown()
+	var errList []string
+	if summary.dir != "" {
+		if err := writeOrgSummaryFiles(summary.dir, report, markdown); err != nil {
+			errList = append(errList, "couldn't write the summary report: "+err.Error())
+		}
+	}
+	if summary.issueRepo != "" {
+		if err := summary.publishIssue(markdown); err != nil {
+			errList = append(errList, "couldn't publish the summary report: "+err.Error())
+		}
+	}
+	if len(errList) > 0 {
+		return errors.New(strings.Join(errList, "\n"))
+	}
+	return nil
+}
+
+func writeOrgSummaryFiles(dir string, report *orgSummaryReport, markdown string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(filepath.Join(dir, orgSummaryJsonFile), content, 0644); err != nil {
+		return err
+	}
+	if err = os.WriteFile(filepath.Join(dir, orgSummaryMarkdownFile), []byte(markdown), 0644); err != nil {
+		return err
+	}
+	log.Info("The summary report was written to", dir)
+	return nil
+}
+
+// The summary issue is created in a repository of the owner of the first repository, using its Git params
+func (summary *orgSummary) publishIssue(markdown string) error {
+	if summary.git.GitProvider != vcsutils.GitHub {
+		log.Warn("Publishing the summary report to an issue is supported only on GitHub. Skipping the summary issue")
+		return nil
+	}
+	git := summary.git
+	git.RepoName = summary.issueRepo
+	return publishIssueReport(&git, orgSummaryIssueTitle, orgSummaryMarker, markdown+"\n\n"+orgSummaryMarker)
+}
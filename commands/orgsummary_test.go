@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestOrgSummary() *orgSummary {
+	summary := &orgSummary{}
+	summary.addBranchResults("npm-repo", "master", []formats.VulnerabilityOrViolationRow{{Severity: "High"}, {Severity: "Low"}, {Severity: "Low"}})
+	summary.addBranchResults("pip-repo", "master", []formats.VulnerabilityOrViolationRow{{Severity: "Critical"}, {Severity: "Unknown"}})
+	summary.addBranchResults("mvn-repo", "dev", []formats.VulnerabilityOrViolationRow{{Severity: "High"}, {Severity: "Medium"}})
+	summary.addBranchResults("go-repo", "master", nil)
+	summary.addRepositoryError("docs-repo", errors.New("couldn't download the repository\nstatus 404"))
+	summary.addRepositoryError("go-repo", nil)
+	return summary
+}
+
+func TestOrgSummaryCreateReport(t *testing.T) {
+	report := createTestOrgSummary().createReport()
+	// The branches are sorted by their issues, from the most severe
+	var repositories []string
+	for _, branch := range report.Branches {
+		repositories = append(repositories, branch.Repository)
+	}
+	assert.Equal(t, []string{"pip-repo", "mvn-repo", "npm-repo", "go-repo"}, repositories)
+	assert.Equal(t, severityBreakdown{High: 1, Low: 2, Total: 3}, report.Branches[2].Issues)
+	assert.Equal(t, severityBreakdown{Critical: 1, High: 2, Medium: 1, Low: 2, Unknown: 1, Total: 7}, report.Total)
+	assert.Equal(t, []orgSummaryFailure{{Repository: "docs-repo", Error: "couldn't download the repository\nstatus 404"}}, report.FailedRepositories)
+	assert.Equal(t, 4, report.repositoriesCount())
+}
+
+func TestOrgSummaryToMarkdown(t *testing.T) {
+	markdown := createTestOrgSummary().createReport().toMarkdown()
+	assert.Contains(t, markdown, "Found 7 issues in 4 scanned branches of 4 repositories.")
+	assert.Contains(t, markdown, orgSummaryTableHeader+"\n| pip-repo | master | 1 | 0 | 0 | 0 | 1 | 2 |\n| mvn-repo | dev | 0 | 1 | 1 | 0 | 0 | 2 |")
+	assert.Contains(t, markdown, "\n| **Total** | | **1** | **2** | **1** | **2** | **1** | **7** |")
+	// Only the first line of the error is shown
+	assert.Contains(t, markdown, orgSummaryFailedTitle+"- **docs-repo**: couldn't download the repository\n")
+}
+
+func TestOrgSummaryWriteFiles(t *testing.T) {
+	summary := createTestOrgSummary()
+	summary.dir = filepath.Join(t.TempDir(), "reports")
+	require.NoError(t, summary.publish())
+
+	content, err := os.ReadFile(filepath.Join(summary.dir, orgSummaryJsonFile))
+	require.NoError(t, err)
+	var report orgSummaryReport
+	require.NoError(t, json.Unmarshal(content, &report))
+	assert.Equal(t, summary.createReport(), &report)
+
+	markdown, err := os.ReadFile(filepath.Join(summary.dir, orgSummaryMarkdownFile))
+	require.NoError(t, err)
+	assert.Equal(t, report.toMarkdown(), string(markdown))
+}
+
+func TestOrgSummaryPublishIssue(t *testing.T) {
+	var requestedIssue map[string]string
+	var requestedPath string
+	server := createIssuesServer(t, `[]`, &requestedIssue, &requestedPath)
+	defer server.Close()
+
+	summary := createTestOrgSummary()
+	summary.issueRepo = "frogbot"
+	summary.git = utils.Git{GitProvider: vcsutils.GitHub, RepoOwner: "jfrog", RepoName: "npm-repo", Token: "123456", ApiEndpoint: server.URL}
+	require.NoError(t, summary.publish())
+	assert.Equal(t, "POST /repos/jfrog/frogbot/issues", requestedPath)
+	assert.Equal(t, orgSummaryIssueTitle, requestedIssue["title"])
+	assert.Contains(t, requestedIssue["body"], "| pip-repo | master |")
+	assert.Contains(t, requestedIssue["body"], orgSummaryMarker)
+}
+
+func TestNewOrgSummary(t *testing.T) {
+	configAggregator := utils.FrogbotConfigAggregator{{}}
+	assert.Nil(t, newOrgSummary(configAggregator))
+	assert.Nil(t, newOrgSummary(nil))
+	configAggregator[0].OrgSummaryDir = "reports"
+	assert.Equal(t, "reports", newOrgSummary(configAggregator).dir)
+
+	// A nil summary ignores the results
+	var summary *orgSummary
+	summary.addBranchResults("npm-repo", "master", []formats.VulnerabilityOrViolationRow{{Severity: "High"}})
+	summary.addRepositoryError("npm-repo", errors.New("failed"))
+	assert.NoError(t, summary.publish())
+}
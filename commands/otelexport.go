@@ -0,0 +1,275 @@
+package commands
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	otelTracesPath       = "/v1/traces"
+	otelExportTimeout    = 10 * time.Second
+	otelScopeName        = "github.com/jfrog/frogbot"
+	otelSpanKindInternal = 1
+	otelStatusOk         = 1
+	otelStatusError      = 2
+
+	// The types of the findings
+	vulnerabilityFindingType    = "vulnerability"
+	violationFindingType        = "violation"
+	misconfigurationFindingType = "misconfiguration"
+	secretFindingType           = "secret"
+)
+
+// The traces of the OTLP/HTTP protocol, in its JSON encoding, in which the trace and span IDs are hex encoded and the 64-bit integers are strings
+type otlpTraces struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceId           string          `json:"traceId"`
+	SpanId            string          `json:"spanId"`
+	ParentSpanId      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes"`
+	Status            *otlpStatus     `json:"status,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string         `json:"stringValue,omitempty"`
+	BoolValue   *bool           `json:"boolValue,omitempty"`
+	IntValue    string          `json:"intValue,omitempty"`
+	ArrayValue  *otlpArrayValue `json:"arrayValue,omitempty"`
+}
+
+type otlpArrayValue struct {
+	Values []otlpAnyValue `json:"values"`
+}
+
+func stringAttribute(key, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpAnyValue{StringValue: &value}}
+}
+
+func boolAttribute(key string, value bool) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpAnyValue{BoolValue: &value}}
+}
+
+func intAttribute(key string, value int) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpAnyValue{IntValue: strconv.Itoa(value)}}
+}
+
+func stringsAttribute(key string, values []string) otlpAttribute {
+	arrayValue := &otlpArrayValue{Values: []otlpAnyValue{}}
+	for index := range values {
+		arrayValue.Values = append(arrayValue.Values, otlpAnyValue{StringValue: &values[index]})
+	}
+	return otlpAttribute{Key: key, Value: otlpAnyValue{ArrayValue: arrayValue}}
+}
+
+// exportOtelTrace exports the pull request scan results to the OpenTelemetry endpoint.
+// The export is an integration point only, so its failure is logged rather than failing the scan.
+func exportOtelTrace(repoConfig *utils.FrogbotRepoConfig, results *auditResults, startTime time.Time) {
+	traces, err := createOtelTraces(repoConfig, results, startTime, time.Now())
+	if err == nil {
+		// The custom headers and the user agent of the Git provider and of Xray aren't sent to the OpenTelemetry endpoint
+		err = postOtelTraces(utils.NewExternalHttpClient(otelExportTimeout), repoConfig.OtelEndpoint, repoConfig.OtelHeaders, traces)
+	}
+	if err != nil {
+		log.Warn("couldn't export the scan results to the OpenTelemetry endpoint:", err.Error())
+		return
+	}
+	log.Info("The scan results were exported to the OpenTelemetry endpoint", repoConfig.OtelEndpoint)
+}
+
+// Create a trace of the scan, with a frogbot.scan span for the pull request scan and a frogbot.finding child span for each issue.
+// The secrets are exported with their file and type only, without their redacted values.
+func createOtelTraces(repoConfig *utils.FrogbotRepoConfig, results *auditResults, startTime, endTime time.Time) (*otlpTraces, error) {
+	traceId, err := newOtelId(16)
+	if err != nil {
+		return nil, err
+	}
+	scanSpanId, err := newOtelId(8)
+	if err != nil {
+		return nil, err
+	}
+	commonAttributes := []otlpAttribute{
+		stringAttribute("vcs.provider", getProviderLabel(repoConfig.GitProvider)),
+		stringAttribute("vcs.repository.owner", repoConfig.RepoOwner),
+		stringAttribute("vcs.repository.name", repoConfig.RepoName),
+		intAttribute("vcs.pull_request.id", repoConfig.PullRequestID),
+	}
+	var breakdown severityBreakdown
+	var findingsSpans []otlpSpan
+	addFinding := func(findingType, severity string, attributes ...otlpAttribute) error {
+		spanId, err := newOtelId(8)
+		if err != nil {
+			return err
+		}
+		findingAttributes := append(append([]otlpAttribute{}, commonAttributes...), stringAttribute("frogbot.finding.type", findingType), stringAttribute("frogbot.finding.severity", severity))
+		findingsSpans = append(findingsSpans, otlpSpan{
+			TraceId:           traceId,
+			SpanId:            spanId,
+			ParentSpanId:      scanSpanId,
+			Name:              "frogbot.finding",
+			Kind:              otelSpanKindInternal,
+			StartTimeUnixNano: formatUnixNano(endTime),
+			EndTimeUnixNano:   formatUnixNano(endTime),
+			Attributes:        append(findingAttributes, attributes...),
+		})
+		return nil
+	}
+	for _, row := range results.vulnerabilitiesRows {
+		breakdown.addIssue(row.Severity)
+		findingType := vulnerabilityFindingType
+		if _, exists := results.violationsPolicies[getUniqueID(row)]; exists {
+			findingType = violationFindingType
+		}
+		var cves []string
+		for _, cve := range row.Cves {
+			if cve.Id != "" {
+				cves = append(cves, cve.Id)
+			}
+		}
+		if err = addFinding(findingType, row.Severity,
+			stringAttribute("frogbot.finding.issue_id", row.IssueId),
+			stringsAttribute("frogbot.finding.cves", cves),
+			stringAttribute("frogbot.finding.dependency.name", row.ImpactedDependencyName),
+			stringAttribute("frogbot.finding.dependency.version", row.ImpactedDependencyVersion),
+			stringsAttribute("frogbot.finding.fixed_versions", row.FixedVersions),
+			stringAttribute("frogbot.finding.ecosystem", row.Technology.ToString())); err != nil {
+			return nil, err
+		}
+	}
+	for _, row := range results.iacRows {
+		breakdown.addIssue(row.Severity)
+		if err = addFinding(misconfigurationFindingType, row.Severity,
+			stringAttribute("frogbot.finding.rule_id", row.RuleId),
+			stringAttribute("frogbot.finding.file", row.File),
+			intAttribute("frogbot.finding.line", row.Line)); err != nil {
+			return nil, err
+		}
+	}
+	for _, secret := range results.secrets {
+		// The secrets have no severity
+		if err = addFinding(secretFindingType, "",
+			stringAttribute("frogbot.finding.secret_type", secret.secretType),
+			stringAttribute("frogbot.finding.file", secret.file),
+			intAttribute("frogbot.finding.line", secret.line)); err != nil {
+			return nil, err
+		}
+	}
+
+	shouldFail := repoConfig.ShouldFail(results.failingIssuesFound)
+	status := &otlpStatus{Code: otelStatusOk}
+	if shouldFail {
+		status = &otlpStatus{Code: otelStatusError, Message: "security issues were found"}
+	}
+	scanSpan := otlpSpan{
+		TraceId:           traceId,
+		SpanId:            scanSpanId,
+		Name:              "frogbot.scan",
+		Kind:              otelSpanKindInternal,
+		StartTimeUnixNano: formatUnixNano(startTime),
+		EndTimeUnixNano:   formatUnixNano(endTime),
+		Attributes: append(append([]otlpAttribute{}, commonAttributes...),
+			boolAttribute("frogbot.scan.failed", shouldFail),
+			intAttribute("frogbot.issues.total", results.issuesCount()),
+			intAttribute("frogbot.issues.critical", breakdown.Critical),
+			intAttribute("frogbot.issues.high", breakdown.High),
+			intAttribute("frogbot.issues.medium", breakdown.Medium),
+			intAttribute("frogbot.issues.low", breakdown.Low),
+			intAttribute("frogbot.issues.unknown", breakdown.Unknown),
+			intAttribute("frogbot.secrets", len(results.secrets))),
+		Status: status,
+	}
+	return &otlpTraces{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: []otlpAttribute{stringAttribute("service.name", "frogbot")}},
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: otelScopeName}, Spans: append([]otlpSpan{scanSpan}, findingsSpans...)}},
+	}}}, nil
+}
+
+// Post the traces to the /v1/traces path of the endpoint, unless the endpoint already ends with it
+func postOtelTraces(client *http.Client, endpoint string, headers map[string]string, traces *otlpTraces) error {
+	content, err := json.Marshal(traces)
+	if err != nil {
+		return err
+	}
+	tracesUrl := strings.TrimSuffix(endpoint, "/")
+	if !strings.HasSuffix(tracesUrl, otelTracesPath) {
+		tracesUrl += otelTracesPath
+	}
+	request, err := http.NewRequest(http.MethodPost, tracesUrl, bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("the endpoint %s returned status %d: %s", tracesUrl, response.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// Create a random trace ID or span ID of the given length in bytes
+func newOtelId(length int) (string, error) {
+	id := make([]byte, length)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+func formatUnixNano(timestamp time.Time) string {
+	return strconv.FormatInt(timestamp.UnixNano(), 10)
+}
@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+func getOtelAttribute(attributes []otlpAttribute, key string) *otlpAnyValue {
+	for index := range attributes {
+		if attributes[index].Key == key {
+			return &attributes[index].Value
+		}
+	}
+	return nil
+}
+
+func TestCreateOtelTraces(t *testing.T) {
+	failOnSecurityIssues := true
+	repoConfig := &utils.FrogbotRepoConfig{Params: utils.Params{
+		Scan: utils.Scan{FailOnSecurityIssues: &failOnSecurityIssues},
+		Git:  utils.Git{GitProvider: vcsutils.GitHub, RepoOwner: "jfrog", RepoName: "frogbot", PullRequestID: 7},
+	}}
+	results := &auditResults{
+		failingIssuesFound: true,
+		vulnerabilitiesRows: []formats.VulnerabilityOrViolationRow{{
+			Severity: "High", ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "4.17.20", IssueId: "XRAY-1",
+			Cves: []formats.CveRow{{Id: "CVE-2021-23337"}}, FixedVersions: []string{"[4.17.21]"}, Technology: coreutils.Npm,
+		}},
+		iacRows: []utils.IacRow{{Severity: "Medium", File: "main.tf", Line: 3, RuleId: "aws_s3_bucket_public"}},
+		secrets: []secretRow{{file: "config.yml", line: 5, secretType: "AWS access key ID", redactedValue: "AKIA****"}},
+	}
+	startTime := time.Unix(1700000000, 0)
+	traces, err := createOtelTraces(repoConfig, results, startTime, startTime.Add(time.Minute))
+	assert.NoError(t, err)
+	if !assert.Len(t, traces.ResourceSpans, 1) || !assert.Len(t, traces.ResourceSpans[0].ScopeSpans, 1) {
+		return
+	}
+	spans := traces.ResourceSpans[0].ScopeSpans[0].Spans
+	if !assert.Len(t, spans, 4) {
+		return
+	}
+
+	scanSpan := spans[0]
+	assert.Equal(t, "frogbot.scan", scanSpan.Name)
+	assert.Len(t, scanSpan.TraceId, 32)
+	assert.Len(t, scanSpan.SpanId, 16)
+	assert.Empty(t, scanSpan.ParentSpanId)
+	assert.Equal(t, "1700000000000000000", scanSpan.StartTimeUnixNano)
+	assert.Equal(t, "1700000060000000000", scanSpan.EndTimeUnixNano)
+	assert.Equal(t, &otlpStatus{Code: otelStatusError, Message: "security issues were found"}, scanSpan.Status)
+	assert.Equal(t, "frogbot", *getOtelAttribute(scanSpan.Attributes, "vcs.repository.name").StringValue)
+	assert.Equal(t, "github", *getOtelAttribute(scanSpan.Attributes, "vcs.provider").StringValue)
+	assert.Equal(t, "7", getOtelAttribute(scanSpan.Attributes, "vcs.pull_request.id").IntValue)
+	assert.Equal(t, "3", getOtelAttribute(scanSpan.Attributes, "frogbot.issues.total").IntValue)
+	assert.Equal(t, "1", getOtelAttribute(scanSpan.Attributes, "frogbot.issues.high").IntValue)
+	assert.Equal(t, "0", getOtelAttribute(scanSpan.Attributes, "frogbot.issues.unknown").IntValue)
+	assert.True(t, *getOtelAttribute(scanSpan.Attributes, "frogbot.scan.failed").BoolValue)
+
+	for _, span := range spans[1:] {
+		assert.Equal(t, "frogbot.finding", span.Name)
+		assert.Equal(t, scanSpan.TraceId, span.TraceId)
+		assert.Equal(t, scanSpan.SpanId, span.ParentSpanId)
+		assert.Equal(t, "jfrog", *getOtelAttribute(span.Attributes, "vcs.repository.owner").StringValue)
+	}
+	vulnerabilitySpan := spans[1]
+	assert.Equal(t, vulnerabilityFindingType, *getOtelAttribute(vulnerabilitySpan.Attributes, "frogbot.finding.type").StringValue)
+	assert.Equal(t, "High", *getOtelAttribute(vulnerabilitySpan.Attributes, "frogbot.finding.severity").StringValue)
+	assert.Equal(t, "CVE-2021-23337", *getOtelAttribute(vulnerabilitySpan.Attributes, "frogbot.finding.cves").ArrayValue.Values[0].StringValue)
+	assert.Equal(t, "npm", *getOtelAttribute(vulnerabilitySpan.Attributes, "frogbot.finding.ecosystem").StringValue)
+	assert.Equal(t, misconfigurationFindingType, *getOtelAttribute(spans[2].Attributes, "frogbot.finding.type").StringValue)
+	assert.Equal(t, "3", getOtelAttribute(spans[2].Attributes, "frogbot.finding.line").IntValue)
+
+	// The values of the secrets aren't exported
+	secretSpan := spans[3]
+	assert.Equal(t, secretFindingType, *getOtelAttribute(secretSpan.Attributes, "frogbot.finding.type").StringValue)
+	content, err := json.Marshal(secretSpan)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "AKIA")
+}
+
+func TestPostOtelTraces(t *testing.T) {
+	var receivedPath, receivedContentType, receivedApiKey string
+	var received otlpTraces
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.Path
+		receivedContentType = r.Header.Get("Content-Type")
+		receivedApiKey = r.Header.Get("Api-Key")
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(body, &received))
+		if r.URL.Path == "/unavailable"+otelTracesPath {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	traces := &otlpTraces{ResourceSpans: []otlpResourceSpans{{Resource: otlpResource{Attributes: []otlpAttribute{stringAttribute("service.name", "frogbot")}}}}}
+
+	assert.NoError(t, postOtelTraces(server.Client(), server.URL+"/", map[string]string{"Api-Key": "1234"}, traces))
+	assert.Equal(t, otelTracesPath, receivedPath)
+	assert.Equal(t, "application/json", receivedContentType)
+	assert.Equal(t, "1234", receivedApiKey)
+	assert.Equal(t, *traces, received)
+
+	// An endpoint which already includes the traces path is used as is
+	assert.NoError(t, postOtelTraces(server.Client(), server.URL+"/collector/v1/traces", nil, traces))
+	assert.Equal(t, "/collector/v1/traces", receivedPath)
+
+	assert.ErrorContains(t, postOtelTraces(server.Client(), server.URL+"/unavailable", nil, traces), "returned status 503")
+}
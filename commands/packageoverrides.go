@@ -0,0 +1,160 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const packageJsonFileName = "package.json"
+
+// Matches the indentation of the first field of a JSON file
+var jsonIndentRegex = regexp.MustCompile(`\n([ \t]+)"`)
+
+// The package.json sections, which override the versions of transitive dependencies
+var packageOverridesSections = map[coreutils.Technology]string{
+	coreutils.Npm:  "overrides",
+	coreutils.Yarn: "resolutions",
+}
+
+// isPackageOverrideSupported returns true if the version of a transitive dependency of the technology can be overridden in the project's manifest
+func isPackageOverrideSupported(packageType coreutils.Technology) bool {
+	_, exists := packageOverridesSections[packageType]
+	return exists || packageType == coreutils.Go
+}
+
+// overridePackageVersion fixes a vulnerable transitive dependency, which isn't fixed by upgrading a direct dependency, by overriding its version in the project's manifest:
+// in the 'overrides' section of the package.json file for npm, in its 'resolutions' section for Yarn, and with a replace directive of the go.mod file for Go.
+func overridePackageVersion(packageType coreutils.Technology, impactedPackage, fixVersion, workingDir string) (err error) {
+	if !isPackageOverrideSupported(packageType) {
+		return fmt.Errorf("overriding the versions of %s transitive dependencies is currently not supported", packageType)
+	}
+	if workingDir != "" {
+		var restoreDir func() error
+		if restoreDir, err = utils.Chdir(workingDir); err != nil {
+			return err
+		}
+		defer func() {
+			e := restoreDir()
+			if err == nil {
+				err = e
+			}
+		}()
+	}
+	if packageType == coreutils.Go {
+		return overrideGoModuleVersion(impactedPackage, fixVersion)
+	}
+	return overridePackageJsonVersion(packageType, impactedPackage, fixVersion)
+}
+
+// Override the version in the package.json file, and update the lock file by running the install command of the package manager
+func overridePackageJsonVersion(packageType coreutils.Technology, impactedPackage, fixVersion string) error {
+	section := packageOverridesSections[packageType]
+	log.Info(fmt.Sprintf("%s is a transitive dependency. Overriding its version in the '%s' section of %s", impactedPackage, section, packageJsonFileName))
+	content, err := os.ReadFile(packageJsonFileName)
+	if err != nil {
+		return err
+	}
+	if content, err = setPackageJsonOverride(content, section, impactedPackage, fixVersion); err != nil {
+		return fmt.Errorf("couldn't override the version of %s in %s: %s", impactedPackage, packageJsonFileName, err.Error())
+	}
+	if err = os.WriteFile(packageJsonFileName, content, 0600); err != nil {
+		return err
+	}
+	return runPackageMangerCommand(packageType.GetExecCommandName(), []string{"install"})
+}
+
+// setPackageJsonOverride sets the version of the package in the given section of the package.json content.
+// The rest of the file, including the order of the fields and the indentation, is kept.
+func setPackageJsonOverride(content []byte, section, packageName, packageVersion string) ([]byte, error) {
+	indent := "  "
+	if match := jsonIndentRegex.FindSubmatch(content); match != nil {
+		indent = string(match[1])
+	}
+	quotedName, err := json.Marshal(packageName)
+	if err != nil {
+		return nil, err
+	}
+	quotedVersion, err := json.Marshal(packageVersion)
+	if err != nil {
+		return nil, err
+	}
+	rootStart := bytes.IndexByte(content, '{')
+	if rootStart < 0 {
+		return nil, errors.New("a JSON object is expected")
+	}
+	sectionStart, _, rootEnd, err := findJsonField(content, rootStart, section)
+	if err != nil {
+		return nil, err
+	}
+	if sectionStart < 0 {
+		quotedSection, err := json.Marshal(section)
+		if err != nil {
+			return nil, err
+		}
+		field := fmt.Sprintf("%s: {\n%s%s: %s\n%s}", quotedSection, strings.Repeat(indent, 2), quotedName, quotedVersion, indent)
+		return insertJsonField(content, rootEnd, field, indent, 1), nil
+	}
+	if content[sectionStart] != '{' {
+		return nil, fmt.Errorf("the '%s' field is expected to be an object", section)
+	}
+	versionStart, versionEnd, sectionEnd, err := findJsonField(content, sectionStart, packageName)
+	if err != nil {
+		return nil, err
+	}
+	if versionStart >= 0 {
+		return append(append(append([]byte{}, content[:versionStart]...), quotedVersion...), content[versionEnd:]...), nil
+	}
+	return insertJsonField(content, sectionEnd, fmt.Sprintf("%s: %s", quotedName, quotedVersion), indent, 2), nil
+}
+
+// findJsonField looks for the field in the JSON object starting at objectStart.
+// It returns the start and end offsets of the field's value, or -1 if the field doesn't exist, and the offset of the object's closing brace.
+func findJsonField(content []byte, objectStart int, fieldName string) (valueStart, valueEnd, objectClose int, err error) {
+	valueStart, valueEnd = -1, -1
+	decoder := json.NewDecoder(bytes.NewReader(content[objectStart:]))
+	if _, err = decoder.Token(); err != nil {
+		return
+	}
+	for decoder.More() {
+		var key json.Token
+		if key, err = decoder.Token(); err != nil {
+			return
+		}
+		var value json.RawMessage
+		if err = decoder.Decode(&value); err != nil {
+			return
+		}
+		if key == fieldName {
+			valueEnd = objectStart + int(decoder.InputOffset())
+			valueStart = valueEnd - len(value)
+		}
+	}
+	if _, err = decoder.Token(); err != nil {
+		return
+	}
+	objectClose = objectStart + int(decoder.InputOffset()) - 1
+	return
+}
+
+// Insert the field as the last field of the object, which closes at objectClose and is nested in the given level of indentation
+func insertJsonField(content []byte, objectClose int, field, indent string, level int) []byte {
+	fields := bytes.TrimRight(content[:objectClose], " \t\r\n")
+	separator := ","
+	if bytes.HasSuffix(fields, []byte("{")) {
+		separator = ""
+	}
+	var result bytes.Buffer
+	result.Write(fields)
+	result.WriteString(separator + "\n" + strings.Repeat(indent, level) + field + "\n" + strings.Repeat(indent, level-1))
+	result.Write(content[objectClose:])
+	return result.Bytes()
+}
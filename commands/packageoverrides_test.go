@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPackageOverrideSupported(t *testing.T) {
+	assert.True(t, isPackageOverrideSupported(coreutils.Npm))
+	assert.True(t, isPackageOverrideSupported(coreutils.Yarn))
+	assert.True(t, isPackageOverrideSupported(coreutils.Go))
+	assert.False(t, isPackageOverrideSupported(coreutils.Maven))
+}
+
+func TestSetPackageJsonOverride(t *testing.T) {
+	testCases := []struct {
+		name     string
+		section  string
+		content  string
+		expected string
+	}{
+		{
+			name:     "newSection",
+			section:  "overrides",
+			content:  "{\n  \"name\": \"test\",\n  \"dependencies\": {\n    \"express\": \"4.18.0\"\n  }\n}\n",
+			expected: "{\n  \"name\": \"test\",\n  \"dependencies\": {\n    \"express\": \"4.18.0\"\n  },\n  \"overrides\": {\n    \"minimist\": \"1.2.6\"\n  }\n}\n",
+		},
+		{
+			name:     "existingSection",
+			section:  "resolutions",
+			content:  "{\n    \"resolutions\": {\n        \"lodash\": \"4.17.21\"\n    },\n    \"name\": \"test\"\n}",
+			expected: "{\n    \"resolutions\": {\n        \"lodash\": \"4.17.21\",\n        \"minimist\": \"1.2.6\"\n    },\n    \"name\": \"test\"\n}",
+		},
+		{
+			name:     "existingOverride",
+			section:  "overrides",
+			content:  "{\n  \"overrides\": {\n    \"minimist\": \"1.2.0\",\n    \"lodash\": \"4.17.21\"\n  }\n}",
+			expected: "{\n  \"overrides\": {\n    \"minimist\": \"1.2.6\",\n    \"lodash\": \"4.17.21\"\n  }\n}",
+		},
+		{
+			name:     "emptySection",
+			section:  "overrides",
+			content:  "{\n  \"name\": \"test\",\n  \"overrides\": {}\n}",
+			expected: "{\n  \"name\": \"test\",\n  \"overrides\": {\n    \"minimist\": \"1.2.6\"\n  }\n}",
+		},
+		{
+			name:     "emptyObject",
+			section:  "overrides",
+			content:  "{}",
+			expected: "{\n  \"overrides\": {\n    \"minimist\": \"1.2.6\"\n  }\n}",
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			content, err := setPackageJsonOverride([]byte(test.content), test.section, "minimist", "1.2.6")
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, string(content))
+		})
+	}
+}
+
+func TestSetPackageJsonOverrideErrors(t *testing.T) {
+	_, err := setPackageJsonOverride([]byte("[]"), "overrides", "minimist", "1.2.6")
+	assert.Error(t, err)
+	_, err = setPackageJsonOverride([]byte("{\"overrides\": \"1.2.0\"}"), "overrides", "minimist", "1.2.6")
+	assert.Error(t, err)
+	_, err = setPackageJsonOverride([]byte("{\"overrides\": {"), "overrides", "minimist", "1.2.6")
+	assert.Error(t, err)
+}
@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+)
+
+const partialScanTitle = "#### ⚠️ Partial scan"
+
+type projectAuditFunc func(project utils.Project) ([]services.ScanResponse, bool, error)
+
+// auditWorkingDirsSeparately audits each working dir of the project separately, after the audit of all the working dirs together failed, so that the issues
+// of the working dirs which can be scanned are reported. It returns the working dirs which were scanned, and the working dirs which couldn't be scanned.
+// The error of the whole audit is returned if the project has a single working dir, if Xray can't be reached, or if none of the working dirs can be scanned.
+func auditWorkingDirsSeparately(project utils.Project, auditErr error, audit projectAuditFunc) (results []services.ScanResponse, isMultipleRoot bool, scannedDirs, failedDirs []string, err error) {
+	if len(project.WorkingDirs) < 2 || isXrayConnectivityError(auditErr) {
+		return nil, false, nil, nil, auditErr
+	}
+	log.Warn("The scan of the working dirs failed. Scanning each working dir separately:", auditErr.Error())
+	for _, workingDir := range project.WorkingDirs {
+		workingDirProject := project
+		workingDirProject.WorkingDirs = []string{workingDir}
+		workingDirResults, workingDirMultipleRoot, e := audit(workingDirProject)
+		if e != nil {
+			log.Warn(fmt.Sprintf("couldn't scan the working dir '%s': %s", workingDir, e.Error()))
+			failedDirs = append(failedDirs, workingDir)
+			continue
+		}
+		results = append(results, workingDirResults...)
+		isMultipleRoot = isMultipleRoot || workingDirMultipleRoot
+		scannedDirs = append(scannedDirs, workingDir)
+	}
+	if len(scannedDirs) == 0 {
+		return nil, false, nil, nil, auditErr
+	}
+	return
+}
+
+// Return a scan execution error if some working dirs couldn't be scanned, unless Frogbot is configured to avoid the failure
+func getPartialScanError(repoConfig *utils.FrogbotRepoConfig, failedWorkingDirs []string) error {
+	if len(failedWorkingDirs) == 0 {
+		return nil
+	}
+	err := &ScanExecutionError{Err: fmt.Errorf(partialScanErr, strings.Join(failedWorkingDirs, ", "))}
+	if repoConfig.ShouldFailOnPartialScan() && repoConfig.ShouldFailOnScanError() {
+		return err
+	}
+	log.Warn(err.Error())
+	return nil
+}
+
+// Note the working dirs which couldn't be scanned, whose issues aren't shown
+func createPartialScanNote(failedWorkingDirs []string) string {
+	if len(failedWorkingDirs) == 0 {
+		return ""
+	}
+	var note strings.Builder
+	note.WriteString("\n\n" + partialScanTitle + "\n\nThe issues of the following subprojects aren't shown, since they couldn't be scanned. See the Frogbot log for the errors.\n")
+	for _, workingDir := range failedWorkingDirs {
+		note.WriteString(fmt.Sprintf("\n- Could not scan subproject `%s`", workingDir))
+	}
+	return note.String()
+}
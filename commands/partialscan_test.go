@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditWorkingDirsSeparately(t *testing.T) {
+	auditErr := errors.New("audit command in /tmp/broken failed")
+	var auditedDirs []string
+	audit := func(project utils.Project) ([]services.ScanResponse, bool, error) {
+		auditedDirs = append(auditedDirs, project.WorkingDirs...)
+		if project.WorkingDirs[0] == "broken" {
+			return nil, false, errors.New("'npm install' failed")
+		}
+		return []services.ScanResponse{{ScanId: project.WorkingDirs[0]}}, false, nil
+	}
+
+	results, _, scannedDirs, failedDirs, err := auditWorkingDirsSeparately(utils.Project{WorkingDirs: []string{"api", "broken", "web"}}, auditErr, audit)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"api", "broken", "web"}, auditedDirs)
+	assert.Equal(t, []services.ScanResponse{{ScanId: "api"}, {ScanId: "web"}}, results)
+	assert.Equal(t, []string{"api", "web"}, scannedDirs)
+	assert.Equal(t, []string{"broken"}, failedDirs)
+
+	// The error of the whole audit is returned, if none of the working dirs can be scanned
+	_, _, _, _, err = auditWorkingDirsSeparately(utils.Project{WorkingDirs: []string{"broken", "broken"}}, auditErr, audit)
+	assert.Equal(t, auditErr, err)
+
+	// A single working dir, or a connectivity error, isn't audited again
+	auditedDirs = nil
+	_, _, _, _, err = auditWorkingDirsSeparately(utils.Project{WorkingDirs: []string{"api"}}, auditErr, audit)
+	assert.Equal(t, auditErr, err)
+	connectivityErr := errors.New("server response: 503 Service Unavailable")
+	_, _, _, _, err = auditWorkingDirsSeparately(utils.Project{WorkingDirs: []string{"api", "web"}}, connectivityErr, audit)
+	assert.Equal(t, connectivityErr, err)
+	assert.Empty(t, auditedDirs)
+}
+
+func TestCreatePartialScanNote(t *testing.T) {
+	assert.Empty(t, createPartialScanNote(nil))
+	assert.Equal(t, "\n\n#### ⚠️ Partial scan\n\nThe issues of the following subprojects aren't shown, since they couldn't be scanned. See the Frogbot log for the errors.\n"+
+		"\n- Could not scan subproject `packages/broken`\n- Could not scan subproject `services/legacy`", createPartialScanNote([]string{"packages/broken", "services/legacy"}))
+}
+
+func TestGetPartialScanError(t *testing.T) {
+	repoConfig := &utils.FrogbotRepoConfig{}
+	assert.NoError(t, getPartialScanError(repoConfig, nil))
+
+	// The partial scan fails by default
+	err := getPartialScanError(repoConfig, []string{"packages/broken", "services/legacy"})
+	var scanErr *ScanExecutionError
+	if assert.ErrorAs(t, err, &scanErr) {
+		assert.Contains(t, err.Error(), "packages/broken, services/legacy")
+	}
+
+	// The failure is avoided by setting failOnPartialScan or failOnScanError to false
+	disabled := false
+	repoConfig.FailOnPartialScan = &disabled
+	assert.NoError(t, getPartialScanError(repoConfig, []string{"packages/broken"}))
+	repoConfig.FailOnPartialScan = nil
+	repoConfig.FailOnScanError = &disabled
+	assert.NoError(t, getPartialScanError(repoConfig, []string{"packages/broken"}))
+}
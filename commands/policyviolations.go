@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+)
+
+const (
+	policyViolationsTitle   = "#### 🛡️ Xray policies"
+	policyFailedSummary     = "❌ The pull request doesn't pass the policies of the watches %s, since it adds violations of rules which fail the build."
+	policyPassedSummary     = "✅ The pull request passes the policies of the watches %s, since none of the violations it adds are of rules which fail the build."
+	policyVulnerabilityNote = "%d of the issues are vulnerabilities which don't violate an Xray policy."
+)
+
+// The Xray watches whose policies a violation triggered
+type violationPolicy struct {
+	watches []string
+	// True if a rule of a policy which triggered the violation fails the build
+	failBuild bool
+}
+
+// Add the watches whose policies were triggered by each violation found in the scans of a single project, and the watches configured for this project.
+// The graph scan results hold the watch and the fail build action of the triggered rule, so these are shown as the policy of the violation.
+// The watches of the violations are added too, since the policies may be applied through the JFrog project rather than the configured watches.
+func (results *auditResults) addViolationsPolicies(watches []string, scans []services.ScanResponse) {
+	if results.violationsPolicies == nil {
+		results.violationsPolicies = make(map[string]violationPolicy)
+	}
+	results.policyWatches = appendUniqueWatches(results.policyWatches, watches...)
+	for _, scan := range scans {
+		for _, violation := range scan.Violations {
+			if violation.WatchName != "" {
+				results.policyWatches = appendUniqueWatches(results.policyWatches, violation.WatchName)
+			}
+			for componentId := range violation.Components {
+				name, version, _ := xrayutils.SplitComponentId(componentId)
+				issueId := getUniqueID(formats.VulnerabilityOrViolationRow{ImpactedDependencyName: name, ImpactedDependencyVersion: version, IssueId: violation.IssueId})
+				policy := results.violationsPolicies[issueId]
+				if violation.WatchName != "" {
+					policy.watches = appendUniqueWatches(policy.watches, violation.WatchName)
+				}
+				policy.failBuild = policy.failBuild || violation.FailBuild
+				results.violationsPolicies[issueId] = policy
+			}
+		}
+	}
+}
+
+func appendUniqueWatches(watches []string, added ...string) []string {
+	for _, watch := range added {
+		found := false
+		for _, existing := range watches {
+			found = found || existing == watch
+		}
+		if !found {
+			watches = append(watches, watch)
+		}
+	}
+	return watches
+}
+
+// Create a note which shows the watches whose policies each violation triggered, and whether the pull request passes the policies overall.
+// The issues which aren't violations, found in projects with no watches or requested by the both scan mode, are counted separately.
+func createPolicyViolationsNote(vulnerabilitiesRows []formats.VulnerabilityOrViolationRow, violationsPolicies map[string]violationPolicy, policyWatches []string) string {
+	if len(policyWatches) == 0 && len(violationsPolicies) == 0 {
+		return ""
+	}
+	var notes strings.Builder
+	failed := false
+	vulnerabilitiesCount := 0
+	for _, row := range vulnerabilitiesRows {
+		policy, exists := violationsPolicies[getUniqueID(row)]
+		if !exists {
+			vulnerabilitiesCount++
+			continue
+		}
+		failed = failed || policy.failBuild
+		action := "reported by"
+		if policy.failBuild {
+			action = "blocked by"
+		}
+		sortedWatches := append([]string{}, policy.watches...)
+		sort.Strings(sortedWatches)
+		notes.WriteString(fmt.Sprintf("- **%s %s** (%s): %s the policies of %s\n", row.ImpactedDependencyName, row.ImpactedDependencyVersion, getIssueDisplayId(row), action, formatWatches(sortedWatches)))
+	}
+	summary := fmt.Sprintf(policyPassedSummary, formatWatches(policyWatches))
+	if failed {
+		summary = fmt.Sprintf(policyFailedSummary, formatWatches(policyWatches))
+	}
+	note := "\n\n" + policyViolationsTitle + "\n\n" + summary + "\n"
+	if notes.Len() > 0 {
+		note += "\n" + notes.String()
+	}
+	if vulnerabilitiesCount > 0 {
+		note += "\n" + fmt.Sprintf(policyVulnerabilityNote, vulnerabilitiesCount) + "\n"
+	}
+	return note
+}
+
+func formatWatches(watches []string) string {
+	if len(watches) == 0 {
+		return "an unknown watch"
+	}
+	return "`" + strings.Join(watches, "`, `") + "`"
+}
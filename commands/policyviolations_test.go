@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddViolationsPolicies(t *testing.T) {
+	results := &auditResults{}
+	results.addViolationsPolicies([]string{"security-watch"}, []services.ScanResponse{{Violations: []services.Violation{
+		{IssueId: "XRAY-1", WatchName: "security-watch", FailBuild: true, Components: map[string]services.Component{"npm://lodash:4.17.20": {}}},
+		{IssueId: "XRAY-1", WatchName: "audit-watch", Components: map[string]services.Component{"npm://lodash:4.17.20": {}}},
+		{IssueId: "XRAY-2", WatchName: "audit-watch", Components: map[string]services.Component{"npm://minimist:1.2.5": {}}},
+	}}})
+	assert.Equal(t, []string{"security-watch", "audit-watch"}, results.policyWatches)
+	assert.Equal(t, map[string]violationPolicy{
+		"lodash4.17.20XRAY-1": {watches: []string{"security-watch", "audit-watch"}, failBuild: true},
+		"minimist1.2.5XRAY-2": {watches: []string{"audit-watch"}},
+	}, results.violationsPolicies)
+}
+
+func TestCreatePolicyViolationsNote(t *testing.T) {
+	// No note is added if no policies apply
+	assert.Empty(t, createPolicyViolationsNote(nil, nil, nil))
+
+	violationsPolicies := map[string]violationPolicy{
+		"lodash4.17.20XRAY-1": {watches: []string{"security-watch", "audit-watch"}, failBuild: true},
+		"minimist1.2.5XRAY-2": {watches: []string{"audit-watch"}},
+	}
+	rows := []formats.VulnerabilityOrViolationRow{
+		{ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "4.17.20", IssueId: "XRAY-1", Cves: []formats.CveRow{{Id: "CVE-2021-23337"}}},
+		{ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "1.2.5", IssueId: "XRAY-2"},
+		{ImpactedDependencyName: "express", ImpactedDependencyVersion: "4.17.0", IssueId: "XRAY-3"},
+	}
+	expected := "\n\n" + policyViolationsTitle + "\n\n" +
+		"❌ The pull request doesn't pass the policies of the watches `security-watch`, `audit-watch`, since it adds violations of rules which fail the build.\n\n" +
+		"- **lodash 4.17.20** (CVE-2021-23337): blocked by the policies of `audit-watch`, `security-watch`\n" +
+		"- **minimist 1.2.5** (XRAY-2): reported by the policies of `audit-watch`\n" +
+		"\n1 of the issues are vulnerabilities which don't violate an Xray policy.\n"
+	assert.Equal(t, expected, createPolicyViolationsNote(rows, violationsPolicies, []string{"security-watch", "audit-watch"}))
+
+	// The pull request passes the policies if none of its violations fail the build
+	note := createPolicyViolationsNote(rows[1:2], violationsPolicies, []string{"audit-watch"})
+	assert.Contains(t, note, "✅ The pull request passes the policies of the watches `audit-watch`")
+	assert.NotContains(t, note, "don't violate an Xray policy")
+}
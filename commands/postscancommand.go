@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	postScanCommandTimeout = 10 * time.Minute
+	scanResultsFileName    = "frogbot-scan-results.json"
+)
+
+// The scan results passed to the post scan command
+type postScanResults struct {
+	RepoOwner     string `json:"repoOwner"`
+	RepoName      string `json:"repoName"`
+	PullRequestID int    `json:"pullRequestId"`
+	// True if an issue which fails the scan was found, according to the severity policy
+	FailingIssuesFound bool                                  `json:"failingIssuesFound"`
+	Vulnerabilities    []formats.VulnerabilityOrViolationRow `json:"vulnerabilities"`
+	Misconfigurations  []utils.IacRow                        `json:"misconfigurations"`
+	Secrets            []postScanSecret                      `json:"secrets"`
+	// The issues omitted from the results by the ignoredDependencies entries, with the entry which suppressed each issue
+	SuppressedVulnerabilities []utils.SuppressedIssue `json:"suppressedVulnerabilities"`
+}
+
+type postScanSecret struct {
+	File          string `json:"file"`
+	Line          int    `json:"line"`
+	SecretType    string `json:"secretType"`
+	RedactedValue string `json:"redactedValue"`
+}
+
+// runPostScanCommand writes the scan results to a JSON file, and executes the post scan command with the path of the file in the JF_SCAN_RESULTS_PATH environment variable.
+// Like the install command, the command is split into its arguments and executed without a shell. The command is stopped if it doesn't complete in time.
+// The post scan command is an integration point only, so its failure is logged rather than failing the scan.
+func runPostScanCommand(repoConfig *utils.FrogbotRepoConfig, results *auditResults) {
+	if err := executePostScanCommand(repoConfig, results, postScanCommandTimeout); err != nil {
+		log.Warn("the post scan command failed:", err.Error())
+	}
+}
+
+func executePostScanCommand(repoConfig *utils.FrogbotRepoConfig, results *auditResults, timeout time.Duration) (err error) {
+	commandParts := strings.Fields(repoConfig.PostScanCommand)
+	if len(commandParts) == 0 {
+		return nil
+	}
+	tempDir, err := fileutils.CreateTempDir()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		e := fileutils.RemoveTempDir(tempDir)
+		if err == nil {
+			err = e
+		}
+	}()
+	resultsPath := filepath.Join(tempDir, scanResultsFileName)
+	if err = writePostScanResults(repoConfig, results, resultsPath); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	log.Info("Executing the post scan command", "'"+commandParts[0]+"'", commandParts[1:])
+	//#nosec G204 -- The command is set by the user in the Frogbot configuration, like the install command.
+	cmd := exec.CommandContext(ctx, commandParts[0], commandParts[1:]...)
+	cmd.Env = append(os.Environ(), utils.ScanResultsPathEnv+"="+resultsPath)
+	output, err := cmd.CombinedOutput()
+	if len(output) > 0 {
+		log.Info("The post scan command output:\n" + string(output))
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("the command didn't complete in %s", timeout)
+	}
+	return err
+}
+
+func writePostScanResults(repoConfig *utils.FrogbotRepoConfig, results *auditResults, resultsPath string) error {
+	scanResults := postScanResults{
+		RepoOwner:                 repoConfig.RepoOwner,
+		RepoName:                  repoConfig.RepoName,
+		PullRequestID:             repoConfig.PullRequestID,
+		FailingIssuesFound:        results.failingIssuesFound,
+		Vulnerabilities:           results.vulnerabilitiesRows,
+		Misconfigurations:         results.iacRows,
+		Secrets:                   []postScanSecret{},
+		SuppressedVulnerabilities: results.suppressedIssues,
+	}
+	// Write empty lists rather than null, so that the file is simpler to process
+	if scanResults.Vulnerabilities == nil {
+		scanResults.Vulnerabilities = []formats.VulnerabilityOrViolationRow{}
+	}
+	if scanResults.Misconfigurations == nil {
+		scanResults.Misconfigurations = []utils.IacRow{}
+	}
+	if scanResults.SuppressedVulnerabilities == nil {
+		scanResults.SuppressedVulnerabilities = []utils.SuppressedIssue{}
+	}
+	for _, secret := range results.secrets {
+		scanResults.Secrets = append(scanResults.Secrets, postScanSecret{File: secret.file, Line: secret.line, SecretType: secret.secretType, RedactedValue: secret.redactedValue})
+	}
+	content, err := json.MarshalIndent(scanResults, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(resultsPath, content, 0600)
+}
@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWritePostScanResults(t *testing.T) {
+	repoConfig := &utils.FrogbotRepoConfig{Params: utils.Params{Git: utils.Git{RepoOwner: "jfrog", RepoName: "frogbot", PullRequestID: 7}}}
+	results := &auditResults{
+		failingIssuesFound:  true,
+		vulnerabilitiesRows: []formats.VulnerabilityOrViolationRow{{ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "4.17.20", IssueId: "XRAY-1"}},
+		secrets:             []secretRow{{file: "config.yml", line: 3, secretType: "AWS access key ID", redactedValue: "AKIA****"}},
+		suppressedIssues: []utils.SuppressedIssue{{
+			VulnerabilityOrViolationRow: formats.VulnerabilityOrViolationRow{ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "0.0.8", IssueId: "XRAY-2"},
+			IgnoredDependency:           "minimist <1.0.0",
+		}},
+	}
+	resultsPath := filepath.Join(t.TempDir(), scanResultsFileName)
+	assert.NoError(t, writePostScanResults(repoConfig, results, resultsPath))
+	content, err := os.ReadFile(resultsPath)
+	assert.NoError(t, err)
+	var scanResults postScanResults
+	assert.NoError(t, json.Unmarshal(content, &scanResults))
+	assert.Equal(t, postScanResults{
+		RepoOwner:                 "jfrog",
+		RepoName:                  "frogbot",
+		PullRequestID:             7,
+		FailingIssuesFound:        true,
+		Vulnerabilities:           results.vulnerabilitiesRows,
+		Misconfigurations:         []utils.IacRow{},
+		Secrets:                   []postScanSecret{{File: "config.yml", Line: 3, SecretType: "AWS access key ID", RedactedValue: "AKIA****"}},
+		SuppressedVulnerabilities: results.suppressedIssues,
+	}, scanResults)
+	// The suppressed issues are written with the fields of the issues
+	assert.Contains(t, string(content), `"ignoredDependency": "minimist \u003c1.0.0"`)
+	assert.Contains(t, string(content), `"impactedPackageName": "minimist"`)
+}
+
+func TestExecutePostScanCommand(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "output.json")
+	// A failure of the command is returned
+	repoConfig := &utils.FrogbotRepoConfig{Params: utils.Params{PostScanCommand: "cp " + outputPath}}
+	assert.Error(t, executePostScanCommand(repoConfig, &auditResults{}, time.Minute))
+
+	// The command reads the results file through the JF_SCAN_RESULTS_PATH environment variable
+	scriptPath := filepath.Join(t.TempDir(), "post-scan.sh")
+	assert.NoError(t, os.WriteFile(scriptPath, []byte("#!/bin/sh\ncp \"$"+utils.ScanResultsPathEnv+"\" \"$1\"\n"), 0700))
+	repoConfig.PostScanCommand = scriptPath + " " + outputPath
+	assert.NoError(t, executePostScanCommand(repoConfig, &auditResults{failingIssuesFound: true}, time.Minute))
+	content, err := os.ReadFile(outputPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), `"failingIssuesFound": true`)
+
+	// Commands which don't complete in time are stopped
+	repoConfig.PostScanCommand = "sleep 5"
+	assert.EqualError(t, executePostScanCommand(repoConfig, &auditResults{}, 100*time.Millisecond), "the command didn't complete in 100ms")
+
+	// No command is executed if the command is empty
+	repoConfig.PostScanCommand = " "
+	assert.NoError(t, executePostScanCommand(repoConfig, &auditResults{}, time.Minute))
+}
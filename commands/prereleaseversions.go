@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+)
+
+var (
+	// The Maven qualifiers of the snapshot, alpha, beta, milestone, release candidate and early access versions, such as 1.4-SNAPSHOT, 6.0.0-M1 and 2.0.0.Beta2
+	mavenPrereleaseRegex = regexp.MustCompile(`(?i)[.-](snapshot|alpha|a|beta|b|milestone|m|rc|cr|preview|ea)[.-]?\d*([.-]|$)`)
+	// The PEP 440 alpha, beta, release candidate and development releases, such as 3.0b1, 1.0rc2 and 2.1.0.dev3
+	pythonPrereleaseRegex = regexp.MustCompile(`(?i)\d[._-]?(a|alpha|b|beta|c|rc|pre|preview|dev)[._-]?\d*`)
+	// The common pre-release keywords, for the package managers without a specific versioning scheme
+	genericPrereleaseRegex = regexp.MustCompile(`(?i)(snapshot|alpha|beta|rc|preview|dev)`)
+)
+
+// Return true if the version is a pre-release version, according to the versioning scheme of the package manager
+func isPrereleaseVersion(technology coreutils.Technology, packageVersion string) bool {
+	switch technology {
+	case coreutils.Npm, coreutils.Yarn, coreutils.Go, coreutils.Nuget, coreutils.Dotnet:
+		// Semantic versions have the pre-release after a hyphen, and the build metadata, which doesn't make a pre-release, after a plus sign
+		return strings.Contains(strings.SplitN(packageVersion, "+", 2)[0], "-")
+	case coreutils.Maven, coreutils.Gradle:
+		return mavenPrereleaseRegex.MatchString(packageVersion)
+	case coreutils.Pip, coreutils.Pipenv, coreutils.Poetry:
+		return pythonPrereleaseRegex.MatchString(packageVersion)
+	default:
+		return genericPrereleaseRegex.MatchString(packageVersion)
+	}
+}
+
+// Return the fix versions which aren't pre-release versions
+func getStableFixVersions(technology coreutils.Technology, fixVersions []string) (stableVersions []string) {
+	for _, fixVersion := range fixVersions {
+		if !isPrereleaseVersion(technology, parseVersionChangeString(fixVersion)) {
+			stableVersions = append(stableVersions, fixVersion)
+		}
+	}
+	return
+}
+
+// getFixVersionByPolicy returns the fix version chosen by the upgrade strategy. Unless allowPrerelease is set, the version is chosen out of the stable fix versions,
+// and a pre-release version is returned only if none of the stable fix versions fixes the impacted package.
+func getFixVersionByPolicy(upgradeStrategy string, allowPrerelease bool, technology coreutils.Technology, impactedPackageVersion string, fixVersions []string) string {
+	if !allowPrerelease {
+		if fixVersion := getFixVersion(upgradeStrategy, impactedPackageVersion, getStableFixVersions(technology, fixVersions)); fixVersion != "" {
+			return fixVersion
+		}
+	}
+	return getFixVersion(upgradeStrategy, impactedPackageVersion, fixVersions)
+}
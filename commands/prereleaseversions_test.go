@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPrereleaseVersion(t *testing.T) {
+	testCases := []struct {
+		technology coreutils.Technology
+		version    string
+		expected   bool
+	}{
+		{technology: coreutils.Npm, version: "2.0.0-rc.1", expected: true},
+		{technology: coreutils.Npm, version: "2.0.0", expected: false},
+		{technology: coreutils.Npm, version: "2.0.0+build.5", expected: false},
+		{technology: coreutils.Go, version: "v1.9.0-beta.2", expected: true},
+		{technology: coreutils.Go, version: "v2.0.0+incompatible", expected: false},
+		{technology: coreutils.Nuget, version: "6.0.0-preview.7", expected: true},
+		{technology: coreutils.Maven, version: "1.4-SNAPSHOT", expected: true},
+		{technology: coreutils.Maven, version: "6.0.0-M1", expected: true},
+		{technology: coreutils.Maven, version: "2.0.0.Beta2", expected: true},
+		{technology: coreutils.Gradle, version: "3.1.0-rc1", expected: true},
+		{technology: coreutils.Maven, version: "5.3.27.RELEASE", expected: false},
+		{technology: coreutils.Maven, version: "4.1.94.Final", expected: false},
+		{technology: coreutils.Maven, version: "32.0.1-android", expected: false},
+		{technology: coreutils.Maven, version: "2.15.2", expected: false},
+		{technology: coreutils.Pip, version: "3.0b1", expected: true},
+		{technology: coreutils.Pipenv, version: "1.0rc2", expected: true},
+		{technology: coreutils.Poetry, version: "2.1.0.dev3", expected: true},
+		{technology: coreutils.Pip, version: "2.31.0", expected: false},
+		{technology: coreutils.Pip, version: "1.0.post1", expected: false},
+	}
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, isPrereleaseVersion(testCase.technology, testCase.version), string(testCase.technology)+" "+testCase.version)
+	}
+}
+
+func TestGetFixVersionByPolicy(t *testing.T) {
+	fixVersions := []string{"[1.6.22-rc.1]", "[1.6.22]", "[2.0.0-beta.1]"}
+	assert.Equal(t, "1.6.22", getFixVersionByPolicy(utils.MinimalUpgradeStrategy, false, coreutils.Npm, "1.6.2", fixVersions))
+	assert.Equal(t, "1.6.22", getFixVersionByPolicy(utils.LatestUpgradeStrategy, false, coreutils.Npm, "1.6.2", fixVersions))
+	// The pre-release versions are chosen, if allowed
+	assert.Equal(t, "2.0.0-beta.1", getFixVersionByPolicy(utils.LatestUpgradeStrategy, true, coreutils.Npm, "1.6.2", fixVersions))
+
+	// A pre-release version is chosen if it's the only available fix
+	assert.Equal(t, "2.0.0-beta.1", getFixVersionByPolicy(utils.MinimalUpgradeStrategy, false, coreutils.Npm, "1.7.0", fixVersions))
+	assert.Equal(t, "1.4-SNAPSHOT", getFixVersionByPolicy(utils.MinimalUpgradeStrategy, false, coreutils.Maven, "1.3", []string{"[1.4-SNAPSHOT]"}))
+}
@@ -0,0 +1,266 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/xanzy/go-gitlab"
+)
+
+const (
+	// The digest marker is a hidden markdown comment, which identifies the issue of the pull requests digest
+	digestMarker                 = "[//]: # (frogbot-pull-requests-digest)"
+	digestIssueTitle             = "Frogbot pull requests digest"
+	digestTitle                  = "## 🐸 Frogbot pull requests digest\n\nScanned %d of %d open pull requests, which add %d new issues."
+	digestTableHeader            = "\n\n| PULL REQUEST | BRANCH | CRITICAL | HIGH | MEDIUM | LOW | UNKNOWN | SECRETS | TOTAL |\n" + "-- | -- | :--: | :--: | :--: | :--: | :--: | :--: | :--:"
+	digestTableRow               = "\n| %s | %s | %d | %d | %d | %d | %d | %d | %d |"
+	digestFailedTitle            = "\n\n#### ❌ Failed pull requests\n\n"
+	digestNotScannedTitle        = "\n\n#### ⏭️ Pull requests which weren't scanned\n\nOnly the newest %d pull requests are scanned, according to digestMaxPullRequests.\n\n"
+	defaultDigestMaxPullRequests = 20
+	digestPullRequestsPerPage    = 100
+	errUnsupportedDigestProvider = "the pull requests digest is supported only on GitHub and GitLab, but the Git provider of %s is %s"
+)
+
+type PullRequestsDigestCmd struct {
+}
+
+func (cmd PullRequestsDigestCmd) Run(configAggregator utils.FrogbotConfigAggregator, client vcsclient.VcsClient) error {
+	return utils.RunOnRepositories(configAggregator, client, func(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient) error {
+		return createPullRequestsDigest(repoConfig, client)
+	})
+}
+
+// The new issues added by a single scanned pull request
+type digestPullRequest struct {
+	pullRequest vcsclient.PullRequestInfo
+	// The vulnerabilities and the IaC issues, by severity
+	issues severityBreakdown
+	// The secrets have no severity, and are counted separately
+	secrets int
+	err     error
+}
+
+func (scannedPullRequest *digestPullRequest) issuesCount() int {
+	return scannedPullRequest.issues.Total + scannedPullRequest.secrets
+}
+
+// The digest of the open pull requests of a repository
+type pullRequestsDigest struct {
+	// The scanned pull requests, from the newest
+	scanned []digestPullRequest
+	// The pull requests which weren't scanned because of the digestMaxPullRequests limit
+	notScanned []vcsclient.PullRequestInfo
+	maxScanned int
+}
+
+// Scan the open pull requests of the repository, and publish a single digest with the new issues added by each of them.
+// Unlike scan-pull-requests, no comment is added to the pull requests themselves.
+func createPullRequestsDigest(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient) error {
+	if repoConfig.GitProvider != vcsutils.GitHub && repoConfig.GitProvider != vcsutils.GitLab {
+		return fmt.Errorf(errUnsupportedDigestProvider, repoConfig.RepoName, repoConfig.GitProvider.String())
+	}
+	openPullRequests, err := listAllOpenPullRequests(repoConfig)
+	if err != nil {
+		return &VcsError{Err: err}
+	}
+	digest := newPullRequestsDigest(openPullRequests, repoConfig.DigestMaxPullRequests)
+	log.Info(fmt.Sprintf("Scanning %d of the %d open pull requests of %s for the digest", len(digest.scanned), len(openPullRequests), repoConfig.RepoName))
+	for i := range digest.scanned {
+		scannedPullRequest := &digest.scanned[i]
+		scannedPullRequest.err = downloadPullRequest(scannedPullRequest.pullRequest, *repoConfig, client, func(frogbotParams *utils.FrogbotRepoConfig) (e error) {
+			targetBranch := newTargetBranchCheckout(frogbotParams, client)
+			defer func() {
+				removeErr := targetBranch.remove()
+				if e == nil {
+					e = removeErr
+				}
+			}()
+			results, e := auditPullRequest(frogbotParams, client, targetBranch)
+			if e != nil {
+				return e
+			}
+			scannedPullRequest.issues = results.severitySummary()
+			if frogbotParams.ScanSecrets {
+				secrets, e := auditPullRequestSecrets(targetBranch)
+				if e != nil {
+					return fmt.Errorf("the secrets scan failed: %w", e)
+				}
+				scannedPullRequest.secrets = len(secrets)
+			}
+			return nil
+		})
+		if scannedPullRequest.err != nil {
+			log.Error(fmt.Errorf(errPullRequestScan, int(scannedPullRequest.pullRequest.ID), repoConfig.RepoName, scannedPullRequest.err).Error())
+		}
+	}
+	return digest.publish(repoConfig)
+}
+
+// Return the pull requests to scan, from the newest, up to the max pull requests
+func newPullRequestsDigest(openPullRequests []vcsclient.PullRequestInfo, maxPullRequests int) *pullRequestsDigest {
+	if maxPullRequests <= 0 {
+		maxPullRequests = defaultDigestMaxPullRequests
+	}
+	pullRequests := append([]vcsclient.PullRequestInfo{}, openPullRequests...)
+	sort.SliceStable(pullRequests, func(i, j int) bool {
+		return pullRequests[i].ID > pullRequests[j].ID
+	})
+	digest := &pullRequestsDigest{maxScanned: maxPullRequests}
+	for i, pullRequest := range pullRequests {
+		if i >= maxPullRequests {
+			digest.notScanned = pullRequests[i:]
+			break
+		}
+		digest.scanned = append(digest.scanned, digestPullRequest{pullRequest: pullRequest})
+	}
+	return digest
+}
+
+// listAllOpenPullRequests lists all the open pull requests of the repository.
+// The froggit-go VCS client returns only the first page of the pull requests, and therefore the APIs of the Git providers are used directly.
+func listAllOpenPullRequests(repoConfig *utils.FrogbotRepoConfig) ([]vcsclient.PullRequestInfo, error) {
+	if repoConfig.GitProvider == vcsutils.GitLab {
+		return listAllOpenMergeRequests(repoConfig)
+	}
+	ghClient, err := newGitHubClient(&repoConfig.Git)
+	if err != nil {
+		return nil, err
+	}
+	var openPullRequests []vcsclient.PullRequestInfo
+	options := &github.PullRequestListOptions{State: "open", ListOptions: github.ListOptions{PerPage: digestPullRequestsPerPage}}
+	for {
+		pullRequests, response, err := ghClient.PullRequests.List(context.Background(), repoConfig.RepoOwner, repoConfig.RepoName, options)
+		if err != nil {
+			return nil, err
+		}
+		for _, pullRequest := range pullRequests {
+			openPullRequests = append(openPullRequests, vcsclient.PullRequestInfo{
+				ID:     int64(pullRequest.GetNumber()),
+				Source: vcsclient.BranchInfo{Name: pullRequest.GetHead().GetRef(), Repository: getPullRequestRepoName(pullRequest.GetHead(), repoConfig.RepoName)},
+				Target: vcsclient.BranchInfo{Name: pullRequest.GetBase().GetRef(), Repository: getPullRequestRepoName(pullRequest.GetBase(), repoConfig.RepoName)},
+			})
+		}
+		if response.NextPage == 0 {
+			return openPullRequests, nil
+		}
+		options.Page = response.NextPage
+	}
+}
+
+// On GitLab, the merge requests of all the projects of the user are returned by the froggit-go VCS client, and therefore only the merge requests of the project are listed
+func listAllOpenMergeRequests(repoConfig *utils.FrogbotRepoConfig) ([]vcsclient.PullRequestInfo, error) {
+	glClient, err := newGitLabClient(&repoConfig.Git)
+	if err != nil {
+		return nil, err
+	}
+	projectId := fmt.Sprintf("%s/%s", repoConfig.RepoOwner, repoConfig.RepoName)
+	var openPullRequests []vcsclient.PullRequestInfo
+	options := &gitlab.ListProjectMergeRequestsOptions{State: gitlab.String("opened"), ListOptions: gitlab.ListOptions{PerPage: digestPullRequestsPerPage}}
+	for {
+		mergeRequests, response, err := glClient.MergeRequests.ListProjectMergeRequests(projectId, options)
+		if err != nil {
+			return nil, err
+		}
+		for _, mergeRequest := range mergeRequests {
+			openPullRequests = append(openPullRequests, vcsclient.PullRequestInfo{
+				ID:     int64(mergeRequest.IID),
+				Source: vcsclient.BranchInfo{Name: mergeRequest.SourceBranch, Repository: repoConfig.RepoName},
+				Target: vcsclient.BranchInfo{Name: mergeRequest.TargetBranch, Repository: repoConfig.RepoName},
+			})
+		}
+		if response.NextPage == 0 {
+			return openPullRequests, nil
+		}
+		options.Page = response.NextPage
+	}
+}
+
+// The repository of the branch is missing if it was deleted, such as a deleted fork
+func getPullRequestRepoName(branch *github.PullRequestBranch, defaultRepoName string) string {
+	if name := branch.GetRepo().GetName(); name != "" {
+		return name
+	}
+	return defaultRepoName
+}
+
+func (digest *pullRequestsDigest) issuesCount() (count int) {
+	for i := range digest.scanned {
+		count += digest.scanned[i].issuesCount()
+	}
+	return
+}
+
+// Create the markdown of the digest, with a table of the scanned pull requests from the newest
+func (digest *pullRequestsDigest) toMarkdown() string {
+	var markdown strings.Builder
+	var succeeded, failed []digestPullRequest
+	for _, scannedPullRequest := range digest.scanned {
+		if scannedPullRequest.err != nil {
+			failed = append(failed, scannedPullRequest)
+		} else {
+			succeeded = append(succeeded, scannedPullRequest)
+		}
+	}
+	markdown.WriteString(fmt.Sprintf(digestTitle, len(digest.scanned), len(digest.scanned)+len(digest.notScanned), digest.issuesCount()))
+	if len(succeeded) > 0 {
+		markdown.WriteString(digestTableHeader)
+		for _, scannedPullRequest := range succeeded {
+			issues := scannedPullRequest.issues
+			markdown.WriteString(fmt.Sprintf(digestTableRow, getDigestPullRequestName(scannedPullRequest.pullRequest), getDigestBranches(scannedPullRequest.pullRequest),
+				issues.Critical, issues.High, issues.Medium, issues.Low, issues.Unknown, scannedPullRequest.secrets, scannedPullRequest.issuesCount()))
+		}
+	}
+	if len(failed) > 0 {
+		markdown.WriteString(digestFailedTitle)
+		for _, failedPullRequest := range failed {
+			// Only the first line of the error is shown, to keep the digest readable
+			errorLine, _, _ := strings.Cut(failedPullRequest.err.Error(), "\n")
+			markdown.WriteString(fmt.Sprintf("- **%s**: %s\n", getDigestPullRequestName(failedPullRequest.pullRequest), errorLine))
+		}
+	}
+	if len(digest.notScanned) > 0 {
+		markdown.WriteString(fmt.Sprintf(digestNotScannedTitle, digest.maxScanned))
+		for _, pullRequest := range digest.notScanned {
+			markdown.WriteString(fmt.Sprintf("- **%s** (%s)\n", getDigestPullRequestName(pullRequest), getDigestBranches(pullRequest)))
+		}
+	}
+	return markdown.String()
+}
+
+func getDigestPullRequestName(pullRequest vcsclient.PullRequestInfo) string {
+	return fmt.Sprintf("#%d", pullRequest.ID)
+}
+
+func getDigestBranches(pullRequest vcsclient.PullRequestInfo) string {
+	return fmt.Sprintf("%s → %s", pullRequest.Source.Name, pullRequest.Target.Name)
+}
+
+// Publish the digest to an issue of the repository, which is updated by the following runs.
+// The digest is published even if some pull requests failed, and their errors are returned after it.
+func (digest *pullRequestsDigest) publish(repoConfig *utils.FrogbotRepoConfig) error {
+	report := digest.toMarkdown() + "\n\n" + digestMarker
+	var errs []error
+	var err error
+	if repoConfig.GitProvider == vcsutils.GitLab {
+		err = publishGitLabIssueReport(&repoConfig.Git, digestIssueTitle, digestMarker, report)
+	} else {
+		err = publishIssueReport(&repoConfig.Git, digestIssueTitle, digestMarker, report)
+	}
+	if err != nil {
+		errs = append(errs, fmt.Errorf("couldn't publish the pull requests digest: %w", err))
+	}
+	for _, scannedPullRequest := range digest.scanned {
+		if scannedPullRequest.err != nil {
+			errs = append(errs, fmt.Errorf(errPullRequestScan, int(scannedPullRequest.pullRequest.ID), repoConfig.RepoName, scannedPullRequest.err))
+		}
+	}
+	return errors.Join(errs...)
+}
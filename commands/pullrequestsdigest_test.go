@@ -0,0 +1,165 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestPullRequests(ids ...int64) (pullRequests []vcsclient.PullRequestInfo) {
+	for _, id := range ids {
+		pullRequests = append(pullRequests, vcsclient.PullRequestInfo{
+			ID:     id,
+			Source: vcsclient.BranchInfo{Name: fmt.Sprintf("feature-%d", id), Repository: "frogbot"},
+			Target: vcsclient.BranchInfo{Name: "main", Repository: "frogbot"},
+		})
+	}
+	return
+}
+
+func TestNewPullRequestsDigest(t *testing.T) {
+	// The newest pull requests are scanned
+	digest := newPullRequestsDigest(createTestPullRequests(3, 7, 5, 1), 2)
+	require.Len(t, digest.scanned, 2)
+	assert.Equal(t, int64(7), digest.scanned[0].pullRequest.ID)
+	assert.Equal(t, int64(5), digest.scanned[1].pullRequest.ID)
+	assert.Equal(t, createTestPullRequests(3, 1), digest.notScanned)
+
+	digest = newPullRequestsDigest(createTestPullRequests(3, 7, 5, 1), 0)
+	assert.Len(t, digest.scanned, 4)
+	assert.Empty(t, digest.notScanned)
+	assert.Equal(t, defaultDigestMaxPullRequests, digest.maxScanned)
+}
+
+func createTestPullRequestsDigest() *pullRequestsDigest {
+	digest := newPullRequestsDigest(createTestPullRequests(1, 2, 3, 4), 3)
+	digest.scanned[0].issues = severityBreakdown{Critical: 1, Low: 2, Total: 3}
+	digest.scanned[0].secrets = 1
+	digest.scanned[2].err = errors.New("couldn't download the branch\nstatus 404")
+	return digest
+}
+
+func TestPullRequestsDigestToMarkdown(t *testing.T) {
+	markdown := createTestPullRequestsDigest().toMarkdown()
+	assert.Contains(t, markdown, "Scanned 3 of 4 open pull requests, which add 4 new issues.")
+	assert.Contains(t, markdown, digestTableHeader+"\n| #4 | feature-4 → main | 1 | 0 | 0 | 2 | 0 | 1 | 4 |\n| #3 | feature-3 → main | 0 | 0 | 0 | 0 | 0 | 0 | 0 |")
+	assert.NotContains(t, markdown, "| #2 |")
+	// Only the first line of the error is shown
+	assert.Contains(t, markdown, digestFailedTitle+"- **#2**: couldn't download the branch\n")
+	assert.Contains(t, markdown, fmt.Sprintf(digestNotScannedTitle, 3)+"- **#1** (feature-1 → main)\n")
+}
+
+func TestPullRequestsDigestPublishIssue(t *testing.T) {
+	var requestedIssue map[string]string
+	var requestedPath string
+	server := createIssuesServer(t, `[]`, &requestedIssue, &requestedPath)
+	defer server.Close()
+
+	repoConfig := newTestRepoConfig(vcsutils.GitHub, server.URL)
+	err := createTestPullRequestsDigest().publish(repoConfig)
+	// The digest is published, and the failed pull requests fail the run
+	assert.EqualError(t, err, fmt.Errorf(errPullRequestScan, 2, "frogbot", errors.New("couldn't download the branch\nstatus 404")).Error())
+	assert.Equal(t, "POST /repos/jfrog/frogbot/issues", requestedPath)
+	assert.Equal(t, digestIssueTitle, requestedIssue["title"])
+	assert.Contains(t, requestedIssue["body"], "| #4 | feature-4 → main |")
+	assert.Contains(t, requestedIssue["body"], digestMarker)
+}
+
+func TestListAllOpenPullRequestsGitHub(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/jfrog/frogbot/pulls", r.URL.Path)
+		assert.Equal(t, "open", r.URL.Query().Get("state"))
+		pullRequest := `{"number": %d, "head": {"ref": "feature-%d", "repo": {"name": "%s"}}, "base": {"ref": "main", "repo": {"name": "frogbot"}}}`
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/jfrog/frogbot/pulls?state=open&page=2>; rel="next"`, server.URL))
+			_, err := fmt.Fprintf(w, "["+pullRequest+"]", 2, 2, "frogbot-fork")
+			assert.NoError(t, err)
+			return
+		}
+		// The repository of a deleted fork is missing
+		_, err := fmt.Fprint(w, `[{"number": 1, "head": {"ref": "feature-1"}, "base": {"ref": "main", "repo": {"name": "frogbot"}}}]`)
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	repoConfig := newTestRepoConfig(vcsutils.GitHub, server.URL)
+	pullRequests, err := listAllOpenPullRequests(repoConfig)
+	require.NoError(t, err)
+	expected := createTestPullRequests(2, 1)
+	expected[0].Source.Repository = "frogbot-fork"
+	assert.Equal(t, expected, pullRequests)
+}
+
+// The GitLab client requests the base URL when it's created, to configure its rate limit
+func isGitLabRateLimitRequest(r *http.Request) bool {
+	return r.URL.Path == "/api/v4/"
+}
+
+func TestListAllOpenPullRequestsGitLab(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isGitLabRateLimitRequest(r) {
+			return
+		}
+		assert.Equal(t, "/api/v4/projects/jfrog/frogbot/merge_requests", r.URL.Path)
+		assert.Equal(t, "opened", r.URL.Query().Get("state"))
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("X-Next-Page", "2")
+			_, err := fmt.Fprint(w, `[{"iid": 2, "source_branch": "feature-2", "target_branch": "main"}]`)
+			assert.NoError(t, err)
+			return
+		}
+		_, err := fmt.Fprint(w, `[{"iid": 1, "source_branch": "feature-1", "target_branch": "main"}]`)
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	repoConfig := newTestRepoConfig(vcsutils.GitLab, server.URL)
+	pullRequests, err := listAllOpenPullRequests(repoConfig)
+	require.NoError(t, err)
+	assert.Equal(t, createTestPullRequests(2, 1), pullRequests)
+}
+
+func TestPullRequestsDigestPublishGitLabIssue(t *testing.T) {
+	var requestedIssue map[string]string
+	var requestedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isGitLabRateLimitRequest(r) {
+			return
+		}
+		if r.Method == http.MethodGet {
+			assert.Equal(t, "/api/v4/projects/jfrog/frogbot/issues", r.URL.Path)
+			assert.Equal(t, "opened", r.URL.Query().Get("state"))
+			_, err := fmt.Fprintf(w, `[{"id": 30, "iid": 3, "description": "old digest\n\n%s"}]`, digestMarker)
+			assert.NoError(t, err)
+			return
+		}
+		requestedPath = r.Method + " " + r.URL.Path
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&requestedIssue))
+		_, err := fmt.Fprint(w, `{"id": 30, "iid": 3}`)
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	repoConfig := newTestRepoConfig(vcsutils.GitLab, server.URL)
+	digest := newPullRequestsDigest(createTestPullRequests(1), 0)
+	assert.NoError(t, digest.publish(repoConfig))
+	// The existing digest issue is updated
+	assert.Equal(t, "PUT /api/v4/projects/jfrog/frogbot/issues/3", requestedPath)
+	assert.Equal(t, digestIssueTitle, requestedIssue["title"])
+	assert.Contains(t, requestedIssue["description"], "| #1 | feature-1 → main |")
+}
+
+func TestCreatePullRequestsDigestUnsupportedProvider(t *testing.T) {
+	repoConfig := &utils.FrogbotRepoConfig{Params: utils.Params{Git: utils.Git{GitProvider: vcsutils.BitbucketServer, RepoOwner: "jfrog", RepoName: "frogbot"}}}
+	assert.EqualError(t, createPullRequestsDigest(repoConfig, mockVcsClient(t)), fmt.Sprintf(errUnsupportedDigestProvider, "frogbot", vcsutils.BitbucketServer.String()))
+}
@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const remediationCommandsTitle = "#### 🛠️ Remediation commands"
+
+// detectProjectEcosystems detects the package managers of the project by the manifests in its working directories, such as go.mod and yarn.lock
+func detectProjectEcosystems(project *utils.Project) ([]coreutils.Technology, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	var ecosystems []coreutils.Technology
+	for _, workDir := range getFullPathWorkingDirs(project, wd) {
+		detected, err := coreutils.DetectTechnologies(workDir, false, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, tech := range coreutils.ToTechnologies(coreutils.DetectedTechnologiesToSlice(detected)) {
+			if project.IsEcosystemScanned(tech) {
+				ecosystems = append(ecosystems, tech)
+			}
+		}
+	}
+	return ecosystems, nil
+}
+
+// Choose the package manager of the project, which installs the impacted dependency of the row.
+// Xray reports the package type of the dependency, so Yarn and npm projects, or pip, Pipenv and Poetry projects can't be told apart without the manifest.
+func getRowEcosystem(row formats.VulnerabilityOrViolationRow, ecosystems []coreutils.Technology) coreutils.Technology {
+	var candidates []coreutils.Technology
+	for _, ecosystem := range ecosystems {
+		if ecosystem == row.Technology {
+			return ecosystem
+		}
+		if row.Technology == "" || getEcosystemPackageType(ecosystem) == getEcosystemPackageType(row.Technology) {
+			candidates = append(candidates, ecosystem)
+		}
+	}
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+	return row.Technology
+}
+
+// Yarn installs npm packages, though its package type isn't defined as npm
+func getEcosystemPackageType(ecosystem coreutils.Technology) string {
+	if ecosystem == coreutils.Yarn {
+		return coreutils.Npm.GetPackageType()
+	}
+	return ecosystem.GetPackageType()
+}
+
+// Return the command which upgrades the impacted dependency to the fix version, or an empty string if the package manager has no such command
+func getRemediationCommand(ecosystem coreutils.Technology, impactedPackage, fixVersion string) string {
+	switch ecosystem {
+	case coreutils.Go:
+		return fmt.Sprintf("go get %s@v%s", impactedPackage, strings.TrimPrefix(fixVersion, "v"))
+	case coreutils.Npm:
+		return fmt.Sprintf("npm install %s@%s", impactedPackage, fixVersion)
+	case coreutils.Yarn:
+		return fmt.Sprintf("yarn up %s@%s", impactedPackage, fixVersion)
+	case coreutils.Pip:
+		return fmt.Sprintf("pip install %s==%s", impactedPackage, fixVersion)
+	case coreutils.Pipenv:
+		return fmt.Sprintf("pipenv install %s==%s", impactedPackage, fixVersion)
+	case coreutils.Poetry:
+		return fmt.Sprintf("poetry add %s==%s", impactedPackage, fixVersion)
+	case coreutils.Maven:
+		return fmt.Sprintf("mvn versions:use-dep-version -Dincludes=%s -DdepVersion=%s -DgenerateBackupPoms=false", impactedPackage, fixVersion)
+	case coreutils.Nuget, coreutils.Dotnet:
+		return fmt.Sprintf("dotnet add package %s --version %s", impactedPackage, fixVersion)
+	default:
+		return ""
+	}
+}
+
+// Add the remediation commands of the fixable issues of a single project. The fix version is chosen like in fix pull requests.
+func (results *auditResults) addRemediationCommands(project *utils.Project, vulnerabilitiesRows []formats.VulnerabilityOrViolationRow, upgradeStrategy string, allowPrerelease bool) {
+	ecosystems, err := detectProjectEcosystems(project)
+	if err != nil {
+		// The commands are optional, so the scan continues without them
+		log.Warn("couldn't detect the package managers of the project. Skipping the remediation commands:", err.Error())
+		return
+	}
+	if results.remediationCommands == nil {
+		results.remediationCommands = make(map[string]string)
+	}
+	for _, row := range vulnerabilitiesRows {
+		fixVersion := getFixVersionByPolicy(upgradeStrategy, allowPrerelease, row.Technology, row.ImpactedDependencyVersion, row.FixedVersions)
+		if fixVersion == "" {
+			continue
+		}
+		if command := getRemediationCommand(getRowEcosystem(row, ecosystems), row.ImpactedDependencyName, fixVersion); command != "" {
+			results.remediationCommands[getUniqueID(row)] = command
+		}
+	}
+}
+
+// Create a note with the command which fixes each fixable issue shown in the comment
+func createRemediationCommandsNotes(vulnerabilitiesRows []formats.VulnerabilityOrViolationRow, remediationCommands map[string]string) string {
+	var notes strings.Builder
+	for _, row := range vulnerabilitiesRows {
+		command, exists := remediationCommands[getUniqueID(row)]
+		if !exists {
+			continue
+		}
+		notes.WriteString(fmt.Sprintf("- **%s %s** (%s): `%s`\n", row.ImpactedDependencyName, row.ImpactedDependencyVersion, getIssueDisplayId(row), command))
+	}
+	if notes.Len() == 0 {
+		return ""
+	}
+	return "\n\n" + remediationCommandsTitle + "\n\n" + notes.String()
+}
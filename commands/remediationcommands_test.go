@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRemediationCommand(t *testing.T) {
+	testCases := []struct {
+		ecosystem       coreutils.Technology
+		impactedPackage string
+		fixVersion      string
+		expected        string
+	}{
+		{ecosystem: coreutils.Go, impactedPackage: "github.com/gin-gonic/gin", fixVersion: "1.9.1", expected: "go get github.com/gin-gonic/gin@v1.9.1"},
+		{ecosystem: coreutils.Npm, impactedPackage: "lodash", fixVersion: "4.17.21", expected: "npm install lodash@4.17.21"},
+		{ecosystem: coreutils.Yarn, impactedPackage: "lodash", fixVersion: "4.17.21", expected: "yarn up lodash@4.17.21"},
+		{ecosystem: coreutils.Pip, impactedPackage: "pyjwt", fixVersion: "2.4.0", expected: "pip install pyjwt==2.4.0"},
+		{ecosystem: coreutils.Pipenv, impactedPackage: "pyjwt", fixVersion: "2.4.0", expected: "pipenv install pyjwt==2.4.0"},
+		{ecosystem: coreutils.Poetry, impactedPackage: "pyjwt", fixVersion: "2.4.0", expected: "poetry add pyjwt==2.4.0"},
+		{ecosystem: coreutils.Maven, impactedPackage: "org.jfrog:frog", fixVersion: "1.2.0",
+			expected: "mvn versions:use-dep-version -Dincludes=org.jfrog:frog -DdepVersion=1.2.0 -DgenerateBackupPoms=false"},
+		{ecosystem: coreutils.Nuget, impactedPackage: "Newtonsoft.Json", fixVersion: "13.0.1", expected: "dotnet add package Newtonsoft.Json --version 13.0.1"},
+		{ecosystem: coreutils.Gradle, impactedPackage: "org.jfrog:frog", fixVersion: "1.2.0", expected: ""},
+	}
+	for _, test := range testCases {
+		t.Run(string(test.ecosystem), func(t *testing.T) {
+			assert.Equal(t, test.expected, getRemediationCommand(test.ecosystem, test.impactedPackage, test.fixVersion))
+		})
+	}
+}
+
+func TestGetRowEcosystem(t *testing.T) {
+	npmRow := formats.VulnerabilityOrViolationRow{Technology: coreutils.Npm}
+	// The manifest tells Yarn projects apart
+	assert.Equal(t, coreutils.Yarn, getRowEcosystem(npmRow, []coreutils.Technology{coreutils.Go, coreutils.Yarn}))
+	assert.Equal(t, coreutils.Npm, getRowEcosystem(npmRow, []coreutils.Technology{coreutils.Npm, coreutils.Yarn}))
+	assert.Equal(t, coreutils.Npm, getRowEcosystem(npmRow, nil))
+	assert.Equal(t, coreutils.Poetry, getRowEcosystem(formats.VulnerabilityOrViolationRow{Technology: coreutils.Pip}, []coreutils.Technology{coreutils.Poetry}))
+	assert.Equal(t, coreutils.Go, getRowEcosystem(formats.VulnerabilityOrViolationRow{}, []coreutils.Technology{coreutils.Go}))
+}
+
+func TestAddRemediationCommands(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{"go.mod": "module github.com/jfrog/remediation\n\ngo 1.19\n"})
+	restoreDir, err := utils.Chdir(dir)
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, restoreDir())
+	}()
+	rows := []formats.VulnerabilityOrViolationRow{
+		{ImpactedDependencyName: "github.com/gin-gonic/gin", ImpactedDependencyVersion: "v1.7.0", FixedVersions: []string{"[1.7.7]", "[1.9.1]"},
+			Technology: coreutils.Go, IssueId: "XRAY-1", Cves: []formats.CveRow{{Id: "CVE-2023-29401"}}},
+		{ImpactedDependencyName: "github.com/jfrog/unfixed", ImpactedDependencyVersion: "v1.0.0", Technology: coreutils.Go, IssueId: "XRAY-2"},
+	}
+	results := &auditResults{}
+	results.addRemediationCommands(&utils.Project{}, rows, utils.LatestUpgradeStrategy, false)
+	assert.Equal(t, map[string]string{getUniqueID(rows[0]): "go get github.com/gin-gonic/gin@v1.9.1"}, results.remediationCommands)
+
+	expected := "\n\n" + remediationCommandsTitle + "\n\n- **github.com/gin-gonic/gin v1.7.0** (CVE-2023-29401): `go get github.com/gin-gonic/gin@v1.9.1`\n"
+	assert.Equal(t, expected, createRemediationCommandsNotes(rows, results.remediationCommands))
+	assert.Empty(t, createRemediationCommandsNotes(rows, nil))
+}
@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/xanzy/go-gitlab"
+)
+
+const (
+	// The report marker is a hidden markdown comment, which identifies the issue of the branch report
+	reportMarker      = "[//]: # (frogbot-report %s)"
+	reportIssueTitle  = "Frogbot scan results for the %s branch"
+	reportIssuesCount = 100
+)
+
+// Publish the results of a repository scan to the configured report target.
+// The froggit-go VCS client doesn't support issues, and therefore the GitHub API is used directly.
+func publishRepositoryReport(repoConfig *utils.FrogbotRepoConfig, branch string, results *auditResults) error {
+	if repoConfig.ReportTarget != utils.IssueReportTarget {
+		return nil
+	}
+	if repoConfig.GitProvider != vcsutils.GitHub {
+		log.Warn("Reporting the scan results to an issue is supported only on GitHub. Skipping the report")
+		return nil
+	}
+	marker := fmt.Sprintf(reportMarker, branch)
+	report := createPullRequestMessage(results.vulnerabilitiesRows, repoConfig.OutputWriter) +
+		createSeverityNotes(len(results.vulnerabilitiesRows) > 0, &repoConfig.Scan, repoConfig.OutputWriter) +
+		"\n\n" + marker
+	log.Info("Publishing the scan results of the", branch, "branch")
+	return publishIssueReport(&repoConfig.Git, fmt.Sprintf(reportIssueTitle, branch), marker, report)
+}
+
+// Create the issue of the report, or update it if it already exists. The issue is identified by the marker in its body.
+func publishIssueReport(git *utils.Git, title, marker, report string) error {
+	client, err := newGitHubClient(git)
+	if err != nil {
+		return err
+	}
+	issueNumber, err := findReportIssue(client, git, marker)
+	if err != nil {
+		return err
+	}
+	issueRequest := &github.IssueRequest{Title: github.String(title), Body: github.String(report)}
+	if issueNumber == 0 {
+		log.Info("Creating the issue:", title)
+		_, _, err = client.Issues.Create(context.Background(), git.RepoOwner, git.RepoName, issueRequest)
+		return err
+	}
+	log.Info("Updating issue", issueNumber, "with the report:", title)
+	_, _, err = client.Issues.Edit(context.Background(), git.RepoOwner, git.RepoName, issueNumber, issueRequest)
+	return err
+}
+
+// Return the number of the open issue with the report marker, or 0 if no such issue exists
+func findReportIssue(client *github.Client, git *utils.Git, marker string) (int, error) {
+	options := &github.IssueListByRepoOptions{State: "open", ListOptions: github.ListOptions{PerPage: reportIssuesCount}}
+	for {
+		issues, response, err := client.Issues.ListByRepo(context.Background(), git.RepoOwner, git.RepoName, options)
+		if err != nil {
+			return 0, err
+		}
+		for _, issue := range issues {
+			// The issues list includes pull requests too
+			if !issue.IsPullRequest() && strings.Contains(issue.GetBody(), marker) {
+				return issue.GetNumber(), nil
+			}
+		}
+		if response.NextPage == 0 {
+			return 0, nil
+		}
+		options.Page = response.NextPage
+	}
+}
+
+// Create the GitLab issue of the report, or update it if it already exists. The issue is identified by the marker in its description.
+func publishGitLabIssueReport(git *utils.Git, title, marker, report string) error {
+	client, err := newGitLabClient(git)
+	if err != nil {
+		return err
+	}
+	projectId := fmt.Sprintf("%s/%s", git.RepoOwner, git.RepoName)
+	issueId, err := findGitLabReportIssue(client, projectId, marker)
+	if err != nil {
+		return err
+	}
+	if issueId == 0 {
+		log.Info("Creating the issue:", title)
+		_, _, err = client.Issues.CreateIssue(projectId, &gitlab.CreateIssueOptions{Title: gitlab.String(title), Description: gitlab.String(report)})
+		return err
+	}
+	log.Info("Updating issue", issueId, "with the report:", title)
+	_, _, err = client.Issues.UpdateIssue(projectId, issueId, &gitlab.UpdateIssueOptions{Title: gitlab.String(title), Description: gitlab.String(report)})
+	return err
+}
+
+// Return the IID of the open GitLab issue with the report marker, or 0 if no such issue exists
+func findGitLabReportIssue(client *gitlab.Client, projectId, marker string) (int, error) {
+	options := &gitlab.ListProjectIssuesOptions{State: gitlab.String("opened"), ListOptions: gitlab.ListOptions{PerPage: reportIssuesCount}}
+	for {
+		issues, response, err := client.Issues.ListProjectIssues(projectId, options)
+		if err != nil {
+			return 0, err
+		}
+		for _, issue := range issues {
+			if strings.Contains(issue.Description, marker) {
+				return issue.IID, nil
+			}
+		}
+		if response.NextPage == 0 {
+			return 0, nil
+		}
+		options.Page = response.NextPage
+	}
+}
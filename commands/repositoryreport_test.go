@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+// Create a server with the given open issues, which records the created or updated issue
+func createIssuesServer(t *testing.T, issuesJson string, requestedIssue *map[string]string, requestedPath *string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			assert.Equal(t, "/repos/jfrog/frogbot/issues", r.URL.Path)
+			assert.Equal(t, "open", r.URL.Query().Get("state"))
+			_, err := fmt.Fprint(w, issuesJson)
+			assert.NoError(t, err)
+			return
+		}
+		*requestedPath = r.Method + " " + r.URL.Path
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(requestedIssue))
+		_, err := fmt.Fprint(w, `{"number": 1}`)
+		assert.NoError(t, err)
+	}))
+}
+
+func TestPublishRepositoryReportCreatesIssue(t *testing.T) {
+	var requestedIssue map[string]string
+	var requestedPath string
+	// A pull request with the marker and a report of a different branch are ignored
+	issues := fmt.Sprintf(`[{"number": 2, "body": "%s", "pull_request": {"url": "pr"}}, {"number": 3, "body": "%s"}]`,
+		fmt.Sprintf(reportMarker, "master"), fmt.Sprintf(reportMarker, "dev"))
+	server := createIssuesServer(t, issues, &requestedIssue, &requestedPath)
+	defer server.Close()
+
+	results := &auditResults{vulnerabilitiesRows: []formats.VulnerabilityOrViolationRow{{Severity: "High", ImpactedDependencyName: "lodash"}}}
+	repoConfig := newTestRepoConfig(vcsutils.GitHub, server.URL)
+	repoConfig.ReportTarget = utils.IssueReportTarget
+	assert.NoError(t, publishRepositoryReport(repoConfig, "master", results))
+	assert.Equal(t, "POST /repos/jfrog/frogbot/issues", requestedPath)
+	assert.Equal(t, "Frogbot scan results for the master branch", requestedIssue["title"])
+	assert.Contains(t, requestedIssue["body"], "lodash")
+	assert.Contains(t, requestedIssue["body"], fmt.Sprintf(reportMarker, "master"))
+}
+
+func TestPublishRepositoryReportUpdatesIssue(t *testing.T) {
+	var requestedIssue map[string]string
+	var requestedPath string
+	issues := fmt.Sprintf(`[{"number": 3, "body": "Old results\n\n%s"}]`, fmt.Sprintf(reportMarker, "master"))
+	server := createIssuesServer(t, issues, &requestedIssue, &requestedPath)
+	defer server.Close()
+
+	repoConfig := newTestRepoConfig(vcsutils.GitHub, server.URL)
+	repoConfig.ReportTarget = utils.IssueReportTarget
+	assert.NoError(t, publishRepositoryReport(repoConfig, "master", &auditResults{}))
+	assert.Equal(t, "PATCH /repos/jfrog/frogbot/issues/3", requestedPath)
+	assert.Contains(t, requestedIssue["body"], fmt.Sprintf(reportMarker, "master"))
+}
+
+func TestPublishRepositoryReportSkipped(t *testing.T) {
+	// No requests are expected, since no server is listening on the API endpoint
+	repoConfig := newTestRepoConfig(vcsutils.GitHub, "http://127.0.0.1:1")
+	assert.NoError(t, publishRepositoryReport(repoConfig, "master", &auditResults{}))
+
+	repoConfig.ReportTarget = utils.IssueReportTarget
+	repoConfig.GitProvider = vcsutils.GitLab
+	assert.NoError(t, publishRepositoryReport(repoConfig, "master", &auditResults{}))
+}
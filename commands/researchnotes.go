@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+)
+
+const (
+	researchNotesTitle        = "#### 🔬 JFrog research"
+	researchSeverityNote      = "**JFrog research severity:** %s (Xray severity: %s)"
+	researchRemediationNote   = "**Remediation:** %s"
+	researchNoteSummaryFormat = "%s %s (%s)"
+)
+
+// Return true if the JFrog research information of the issue adds guidance beyond the Xray issue, which is a remediation advice or a research severity which differs from the Xray severity
+func hasResearchAdvice(row formats.VulnerabilityOrViolationRow) bool {
+	research := row.JfrogResearchInformation
+	if research == nil {
+		return false
+	}
+	return strings.TrimSpace(research.Remediation) != "" || isResearchSeverityDifferent(row)
+}
+
+func isResearchSeverityDifferent(row formats.VulnerabilityOrViolationRow) bool {
+	researchSeverity := strings.TrimSpace(row.JfrogResearchInformation.Severity)
+	return researchSeverity != "" && !strings.EqualFold(researchSeverity, row.Severity)
+}
+
+// Create the content of the research note of a single issue, with the research severity and its reasons, and the remediation advice
+func createResearchNoteContent(row formats.VulnerabilityOrViolationRow) string {
+	research := row.JfrogResearchInformation
+	var content []string
+	if isResearchSeverityDifferent(row) {
+		severityNote := fmt.Sprintf(researchSeverityNote, strings.TrimSpace(research.Severity), row.Severity)
+		for _, reason := range research.SeverityReasons {
+			if reason.Name == "" {
+				continue
+			}
+			severityNote += "\n- " + reason.Name
+			if reason.Description != "" {
+				severityNote += ": " + reason.Description
+			}
+		}
+		content = append(content, severityNote)
+	}
+	if remediation := strings.TrimSpace(research.Remediation); remediation != "" {
+		content = append(content, fmt.Sprintf(researchRemediationNote, remediation))
+	}
+	return "\n" + strings.Join(content, "\n\n")
+}
+
+// Create an expandable note for each issue shown in the comment, for which the JFrog research provides guidance beyond the Xray issue
+func createResearchNotes(vulnerabilitiesRows []formats.VulnerabilityOrViolationRow, writer utils.OutputWriter) string {
+	var notes strings.Builder
+	for _, row := range vulnerabilitiesRows {
+		if !hasResearchAdvice(row) {
+			continue
+		}
+		summary := fmt.Sprintf(researchNoteSummaryFormat, row.ImpactedDependencyName, row.ImpactedDependencyVersion, getIssueDisplayId(row))
+		notes.WriteString(writer.CollapsibleSection(summary, createResearchNoteContent(row)))
+	}
+	if notes.Len() == 0 {
+		return ""
+	}
+	return "\n\n" + researchNotesTitle + notes.String()
+}
@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateResearchNotes(t *testing.T) {
+	rows := []formats.VulnerabilityOrViolationRow{
+		{Severity: "Critical", ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "1.2.5", Cves: []formats.CveRow{{Id: "CVE-2021-44906"}},
+			JfrogResearchInformation: &formats.JfrogResearchInformation{
+				Severity:        "Low",
+				SeverityReasons: []formats.JfrogResearchSeverityReason{{Name: "Exploitation requires a rare configuration", Description: "The API must be exposed", IsPositive: true}},
+				Remediation:     "Avoid passing user input to minimist",
+			}},
+		// The research severity is the same as the Xray severity, and there's no remediation advice
+		{Severity: "High", ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "4.17.19", IssueId: "XRAY-1",
+			JfrogResearchInformation: &formats.JfrogResearchInformation{Severity: "High", Summary: "Prototype pollution"}},
+		{Severity: "Medium", ImpactedDependencyName: "axios", ImpactedDependencyVersion: "0.21.0", IssueId: "XRAY-2"},
+		{Severity: "Medium", ImpactedDependencyName: "ws", ImpactedDependencyVersion: "7.4.5", IssueId: "XRAY-3",
+			JfrogResearchInformation: &formats.JfrogResearchInformation{Remediation: "Limit the headers size"}},
+	}
+	expected := "\n\n" + researchNotesTitle +
+		"\n\n<details>\n<summary>minimist 1.2.5 (CVE-2021-44906)</summary>\n\n" +
+		"**JFrog research severity:** Low (Xray severity: Critical)\n- Exploitation requires a rare configuration: The API must be exposed\n\n" +
+		"**Remediation:** Avoid passing user input to minimist\n\n</details>" +
+		"\n\n<details>\n<summary>ws 7.4.5 (XRAY-3)</summary>\n\n**Remediation:** Limit the headers size\n\n</details>"
+	assert.Equal(t, expected, createResearchNotes(rows, &utils.StandardOutput{}))
+	assert.Contains(t, createResearchNotes(rows, &utils.SimplifiedOutput{}), "\n\n**ws 7.4.5 (XRAY-3)**\n\n**Remediation:** Limit the headers size")
+	assert.Empty(t, createResearchNotes(rows[1:3], &utils.StandardOutput{}))
+}
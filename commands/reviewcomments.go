@@ -0,0 +1,209 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/xanzy/go-gitlab"
+)
+
+// Identifies the review comments of the diff annotations, so that they aren't posted again when the pull request is scanned again
+const diffAnnotationMarker = "[//]: # (frogbot-diff-annotation)"
+
+var hunkHeaderRegex = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// A comment on a line added by the pull request
+type reviewComment struct {
+	// The path of the file, relative to the root of the repository
+	file string
+	line int
+	body string
+}
+
+// The froggit-go VCS client doesn't support the diffs and the review comments of pull requests, and therefore the GitHub and the GitLab APIs are used directly.
+// On the other providers, the diff annotations are listed in the comment of the scan results.
+func isReviewCommentsSupported(provider vcsutils.VcsProvider) bool {
+	return provider == vcsutils.GitHub || provider == vcsutils.GitLab
+}
+
+// getPullRequestAddedLines returns the lines added by the pull request according to its diff, by the path of the file relative to the root of the repository
+func getPullRequestAddedLines(repoConfig *utils.FrogbotRepoConfig) (map[string][]addedLine, error) {
+	if repoConfig.GitProvider == vcsutils.GitLab {
+		return getGitLabMergeRequestAddedLines(&repoConfig.Git, repoConfig.PullRequestID)
+	}
+	return getGitHubPullRequestAddedLines(&repoConfig.Git, repoConfig.PullRequestID)
+}
+
+func getGitHubPullRequestAddedLines(git *utils.Git, pullRequestID int) (map[string][]addedLine, error) {
+	client, err := newGitHubClient(git)
+	if err != nil {
+		return nil, err
+	}
+	addedLines := make(map[string][]addedLine)
+	options := &github.ListOptions{PerPage: 100}
+	for {
+		files, response, err := client.PullRequests.ListFiles(context.Background(), git.RepoOwner, git.RepoName, pullRequestID, options)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			if fileAddedLines := parseAddedLines(file.GetPatch()); len(fileAddedLines) > 0 {
+				addedLines[file.GetFilename()] = fileAddedLines
+			}
+		}
+		if response.NextPage == 0 {
+			return addedLines, nil
+		}
+		options.Page = response.NextPage
+	}
+}
+
+func getGitLabMergeRequestAddedLines(git *utils.Git, mergeRequestID int) (map[string][]addedLine, error) {
+	client, err := newGitLabClient(git)
+	if err != nil {
+		return nil, err
+	}
+	mergeRequest, _, err := client.MergeRequests.GetMergeRequestChanges(fmt.Sprintf("%s/%s", git.RepoOwner, git.RepoName), mergeRequestID, &gitlab.GetMergeRequestChangesOptions{})
+	if err != nil {
+		return nil, err
+	}
+	addedLines := make(map[string][]addedLine)
+	for _, change := range mergeRequest.Changes {
+		if fileAddedLines := parseAddedLines(change.Diff); len(fileAddedLines) > 0 && !change.DeletedFile {
+			addedLines[change.NewPath] = fileAddedLines
+		}
+	}
+	return addedLines, nil
+}
+
+// Return the added lines of a unified diff of a single file, with their numbers in the new version of the file
+func parseAddedLines(patch string) (addedLines []addedLine) {
+	lineNumber := 0
+	for _, line := range strings.Split(patch, "\n") {
+		if match := hunkHeaderRegex.FindStringSubmatch(line); match != nil {
+			lineNumber, _ = strconv.Atoi(match[1])
+			continue
+		}
+		if lineNumber == 0 {
+			// The headers of the diff, before the first hunk
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "+"):
+			addedLines = append(addedLines, addedLine{number: lineNumber, text: strings.TrimSuffix(line[1:], "\r")})
+			lineNumber++
+		case strings.HasPrefix(line, "-"), strings.HasPrefix(line, `\`):
+			// The removed lines and the "No newline at end of file" notes don't exist in the new version of the file
+		default:
+			lineNumber++
+		}
+	}
+	return
+}
+
+// postReviewComments posts the comments on the lines of the pull request, except for the comments which were already posted by previous scans
+func postReviewComments(repoConfig *utils.FrogbotRepoConfig, comments []reviewComment) error {
+	if repoConfig.GitProvider == vcsutils.GitLab {
+		return postGitLabReviewComments(&repoConfig.Git, repoConfig.PullRequestID, comments)
+	}
+	return postGitHubReviewComments(&repoConfig.Git, repoConfig.PullRequestID, comments)
+}
+
+func postGitHubReviewComments(git *utils.Git, pullRequestID int, comments []reviewComment) error {
+	client, err := newGitHubClient(git)
+	if err != nil {
+		return err
+	}
+	pullRequest, _, err := client.PullRequests.Get(context.Background(), git.RepoOwner, git.RepoName, pullRequestID)
+	if err != nil {
+		return err
+	}
+	postedComments := make(map[reviewComment]bool)
+	options := &github.PullRequestListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		existingComments, response, err := client.PullRequests.ListComments(context.Background(), git.RepoOwner, git.RepoName, pullRequestID, options)
+		if err != nil {
+			return err
+		}
+		for _, comment := range existingComments {
+			postedComments[reviewComment{file: comment.GetPath(), line: comment.GetLine(), body: comment.GetBody()}] = true
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		options.Page = response.NextPage
+	}
+	for _, comment := range comments {
+		if postedComments[comment] {
+			continue
+		}
+		_, _, err = client.PullRequests.CreateComment(context.Background(), git.RepoOwner, git.RepoName, pullRequestID, &github.PullRequestComment{
+			Body:     github.String(comment.body),
+			CommitID: github.String(pullRequest.GetHead().GetSHA()),
+			Path:     github.String(comment.file),
+			Line:     github.Int(comment.line),
+			Side:     github.String("RIGHT"),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func postGitLabReviewComments(git *utils.Git, mergeRequestID int, comments []reviewComment) error {
+	client, err := newGitLabClient(git)
+	if err != nil {
+		return err
+	}
+	projectId := fmt.Sprintf("%s/%s", git.RepoOwner, git.RepoName)
+	mergeRequest, _, err := client.MergeRequests.GetMergeRequest(projectId, mergeRequestID, &gitlab.GetMergeRequestsOptions{})
+	if err != nil {
+		return err
+	}
+	postedComments := make(map[reviewComment]bool)
+	options := &gitlab.ListMergeRequestDiscussionsOptions{PerPage: 100}
+	for {
+		discussions, response, err := client.Discussions.ListMergeRequestDiscussions(projectId, mergeRequestID, options)
+		if err != nil {
+			return err
+		}
+		for _, discussion := range discussions {
+			for _, note := range discussion.Notes {
+				if note.Position != nil {
+					postedComments[reviewComment{file: note.Position.NewPath, line: note.Position.NewLine, body: note.Body}] = true
+				}
+			}
+		}
+		if response.NextPage == 0 {
+			break
+		}
+		options.Page = response.NextPage
+	}
+	for _, comment := range comments {
+		if postedComments[comment] {
+			continue
+		}
+		_, _, err = client.Discussions.CreateMergeRequestDiscussion(projectId, mergeRequestID, &gitlab.CreateMergeRequestDiscussionOptions{
+			Body: gitlab.String(comment.body),
+			Position: &gitlab.NotePosition{
+				BaseSHA:      mergeRequest.DiffRefs.BaseSha,
+				StartSHA:     mergeRequest.DiffRefs.StartSha,
+				HeadSHA:      mergeRequest.DiffRefs.HeadSha,
+				PositionType: "text",
+				NewPath:      comment.file,
+				NewLine:      comment.line,
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAddedLines(t *testing.T) {
+	patch := "diff --git a/package.json b/package.json\n--- a/package.json\n+++ b/package.json\n" +
+		"@@ -2,4 +2,5 @@\n   \"dependencies\": {\n-    \"express\": \"4.17.0\"\n+    \"express\": \"4.17.0\",\n+    \"lodash\": \"4.17.20\"\n   }\n }\n\\ No newline at end of file\n" +
+		"@@ -20 +21,2 @@ scripts\n+    \"test\": \"jest\"\r\n     \"build\": \"tsc\""
+	assert.Equal(t, []addedLine{
+		{number: 3, text: `    "express": "4.17.0",`},
+		{number: 4, text: `    "lodash": "4.17.20"`},
+		{number: 21, text: `    "test": "jest"`},
+	}, parseAddedLines(patch))
+	assert.Empty(t, parseAddedLines(""))
+}
+
+func TestFilterManifestsAddedLines(t *testing.T) {
+	lines := []addedLine{{number: 1, text: "lodash"}}
+	addedManifestLines := filterManifestsAddedLines(map[string][]addedLine{
+		"package.json":      lines,
+		"web/package.json":  lines,
+		"package-lock.json": lines,
+		"README.md":         lines,
+	})
+	assert.Equal(t, map[string][]addedLine{"package.json": lines, "web/package.json": lines}, addedManifestLines)
+}
+
+func TestPostGitHubReviewComments(t *testing.T) {
+	postedComment := reviewComment{file: "package.json", line: 3, body: "posted" + diffAnnotationMarker}
+	newComment := reviewComment{file: "package.json", line: 4, body: "new" + diffAnnotationMarker}
+	var createdComments []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/repos/jfrog/frogbot/pulls/5":
+			_, err := w.Write([]byte(`{"number": 5, "head": {"sha": "abc123"}}`))
+			assert.NoError(t, err)
+		case r.URL.Path == "/repos/jfrog/frogbot/pulls/5/comments" && r.Method == http.MethodGet:
+			_, err := w.Write([]byte(`[{"path": "package.json", "line": 3, "body": "posted` + diffAnnotationMarker + `"}]`))
+			assert.NoError(t, err)
+		case r.URL.Path == "/repos/jfrog/frogbot/pulls/5/comments" && r.Method == http.MethodPost:
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			var comment map[string]any
+			assert.NoError(t, json.Unmarshal(body, &comment))
+			createdComments = append(createdComments, comment)
+			w.WriteHeader(http.StatusCreated)
+			_, err = w.Write([]byte("{}"))
+			assert.NoError(t, err)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	repoConfig := newTestRepoConfig(vcsutils.GitHub, server.URL)
+	repoConfig.PullRequestID = 5
+	assert.NoError(t, postReviewComments(repoConfig, []reviewComment{postedComment, newComment}))
+	// The comment which was posted by a previous scan isn't posted again
+	assert.Equal(t, []map[string]any{{"body": newComment.body, "commit_id": "abc123", "path": "package.json", "line": float64(4), "side": "RIGHT"}}, createdComments)
+}
+
+func TestGitLabReviewComments(t *testing.T) {
+	var createdDiscussions []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isGitLabRateLimitRequest(r) {
+			return
+		}
+		var response string
+		switch {
+		case r.URL.Path == "/api/v4/projects/jfrog/frogbot/merge_requests/5/changes":
+			response = `{"id": 1, "changes": [{"new_path": "go.mod", "diff": "@@ -3 +3 @@\n-require golang.org/x/net v0.6.0\n+require golang.org/x/net v0.7.0\n"},
+				{"new_path": "removed/go.mod", "deleted_file": true, "diff": "@@ -1 +0,0 @@\n-module removed\n"}]}`
+		case r.URL.Path == "/api/v4/projects/jfrog/frogbot/merge_requests/5":
+			response = `{"id": 1, "diff_refs": {"base_sha": "base", "head_sha": "head", "start_sha": "start"}}`
+		case r.URL.Path == "/api/v4/projects/jfrog/frogbot/merge_requests/5/discussions" && r.Method == http.MethodGet:
+			response = `[{"id": "1", "notes": [{"id": 1, "body": "posted", "position": {"new_path": "go.mod", "new_line": 3}}]}]`
+		case r.URL.Path == "/api/v4/projects/jfrog/frogbot/merge_requests/5/discussions" && r.Method == http.MethodPost:
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			var discussion map[string]any
+			assert.NoError(t, json.Unmarshal(body, &discussion))
+			createdDiscussions = append(createdDiscussions, discussion)
+			w.WriteHeader(http.StatusCreated)
+			response = `{"id": "2"}`
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		_, err := w.Write([]byte(response))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	repoConfig := &utils.FrogbotRepoConfig{Params: utils.Params{Git: utils.Git{GitProvider: vcsutils.GitLab, RepoOwner: "jfrog", RepoName: "frogbot", ApiEndpoint: server.URL, PullRequestID: 5}}}
+	addedLines, err := getPullRequestAddedLines(repoConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]addedLine{"go.mod": {{number: 3, text: "require golang.org/x/net v0.7.0"}}}, addedLines)
+
+	assert.NoError(t, postReviewComments(repoConfig, []reviewComment{{file: "go.mod", line: 3, body: "posted"}, {file: "go.mod", line: 3, body: "new"}}))
+	if assert.Len(t, createdDiscussions, 1) {
+		assert.Equal(t, "new", createdDiscussions[0]["body"])
+		assert.Equal(t, map[string]any{"base_sha": "base", "start_sha": "start", "head_sha": "head", "position_type": "text", "new_path": "go.mod", "new_line": float64(3), "line_range": nil},
+			createdDiscussions[0]["position"])
+	}
+}
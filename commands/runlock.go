@@ -0,0 +1,158 @@
+package commands
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	runLockComment = "🐸 Frogbot is scanning commit %s. Other Frogbot runs on this commit are skipped, to avoid duplicate comments."
+	// A run lock expires after this duration, so that a run which was stopped before commenting doesn't block the following runs
+	runLockTimeout = 30 * time.Minute
+)
+
+// acquireRunLock returns true if this run may scan the commit, or false if another Frogbot run is already scanning it.
+// Since the git providers don't support locks, the lock is a comment with a hidden marker, added when the run starts.
+// The runs which started concurrently all add their lock comments, and the run with the earliest comment holds the lock.
+// The lock is released by the results comment of the run which holds it, or when it expires.
+// The ID of the run is returned if its lock comment was added, so that the comment can be deleted when the run ends.
+func acquireRunLock(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient, commitSha string) (runId string, acquired bool, err error) {
+	if holder, err := getRunLockHolder(repoConfig, client, commitSha, time.Now()); err != nil || holder != "" {
+		return "", false, err
+	}
+	if runId, err = generateRunId(); err != nil {
+		return "", false, err
+	}
+	lockComment := utils.GetBotHeader(repoConfig.BotName) + fmt.Sprintf(runLockComment, commitSha) + utils.GetRunLockMarker(commitSha, runId)
+	if err = client.AddPullRequestComment(context.Background(), repoConfig.RepoOwner, repoConfig.RepoName, lockComment, repoConfig.PullRequestID); err != nil {
+		return "", false, err
+	}
+	// Another run may have added its lock comment in the meantime
+	holder, err := getRunLockHolder(repoConfig, client, commitSha, time.Now())
+	if err != nil {
+		return runId, false, err
+	}
+	return runId, holder == runId, nil
+}
+
+// releaseRunLock deletes the lock comments of the run, so that they don't clutter the pull request.
+// The VCS client can't delete comments, so the comments are deleted using the API of the git provider, if it's GitHub or GitLab.
+// The lock comments of the other git providers are kept, and the lock is released by the results comment.
+func releaseRunLock(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient, runId string, deleteComment commentDeleter) {
+	if deleteComment == nil {
+		return
+	}
+	comments, err := client.ListPullRequestComments(context.Background(), repoConfig.RepoOwner, repoConfig.RepoName, repoConfig.PullRequestID)
+	if err != nil {
+		log.Warn("couldn't delete the run lock comment:", err.Error())
+		return
+	}
+	for _, comment := range comments {
+		if _, lockRunId, found := utils.ParseRunLockMarker(comment.Content); found && lockRunId == runId {
+			if err = deleteComment(comment.ID); err != nil {
+				log.Warn("couldn't delete the run lock comment:", err.Error())
+			}
+		}
+	}
+}
+
+type commentDeleter func(commentId int64) error
+
+// Return a function which deletes a pull request comment, or nil if the git provider isn't supported
+func getCommentDeleter(repoConfig *utils.FrogbotRepoConfig) (commentDeleter, error) {
+	switch repoConfig.GitProvider {
+	case vcsutils.GitHub:
+		client, err := newGitHubClient(&repoConfig.Git)
+		if err != nil {
+			return nil, err
+		}
+		return func(commentId int64) error {
+			_, err := client.Issues.DeleteComment(context.Background(), repoConfig.RepoOwner, repoConfig.RepoName, commentId)
+			return err
+		}, nil
+	case vcsutils.GitLab:
+		client, err := newGitLabClient(&repoConfig.Git)
+		if err != nil {
+			return nil, err
+		}
+		projectId := fmt.Sprintf("%s/%s", repoConfig.RepoOwner, repoConfig.RepoName)
+		return func(commentId int64) error {
+			_, err := client.Notes.DeleteMergeRequestNote(projectId, repoConfig.PullRequestID, int(commentId))
+			return err
+		}, nil
+	}
+	return nil, nil
+}
+
+// Return the ID of the run which holds the lock of the commit, or an empty string if the commit isn't locked
+func getRunLockHolder(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient, commitSha string, now time.Time) (string, error) {
+	comments, err := client.ListPullRequestComments(context.Background(), repoConfig.RepoOwner, repoConfig.RepoName, repoConfig.PullRequestID)
+	if err != nil {
+		return "", err
+	}
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].Created.Before(comments[j].Created)
+	})
+	var holder string
+	for _, comment := range comments {
+		if lockedCommit, runId, found := utils.ParseRunLockMarker(comment.Content); found {
+			if holder == "" && lockedCommit == commitSha && now.Sub(comment.Created) < runLockTimeout {
+				holder = runId
+			}
+			continue
+		}
+		if repoConfig.IsFrogbotResultComment(comment.Content) {
+			// The results comment of the run which holds the lock releases it
+			holder = ""
+		}
+	}
+	return holder, nil
+}
+
+func generateRunId() (string, error) {
+	randomBytes := make([]byte, 8)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(randomBytes), nil
+}
+
+// Return true if the pull request should be scanned, or false if another Frogbot run is already scanning the commit,
+// and a function which deletes the lock comment of this run, to be called when the run ends.
+// The lock is advisory, so the pull request is scanned if the lock can't be acquired due to an error.
+func shouldScanCommit(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient) (scan bool, release func()) {
+	release = func() {}
+	commitSha := utils.GetHeadCommitSha(".")
+	if commitSha == "" {
+		log.Warn("couldn't find the scanned commit, so duplicate Frogbot runs on this commit can't be detected")
+		return true, release
+	}
+	runId, acquired, err := acquireRunLock(repoConfig, client, commitSha)
+	if runId != "" {
+		release = func() {
+			deleteComment, e := getCommentDeleter(repoConfig)
+			if e != nil {
+				log.Warn("couldn't delete the run lock comment:", e.Error())
+				return
+			}
+			releaseRunLock(repoConfig, client, runId, deleteComment)
+		}
+	}
+	if err != nil {
+		log.Warn("couldn't check for other Frogbot runs on this commit:", err.Error())
+		return true, release
+	}
+	if !acquired {
+		log.Info(fmt.Sprintf("Another Frogbot run is already scanning commit %s. Skipping this run", commitSha))
+	}
+	return acquired, release
+}
@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+)
+
+const runLockTestCommit = "0123456789abcdef"
+
+func createRunLockTestComment(commitSha, runId string, created time.Time) vcsclient.CommentInfo {
+	return vcsclient.CommentInfo{Content: fmt.Sprintf(runLockComment, commitSha) + utils.GetRunLockMarker(commitSha, runId), Created: created}
+}
+
+func TestGetRunLockHolder(t *testing.T) {
+	now := time.Now()
+	resultComment := vcsclient.CommentInfo{Content: (&utils.StandardOutput{}).NoVulnerabilitiesTitle(), Created: now.Add(-5 * time.Minute)}
+	testCases := []struct {
+		name     string
+		comments []vcsclient.CommentInfo
+		expected string
+	}{
+		{name: "no lock", comments: []vcsclient.CommentInfo{{Content: "LGTM", Created: now}}, expected: ""},
+		{name: "earliest lock", comments: []vcsclient.CommentInfo{
+			createRunLockTestComment(runLockTestCommit, "bb", now.Add(-time.Minute)),
+			createRunLockTestComment(runLockTestCommit, "aa", now.Add(-2*time.Minute)),
+		}, expected: "aa"},
+		{name: "other commit", comments: []vcsclient.CommentInfo{createRunLockTestComment("fedcba", "aa", now)}, expected: ""},
+		{name: "expired lock", comments: []vcsclient.CommentInfo{createRunLockTestComment(runLockTestCommit, "aa", now.Add(-runLockTimeout))}, expected: ""},
+		{name: "released lock", comments: []vcsclient.CommentInfo{createRunLockTestComment(runLockTestCommit, "aa", now.Add(-10*time.Minute)), resultComment}, expected: ""},
+		{name: "lock after release", comments: []vcsclient.CommentInfo{
+			createRunLockTestComment(runLockTestCommit, "aa", now.Add(-10*time.Minute)), resultComment,
+			createRunLockTestComment(runLockTestCommit, "cc", now.Add(-time.Minute)),
+		}, expected: "cc"},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			client := mockVcsClient(t)
+			client.EXPECT().ListPullRequestComments(context.Background(), "jfrog", "frogbot", 1).Return(test.comments, nil)
+			repoConfig := newTestRepoConfig(vcsutils.GitHub, "")
+			repoConfig.PullRequestID = 1
+			holder, err := getRunLockHolder(repoConfig, client, runLockTestCommit, now)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, holder)
+		})
+	}
+}
+
+func TestAcquireRunLock(t *testing.T) {
+	repoConfig := newTestRepoConfig(vcsutils.GitHub, "")
+	repoConfig.PullRequestID = 1
+	now := time.Now()
+
+	// The lock comment of this run is the earliest
+	client := mockVcsClient(t)
+	var ownComment vcsclient.CommentInfo
+	gomock.InOrder(
+		client.EXPECT().ListPullRequestComments(context.Background(), "jfrog", "frogbot", 1).Return(nil, nil),
+		client.EXPECT().AddPullRequestComment(context.Background(), "jfrog", "frogbot", gomock.Any(), 1).DoAndReturn(
+			func(_ context.Context, _, _, content string, _ int) error {
+				ownComment = vcsclient.CommentInfo{Content: content, Created: now}
+				return nil
+			}),
+		client.EXPECT().ListPullRequestComments(context.Background(), "jfrog", "frogbot", 1).DoAndReturn(
+			func(context.Context, string, string, int) ([]vcsclient.CommentInfo, error) {
+				return []vcsclient.CommentInfo{ownComment, createRunLockTestComment(runLockTestCommit, "bb", now.Add(time.Second))}, nil
+			}),
+	)
+	runId, acquired, err := acquireRunLock(repoConfig, client, runLockTestCommit)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+	assert.Contains(t, ownComment.Content, runId)
+	assert.Contains(t, ownComment.Content, fmt.Sprintf(runLockComment, runLockTestCommit))
+
+	// Another run added its lock comment first
+	client = mockVcsClient(t)
+	gomock.InOrder(
+		client.EXPECT().ListPullRequestComments(context.Background(), "jfrog", "frogbot", 1).Return(nil, nil),
+		client.EXPECT().AddPullRequestComment(context.Background(), "jfrog", "frogbot", gomock.Any(), 1).Return(nil),
+		client.EXPECT().ListPullRequestComments(context.Background(), "jfrog", "frogbot", 1).Return(
+			[]vcsclient.CommentInfo{createRunLockTestComment(runLockTestCommit, "aa", now.Add(-time.Second))}, nil),
+	)
+	runId, acquired, err = acquireRunLock(repoConfig, client, runLockTestCommit)
+	assert.NoError(t, err)
+	assert.False(t, acquired)
+	assert.NotEmpty(t, runId)
+
+	// The commit is already locked, so no lock comment is added
+	client = mockVcsClient(t)
+	client.EXPECT().ListPullRequestComments(context.Background(), "jfrog", "frogbot", 1).Return(
+		[]vcsclient.CommentInfo{createRunLockTestComment(runLockTestCommit, "aa", now.Add(-time.Minute))}, nil)
+	runId, acquired, err = acquireRunLock(repoConfig, client, runLockTestCommit)
+	assert.NoError(t, err)
+	assert.False(t, acquired)
+	assert.Empty(t, runId)
+
+	client = mockVcsClient(t)
+	client.EXPECT().ListPullRequestComments(context.Background(), "jfrog", "frogbot", 1).Return(nil, errors.New("forbidden"))
+	_, _, err = acquireRunLock(repoConfig, client, runLockTestCommit)
+	assert.EqualError(t, err, "forbidden")
+}
+
+func TestReleaseRunLock(t *testing.T) {
+	repoConfig := newTestRepoConfig(vcsutils.GitHub, "")
+	repoConfig.PullRequestID = 1
+	now := time.Now()
+	ownComment := createRunLockTestComment(runLockTestCommit, "aa", now)
+	ownComment.ID = 11
+	otherComment := createRunLockTestComment(runLockTestCommit, "bb", now)
+	otherComment.ID = 12
+	client := mockVcsClient(t)
+	client.EXPECT().ListPullRequestComments(context.Background(), "jfrog", "frogbot", 1).Return([]vcsclient.CommentInfo{{ID: 10, Content: "LGTM"}, ownComment, otherComment}, nil)
+	var deletedComments []int64
+	releaseRunLock(repoConfig, client, "aa", func(commentId int64) error {
+		deletedComments = append(deletedComments, commentId)
+		return nil
+	})
+	// Only the lock comment of this run is deleted
+	assert.Equal(t, []int64{11}, deletedComments)
+
+	// The lock comments are kept if the git provider isn't supported
+	repoConfig.GitProvider = vcsutils.BitbucketServer
+	deleteComment, err := getCommentDeleter(repoConfig)
+	assert.NoError(t, err)
+	assert.Nil(t, deleteComment)
+	releaseRunLock(repoConfig, mockVcsClient(t), "aa", nil)
+}
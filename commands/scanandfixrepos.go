@@ -5,8 +5,8 @@ import (
 	"fmt"
 	"github.com/jfrog/frogbot/commands/utils"
 	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-client-go/utils/log"
 	"path/filepath"
-	"strings"
 )
 
 type ScanAndFixRepositories struct {
@@ -17,23 +17,23 @@ type ScanAndFixRepositories struct {
 }
 
 func (cmd ScanAndFixRepositories) Run(configAggregator utils.FrogbotConfigAggregator, client vcsclient.VcsClient) error {
-	var errList strings.Builder
-	for repoNum := range configAggregator {
-		err := cmd.scanAndFixSingleRepository(&configAggregator[repoNum], client)
-		if err != nil {
-			errList.WriteString(fmt.Sprintf("repository %s returned the following error: \n%s\n", configAggregator[repoNum].RepoName, err.Error()))
-		}
-	}
-
-	if errList.String() != "" {
-		return errors.New(errList.String())
-	}
-	return nil
+	summary := newOrgSummary(configAggregator)
+	err := utils.RunOnRepositories(configAggregator, client, func(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient) error {
+		err := cmd.scanAndFixSingleRepository(repoConfig, client, summary)
+		summary.addRepositoryError(repoConfig.RepoName, err)
+		return err
+	})
+	// The summary is published even if some of the repositories failed
+	return errors.Join(err, summary.publish())
 }
 
-func (cmd ScanAndFixRepositories) scanAndFixSingleRepository(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient) error {
+func (cmd ScanAndFixRepositories) scanAndFixSingleRepository(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient, summary *orgSummary) error {
 	for _, branch := range repoConfig.Branches {
-		err := cmd.downloadAndRunScanAndFix(client, branch, repoConfig)
+		if !repoConfig.IsFixPRBranch(branch) {
+			log.Info(fmt.Sprintf(skippedFixPRBranchMessage, branch))
+			continue
+		}
+		err := cmd.downloadAndRunScanAndFix(client, branch, repoConfig, summary)
 		if err != nil {
 			return err
 		}
@@ -42,7 +42,7 @@ func (cmd ScanAndFixRepositories) scanAndFixSingleRepository(repoConfig *utils.F
 	return nil
 }
 
-func (cmd ScanAndFixRepositories) downloadAndRunScanAndFix(client vcsclient.VcsClient, branch string, repoConfig *utils.FrogbotRepoConfig) (err error) {
+func (cmd ScanAndFixRepositories) downloadAndRunScanAndFix(client vcsclient.VcsClient, branch string, repoConfig *utils.FrogbotRepoConfig, summary *orgSummary) (err error) {
 	wd, cleanup, err := utils.DownloadRepoToTempDir(client, branch, &repoConfig.Git)
 	if err != nil {
 		return err
@@ -54,7 +54,7 @@ func (cmd ScanAndFixRepositories) downloadAndRunScanAndFix(client vcsclient.VcsC
 		}
 	}()
 
-	restoreDir, err := utils.Chdir(wd)
+	restoreDir, err := utils.ChdirExclusively(wd)
 	if err != nil {
 		return err
 	}
@@ -65,6 +65,6 @@ func (cmd ScanAndFixRepositories) downloadAndRunScanAndFix(client vcsclient.VcsC
 		}
 	}()
 
-	var cfp = CreateFixPullRequestsCmd{dryRun: cmd.dryRun, dryRunRepoPath: filepath.Join(cmd.dryRunRepoPath, repoConfig.RepoName)}
+	var cfp = CreateFixPullRequestsCmd{dryRun: cmd.dryRun, dryRunRepoPath: filepath.Join(cmd.dryRunRepoPath, repoConfig.RepoName), orgSummary: summary}
 	return cfp.scanAndFixRepository(repoConfig, client, branch)
 }
@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+)
+
+// The scan-history baseline of a pull request, which holds the last scan of the source branch which reported no issues
+type scanHistoryBaseline struct {
+	storage utils.ScanHistoryStorage
+	key     string
+	// The last clean scan of the source branch, or nil if the branch has no clean scan in the scan history
+	entry *utils.ScanHistoryEntry
+}
+
+// loadScanHistoryBaseline loads the last clean scan of the source branch of the pull request, if the new issues are compared against the scan history.
+// A failure to read the scan history doesn't fail the scan, since the target branch is scanned instead.
+func loadScanHistoryBaseline(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient) *scanHistoryBaseline {
+	if repoConfig.NewIssuesBaseline != utils.ScanHistoryBaseline || repoConfig.IncludeAllVulnerabilities {
+		return nil
+	}
+	sourceBranch, err := getPullRequestSourceBranch(repoConfig, client)
+	if err != nil {
+		log.Warn("couldn't find the source branch of the pull request, so the target branch is scanned instead of using the scan history:", err.Error())
+		return nil
+	}
+	storage, err := utils.NewScanHistoryStorage(&repoConfig.Params)
+	if err != nil {
+		log.Warn(err.Error())
+		return nil
+	}
+	baseline := &scanHistoryBaseline{storage: storage, key: utils.GetScanHistoryKey(&repoConfig.Git, sourceBranch)}
+	if baseline.entry, err = storage.Load(baseline.key); err != nil {
+		log.Warn("couldn't read the scan history, so the target branch is scanned instead:", err.Error())
+	} else if baseline.entry == nil {
+		log.Info(fmt.Sprintf("The %s branch has no clean scan in the scan history, so the target branch is scanned", sourceBranch))
+	} else {
+		log.Info(fmt.Sprintf("Comparing the pull request against the last clean scan of the %s branch, from %s", sourceBranch, baseline.entry.ScannedAt.Format(time.RFC3339)))
+	}
+	return baseline
+}
+
+func getPullRequestSourceBranch(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient) (string, error) {
+	pullRequests, err := client.ListOpenPullRequests(context.Background(), repoConfig.RepoOwner, repoConfig.RepoName)
+	if err != nil {
+		return "", err
+	}
+	for _, pullRequest := range pullRequests {
+		if pullRequest.ID == int64(repoConfig.PullRequestID) {
+			return pullRequest.Source.Name, nil
+		}
+	}
+	return "", fmt.Errorf("pull request %d isn't open", repoConfig.PullRequestID)
+}
+
+// Return the scan of the project in the last clean scan of the source branch, if it was scanned in it
+func (baseline *scanHistoryBaseline) getProjectScan(project *utils.Project) ([]services.ScanResponse, bool) {
+	if baseline == nil || baseline.entry == nil {
+		return nil, false
+	}
+	scan, exists := baseline.entry.Projects[getScanHistoryProjectKey(project)]
+	return scan, exists
+}
+
+// save persists the scan of the source branch as its new baseline, if the scan reported no issues.
+// The scan history is an addition to the scan, so a failure to save it doesn't fail the scan.
+func (baseline *scanHistoryBaseline) save(results *auditResults) {
+	if baseline == nil || results.issuesCount() > 0 {
+		return
+	}
+	entry := &utils.ScanHistoryEntry{CommitSha: utils.GetHeadCommitSha("."), ScannedAt: time.Now(), Projects: results.projectScans}
+	if err := baseline.storage.Save(baseline.key, entry); err != nil {
+		log.Warn("couldn't save the scan to the scan history:", err.Error())
+	}
+}
+
+// Projects are identified in the scan history by their working dirs
+func getScanHistoryProjectKey(project *utils.Project) string {
+	if len(project.WorkingDirs) == 0 {
+		return utils.RootDir
+	}
+	return strings.Join(project.WorkingDirs, ",")
+}
+
+// Keep the scan of the project, to save it in the scan history
+func (results *auditResults) addProjectScan(project *utils.Project, scan []services.ScanResponse) {
+	if results.scanHistory == nil {
+		return
+	}
+	if results.projectScans == nil {
+		results.projectScans = make(map[string][]services.ScanResponse)
+	}
+	results.projectScans[getScanHistoryProjectKey(project)] = scan
+}
@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanHistoryBaseline(t *testing.T) {
+	repoConfig := &utils.FrogbotRepoConfig{Params: utils.Params{
+		Git:               utils.Git{GitProvider: vcsutils.GitHub, RepoOwner: "jfrog", RepoName: "frogbot", PullRequestID: 7},
+		NewIssuesBaseline: utils.ScanHistoryBaseline,
+		ScanHistoryDir:    t.TempDir(),
+	}}
+	pullRequests := []vcsclient.PullRequestInfo{
+		{ID: 6, Source: vcsclient.BranchInfo{Name: "other"}},
+		{ID: 7, Source: vcsclient.BranchInfo{Name: "feature"}, Target: vcsclient.BranchInfo{Name: "main"}},
+	}
+	client := mockVcsClient(t)
+	client.EXPECT().ListOpenPullRequests(context.Background(), "jfrog", "frogbot").Return(pullRequests, nil).Times(3)
+
+	// The branch has no clean scan yet
+	baseline := loadScanHistoryBaseline(repoConfig, client)
+	if !assert.NotNil(t, baseline) {
+		return
+	}
+	project := &utils.Project{WorkingDirs: []string{"a", "b"}}
+	_, found := baseline.getProjectScan(project)
+	assert.False(t, found)
+
+	// A scan with issues isn't saved
+	scan := []services.ScanResponse{{ScanId: "scan-1"}}
+	results := &auditResults{scanHistory: baseline, vulnerabilitiesRows: []formats.VulnerabilityOrViolationRow{{IssueId: "XRAY-1"}}}
+	results.addProjectScan(project, scan)
+	baseline.save(results)
+	_, found = loadScanHistoryBaseline(repoConfig, client).getProjectScan(project)
+	assert.False(t, found)
+
+	// A clean scan is the baseline of the next scans of the branch
+	results.vulnerabilitiesRows = nil
+	baseline.save(results)
+	baseline = loadScanHistoryBaseline(repoConfig, client)
+	previousScan, found := baseline.getProjectScan(project)
+	assert.True(t, found)
+	assert.Equal(t, scan, previousScan)
+	assert.WithinDuration(t, time.Now(), baseline.entry.ScannedAt, time.Minute)
+	_, found = baseline.getProjectScan(&utils.Project{})
+	assert.False(t, found)
+
+	// The target branch is the default baseline
+	repoConfig.NewIssuesBaseline = ""
+	assert.Nil(t, loadScanHistoryBaseline(repoConfig, client))
+	_, found = (*scanHistoryBaseline)(nil).getProjectScan(project)
+	assert.False(t, found)
+}
+
+func TestLoadScanHistoryBaselineClosedPullRequest(t *testing.T) {
+	repoConfig := &utils.FrogbotRepoConfig{Params: utils.Params{
+		Git:               utils.Git{RepoOwner: "jfrog", RepoName: "frogbot", PullRequestID: 7},
+		NewIssuesBaseline: utils.ScanHistoryBaseline,
+		ScanHistoryDir:    t.TempDir(),
+	}}
+	client := mockVcsClient(t)
+	client.EXPECT().ListOpenPullRequests(context.Background(), "jfrog", "frogbot").Return([]vcsclient.PullRequestInfo{}, nil)
+	// The target branch is scanned instead
+	assert.Nil(t, loadScanHistoryBaseline(repoConfig, client))
+}
@@ -0,0 +1,154 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	// Local directory scan output formats
+	MarkdownFormat   = "markdown"
+	SimplifiedFormat = "simplified"
+	JsonFormat       = "json"
+
+	unsupportedFormatErr = "the output format '%s' is not supported. The supported formats are: " + MarkdownFormat + ", " + SimplifiedFormat + " and " + JsonFormat
+)
+
+// ScanLocalDirectoryCmd scans a local directory without any Git operation, and prints the results or writes them to a file.
+type ScanLocalDirectoryCmd struct {
+	// The path of the directory to scan. If empty, the current working directory is scanned.
+	Path string
+	// The output format of the results
+	Format string
+	// The path of a file to write the results to. If empty, the results are printed to the standard output.
+	OutputFile string
+	// The path of a file to write the CycloneDX SBOM of the scanned dependencies to. If empty, the SBOM isn't created.
+	SbomOutputFile string
+}
+
+// Run the local directory scan. The VCS client isn't used, and may be nil.
+func (cmd ScanLocalDirectoryCmd) Run(configAggregator utils.FrogbotConfigAggregator, _ vcsclient.VcsClient) (err error) {
+	if err = utils.ValidateSingleRepoConfiguration(&configAggregator); err != nil {
+		return err
+	}
+	if !isSupportedLocalScanFormat(cmd.Format) {
+		return fmt.Errorf(unsupportedFormatErr, cmd.Format)
+	}
+	// The output files paths are relative to the original working directory
+	if cmd.OutputFile != "" {
+		if cmd.OutputFile, err = filepath.Abs(cmd.OutputFile); err != nil {
+			return err
+		}
+	}
+	if cmd.SbomOutputFile != "" {
+		if cmd.SbomOutputFile, err = filepath.Abs(cmd.SbomOutputFile); err != nil {
+			return err
+		}
+	}
+	repoConfig := &configAggregator[0]
+	if cmd.Path != "" {
+		var restoreDir func() error
+		if restoreDir, err = utils.Chdir(cmd.Path); err != nil {
+			return err
+		}
+		defer func() {
+			e := restoreDir()
+			if err == nil {
+				err = e
+			}
+		}()
+	}
+
+	results, err := auditLocalDirectory(repoConfig)
+	if err != nil {
+		return &ScanExecutionError{Err: err}
+	}
+	scanMetrics.addScanResults(repoConfig.RepoName, results)
+	if cmd.SbomOutputFile != "" {
+		if err = utils.ExportCycloneDxSbom(results.scanResults, cmd.SbomOutputFile); err != nil {
+			return err
+		}
+	}
+	output, err := formatLocalScanResults(results.vulnerabilitiesRows, cmd.Format)
+	if err != nil {
+		return err
+	}
+	if err = cmd.writeOutput(output); err != nil {
+		return err
+	}
+	for _, ignoredIssue := range getExpiringIgnoredIssues(repoConfig) {
+		log.Warn(ignoredIssue.Id, "is ignored until", ignoredIssue.Until.Format("2006-01-02")+". It will be reported again after this date")
+	}
+
+	// Fail the Frogbot task, if a security issue is found and Frogbot isn't configured to avoid the failure.
+	if repoConfig.ShouldFail(results.failingIssuesFound) {
+		err = &SecurityIssuesFoundError{}
+	}
+	return err
+}
+
+// Audit the current working directory and return all the issues found in it, along with the raw scan results
+func auditLocalDirectory(repoConfig *utils.FrogbotRepoConfig) (*auditResults, error) {
+	results := &auditResults{onlyWithExploits: repoConfig.OnlyWithExploits, cvssPolicy: repoConfig.CvssPolicy, unknownSeverityAs: repoConfig.UnknownSeverityAs}
+	for projectIndex := range repoConfig.Projects {
+		project := &repoConfig.Projects[projectIndex]
+		xrayScanParams := createXrayScanParams(project.Watches, repoConfig.JFrogProjectKey, repoConfig.ScanMode)
+		currentScan, isMultipleRoot, err := auditSource(xrayScanParams, *project, &repoConfig.Server)
+		if err != nil {
+			return nil, err
+		}
+		allIssuesRows, err := createAllIssuesRows(currentScan, isMultipleRoot)
+		if err != nil {
+			return nil, err
+		}
+		results.addProjectIssues(project, allIssuesRows)
+		results.scanResults = append(results.scanResults, currentScan...)
+	}
+	log.Info("Xray scan completed")
+	return results, nil
+}
+
+func isSupportedLocalScanFormat(format string) bool {
+	switch format {
+	case "", MarkdownFormat, SimplifiedFormat, JsonFormat:
+		return true
+	}
+	return false
+}
+
+func formatLocalScanResults(vulnerabilitiesRows []formats.VulnerabilityOrViolationRow, format string) (string, error) {
+	switch format {
+	case "", MarkdownFormat:
+		return createPullRequestMessage(vulnerabilitiesRows, &utils.StandardOutput{}), nil
+	case SimplifiedFormat:
+		return createPullRequestMessage(vulnerabilitiesRows, &utils.SimplifiedOutput{}), nil
+	case JsonFormat:
+		if vulnerabilitiesRows == nil {
+			vulnerabilitiesRows = []formats.VulnerabilityOrViolationRow{}
+		}
+		content, err := json.MarshalIndent(vulnerabilitiesRows, "", "  ")
+		return string(content), err
+	}
+	return "", fmt.Errorf(unsupportedFormatErr, format)
+}
+
+func (cmd ScanLocalDirectoryCmd) writeOutput(output string) error {
+	return writeCommandOutput(output, cmd.OutputFile, "scan results")
+}
+
+// Print the output of the command, or write it to the output file, if one is set
+func writeCommandOutput(output, outputFile, description string) error {
+	if outputFile == "" {
+		_, err := fmt.Fprintln(os.Stdout, output)
+		return err
+	}
+	log.Info("Writing the", description, "to", outputFile)
+	return os.WriteFile(outputFile, []byte(output), 0600)
+}
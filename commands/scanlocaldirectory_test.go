@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+var localScanTestRows = []formats.VulnerabilityOrViolationRow{{
+	Severity:                  "High",
+	ImpactedDependencyName:    "github.com/nats-io/nats-streaming-server",
+	ImpactedDependencyVersion: "v0.21.0",
+	FixedVersions:             []string{"[0.24.1]"},
+	Cves:                      []formats.CveRow{{Id: "CVE-2022-24450"}},
+}}
+
+func TestFormatLocalScanResults(t *testing.T) {
+	output, err := formatLocalScanResults(localScanTestRows, MarkdownFormat)
+	assert.NoError(t, err)
+	assert.Equal(t, createPullRequestMessage(localScanTestRows, &utils.StandardOutput{}), output)
+
+	output, err = formatLocalScanResults(localScanTestRows, "")
+	assert.NoError(t, err)
+	assert.Equal(t, createPullRequestMessage(localScanTestRows, &utils.StandardOutput{}), output)
+
+	output, err = formatLocalScanResults(localScanTestRows, SimplifiedFormat)
+	assert.NoError(t, err)
+	assert.Equal(t, createPullRequestMessage(localScanTestRows, &utils.SimplifiedOutput{}), output)
+
+	output, err = formatLocalScanResults(localScanTestRows, JsonFormat)
+	assert.NoError(t, err)
+	var rows []formats.VulnerabilityOrViolationRow
+	assert.NoError(t, json.Unmarshal([]byte(output), &rows))
+	assert.Equal(t, localScanTestRows, rows)
+
+	output, err = formatLocalScanResults(nil, JsonFormat)
+	assert.NoError(t, err)
+	assert.Equal(t, "[]", output)
+}
+
+func TestScanLocalDirectoryUnsupportedFormat(t *testing.T) {
+	cmd := ScanLocalDirectoryCmd{Format: "xml"}
+	err := cmd.Run(utils.FrogbotConfigAggregator{{}}, nil)
+	assert.EqualError(t, err, "the output format 'xml' is not supported. The supported formats are: markdown, simplified and json")
+}
+
+func TestScanLocalDirectoryWriteOutput(t *testing.T) {
+	outputFile := filepath.Join(t.TempDir(), "results.md")
+	cmd := ScanLocalDirectoryCmd{OutputFile: outputFile}
+	assert.NoError(t, cmd.writeOutput("results"))
+	content, err := os.ReadFile(outputFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "results", string(content))
+}
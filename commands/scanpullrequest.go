@@ -3,10 +3,14 @@ package commands
 import (
 	"context"
 	"errors"
+	"fmt"
 	coreconfig "github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/jfrog/frogbot/commands/utils"
 	"github.com/jfrog/froggit-go/vcsclient"
@@ -22,20 +26,54 @@ const (
 	securityIssueFoundErr    = "issues were detected by Frogbot\n You can avoid marking the Frogbot scan as failed by setting failOnSecurityIssues to false in the " + utils.FrogbotConfigFile + " file"
 	installationCmdFailedErr = "Couldn't run the installation command on the base branch. Assuming new project in the source branch: "
 	noGitHubEnvErr           = "frogbot did not scan this PR, because a GitHub Environment named 'frogbot' does not exist. Please refer to the Frogbot documentation for instructions on how to create the Environment"
+	unchangedIssuesSummary   = "🐸 Frogbot: %d issues, unchanged since %s"
+	unchangedNoIssuesSummary = "🐸 Frogbot: no issues, unchanged since %s"
+	xrayScansNote            = "\n\n🔍 **View in Xray:** %s"
+	commitShaPlaceholder     = "${COMMIT_SHA}"
+	timestampPlaceholder     = "${TIMESTAMP}"
+	partialScanErr           = "the scan of the following working dirs failed: %s\n You can avoid marking the Frogbot scan as failed due to these working dirs by setting failOnPartialScan to false in the " + utils.FrogbotConfigFile + " file"
+	scanFailedErr            = "the Xray scan failed: %w\n You can avoid marking the Frogbot scan as failed due to scan errors by setting failOnScanError to false in the " + utils.FrogbotConfigFile + " file"
+	scanErrorComment         = "## ⚠️ Frogbot couldn't complete the scan\n\nThe Xray scan of this pull request failed, so it may include security issues which weren't reported.\n\n```\n%s\n```"
+	pathIgnoresNote          = "\n\n📁 The issues found in the paths which match the pathIgnores patterns (%s) are shown, but don't fail the scan."
+	failureCommentNote       = "\n\n🚫 %s"
 	noGitHubEnvReviewersErr  = "frogbot did not scan this PR, because the existing GitHub Environment named 'frogbot' doesn't have reviewers selected. Please refer to the Frogbot documentation for instructions on how to create the Environment"
 )
 
+// Parts of the errors returned by the git providers when the comment exceeds their maximum length
+var commentTooLongReasons = []string{"too long", "too large", "maximum is", "exceeds the maximum"}
+
 type ScanPullRequestCmd struct{}
 
 // Run ScanPullRequest method only works for single repository scan.
 // Therefore, the first repository config represents the repository on which Frogbot runs, and it is the only one that matters.
-func (cmd ScanPullRequestCmd) Run(configAggregator utils.FrogbotConfigAggregator, client vcsclient.VcsClient) error {
-	if err := utils.ValidateSingleRepoConfiguration(&configAggregator); err != nil {
+func (cmd ScanPullRequestCmd) Run(configAggregator utils.FrogbotConfigAggregator, client vcsclient.VcsClient) (err error) {
+	if err = utils.ValidateSingleRepoConfiguration(&configAggregator); err != nil {
 		return err
 	}
 	repoConfig := &(configAggregator)[0]
 	if repoConfig.GitProvider == vcsutils.GitHub {
-		if err := verifyGitHubFrogbotEnvironment(client, repoConfig); err != nil {
+		if err = verifyGitHubFrogbotEnvironment(client, repoConfig); err != nil {
+			return err
+		}
+		// The comments are added to the pull request in the base repository, while the code is downloaded from the fork
+		var cleanup func() error
+		if cleanup, err = checkoutForkPullRequestHead(repoConfig, client); err != nil {
+			return err
+		}
+		defer func() {
+			e := cleanup()
+			if err == nil {
+				err = e
+			}
+		}()
+	}
+	if utils.IsConfigFromRepo() {
+		// The source branch of the pull request is checked out in the working directory
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		if err = repoConfig.ApplyRepoConfig(wd); err != nil {
 			return err
 		}
 	}
@@ -46,63 +84,660 @@ func (cmd ScanPullRequestCmd) Run(configAggregator utils.FrogbotConfigAggregator
 // a. Audit the dependencies of the source and the target branches.
 // b. Compare the vulnerabilities found in source and target branches, and show only the new vulnerabilities added by the pull request.
 // Otherwise, only the source branch is scanned and all found vulnerabilities are being displayed.
-func scanPullRequest(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient) error {
+func scanPullRequest(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient) (err error) {
 	// Validate scan params
 	if len(repoConfig.Branches) == 0 {
 		return &utils.ErrMissingEnv{VariableName: utils.GitBaseBranchEnv}
 	}
+	if shouldSkipDraftPullRequest(repoConfig) {
+		return nil
+	}
+	if repoConfig.PreventDuplicateRuns {
+		scan, releaseRunLock := shouldScanCommit(repoConfig, client)
+		defer releaseRunLock()
+		if !scan {
+			return nil
+		}
+	}
+	startTime := time.Now()
+	statusReporter := newCommitStatusReporter(repoConfig, client)
+	statusReporter.setPending()
+	defer statusReporter.setErrorIfUnfinished()
+
+	// The target branch is downloaded once, and is shared by the scans of the pull request
+	targetBranch := newTargetBranchCheckout(repoConfig, client)
+	defer func() {
+		e := targetBranch.remove()
+		if err == nil {
+			err = e
+		}
+	}()
 
 	// Audit PR code
-	vulnerabilitiesRows, err := auditPullRequest(repoConfig, client)
+	results, err := auditPullRequest(repoConfig, client, targetBranch)
 	if err != nil {
-		return err
+		return reportScanError(repoConfig, client, err)
+	}
+	if repoConfig.ScanSecrets {
+		if results.secrets, err = auditPullRequestSecrets(targetBranch); err != nil {
+			return reportScanError(repoConfig, client, fmt.Errorf("the secrets scan failed: %w", err))
+		}
+		results.addSecretsGating(repoConfig)
+	}
+	if repoConfig.WarnUnpinned {
+		// The unpinned dependencies are an advisory only, so a failure to find them doesn't fail the scan
+		if results.unpinnedDependencies, err = auditPullRequestUnpinnedDependencies(); err != nil {
+			log.Warn("couldn't check the manifests for unpinned dependencies:", err.Error())
+			err = nil
+		}
 	}
+	if repoConfig.AnnotateDiff {
+		results.addDiffAnnotations(repoConfig, targetBranch)
+	}
+	if len(results.vulnerabilitiesRows) > 0 {
+		results.addVulnerabilitiesAge(repoConfig, time.Now())
+	}
+	scanMetrics.addScanResults(repoConfig.RepoName, results)
+	// Create the notes, which follow the issues tables
+	notes := createIntroducedViaNotes(results.vulnerabilitiesRows, results.introducingDependencies) +
+		createDiffAnnotationsNote(results.vulnerabilitiesRows, results.diffAnnotations) +
+		createSubmodulesNote(results.vulnerabilitiesRows, results.submoduleIssues) +
+		createVulnerabilitiesAgeNote(results.vulnerabilitiesRows, results.publishedDates, results.olderVulnerabilitiesCount, repoConfig.FailOnVulnsOlderThanDays, time.Now()) +
+		createRemediationCommandsNotes(results.vulnerabilitiesRows, results.remediationCommands) +
+		createFixChainsNotes(results.vulnerabilitiesRows, results.fixChains) +
+		createResearchNotes(results.vulnerabilitiesRows, repoConfig.OutputWriter) +
+		createRiskChangesNotes(results.riskChanges, repoConfig.SeverityColors) +
+		utils.GetIgnoredIssuesExpiryNote(getExpiringIgnoredIssues(repoConfig)) +
+		createPathIgnoresNote(repoConfig, results) +
+		createUnpinnedDependenciesNote(results.unpinnedDependencies) +
+		createPolicyViolationsNote(results.vulnerabilitiesRows, results.violationsPolicies, results.policyWatches) +
+		createPartialScanNote(results.failedWorkingDirs) +
+		createInlineIgnoresNote(results.suppressedIssues)
+	if repoConfig.ShowXrayScanLink {
+		notes += createXrayScansNote(results.xrayScans)
+	}
+	notes += createFailureCommentNote(repoConfig, results)
 
-	// Create pull request message
-	message := createPullRequestMessage(vulnerabilitiesRows, repoConfig.OutputWriter)
+	// Add comment to the pull request, unless the results are written to the GitHub Actions step summary instead, or the pull request is no longer open
+	commented := writeStepSummary(repoConfig, results, notes)
+	pullRequestClosed := !commented && isPullRequestClosed(repoConfig)
+	if commented {
+		log.Info("The scan results were written to the GitHub Actions step summary. Skipping the pull request comment")
+	} else if pullRequestClosed {
+		log.Info(fmt.Sprintf("Pull request %d is no longer open. Skipping the pull request comment. The scan results are:\n%s", repoConfig.PullRequestID, createCommentMessage(repoConfig, results, notes)))
+		commented = true
+	} else if repoConfig.CommentStyle == utils.StatusCommentStyle {
+		if err = commentStatus(repoConfig, client, results, notes); err != nil {
+			return err
+		}
+		commented = true
+	} else if repoConfig.SplitCommentsBySeverity {
+		if commented, err = commentBySeverityTiers(repoConfig, client, results, notes); err != nil {
+			return err
+		}
+	}
+	if !commented {
+		if err = commentAllResults(repoConfig, client, results, notes); err != nil {
+			return err
+		}
+	}
 
-	// Add comment to the pull request
-	if err = client.AddPullRequestComment(context.Background(), repoConfig.RepoOwner, repoConfig.RepoName, message, repoConfig.PullRequestID); err != nil {
-		return errors.New("couldn't add pull request comment: " + err.Error())
+	if !pullRequestClosed {
+		results.postDiffReviewComments(repoConfig)
+	}
+	if repoConfig.PostScanCommand != "" {
+		runPostScanCommand(repoConfig, results)
+	}
+	if repoConfig.OtelEndpoint != "" {
+		exportOtelTrace(repoConfig, results, startTime)
+	}
+	results.scanHistory.save(results)
+
+	if repoConfig.GitLabApprovalGate && repoConfig.GitProvider == vcsutils.GitLab && !pullRequestClosed {
+		if err = applyGitLabApprovalGate(repoConfig, results.issuesCount() > 0); err != nil {
+			return errors.New("couldn't update the merge request approval: " + err.Error())
+		}
 	}
 
 	// Fail the Frogbot task, if a security issue is found and Frogbot isn't configured to avoid the failure.
-	if repoConfig.FailOnSecurityIssues != nil && *repoConfig.FailOnSecurityIssues && len(vulnerabilitiesRows) > 0 {
-		err = errors.New(securityIssueFoundErr)
+	shouldFail := repoConfig.ShouldFail(results.failingIssuesFound)
+	if shouldFail {
+		statusReporter.setScanResult(shouldFail, results.issuesCount())
+		return &SecurityIssuesFoundError{}
+	}
+	// The issues of the working dirs which were scanned are reported, but the skipped working dirs may hide issues, so the scan fails
+	if err = getPartialScanError(repoConfig, results.failedWorkingDirs); err != nil {
+		return err
+	}
+	statusReporter.setScanResult(shouldFail, results.issuesCount())
+	return nil
+}
+
+// commentAllResults adds a single comment with all the scan results to the pull request
+func commentAllResults(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient, results *auditResults, notes string) (err error) {
+	issuesCount := results.issuesCount()
+	message := createCommentMessage(repoConfig, results, notes)
+	// Committed secrets are always reported in full, so that they aren't missed in a summary
+	if repoConfig.SummarizeUnchangedResults && len(results.secrets) == 0 {
+		if message, err = summarizeUnchangedResults(repoConfig, client, results.vulnerabilitiesRows, results.iacRows, message); err != nil {
+			return err
+		}
+	}
+	// A clean scan isn't replied to the results thread either, if its comment is suppressed
+	if repoConfig.ThreadedUpdates && !(issuesCount == 0 && repoConfig.SuppressCleanComment) {
+		var threaded bool
+		if message, threaded, err = commentThreadedUpdate(repoConfig, results, message); err != nil || threaded {
+			return err
+		}
+	}
+	if repoConfig.CommentOnlyOnChange {
+		var changed bool
+		if message, changed, err = addContentMarker(repoConfig, client, results, message); err != nil || !changed {
+			return err
+		}
+	}
+	return commentScanResults(repoConfig, client, issuesCount, message)
+}
+
+// addContentMarker appends the content marker to the comment, and returns false if the newest comment with a content marker has the same findings,
+// so that the existing comment is kept rather than posting the unchanged results again.
+// The findings are hashed rather than the comment, since the comment changes between scans of the same findings, such as with the Xray scan IDs.
+func addContentMarker(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient, results *auditResults, message string) (string, bool, error) {
+	contentHash, err := results.getFindingsHash()
+	if err != nil {
+		return "", false, err
+	}
+	message += utils.GetContentMarker(contentHash)
+	comments, err := client.ListPullRequestComments(context.Background(), repoConfig.RepoOwner, repoConfig.RepoName, repoConfig.PullRequestID)
+	if err != nil {
+		// The comment is posted, if the previous comments can't be read
+		log.Warn("couldn't read the pull request comments:", err.Error())
+		return message, true, nil
+	}
+	sort.Slice(comments, func(i, j int) bool {
+		return comments[i].Created.After(comments[j].Created)
+	})
+	for _, comment := range comments {
+		if previousHash, found := utils.ParseContentMarker(comment.Content); found {
+			if previousHash == contentHash {
+				log.Info("The findings are unchanged since the previous comment. Keeping the existing comment, since commentOnlyOnChange is set")
+				return message, false, nil
+			}
+			break
+		}
+	}
+	return message, true, nil
+}
+
+// commentScanResults adds the scan results comment to the pull request, unless no issues were found and the clean scan comment is suppressed
+func commentScanResults(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient, issuesCount int, message string) error {
+	if issuesCount == 0 && repoConfig.SuppressCleanComment {
+		log.Info("No issues were found. Skipping the pull request comment, since suppressCleanComment is set")
+		return nil
+	}
+	if err := addPullRequestComment(repoConfig, client, message); err != nil {
+		if !isPermissionError(err) {
+			return &VcsError{Err: fmt.Errorf("couldn't add pull request comment: %w", err)}
+		}
+		// The scan results are still logged, and the task fails according to the results
+		log.Warn("the token isn't permitted to comment on the pull request, which is expected for pull requests from forks. The scan results are:\n" + message)
+	}
+	return nil
+}
+
+// reportScanError adds a comment stating that the scan failed, so that a failed scan isn't mistaken for a clean one.
+// The scan error fails the Frogbot task, unless Frogbot is configured to avoid the failure.
+func reportScanError(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient, scanErr error) error {
+	if err := addPullRequestComment(repoConfig, client, fmt.Sprintf(scanErrorComment, scanErr.Error())); err != nil {
+		log.Warn("couldn't add the scan error comment to the pull request:", err.Error())
+	}
+	err := &ScanExecutionError{Err: fmt.Errorf(scanFailedErr, scanErr)}
+	if repoConfig.ShouldFailOnScanError() {
+		return err
+	}
+	log.Warn(err.Error())
+	return nil
+}
+
+// Add the comment to the pull request, with the botName header, truncated to the maximum comment length.
+// If the git provider rejects the comment due to its length, the comment is truncated to half of the length and added again.
+func addPullRequestComment(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient, message string) error {
+	maxLength := utils.GetMaxCommentLength(repoConfig.GitProvider, repoConfig.MaxCommentLength)
+	comment := utils.TruncateComment(utils.GetBotHeader(repoConfig.BotName)+message, maxLength)
+	err := client.AddPullRequestComment(context.Background(), repoConfig.RepoOwner, repoConfig.RepoName, comment, repoConfig.PullRequestID)
+	if err == nil || !isCommentTooLongError(err) {
+		return err
+	}
+	log.Warn("the pull request comment was rejected due to its length. Adding a shorter comment:", err.Error())
+	comment = utils.TruncateComment(comment, len([]rune(comment))/2)
+	return client.AddPullRequestComment(context.Background(), repoConfig.RepoOwner, repoConfig.RepoName, comment, repoConfig.PullRequestID)
+}
+
+func isCommentTooLongError(err error) bool {
+	errMessage := strings.ToLower(err.Error())
+	for _, reason := range commentTooLongReasons {
+		if strings.Contains(errMessage, reason) {
+			return true
+		}
+	}
+	return false
+}
+
+// Create the severity legend of the issues table, and the note describing the configured severity policy
+func createSeverityNotes(issuesFound bool, scan *utils.Scan, writer utils.OutputWriter) string {
+	var notes string
+	if issuesFound {
+		notes = writer.SeverityLegend()
+	}
+	return notes + scan.GetSeverityPolicyNote()
+}
+
+// summarizeUnchangedResults replaces the message with a compact summary, if the issues are identical to the issues in the previous Frogbot comment.
+// The hash of the issues is tracked in a hidden marker, appended to the message.
+func summarizeUnchangedResults(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient, vulnerabilitiesRows []formats.VulnerabilityOrViolationRow, iacRows []utils.IacRow, message string) (string, error) {
+	issuesHash, err := utils.GetIssuesHash(vulnerabilitiesRows, iacRows)
+	if err != nil {
+		return "", err
+	}
+	commitSha := utils.GetHeadCommitSha(".")
+	previousHash, previousSha, err := getPreviousIssuesMarker(repoConfig, client)
+	if err != nil {
+		// The full message is posted, if the previous comments can't be read
+		log.Warn("couldn't read the pull request comments:", err.Error())
+	} else if previousHash == issuesHash {
+		log.Info("The issues are unchanged since the previous scan. Adding a summary comment")
+		// Keep the commit in which this set of issues was first found
+		commitSha = previousSha
+		message = createUnchangedResultsSummary(len(vulnerabilitiesRows)+len(iacRows), commitSha)
+	}
+	return message + utils.GetIssuesMarker(issuesHash, commitSha), nil
+}
+
+// Return the issues hash and commit SHA of the newest Frogbot comment with an issues marker
+func getPreviousIssuesMarker(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient) (issuesHash, commitSha string, err error) {
+	comments, err := client.ListPullRequestComments(context.Background(), repoConfig.RepoOwner, repoConfig.RepoName, repoConfig.PullRequestID)
+	if err != nil {
+		err = &VcsError{Err: err}
+		return
+	}
+	sort.Slice(comments, func(i, j int) bool {
+		return comments[i].Created.After(comments[j].Created)
+	})
+	for _, comment := range comments {
+		var found bool
+		if issuesHash, commitSha, found = utils.ParseIssuesMarker(comment.Content); found {
+			return
+		}
+	}
+	return
+}
+
+// Note that the issues of the ignored paths don't fail the scan, if such issues were found
+func createPathIgnoresNote(repoConfig *utils.FrogbotRepoConfig, results *auditResults) string {
+	if !results.pathIgnoredIssuesFound {
+		return ""
+	}
+	return fmt.Sprintf(pathIgnoresNote, "`"+strings.Join(repoConfig.PathIgnores, "`, `")+"`")
+}
+
+// The failure comment explains the next steps to the author of the pull request, so it's added only if the scan fails
+func createFailureCommentNote(repoConfig *utils.FrogbotRepoConfig, results *auditResults) string {
+	if repoConfig.FailureComment == "" || !repoConfig.ShouldFail(results.failingIssuesFound) {
+		return ""
+	}
+	return fmt.Sprintf(failureCommentNote, repoConfig.FailureComment)
+}
+
+func createUnchangedResultsSummary(issuesCount int, commitSha string) string {
+	since := "the previous scan"
+	if commitSha != "" {
+		since = commitSha
+	}
+	if issuesCount == 0 {
+		return fmt.Sprintf(unchangedNoIssuesSummary, since)
+	}
+	return fmt.Sprintf(unchangedIssuesSummary, issuesCount, since)
+}
+
+// The issues found in all the projects of the repository
+type auditResults struct {
+	// The issues to display, filtered according to the severity policy of each project
+	vulnerabilitiesRows []formats.VulnerabilityOrViolationRow
+	// True if an issue fails the scan, according to the severity policy of its project
+	failingIssuesFound bool
+	// Maps the new issues to the chains of dependencies, starting at the direct dependencies added or updated by the pull request, through which they were introduced
+	introducingDependencies map[string][][]formats.ComponentRow
+	// The dependencies updated by the pull request to versions with new issues
+	riskChanges []dependencyRiskChange
+	// The Xray scans of the source branch, which have a scan ID or a link to the scan in Xray
+	xrayScans []services.ScanResponse
+	// The raw scan results of all the projects
+	scanResults []services.ScanResponse
+	// The misconfigurations found by the Infrastructure as Code scan, filtered according to the severity policy of each project
+	iacRows []utils.IacRow
+	// The secrets found in the lines added by the pull request
+	secrets []secretRow
+	// Maps the fixable issues to the commands which upgrade their impacted dependencies to the fix versions
+	remediationCommands map[string]string
+	// Maps the fixable transitive issues to the upgrades of the direct dependencies which pull the fixed versions
+	fixChains map[string][]fixChain
+	// Add only the issues with a known exploit
+	onlyWithExploits bool
+	// The issues below the minimal CVSS score don't fail the scan
+	cvssPolicy utils.CvssPolicy
+	// The severity the issues with an unknown severity are sorted and gated as, or ignore to drop them
+	unknownSeverityAs string
+	// True if issues were found in the working dirs which match the pathIgnores patterns, and therefore don't fail the scan
+	pathIgnoredIssuesFound bool
+	// The direct dependencies of the source branch, which are specified with version ranges rather than exact versions
+	unpinnedDependencies []unpinnedDependency
+	// Maps the violations to the Xray watches whose policies they triggered
+	violationsPolicies map[string]violationPolicy
+	// The watches configured for the scanned projects, and the watches of the violations found
+	policyWatches []string
+	// Maps the new issues to the lines added by the pull request to the manifests, which introduced their direct dependencies
+	diffAnnotations map[string][]diffAnnotation
+	// The diff annotations to post as review comments, if the provider supports review comments
+	diffReviewComments []reviewComment
+	// The new issues of the projects whose working dirs match the pathIgnores patterns
+	pathIgnoredIssues map[string]bool
+	// The dates the new issues were created in the Xray database, by their Xray issue IDs and by their CVE IDs
+	publishedDates map[string]time.Time
+	// The number of new vulnerabilities which were created in the Xray database more than failOnVulnsOlderThanDays ago
+	olderVulnerabilitiesCount int
+	// The issues omitted from the results by the ignoredDependencies entries, which are kept in the JSON results
+	suppressedIssues []utils.SuppressedIssue
+	// Maps the issues found in the git submodules to the submodule paths
+	submoduleIssues map[string]string
+	// The working dirs which couldn't be scanned, while the other working dirs of their projects were scanned
+	failedWorkingDirs []string
+	// The last clean scan of the source branch, if the new issues are compared against the scan history
+	scanHistory *scanHistoryBaseline
+	// The scans of the source branch by project, which are saved in the scan history if no issues are found
+	projectScans map[string][]services.ScanResponse
+}
+
+// The number of issues, misconfigurations and secrets found
+func (results *auditResults) issuesCount() int {
+	return len(results.vulnerabilitiesRows) + len(results.iacRows) + len(results.secrets)
+}
+
+// Return a hash which identifies the issues, misconfigurations and secrets found, regardless of their order
+func (results *auditResults) getFindingsHash() (string, error) {
+	issuesHash, err := utils.GetIssuesHash(results.vulnerabilitiesRows, results.iacRows)
+	if err != nil {
+		return "", err
+	}
+	var secrets []string
+	for _, secret := range results.secrets {
+		secrets = append(secrets, fmt.Sprintf("%s:%d:%s", secret.file, secret.line, secret.secretType))
+	}
+	sort.Strings(secrets)
+	return utils.Md5Hash(append([]string{issuesHash}, secrets...)...)
+}
+
+// Add the issues of a single project, according to its severity policy. If configured, only the issues with a known exploit are added.
+// The issues of a project whose working dirs match the pathIgnores patterns are added, but don't fail the scan.
+// The issues of the ignored dependencies, and the issues ignored by inline ignores, are kept separately, and don't fail the scan.
+// The issues below the minimal CVSS score don't fail the scan, and are added only if they aren't configured to be hidden.
+func (results *auditResults) addProjectIssues(project *utils.Project, vulnerabilitiesRows []formats.VulnerabilityOrViolationRow) {
+	vulnerabilitiesRows = setDeclaredDirectComponents(project, vulnerabilitiesRows)
+	if results.unknownSeverityAs != "" {
+		// The issues with a mapped severity are sorted among the issues of that severity
+		vulnerabilitiesRows = utils.MapUnknownSeverity(vulnerabilitiesRows, results.unknownSeverityAs)
+		sortIssuesRows(vulnerabilitiesRows)
+	}
+	vulnerabilitiesRows, suppressedIssues := project.FilterIgnoredDependencies(vulnerabilitiesRows)
+	results.suppressedIssues = append(results.suppressedIssues, suppressedIssues...)
+	vulnerabilitiesRows, suppressedIssues = filterInlineIgnoredIssues(project, vulnerabilitiesRows)
+	results.suppressedIssues = append(results.suppressedIssues, suppressedIssues...)
+	vulnerabilitiesRows = project.FilterBySeverity(project.FilterIgnoredIssues(vulnerabilitiesRows, time.Now()))
+	if results.onlyWithExploits {
+		vulnerabilitiesRows = utils.FilterWithKnownExploits(vulnerabilitiesRows)
+	}
+	gatedRows, _ := results.cvssPolicy.SplitByMinCvss(vulnerabilitiesRows)
+	if results.cvssPolicy.HideBelowMinCvss {
+		vulnerabilitiesRows = gatedRows
+	}
+	pathIgnoredIssues := getPathIgnoredIssues(project, vulnerabilitiesRows)
+	if len(pathIgnoredIssues) > 0 {
+		results.pathIgnoredIssuesFound = true
+		if results.pathIgnoredIssues == nil {
+			results.pathIgnoredIssues = make(map[string]bool)
+		}
+		for issueId := range pathIgnoredIssues {
+			results.pathIgnoredIssues[issueId] = true
+		}
+	}
+	var failingCandidates []formats.VulnerabilityOrViolationRow
+	for _, row := range gatedRows {
+		if !pathIgnoredIssues[getUniqueID(row)] {
+			failingCandidates = append(failingCandidates, row)
+		}
+	}
+	results.failingIssuesFound = results.failingIssuesFound || project.HasFailingIssues(failingCandidates)
+	results.addSubmoduleIssues(project, vulnerabilitiesRows)
+	results.vulnerabilitiesRows = append(results.vulnerabilitiesRows, vulnerabilitiesRows...)
+}
+
+// Add the misconfigurations of a single project, according to its severity policy
+func (results *auditResults) addProjectIacIssues(project *utils.Project, iacRows []utils.IacRow) {
+	iacRows = project.FilterIacBySeverity(iacRows)
+	var failingCandidates []utils.IacRow
+	for _, row := range iacRows {
+		if project.IsPathIgnored(getRepositoryPath(row.FullPath, row.File)) {
+			results.pathIgnoredIssuesFound = true
+		} else {
+			failingCandidates = append(failingCandidates, row)
+		}
+	}
+	results.failingIssuesFound = results.failingIssuesFound || project.HasFailingIacIssues(failingCandidates)
+	results.iacRows = append(results.iacRows, iacRows...)
+}
+
+// Return the unique IDs of the issues found in the paths which match the pathIgnores patterns. All the issues of a project whose working dirs match the patterns
+// are path ignored, and the issues of the other projects are path ignored if all their impact paths start in modules whose dirs match the patterns.
+func getPathIgnoredIssues(project *utils.Project, vulnerabilitiesRows []formats.VulnerabilityOrViolationRow) map[string]bool {
+	pathIgnoredIssues := make(map[string]bool)
+	if len(vulnerabilitiesRows) == 0 || (!project.PathIgnored && len(project.PathIgnores) == 0) {
+		return pathIgnoredIssues
+	}
+	var modulesDirs map[string]string
+	if !project.PathIgnored {
+		wd, err := os.Getwd()
+		if err != nil {
+			log.Warn("couldn't find the modules of the issues, so the pathIgnores patterns aren't applied to them:", err.Error())
+			return pathIgnoredIssues
+		}
+		modulesDirs = utils.GetModulesDirs(getFullPathWorkingDirs(project, wd), wd)
+	}
+	for i := range vulnerabilitiesRows {
+		if project.IsIssuePathIgnored(&vulnerabilitiesRows[i], modulesDirs) {
+			pathIgnoredIssues[getUniqueID(vulnerabilitiesRows[i])] = true
+		}
+	}
+	return pathIgnoredIssues
+}
+
+// Return the path relative to the root of the repository, which is the current dir, of a file found by a scan.
+// If the full path of the file is unknown, or isn't under the root of the repository, the given relative path is returned.
+func getRepositoryPath(fullPath, relativePath string) string {
+	wd, err := os.Getwd()
+	if fullPath == "" || err != nil {
+		return relativePath
+	}
+	repositoryPath, err := filepath.Rel(wd, fullPath)
+	if err != nil || strings.HasPrefix(repositoryPath, "..") {
+		return relativePath
+	}
+	return filepath.ToSlash(repositoryPath)
+}
+
+func (results *auditResults) addXrayScans(scans []services.ScanResponse) {
+	for _, scan := range scans {
+		if scan.ScanId != "" || scan.XrayDataUrl != "" {
+			results.xrayScans = append(results.xrayScans, services.ScanResponse{ScanId: scan.ScanId, XrayDataUrl: scan.XrayDataUrl})
+		}
+	}
+}
+
+// Return the ignored issues of all the projects, which expire within the configured warning window
+func getExpiringIgnoredIssues(repoConfig *utils.FrogbotRepoConfig) (expiringIssues []utils.IgnoredIssue) {
+	ids := make(map[string]bool)
+	for index := range repoConfig.Projects {
+		for _, ignoredIssue := range repoConfig.Projects[index].GetExpiringIgnoredIssues(time.Now(), repoConfig.IgnoreExpiryWarningDays) {
+			if !ids[ignoredIssue.Id] {
+				ids[ignoredIssue.Id] = true
+				expiringIssues = append(expiringIssues, ignoredIssue)
+			}
+		}
+	}
+	return
+}
+
+// Add the dependencies of a single project, which were updated to versions with new issues
+func (results *auditResults) addRiskChanges(previousScan, currentScan []services.ScanResponse, isMultipleRoot bool) error {
+	previousRows, err := createAllIssuesRows(previousScan, isMultipleRoot)
+	if err != nil {
+		return err
 	}
-	return err
+	currentRows, err := createAllIssuesRows(currentScan, isMultipleRoot)
+	if err != nil {
+		return err
+	}
+	results.riskChanges = append(results.riskChanges, getDependenciesRiskChanges(previousRows, currentRows)...)
+	return nil
 }
 
-func auditPullRequest(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient) ([]formats.VulnerabilityOrViolationRow, error) {
-	xrayScanParams := createXrayScanParams(repoConfig.Watches, repoConfig.JFrogProjectKey)
-	var vulnerabilitiesRows []formats.VulnerabilityOrViolationRow
-	for _, project := range repoConfig.Projects {
-		currentScan, isMultipleRoot, err := auditSource(xrayScanParams, project, &repoConfig.Server)
+// auditPullRequest returns the issues to display, and a map between the new issues and the direct dependencies
+// added or updated by the pull request, through which they were introduced.
+// Each project is scanned independently, with its own Xray watches and severity policy.
+func auditPullRequest(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient, targetBranch *targetBranchCheckout) (*auditResults, error) {
+	results := &auditResults{
+		introducingDependencies: make(map[string][][]formats.ComponentRow),
+		onlyWithExploits:        repoConfig.OnlyWithExploits,
+		cvssPolicy:              repoConfig.CvssPolicy,
+		unknownSeverityAs:       repoConfig.UnknownSeverityAs,
+		scanHistory:             loadScanHistoryBaseline(repoConfig, client),
+	}
+	var npmRegistry *npmRegistryClient
+	if repoConfig.ShowFixChains {
+		npmRegistry = newNpmRegistryClient()
+	}
+	projects, err := getScannedProjects(repoConfig)
+	if err != nil {
+		return nil, err
+	}
+	projects = disableUntrustedInlineIgnores(repoConfig, projects)
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	for projectIndex := range projects {
+		project := &projects[projectIndex]
+		detectProjectWorkingDirs(project, wd)
+		if project.ScanIaC {
+			// All the misconfigurations of the source branch are reported, since they are fixed in place rather than by upgrades
+			iacRows, err := auditSourceIac(project, &repoConfig.Server)
+			if err != nil {
+				return nil, err
+			}
+			results.addProjectIacIssues(project, iacRows)
+		}
+		// The scanned working dirs may be narrowed to the modules with changed manifests, while the severity policy of the project still applies
+		scannedProject := *project
+		if repoConfig.ScanChangedOnly && !repoConfig.IncludeAllVulnerabilities {
+			changedModulesDirs, err := getChangedModulesWorkingDirs(targetBranch, project)
+			if err != nil {
+				return nil, err
+			}
+			if len(changedModulesDirs) == 0 {
+				continue
+			}
+			scannedProject.WorkingDirs = changedModulesDirs
+		}
+		xrayScanParams := createXrayScanParams(project.Watches, repoConfig.JFrogProjectKey, repoConfig.ScanMode)
+		currentScan, isMultipleRoot, err := auditSource(xrayScanParams, scannedProject, &repoConfig.Server)
+		if err != nil {
+			// The target branch is scanned in the working dirs which were scanned in the source branch only
+			var failedDirs []string
+			currentScan, isMultipleRoot, scannedProject.WorkingDirs, failedDirs, err = auditWorkingDirsSeparately(scannedProject, err, func(project utils.Project) ([]services.ScanResponse, bool, error) {
+				return auditSource(xrayScanParams, project, &repoConfig.Server)
+			})
+			results.failedWorkingDirs = append(results.failedWorkingDirs, failedDirs...)
+		}
 		if err != nil {
 			return nil, err
 		}
+		results.addXrayScans(currentScan)
+		results.addViolationsPolicies(xrayScanParams.Watches, currentScan)
+		results.scanResults = append(results.scanResults, currentScan...)
 		if repoConfig.IncludeAllVulnerabilities {
 			log.Info("Frogbot is configured to show all vulnerabilities")
 			allIssuesRows, err := createAllIssuesRows(currentScan, isMultipleRoot)
 			if err != nil {
 				return nil, err
 			}
-			vulnerabilitiesRows = append(vulnerabilitiesRows, allIssuesRows...)
+			results.addProjectIssues(project, allIssuesRows)
+			if repoConfig.ShowRemediationCommands {
+				results.addRemediationCommands(project, allIssuesRows, repoConfig.UpgradeStrategy, repoConfig.AllowPrerelease)
+			}
+			if npmRegistry != nil {
+				results.addFixChains(allIssuesRows, npmRegistry)
+			}
 			continue
 		}
-		// Audit target code
-		previousScan, isMultipleRoot, err := auditTarget(client, xrayScanParams, project, repoConfig.Branches[0], &repoConfig.Git, &repoConfig.Server)
-		if err != nil {
-			return nil, err
+		results.addProjectScan(&scannedProject, currentScan)
+		// Audit target code, unless the project is compared against the last clean scan of the source branch.
+		// With the delta scan, only the dependencies removed by the pull request are scanned in the target branch.
+		previousScan, found := results.scanHistory.getProjectScan(&scannedProject)
+		var delta *deltaScanResults
+		if !found && repoConfig.DeltaScan {
+			if delta, err = auditDelta(targetBranch, xrayScanParams, scannedProject, repoConfig); err != nil {
+				return nil, err
+			}
 		}
-		newIssuesRows, err := createNewIssuesRows(previousScan, currentScan, isMultipleRoot)
-		if err != nil {
+		var newIssuesRows []formats.VulnerabilityOrViolationRow
+		var introducingDependencies map[string][][]formats.ComponentRow
+		if delta != nil {
+			previousScan = delta.targetScans
+			if newIssuesRows, err = delta.getNewIssuesRows(currentScan, isMultipleRoot); err != nil {
+				return nil, err
+			}
+			introducingDependencies = getIntroducingDirectDependencies(delta.targetDirectDependencies, newIssuesRows)
+		} else {
+			if !found {
+				if previousScan, isMultipleRoot, err = auditTarget(targetBranch, xrayScanParams, scannedProject, &repoConfig.Server); err != nil {
+					return nil, err
+				}
+			}
+			if newIssuesRows, err = createNewIssuesRows(previousScan, currentScan, isMultipleRoot); err != nil {
+				return nil, err
+			}
+			introducingDependencies = getIntroducingDependencies(previousScan, newIssuesRows)
+		}
+		if err = results.addRiskChanges(previousScan, currentScan, isMultipleRoot); err != nil {
 			return nil, err
 		}
-		vulnerabilitiesRows = append(vulnerabilitiesRows, newIssuesRows...)
+		results.addNewIssues(repoConfig, project, newIssuesRows, introducingDependencies, npmRegistry)
 	}
 	log.Info("Xray scan completed")
-	return vulnerabilitiesRows, nil
+	return results, nil
+}
+
+// Add the new issues of a single project, added by the pull request, with the direct dependencies through which they were introduced
+func (results *auditResults) addNewIssues(repoConfig *utils.FrogbotRepoConfig, project *utils.Project, newIssuesRows []formats.VulnerabilityOrViolationRow,
+	introducingDependencies map[string][][]formats.ComponentRow, npmRegistry *npmRegistryClient) {
+	for issueId, chains := range introducingDependencies {
+		results.introducingDependencies[issueId] = chains
+	}
+	results.addProjectIssues(project, newIssuesRows)
+	if repoConfig.ShowRemediationCommands {
+		results.addRemediationCommands(project, newIssuesRows, repoConfig.UpgradeStrategy, repoConfig.AllowPrerelease)
+	}
+	if npmRegistry != nil {
+		results.addFixChains(newIssuesRows, npmRegistry)
+	}
 }
 
 // Verify that the 'frogbot' GitHub environment was properly configured on the repository
@@ -119,7 +754,7 @@ func verifyGitHubFrogbotEnvironment(client vcsclient.VcsClient, repoConfig *util
 	// If repository is not public, using 'frogbot' environment is not mandatory
 	repoInfo, err := client.GetRepositoryInfo(context.Background(), repoConfig.RepoOwner, repoConfig.RepoName)
 	if err != nil {
-		return err
+		return &VcsError{Err: err}
 	}
 	if repoInfo.RepositoryVisibility != vcsclient.Public {
 		return nil
@@ -137,26 +772,61 @@ func verifyGitHubFrogbotEnvironment(client vcsclient.VcsClient, repoConfig *util
 	return nil
 }
 
-// Create vulnerabilities rows. The rows should contain only the new issues added by this PR
+// Create vulnerabilities rows. The rows should contain only the new issues added by this PR.
+// If both violations and vulnerabilities were requested, the vulnerabilities which are also violations are shown once, as violations.
 func createNewIssuesRows(previousScan, currentScan []services.ScanResponse, isMultipleRoot bool) (vulnerabilitiesRows []formats.VulnerabilityOrViolationRow, err error) {
 	previousScanAggregatedResults := aggregateScanResults(previousScan)
 	currentScanAggregatedResults := aggregateScanResults(currentScan)
 
+	var newViolations, newVulnerabilities []formats.VulnerabilityOrViolationRow
 	if len(currentScanAggregatedResults.Violations) > 0 {
-		newViolations, err := getNewViolations(previousScanAggregatedResults, currentScanAggregatedResults, isMultipleRoot)
-		if err != nil {
+		if newViolations, err = getNewViolations(previousScanAggregatedResults, currentScanAggregatedResults, isMultipleRoot); err != nil {
 			return vulnerabilitiesRows, err
 		}
-		vulnerabilitiesRows = append(vulnerabilitiesRows, newViolations...)
-	} else if len(currentScanAggregatedResults.Vulnerabilities) > 0 {
-		newVulnerabilities, err := getNewVulnerabilities(previousScanAggregatedResults, currentScanAggregatedResults, isMultipleRoot)
-		if err != nil {
+	}
+	if len(currentScanAggregatedResults.Vulnerabilities) > 0 {
+		if newVulnerabilities, err = getNewVulnerabilities(previousScanAggregatedResults, currentScanAggregatedResults, isMultipleRoot); err != nil {
 			return vulnerabilitiesRows, err
 		}
-		vulnerabilitiesRows = append(vulnerabilitiesRows, newVulnerabilities...)
 	}
+	return mergeIssuesRows(newViolations, newVulnerabilities), nil
+}
+
+// Merge the violations and the vulnerabilities rows, omitting the vulnerabilities which are also violations.
+// The merged rows are sorted, so that the results don't depend on the order of the components in the scan results.
+func mergeIssuesRows(violationsRows, vulnerabilitiesRows []formats.VulnerabilityOrViolationRow) []formats.VulnerabilityOrViolationRow {
+	mergedRows := append([]formats.VulnerabilityOrViolationRow{}, violationsRows...)
+	violationIds := make(map[string]bool)
+	for _, row := range violationsRows {
+		violationIds[getUniqueID(row)] = true
+	}
+	for _, row := range vulnerabilitiesRows {
+		if !violationIds[getUniqueID(row)] {
+			mergedRows = append(mergedRows, row)
+		}
+	}
+	sortIssuesRows(mergedRows)
+	return mergedRows
+}
 
-	return vulnerabilitiesRows, nil
+// The rows are created by iterating the components map of each issue, so the order of the rows of the same severity varies between runs.
+// Sort the rows by their severity, with the fixable issues first, and then by the impacted dependency and the issue ID.
+func sortIssuesRows(rows []formats.VulnerabilityOrViolationRow) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].SeverityNumValue != rows[j].SeverityNumValue {
+			return rows[i].SeverityNumValue > rows[j].SeverityNumValue
+		}
+		if fixableI, fixableJ := len(rows[i].FixedVersions) > 0, len(rows[j].FixedVersions) > 0; fixableI != fixableJ {
+			return fixableI
+		}
+		if rows[i].ImpactedDependencyName != rows[j].ImpactedDependencyName {
+			return rows[i].ImpactedDependencyName < rows[j].ImpactedDependencyName
+		}
+		if rows[i].ImpactedDependencyVersion != rows[j].ImpactedDependencyVersion {
+			return rows[i].ImpactedDependencyVersion < rows[j].ImpactedDependencyVersion
+		}
+		return rows[i].IssueId < rows[j].IssueId
+	})
 }
 
 func aggregateScanResults(scanResults []services.ScanResponse) services.ScanResponse {
@@ -173,14 +843,19 @@ func aggregateScanResults(scanResults []services.ScanResponse) services.ScanResp
 
 // Create vulnerabilities rows. The rows should contain all the issues that were found in this module scan.
 func getScanVulnerabilitiesRows(violations []services.Violation, vulnerabilities []services.Vulnerability, isMultipleRoot bool) ([]formats.VulnerabilityOrViolationRow, error) {
+	var violationsRows, vulnerabilitiesRows []formats.VulnerabilityOrViolationRow
+	var err error
 	if len(violations) > 0 {
-		violationsRows, _, _, err := xrayutils.PrepareViolations(violations, isMultipleRoot, true)
-		return violationsRows, err
+		if violationsRows, _, _, err = xrayutils.PrepareViolations(violations, isMultipleRoot, true); err != nil {
+			return nil, err
+		}
 	}
 	if len(vulnerabilities) > 0 {
-		return xrayutils.PrepareVulnerabilities(vulnerabilities, isMultipleRoot, true)
+		if vulnerabilitiesRows, err = xrayutils.PrepareVulnerabilities(vulnerabilities, isMultipleRoot, true); err != nil {
+			return nil, err
+		}
 	}
-	return []formats.VulnerabilityOrViolationRow{}, nil
+	return mergeIssuesRows(violationsRows, vulnerabilitiesRows), nil
 }
 
 // Create vulnerabilities rows. The rows should contain all the issues that were found in this PR
@@ -189,9 +864,17 @@ func createAllIssuesRows(currentScan []services.ScanResponse, isMultipleRoot boo
 	return getScanVulnerabilitiesRows(violations, vulnerabilities, isMultipleRoot)
 }
 
-func createXrayScanParams(watches []string, project string) (params services.XrayGraphScanParams) {
+// Create the params of the Xray graph scan, according to the scan mode.
+// By default, the violations of the watches or of the JFrog project are requested, or all the vulnerabilities if neither is configured.
+func createXrayScanParams(watches []string, project, scanMode string) (params services.XrayGraphScanParams) {
 	params.ScanType = services.Dependency
 	params.IncludeLicenses = false
+	if scanMode == utils.VulnerabilitiesScanMode {
+		params.IncludeVulnerabilities = true
+		return
+	}
+	// The vulnerabilities are requested in addition to the violations
+	params.IncludeVulnerabilities = scanMode == utils.BothScanMode
 	if len(watches) > 0 {
 		params.Watches = watches
 		return
@@ -214,10 +897,20 @@ func auditSource(xrayScanParams services.XrayGraphScanParams, project utils.Proj
 	return runInstallAndAudit(xrayScanParams, &project, server, true, fullPathWds...)
 }
 
+func auditSourceIac(project *utils.Project, server *coreconfig.ServerDetails) ([]utils.IacRow, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	return auditIac(server, getFullPathWorkingDirs(project, wd))
+}
+
 func getFullPathWorkingDirs(project *utils.Project, baseWd string) []string {
 	var fullPathWds []string
-	if len(project.WorkingDirs) != 0 {
-		for _, workDir := range project.WorkingDirs {
+	detectProjectWorkingDirs(project, baseWd)
+	workingDirs := project.WorkingDirs
+	if len(workingDirs) != 0 {
+		for _, workDir := range workingDirs {
 			if workDir == utils.RootDir {
 				fullPathWds = append(fullPathWds, baseWd)
 				continue
@@ -230,22 +923,45 @@ func getFullPathWorkingDirs(project *utils.Project, baseWd string) []string {
 	return fullPathWds
 }
 
-func auditTarget(client vcsclient.VcsClient, xrayScanParams services.XrayGraphScanParams, project utils.Project, branch string, git *utils.Git, server *coreconfig.ServerDetails) (res []services.ScanResponse, isMultipleRoot bool, err error) {
-	// First download the target repo to temp dir
-	log.Info("Auditing " + git.RepoName + " " + branch)
-	wd, cleanup, err := utils.DownloadRepoToTempDir(client, branch, git)
+// Detect the working dirs of the project by the dependency manifests in the base dir, if the project auto-detects them. If none are detected, the base dir is scanned.
+// The detected dirs are kept in the project, so the dirs detected in the source branch are also scanned in the target branch, without detecting them again.
+func detectProjectWorkingDirs(project *utils.Project, baseWd string) {
+	if len(project.WorkingDirs) != 0 || !project.AutoDetectWorkingDirs {
+		return
+	}
+	project.WorkingDirs = []string{utils.RootDir}
+	workingDirs, err := utils.DetectWorkingDirs(baseWd, project.AutoDetectExcludes)
 	if err != nil {
+		log.Warn("couldn't detect the working directories. Scanning the root of the repository instead:", err.Error())
 		return
 	}
-	// Cleanup
-	defer func() {
-		e := cleanup()
-		if err == nil {
-			err = e
-		}
-	}()
-	fullPathWds := getFullPathWorkingDirs(&project, wd)
-	return runInstallAndAudit(xrayScanParams, &project, server, false, fullPathWds...)
+	if len(workingDirs) == 0 {
+		log.Info("No dependency manifests were detected. Scanning the root of the repository")
+		return
+	}
+	log.Info("Detected the working directories:", strings.Join(workingDirs, ", "))
+	project.WorkingDirs = workingDirs
+}
+
+func auditTarget(targetBranch *targetBranchCheckout, xrayScanParams services.XrayGraphScanParams, project utils.Project, server *coreconfig.ServerDetails) (res []services.ScanResponse, isMultipleRoot bool, err error) {
+	log.Info("Auditing " + targetBranch.git.RepoName + " " + targetBranch.branch)
+	err = inTargetBranch(targetBranch, project, func(fullPathWds []string) (e error) {
+		res, isMultipleRoot, e = runInstallAndAudit(xrayScanParams, &project, server, false, fullPathWds...)
+		return
+	})
+	return
+}
+
+// Run the action on the full paths of the working dirs of the project in the target branch, which is downloaded if it wasn't downloaded yet
+func inTargetBranch(targetBranch *targetBranchCheckout, project utils.Project, action func(fullPathWds []string) error) error {
+	wd, err := targetBranch.getDir()
+	if err != nil {
+		return err
+	}
+	if err = cloneTargetSubmodule(&project, wd, targetBranch.branch, targetBranch.git); err != nil {
+		return err
+	}
+	return action(getFullPathWorkingDirs(&project, wd))
 }
 
 func runInstallAndAudit(xrayScanParams services.XrayGraphScanParams, project *utils.Project, server *coreconfig.ServerDetails, failOnInstallationErrors bool, workDirs ...string) (results []services.ScanResponse, isMultipleRoot bool, err error) {
@@ -255,8 +971,25 @@ func runInstallAndAudit(xrayScanParams services.XrayGraphScanParams, project *ut
 		}
 	}
 
-	results, isMultipleRoot, err = audit.GenericAudit(xrayScanParams, server, false, project.UseWrapper, false,
-		nil, nil, project.PipRequirementsFile, false, workDirs, []string{}...)
+	// The generic audit scans the Go modules as they are required, ignoring the replace directives of go.mod
+	goModReplaced, err := hasGoModReplaces(workDirs)
+	if err != nil {
+		return nil, false, err
+	}
+	// The generic audit runs the audit of the given technologies in every working directory, so the ecosystems of the project are filtered per working directory by the batch audit
+	// The dependency graphs are cached by the batch audit only
+	useBatchAudit := project.ScanBatchSize > 1 || goModReplaced || len(project.Ecosystems) > 0 || project.ScanCache != nil
+	results, isMultipleRoot, err = auditWithFailover(server, project.XrayFailoverUrls, func(server *coreconfig.ServerDetails) ([]services.ScanResponse, bool, error) {
+		if useBatchAudit {
+			batchSize := project.ScanBatchSize
+			if batchSize < 1 {
+				batchSize = 1
+			}
+			return batchAudit(xrayScanParams, project, server, batchSize, workDirs)
+		}
+		return audit.GenericAudit(xrayScanParams, server, false, project.UseWrapper, false,
+			nil, nil, project.PipRequirementsFile, false, workDirs, []string{}...)
+	})
 	if err != nil {
 		return nil, false, err
 	}
@@ -329,6 +1062,124 @@ func getNewVulnerabilities(previousScan, currentScan services.ScanResponse, isMu
 	return
 }
 
+// getIntroducingDependencies returns a map between the unique IDs of new transitive issues and the chains of dependencies through which they were introduced.
+// A direct dependency is considered as introduced by the pull request if it doesn't appear, with the same version, in any impact path of the previous scan.
+func getIntroducingDependencies(previousScan []services.ScanResponse, newIssuesRows []formats.VulnerabilityOrViolationRow) map[string][][]formats.ComponentRow {
+	return getIntroducingDirectDependencies(getDirectDependenciesFromImpactPaths(previousScan), newIssuesRows)
+}
+
+// Return the chains through which the new transitive issues were introduced, which are the impact paths starting at a direct dependency which isn't in previousDirectDependencies.
+// Each chain starts at the direct dependency, and ends at the parent of the impacted dependency.
+func getIntroducingDirectDependencies(previousDirectDependencies map[formats.ComponentRow]bool, newIssuesRows []formats.VulnerabilityOrViolationRow) map[string][][]formats.ComponentRow {
+	introducingDependencies := make(map[string][][]formats.ComponentRow)
+	for _, row := range newIssuesRows {
+		addedChains := make(map[string]bool)
+		for _, impactPath := range row.ImpactPaths {
+			// The first node in the impact path is the scanned project, and the second one is the direct dependency.
+			// Shorter paths belong to direct dependencies, which don't need any further explanation.
+			if len(impactPath) < 3 || previousDirectDependencies[impactPath[1]] {
+				continue
+			}
+			chain := impactPath[1 : len(impactPath)-1]
+			if chainKey := formatIntroducingChain(chain); !addedChains[chainKey] {
+				addedChains[chainKey] = true
+				introducingDependencies[getUniqueID(row)] = append(introducingDependencies[getUniqueID(row)], chain)
+			}
+		}
+	}
+	return introducingDependencies
+}
+
+// Format the chain of dependencies, such as **optimist 0.6.1** → **minimist 0.0.8**
+func formatIntroducingChain(chain []formats.ComponentRow) string {
+	dependencies := make([]string, 0, len(chain))
+	for _, dependency := range chain {
+		dependencies = append(dependencies, fmt.Sprintf("**%s %s**", dependency.Name, dependency.Version))
+	}
+	return strings.Join(dependencies, " → ")
+}
+
+func getDirectDependenciesFromImpactPaths(scanResults []services.ScanResponse) map[formats.ComponentRow]bool {
+	directDependencies := make(map[formats.ComponentRow]bool)
+	addComponents := func(components map[string]services.Component) {
+		for _, component := range components {
+			for _, impactPath := range component.ImpactPaths {
+				if len(impactPath) < 2 {
+					continue
+				}
+				name, version, _ := xrayutils.SplitComponentId(impactPath[1].ComponentId)
+				directDependencies[formats.ComponentRow{Name: name, Version: version}] = true
+			}
+		}
+	}
+	for _, scanResult := range scanResults {
+		for _, violation := range scanResult.Violations {
+			addComponents(violation.Components)
+		}
+		for _, vulnerability := range scanResult.Vulnerabilities {
+			addComponents(vulnerability.Components)
+		}
+	}
+	return directDependencies
+}
+
+// Create a note with links to the Xray scans, so that developers can view the full scan reports in Xray.
+// If Xray didn't return a link, the scan ID is shown instead. If Xray returned neither, the note is omitted.
+func createXrayScansNote(scans []services.ScanResponse) string {
+	var scanReferences []string
+	for i, scan := range scans {
+		label := scan.ScanId
+		if label == "" {
+			label = fmt.Sprintf("scan %d", i+1)
+		}
+		if scan.XrayDataUrl != "" {
+			scanReferences = append(scanReferences, fmt.Sprintf("[%s](%s)", label, scan.XrayDataUrl))
+		} else {
+			scanReferences = append(scanReferences, fmt.Sprintf("`%s`", label))
+		}
+	}
+	if len(scanReferences) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(xrayScansNote, strings.Join(scanReferences, " · "))
+}
+
+const introducedViaTitle = "#### 🔗 Introduced by the direct dependencies added or updated in this pull request"
+
+// Create notes which explain through which chains of new direct dependencies the transitive issues were introduced
+func createIntroducedViaNotes(vulnerabilitiesRows []formats.VulnerabilityOrViolationRow, introducingDependencies map[string][][]formats.ComponentRow) string {
+	var notes strings.Builder
+	for _, row := range vulnerabilitiesRows {
+		chains, exists := introducingDependencies[getUniqueID(row)]
+		if !exists {
+			continue
+		}
+		formattedChains := make([]string, 0, len(chains))
+		for _, chain := range chains {
+			formattedChains = append(formattedChains, formatIntroducingChain(chain))
+		}
+		notes.WriteString(fmt.Sprintf("- **%s %s** (%s) is introduced via %s\n", row.ImpactedDependencyName, row.ImpactedDependencyVersion, getIssueDisplayId(row), strings.Join(formattedChains, ", ")))
+	}
+	if notes.Len() == 0 {
+		return ""
+	}
+	return "\n\n" + introducedViaTitle + "\n\n" + notes.String()
+}
+
+// Return the CVEs of the issue, or the Xray issue ID if the issue has no CVEs
+func getIssueDisplayId(row formats.VulnerabilityOrViolationRow) string {
+	var cves []string
+	for _, cve := range row.Cves {
+		if cve.Id != "" {
+			cves = append(cves, cve.Id)
+		}
+	}
+	if len(cves) == 0 {
+		return row.IssueId
+	}
+	return strings.Join(cves, ", ")
+}
+
 func getUniqueID(vulnerability formats.VulnerabilityOrViolationRow) string {
 	return vulnerability.ImpactedDependencyName + vulnerability.ImpactedDependencyVersion + vulnerability.IssueId
 }
@@ -341,6 +1192,40 @@ func createPullRequestMessage(vulnerabilitiesRows []formats.VulnerabilityOrViola
 	return writer.VulnerabiltiesTitle() + writer.TableHeader() + tableContent
 }
 
+// Replace the placeholders of the configured clean scan message with the scanned commit and the time of the scan
+func createCleanScanMessage(cleanScanMessage, commitSha string, scanTime time.Time) string {
+	return strings.NewReplacer(
+		commitShaPlaceholder, commitSha,
+		timestampPlaceholder, scanTime.UTC().Format(time.RFC3339),
+	).Replace(cleanScanMessage)
+}
+
+// Create the message of the dependencies issues table, followed by the Infrastructure section of the misconfigurations and the Secrets section, if any were found
+func createScanResultsMessage(results *auditResults, writer utils.OutputWriter) string {
+	if len(results.iacRows) == 0 && len(results.secrets) == 0 {
+		return createPullRequestMessage(results.vulnerabilitiesRows, writer)
+	}
+	return writer.VulnerabiltiesTitle() + createScanResultsTables(results, writer, utils.DefaultSectionOrder)
+}
+
+// Create the issues tables of the scan results in the order of the sections, without the title of the message. The sections which aren't listed are hidden.
+func createScanResultsTables(results *auditResults, writer utils.OutputWriter, sectionOrder []string) string {
+	var tables strings.Builder
+	for _, section := range sectionOrder {
+		switch section {
+		case utils.SecuritySection:
+			if len(results.vulnerabilitiesRows) > 0 {
+				tables.WriteString(writer.TableHeader() + getTableContent(results.vulnerabilitiesRows, writer))
+			}
+		case utils.IacSection:
+			tables.WriteString(createIacContent(results.iacRows, writer))
+		case utils.SecretsSection:
+			tables.WriteString(createSecretsContent(results.secrets))
+		}
+	}
+	return tables.String()
+}
+
 func getTableContent(vulnerabilitiesRows []formats.VulnerabilityOrViolationRow, writer utils.OutputWriter) string {
 	var tableContent string
 	for _, vulnerability := range vulnerabilitiesRows {
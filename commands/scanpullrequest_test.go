@@ -8,14 +8,17 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/jfrog/froggit-go/vcsclient"
 	"github.com/jfrog/froggit-go/vcsutils"
 	coreconfig "github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 
 	"github.com/jfrog/frogbot/commands/utils"
 	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
@@ -35,25 +38,90 @@ const (
 
 func TestCreateXrayScanParams(t *testing.T) {
 	// Project
-	params := createXrayScanParams(nil, "")
+	params := createXrayScanParams(nil, "", "")
 	assert.Empty(t, params.Watches)
 	assert.Equal(t, "", params.ProjectKey)
 	assert.True(t, params.IncludeVulnerabilities)
 	assert.False(t, params.IncludeLicenses)
 
 	// Watches
-	params = createXrayScanParams([]string{"watch-1", "watch-2"}, "")
+	params = createXrayScanParams([]string{"watch-1", "watch-2"}, "", "")
 	assert.Equal(t, []string{"watch-1", "watch-2"}, params.Watches)
 	assert.Equal(t, "", params.ProjectKey)
 	assert.False(t, params.IncludeVulnerabilities)
 	assert.False(t, params.IncludeLicenses)
 
 	// Project
-	params = createXrayScanParams(nil, "project")
+	params = createXrayScanParams(nil, "project", "")
 	assert.Empty(t, params.Watches)
 	assert.Equal(t, "project", params.ProjectKey)
 	assert.False(t, params.IncludeVulnerabilities)
 	assert.False(t, params.IncludeLicenses)
+
+	// The vulnerabilities scan mode ignores the watches
+	params = createXrayScanParams([]string{"watch-1"}, "project", utils.VulnerabilitiesScanMode)
+	assert.Empty(t, params.Watches)
+	assert.Equal(t, "", params.ProjectKey)
+	assert.True(t, params.IncludeVulnerabilities)
+
+	// The both scan mode requests the vulnerabilities in addition to the violations
+	params = createXrayScanParams([]string{"watch-1"}, "", utils.BothScanMode)
+	assert.Equal(t, []string{"watch-1"}, params.Watches)
+	assert.True(t, params.IncludeVulnerabilities)
+	params = createXrayScanParams([]string{"watch-1"}, "", utils.ViolationsScanMode)
+	assert.Equal(t, []string{"watch-1"}, params.Watches)
+	assert.False(t, params.IncludeVulnerabilities)
+}
+
+func TestCreateNewIssuesRowsWithBothScanMode(t *testing.T) {
+	currentScan := services.ScanResponse{
+		Violations: []services.Violation{{IssueId: "XRAY-1", Severity: "high", ViolationType: "security", WatchName: "watch-1", Components: map[string]services.Component{"npm://lodash:4.17.20": {}}}},
+		Vulnerabilities: []services.Vulnerability{
+			{IssueId: "XRAY-1", Severity: "high", Components: map[string]services.Component{"npm://lodash:4.17.20": {}}},
+			{IssueId: "XRAY-2", Severity: "low", Components: map[string]services.Component{"npm://minimist:1.2.5": {}}},
+		},
+	}
+	rows, err := createNewIssuesRows([]services.ScanResponse{{}}, []services.ScanResponse{currentScan}, false)
+	assert.NoError(t, err)
+	// The vulnerability which is also a violation is shown once
+	if assert.Len(t, rows, 2) {
+		assert.Equal(t, "XRAY-1", rows[0].IssueId)
+		assert.Equal(t, "XRAY-2", rows[1].IssueId)
+	}
+	rows, err = createAllIssuesRows([]services.ScanResponse{currentScan}, false)
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+}
+
+func TestCreateIssuesRowsDeterministicOrder(t *testing.T) {
+	currentScan := services.ScanResponse{
+		Vulnerabilities: []services.Vulnerability{
+			{IssueId: "XRAY-3", Cves: []services.Cve{{Id: "CVE-2023-0003"}}, Severity: "High", Components: map[string]services.Component{"npm://minimist:1.2.5": {}, "npm://lodash:4.17.20": {}, "npm://axios:0.21.0": {}}},
+			{IssueId: "XRAY-2", Cves: []services.Cve{{Id: "CVE-2023-0002"}}, Severity: "High", Components: map[string]services.Component{"npm://lodash:4.17.20": {FixedVersions: []string{"[4.17.21]"}}}},
+			{IssueId: "XRAY-1", Cves: []services.Cve{{Id: "CVE-2023-0001"}}, Severity: "High", Components: map[string]services.Component{"npm://lodash:4.17.20": {}, "npm://lodash:4.17.19": {}}},
+			{IssueId: "XRAY-4", Cves: []services.Cve{{Id: "CVE-2023-0004"}}, Severity: "Critical", Components: map[string]services.Component{"npm://minimist:1.2.5": {}}},
+		},
+	}
+	expected := []string{"XRAY-4 minimist 1.2.5", "XRAY-2 lodash 4.17.20", "XRAY-3 axios 0.21.0", "XRAY-1 lodash 4.17.19", "XRAY-1 lodash 4.17.20", "XRAY-3 lodash 4.17.20", "XRAY-3 minimist 1.2.5"}
+	// The components maps are iterated in a random order, so the rows are created several times
+	for i := 0; i < 10; i++ {
+		for _, createRows := range []func() ([]formats.VulnerabilityOrViolationRow, error){
+			func() ([]formats.VulnerabilityOrViolationRow, error) {
+				return createAllIssuesRows([]services.ScanResponse{currentScan}, false)
+			},
+			func() ([]formats.VulnerabilityOrViolationRow, error) {
+				return createNewIssuesRows([]services.ScanResponse{{}}, []services.ScanResponse{currentScan}, false)
+			},
+		} {
+			rows, err := createRows()
+			assert.NoError(t, err)
+			var actual []string
+			for _, row := range rows {
+				actual = append(actual, row.IssueId+" "+row.ImpactedDependencyName+" "+row.ImpactedDependencyVersion)
+			}
+			assert.Equal(t, expected, actual)
+		}
+	}
 }
 
 func TestCreateVulnerabilitiesRows(t *testing.T) {
@@ -351,6 +419,207 @@ func TestGetNewVulnerabilitiesCaseNoNewVulnerabilities(t *testing.T) {
 	assert.Len(t, rows, 0)
 }
 
+func TestGetIntroducingDependencies(t *testing.T) {
+	// Previous scan, in which minimist was pulled in by mkdirp 0.5.5
+	previousScan := services.ScanResponse{
+		Vulnerabilities: []services.Vulnerability{{
+			IssueId:  "XRAY-1",
+			Severity: "high",
+			Components: map[string]services.Component{"npm://minimist:1.2.5": {
+				ImpactPaths: [][]services.ImpactPathNode{{{ComponentId: "npm://project:1.0.0"}, {ComponentId: "npm://mkdirp:0.5.5"}, {ComponentId: "npm://minimist:1.2.5"}}},
+			}},
+		}},
+	}
+
+	// Current scan, in which the pull request added optimist 0.6.1 and pulled in an older version of minimist
+	currentScan := services.ScanResponse{
+		Vulnerabilities: []services.Vulnerability{
+			{
+				IssueId:  "XRAY-1",
+				Severity: "high",
+				Cves:     []services.Cve{{Id: "CVE-2021-44906"}},
+				Components: map[string]services.Component{"npm://minimist:0.0.10": {
+					ImpactPaths: [][]services.ImpactPathNode{
+						{{ComponentId: "npm://project:1.0.0"}, {ComponentId: "npm://mkdirp:0.5.5"}, {ComponentId: "npm://minimist:0.0.10"}},
+						{{ComponentId: "npm://project:1.0.0"}, {ComponentId: "npm://optimist:0.6.1"}, {ComponentId: "npm://minimist:0.0.10"}},
+						{{ComponentId: "npm://project:1.0.0"}, {ComponentId: "npm://optimist:0.6.1"}, {ComponentId: "npm://wordwrap:0.0.3"}, {ComponentId: "npm://minimist:0.0.10"}},
+					},
+				}},
+			},
+			{
+				IssueId:  "XRAY-2",
+				Severity: "low",
+				Components: map[string]services.Component{"npm://optimist:0.6.1": {
+					ImpactPaths: [][]services.ImpactPathNode{{{ComponentId: "npm://project:1.0.0"}, {ComponentId: "npm://optimist:0.6.1"}}},
+				}},
+			},
+		},
+	}
+
+	rows, err := createNewIssuesRows([]services.ScanResponse{previousScan}, []services.ScanResponse{currentScan}, false)
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	introducingDependencies := getIntroducingDependencies([]services.ScanResponse{previousScan}, rows)
+
+	// Only the transitive minimist issue is introduced via a new direct dependency
+	assert.Len(t, introducingDependencies, 1)
+	// The chains are taken from the impact paths, from the new direct dependency to the parent of the impacted dependency
+	assert.Equal(t, [][]formats.ComponentRow{
+		{{Name: "optimist", Version: "0.6.1"}},
+		{{Name: "optimist", Version: "0.6.1"}, {Name: "wordwrap", Version: "0.0.3"}},
+	}, introducingDependencies["minimist0.0.10XRAY-1"])
+
+	notes := createIntroducedViaNotes(rows, introducingDependencies)
+	assert.Equal(t, "\n\n"+introducedViaTitle+"\n\n- **minimist 0.0.10** (CVE-2021-44906) is introduced via **optimist 0.6.1**, **optimist 0.6.1** → **wordwrap 0.0.3**\n", notes)
+	assert.Empty(t, createIntroducedViaNotes(rows, map[string][][]formats.ComponentRow{}))
+}
+
+func getTestContentMarker(t *testing.T, results *auditResults) string {
+	contentHash, err := results.getFindingsHash()
+	assert.NoError(t, err)
+	return utils.GetContentMarker(contentHash)
+}
+
+func TestAddContentMarker(t *testing.T) {
+	repoConfig := &utils.FrogbotRepoConfig{Params: utils.Params{Git: utils.Git{RepoOwner: "jfrog", RepoName: "frogbot", PullRequestID: 1}}}
+	results := &auditResults{vulnerabilitiesRows: []formats.VulnerabilityOrViolationRow{
+		{IssueId: "XRAY-1", ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "1.2.5"},
+		{IssueId: "XRAY-2", ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "4.17.20"},
+	}}
+	// The order of the findings doesn't change the content marker
+	reorderedResults := &auditResults{vulnerabilitiesRows: []formats.VulnerabilityOrViolationRow{results.vulnerabilitiesRows[1], results.vulnerabilitiesRows[0]}}
+	otherResults := &auditResults{vulnerabilitiesRows: results.vulnerabilitiesRows, secrets: []secretRow{{file: "config.yml", line: 3, secretType: "AWS access key ID"}}}
+	contentMarker := getTestContentMarker(t, results)
+	assert.Equal(t, contentMarker, getTestContentMarker(t, reorderedResults))
+	assert.NotEqual(t, contentMarker, getTestContentMarker(t, otherResults))
+
+	// The findings are unchanged since the newest comment with a content marker, even though the comment differs, such as with the Xray scan IDs
+	client := mockVcsClient(t)
+	client.EXPECT().ListPullRequestComments(context.Background(), "jfrog", "frogbot", 1).Return([]vcsclient.CommentInfo{
+		{Content: "other message" + getTestContentMarker(t, otherResults), Created: time.Unix(1, 0)},
+		{Content: "previous message" + contentMarker, Created: time.Unix(2, 0)},
+		{Content: "unrelated comment", Created: time.Unix(3, 0)},
+	}, nil)
+	message, changed, err := addContentMarker(repoConfig, client, reorderedResults, "full message")
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, "full message"+contentMarker, message)
+
+	// The newest comment differs, even though an older comment has the same findings
+	client = mockVcsClient(t)
+	client.EXPECT().ListPullRequestComments(context.Background(), "jfrog", "frogbot", 1).Return([]vcsclient.CommentInfo{
+		{Content: "full message" + contentMarker, Created: time.Unix(1, 0)},
+		{Content: "other message" + getTestContentMarker(t, otherResults), Created: time.Unix(2, 0)},
+	}, nil)
+	_, changed, err = addContentMarker(repoConfig, client, results, "full message")
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	// The comment is posted if the comments can't be read
+	client = mockVcsClient(t)
+	client.EXPECT().ListPullRequestComments(context.Background(), "jfrog", "frogbot", 1).Return(nil, errors.New("bad request"))
+	_, changed, err = addContentMarker(repoConfig, client, results, "full message")
+	assert.NoError(t, err)
+	assert.True(t, changed)
+}
+
+func TestSummarizeUnchangedResults(t *testing.T) {
+	rows := []formats.VulnerabilityOrViolationRow{{IssueId: "XRAY-1", ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "1.2.5"}}
+	issuesHash, err := utils.GetIssuesHash(rows, nil)
+	assert.NoError(t, err)
+	repoConfig := &utils.FrogbotRepoConfig{Params: utils.Params{Git: utils.Git{RepoOwner: "jfrog", RepoName: "frogbot", PullRequestID: 1}}}
+
+	// The issues are unchanged since the first comment
+	client := mockVcsClient(t)
+	client.EXPECT().ListPullRequestComments(context.Background(), "jfrog", "frogbot", 1).Return([]vcsclient.CommentInfo{
+		{Content: "full message" + utils.GetIssuesMarker(issuesHash, "abc123"), Created: time.Unix(1, 0)},
+		{Content: "unrelated comment", Created: time.Unix(2, 0)},
+	}, nil)
+	message, err := summarizeUnchangedResults(repoConfig, client, rows, nil, "full message")
+	assert.NoError(t, err)
+	assert.Equal(t, "🐸 Frogbot: 1 issues, unchanged since abc123"+utils.GetIssuesMarker(issuesHash, "abc123"), message)
+
+	// The issues changed since the newest comment
+	client = mockVcsClient(t)
+	client.EXPECT().ListPullRequestComments(context.Background(), "jfrog", "frogbot", 1).Return([]vcsclient.CommentInfo{
+		{Content: "full message" + utils.GetIssuesMarker(issuesHash, "abc123"), Created: time.Unix(1, 0)},
+		{Content: "no issues" + utils.GetIssuesMarker("0123", "def456"), Created: time.Unix(2, 0)},
+	}, nil)
+	message, err = summarizeUnchangedResults(repoConfig, client, rows, nil, "full message")
+	assert.NoError(t, err)
+	assert.Equal(t, "full message"+utils.GetIssuesMarker(issuesHash, utils.GetHeadCommitSha(".")), message)
+
+	// The comments can't be read
+	client = mockVcsClient(t)
+	client.EXPECT().ListPullRequestComments(context.Background(), "jfrog", "frogbot", 1).Return(nil, errors.New("bad request"))
+	message, err = summarizeUnchangedResults(repoConfig, client, rows, nil, "full message")
+	assert.NoError(t, err)
+	assert.Equal(t, "full message"+utils.GetIssuesMarker(issuesHash, utils.GetHeadCommitSha(".")), message)
+}
+
+func TestAddPullRequestComment(t *testing.T) {
+	repoConfig := &utils.FrogbotRepoConfig{Params: utils.Params{Git: utils.Git{RepoOwner: "jfrog", RepoName: "frogbot", PullRequestID: 1}, MaxCommentLength: 200}}
+	shortMessage := strings.Repeat("a", 200)
+	longMessage := strings.Repeat("line\n", 60)
+
+	// Comments at the maximum length aren't truncated
+	client := mockVcsClient(t)
+	client.EXPECT().AddPullRequestComment(context.Background(), "jfrog", "frogbot", shortMessage, 1).Return(nil)
+	assert.NoError(t, addPullRequestComment(repoConfig, client, shortMessage))
+
+	// Longer comments are truncated
+	client = mockVcsClient(t)
+	client.EXPECT().AddPullRequestComment(context.Background(), "jfrog", "frogbot", utils.TruncateComment(longMessage, 200), 1).Return(nil)
+	assert.NoError(t, addPullRequestComment(repoConfig, client, longMessage))
+
+	// Comments rejected due to their length are truncated to half of the length and added again
+	client = mockVcsClient(t)
+	rejectedComment := utils.TruncateComment(longMessage, 200)
+	client.EXPECT().AddPullRequestComment(context.Background(), "jfrog", "frogbot", rejectedComment, 1).Return(errors.New("422 Validation Failed [{Resource:IssueComment Field:body Code:custom Message:body is too long (maximum is 65536 characters)}]"))
+	client.EXPECT().AddPullRequestComment(context.Background(), "jfrog", "frogbot", utils.TruncateComment(rejectedComment, len([]rune(rejectedComment))/2), 1).Return(nil)
+	assert.NoError(t, addPullRequestComment(repoConfig, client, longMessage))
+
+	// Other errors are returned without a retry
+	client = mockVcsClient(t)
+	client.EXPECT().AddPullRequestComment(context.Background(), "jfrog", "frogbot", shortMessage, 1).Return(errors.New("bad request"))
+	assert.EqualError(t, addPullRequestComment(repoConfig, client, shortMessage), "bad request")
+}
+
+func TestAddPullRequestCommentWithBotName(t *testing.T) {
+	repoConfig := &utils.FrogbotRepoConfig{Params: utils.Params{Git: utils.Git{RepoOwner: "jfrog", RepoName: "frogbot", PullRequestID: 1}, BotName: "Security Bot"}}
+	expectedComment := "[//]: # (frogbot-bot-header)\n\n**Security Bot**\n\nmessage"
+	client := mockVcsClient(t)
+	client.EXPECT().AddPullRequestComment(context.Background(), "jfrog", "frogbot", expectedComment, 1).Return(nil)
+	assert.NoError(t, addPullRequestComment(repoConfig, client, "message"))
+}
+
+func TestReportScanError(t *testing.T) {
+	repoConfig := &utils.FrogbotRepoConfig{Params: utils.Params{Git: utils.Git{RepoOwner: "jfrog", RepoName: "frogbot", PullRequestID: 1}}}
+	scanErr := errors.New("xray is unavailable")
+	expectedComment := fmt.Sprintf(scanErrorComment, "xray is unavailable")
+
+	// By default, the scan error fails the task
+	client := mockVcsClient(t)
+	client.EXPECT().AddPullRequestComment(context.Background(), "jfrog", "frogbot", expectedComment, 1).Return(nil)
+	err := reportScanError(repoConfig, client, scanErr)
+	assert.EqualError(t, err, "the Xray scan failed: xray is unavailable\n You can avoid marking the Frogbot scan as failed due to scan errors by setting failOnScanError to false in the frogbot-config.yml file")
+	var scanExecutionErr *ScanExecutionError
+	assert.ErrorAs(t, err, &scanExecutionErr)
+	assert.ErrorIs(t, err, scanErr)
+
+	// The comment still states that the scan failed, even if the task doesn't fail
+	failOnScanError := false
+	repoConfig.FailOnScanError = &failOnScanError
+	client = mockVcsClient(t)
+	client.EXPECT().AddPullRequestComment(context.Background(), "jfrog", "frogbot", expectedComment, 1).Return(errors.New("bad request"))
+	assert.NoError(t, reportScanError(repoConfig, client, scanErr))
+}
+
+func TestCreateUnchangedResultsSummary(t *testing.T) {
+	assert.Equal(t, "🐸 Frogbot: 3 issues, unchanged since abc123", createUnchangedResultsSummary(3, "abc123"))
+	assert.Equal(t, "🐸 Frogbot: no issues, unchanged since the previous scan", createUnchangedResultsSummary(0, ""))
+}
+
 func TestCreatePullRequestMessageNoVulnerabilities(t *testing.T) {
 	vulnerabilities := []formats.VulnerabilityOrViolationRow{}
 	message := createPullRequestMessage(vulnerabilities, &utils.StandardOutput{})
@@ -359,6 +628,23 @@ func TestCreatePullRequestMessageNoVulnerabilities(t *testing.T) {
 	assert.NoError(t, err)
 	expectedMessage := strings.ReplaceAll(string(expectedMessageByte), "\r\n", "\n")
 	assert.Equal(t, expectedMessage, message)
+
+	// The default comment is added to pull requests with no issues
+	repoConfig := &utils.FrogbotRepoConfig{Params: utils.Params{Git: utils.Git{RepoOwner: "jfrog", RepoName: "frogbot", PullRequestID: 1}}}
+	client := mockVcsClient(t)
+	client.EXPECT().AddPullRequestComment(context.Background(), "jfrog", "frogbot", expectedMessage, 1).Return(nil)
+	assert.NoError(t, commentScanResults(repoConfig, client, len(vulnerabilities), message))
+
+	// No comment is added if the clean scan comment is suppressed. The mock client fails the test on any call.
+	repoConfig.SuppressCleanComment = true
+	assert.NoError(t, commentScanResults(repoConfig, mockVcsClient(t), len(vulnerabilities), message))
+}
+
+func TestCreateCleanScanMessage(t *testing.T) {
+	scanTime := time.Date(2023, 3, 14, 10, 30, 0, 0, time.UTC)
+	assert.Equal(t, "✅ No issues found in abc123 at 2023-03-14T10:30:00Z",
+		createCleanScanMessage("✅ No issues found in ${COMMIT_SHA} at ${TIMESTAMP}", "abc123", scanTime))
+	assert.Equal(t, "No issues", createCleanScanMessage("No issues", "abc123", scanTime))
 }
 
 func TestCreatePullRequestMessage(t *testing.T) {
@@ -455,15 +741,55 @@ func TestScanPullRequestMultiWorkDirNoFail(t *testing.T) {
 	testScanPullRequest(t, testMultiDirProjConfigPath, "multi-dir-test-proj", false)
 }
 
+// The requests of the subgroups are tested by TestGitLabSubgroupRequests, so this scan, which requires a JFrog Platform, is skipped if none is configured
+func TestScanPullRequestGitLabSubgroup(t *testing.T) {
+	if os.Getenv(utils.JFrogUrlEnv) == "" {
+		t.Skipf("the scan requires a JFrog Platform, set by '%s'", utils.JFrogUrlEnv)
+	}
+	testScanPullRequestWithNamespace(t, testCleanProjConfigPath, "jfrog/security/frogbot", "clean-test-proj", false)
+}
+
+func TestGitLabSubgroupRequests(t *testing.T) {
+	namespace, projectName := "jfrog/security/frogbot", "clean-test-proj"
+	gitLabHandler := createGitLabHandler(t, namespace, projectName)
+	var requestedURIs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedURIs = append(requestedURIs, r.RequestURI)
+		gitLabHandler(w, r)
+	}))
+	defer server.Close()
+	_, cleanUp := utils.PrepareTestEnvironment(t, projectName, "scanpullrequest")
+	defer cleanUp()
+
+	client, err := vcsclient.NewClientBuilder(vcsutils.GitLab).ApiEndpoint(server.URL).Token("123456").Build()
+	assert.NoError(t, err)
+	repoConfig := &utils.FrogbotRepoConfig{Params: utils.Params{Git: utils.Git{GitProvider: vcsutils.GitLab, RepoOwner: namespace, RepoName: projectName, PullRequestID: 1}}}
+
+	// The full path of the project is encoded when downloading the repository and when commenting on the merge request
+	wd, cleanupRepo, err := utils.DownloadRepoToTempDir(client, "master", &repoConfig.Git)
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, cleanupRepo())
+	}()
+	assert.FileExists(t, filepath.Join(wd, "package.json"))
+	assert.NoError(t, addPullRequestComment(repoConfig, client, "comment"))
+	assert.Contains(t, requestedURIs, "/api/v4/projects/jfrog%2Fsecurity%2Ffrogbot%2Fclean-test-proj/repository/archive.tar.gz?sha=master")
+	assert.Contains(t, requestedURIs, "/api/v4/projects/jfrog%2Fsecurity%2Ffrogbot%2Fclean-test-proj/merge_requests/1/notes")
+}
+
 func testScanPullRequest(t *testing.T, configPath, projectName string, failOnSecurityIssues bool) {
+	testScanPullRequestWithNamespace(t, configPath, "jfrog", projectName, failOnSecurityIssues)
+}
+
+func testScanPullRequestWithNamespace(t *testing.T, configPath, namespace, projectName string, failOnSecurityIssues bool) {
 	params, restoreEnv := verifyEnv(t)
 	defer restoreEnv()
 
 	// Create mock GitLab server
-	server := httptest.NewServer(createGitLabHandler(t, projectName))
+	server := httptest.NewServer(createGitLabHandler(t, namespace, projectName))
 	defer server.Close()
 
-	configAggregator, client := prepareConfigAndClient(t, configPath, failOnSecurityIssues, server, params)
+	configAggregator, client := prepareConfigAndClient(t, configPath, namespace, failOnSecurityIssues, server, params)
 	_, cleanUp := utils.PrepareTestEnvironment(t, projectName, "scanpullrequest")
 	defer cleanUp()
 
@@ -535,10 +861,10 @@ func TestVerifyGitHubFrogbotEnvironmentOnPrem(t *testing.T) {
 	assert.NoError(t, err)
 }
 
-func prepareConfigAndClient(t *testing.T, configPath string, failOnSecurityIssues bool, server *httptest.Server, serverParams coreconfig.ServerDetails) (utils.FrogbotConfigAggregator, vcsclient.VcsClient) {
+func prepareConfigAndClient(t *testing.T, configPath, namespace string, failOnSecurityIssues bool, server *httptest.Server, serverParams coreconfig.ServerDetails) (utils.FrogbotConfigAggregator, vcsclient.VcsClient) {
 	gitParams := utils.Git{
 		GitProvider:   vcsutils.GitLab,
-		RepoOwner:     "jfrog",
+		RepoOwner:     namespace,
 		Token:         "123456",
 		ApiEndpoint:   server.URL,
 		PullRequestID: 1,
@@ -565,8 +891,10 @@ func TestScanPullRequestError(t *testing.T) {
 	assert.Error(t, app.Run([]string{"frogbot", "spr"}))
 }
 
-// Create HTTP handler to mock GitLab server
-func createGitLabHandler(t *testing.T, projectName string) http.HandlerFunc {
+// Create HTTP handler to mock GitLab server.
+// The namespace may be nested in subgroups, such as "group/subgroup", and GitLab expects the full path of the project to be URL-encoded.
+func createGitLabHandler(t *testing.T, namespace, projectName string) http.HandlerFunc {
+	projectPath := url.PathEscape(namespace + "/" + projectName)
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Return 200 on ping
 		if r.RequestURI == "/api/v4/" {
@@ -575,7 +903,7 @@ func createGitLabHandler(t *testing.T, projectName string) http.HandlerFunc {
 		}
 
 		// Return test-proj.tar.gz when using DownloadRepository
-		if r.RequestURI == fmt.Sprintf("/api/v4/projects/jfrog%s/repository/archive.tar.gz?sha=master", "%2F"+projectName) {
+		if r.RequestURI == fmt.Sprintf("/api/v4/projects/%s/repository/archive.tar.gz?sha=master", projectPath) {
 			w.WriteHeader(http.StatusOK)
 			repoFile, err := os.ReadFile(filepath.Join("..", projectName+".tar.gz"))
 			assert.NoError(t, err)
@@ -583,7 +911,7 @@ func createGitLabHandler(t *testing.T, projectName string) http.HandlerFunc {
 			assert.NoError(t, err)
 		}
 		// clean-test-proj should not include any vulnerabilities so assertion is not needed.
-		if r.RequestURI == fmt.Sprintf("/api/v4/projects/jfrog%s/merge_requests/1/notes", "%2Fclean-test-proj") {
+		if projectName == "clean-test-proj" && r.RequestURI == fmt.Sprintf("/api/v4/projects/%s/merge_requests/1/notes", projectPath) {
 			w.WriteHeader(http.StatusOK)
 			_, err := w.Write([]byte("{}"))
 			assert.NoError(t, err)
@@ -591,7 +919,7 @@ func createGitLabHandler(t *testing.T, projectName string) http.HandlerFunc {
 		}
 
 		// Return 200 when using the REST that creates the comment
-		if r.RequestURI == fmt.Sprintf("/api/v4/projects/jfrog%s/merge_requests/1/notes", "%2F"+projectName) {
+		if r.RequestURI == fmt.Sprintf("/api/v4/projects/%s/merge_requests/1/notes", projectPath) {
 			buf := new(bytes.Buffer)
 			_, err := buf.ReadFrom(r.Body)
 			assert.NoError(t, err)
@@ -657,6 +985,21 @@ func TestGetFullPathWorkingDirs(t *testing.T) {
 	}
 }
 
+func TestGetFullPathWorkingDirsAutoDetect(t *testing.T) {
+	baseWd := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(baseWd, "web"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(baseWd, "web", "package.json"), []byte("{}"), 0644))
+	project := &utils.Project{AutoDetectWorkingDirs: true}
+	assert.Equal(t, []string{filepath.Join(baseWd, "web")}, getFullPathWorkingDirs(project, baseWd))
+	// The dirs detected in the source branch are reused in the target branch, which isn't walked again
+	targetWd := t.TempDir()
+	assert.Equal(t, []string{filepath.Join(targetWd, "web")}, getFullPathWorkingDirs(project, targetWd))
+
+	// The root is scanned if no manifests are detected
+	project = &utils.Project{AutoDetectWorkingDirs: true, AutoDetectExcludes: []string{"web"}}
+	assert.Equal(t, []string{baseWd}, getFullPathWorkingDirs(project, baseWd))
+}
+
 // Set new logger with output redirection to a null logger. This is useful for negative tests.
 // Caller is responsible to set the old log back.
 func redirectLogOutputToNil() (previousLog log.Log) {
@@ -667,3 +1010,178 @@ func redirectLogOutputToNil() (previousLog log.Log) {
 	log.SetLogger(newLog)
 	return previousLog
 }
+
+func TestCreateXrayScansNote(t *testing.T) {
+	assert.Empty(t, createXrayScansNote(nil))
+
+	results := &auditResults{}
+	results.addXrayScans([]services.ScanResponse{
+		{ScanId: "scan-1", XrayDataUrl: "https://xray.example.com/ui/scans/scan-1", Vulnerabilities: []services.Vulnerability{{IssueId: "XRAY-1"}}},
+		{},
+		{ScanId: "scan-2"},
+		{XrayDataUrl: "https://xray.example.com/ui/scans/3"},
+	})
+	assert.Equal(t, "\n\n🔍 **View in Xray:** [scan-1](https://xray.example.com/ui/scans/scan-1) · `scan-2` · [scan 3](https://xray.example.com/ui/scans/3)",
+		createXrayScansNote(results.xrayScans))
+}
+
+func TestAddProjectIssuesOnlyWithExploits(t *testing.T) {
+	exploited := formats.VulnerabilityOrViolationRow{Severity: "Low", IssueId: "XRAY-1", JfrogResearchInformation: &formats.JfrogResearchInformation{
+		SeverityReasons: []formats.JfrogResearchSeverityReason{{Name: "The issue has an exploit published"}}}}
+	rows := []formats.VulnerabilityOrViolationRow{{Severity: "Critical", IssueId: "XRAY-2"}, exploited}
+
+	results := &auditResults{onlyWithExploits: true}
+	results.addProjectIssues(&utils.Project{}, rows)
+	assert.Equal(t, []formats.VulnerabilityOrViolationRow{exploited}, results.vulnerabilitiesRows)
+	assert.True(t, results.failingIssuesFound)
+
+	// Issues without a known exploit don't fail the scan
+	results = &auditResults{onlyWithExploits: true}
+	results.addProjectIssues(&utils.Project{}, rows[:1])
+	assert.Empty(t, results.vulnerabilitiesRows)
+	assert.False(t, results.failingIssuesFound)
+
+	results = &auditResults{}
+	results.addProjectIssues(&utils.Project{}, rows)
+	assert.Len(t, results.vulnerabilitiesRows, 2)
+}
+
+func TestAddProjectIssuesMinCvss(t *testing.T) {
+	rows := []formats.VulnerabilityOrViolationRow{{Severity: "Critical", IssueId: "XRAY-1", Cves: []formats.CveRow{{CvssV3: "6.5"}}}}
+
+	// The issues below the minimal CVSS score are shown, but don't fail the scan
+	results := &auditResults{cvssPolicy: utils.CvssPolicy{MinCvss: 7}}
+	results.addProjectIssues(&utils.Project{}, rows)
+	assert.Equal(t, rows, results.vulnerabilitiesRows)
+	assert.False(t, results.failingIssuesFound)
+
+	results = &auditResults{cvssPolicy: utils.CvssPolicy{MinCvss: 7, HideBelowMinCvss: true}}
+	results.addProjectIssues(&utils.Project{}, rows)
+	assert.Empty(t, results.vulnerabilitiesRows)
+	assert.False(t, results.failingIssuesFound)
+
+	results = &auditResults{cvssPolicy: utils.CvssPolicy{MinCvss: 6.5}}
+	results.addProjectIssues(&utils.Project{}, rows)
+	assert.True(t, results.failingIssuesFound)
+}
+
+func TestAddProjectIssuesEcosystemPolicies(t *testing.T) {
+	project := &utils.Project{
+		SeverityPolicy:    utils.SeverityPolicy{FailSeverityThreshold: "High"},
+		EcosystemPolicies: map[string]utils.SeverityPolicy{"npm": {FailSeverityThreshold: "Medium"}},
+	}
+	// A Medium issue fails the scan in the npm working dirs only
+	results := &auditResults{}
+	results.addProjectIssues(project, []formats.VulnerabilityOrViolationRow{{Severity: "Medium", IssueId: "XRAY-1", Technology: coreutils.Go}})
+	assert.False(t, results.failingIssuesFound)
+
+	results.addProjectIssues(project, []formats.VulnerabilityOrViolationRow{{Severity: "Medium", IssueId: "XRAY-2", Technology: coreutils.Npm}})
+	assert.True(t, results.failingIssuesFound)
+	assert.Len(t, results.vulnerabilitiesRows, 2)
+}
+
+func TestAddProjectIssuesUnknownSeverityAs(t *testing.T) {
+	rows := []formats.VulnerabilityOrViolationRow{
+		{Severity: "Medium", SeverityNumValue: 2, IssueId: "XRAY-1"},
+		{Severity: "Unknown", IssueId: "XRAY-2"},
+	}
+	project := &utils.Project{SeverityPolicy: utils.SeverityPolicy{FailSeverityThreshold: "High"}}
+
+	// By default, the unknown severity is the lowest tier, and doesn't fail the scan
+	results := &auditResults{}
+	results.addProjectIssues(project, rows)
+	assert.Equal(t, rows, results.vulnerabilitiesRows)
+	assert.False(t, results.failingIssuesFound)
+
+	// The mapped issues are sorted and gated as issues of their mapped severity
+	results = &auditResults{unknownSeverityAs: "High"}
+	results.addProjectIssues(project, rows)
+	if assert.Len(t, results.vulnerabilitiesRows, 2) {
+		assert.Equal(t, "XRAY-2", results.vulnerabilitiesRows[0].IssueId)
+		assert.Equal(t, "High", results.vulnerabilitiesRows[0].Severity)
+	}
+	assert.True(t, results.failingIssuesFound)
+
+	results = &auditResults{unknownSeverityAs: utils.IgnoreUnknownSeverity}
+	results.addProjectIssues(&utils.Project{}, rows)
+	assert.Equal(t, rows[:1], results.vulnerabilitiesRows)
+}
+
+func TestAddProjectIssuesIgnoredDependencies(t *testing.T) {
+	rows := []formats.VulnerabilityOrViolationRow{
+		{Severity: "Critical", ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "4.17.15", IssueId: "XRAY-1"},
+		{Severity: "Low", ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "0.0.8", IssueId: "XRAY-2"},
+	}
+	// The issues of the ignored dependencies are kept separately, and don't fail the scan
+	results := &auditResults{}
+	results.addProjectIssues(&utils.Project{IgnoredDependencies: []string{"lodash <4.17.21"}, SeverityPolicy: utils.SeverityPolicy{FailSeverityThreshold: "High"}}, rows)
+	assert.Equal(t, rows[1:], results.vulnerabilitiesRows)
+	assert.Equal(t, []utils.SuppressedIssue{{VulnerabilityOrViolationRow: rows[0], IgnoredDependency: "lodash <4.17.21"}}, results.suppressedIssues)
+	assert.False(t, results.failingIssuesFound)
+}
+
+func TestAddProjectIssuesPathIgnored(t *testing.T) {
+	rows := []formats.VulnerabilityOrViolationRow{{Severity: "Critical", IssueId: "XRAY-1"}}
+	repoConfig := &utils.FrogbotRepoConfig{Params: utils.Params{PathIgnores: []string{"examples/", "testdata/"}}}
+
+	// The issues of the ignored paths are shown, but don't fail the scan
+	results := &auditResults{}
+	results.addProjectIssues(&utils.Project{PathIgnored: true}, rows)
+	results.addProjectIacIssues(&utils.Project{PathIgnored: true}, []utils.IacRow{{Severity: "High"}})
+	assert.Equal(t, rows, results.vulnerabilitiesRows)
+	assert.Len(t, results.iacRows, 1)
+	assert.False(t, results.failingIssuesFound)
+	assert.Equal(t, "\n\n📁 The issues found in the paths which match the pathIgnores patterns (`examples/`, `testdata/`) are shown, but don't fail the scan.", createPathIgnoresNote(repoConfig, results))
+
+	results = &auditResults{}
+	results.addProjectIssues(&utils.Project{}, rows)
+	assert.True(t, results.failingIssuesFound)
+	assert.Empty(t, createPathIgnoresNote(repoConfig, results))
+
+	// In the root working dir, the misconfigurations and the secrets are matched by their files
+	results = &auditResults{secrets: []secretRow{{file: "examples/demo/.env"}}}
+	project := &utils.Project{PathIgnores: repoConfig.PathIgnores}
+	results.addProjectIacIssues(project, []utils.IacRow{{Severity: "High", File: "examples/chart/values.yaml"}})
+	results.addSecretsGating(repoConfig)
+	assert.False(t, results.failingIssuesFound)
+	assert.True(t, results.pathIgnoredIssuesFound)
+	results.addProjectIacIssues(project, []utils.IacRow{{Severity: "High", File: "chart/values.yaml"}})
+	assert.True(t, results.failingIssuesFound)
+}
+
+func TestAddProjectIssuesPathIgnoredModule(t *testing.T) {
+	baseWd := t.TempDir()
+	restoreDir, err := utils.Chdir(baseWd)
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, restoreDir())
+	}()
+	assert.NoError(t, os.MkdirAll(filepath.Join("examples", "demo"), 0700))
+	assert.NoError(t, os.WriteFile(filepath.Join("examples", "demo", "package.json"), []byte(`{"name": "demo"}`), 0600))
+
+	// The issues of the modules under the ignored paths don't fail the scan, even if the root of the repository is scanned
+	rows := []formats.VulnerabilityOrViolationRow{{Severity: "Critical", IssueId: "XRAY-1", ImpactedDependencyName: "lodash",
+		ImpactPaths: [][]formats.ComponentRow{{{Name: "demo"}, {Name: "lodash"}}}}}
+	results := &auditResults{}
+	results.addProjectIssues(&utils.Project{WorkingDirs: []string{utils.RootDir}, PathIgnores: []string{"examples/"}}, rows)
+	assert.Equal(t, rows, results.vulnerabilitiesRows)
+	assert.False(t, results.failingIssuesFound)
+	assert.True(t, results.pathIgnoredIssuesFound)
+	assert.True(t, results.pathIgnoredIssues[getUniqueID(rows[0])])
+}
+
+func TestCreateFailureCommentNote(t *testing.T) {
+	failOnSecurityIssues := true
+	repoConfig := &utils.FrogbotRepoConfig{Params: utils.Params{
+		Scan:           utils.Scan{FailOnSecurityIssues: &failOnSecurityIssues},
+		FailureComment: "Contact #security to request an exception",
+	}}
+	assert.Equal(t, "\n\n🚫 Contact #security to request an exception", createFailureCommentNote(repoConfig, &auditResults{failingIssuesFound: true}))
+	// The failure comment isn't added to the passing scans
+	assert.Empty(t, createFailureCommentNote(repoConfig, &auditResults{}))
+	failOnSecurityIssues = false
+	assert.Empty(t, createFailureCommentNote(repoConfig, &auditResults{failingIssuesFound: true}))
+	failOnSecurityIssues = true
+	repoConfig.FailureComment = ""
+	assert.Empty(t, createFailureCommentNote(repoConfig, &auditResults{failingIssuesFound: true}))
+}
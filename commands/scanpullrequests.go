@@ -9,22 +9,19 @@ import (
 
 	"github.com/jfrog/frogbot/commands/utils"
 	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
 )
 
-var errPullRequestScan = "pull Request number %d in repository %s returned the following error: \n%s\n"
+var errPullRequestScan = "pull Request number %d in repository %s returned the following error: \n%w"
 
 type ScanAllPullRequestsCmd struct {
 }
 
 func (cmd ScanAllPullRequestsCmd) Run(configAggregator utils.FrogbotConfigAggregator, client vcsclient.VcsClient) error {
-	for _, config := range configAggregator {
-		err := scanAllPullRequests(config, client)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return utils.RunOnRepositories(configAggregator, client, func(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient) error {
+		return scanAllPullRequests(*repoConfig, client)
+	})
 }
 
 // Scan pull requests as follows:
@@ -35,27 +32,24 @@ func (cmd ScanAllPullRequestsCmd) Run(configAggregator utils.FrogbotConfigAggreg
 func scanAllPullRequests(repo utils.FrogbotRepoConfig, client vcsclient.VcsClient) (err error) {
 	openPullRequests, err := client.ListOpenPullRequests(context.Background(), repo.RepoOwner, repo.RepoName)
 	if err != nil {
-		return err
+		return &VcsError{Err: err}
 	}
-	var errList strings.Builder
+	// The errors are joined, rather than flattened to a string, so that their types are kept
+	var errs []error
 	for _, pr := range openPullRequests {
 		shouldScan, e := shouldScanPullRequest(repo, client, int(pr.ID))
 		if e != nil {
-			errList.WriteString(fmt.Sprintf(errPullRequestScan, int(pr.ID), repo.RepoName, e.Error()))
+			errs = append(errs, fmt.Errorf(errPullRequestScan, int(pr.ID), repo.RepoName, e))
 		}
 		if shouldScan {
 			e = downloadAndScanPullRequest(pr, repo, client)
-			// If error, write it in errList and continue to the next PR.
+			// If error, add it to errs and continue to the next PR.
 			if e != nil {
-				errList.WriteString(fmt.Sprintf(errPullRequestScan, int(pr.ID), repo.RepoName, e.Error()))
+				errs = append(errs, fmt.Errorf(errPullRequestScan, int(pr.ID), repo.RepoName, e))
 			}
 		}
 	}
-
-	if errList.String() != "" {
-		err = errors.New(errList.String())
-	}
-	return
+	return errors.Join(errs...)
 }
 
 func shouldScanPullRequest(repo utils.FrogbotRepoConfig, client vcsclient.VcsClient, prID int) (shouldScan bool, err error) {
@@ -87,12 +81,30 @@ func isFrogbotRescanComment(comment string) bool {
 }
 
 func downloadAndScanPullRequest(pr vcsclient.PullRequestInfo, repo utils.FrogbotRepoConfig, client vcsclient.VcsClient) error {
-	// Download the pull request source ("from") branch
+	return downloadPullRequest(pr, repo, client, func(frogbotParams *utils.FrogbotRepoConfig) error {
+		return scanPullRequest(frogbotParams, client)
+	})
+}
+
+// downloadPullRequest downloads the source branch of the pull request, and runs runFunc in it, with the params of the pull request scan
+func downloadPullRequest(pr vcsclient.PullRequestInfo, repo utils.FrogbotRepoConfig, client vcsclient.VcsClient, runFunc func(frogbotParams *utils.FrogbotRepoConfig) error) (err error) {
+	// Download the pull request source ("from") branch, which may belong to a fork with a different owner
+	sourceOwner := repo.RepoOwner
+	var headCommitSha string
+	if repo.GitProvider == vcsutils.GitHub {
+		if head, e := getGitHubPullRequestHead(&repo.Git, int(pr.ID)); e == nil {
+			sourceOwner = head.owner
+			headCommitSha = head.sha
+		} else {
+			log.Warn("couldn't get the owner of the source branch of pull request", pr.ID, "- assuming it's", sourceOwner+":", e.Error())
+		}
+	}
 	params := utils.Params{Git: utils.Git{
 		GitProvider: repo.GitProvider,
 		Token:       repo.Token,
+		GitHubApp:   repo.GitHubApp,
 		ApiEndpoint: repo.ApiEndpoint,
-		RepoOwner:   repo.RepoOwner,
+		RepoOwner:   sourceOwner,
 		RepoName:    pr.Source.Repository,
 		Branches:    []string{pr.Source.Name}},
 	}
@@ -111,7 +123,7 @@ func downloadAndScanPullRequest(pr vcsclient.PullRequestInfo, repo utils.Frogbot
 			err = e
 		}
 	}()
-	restoreDir, err := utils.Chdir(wd)
+	restoreDir, err := utils.ChdirExclusively(wd)
 	if err != nil {
 		return err
 	}
@@ -121,32 +133,33 @@ func downloadAndScanPullRequest(pr vcsclient.PullRequestInfo, repo utils.Frogbot
 			err = e
 		}
 	}()
-	// The target branch (to) will be downloaded as part of the Frogbot scanPullRequest execution
-	params = utils.Params{
-		Scan: utils.Scan{
-			FailOnSecurityIssues:      repo.FailOnSecurityIssues,
-			IncludeAllVulnerabilities: repo.IncludeAllVulnerabilities,
-			Projects:                  repo.Projects,
-		},
-		Git: utils.Git{
-			GitProvider:   repo.GitProvider,
-			Token:         repo.Token,
-			ApiEndpoint:   repo.ApiEndpoint,
-			RepoOwner:     repo.RepoOwner,
-			Branches:      []string{pr.Target.Name},
-			RepoName:      pr.Target.Repository,
-			PullRequestID: int(pr.ID),
-		},
-		JFrogPlatform: utils.JFrogPlatform{
-			Watches:         repo.Watches,
-			JFrogProjectKey: repo.JFrogProjectKey,
-		},
-	}
+	// The target branch (to) will be downloaded as part of the Frogbot scanPullRequest execution.
+	// All the params of the repository apply to the pull request, except for the git params of the pull request itself.
+	params = repo.Params
+	params.Projects = append([]utils.Project{}, repo.Projects...)
+	params.Branches = []string{pr.Target.Name}
+	params.RepoName = pr.Target.Repository
+	params.PullRequestID = int(pr.ID)
 
 	frogbotParams = &utils.FrogbotRepoConfig{
-		OutputWriter: utils.GetCompatibleOutputWriter(repo.GitProvider),
-		Server:       repo.Server,
-		Params:       params,
+		OutputWriter:    utils.GetCompatibleOutputWriter(repo.GitProvider, repo.SeverityColors, repo.MaxFixedVersions, repo.CompactTable),
+		Server:          repo.Server,
+		Params:          params,
+		CommentTemplate: repo.CommentTemplate,
+		HeadCommitSha:   headCommitSha,
+	}
+	// The downloaded code isn't a git repository, so the head commit is read from the source branch
+	if frogbotParams.CommitStatusContext != "" && frogbotParams.HeadCommitSha == "" {
+		if commit, e := client.GetLatestCommit(context.Background(), sourceOwner, pr.Source.Repository, pr.Source.Name); e == nil {
+			frogbotParams.HeadCommitSha = commit.Hash
+		} else {
+			log.Warn("couldn't get the head commit of pull request", pr.ID, "for the commit status:", e.Error())
+		}
+	}
+	if utils.IsConfigFromRepo() {
+		if err = frogbotParams.ApplyRepoConfig(wd); err != nil {
+			return err
+		}
 	}
-	return scanPullRequest(frogbotParams, client)
+	return runFunc(frogbotParams)
 }
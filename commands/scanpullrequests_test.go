@@ -2,7 +2,9 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"testing"
 	"time"
@@ -11,6 +13,7 @@ import (
 	"github.com/jfrog/frogbot/commands/testdata"
 	"github.com/jfrog/frogbot/commands/utils"
 	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	"github.com/stretchr/testify/assert"
 )
@@ -89,6 +92,21 @@ func mockVcsClient(t *testing.T) *testdata.MockVcsClient {
 	return testdata.NewMockVcsClient(mockCtrl)
 }
 
+// newTestRepoConfig returns the config of the jfrog/frogbot repository, whose Git provider API is served at apiEndpoint.
+// The tests set the params they exercise on the returned config.
+func newTestRepoConfig(provider vcsutils.VcsProvider, apiEndpoint string) *utils.FrogbotRepoConfig {
+	return &utils.FrogbotRepoConfig{
+		OutputWriter: &utils.StandardOutput{},
+		Params: utils.Params{Git: utils.Git{
+			GitProvider: provider,
+			RepoOwner:   "jfrog",
+			RepoName:    "frogbot",
+			Token:       "123456",
+			ApiEndpoint: apiEndpoint,
+		}},
+	}
+}
+
 func TestShouldNotScanPullRequestError(t *testing.T) {
 	// Init mock
 	client := mockVcsClient(t)
@@ -221,3 +239,42 @@ func fakeRepoDownload(_ context.Context, _, _, testProject, targetDir string) er
 	}
 	return fileutils.CopyDir(sourceDir, targetDir, true, []string{})
 }
+
+func TestDownloadPullRequestParams(t *testing.T) {
+	repo := utils.FrogbotRepoConfig{Params: utils.Params{
+		Git:                  utils.Git{GitProvider: vcsutils.BitbucketServer, RepoOwner: "jfrog", RepoName: "frogbot", Branches: []string{"main", "dev"}},
+		Scan:                 utils.Scan{Projects: []utils.Project{{WorkingDirs: []string{"api"}}}},
+		PreventDuplicateRuns: true,
+		UpgradeStrategy:      utils.MinimalUpgradeStrategy,
+	}}
+	pr := vcsclient.PullRequestInfo{ID: 7, Source: vcsclient.BranchInfo{Name: "feature", Repository: "frogbot"}, Target: vcsclient.BranchInfo{Name: "dev", Repository: "frogbot"}}
+	client := mockVcsClient(t)
+	client.EXPECT().DownloadRepository(context.Background(), "jfrog", "frogbot", "feature", gomock.Any()).DoAndReturn(func(_ context.Context, _, _, _, targetDir string) error {
+		return os.WriteFile(filepath.Join(targetDir, "go.mod"), []byte("module frogbot"), 0600)
+	})
+	assert.NoError(t, downloadPullRequest(pr, repo, client, func(frogbotParams *utils.FrogbotRepoConfig) error {
+		// All the params of the repository apply to the pull request
+		assert.True(t, frogbotParams.PreventDuplicateRuns)
+		assert.Equal(t, utils.MinimalUpgradeStrategy, frogbotParams.UpgradeStrategy)
+		assert.Equal(t, repo.Projects, frogbotParams.Projects)
+		assert.Equal(t, []string{"dev"}, frogbotParams.Branches)
+		assert.Equal(t, 7, frogbotParams.PullRequestID)
+		return nil
+	}))
+	// The params of the repository aren't changed
+	assert.Equal(t, []string{"main", "dev"}, repo.Branches)
+}
+
+func TestScanAllPullRequestsJoinedErrors(t *testing.T) {
+	repo := utils.FrogbotRepoConfig{Params: utils.Params{Git: utils.Git{RepoOwner: "jfrog", RepoName: "frogbot"}}}
+	client := mockVcsClient(t)
+	client.EXPECT().ListOpenPullRequests(context.Background(), "jfrog", "frogbot").Return([]vcsclient.PullRequestInfo{{ID: 1}, {ID: 2}}, nil)
+	client.EXPECT().ListPullRequestComments(context.Background(), "jfrog", "frogbot", 1).Return(nil, &SecurityIssuesFoundError{})
+	client.EXPECT().ListPullRequestComments(context.Background(), "jfrog", "frogbot", 2).Return(nil, errors.New("forbidden"))
+	err := scanAllPullRequests(repo, client)
+	assert.EqualError(t, err, fmt.Errorf(errPullRequestScan, 1, "frogbot", &SecurityIssuesFoundError{}).Error()+"\n"+fmt.Errorf(errPullRequestScan, 2, "frogbot", errors.New("forbidden")).Error())
+	// The types of the errors are kept
+	var securityIssuesFoundErr *SecurityIssuesFoundError
+	assert.ErrorAs(t, err, &securityIssuesFoundErr)
+	assert.Equal(t, "issues_found", getRunResult(err))
+}
@@ -0,0 +1,281 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	secretsTitle       = "\n\n#### 🔑 Secrets"
+	secretsTableHeader = "\n| FILE | LINE | TYPE | SECRET |\n" + "-- | :--: | -- | --"
+	secretsTableRow    = "\n| %s | %d | %s | `%s` |"
+	// Files larger than this size are skipped, since secrets are committed in source and config files
+	maxSecretsScanFileSize = 1024 * 1024
+	// The number of characters of the secret shown in the comment, followed by the redaction mask
+	secretVisiblePrefixLength = 4
+	secretRedactionMask       = "********"
+)
+
+// A secret type, detected by the pattern of its values
+type secretDetector struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// The detectors match well-known credential formats only, to avoid reporting random strings as secrets
+var secretDetectors = []secretDetector{
+	{name: "AWS access key ID", pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{name: "GitHub token", pattern: regexp.MustCompile(`\bgh[pousr]_[0-9A-Za-z]{36,255}\b`)},
+	{name: "GitLab personal access token", pattern: regexp.MustCompile(`\bglpat-[0-9A-Za-z_\-]{20,}`)},
+	{name: "Slack token", pattern: regexp.MustCompile(`\bxox[abposr]-[0-9A-Za-z\-]{10,}`)},
+	{name: "Google API key", pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}`)},
+	{name: "Stripe secret key", pattern: regexp.MustCompile(`\b[rs]k_live_[0-9A-Za-z]{24,}`)},
+	{name: "JFrog access token", pattern: regexp.MustCompile(`\bcmVmdGtuOjAxOj[0-9A-Za-z]{40,}`)},
+	{name: "Private key", pattern: regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |ENCRYPTED |PGP )?PRIVATE KEY(?: BLOCK)?-----`)},
+}
+
+// The directories skipped by the secrets scan
+var secretsSkippedDirs = []string{".git", "node_modules", "vendor"}
+
+// secretRow is a secret found in a line added by the pull request. The value of the secret is kept redacted only.
+type secretRow struct {
+	// The path of the file, relative to the root of the repository
+	file string
+	line int
+	// The name of the secret type, such as "AWS access key ID"
+	secretType    string
+	redactedValue string
+}
+
+// The secrets fail the scan, unless they're found in files which match the pathIgnores patterns
+func (results *auditResults) addSecretsGating(repoConfig *utils.FrogbotRepoConfig) {
+	for _, secret := range results.secrets {
+		if repoConfig.IsPathIgnored(secret.file) {
+			results.pathIgnoredIssuesFound = true
+		} else {
+			results.failingIssuesFound = true
+		}
+	}
+}
+
+// auditPullRequestSecrets scans the lines added or changed by the pull request for secrets.
+// The lines are the lines of the source branch, which don't exist in the same file in the target branch.
+func auditPullRequestSecrets(targetBranch *targetBranchCheckout) (secrets []secretRow, err error) {
+	sourceDir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Scanning the changes of the pull request for secrets")
+	targetDir, err := targetBranch.getDir()
+	if err != nil {
+		return nil, err
+	}
+	if secrets, err = scanChangedFilesForSecrets(sourceDir, targetDir); err != nil {
+		return nil, err
+	}
+	// The secrets themselves are never logged
+	log.Info(fmt.Sprintf("The secrets scan found %d secrets", len(secrets)))
+	return secrets, nil
+}
+
+// Scan the lines of the files in sourceDir, which don't exist in the same file in targetDir.
+// The files which can't be committed by the pull request aren't scanned, such as the files generated by the install commands:
+// the files matched by the .gitignore files of sourceDir, and the files of nested repositories, such as git submodules.
+func scanChangedFilesForSecrets(sourceDir, targetDir string) (secrets []secretRow, err error) {
+	var ignorePatterns []gitignore.Pattern
+	err = filepath.WalkDir(sourceDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relativePath, err := filepath.Rel(sourceDir, path)
+		if err != nil {
+			return err
+		}
+		pathComponents := strings.Split(filepath.ToSlash(relativePath), "/")
+		if entry.IsDir() {
+			if path == sourceDir {
+				pathComponents = nil
+			} else if isSecretsSkippedDir(entry.Name()) || gitignore.NewMatcher(ignorePatterns).Match(pathComponents, true) || isNestedRepository(path) {
+				return filepath.SkipDir
+			}
+			dirPatterns, err := readGitignorePatterns(path, pathComponents)
+			if err != nil {
+				return err
+			}
+			// The patterns of nested dirs come last, so that they take precedence over the patterns of their parents
+			ignorePatterns = append(ignorePatterns, dirPatterns...)
+			return nil
+		}
+		if !entry.Type().IsRegular() || gitignore.NewMatcher(ignorePatterns).Match(pathComponents, false) {
+			return nil
+		}
+		fileSecrets, err := scanChangedFileForSecrets(path, filepath.Join(targetDir, relativePath))
+		if err != nil {
+			return err
+		}
+		for i := range fileSecrets {
+			fileSecrets[i].file = filepath.ToSlash(relativePath)
+		}
+		secrets = append(secrets, fileSecrets...)
+		return nil
+	})
+	return
+}
+
+// Read the patterns of the .gitignore file in dir, if it exists. The patterns apply to the files under the dir, whose path components are given.
+func readGitignorePatterns(dir string, dirComponents []string) (patterns []gitignore.Pattern, err error) {
+	content, err := os.ReadFile(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.TrimSpace(line) != "" && !strings.HasPrefix(line, "#") {
+			patterns = append(patterns, gitignore.ParsePattern(line, dirComponents))
+		}
+	}
+	return patterns, nil
+}
+
+// A nested repository, such as a git submodule, has a .git dir or file of its own
+func isNestedRepository(dir string) bool {
+	_, err := os.Lstat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+func isSecretsSkippedDir(name string) bool {
+	for _, skippedDir := range secretsSkippedDirs {
+		if name == skippedDir {
+			return true
+		}
+	}
+	return false
+}
+
+func scanChangedFileForSecrets(sourcePath, targetPath string) ([]secretRow, error) {
+	info, err := os.Stat(sourcePath)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > maxSecretsScanFileSize {
+		return nil, nil
+	}
+	content, err := os.ReadFile(sourcePath)
+	if err != nil {
+		return nil, err
+	}
+	if isBinaryContent(content) {
+		return nil, nil
+	}
+	addedLines, err := getAddedLines(content, targetPath)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't find the lines added to %s: %w", sourcePath, err)
+	}
+	var secrets []secretRow
+	for _, line := range addedLines {
+		for _, secret := range findSecrets(line.text) {
+			secret.line = line.number
+			secrets = append(secrets, secret)
+		}
+	}
+	return secrets, nil
+}
+
+// A line of a file in the source branch, which doesn't exist in the same file in the target branch
+type addedLine struct {
+	// The line number, starting from 1
+	number int
+	text   string
+}
+
+// Return the lines of the source content which don't exist in the file in targetPath. All the lines are added if the target file doesn't exist.
+func getAddedLines(sourceContent []byte, targetPath string) (addedLines []addedLine, err error) {
+	// Count the lines of the target file, so that a line duplicated by the pull request is considered as added
+	targetLines := make(map[string]int)
+	if targetContent, err := os.ReadFile(targetPath); err == nil {
+		lines, err := splitLines(targetContent)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't read the lines of %s: %w", targetPath, err)
+		}
+		for _, line := range lines {
+			targetLines[line]++
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	sourceLines, err := splitLines(sourceContent)
+	if err != nil {
+		return nil, err
+	}
+	for index, line := range sourceLines {
+		if targetLines[line] > 0 {
+			targetLines[line]--
+			continue
+		}
+		addedLines = append(addedLines, addedLine{number: index + 1, text: line})
+	}
+	return addedLines, nil
+}
+
+// Files with a NUL byte in their first 8000 bytes are considered binary, like in git
+func isBinaryContent(content []byte) bool {
+	if len(content) > 8000 {
+		content = content[:8000]
+	}
+	return bytes.IndexByte(content, 0) >= 0
+}
+
+// Split the content into lines. Lines longer than the size limit of the scanned files fail the split, rather than being dropped with the rest of the file.
+func splitLines(content []byte) (lines []string, err error) {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	// The buffer holds a line as long as a scanned file, and the line break after it
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSecretsScanFileSize+1)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+// Find the secrets in a single line, and redact their values
+func findSecrets(line string) (secrets []secretRow) {
+	for _, detector := range secretDetectors {
+		for _, value := range detector.pattern.FindAllString(line, -1) {
+			secrets = append(secrets, secretRow{secretType: detector.name, redactedValue: redactSecret(value)})
+		}
+	}
+	return
+}
+
+// Keep only the first characters of the secret, which identify its type, such as "AKIA********".
+// Short values are redacted completely.
+func redactSecret(value string) string {
+	if len(value) <= secretVisiblePrefixLength*3 {
+		return secretRedactionMask
+	}
+	return value[:secretVisiblePrefixLength] + secretRedactionMask
+}
+
+// Create the Secrets section of the comment, which lists the secrets with their redacted values
+func createSecretsContent(secrets []secretRow) string {
+	if len(secrets) == 0 {
+		return ""
+	}
+	var tableContent strings.Builder
+	for _, secret := range secrets {
+		tableContent.WriteString(fmt.Sprintf(secretsTableRow, secret.file, secret.line, secret.secretType, secret.redactedValue))
+	}
+	return secretsTitle + "\n" + secretsTableHeader + tableContent.String()
+}
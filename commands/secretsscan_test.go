@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Built from parts, so that the test file itself doesn't contain secrets
+var (
+	testAwsKeyId    = "AKIA" + "IOSFODNN7EXAMPLE"
+	testGitHubToken = "ghp_" + strings.Repeat("a1B2", 9)
+	testPrivateKey  = "-----BEGIN RSA " + "PRIVATE KEY-----"
+)
+
+func TestFindSecrets(t *testing.T) {
+	secrets := findSecrets("aws_access_key_id = " + testAwsKeyId + " token: " + testGitHubToken)
+	if assert.Len(t, secrets, 2) {
+		assert.Equal(t, secretRow{secretType: "AWS access key ID", redactedValue: "AKIA********"}, secrets[0])
+		assert.Equal(t, secretRow{secretType: "GitHub token", redactedValue: "ghp_********"}, secrets[1])
+	}
+	assert.Empty(t, findSecrets("password: ${PASSWORD}"))
+	assert.Len(t, findSecrets(testPrivateKey), 1)
+}
+
+func TestRedactSecret(t *testing.T) {
+	assert.Equal(t, "AKIA********", redactSecret(testAwsKeyId))
+	assert.Equal(t, "********", redactSecret("short-secret"))
+}
+
+func TestScanChangedFilesForSecrets(t *testing.T) {
+	targetDir, sourceDir := t.TempDir(), t.TempDir()
+	writeTestFiles(t, targetDir, map[string]string{
+		"config/settings.yml": "aws:\n  key: " + testAwsKeyId + "\n",
+		"deploy.sh":           "#!/bin/bash\necho deploying\n",
+	})
+	writeTestFiles(t, sourceDir, map[string]string{
+		// The existing secret isn't reported, since it wasn't added by the pull request
+		"config/settings.yml": "aws:\n  key: " + testAwsKeyId + "\n  region: us-east-1\n",
+		"deploy.sh":           "#!/bin/bash\necho deploying\nexport GITHUB_TOKEN=" + testGitHubToken + "\n",
+		"keys/id_rsa":         testPrivateKey + "\nMIIEpAIBAAKCAQEA\n",
+		"image.png":           "\x89PNG\x00" + testAwsKeyId,
+		".git/config":         "token = " + testGitHubToken,
+		// The files generated by the install commands are absent from the target branch, and aren't committed by the pull request
+		".gitignore":                        "# Generated files\nbuild/\n*.log\n",
+		"build/generated.js":                "const token = '" + testGitHubToken + "'",
+		"install.log":                       "GITHUB_TOKEN=" + testGitHubToken,
+		"app/.gitignore":                    ".venv\n",
+		"app/.venv/lib/site-packages/a.py":  "key = '" + testAwsKeyId + "'",
+		"libs/submodule/.git":               "gitdir: ../../.git/modules/submodule",
+		"libs/submodule/config/settings.sh": "export GITHUB_TOKEN=" + testGitHubToken,
+	})
+	secrets, err := scanChangedFilesForSecrets(sourceDir, targetDir)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []secretRow{
+		{file: "deploy.sh", line: 3, secretType: "GitHub token", redactedValue: "ghp_********"},
+		{file: "keys/id_rsa", line: 1, secretType: "Private key", redactedValue: "----********"},
+	}, secrets)
+
+	// The secret values are never included in the comment
+	content := createSecretsContent(secrets)
+	assert.NotContains(t, content, testGitHubToken)
+	assert.Contains(t, content, "\n| deploy.sh | 3 | GitHub token | `ghp_********` |")
+	assert.Empty(t, createSecretsContent(nil))
+
+	// The scan fails if a file can't be split into lines, rather than reporting all its lines as added
+	writeTestFiles(t, targetDir, map[string]string{"deploy.sh": strings.Repeat("a", maxSecretsScanFileSize+2)})
+	_, err = scanChangedFilesForSecrets(sourceDir, targetDir)
+	assert.ErrorIs(t, err, bufio.ErrTooLong)
+}
+
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	for path, content := range files {
+		fullPath := filepath.Join(dir, path)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(fullPath), 0700))
+		assert.NoError(t, os.WriteFile(fullPath, []byte(content), 0600))
+	}
+}
@@ -0,0 +1,142 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	urgentTier                = "urgent"
+	lowPriorityTier           = "low-priority"
+	urgentTierTitle           = "### 🚨 High and Critical severity issues\n"
+	lowPriorityTierTitle      = "### Low and Medium severity issues\n"
+	lowPriorityTierSummary    = "%d Low and Medium severity issues"
+	tierResolvedComment       = "✅ Frogbot: all the %s severity issues in this pull request were fixed"
+	urgentTierSeverities      = "High and Critical"
+	lowPriorityTierSeverities = "Low and Medium"
+	minUrgentSeverityNumValue = 3 // High
+)
+
+// The issues of a single severity tier, posted in a separate comment
+type severityTier struct {
+	name       string
+	severities string
+	results    *auditResults
+}
+
+// The unknown severity is the lowest tier, so issues with an unknown severity are low priority
+func isUrgentSeverity(severity string) bool {
+	return utils.GetSeverityNumValue(severity) >= minUrgentSeverityNumValue
+}
+
+// Split the results to the urgent High and Critical issues, and the low priority Low and Medium issues. Secrets are always urgent.
+func splitResultsBySeverity(results *auditResults) (urgent, lowPriority *auditResults) {
+	urgent = &auditResults{secrets: results.secrets, onlyWithExploits: results.onlyWithExploits, cvssPolicy: results.cvssPolicy, unknownSeverityAs: results.unknownSeverityAs}
+	lowPriority = &auditResults{onlyWithExploits: results.onlyWithExploits, cvssPolicy: results.cvssPolicy, unknownSeverityAs: results.unknownSeverityAs}
+	for _, row := range results.vulnerabilitiesRows {
+		if isUrgentSeverity(row.Severity) {
+			urgent.vulnerabilitiesRows = append(urgent.vulnerabilitiesRows, row)
+		} else {
+			lowPriority.vulnerabilitiesRows = append(lowPriority.vulnerabilitiesRows, row)
+		}
+	}
+	for _, row := range results.iacRows {
+		if isUrgentSeverity(row.Severity) {
+			urgent.iacRows = append(urgent.iacRows, row)
+		} else {
+			lowPriority.iacRows = append(lowPriority.iacRows, row)
+		}
+	}
+	return
+}
+
+// commentBySeverityTiers posts the urgent issues and the low priority issues in two separate comments.
+// Since editing comments isn't supported for all the git providers, a tier is commented again only if its issues changed since its previous comment.
+// If all the issues of a tier were fixed, a comment stating it is added.
+// Returns false if no issues were found and the pull request has no tier comments, so that the clean scan comment is added instead.
+func commentBySeverityTiers(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient, results *auditResults, notes string) (bool, error) {
+	previousHashes, err := getPreviousTierMarkers(repoConfig, client)
+	if err != nil {
+		// All the tiers are commented, if the previous comments can't be read
+		log.Warn("couldn't read the pull request comments:", err.Error())
+		previousHashes = map[string]string{}
+	}
+	if results.issuesCount() == 0 && len(previousHashes) == 0 {
+		return false, nil
+	}
+	urgent, lowPriority := splitResultsBySeverity(results)
+	tiers := []severityTier{
+		{name: urgentTier, severities: urgentTierSeverities, results: urgent},
+		{name: lowPriorityTier, severities: lowPriorityTierSeverities, results: lowPriority},
+	}
+	// The notes are added to the urgent comment, unless there are no urgent issues
+	notesTier := urgentTier
+	if urgent.issuesCount() == 0 {
+		notesTier = lowPriorityTier
+	}
+	for _, tier := range tiers {
+		issuesHash, err := utils.GetIssuesHash(tier.results.vulnerabilitiesRows, tier.results.iacRows)
+		if err != nil {
+			return false, err
+		}
+		previousHash, commented := previousHashes[tier.name]
+		issuesCount := tier.results.issuesCount()
+		// Committed secrets are always commented, so that they aren't missed
+		if commented && previousHash == issuesHash && len(tier.results.secrets) == 0 {
+			log.Info(fmt.Sprintf("The %s severity issues are unchanged since the previous scan. Skipping their comment", tier.severities))
+			continue
+		}
+		if !commented && issuesCount == 0 {
+			continue
+		}
+		tierNotes := ""
+		if tier.name == notesTier {
+			tierNotes = notes
+		}
+		message := createTierMessage(&tier, repoConfig, tierNotes) + utils.GetTierMarker(tier.name, issuesHash)
+		if err = commentScanResults(repoConfig, client, issuesCount, message); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func createTierMessage(tier *severityTier, repoConfig *utils.FrogbotRepoConfig, notes string) string {
+	writer := repoConfig.OutputWriter
+	if tier.results.issuesCount() == 0 {
+		return fmt.Sprintf(tierResolvedComment, tier.severities)
+	}
+	tables := createScanResultsTables(tier.results, writer, repoConfig.GetSectionOrder())
+	message := urgentTierTitle + writer.VulnerabiltiesTitle() + tables
+	if tier.name == lowPriorityTier {
+		// The low priority issues are collapsed
+		summary := fmt.Sprintf(lowPriorityTierSummary, tier.results.issuesCount())
+		message = lowPriorityTierTitle + writer.VulnerabiltiesTitle() + writer.CollapsibleSection(summary, tables)
+	}
+	return message + createSeverityNotes(true, &repoConfig.Scan, writer) + notes
+}
+
+// Map the severity tiers to the issues hash in the newest comment of each tier
+func getPreviousTierMarkers(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient) (map[string]string, error) {
+	comments, err := client.ListPullRequestComments(context.Background(), repoConfig.RepoOwner, repoConfig.RepoName, repoConfig.PullRequestID)
+	if err != nil {
+		return nil, &VcsError{Err: err}
+	}
+	sort.Slice(comments, func(i, j int) bool {
+		return comments[i].Created.After(comments[j].Created)
+	})
+	previousHashes := make(map[string]string)
+	for _, comment := range comments {
+		if tier, issuesHash, found := utils.ParseTierMarker(comment.Content); found {
+			if _, exists := previousHashes[tier]; !exists {
+				previousHashes[tier] = issuesHash
+			}
+		}
+	}
+	return previousHashes, nil
+}
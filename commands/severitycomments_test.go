@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+var severityTiersTestResults = &auditResults{
+	vulnerabilitiesRows: []formats.VulnerabilityOrViolationRow{
+		{Severity: "Critical", IssueId: "XRAY-1", ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "1.2.5"},
+		{Severity: "Low", IssueId: "XRAY-2", ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "4.17.20"},
+	},
+	iacRows:    []utils.IacRow{{Severity: "Medium", File: "k8s/service.yaml", Finding: "Service exposes a NodePort", RuleId: "k8s-node-port"}},
+	cvssPolicy: utils.CvssPolicy{MinCvss: 7},
+}
+
+func TestSplitResultsBySeverity(t *testing.T) {
+	urgent, lowPriority := splitResultsBySeverity(severityTiersTestResults)
+	assert.Equal(t, severityTiersTestResults.vulnerabilitiesRows[:1], urgent.vulnerabilitiesRows)
+	assert.Empty(t, urgent.iacRows)
+	assert.Equal(t, severityTiersTestResults.vulnerabilitiesRows[1:], lowPriority.vulnerabilitiesRows)
+	assert.Equal(t, severityTiersTestResults.iacRows, lowPriority.iacRows)
+	// The tiers keep the CVSS policy of the results
+	assert.Equal(t, severityTiersTestResults.cvssPolicy, urgent.cvssPolicy)
+	assert.Equal(t, severityTiersTestResults.cvssPolicy, lowPriority.cvssPolicy)
+}
+
+func TestCommentBySeverityTiers(t *testing.T) {
+	repoConfig := &utils.FrogbotRepoConfig{
+		OutputWriter: &utils.StandardOutput{},
+		Params:       utils.Params{Git: utils.Git{RepoOwner: "jfrog", RepoName: "frogbot", PullRequestID: 1}},
+	}
+	var comments []vcsclient.CommentInfo
+	runScan := func(results *auditResults) (commented bool, newComments []string) {
+		client := mockVcsClient(t)
+		client.EXPECT().ListPullRequestComments(context.Background(), "jfrog", "frogbot", 1).Return(comments, nil)
+		client.EXPECT().AddPullRequestComment(context.Background(), "jfrog", "frogbot", gomock.Any(), 1).DoAndReturn(func(_ context.Context, _, _, content string, _ int) error {
+			newComments = append(newComments, content)
+			comments = append(comments, vcsclient.CommentInfo{Content: content, Created: time.Unix(int64(len(comments)), 0)})
+			return nil
+		}).AnyTimes()
+		commented, err := commentBySeverityTiers(repoConfig, client, results, "\n\nnotes")
+		assert.NoError(t, err)
+		return
+	}
+
+	// No tier comments are added to a clean pull request, so that the clean scan comment is added instead
+	commented, newComments := runScan(&auditResults{})
+	assert.False(t, commented)
+	assert.Empty(t, newComments)
+
+	// The urgent and the low priority issues are added in two comments
+	commented, newComments = runScan(severityTiersTestResults)
+	assert.True(t, commented)
+	if assert.Len(t, newComments, 2) {
+		assert.True(t, strings.HasPrefix(newComments[0], urgentTierTitle))
+		assert.Contains(t, newComments[0], "minimist")
+		assert.NotContains(t, newComments[0], "lodash")
+		assert.Contains(t, newComments[0], "\n\nnotes")
+		assert.True(t, strings.HasPrefix(newComments[1], lowPriorityTierTitle))
+		assert.Contains(t, newComments[1], "<summary>2 Low and Medium severity issues</summary>")
+		assert.Contains(t, newComments[1], "lodash")
+		assert.NotContains(t, newComments[1], "\n\nnotes")
+	}
+
+	// Unchanged tiers aren't commented again
+	_, newComments = runScan(severityTiersTestResults)
+	assert.Empty(t, newComments)
+
+	// Once the urgent issues are fixed, a single comment states it, and the low priority tier is unchanged
+	lowPriorityResults := &auditResults{vulnerabilitiesRows: severityTiersTestResults.vulnerabilitiesRows[1:], iacRows: severityTiersTestResults.iacRows}
+	_, newComments = runScan(lowPriorityResults)
+	if assert.Len(t, newComments, 1) {
+		assert.True(t, strings.HasPrefix(newComments[0], "✅ Frogbot: all the High and Critical severity issues in this pull request were fixed"))
+	}
+	_, newComments = runScan(lowPriorityResults)
+	assert.Empty(t, newComments)
+
+	// Once all the issues are fixed, the low priority tier is resolved too
+	commented, newComments = runScan(&auditResults{})
+	assert.True(t, commented)
+	if assert.Len(t, newComments, 1) {
+		assert.True(t, strings.HasPrefix(newComments[0], "✅ Frogbot: all the Low and Medium severity issues in this pull request were fixed"))
+	}
+}
+
+func TestTierMarkerIsFrogbotComment(t *testing.T) {
+	comment := "✅ Frogbot: all the issues were fixed" + utils.GetTierMarker(urgentTier, "0123")
+	assert.True(t, (&utils.StandardOutput{}).IsFrogbotResultComment(comment))
+	tier, issuesHash, found := utils.ParseTierMarker(comment)
+	assert.True(t, found)
+	assert.Equal(t, urgentTier, tier)
+	assert.Equal(t, "0123", issuesHash)
+}
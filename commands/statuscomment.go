@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	statusCommentPassed = "✅ Frogbot scan passed"
+	statusCommentFailed = "❌ Frogbot scan failed"
+)
+
+// commentStatus adds the single line status comment to the pull request, instead of the issues tables.
+// The full results are written to the log, which the status comment links to.
+func commentStatus(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient, results *auditResults, notes string) (err error) {
+	log.Info("The scan results:\n" + createCommentMessage(repoConfig, results, notes))
+	message := createStatusComment(results, getCiRunUrl()) + createFailureCommentNote(repoConfig, results)
+	if repoConfig.CommentOnlyOnChange {
+		var changed bool
+		if message, changed, err = addContentMarker(repoConfig, client, results, message); err != nil || !changed {
+			return err
+		}
+	}
+	return commentScanResults(repoConfig, client, results.issuesCount(), message)
+}
+
+// Create a single line with the scan result and the number of issues of each severity, such as
+// "❌ Frogbot scan failed: 3 issues (1 Critical, 2 High) · [Details](https://github.com/jfrog/frogbot/actions/runs/1234)".
+// If the URL of the CI run is unknown, the link is omitted.
+func createStatusComment(results *auditResults, detailsUrl string) string {
+	status := statusCommentPassed
+	if results.failingIssuesFound {
+		status = statusCommentFailed
+	}
+	summary := results.severitySummary()
+	var counts []string
+	if summary.Total > 0 {
+		counts = append(counts, fmt.Sprintf("%s (%s)", pluralize(summary.Total, "issue"), formatSeverityCounts(summary)))
+	}
+	if len(results.secrets) > 0 {
+		counts = append(counts, pluralize(len(results.secrets), "secret"))
+	}
+	if len(counts) == 0 {
+		counts = append(counts, "no issues")
+	}
+	message := status + ": " + strings.Join(counts, ", ")
+	if detailsUrl != "" {
+		message += fmt.Sprintf(" · [Details](%s)", detailsUrl)
+	}
+	return message
+}
+
+// Return the non-zero counts of the severities, from the highest severity to the lowest, such as "1 Critical, 2 High"
+func formatSeverityCounts(summary severityBreakdown) string {
+	var counts []string
+	for _, severityCount := range []struct {
+		severity string
+		count    int
+	}{{"Critical", summary.Critical}, {"High", summary.High}, {"Medium", summary.Medium}, {"Low", summary.Low}, {"Unknown", summary.Unknown}} {
+		if severityCount.count > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", severityCount.count, severityCount.severity))
+		}
+	}
+	return strings.Join(counts, ", ")
+}
+
+func pluralize(count int, noun string) string {
+	if count == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
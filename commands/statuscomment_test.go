@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateStatusComment(t *testing.T) {
+	results := &auditResults{
+		failingIssuesFound: true,
+		vulnerabilitiesRows: []formats.VulnerabilityOrViolationRow{
+			{Severity: "High", IssueId: "XRAY-1"}, {Severity: "Critical", IssueId: "XRAY-2"}, {Severity: "High", IssueId: "XRAY-3"},
+		},
+		secrets: []secretRow{{file: "config.yml", line: 3}},
+	}
+	assert.Equal(t, "❌ Frogbot scan failed: 3 issues (1 Critical, 2 High), 1 secret · [Details](https://github.com/jfrog/frogbot/actions/runs/1234)",
+		createStatusComment(results, "https://github.com/jfrog/frogbot/actions/runs/1234"))
+
+	// Issues which don't fail the scan, according to the severity policy
+	results = &auditResults{iacRows: []utils.IacRow{{Severity: "Low"}}}
+	assert.Equal(t, "✅ Frogbot scan passed: 1 issue (1 Low)", createStatusComment(results, ""))
+
+	assert.Equal(t, "✅ Frogbot scan passed: no issues", createStatusComment(&auditResults{}, ""))
+}
+
+func TestCommentStatus(t *testing.T) {
+	for _, env := range []string{"GITHUB_RUN_ID", "BUILD_BUILDID", "BUILD_URL"} {
+		t.Setenv(env, "")
+	}
+	t.Setenv("CI_JOB_URL", "https://gitlab.com/jfrog/frogbot/-/jobs/42")
+	repoConfig := &utils.FrogbotRepoConfig{
+		OutputWriter: &utils.StandardOutput{},
+		Params:       utils.Params{Git: utils.Git{RepoOwner: "jfrog", RepoName: "frogbot", PullRequestID: 1}, CommentStyle: utils.StatusCommentStyle},
+	}
+	results := &auditResults{failingIssuesFound: true, vulnerabilitiesRows: []formats.VulnerabilityOrViolationRow{{Severity: "Medium", IssueId: "XRAY-1"}}}
+	client := mockVcsClient(t)
+	client.EXPECT().AddPullRequestComment(context.Background(), "jfrog", "frogbot", "❌ Frogbot scan failed: 1 issue (1 Medium) · [Details](https://gitlab.com/jfrog/frogbot/-/jobs/42)", 1).Return(nil)
+	assert.NoError(t, commentStatus(repoConfig, client, results, ""))
+
+	// The clean status comment is suppressed, like the full clean scan comment
+	repoConfig.SuppressCleanComment = true
+	assert.NoError(t, commentStatus(repoConfig, mockVcsClient(t), &auditResults{}, ""))
+}
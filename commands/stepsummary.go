@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"errors"
+	"os"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// writeStepSummary appends the scan results, as rendered for the pull request comment, to the GitHub Actions step summary.
+// It returns true if the pull request comment should be skipped, which happens only if the step summary was written instead of it.
+func writeStepSummary(repoConfig *utils.FrogbotRepoConfig, results *auditResults, notes string) bool {
+	if repoConfig.WriteStepSummary == "" {
+		return false
+	}
+	if err := appendStepSummary(createCommentMessage(repoConfig, results, notes)); err != nil {
+		// The results are still reported in the pull request comment, so that they aren't lost
+		log.Warn("couldn't write the GitHub Actions step summary. Adding the pull request comment instead:", err.Error())
+		return false
+	}
+	return repoConfig.WriteStepSummary == utils.InsteadOfCommentStepSummary
+}
+
+func appendStepSummary(markdown string) (err error) {
+	if _, exist := os.LookupEnv(utils.GitHubActionsEnv); !exist {
+		return errors.New("the step summary is supported only on GitHub Actions")
+	}
+	summaryPath := os.Getenv(utils.GitHubStepSummaryEnv)
+	if summaryPath == "" {
+		return errors.New("the " + utils.GitHubStepSummaryEnv + " environment variable is empty")
+	}
+	summaryFile, err := os.OpenFile(summaryPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if e := summaryFile.Close(); err == nil {
+			err = e
+		}
+	}()
+	_, err = summaryFile.WriteString(markdown + "\n")
+	return err
+}
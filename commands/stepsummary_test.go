@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteStepSummary(t *testing.T) {
+	summaryPath := filepath.Join(t.TempDir(), "step_summary.md")
+	t.Setenv(utils.GitHubActionsEnv, "true")
+	t.Setenv(utils.GitHubStepSummaryEnv, summaryPath)
+	repoConfig := createCommentTemplateTestConfig("")
+	message := createCommentMessage(repoConfig, commentTemplateTestResults, "\n\nnotes")
+
+	// No step summary is written by default
+	assert.False(t, writeStepSummary(repoConfig, commentTemplateTestResults, "\n\nnotes"))
+	assert.NoFileExists(t, summaryPath)
+
+	// The summary is appended to the step summary of the previous steps
+	repoConfig.WriteStepSummary = utils.WithCommentStepSummary
+	assert.NoError(t, os.WriteFile(summaryPath, []byte("previous step\n"), 0644))
+	assert.False(t, writeStepSummary(repoConfig, commentTemplateTestResults, "\n\nnotes"))
+	content, err := os.ReadFile(summaryPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "previous step\n"+message+"\n", string(content))
+
+	// The pull request comment is skipped
+	repoConfig.WriteStepSummary = utils.InsteadOfCommentStepSummary
+	assert.True(t, writeStepSummary(repoConfig, commentTemplateTestResults, "\n\nnotes"))
+}
+
+func TestWriteStepSummaryOutsideGitHubActions(t *testing.T) {
+	summaryPath := filepath.Join(t.TempDir(), "step_summary.md")
+	t.Setenv(utils.GitHubStepSummaryEnv, summaryPath)
+	// Setenv restores the variable at the end of the test
+	t.Setenv(utils.GitHubActionsEnv, "")
+	assert.NoError(t, os.Unsetenv(utils.GitHubActionsEnv))
+	repoConfig := createCommentTemplateTestConfig("")
+	repoConfig.WriteStepSummary = utils.InsteadOfCommentStepSummary
+
+	// The pull request comment is added, so that the results aren't lost
+	assert.False(t, writeStepSummary(repoConfig, commentTemplateTestResults, ""))
+	assert.NoFileExists(t, summaryPath)
+
+	t.Setenv(utils.GitHubActionsEnv, "true")
+	t.Setenv(utils.GitHubStepSummaryEnv, "")
+	assert.False(t, writeStepSummary(repoConfig, commentTemplateTestResults, ""))
+}
@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const submodulesNoteTitle = "#### 📦 Issues in Git Submodules"
+
+// getScannedProjects returns the projects of the repository, and if scanSubmodules is set, a project for each git submodule of the source branch.
+// A submodule is scanned with the settings of the first project, unless one of the projects already scans its path.
+// The submodules which can't be cloned are skipped with a warning, since they may be private or unreachable from the CI.
+func getScannedProjects(repoConfig *utils.FrogbotRepoConfig) ([]utils.Project, error) {
+	if !repoConfig.ScanSubmodules || len(repoConfig.Projects) == 0 {
+		return repoConfig.Projects, nil
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	submodules, err := utils.GetSubmodules(wd)
+	if err != nil {
+		return nil, err
+	}
+	projects := append([]utils.Project{}, repoConfig.Projects...)
+	for index := range submodules {
+		submodule := submodules[index]
+		if isScannedWorkingDir(repoConfig.Projects, submodule.Path) {
+			continue
+		}
+		pathIgnored := repoConfig.IsPathIgnored(submodule.Path)
+		if pathIgnored && repoConfig.HidePathIgnoredIssues {
+			continue
+		}
+		if err = submodule.CloneIfMissing(wd, &repoConfig.Git); err != nil {
+			log.Warn(fmt.Sprintf("the '%s' submodule isn't scanned: %s", submodule.Path, err.Error()))
+			continue
+		}
+		project := repoConfig.Projects[0]
+		project.WorkingDirs = []string{submodule.Path}
+		project.PathIgnored = pathIgnored
+		project.Submodule = &submodule
+		projects = append(projects, project)
+	}
+	return projects, nil
+}
+
+func isScannedWorkingDir(projects []utils.Project, workingDir string) bool {
+	for _, project := range projects {
+		for _, projectWorkingDir := range project.WorkingDirs {
+			if path.Clean(filepath.ToSlash(projectWorkingDir)) == workingDir {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Clone the submodule of the project into the downloaded target branch, which doesn't include the submodules contents.
+// The commit of the submodule recorded in the target branch is checked out, if the branch can be found in the local repository.
+func cloneTargetSubmodule(project *utils.Project, targetWd, branch string, git *utils.Git) error {
+	if project.Submodule == nil {
+		return nil
+	}
+	submodule := *project.Submodule
+	submodule.Commit = utils.ResolveSubmoduleCommit(".", branch, submodule.Path)
+	if submodule.Commit == "" {
+		log.Debug(fmt.Sprintf("The commit of the '%s' submodule in the %s branch wasn't found. Cloning the head of the submodule", submodule.Path, branch))
+	}
+	return submodule.CloneIfMissing(targetWd, git)
+}
+
+// Add the new issues of a submodule project, to attribute them to the submodule path in the comment
+func (results *auditResults) addSubmoduleIssues(project *utils.Project, vulnerabilitiesRows []formats.VulnerabilityOrViolationRow) {
+	if project.Submodule == nil {
+		return
+	}
+	if results.submoduleIssues == nil {
+		results.submoduleIssues = make(map[string]string)
+	}
+	for _, row := range vulnerabilitiesRows {
+		results.submoduleIssues[getUniqueID(row)] = project.Submodule.Path
+	}
+}
+
+// Create a note with the submodules in which the issues were found
+func createSubmodulesNote(vulnerabilitiesRows []formats.VulnerabilityOrViolationRow, submoduleIssues map[string]string) string {
+	var notes strings.Builder
+	for _, row := range vulnerabilitiesRows {
+		submodulePath, exists := submoduleIssues[getUniqueID(row)]
+		if !exists {
+			continue
+		}
+		notes.WriteString(fmt.Sprintf("- **%s %s** (%s) is found in the `%s` submodule\n", row.ImpactedDependencyName, row.ImpactedDependencyVersion, getIssueDisplayId(row), submodulePath))
+	}
+	if notes.Len() == 0 {
+		return ""
+	}
+	return "\n\n" + submodulesNoteTitle + "\n\n" + notes.String()
+}
@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetScannedProjects(t *testing.T) {
+	dir := t.TempDir()
+	gitmodules := `[submodule "lib"]
+	path = vendor/lib
+	url = https://github.com/jfrog/lib.git
+[submodule "tools"]
+	path = tools
+	url = https://github.com/jfrog/tools.git
+[submodule "examples"]
+	path = examples/demo
+	url = https://github.com/jfrog/demo.git
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, ".gitmodules"), []byte(gitmodules), 0644))
+	// The submodule is already checked out, so it isn't cloned
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "vendor", "lib"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "vendor", "lib", "go.mod"), []byte("module lib"), 0644))
+	restoreDir, err := utils.Chdir(dir)
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, restoreDir())
+	}()
+
+	rootProject := utils.Project{WorkingDirs: []string{utils.RootDir}, Watches: []string{"watch-1"}}
+	toolsProject := utils.Project{WorkingDirs: []string{"tools/"}}
+	repoConfig := &utils.FrogbotRepoConfig{Params: utils.Params{
+		Scan:                  utils.Scan{Projects: []utils.Project{rootProject, toolsProject}},
+		ScanSubmodules:        true,
+		PathIgnores:           []string{"examples/"},
+		HidePathIgnoredIssues: true,
+	}}
+	projects, err := getScannedProjects(repoConfig)
+	assert.NoError(t, err)
+	// The tools submodule is already scanned by a project, and the examples submodule is path ignored
+	if assert.Len(t, projects, 3) {
+		assert.Equal(t, []string{"vendor/lib"}, projects[2].WorkingDirs)
+		assert.Equal(t, []string{"watch-1"}, projects[2].Watches)
+		assert.Equal(t, "lib", projects[2].Submodule.Name)
+	}
+	assert.Len(t, repoConfig.Projects, 2)
+
+	repoConfig.ScanSubmodules = false
+	projects, err = getScannedProjects(repoConfig)
+	assert.NoError(t, err)
+	assert.Len(t, projects, 2)
+}
+
+func TestCreateSubmodulesNote(t *testing.T) {
+	rootRow := formats.VulnerabilityOrViolationRow{ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "1.2.5", IssueId: "XRAY-1"}
+	submoduleRow := formats.VulnerabilityOrViolationRow{ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "4.17.15", IssueId: "XRAY-2", Cves: []formats.CveRow{{Id: "CVE-2020-8203"}}}
+	results := &auditResults{}
+	results.addProjectIssues(&utils.Project{}, []formats.VulnerabilityOrViolationRow{rootRow})
+	results.addProjectIssues(&utils.Project{Submodule: &utils.Submodule{Name: "lib", Path: "vendor/lib"}}, []formats.VulnerabilityOrViolationRow{submoduleRow})
+
+	assert.Equal(t, "\n\n"+submodulesNoteTitle+"\n\n- **lodash 4.17.15** (CVE-2020-8203) is found in the `vendor/lib` submodule\n",
+		createSubmodulesNote(results.vulnerabilitiesRows, results.submoduleIssues))
+	assert.Empty(t, createSubmodulesNote([]formats.VulnerabilityOrViolationRow{rootRow}, results.submoduleIssues))
+}
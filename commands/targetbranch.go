@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsclient"
+)
+
+// targetBranchCheckout is the code of the target branch of a pull request, which is downloaded on its first use,
+// and is shared by the target branch audit, the changed manifests, the secrets scan and the diff annotations of the pull request scan.
+type targetBranchCheckout struct {
+	client  vcsclient.VcsClient
+	branch  string
+	git     *utils.Git
+	dir     string
+	cleanup func() error
+}
+
+func newTargetBranchCheckout(repoConfig *utils.FrogbotRepoConfig, client vcsclient.VcsClient) *targetBranchCheckout {
+	return &targetBranchCheckout{client: client, branch: repoConfig.Branches[0], git: &repoConfig.Git}
+}
+
+// getDir returns the dir of the target branch, and downloads it if it wasn't downloaded yet
+func (tbc *targetBranchCheckout) getDir() (string, error) {
+	if tbc.dir != "" {
+		return tbc.dir, nil
+	}
+	dir, cleanup, err := utils.DownloadRepoToTempDir(tbc.client, tbc.branch, tbc.git)
+	if err != nil {
+		return "", err
+	}
+	tbc.dir, tbc.cleanup = dir, cleanup
+	return dir, nil
+}
+
+// remove removes the downloaded target branch, if it was downloaded
+func (tbc *targetBranchCheckout) remove() error {
+	if tbc.cleanup == nil {
+		return nil
+	}
+	err := tbc.cleanup()
+	tbc.dir, tbc.cleanup = "", nil
+	return err
+}
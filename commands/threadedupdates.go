@@ -0,0 +1,175 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/xanzy/go-gitlab"
+)
+
+const (
+	threadedUpdatesPerPage = 100
+	// The length of the hex prefix of the hash, which identifies an issue in the thread marker
+	threadIssueKeyLength = 8
+	// The maximum number of new issues listed in a reply
+	maxThreadReplyIssues = 10
+)
+
+// The newest comment of the results thread
+type resultsThread struct {
+	discussionId string
+	issueKeys    []string
+}
+
+// commentThreadedUpdate replies to the results thread with the issues resolved and found since the previous comment of the thread, when threadedUpdates is set.
+// It returns true if the reply was handled, whether it was added or skipped since the issues are unchanged. Otherwise, it returns the message with the thread marker,
+// to be added as the root comment of a new thread. The threads are supported on GitLab only, since the pull request comments of the other git providers can't be replied to.
+func commentThreadedUpdate(repoConfig *utils.FrogbotRepoConfig, results *auditResults, message string) (string, bool, error) {
+	issueDescriptions, err := getThreadIssues(results)
+	if err != nil {
+		return "", false, err
+	}
+	issueKeys := make([]string, 0, len(issueDescriptions))
+	for key := range issueDescriptions {
+		issueKeys = append(issueKeys, key)
+	}
+	sort.Strings(issueKeys)
+	marker := utils.GetThreadMarker(issueKeys)
+	if repoConfig.GitProvider != vcsutils.GitLab {
+		log.Debug("Threaded updates are supported on GitLab only. Adding the results comment")
+		return message + marker, false, nil
+	}
+	client, err := newGitLabClient(&repoConfig.Git)
+	if err != nil {
+		return "", false, err
+	}
+	projectId := fmt.Sprintf("%s/%s", repoConfig.RepoOwner, repoConfig.RepoName)
+	thread, err := getResultsThread(client, projectId, repoConfig.PullRequestID)
+	if err != nil {
+		// The results comment is added, if the thread can't be read
+		log.Warn("couldn't read the merge request discussions:", err.Error())
+		return message + marker, false, nil
+	}
+	if thread == nil {
+		return message + marker, false, nil
+	}
+	reply, changed := createThreadReply(thread.issueKeys, issueKeys, issueDescriptions)
+	if !changed {
+		log.Info("The issues are unchanged since the previous comment of the results thread. Skipping the reply, since threadedUpdates is set")
+		return "", true, nil
+	}
+	maxLength := utils.GetMaxCommentLength(repoConfig.GitProvider, repoConfig.MaxCommentLength)
+	body := utils.TruncateComment(utils.GetBotHeader(repoConfig.BotName)+reply+marker, maxLength)
+	log.Info("Replying to the results thread of merge request", repoConfig.PullRequestID)
+	if _, _, err = client.Discussions.AddMergeRequestDiscussionNote(projectId, repoConfig.PullRequestID, thread.discussionId, &gitlab.AddMergeRequestDiscussionNoteOptions{Body: &body}); err != nil {
+		return "", true, &VcsError{Err: fmt.Errorf("couldn't reply to the results thread: %w", err)}
+	}
+	return "", true, nil
+}
+
+// Return the discussion with the newest thread marker, or nil if the results thread wasn't started yet
+func getResultsThread(client *gitlab.Client, projectId string, mergeRequestId int) (*resultsThread, error) {
+	var thread *resultsThread
+	var newestNote *gitlab.Note
+	options := &gitlab.ListMergeRequestDiscussionsOptions{PerPage: threadedUpdatesPerPage}
+	for {
+		discussions, response, err := client.Discussions.ListMergeRequestDiscussions(projectId, mergeRequestId, options)
+		if err != nil {
+			return nil, err
+		}
+		for _, discussion := range discussions {
+			for _, note := range discussion.Notes {
+				issueKeys, found := utils.ParseThreadMarker(note.Body)
+				if !found {
+					continue
+				}
+				// The discussions and their notes are listed from the oldest to the newest, but a reply in an older discussion may be newer than a later discussion
+				if newestNote == nil || note.CreatedAt == nil || newestNote.CreatedAt == nil || !note.CreatedAt.Before(*newestNote.CreatedAt) {
+					newestNote = note
+					thread = &resultsThread{discussionId: discussion.ID, issueKeys: issueKeys}
+				}
+			}
+		}
+		if response == nil || response.NextPage == 0 {
+			return thread, nil
+		}
+		options.Page = response.NextPage
+	}
+}
+
+// Create the reply with the numbers of the resolved and the new issues, and the list of the new issues. Returns false if the issues are unchanged.
+func createThreadReply(previousKeys, currentKeys []string, issueDescriptions map[string]string) (string, bool) {
+	previous := make(map[string]bool, len(previousKeys))
+	for _, key := range previousKeys {
+		previous[key] = true
+	}
+	var newIssues []string
+	for _, key := range currentKeys {
+		if !previous[key] {
+			newIssues = append(newIssues, issueDescriptions[key])
+		} else {
+			delete(previous, key)
+		}
+	}
+	resolvedCount := len(previous)
+	if resolvedCount == 0 && len(newIssues) == 0 {
+		return "", false
+	}
+	var reply strings.Builder
+	if resolvedCount > 0 {
+		reply.WriteString(fmt.Sprintf("✅ %s resolved since the last push\n", pluralize(resolvedCount, "issue")))
+	}
+	if len(newIssues) > 0 {
+		reply.WriteString(fmt.Sprintf("⚠️ %s found since the last push:\n", pluralize(len(newIssues), "issue")))
+		sort.Strings(newIssues)
+		for index, description := range newIssues {
+			if index == maxThreadReplyIssues {
+				reply.WriteString(fmt.Sprintf("- and %d more\n", len(newIssues)-maxThreadReplyIssues))
+				break
+			}
+			reply.WriteString("- " + description + "\n")
+		}
+	}
+	if len(currentKeys) == 0 {
+		reply.WriteString("\nNo issues remain.")
+	} else {
+		reply.WriteString(fmt.Sprintf("\n%s remaining.", pluralize(len(currentKeys), "issue")))
+	}
+	return reply.String(), true
+}
+
+// Return the descriptions of the issues, by their keys in the thread marker
+func getThreadIssues(results *auditResults) (map[string]string, error) {
+	issues := make(map[string]string)
+	addIssue := func(description string, keyValues ...string) error {
+		hash, err := utils.Md5Hash(keyValues...)
+		if err != nil {
+			return err
+		}
+		issues[hash[:threadIssueKeyLength]] = description
+		return nil
+	}
+	for _, row := range results.vulnerabilitiesRows {
+		description := fmt.Sprintf("%s: %s %s (%s)", row.Severity, row.ImpactedDependencyName, row.ImpactedDependencyVersion, strings.Join(getRowIssueIds(row), ", "))
+		if err := addIssue(description, vulnerabilityFindingType, getUniqueID(row)); err != nil {
+			return nil, err
+		}
+	}
+	for _, row := range results.iacRows {
+		description := fmt.Sprintf("%s: %s in %s:%d", row.Severity, row.RuleId, row.File, row.Line)
+		if err := addIssue(description, misconfigurationFindingType, row.File, fmt.Sprint(row.Line), row.RuleId); err != nil {
+			return nil, err
+		}
+	}
+	for _, secret := range results.secrets {
+		description := fmt.Sprintf("Secret: %s in %s:%d", secret.secretType, secret.file, secret.line)
+		if err := addIssue(description, secretFindingType, secret.file, fmt.Sprint(secret.line), secret.secretType); err != nil {
+			return nil, err
+		}
+	}
+	return issues, nil
+}
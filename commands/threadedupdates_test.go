@@ -0,0 +1,147 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+func createThreadedUpdatesTestResults() *auditResults {
+	return &auditResults{
+		vulnerabilitiesRows: []formats.VulnerabilityOrViolationRow{{
+			Severity: "High", ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "4.17.20", IssueId: "XRAY-1", Cves: []formats.CveRow{{Id: "CVE-2021-23337"}},
+		}},
+		iacRows: []utils.IacRow{{Severity: "Medium", File: "main.tf", Line: 3, RuleId: "aws_s3_bucket_public"}},
+	}
+}
+
+func getThreadedUpdatesTestKeys(t *testing.T, results *auditResults) map[string]string {
+	issues, err := getThreadIssues(results)
+	assert.NoError(t, err)
+	keys := make(map[string]string)
+	for key, description := range issues {
+		keys[description] = key
+	}
+	return keys
+}
+
+func TestCreateThreadReply(t *testing.T) {
+	descriptions := map[string]string{"0000000d": "High: lodash 4.17.20 (CVE-2021-23337)"}
+	reply, changed := createThreadReply([]string{"0000000a", "0000000b", "0000000c"}, []string{"0000000b", "0000000d"}, descriptions)
+	assert.True(t, changed)
+	assert.Equal(t, "✅ 2 issues resolved since the last push\n⚠️ 1 issue found since the last push:\n- High: lodash 4.17.20 (CVE-2021-23337)\n\n2 issues remaining.", reply)
+
+	reply, changed = createThreadReply([]string{"0000000a"}, []string{}, descriptions)
+	assert.True(t, changed)
+	assert.Equal(t, "✅ 1 issue resolved since the last push\n\nNo issues remain.", reply)
+
+	_, changed = createThreadReply([]string{"0000000a", "0000000b"}, []string{"0000000a", "0000000b"}, descriptions)
+	assert.False(t, changed)
+}
+
+func TestCommentThreadedUpdateGitLab(t *testing.T) {
+	results := createThreadedUpdatesTestResults()
+	keys := getThreadedUpdatesTestKeys(t, results)
+	lodashKey, iacKey := keys["High: lodash 4.17.20 (CVE-2021-23337)"], keys["Medium: aws_s3_bucket_public in main.tf:3"]
+	previousKeys := []string{lodashKey, "0000000a"}
+
+	var replyDiscussion, replyBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		switch r.URL.EscapedPath() {
+		// The GitLab client sends a request to configure its rate limiter
+		case "/api/v4/":
+		case "/api/v4/projects/jfrog%2Ffrogbot/merge_requests/7/discussions":
+			// The root comment of the thread, and its newer reply, followed by an unrelated discussion
+			_, err = fmt.Fprintf(w, `[
+				{"id": "root", "notes": [
+					{"body": %q, "created_at": "2023-02-01T00:00:00Z"},
+					{"body": %q, "created_at": "2023-02-03T00:00:00Z"}
+				]},
+				{"id": "other", "notes": [{"body": "LGTM", "created_at": "2023-02-02T00:00:00Z"}]}
+			]`, "results"+utils.GetThreadMarker([]string{lodashKey}), "update"+utils.GetThreadMarker(previousKeys))
+		case "/api/v4/projects/jfrog%2Ffrogbot/merge_requests/7/discussions/root/notes":
+			assert.Equal(t, http.MethodPost, r.Method)
+			var note struct {
+				Body string `json:"body"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&note))
+			replyDiscussion, replyBody = "root", note.Body
+			_, err = fmt.Fprint(w, `{"id": 1}`)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.EscapedPath())
+		}
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	repoConfig := &utils.FrogbotRepoConfig{Params: utils.Params{
+		Git:             utils.Git{GitProvider: vcsutils.GitLab, RepoOwner: "jfrog", RepoName: "frogbot", Token: "123456", ApiEndpoint: server.URL, PullRequestID: 7},
+		ThreadedUpdates: true,
+	}}
+
+	// The reply compares the issues with the newest comment of the thread
+	message, threaded, err := commentThreadedUpdate(repoConfig, results, "results")
+	assert.NoError(t, err)
+	assert.True(t, threaded)
+	assert.Empty(t, message)
+	assert.Equal(t, "root", replyDiscussion)
+	assert.Contains(t, replyBody, "✅ 1 issue resolved since the last push\n⚠️ 1 issue found since the last push:\n- Medium: aws_s3_bucket_public in main.tf:3\n\n2 issues remaining.")
+	issueKeys, found := utils.ParseThreadMarker(replyBody)
+	assert.True(t, found)
+	assert.ElementsMatch(t, []string{lodashKey, iacKey}, issueKeys)
+
+	// No reply is added if the issues are unchanged
+	replyBody = ""
+	previousKeys = []string{lodashKey, iacKey}
+	_, threaded, err = commentThreadedUpdate(repoConfig, results, "results")
+	assert.NoError(t, err)
+	assert.True(t, threaded)
+	assert.Empty(t, replyBody)
+}
+
+func TestCommentThreadedUpdateNoThread(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() == "/api/v4/projects/jfrog%2Ffrogbot/merge_requests/7/discussions" {
+			_, err := fmt.Fprint(w, `[{"id": "other", "notes": [{"body": "LGTM"}]}]`)
+			assert.NoError(t, err)
+		}
+	}))
+	defer server.Close()
+	results := createThreadedUpdatesTestResults()
+	for _, git := range []utils.Git{
+		// The results comment starts the thread
+		{GitProvider: vcsutils.GitLab, RepoOwner: "jfrog", RepoName: "frogbot", Token: "123456", ApiEndpoint: server.URL, PullRequestID: 7},
+		// The pull request comments of the other git providers can't be replied to
+		{GitProvider: vcsutils.GitHub, RepoOwner: "jfrog", RepoName: "frogbot", PullRequestID: 7},
+	} {
+		repoConfig := &utils.FrogbotRepoConfig{Params: utils.Params{Git: git, ThreadedUpdates: true}}
+		message, threaded, err := commentThreadedUpdate(repoConfig, results, "results")
+		assert.NoError(t, err)
+		assert.False(t, threaded)
+		issueKeys, found := utils.ParseThreadMarker(message)
+		assert.True(t, found)
+		assert.Len(t, issueKeys, 2)
+		assert.Contains(t, message, "results")
+	}
+}
+
+func TestCommentThreadedUpdateSuppressCleanComment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to %s", r.URL.EscapedPath())
+	}))
+	defer server.Close()
+	repoConfig := &utils.FrogbotRepoConfig{Params: utils.Params{
+		Git:             utils.Git{GitProvider: vcsutils.GitLab, RepoOwner: "jfrog", RepoName: "frogbot", Token: "123456", ApiEndpoint: server.URL, PullRequestID: 7},
+		ThreadedUpdates: true,
+	}, OutputWriter: &utils.StandardOutput{}}
+	repoConfig.SuppressCleanComment = true
+	// Neither the results comment nor a reply to its thread is added to a clean merge request. The mock client fails the test on any call.
+	assert.NoError(t, commentAllResults(repoConfig, mockVcsClient(t), &auditResults{}, ""))
+}
@@ -0,0 +1,203 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	unpinnedDependenciesTitle = "#### 📌 Unpinned dependencies"
+	unpinnedDependenciesNote  = "The following direct dependencies are specified with version ranges, so the versions installed may change between builds. Pinning them to exact versions makes the builds reproducible. This is an advisory only, and doesn't fail the scan."
+)
+
+// The operators of a pip requirement, other than the exact pinning operators == and ===
+var pipRangeOperatorRegex = regexp.MustCompile(`(~=|!=|<=|>=|<|>)`)
+
+// A direct dependency, which is specified with a version range rather than an exact version
+type unpinnedDependency struct {
+	// The path of the manifest, relative to the root of the repository
+	manifest string
+	name     string
+	version  string
+}
+
+// The parsers of the manifests, by file name, which return the unpinned direct dependencies of the manifest
+var unpinnedDependenciesParsers = map[string]func(content []byte) ([]unpinnedDependency, error){
+	"package.json": getNpmUnpinnedDependencies,
+	"pom.xml":      getMavenUnpinnedDependencies,
+}
+
+func getUnpinnedDependenciesParser(fileName string) func(content []byte) ([]unpinnedDependency, error) {
+	if parser, exists := unpinnedDependenciesParsers[fileName]; exists {
+		return parser
+	}
+	if strings.HasPrefix(fileName, "requirements") && strings.HasSuffix(fileName, ".txt") {
+		return getPipUnpinnedDependencies
+	}
+	return nil
+}
+
+// Find the unpinned dependencies in the manifests of the source branch, which is the current working dir
+func auditPullRequestUnpinnedDependencies() ([]unpinnedDependency, error) {
+	sourceDir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	return findUnpinnedDependencies(sourceDir)
+}
+
+// findUnpinnedDependencies walks the manifests under the root dir, and returns the direct dependencies specified with loose version ranges.
+// The manifests of npm, pip and Maven are supported. Go modules are always pinned to exact versions, and other manifests are skipped, as are the manifests which can't be parsed.
+func findUnpinnedDependencies(rootDir string) (dependencies []unpinnedDependency, err error) {
+	err = filepath.WalkDir(rootDir, func(path string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if entry.IsDir() {
+			if isSecretsSkippedDir(entry.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		parser := getUnpinnedDependenciesParser(entry.Name())
+		if parser == nil {
+			return nil
+		}
+		relativePath, err := filepath.Rel(rootDir, path)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		manifestDependencies, err := parser(content)
+		if err != nil {
+			// The check is an advisory, so a manifest which can't be parsed, such as a template, doesn't fail the scan
+			log.Warn("couldn't parse the manifest", filepath.ToSlash(relativePath), "so its unpinned dependencies are skipped:", err.Error())
+			return nil
+		}
+		for i := range manifestDependencies {
+			manifestDependencies[i].manifest = filepath.ToSlash(relativePath)
+		}
+		dependencies = append(dependencies, manifestDependencies...)
+		return nil
+	})
+	return
+}
+
+func getNpmUnpinnedDependencies(content []byte) ([]unpinnedDependency, error) {
+	var packageJson struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(content, &packageJson); err != nil {
+		return nil, err
+	}
+	var dependencies []unpinnedDependency
+	for _, dependenciesMap := range []map[string]string{packageJson.Dependencies, packageJson.DevDependencies} {
+		for name, version := range dependenciesMap {
+			if isNpmVersionRange(version) {
+				dependencies = append(dependencies, unpinnedDependency{name: name, version: version})
+			}
+		}
+	}
+	sort.Slice(dependencies, func(i, j int) bool {
+		return dependencies[i].name < dependencies[j].name
+	})
+	return dependencies, nil
+}
+
+// Return true if the npm version is a range, such as "^1.2.3", "~1.2.3", "1.x" or "*".
+// Dependencies which aren't fetched from the registry, such as "file:../lib" and git URLs, aren't ranges.
+func isNpmVersionRange(version string) bool {
+	version = strings.TrimSpace(version)
+	if strings.Contains(version, ":") || strings.Contains(version, "/") {
+		return false
+	}
+	if version == "" || version == "latest" || strings.ContainsAny(version, "^~*<>|") || strings.Contains(version, " - ") {
+		return true
+	}
+	for _, part := range strings.Split(strings.TrimPrefix(version, "="), ".") {
+		if part == "x" || part == "X" {
+			return true
+		}
+	}
+	return false
+}
+
+func getPipUnpinnedDependencies(content []byte) ([]unpinnedDependency, error) {
+	var dependencies []unpinnedDependency
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if commentStart := strings.Index(line, "#"); commentStart >= 0 {
+			line = line[:commentStart]
+		}
+		// Skip the environment markers, such as "; python_version < '3.8'"
+		if markersStart := strings.Index(line, ";"); markersStart >= 0 {
+			line = line[:markersStart]
+		}
+		line = strings.TrimSpace(line)
+		// Skip the options, such as "-r other.txt", and the requirements from URLs and paths
+		if line == "" || strings.HasPrefix(line, "-") || strings.Contains(line, "@") || strings.Contains(line, "/") {
+			continue
+		}
+		name, version := line, "*"
+		if specifierStart := strings.IndexAny(line, "=<>!~"); specifierStart >= 0 {
+			name, version = line[:specifierStart], strings.TrimSpace(line[specifierStart:])
+		}
+		if extrasStart := strings.Index(name, "["); extrasStart >= 0 {
+			name = name[:extrasStart]
+		}
+		if !strings.HasPrefix(version, "==") || pipRangeOperatorRegex.MatchString(strings.TrimLeft(version, "=")) || strings.Contains(version, "*") {
+			dependencies = append(dependencies, unpinnedDependency{name: strings.TrimSpace(name), version: version})
+		}
+	}
+	return dependencies, scanner.Err()
+}
+
+func getMavenUnpinnedDependencies(content []byte) ([]unpinnedDependency, error) {
+	var pom struct {
+		Dependencies []struct {
+			GroupId    string `xml:"groupId"`
+			ArtifactId string `xml:"artifactId"`
+			Version    string `xml:"version"`
+		} `xml:"dependencies>dependency"`
+	}
+	if err := xml.Unmarshal(content, &pom); err != nil {
+		return nil, err
+	}
+	var dependencies []unpinnedDependency
+	for _, dependency := range pom.Dependencies {
+		version := strings.TrimSpace(dependency.Version)
+		// The versions of dependencies with no version are set by the parent or the dependency management, and the properties aren't resolved
+		if strings.HasPrefix(version, "[") || strings.HasPrefix(version, "(") || version == "LATEST" || version == "RELEASE" {
+			dependencies = append(dependencies, unpinnedDependency{name: dependency.GroupId + ":" + dependency.ArtifactId, version: version})
+		}
+	}
+	return dependencies, nil
+}
+
+// Create the advisory section of the comment, which lists the unpinned dependencies
+func createUnpinnedDependenciesNote(dependencies []unpinnedDependency) string {
+	if len(dependencies) == 0 {
+		return ""
+	}
+	var notes strings.Builder
+	for _, dependency := range dependencies {
+		notes.WriteString(fmt.Sprintf("- **%s** `%s` in `%s`\n", dependency.name, dependency.version, dependency.manifest))
+	}
+	return "\n\n" + unpinnedDependenciesTitle + "\n\n" + unpinnedDependenciesNote + "\n\n" + notes.String()
+}
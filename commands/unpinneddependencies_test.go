@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsNpmVersionRange(t *testing.T) {
+	for _, version := range []string{"^1.2.3", "~1.2.3", "*", "", "latest", "1.x", "1.2.X", ">=1.0.0", "1.0.0 - 2.0.0", "<2 || >3"} {
+		assert.True(t, isNpmVersionRange(version), version)
+	}
+	for _, version := range []string{"1.2.3", "=1.2.3", "file:../lib", "github:jfrog/frogbot", "git+https://github.com/jfrog/frogbot.git", "npm:lodash@4.17.21"} {
+		assert.False(t, isNpmVersionRange(version), version)
+	}
+}
+
+func TestGetPipUnpinnedDependencies(t *testing.T) {
+	content := `# The dependencies of the app
+requests==2.28.1
+flask>=2.0
+django~=4.1  # Compatible release
+pyyaml
+urllib3[secure]==1.26.*
+numpy===1.24.0
+six==1.16.0; python_version < "3.8"
+-r other-requirements.txt
+frogbot @ https://github.com/jfrog/frogbot/archive/main.zip
+`
+	dependencies, err := getPipUnpinnedDependencies([]byte(content))
+	assert.NoError(t, err)
+	assert.Equal(t, []unpinnedDependency{
+		{name: "flask", version: ">=2.0"},
+		{name: "django", version: "~=4.1"},
+		{name: "pyyaml", version: "*"},
+		{name: "urllib3", version: "==1.26.*"},
+	}, dependencies)
+}
+
+func TestGetMavenUnpinnedDependencies(t *testing.T) {
+	content := `<project>
+  <dependencies>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId><version>[4.0,5.0)</version></dependency>
+    <dependency><groupId>com.google.guava</groupId><artifactId>guava</artifactId><version>31.1-jre</version></dependency>
+    <dependency><groupId>org.slf4j</groupId><artifactId>slf4j-api</artifactId><version>LATEST</version></dependency>
+    <dependency><groupId>org.jfrog</groupId><artifactId>parent-managed</artifactId></dependency>
+  </dependencies>
+</project>`
+	dependencies, err := getMavenUnpinnedDependencies([]byte(content))
+	assert.NoError(t, err)
+	assert.Equal(t, []unpinnedDependency{
+		{name: "junit:junit", version: "[4.0,5.0)"},
+		{name: "org.slf4j:slf4j-api", version: "LATEST"},
+	}, dependencies)
+}
+
+func TestFindUnpinnedDependencies(t *testing.T) {
+	rootDir := t.TempDir()
+	writeTestFiles(t, rootDir, map[string]string{
+		"package.json":                   `{"dependencies": {"lodash": "^4.17.21", "minimist": "1.2.6"}, "devDependencies": {"jest": "~29.0.0"}}`,
+		"server/requirements.txt":        "flask>=2.0\nrequests==2.28.1\n",
+		"node_modules/lib/package.json":  `{"dependencies": {"debug": "*"}}`,
+		"go.mod":                         "module github.com/jfrog/frogbot\n",
+		"server/requirements-dev.txt":    "pytest\n",
+		"docs/package.json.example":      `{"dependencies": {"react": "^18.0.0"}}`,
+		"services/billing/settings.json": `{"dependencies": {"react": "^18.0.0"}}`,
+	})
+	dependencies, err := findUnpinnedDependencies(rootDir)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []unpinnedDependency{
+		{manifest: "package.json", name: "jest", version: "~29.0.0"},
+		{manifest: "package.json", name: "lodash", version: "^4.17.21"},
+		{manifest: "server/requirements.txt", name: "flask", version: ">=2.0"},
+		{manifest: "server/requirements-dev.txt", name: "pytest", version: "*"},
+	}, dependencies)
+
+	// Invalid manifests are skipped
+	writeTestFiles(t, rootDir, map[string]string{"package.json": "{"})
+	dependencies, err = findUnpinnedDependencies(rootDir)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []unpinnedDependency{
+		{manifest: "server/requirements.txt", name: "flask", version: ">=2.0"},
+		{manifest: "server/requirements-dev.txt", name: "pytest", version: "*"},
+	}, dependencies)
+}
+
+func TestCreateUnpinnedDependenciesNote(t *testing.T) {
+	assert.Empty(t, createUnpinnedDependenciesNote(nil))
+	note := createUnpinnedDependenciesNote([]unpinnedDependency{{manifest: "package.json", name: "lodash", version: "^4.17.21"}})
+	assert.Equal(t, "\n\n"+unpinnedDependenciesTitle+"\n\n"+unpinnedDependenciesNote+"\n\n- **lodash** `^4.17.21` in `package.json`\n", note)
+}
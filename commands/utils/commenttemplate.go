@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+)
+
+const (
+	commentTemplateName = "comment"
+	// The default comment template, which creates the same comment as the output writer of the git provider
+	defaultCommentTemplate    = "{{.Title}}{{.Tables}}{{.Notes}}"
+	errInvalidCommentTemplate = "couldn't parse the comment template %s: %s"
+)
+
+// The functions available in the comment templates, in addition to the builtin functions of Go templates
+var commentTemplateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// DefaultCommentTemplate is used if the configured comment template fails to create the comment
+var DefaultCommentTemplate = template.Must(template.New(commentTemplateName).Funcs(commentTemplateFuncs).Parse(defaultCommentTemplate))
+
+// Read and parse the configured comment template, or return nil if no comment template is configured
+func (p *Params) loadCommentTemplate() (*template.Template, error) {
+	if p.CommentTemplatePath == "" {
+		return nil, nil
+	}
+	content, err := os.ReadFile(p.CommentTemplatePath)
+	if err != nil {
+		return nil, fmt.Errorf(errInvalidCommentTemplate, p.CommentTemplatePath, err.Error())
+	}
+	commentTemplate, err := template.New(commentTemplateName).Funcs(commentTemplateFuncs).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf(errInvalidCommentTemplate, p.CommentTemplatePath, err.Error())
+	}
+	return commentTemplate, nil
+}
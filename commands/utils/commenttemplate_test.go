@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadCommentTemplate(t *testing.T) {
+	params := Params{}
+	commentTemplate, err := params.loadCommentTemplate()
+	assert.NoError(t, err)
+	assert.Nil(t, commentTemplate)
+
+	dir := t.TempDir()
+	params.CommentTemplatePath = filepath.Join(dir, "comment.tmpl")
+	require.NoError(t, os.WriteFile(params.CommentTemplatePath, []byte("{{.IssuesCount}} issues in {{join .Names \", \"}}"), 0644))
+	commentTemplate, err = params.loadCommentTemplate()
+	assert.NoError(t, err)
+	var comment strings.Builder
+	assert.NoError(t, commentTemplate.Execute(&comment, map[string]any{"IssuesCount": 2, "Names": []string{"lodash", "minimist"}}))
+	assert.Equal(t, "2 issues in lodash, minimist", comment.String())
+
+	require.NoError(t, os.WriteFile(params.CommentTemplatePath, []byte("{{range .Vulnerabilities}}"), 0644))
+	_, err = params.loadCommentTemplate()
+	assert.ErrorContains(t, err, "couldn't parse the comment template "+params.CommentTemplatePath)
+
+	params.CommentTemplatePath = filepath.Join(dir, "missing.tmpl")
+	_, err = params.loadCommentTemplate()
+	assert.ErrorContains(t, err, "couldn't parse the comment template "+params.CommentTemplatePath)
+}
+
+func TestNewConfigAggregatorCommentTemplate(t *testing.T) {
+	templatePath := filepath.Join(t.TempDir(), "comment.tmpl")
+	require.NoError(t, os.WriteFile(templatePath, []byte("{{.Title}}"), 0644))
+	configData := &FrogbotConfigAggregator{{Params: Params{Git: Git{RepoName: "frogbot"}, CommentTemplatePath: templatePath}}}
+	gitParams := Git{GitProvider: vcsutils.GitHub, RepoOwner: "jfrog", Token: "123456789"}
+	configAggregator, err := NewConfigAggregator(configData, gitParams, &config.ServerDetails{}, true)
+	assert.NoError(t, err)
+	assert.NotNil(t, configAggregator[0].CommentTemplate)
+
+	// The template is validated when the config is loaded
+	require.NoError(t, os.WriteFile(templatePath, []byte("{{.Title"), 0644))
+	_, err = NewConfigAggregator(configData, gitParams, &config.ServerDetails{}, true)
+	assert.ErrorContains(t, err, "couldn't parse the comment template")
+}
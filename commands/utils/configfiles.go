@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	// The characters which make a config path a glob pattern, such as .frogbot/*.yml
+	globPatternChars         = "*?["
+	errNoConfigFilesFound    = "no frogbot-config files were found in %s"
+	errMultipleDefaultsFiles = "only one of the merged frogbot-config files may include a defaults section"
+)
+
+// The paths of the frogbot-config files set using the repeatable --config flag
+var configPaths []string
+
+func SetConfigPaths(paths []string) {
+	configPaths = paths
+}
+
+// expandConfigPaths returns the frogbot-config files of the given paths. Each path may be a file, a directory, whose .yml and .yaml files are returned sorted by name,
+// or a glob pattern, whose matching files are returned sorted by name. A path of a file is returned as is, even if the file doesn't exist.
+func expandConfigPaths(paths []string) (configFiles []string, err error) {
+	for _, path := range paths {
+		var files []string
+		if strings.ContainsAny(path, globPatternChars) {
+			if files, err = globConfigFiles(path); err != nil {
+				return nil, err
+			}
+		} else if info, statErr := os.Stat(path); statErr == nil && info.IsDir() {
+			if files, err = listConfigFilesInDir(path); err != nil {
+				return nil, err
+			}
+		} else {
+			configFiles = append(configFiles, path)
+			continue
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf(errNoConfigFilesFound, path)
+		}
+		configFiles = append(configFiles, files...)
+	}
+	return
+}
+
+func globConfigFiles(pattern string) (files []string, err error) {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	for _, match := range matches {
+		if info, err := os.Stat(match); err == nil && !info.IsDir() {
+			files = append(files, match)
+		}
+	}
+	return
+}
+
+func listConfigFilesInDir(dir string) (files []string, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if ext := filepath.Ext(entry.Name()); !entry.IsDir() && (ext == ".yml" || ext == ".yaml") {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(files)
+	return
+}
+
+// mergeConfigFiles appends the entries of the config file to the merged config. A repository which is already configured by a previous file
+// is replaced by the entry of the file, in its original position, so that the files can be split by team or by service and later files take precedence.
+// The defaults entries aren't deduplicated, so that a defaults entry in more than one file fails the validation of the merged config.
+func mergeConfigFiles(merged, configFile FrogbotConfigAggregator, configFilePath string) FrogbotConfigAggregator {
+	previousFilesEntries := len(merged)
+	for _, entry := range configFile {
+		if entry.Defaults != nil || entry.RepoName == "" {
+			merged = append(merged, entry)
+			continue
+		}
+		replaced := false
+		for i := 0; i < previousFilesEntries; i++ {
+			if merged[i].Defaults == nil && merged[i].RepoName == entry.RepoName {
+				log.Warn(fmt.Sprintf("The repository '%s' is configured in more than one frogbot-config file. Using its configuration from %s", entry.RepoName, configFilePath))
+				merged[i] = entry
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, entry)
+		}
+	}
+	return merged
+}
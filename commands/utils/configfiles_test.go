@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeConfigFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+}
+
+func TestExpandConfigPaths(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, map[string]string{"b.yml": "", "a.yaml": "", "c.yml": "", "readme.md": ""})
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "sub.yml"), 0700))
+
+	// The files of a directory are sorted by name, and the other files and the subdirectories are skipped
+	configFiles, err := expandConfigPaths([]string{dir})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "a.yaml"), filepath.Join(dir, "b.yml"), filepath.Join(dir, "c.yml")}, configFiles)
+
+	// The paths are expanded in their order, and the paths of files are kept even if the files don't exist
+	configFiles, err = expandConfigPaths([]string{filepath.Join(dir, "*.yml"), filepath.Join(dir, "missing.yml")})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "b.yml"), filepath.Join(dir, "c.yml"), filepath.Join(dir, "missing.yml")}, configFiles)
+
+	_, err = expandConfigPaths([]string{filepath.Join(dir, "*.json")})
+	assert.EqualError(t, err, "no frogbot-config files were found in "+filepath.Join(dir, "*.json"))
+}
+
+func TestReadConfigFromFileSystemMultipleFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, map[string]string{
+		"1-team-a.yml": `
+- params:
+    git:
+      repoName: service-a
+- params:
+    git:
+      repoName: shared
+    scan:
+      includeAllVulnerabilities: true
+`,
+		"2-team-b.yml": `
+- params:
+    git:
+      repoName: shared
+- params:
+    git:
+      repoName: service-b
+`,
+	})
+	configData, err := ReadConfigFromFileSystem(dir)
+	assert.NoError(t, err)
+	// The repository configured in both files is configured by the last file, in its original position
+	if assert.Len(t, *configData, 3) {
+		assert.Equal(t, "service-a", (*configData)[0].RepoName)
+		assert.Equal(t, "shared", (*configData)[1].RepoName)
+		assert.False(t, (*configData)[1].IncludeAllVulnerabilities)
+		assert.Equal(t, "service-b", (*configData)[2].RepoName)
+	}
+
+	// A single file is read as before
+	configData, err = ReadConfigFromFileSystem(filepath.Join(dir, "2-team-b.yml"))
+	assert.NoError(t, err)
+	assert.Len(t, *configData, 2)
+
+	_, err = ReadConfigFromFileSystem(filepath.Join(dir, "1-team-a.yml"), filepath.Join(dir, "missing.yml"))
+	assert.ErrorContains(t, err, "failed to read "+filepath.Join(dir, "missing.yml"))
+}
+
+func TestMergeConfigFilesKeepsDuplicatesOfSameFile(t *testing.T) {
+	repoConfig := func(repoName string) FrogbotRepoConfig {
+		return FrogbotRepoConfig{Params: Params{Git: Git{RepoName: repoName}}}
+	}
+	defaults := FrogbotRepoConfig{Defaults: &Params{}}
+	merged := mergeConfigFiles(nil, FrogbotConfigAggregator{defaults, repoConfig("a")}, "first.yml")
+	merged = mergeConfigFiles(merged, FrogbotConfigAggregator{defaults, repoConfig("b"), repoConfig("b")}, "second.yml")
+	// Only the repositories of previous files are replaced, and the defaults entries are kept, to fail the validation of the merged config
+	assert.Len(t, merged, 5)
+}
+
+func TestValidateConfigFileMultipleFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFiles(t, dir, map[string]string{
+		"a.yml": "- defaults:\n    scan:\n      minSeverity: High\n- params:\n    git:\n      repoName: a\n",
+		"b.yml": "- defaults:\n    scan:\n      minSeverity: Low\n- params:\n    git:\n      repoName: b\n    commentStyle: invalid\n",
+	})
+	validationErrors, err := ValidateConfigFile(dir)
+	assert.NoError(t, err)
+	if assert.Len(t, validationErrors, 2) {
+		assert.Equal(t, filepath.Join(dir, "b.yml"), validationErrors[0].File)
+		assert.Equal(t, ConfigValidationError{Message: errMultipleDefaultsFiles}, validationErrors[1])
+	}
+}
@@ -8,7 +8,106 @@ const (
 	baseResourceUrl = "https://raw.githubusercontent.com/jfrog/frogbot/master/resources/"
 
 	// Errors
-	errUnsupportedMultiRepo = "multi repository configuration isn't supported. only one repository configuration is allowed"
+	errUnsupportedMultiRepo      = "multi repository configuration isn't supported. only one repository configuration is allowed"
+	errRepositoryFailed          = "repository %s returned the following error: \n%s\n"
+	errInvalidScanCacheTtlHours  = "the scan cache TTL of %d hours is invalid. A non-negative number of hours is expected"
+	errInvalidCacheMaxEntries    = "the maximal number of scan cache entries %d is invalid. A non-negative number of entries is expected"
+	errInvalidSeverityReviewers  = "the value of the %s environment is expected to be a semicolon separated list of severity=reviewers pairs, such as Critical=my-org/security,alice;Low=bob. The value received however is %s"
+	errInvalidMinCvss            = "the minCvss param is expected to be a CVSS score between 0 and 10. The value received however is %v"
+	errInvalidVulnsAgeDays       = "the failOnVulnsOlderThanDays value of %d days is invalid. A non-negative number of days is expected"
+	errMultipleTempDirs          = "all the repositories in the frogbot-config file must use the same temp directory"
+	errInvalidIgnoredDependency  = "the ignored dependency '%s' is invalid. A dependency name, optionally followed by a semver range, such as 'lodash >=4.0.0 <4.17.21', is expected"
+	errInvalidIgnoredIssue       = "the ignored issue '%s' is invalid. An issue ID, optionally followed by an expiry date, such as 'CVE-2022-24450 until 2024-06-01', is expected"
+	errInvalidInlineIgnore       = "the inline ignore '%s' is invalid. An issue ID, optionally followed by an expiry date and a reason, such as 'frogbot:ignore CVE-2022-24450 until 2024-06-01 not exploitable', is expected"
+	errInlineIgnoreNoDependency  = "the annotation isn't on the line of a dependency, or on the line before it"
+	errInvalidMaxCommentLength   = "the maximum comment length %d is invalid. A length of at least %d characters is expected, or zero for the limit of the git provider"
+	errInvalidSubmodulePath      = "the path '%s' of the '%s' submodule is invalid, since it's outside of the repository"
+	errEmptyConfig               = "the frogbot-config file is empty"
+	errMissingRepoName           = "repo name is missing from the frogbot-config file"
+	errMultipleDefaults          = "the frogbot-config file may include a single defaults section"
+	errInvalidProxy              = "the proxy URL '%s' is invalid. A URL such as http://proxy.example.com:8080 is expected"
+	errInvalidFixPRBranches      = "the fixPRBranches pattern '%s' is invalid"
+	errInvalidPathIgnores        = "the pathIgnores pattern '%s' is invalid"
+	errInvalidAutoDetectExcludes = "the autoDetectExcludes pattern '%s' is invalid"
+	errSecretEnvAndFile          = "only one of the %s and %s environment variables may be set"
+	errReadSecretFile            = "couldn't read the file set in the %s environment variable: %s"
+	errIncompleteGitHubApp       = "the GitHub App authentication requires the %s, %s and %s environment variables"
+	errMultipleProxies           = "all the repositories in the frogbot-config file must use the same proxy"
+	errReadCaCert                = "couldn't read the CA certificates file '%s': %s"
+	errInvalidCaCert             = "the CA certificates file '%s' doesn't include any PEM encoded certificate"
+	errMultipleCaCerts           = "all the repositories in the frogbot-config file must use the same CA certificates file"
+	errInvalidCustomHeader       = "the custom header '%s' is invalid. A valid HTTP header name and value are expected"
+	errInvalidCustomHeadersEnv   = "the value of the %s environment is expected to be a comma separated list of name=value pairs, such as X-Org-Id=1234,X-Team=security. The value received however is %s"
+	errMultipleCustomHeaders     = "all the repositories in the frogbot-config file must use the same custom headers"
+	errInvalidUserAgent          = "the user agent '%s' is invalid. A valid HTTP header value is expected"
+	errMultipleUserAgents        = "all the repositories in the frogbot-config file must use the same user agent"
+	errInvalidSeverity           = "the severity '%s' set in %s is invalid. The supported severities are Low, Medium, High and Critical"
+	errInvalidUnknownSeverityAs  = "the value '%s' of unknownSeverityAs is invalid. The supported values are Low, Medium, High, Critical and ignore"
+	errInvalidReportTarget       = "the report target '%s' is invalid. The supported report targets are pr-comment and issue"
+	errUnknownProfile            = "the profile '%s' isn't defined in the profiles section of the frogbot-config file"
+	errInvalidUpgradeStrategy    = "the upgrade strategy '%s' is invalid. The supported upgrade strategies are minimal, minor and latest"
+	errInvalidFixPRGrouping      = "the fix pull requests grouping '%s' is invalid. The supported groupings are per-dependency, per-ecosystem and all"
+	errInvalidCommentStyle       = "the comment style '%s' is invalid. The supported comment styles are full and status"
+	errInvalidWriteStepSummary   = "the step summary mode '%s' is invalid. The supported modes are with-comment and instead-of-comment"
+	errInvalidNewIssuesBaseline  = "the new issues baseline '%s' is invalid. The supported baselines are target-branch and scan-history"
+	errInvalidScanMode           = "the scan mode '%s' is invalid. The supported scan modes are vulnerabilities, violations and both"
+	errScanModeWithoutPolicy     = "the scan mode '%s' requires Xray watches or a JFrog project key, whose policies the violations are found by"
+	errInvalidDirectDependencies = "the direct dependencies source '%s' is invalid. The supported sources are manifest and graph"
+	errInvalidEcosystem          = "the ecosystem '%s' is invalid. The supported ecosystems are %s"
+	errInvalidSection            = "the section '%s' set in sectionOrder is invalid. The supported sections are security, iac and secrets"
+	errDuplicateSection          = "the section '%s' is listed more than once in sectionOrder"
+	errInvalidXrayFailoverUrl    = "the Xray failover URL '%s' is invalid. A URL such as https://dr.jfrog.example.com/xray/ is expected"
+	errInvalidOtelEndpoint       = "the OpenTelemetry endpoint '%s' is invalid. A URL such as http://otel-collector:4318 is expected"
+	errInvalidOtelHeader         = "the OpenTelemetry header '%s' is invalid. A valid HTTP header name and value are expected"
+	errInvalidOtelHeadersEnv     = "the value of the %s environment is expected to be a comma separated list of URL encoded name=value pairs, such as api-key=1234. The value received however is %s"
+	errInvalidRepoConfig         = "couldn't parse the frogbot-config file of the scanned pull request: %s"
+	errInvalidRepoArchive        = "failed to download repository %s/%s, branch %s: the downloaded archive isn't a tar.gz archive. This may be caused by an authentication error, for which the git provider returned an HTML page rather than the archive: %s"
+	errEmptyRepoArchive          = "failed to download repository %s/%s, branch %s: the downloaded archive is empty"
+	errDiscussionReportTarget    = "reporting to a GitHub discussion isn't supported, since discussions are available only through the GitHub GraphQL API. Use the issue report target instead"
+
+	// Report targets
+	PullRequestCommentReportTarget = "pr-comment"
+	IssueReportTarget              = "issue"
+	DiscussionReportTarget         = "discussion"
+
+	// Sections of the pull request comment
+	SecuritySection = "security"
+	IacSection      = "iac"
+	SecretsSection  = "secrets"
+
+	// Upgrade strategies
+	MinimalUpgradeStrategy = "minimal"
+	MinorUpgradeStrategy   = "minor"
+	LatestUpgradeStrategy  = "latest"
+
+	// Groupings of the fixes into fix pull requests
+	PerDependencyFixPRGrouping = "per-dependency"
+	PerEcosystemFixPRGrouping  = "per-ecosystem"
+	AllFixPRGrouping           = "all"
+
+	// Styles of the pull request comment
+	FullCommentStyle   = "full"
+	StatusCommentStyle = "status"
+
+	// Drops the issues with an unknown severity, when set in unknownSeverityAs
+	IgnoreUnknownSeverity = "ignore"
+
+	// Sources of the direct dependencies of the issues
+	ManifestDirectDependencies = "manifest"
+	GraphDirectDependencies    = "graph"
+
+	// Baselines of the new issues of a pull request
+	TargetBranchBaseline = "target-branch"
+	ScanHistoryBaseline  = "scan-history"
+
+	// Modes of the GitHub Actions step summary
+	WithCommentStepSummary      = "with-comment"
+	InsteadOfCommentStepSummary = "instead-of-comment"
+
+	// Scan modes
+	VulnerabilitiesScanMode = "vulnerabilities"
+	ViolationsScanMode      = "violations"
+	BothScanMode            = "both"
 
 	// Images
 	NoVulnerabilityBannerSource ImageSource = "noVulnerabilityBanner.png"
@@ -18,6 +117,29 @@ const (
 	mediumSeveritySource        ImageSource = "mediumSeverity.png"
 	lowSeveritySource           ImageSource = "lowSeverity.png"
 
+	// Severity badges
+	criticalSeverityBadge = "🔴"
+	highSeverityBadge     = "🟠"
+	mediumSeverityBadge   = "🟡"
+	lowSeverityBadge      = "🟢"
+
+	// Pull request title length limits
+	gitHubPullRequestTitleMaxLength          = 256
+	gitLabPullRequestTitleMaxLength          = 255
+	bitbucketServerPullRequestTitleMaxLength = 255
+	azureReposPullRequestTitleMaxLength      = 400
+	truncationSuffix                         = "..."
+
+	// Pull request comment length limits, in characters
+	gitHubCommentMaxLength          = 65536
+	gitLabCommentMaxLength          = 1000000
+	bitbucketServerCommentMaxLength = 32768
+	azureReposCommentMaxLength      = 150000
+	truncatedCommentNote            = "\n\n⚠️ This comment was truncated, because it exceeds the maximum length of %d characters. Scan the project locally to view the full results."
+
+	// The minimal maxCommentLength, which fits the truncation note and the trailing markers
+	minCommentLength = 1000
+
 	// VCS providers params
 	GitHub          vcsProvider = "github"
 	GitLab          vcsProvider = "gitlab"
@@ -34,6 +156,16 @@ const (
 	jfrogArtifactoryUrlEnv = "JF_ARTIFACTORY_URL"
 	JFrogPasswordEnv       = "JF_PASSWORD"
 	JFrogTokenEnv          = "JF_ACCESS_TOKEN"
+	// The suffix of the environment variables containing the path of a file with a secret, such as JF_ACCESS_TOKEN_FILE
+	secretFileEnvSuffix = "_FILE"
+
+	// Network environment variables
+	ProxyEnv         = "JF_PROXY"
+	httpProxyEnv     = "HTTP_PROXY"
+	httpsProxyEnv    = "HTTPS_PROXY"
+	CaCertPathEnv    = "JF_CA_CERT_PATH"
+	sslCertFileEnv   = "SSL_CERT_FILE"
+	CustomHeadersEnv = "JF_CUSTOM_HEADERS"
 
 	// Git environment variables
 	GitProvider     = "JF_GIT_PROVIDER"
@@ -51,6 +183,78 @@ const (
 	IncludeAllVulnerabilitiesEnv = "JF_INCLUDE_ALL_VULNERABILITIES"
 	FailOnSecurityIssuesEnv      = "JF_FAIL"
 	UseWrapperEnv                = "JF_USE_WRAPPER"
+	TempDirEnv                   = "JF_TEMP_DIR"
+	PullRequestTitleBadgeEnv     = "JF_PR_TITLE_SEVERITY_BADGE"
+	SummarizeUnchangedResultsEnv = "JF_SUMMARIZE_UNCHANGED_RESULTS"
+	GitLabApprovalGateEnv        = "JF_GITLAB_APPROVAL_GATE"
+	MinSeverityEnv               = "JF_MIN_SEVERITY"
+	FailSeverityThresholdEnv     = "JF_FAIL_SEVERITY_THRESHOLD"
+	ReportTargetEnv              = "JF_REPORT_TARGET"
+	FailOnScanErrorEnv           = "JF_FAIL_ON_SCAN_ERROR"
+	UpgradeStrategyEnv           = "JF_UPGRADE_STRATEGY"
+	ShowXrayScanLinkEnv          = "JF_SHOW_XRAY_SCAN_LINK"
+	ShowRemediationCommandsEnv   = "JF_SHOW_REMEDIATION_COMMANDS"
+	ShowFixChainsEnv             = "JF_SHOW_FIX_CHAINS"
+	CleanScanMessageEnv          = "JF_CLEAN_SCAN_MESSAGE"
+	SuppressCleanCommentEnv      = "JF_SUPPRESS_CLEAN_COMMENT"
+	ScanSecretsEnv               = "JF_SCAN_SECRETS"
+	ScanChangedOnlyEnv           = "JF_SCAN_CHANGED_ONLY"
+	SplitCommentsBySeverityEnv   = "JF_SPLIT_COMMENTS_BY_SEVERITY"
+	FixPRBranchesEnv             = "JF_FIX_PR_BRANCHES"
+	PathIgnoresEnv               = "JF_PATH_IGNORES"
+	HidePathIgnoredIssuesEnv     = "JF_HIDE_PATH_IGNORED_ISSUES"
+	BotNameEnv                   = "JF_BOT_NAME"
+	WarnUnpinnedEnv              = "JF_WARN_UNPINNED"
+	WebhookSecretEnv             = "JF_WEBHOOK_SECRET"
+	ScanModeEnv                  = "JF_SCAN_MODE"
+	CommentOnlyOnChangeEnv       = "JF_COMMENT_ONLY_ON_CHANGE"
+	PostScanCommandEnv           = "JF_POST_SCAN_CMD"
+	ScanResultsPathEnv           = "JF_SCAN_RESULTS_PATH"
+	SectionOrderEnv              = "JF_SECTION_ORDER"
+	XrayFailoverUrlsEnv          = "JF_XRAY_FAILOVER_URLS"
+	AnnotateDiffEnv              = "JF_ANNOTATE_DIFF"
+	RepoConfigCanRelaxGatingEnv  = "JF_REPO_CONFIG_CAN_RELAX_GATING"
+	CommitStatusContextEnv       = "JF_COMMIT_STATUS_CONTEXT"
+	FixPRGroupingEnv             = "JF_FIX_PR_GROUPING"
+	CommentStyleEnv              = "JF_COMMENT_STYLE"
+	AllowPrereleaseEnv           = "JF_ALLOW_PRERELEASE"
+	ScanSubmodulesEnv            = "JF_SCAN_SUBMODULES"
+	NewIssuesBaselineEnv         = "JF_NEW_ISSUES_BASELINE"
+	ScanHistoryDirEnv            = "JF_SCAN_HISTORY_DIR"
+	ScanDraftsEnv                = "JF_SCAN_DRAFTS"
+	WriteStepSummaryEnv          = "JF_WRITE_STEP_SUMMARY"
+	AutoDetectWorkingDirsEnv     = "JF_AUTO_DETECT_WORKING_DIRS"
+	AutoDetectExcludesEnv        = "JF_AUTO_DETECT_EXCLUDES"
+	FixPRReviewersEnv            = "JF_FIX_PR_REVIEWERS"
+	ReviewersBySeverityEnv       = "JF_REVIEWERS_BY_SEVERITY"
+	MinCvssEnv                   = "JF_MIN_CVSS"
+	HideBelowMinCvssEnv          = "JF_HIDE_BELOW_MIN_CVSS"
+	ExcludeUnscoredCvssEnv       = "JF_EXCLUDE_UNSCORED_CVSS"
+	FixPRCooldownDaysEnv         = "JF_FIX_PR_COOLDOWN_DAYS"
+	DeltaScanEnv                 = "JF_DELTA_SCAN"
+	SkipClosedPRsEnv             = "JF_SKIP_CLOSED_PRS"
+	UnknownSeverityAsEnv         = "JF_UNKNOWN_SEVERITY_AS"
+	OtelEndpointEnv              = "JF_OTEL_ENDPOINT"
+	OtelHeadersEnv               = "JF_OTEL_HEADERS"
+	OtelExporterHeadersEnv       = "OTEL_EXPORTER_OTLP_HEADERS"
+	RepoDownloadAttemptsEnv      = "JF_REPO_DOWNLOAD_ATTEMPTS"
+	ThreadedUpdatesEnv           = "JF_THREADED_UPDATES"
+	UserAgentEnv                 = "JF_USER_AGENT"
+	FailureCommentEnv            = "JF_FAILURE_COMMENT"
+	FailOnPartialScanEnv         = "JF_FAIL_ON_PARTIAL_SCAN"
+	CompactTableEnv              = "JF_COMPACT_TABLE"
+	ScanCacheEnv                 = "JF_SCAN_CACHE"
+	ScanCacheDirEnv              = "JF_SCAN_CACHE_DIR"
+	ScanCacheTtlHoursEnv         = "JF_SCAN_CACHE_TTL_HOURS"
+	ScanCacheMaxEntriesEnv       = "JF_SCAN_CACHE_MAX_ENTRIES"
+	InlineIgnoresEnv             = "JF_INLINE_IGNORES"
+	SeverityColorsEnv            = "JF_SEVERITY_COLORS"
+	CommentTemplatePathEnv       = "JF_COMMENT_TEMPLATE_PATH"
+	PreventDuplicateRunsEnv      = "JF_PREVENT_DUPLICATE_RUNS"
+	OnlyWithExploitsEnv          = "JF_ONLY_WITH_EXPLOITS"
+	ProfileEnv                   = "JF_PROFILE"
+	ShowVulnerabilitiesAgeEnv    = "JF_SHOW_VULNERABILITIES_AGE"
+	FailOnVulnsOlderThanDaysEnv  = "JF_FAIL_ON_VULNS_OLDER_THAN_DAYS"
 	WatchesDelimiter             = ","
 
 	//#nosec G101 -- False positive - no hardcoded credentials.
@@ -58,16 +262,34 @@ const (
 	GitBaseBranchEnv    = "JF_GIT_BASE_BRANCH"
 	GitPullRequestIDEnv = "JF_GIT_PULL_REQUEST_ID"
 	GitApiEndpointEnv   = "JF_GIT_API_ENDPOINT"
+	// GitHub App authentication, used instead of the git token
+	GitHubAppIdEnv             = "JF_GITHUB_APP_ID"
+	GitHubAppInstallationIdEnv = "JF_GITHUB_APP_INSTALLATION_ID"
+	//#nosec G101 -- False positive - no hardcoded credentials.
+	GitHubAppPrivateKeyEnv = "JF_GITHUB_APP_PRIVATE_KEY"
 
 	// Comment
 	tableHeader = "\n| SEVERITY | DIRECT DEPENDENCIES | DIRECT DEPENDENCIES VERSIONS | IMPACTED DEPENDENCY NAME | IMPACTED DEPENDENCY VERSION | FIXED VERSIONS | CVE\n" +
 		":--: | -- | -- | -- | -- | :--: | --"
-	simplifiedTableHeader = "\n| SEVERITY | DIRECT DEPENDENCIES | IMPACTED DEPENDENCY NAME | IMPACTED DEPENDENCY VERSION | FIXED VERSIONS | CVE\n" + ":--: | -- | -- | -- | :--: | --"
-	WhatIsFrogbotMd       = "\n\n[What is Frogbot?](https://github.com/jfrog/frogbot#readme)\n"
+	simplifiedTableHeader        = "\n| SEVERITY | DIRECT DEPENDENCIES | IMPACTED DEPENDENCY NAME | IMPACTED DEPENDENCY VERSION | FIXED VERSIONS | CVE\n" + ":--: | -- | -- | -- | :--: | --"
+	compactTableHeader           = "\n| SEVERITY / CVE | IMPACTED DEPENDENCY | FIXED VERSIONS\n" + ":--: | -- | :--:"
+	simplifiedCompactTableHeader = "\n| SEVERITY / CVE | IMPACTED DEPENDENCY | FIXED VERSIONS |\n" + ":--: | -- | :--:"
+	iacTableHeader               = "\n| SEVERITY | FILE | FINDING\n" + ":--: | -- | --"
+	simplifiedIacTableHeader     = "\n| SEVERITY | FILE | FINDING |\n" + ":--: | -- | --"
+	WhatIsFrogbotMd              = "\n\n[What is Frogbot?](https://github.com/jfrog/frogbot#readme)\n"
+	severityLegend               = "\n\n**Severity:** %s Critical · %s High · %s Medium · %s Low"
+	showingSeverityPolicy        = "Showing issues of %s severity and above"
+	failingSeverityPolicy        = "Failing on %s and above"
+	notFailingPolicy             = "Not failing on issues"
+	projectsPolicy               = "Some projects in this repository use a different policy"
+	ecosystemsPolicy             = "Some ecosystems use a different policy"
+	ignoredIssuesExpiryTitle     = "#### ⏳ Ignored issues about to expire\n\nThese issues will be reported again after their expiry date, unless the ignore entries are extended"
 
 	// Product ID for usage reporting
 	productId = "frogbot"
 
 	// The 'GITHUB_ACTIONS' environment variable exists when the CI is GitHub Actions
 	GitHubActionsEnv = "GITHUB_ACTIONS"
+	// The 'GITHUB_STEP_SUMMARY' environment variable is the path of the step summary file of the current GitHub Actions step
+	GitHubStepSummaryEnv = "GITHUB_STEP_SUMMARY"
 )
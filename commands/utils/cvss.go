@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+)
+
+const maxCvssScore = 10
+
+// CvssPolicy determines which vulnerabilities fail the scan according to their CVSS scores, in addition to their severities
+type CvssPolicy struct {
+	// Vulnerabilities whose highest CVSS score is below the given score, between 0 and 10, don't fail the scan. Vulnerabilities without a CVSS score aren't affected.
+	// If zero, the CVSS scores don't affect the scan.
+	MinCvss float64 `yaml:"minCvss,omitempty"`
+	// Also omit the vulnerabilities below minCvss from the results, rather than only not failing the scan on them
+	HideBelowMinCvss bool `yaml:"hideBelowMinCvss,omitempty"`
+	// Treat the vulnerabilities without a CVSS score as below minCvss
+	ExcludeUnscoredCvss bool `yaml:"excludeUnscoredCvss,omitempty"`
+}
+
+func (cp *CvssPolicy) validateMinCvss() error {
+	if cp.MinCvss < 0 || cp.MinCvss > maxCvssScore {
+		return fmt.Errorf(errInvalidMinCvss, cp.MinCvss)
+	}
+	return nil
+}
+
+// GetMaxCvssScore returns the highest CVSS score of the CVEs of the issue, preferring the CVSS v3 score of each CVE over its v2 score.
+// Returns false if none of the CVEs has a CVSS score.
+func GetMaxCvssScore(row formats.VulnerabilityOrViolationRow) (maxScore float64, found bool) {
+	for _, cve := range row.Cves {
+		score, err := strconv.ParseFloat(cve.CvssV3, 64)
+		if err != nil {
+			if score, err = strconv.ParseFloat(cve.CvssV2, 64); err != nil {
+				continue
+			}
+		}
+		if !found || score > maxScore {
+			maxScore = score
+			found = true
+		}
+	}
+	return
+}
+
+func (cp *CvssPolicy) isBelowMinCvss(row formats.VulnerabilityOrViolationRow) bool {
+	if cp.MinCvss == 0 {
+		return false
+	}
+	score, found := GetMaxCvssScore(row)
+	if !found {
+		return cp.ExcludeUnscoredCvss
+	}
+	return score < cp.MinCvss
+}
+
+// SplitByMinCvss returns the issues with a CVSS score of MinCvss and above, and the issues below it
+func (cp *CvssPolicy) SplitByMinCvss(vulnerabilitiesRows []formats.VulnerabilityOrViolationRow) (aboveRows, belowRows []formats.VulnerabilityOrViolationRow) {
+	if cp.MinCvss == 0 {
+		return vulnerabilitiesRows, nil
+	}
+	for _, row := range vulnerabilitiesRows {
+		if cp.isBelowMinCvss(row) {
+			belowRows = append(belowRows, row)
+		} else {
+			aboveRows = append(aboveRows, row)
+		}
+	}
+	return
+}
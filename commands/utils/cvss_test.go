@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMaxCvssScore(t *testing.T) {
+	// The v3 score of each CVE is preferred over its v2 score
+	score, found := GetMaxCvssScore(formats.VulnerabilityOrViolationRow{Cves: []formats.CveRow{{CvssV2: "9.0", CvssV3: "5.3"}, {CvssV2: "7.5"}}})
+	assert.True(t, found)
+	assert.Equal(t, 7.5, score)
+
+	_, found = GetMaxCvssScore(formats.VulnerabilityOrViolationRow{Cves: []formats.CveRow{{Id: "CVE-2021-1"}}})
+	assert.False(t, found)
+	_, found = GetMaxCvssScore(formats.VulnerabilityOrViolationRow{})
+	assert.False(t, found)
+}
+
+func TestSplitByMinCvss(t *testing.T) {
+	high := formats.VulnerabilityOrViolationRow{IssueId: "XRAY-1", Cves: []formats.CveRow{{CvssV3: "9.8"}}}
+	low := formats.VulnerabilityOrViolationRow{IssueId: "XRAY-2", Cves: []formats.CveRow{{CvssV3: "4.0"}}}
+	unscored := formats.VulnerabilityOrViolationRow{IssueId: "XRAY-3"}
+	rows := []formats.VulnerabilityOrViolationRow{high, low, unscored}
+
+	// All the issues are kept by default
+	policy := CvssPolicy{}
+	aboveRows, belowRows := policy.SplitByMinCvss(rows)
+	assert.Equal(t, rows, aboveRows)
+	assert.Empty(t, belowRows)
+
+	// The issues without a CVSS score are kept, unless they're excluded
+	policy.MinCvss = 7
+	aboveRows, belowRows = policy.SplitByMinCvss(rows)
+	assert.Equal(t, []formats.VulnerabilityOrViolationRow{high, unscored}, aboveRows)
+	assert.Equal(t, []formats.VulnerabilityOrViolationRow{low}, belowRows)
+
+	policy.ExcludeUnscoredCvss = true
+	aboveRows, belowRows = policy.SplitByMinCvss(rows)
+	assert.Equal(t, []formats.VulnerabilityOrViolationRow{high}, aboveRows)
+	assert.Equal(t, []formats.VulnerabilityOrViolationRow{low, unscored}, belowRows)
+}
+
+func TestValidateMinCvss(t *testing.T) {
+	for _, minCvss := range []float64{0, 7.5, 10} {
+		policy := CvssPolicy{MinCvss: minCvss}
+		assert.NoError(t, policy.validateMinCvss())
+	}
+	policy := CvssPolicy{MinCvss: 11}
+	assert.EqualError(t, policy.validateMinCvss(), "the minCvss param is expected to be a CVSS score between 0 and 10. The value received however is 11")
+}
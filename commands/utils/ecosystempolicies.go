@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+)
+
+const errInvalidEcosystemPolicy = "the ecosystem '%s' of ecosystemPolicies is invalid. The supported ecosystems are %s"
+
+func (s *Scan) validateEcosystemPolicies() error {
+	for ecosystem, policy := range s.EcosystemPolicies {
+		if !isSupportedEcosystem(ecosystem) {
+			return fmt.Errorf(errInvalidEcosystemPolicy, ecosystem, formatSupportedEcosystems())
+		}
+		if err := policy.validateSeverities(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Return the severity policy of the issues of the ecosystem in this project, which is the policy of the ecosystem, if one is set.
+// The values which aren't set in the policy of the ecosystem are inherited from the severity policy of the project.
+func (p *Project) getEcosystemSeverityPolicy(technology coreutils.Technology) SeverityPolicy {
+	policy, exists := p.EcosystemPolicies[technology.ToString()]
+	if !exists {
+		return p.SeverityPolicy
+	}
+	mergeDefaults(reflect.ValueOf(&policy).Elem(), reflect.ValueOf(p.SeverityPolicy))
+	return policy
+}
+
+// FilterBySeverity returns the issues with a severity of the minSeverity of their ecosystem and above.
+func (p *Project) FilterBySeverity(vulnerabilitiesRows []formats.VulnerabilityOrViolationRow) []formats.VulnerabilityOrViolationRow {
+	if len(p.EcosystemPolicies) == 0 {
+		return p.SeverityPolicy.FilterBySeverity(vulnerabilitiesRows)
+	}
+	var filteredRows []formats.VulnerabilityOrViolationRow
+	for _, row := range vulnerabilitiesRows {
+		policy := p.getEcosystemSeverityPolicy(row.Technology)
+		if policy.isShown(row.Severity) {
+			filteredRows = append(filteredRows, row)
+		}
+	}
+	return filteredRows
+}
+
+// HasFailingIssues returns true if one of the issues has a severity of the failSeverityThreshold of its ecosystem and above.
+// The ecosystem of an issue is the package manager detected in the working dir in which the issue was found.
+func (p *Project) HasFailingIssues(vulnerabilitiesRows []formats.VulnerabilityOrViolationRow) bool {
+	for _, row := range vulnerabilitiesRows {
+		policy := p.getEcosystemSeverityPolicy(row.Technology)
+		if policy.isFailing(row.Severity) {
+			return true
+		}
+	}
+	return false
+}
+
+// Merge the profile over the policies of the ecosystems, so that the profile overrides them like it overrides the policies of the projects.
+// A new map is returned, to keep the config data unchanged.
+func (profile *Profile) mergeEcosystemPolicies(ecosystemPolicies map[string]SeverityPolicy) map[string]SeverityPolicy {
+	if len(ecosystemPolicies) == 0 {
+		return ecosystemPolicies
+	}
+	merged := make(map[string]SeverityPolicy, len(ecosystemPolicies))
+	for ecosystem, policy := range ecosystemPolicies {
+		merged[ecosystem] = profile.mergeSeverityPolicy(policy)
+	}
+	return merged
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+var ecosystemPolicyTestRows = []formats.VulnerabilityOrViolationRow{
+	{Severity: "Medium", IssueId: "XRAY-1", Technology: coreutils.Npm},
+	{Severity: "Medium", IssueId: "XRAY-2", Technology: coreutils.Go},
+	{Severity: "Low", IssueId: "XRAY-3", Technology: coreutils.Go},
+}
+
+func createEcosystemPoliciesTestProject() Project {
+	return Project{
+		SeverityPolicy: SeverityPolicy{MinSeverity: "Medium", FailSeverityThreshold: "High"},
+		EcosystemPolicies: map[string]SeverityPolicy{
+			"npm": {FailSeverityThreshold: "Medium"},
+			"go":  {MinSeverity: "Low", FailSeverityThreshold: "Critical"},
+		},
+	}
+}
+
+func TestGetEcosystemSeverityPolicy(t *testing.T) {
+	project := createEcosystemPoliciesTestProject()
+	// The unset values are inherited from the project
+	assert.Equal(t, SeverityPolicy{MinSeverity: "Medium", FailSeverityThreshold: "Medium"}, project.getEcosystemSeverityPolicy(coreutils.Npm))
+	assert.Equal(t, SeverityPolicy{MinSeverity: "Low", FailSeverityThreshold: "Critical"}, project.getEcosystemSeverityPolicy(coreutils.Go))
+	assert.Equal(t, project.SeverityPolicy, project.getEcosystemSeverityPolicy(coreutils.Maven))
+	// The policies of the config data are unchanged
+	assert.Empty(t, project.EcosystemPolicies["npm"].MinSeverity)
+}
+
+func TestProjectFilterBySeverity(t *testing.T) {
+	project := createEcosystemPoliciesTestProject()
+	filteredRows := project.FilterBySeverity(ecosystemPolicyTestRows)
+	assert.Len(t, filteredRows, 3)
+
+	project.EcosystemPolicies = nil
+	filteredRows = project.FilterBySeverity(ecosystemPolicyTestRows)
+	if assert.Len(t, filteredRows, 2) {
+		assert.Equal(t, "XRAY-2", filteredRows[1].IssueId)
+	}
+}
+
+func TestProjectHasFailingIssues(t *testing.T) {
+	project := createEcosystemPoliciesTestProject()
+	// A Medium npm issue fails, while a Medium go issue doesn't
+	assert.True(t, project.HasFailingIssues(ecosystemPolicyTestRows[:1]))
+	assert.False(t, project.HasFailingIssues(ecosystemPolicyTestRows[1:]))
+
+	project.EcosystemPolicies = nil
+	assert.False(t, project.HasFailingIssues(ecosystemPolicyTestRows))
+}
+
+func TestValidateEcosystemPolicies(t *testing.T) {
+	scan := Scan{EcosystemPolicies: map[string]SeverityPolicy{"npm": {FailSeverityThreshold: "Medium"}}}
+	assert.NoError(t, scan.validateEcosystemPolicies())
+
+	scan.EcosystemPolicies["cargo"] = SeverityPolicy{}
+	assert.EqualError(t, scan.validateEcosystemPolicies(), "the ecosystem 'cargo' of ecosystemPolicies is invalid. The supported ecosystems are dotnet, go, gradle, maven, npm, nuget, pip, pipenv, poetry and yarn")
+
+	scan.EcosystemPolicies = map[string]SeverityPolicy{"go": {MinSeverity: "Severe"}}
+	assert.EqualError(t, scan.validateEcosystemPolicies(), "the severity 'Severe' set in minSeverity is invalid. The supported severities are Low, Medium, High and Critical")
+}
+
+func TestExpandProjectsEcosystemPolicies(t *testing.T) {
+	params := Params{Scan: Scan{
+		EcosystemPolicies: map[string]SeverityPolicy{"npm": {FailSeverityThreshold: "Medium"}},
+		Projects:          []Project{{WorkingDirs: []string{"."}}},
+	}}
+	assert.NoError(t, params.expandProjects())
+	assert.Equal(t, params.EcosystemPolicies, params.Projects[0].EcosystemPolicies)
+}
+
+func TestApplyProfileEcosystemPolicies(t *testing.T) {
+	ecosystemPolicies := map[string]SeverityPolicy{"npm": {FailSeverityThreshold: "Medium"}, "go": {MinSeverity: "Low"}}
+	params := Params{
+		Scan:     Scan{EcosystemPolicies: ecosystemPolicies, Projects: []Project{{EcosystemPolicies: ecosystemPolicies}}},
+		Profiles: map[string]Profile{"strict": {SeverityPolicy: SeverityPolicy{FailSeverityThreshold: "Low"}}},
+	}
+	assert.NoError(t, params.applyProfile("strict"))
+	expected := map[string]SeverityPolicy{"npm": {FailSeverityThreshold: "Low"}, "go": {MinSeverity: "Low", FailSeverityThreshold: "Low"}}
+	assert.Equal(t, expected, params.EcosystemPolicies)
+	assert.Equal(t, expected, params.Projects[0].EcosystemPolicies)
+	// The config data is unchanged
+	assert.Equal(t, "Medium", ecosystemPolicies["npm"].FailSeverityThreshold)
+}
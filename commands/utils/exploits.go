@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+)
+
+// The badge, which annotates the CVEs with a known exploit in the comments
+const KnownExploitBadge = "🔥 KEV"
+
+// The terms of the JFrog research severity reasons, which indicate that the issue is known to be exploited, such as the CISA Known Exploited Vulnerabilities catalog.
+// CISA is matched only as a reference to its catalog, rather than in any reason which mentions it.
+var exploitedInTheWildRegex = regexp.MustCompile(`\bknown exploited\b|\bin the wild\b|\bcisa (?:kev|catalog)\b`)
+
+const publishedExploitTerms = `(?:published|publicly available|proof of concept|pocs?)`
+
+// The terms of the JFrog research severity reasons, which indicate that an exploit of the issue is published. The terms are matched as whole words,
+// in the same sentence as a reference to the exploit, so that a reason such as "a published advisory" or a word such as "epoch" isn't matched.
+var publishedExploitRegex = regexp.MustCompile(`\bpublic exploit|\b` + publishedExploitTerms + `\b[^.]*\bexploit|\bexploit[^.]*\b` + publishedExploitTerms + `\b`)
+
+// HasKnownExploit returns true if the JFrog research information of the issue indicates a known exploit, either published or exploited in the wild
+func HasKnownExploit(vulnerability formats.VulnerabilityOrViolationRow) bool {
+	if vulnerability.JfrogResearchInformation == nil {
+		return false
+	}
+	for _, reason := range vulnerability.JfrogResearchInformation.SeverityReasons {
+		// Positive reasons lower the severity, such as an issue which is hard to exploit
+		if reason.IsPositive {
+			continue
+		}
+		// The name and the description are separate sentences
+		text := strings.ToLower(reason.Name + ". " + reason.Description)
+		if exploitedInTheWildRegex.MatchString(text) || publishedExploitRegex.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterWithKnownExploits returns the issues with a known exploit
+func FilterWithKnownExploits(vulnerabilitiesRows []formats.VulnerabilityOrViolationRow) []formats.VulnerabilityOrViolationRow {
+	var filteredRows []formats.VulnerabilityOrViolationRow
+	for _, row := range vulnerabilitiesRows {
+		if HasKnownExploit(row) {
+			filteredRows = append(filteredRows, row)
+		}
+	}
+	return filteredRows
+}
+
+// Return the CVE of the issue to show in the results table, annotated with the known exploit badge if the issue has a known exploit
+func getCveIdCell(vulnerability formats.VulnerabilityOrViolationRow, badgeSeparator string) string {
+	var cveId string
+	if len(vulnerability.Cves) > 0 {
+		cveId = vulnerability.Cves[0].Id
+	}
+	if !HasKnownExploit(vulnerability) {
+		return cveId
+	}
+	if cveId == "" {
+		return KnownExploitBadge
+	}
+	return cveId + badgeSeparator + KnownExploitBadge
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+func createExploitTestRow(cveId string, reasons ...formats.JfrogResearchSeverityReason) formats.VulnerabilityOrViolationRow {
+	row := formats.VulnerabilityOrViolationRow{Severity: "High", Cves: []formats.CveRow{{Id: cveId}}}
+	if len(reasons) > 0 {
+		row.JfrogResearchInformation = &formats.JfrogResearchInformation{SeverityReasons: reasons}
+	}
+	return row
+}
+
+func TestHasKnownExploit(t *testing.T) {
+	testCases := []struct {
+		name     string
+		reason   formats.JfrogResearchSeverityReason
+		expected bool
+	}{
+		{name: "published exploit", reason: formats.JfrogResearchSeverityReason{Name: "The issue has an exploit published"}, expected: true},
+		{name: "public poc", reason: formats.JfrogResearchSeverityReason{Name: "Exploitation is simple", Description: "A public PoC demonstrates the exploit"}, expected: true},
+		{name: "kev", reason: formats.JfrogResearchSeverityReason{Name: "Listed in the CISA Known Exploited Vulnerabilities catalog"}, expected: true},
+		{name: "in the wild", reason: formats.JfrogResearchSeverityReason{Name: "The issue is exploited in the wild"}, expected: true},
+		{name: "positive reason", reason: formats.JfrogResearchSeverityReason{Name: "No public exploit is known", IsPositive: true}, expected: false},
+		{name: "unrelated reason", reason: formats.JfrogResearchSeverityReason{Name: "The issue can be exploited remotely"}, expected: false},
+		{name: "cisa kev", reason: formats.JfrogResearchSeverityReason{Name: "Listed in the CISA KEV"}, expected: true},
+		{name: "cisa advisory", reason: formats.JfrogResearchSeverityReason{Name: "Mentioned in a CISA advisory", Description: "The exploitation requires local access"}, expected: false},
+		{name: "poc in a word", reason: formats.JfrogResearchSeverityReason{Name: "Exploitation requires an epoch mismatch"}, expected: false},
+		{name: "published unrelated to the exploit", reason: formats.JfrogResearchSeverityReason{Name: "A fix was published", Description: "The exploitation requires local access"}, expected: false},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, HasKnownExploit(createExploitTestRow("CVE-2022-0001", test.reason)))
+		})
+	}
+	assert.False(t, HasKnownExploit(createExploitTestRow("CVE-2022-0001")))
+}
+
+func TestFilterWithKnownExploits(t *testing.T) {
+	exploited := createExploitTestRow("CVE-2022-0001", formats.JfrogResearchSeverityReason{Name: "The issue has an exploit published"})
+	rows := []formats.VulnerabilityOrViolationRow{createExploitTestRow("CVE-2022-0002"), exploited}
+	assert.Equal(t, []formats.VulnerabilityOrViolationRow{exploited}, FilterWithKnownExploits(rows))
+	assert.Empty(t, FilterWithKnownExploits(rows[:1]))
+}
+
+func TestGetCveIdCell(t *testing.T) {
+	reason := formats.JfrogResearchSeverityReason{Name: "The issue has an exploit published"}
+	assert.Equal(t, "CVE-2022-0001<br>"+KnownExploitBadge, getCveIdCell(createExploitTestRow("CVE-2022-0001", reason), "<br>"))
+	assert.Equal(t, KnownExploitBadge, getCveIdCell(createExploitTestRow("", reason), "<br>"))
+	assert.Equal(t, "CVE-2022-0001", getCveIdCell(createExploitTestRow("CVE-2022-0001"), "<br>"))
+
+	simplifiedOutput := &SimplifiedOutput{}
+	assert.Contains(t, simplifiedOutput.TableRow(createExploitTestRow("CVE-2022-0001", reason)), "| CVE-2022-0001 "+KnownExploitBadge+" |")
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jfrog/gofrog/version"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+)
+
+const defaultMaxFixedVersions = 3
+
+// Return the fix versions of the issue to show in the results table, separated by the given separator.
+// The minimal version which fixes the issue is shown first, and the fix versions beyond maxFixedVersions are summarized by a "+k more" suffix.
+// If maxFixedVersions is zero, the default of 3 versions is used, and if it's negative, all the fix versions are shown.
+func getFixedVersionsCell(vulnerability formats.VulnerabilityOrViolationRow, maxFixedVersions int, separator string) string {
+	fixedVersions := orderFixedVersions(vulnerability.ImpactedDependencyVersion, vulnerability.FixedVersions)
+	if maxFixedVersions == 0 {
+		maxFixedVersions = defaultMaxFixedVersions
+	}
+	if maxFixedVersions < 0 || len(fixedVersions) <= maxFixedVersions {
+		return strings.Join(fixedVersions, separator)
+	}
+	return strings.Join(fixedVersions[:maxFixedVersions], separator) + separator + fmt.Sprintf("+%d more", len(fixedVersions)-maxFixedVersions)
+}
+
+// Move the minimal fix version which is greater than the impacted version to the front, keeping the order of the rest of the fix versions
+func orderFixedVersions(impactedVersion string, fixedVersions []string) []string {
+	currentVersion := version.NewVersion(strings.TrimPrefix(impactedVersion, "v"))
+	minimalIndex := -1
+	var minimalVersion string
+	for index, fixedVersion := range fixedVersions {
+		candidate := getFixedVersionLowerBound(fixedVersion)
+		if candidate == "" || currentVersion.Compare(candidate) <= 0 {
+			continue
+		}
+		// Compare returns 1 if the candidate is greater than the version, and -1 if it's lower
+		if minimalIndex == -1 || version.NewVersion(minimalVersion).Compare(candidate) < 0 {
+			minimalIndex, minimalVersion = index, candidate
+		}
+	}
+	if minimalIndex <= 0 {
+		return fixedVersions
+	}
+	ordered := append([]string{fixedVersions[minimalIndex]}, fixedVersions[:minimalIndex]...)
+	return append(ordered, fixedVersions[minimalIndex+1:]...)
+}
+
+// Return the lower bound of a fix version, such as 1.2.3 for [1.2.3] or [1.2.3,2.0.0). An empty string is returned if the range has no inclusive lower bound.
+func getFixedVersionLowerBound(fixedVersion string) string {
+	lowerBound := strings.TrimSpace(strings.Split(fixedVersion, ",")[0])
+	if lowerBound == "" || strings.HasPrefix(lowerBound, "(") {
+		return ""
+	}
+	return strings.TrimPrefix(strings.Trim(lowerBound, "[]"), "v")
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetFixedVersionsCell(t *testing.T) {
+	vulnerability := formats.VulnerabilityOrViolationRow{
+		ImpactedDependencyVersion: "1.2.0",
+		FixedVersions:             []string{"[3.0.0]", "[1.0.5]", "[2.0.1]", "[1.2.4]", "[1.3.0]", "[4.0.0]"},
+	}
+	// The minimal fix version which is greater than the impacted version is shown first
+	assert.Equal(t, "[1.2.4]<br>[3.0.0]<br>[1.0.5]<br>+3 more", getFixedVersionsCell(vulnerability, 0, "<br>"))
+	assert.Equal(t, "[1.2.4] +5 more", getFixedVersionsCell(vulnerability, 1, " "))
+	assert.Equal(t, "[1.2.4] [3.0.0] [1.0.5] [2.0.1] [1.3.0] [4.0.0]", getFixedVersionsCell(vulnerability, -1, " "))
+
+	vulnerability.FixedVersions = []string{"[1.2.1]", "(,1.0.0]"}
+	assert.Equal(t, "[1.2.1]<br>(,1.0.0]", getFixedVersionsCell(vulnerability, 0, "<br>"))
+	vulnerability.FixedVersions = nil
+	assert.Empty(t, getFixedVersionsCell(vulnerability, 0, "<br>"))
+}
+
+func TestOrderFixedVersions(t *testing.T) {
+	assert.Equal(t, []string{"v0.3.8", "v0.4.0"}, orderFixedVersions("v0.3.7", []string{"v0.4.0", "v0.3.8"}))
+	assert.Equal(t, []string{"[1.9.0,2.0.0)", "[2.1.0]"}, orderFixedVersions("1.8.0", []string{"[2.1.0]", "[1.9.0,2.0.0)"}))
+	// None of the fix versions is greater than the impacted version
+	assert.Equal(t, []string{"[1.0.0]", "[0.9.0]"}, orderFixedVersions("2.0.0", []string{"[1.0.0]", "[0.9.0]"}))
+}
@@ -0,0 +1,259 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	defaultGitHubApiEndpoint = "https://api.github.com"
+	gitHubAppUsername        = "x-access-token"
+	// GitHub rejects the JWTs which expire more than 10 minutes after they are issued
+	gitHubAppJwtExpiry = 9 * time.Minute
+	// The installation tokens are refreshed this long before they expire, so that a token doesn't expire while it's used
+	gitHubAppTokenRefreshMargin = 5 * time.Minute
+)
+
+// jwtSigner signs the header and the payload of the JWT, which authenticates Frogbot as a GitHub App
+type jwtSigner interface {
+	Sign(signingInput []byte) ([]byte, error)
+}
+
+// Signs the JWT with the RS256 algorithm, using the private key of the GitHub App
+type rsaJwtSigner struct {
+	privateKey *rsa.PrivateKey
+}
+
+func (s *rsaJwtSigner) Sign(signingInput []byte) ([]byte, error) {
+	hash := sha256.Sum256(signingInput)
+	return rsa.SignPKCS1v15(rand.Reader, s.privateKey, crypto.SHA256, hash[:])
+}
+
+// GitHubAppAuth mints the short-lived installation access tokens of a GitHub App, which Frogbot uses instead of a personal access token.
+// A token is minted on the first use, and is refreshed when it's about to expire.
+// The clients hold copies of the token, such as the VCS client and the git clone and push authentication, so the tokens minted before are
+// replaced by the current token when the requests are sent, by refreshRequestToken.
+type GitHubAppAuth struct {
+	AppId          string
+	InstallationId string
+	apiEndpoint    string
+	signer         jwtSigner
+	httpClient     *http.Client
+	now            func() time.Time
+	mutex          sync.Mutex
+	token          string
+	expiresAt      time.Time
+	// All the tokens minted by the app, guarded by their own mutex, since the tokens are minted by requests which are checked by refreshRequestToken too
+	mintedTokens      map[string]bool
+	mintedTokensMutex sync.RWMutex
+}
+
+type gitHubInstallationToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NewGitHubAppAuth creates the authentication of the GitHub App, with its PEM encoded private key.
+// If apiEndpoint is empty, the tokens are minted by github.com.
+func NewGitHubAppAuth(appId, installationId, privateKeyPem, apiEndpoint string) (*GitHubAppAuth, error) {
+	if appId == "" || installationId == "" || privateKeyPem == "" {
+		return nil, fmt.Errorf(errIncompleteGitHubApp, GitHubAppIdEnv, GitHubAppInstallationIdEnv, GitHubAppPrivateKeyEnv)
+	}
+	privateKey, err := parseGitHubAppPrivateKey(privateKeyPem)
+	if err != nil {
+		return nil, err
+	}
+	return newGitHubAppAuth(appId, installationId, apiEndpoint, &rsaJwtSigner{privateKey: privateKey}), nil
+}
+
+func newGitHubAppAuth(appId, installationId, apiEndpoint string, signer jwtSigner) *GitHubAppAuth {
+	if apiEndpoint == "" {
+		apiEndpoint = defaultGitHubApiEndpoint
+	}
+	return &GitHubAppAuth{
+		AppId:          appId,
+		InstallationId: installationId,
+		apiEndpoint:    strings.TrimSuffix(apiEndpoint, "/"),
+		signer:         signer,
+		httpClient:     http.DefaultClient,
+		now:            time.Now,
+		mintedTokens:   make(map[string]bool),
+	}
+}
+
+// Parse the private key of the GitHub App, which GitHub generates in the PKCS #1 format. Keys converted to the PKCS #8 format are supported too.
+func parseGitHubAppPrivateKey(privateKeyPem string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(privateKeyPem))
+	if block == nil {
+		return nil, errors.New("the private key of the GitHub App isn't a PEM encoded key")
+	}
+	if privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return privateKey, nil
+	}
+	parsedKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the private key of the GitHub App: %s", err.Error())
+	}
+	privateKey, ok := parsedKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("the private key of the GitHub App isn't an RSA key")
+	}
+	return privateKey, nil
+}
+
+// Token returns an installation access token of the GitHub App. The token is minted again if it expires within the refresh margin.
+func (ga *GitHubAppAuth) Token() (string, error) {
+	ga.mutex.Lock()
+	defer ga.mutex.Unlock()
+	if !ga.isTokenExpiring() {
+		return ga.token, nil
+	}
+	installationToken, err := ga.mintInstallationToken()
+	if err != nil {
+		return "", err
+	}
+	ga.token, ga.expiresAt = installationToken.Token, installationToken.ExpiresAt
+	ga.mintedTokensMutex.Lock()
+	ga.mintedTokens[ga.token] = true
+	ga.mintedTokensMutex.Unlock()
+	log.Debug(fmt.Sprintf("Minted an installation access token of the GitHub App %s, which expires at %s", ga.AppId, ga.expiresAt.Format(time.RFC3339)))
+	return ga.token, nil
+}
+
+func (ga *GitHubAppAuth) isTokenExpiring() bool {
+	return ga.token == "" || !ga.now().Add(gitHubAppTokenRefreshMargin).Before(ga.expiresAt)
+}
+
+func (ga *GitHubAppAuth) isMintedToken(token string) bool {
+	ga.mintedTokensMutex.RLock()
+	defer ga.mintedTokensMutex.RUnlock()
+	return ga.mintedTokens[token]
+}
+
+// refreshRequestToken replaces a token minted by the app, which is set in the Authorization header of the request, by the current token, which is minted again if it's about to expire.
+// The token is sent as a bearer token by the GitHub API clients, and as the password of the basic authentication by git.
+// The requests without a token of the app, including the requests which mint the tokens, are unchanged.
+func (ga *GitHubAppAuth) refreshRequestToken(req *http.Request) error {
+	username, requestToken, isBasicAuth := req.BasicAuth()
+	if !isBasicAuth {
+		scheme, bearerToken, found := strings.Cut(req.Header.Get("Authorization"), " ")
+		if !found || (!strings.EqualFold(scheme, "Bearer") && !strings.EqualFold(scheme, "token")) {
+			return nil
+		}
+		requestToken = bearerToken
+	}
+	if !ga.isMintedToken(requestToken) {
+		return nil
+	}
+	token, err := ga.Token()
+	if err != nil || token == requestToken {
+		return err
+	}
+	if isBasicAuth {
+		req.SetBasicAuth(username, token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return nil
+}
+
+func (ga *GitHubAppAuth) mintInstallationToken() (*gitHubInstallationToken, error) {
+	jwt, err := ga.createJwt()
+	if err != nil {
+		return nil, err
+	}
+	tokenUrl := fmt.Sprintf("%s/app/installations/%s/access_tokens", ga.apiEndpoint, ga.InstallationId)
+	request, err := http.NewRequest(http.MethodPost, tokenUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Authorization", "Bearer "+jwt)
+	request.Header.Set("Accept", "application/vnd.github+json")
+	response, err := ga.httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mint an installation access token of the GitHub App: %s", err.Error())
+	}
+	defer func() {
+		if e := response.Body.Close(); e != nil {
+			log.Warn(e)
+		}
+	}()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("failed to mint an installation access token of the GitHub App %s for the installation %s. GitHub responded with %d: %s",
+			ga.AppId, ga.InstallationId, response.StatusCode, strings.TrimSpace(string(body)))
+	}
+	installationToken := &gitHubInstallationToken{}
+	if err = json.Unmarshal(body, installationToken); err != nil {
+		return nil, err
+	}
+	if installationToken.Token == "" {
+		return nil, errors.New("GitHub responded with an empty installation access token")
+	}
+	return installationToken, nil
+}
+
+// Create the JWT which authenticates as the GitHub App. It's issued a minute in the past, to allow for clock drift.
+func (ga *GitHubAppAuth) createJwt() (string, error) {
+	now := ga.now()
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(gitHubAppJwtExpiry).Unix(),
+		"iss": ga.AppId,
+	})
+	if err != nil {
+		return "", err
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature, err := ga.signer.Sign([]byte(signingInput))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign the JWT of the GitHub App: %s", err.Error())
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Read the GitHub App environment variables, and mint the first installation access token of the app.
+// The requests sent using http.DefaultTransport have their tokens of the app refreshed, so that the copies of the token don't expire.
+// If the app ID isn't set, the git params are unchanged.
+func (g *Git) setGitHubAppToken() (err error) {
+	appId := getTrimmedEnv(GitHubAppIdEnv)
+	if appId == "" {
+		return nil
+	}
+	privateKey, err := getSecretEnv(GitHubAppPrivateKeyEnv)
+	if err != nil {
+		return err
+	}
+	// CI secrets may hold the private key with escaped line breaks
+	if !strings.Contains(privateKey, "\n") {
+		privateKey = strings.ReplaceAll(privateKey, `\n`, "\n")
+	}
+	if g.GitHubApp, err = NewGitHubAppAuth(appId, getTrimmedEnv(GitHubAppInstallationIdEnv), privateKey, g.ApiEndpoint); err != nil {
+		return err
+	}
+	configureGitHubAppTransport(g.GitHubApp)
+	g.Token, err = g.GitHubApp.Token()
+	return err
+}
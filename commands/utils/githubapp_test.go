@@ -0,0 +1,184 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeJwtSigner struct{}
+
+func (s *fakeJwtSigner) Sign(signingInput []byte) ([]byte, error) {
+	return []byte("signature-of-" + string(signingInput)), nil
+}
+
+// Create a GitHub API server, which mints a new installation token on each request, valid for an hour from the given time
+func createGitHubAppServer(t *testing.T, now *time.Time, mintedTokens *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/app/installations/5678/access_tokens", r.URL.Path)
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "Bearer "))
+		*mintedTokens++
+		w.WriteHeader(http.StatusCreated)
+		_, err := fmt.Fprintf(w, `{"token": "ghs_token%d", "expires_at": "%s"}`, *mintedTokens, now.Add(time.Hour).Format(time.RFC3339))
+		assert.NoError(t, err)
+	}))
+}
+
+func TestGitHubAppAuthToken(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	mintedTokens := 0
+	server := createGitHubAppServer(t, &now, &mintedTokens)
+	defer server.Close()
+	appAuth := newGitHubAppAuth("1234", "5678", server.URL, &fakeJwtSigner{})
+	appAuth.now = func() time.Time { return now }
+
+	token, err := appAuth.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, "ghs_token1", token)
+
+	// The token is reused until it's about to expire
+	now = now.Add(50 * time.Minute)
+	token, err = appAuth.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, "ghs_token1", token)
+
+	now = now.Add(6 * time.Minute)
+	token, err = appAuth.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, "ghs_token2", token)
+	assert.Equal(t, 2, mintedTokens)
+}
+
+func TestGitHubAppAuthRefreshRequestToken(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	mintedTokens := 0
+	server := createGitHubAppServer(t, &now, &mintedTokens)
+	defer server.Close()
+	appAuth := newGitHubAppAuth("1234", "5678", server.URL, &fakeJwtSigner{})
+	appAuth.now = func() time.Time { return now }
+	token, err := appAuth.Token()
+	assert.NoError(t, err)
+
+	// The clients hold a copy of the first token, which expires
+	now = now.Add(time.Hour)
+	bearerRequest := httptest.NewRequest(http.MethodGet, "https://api.github.com/repos/jfrog/frogbot", nil)
+	bearerRequest.Header.Set("Authorization", "Bearer "+token)
+	assert.NoError(t, appAuth.refreshRequestToken(bearerRequest))
+	assert.Equal(t, "Bearer ghs_token2", bearerRequest.Header.Get("Authorization"))
+
+	basicAuthRequest := httptest.NewRequest(http.MethodPost, "https://github.com/jfrog/frogbot.git/git-receive-pack", nil)
+	basicAuthRequest.SetBasicAuth(gitHubAppUsername, token)
+	assert.NoError(t, appAuth.refreshRequestToken(basicAuthRequest))
+	username, password, _ := basicAuthRequest.BasicAuth()
+	assert.Equal(t, gitHubAppUsername, username)
+	assert.Equal(t, "ghs_token2", password)
+	assert.Equal(t, 2, mintedTokens)
+
+	// A token which wasn't minted by the app, such as the JWT of the app, is unchanged
+	otherRequest := httptest.NewRequest(http.MethodGet, "https://api.github.com/repos/jfrog/frogbot", nil)
+	otherRequest.Header.Set("Authorization", "Bearer other-token")
+	assert.NoError(t, appAuth.refreshRequestToken(otherRequest))
+	assert.Equal(t, "Bearer other-token", otherRequest.Header.Get("Authorization"))
+}
+
+func TestGitHubAppAuthJwt(t *testing.T) {
+	appAuth := newGitHubAppAuth("1234", "5678", "", &fakeJwtSigner{})
+	appAuth.now = func() time.Time { return time.Unix(1700000000, 0) }
+	jwt, err := appAuth.createJwt()
+	assert.NoError(t, err)
+	parts := strings.Split(jwt, ".")
+	if !assert.Len(t, parts, 3) {
+		return
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	assert.NoError(t, err)
+	claims := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(payload, &claims))
+	assert.Equal(t, map[string]interface{}{"iat": float64(1699999940), "exp": float64(1700000540), "iss": "1234"}, claims)
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	assert.NoError(t, err)
+	assert.Equal(t, "signature-of-"+parts[0]+"."+parts[1], string(signature))
+	assert.Equal(t, defaultGitHubApiEndpoint, appAuth.apiEndpoint)
+}
+
+func TestGitHubAppAuthMintError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, err := w.Write([]byte(`{"message": "A JSON web token could not be decoded"}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+	_, err := newGitHubAppAuth("1234", "5678", server.URL, &fakeJwtSigner{}).Token()
+	assert.ErrorContains(t, err, "GitHub responded with 401")
+}
+
+func TestParseGitHubAppPrivateKey(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	pkcs1Pem := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)}))
+	parsedKey, err := parseGitHubAppPrivateKey(pkcs1Pem)
+	assert.NoError(t, err)
+	assert.True(t, privateKey.Equal(parsedKey))
+
+	pkcs8Key, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	assert.NoError(t, err)
+	parsedKey, err = parseGitHubAppPrivateKey(string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Key})))
+	assert.NoError(t, err)
+	assert.True(t, privateKey.Equal(parsedKey))
+
+	_, err = parseGitHubAppPrivateKey("not a key")
+	assert.Error(t, err)
+}
+
+func TestExtractGitParamsFromEnvGitHubApp(t *testing.T) {
+	defer restoreCustomHeaders()()
+	now := time.Now()
+	mintedTokens := 0
+	server := createGitHubAppServer(t, &now, &mintedTokens)
+	defer server.Close()
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	privateKeyPem := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)}))
+
+	t.Setenv(GitProvider, string(GitHub))
+	t.Setenv(GitRepoOwnerEnv, "jfrog")
+	t.Setenv(GitApiEndpointEnv, server.URL)
+	t.Setenv(GitTokenEnv, "")
+	t.Setenv(GitHubAppIdEnv, "1234")
+	t.Setenv(GitHubAppInstallationIdEnv, "5678")
+	// The line breaks of the private key may be escaped
+	t.Setenv(GitHubAppPrivateKeyEnv, strings.ReplaceAll(privateKeyPem, "\n", `\n`))
+	gitParams, err := extractGitParamsFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, vcsutils.GitHub, gitParams.GitProvider)
+	assert.Equal(t, "ghs_token1", gitParams.Token)
+	assert.Equal(t, "x-access-token", gitParams.Username)
+	assert.NotNil(t, gitParams.GitHubApp)
+	// The tokens of the app are refreshed by the default transport
+	headersTransport, ok := http.DefaultTransport.(*customHeadersTransport)
+	if assert.True(t, ok) {
+		assert.Equal(t, gitParams.GitHubApp, headersTransport.gitHubApp)
+	}
+
+	// The token of the GitHub App is used by the VCS client
+	_, err = NewVcsClient(&gitParams)
+	assert.NoError(t, err)
+	assert.Equal(t, "ghs_token1", gitParams.Token)
+
+	t.Setenv(GitHubAppInstallationIdEnv, "")
+	_, err = extractGitParamsFromEnv()
+	assert.EqualError(t, err, fmt.Sprintf(errIncompleteGitHubApp, GitHubAppIdEnv, GitHubAppInstallationIdEnv, GitHubAppPrivateKeyEnv))
+}
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Escapes the characters of the IaC findings which Markdown treats as formatting, so that a finding is shown as is in its table cell.
+// The line breaks are replaced with spaces, since a table row can't span lines.
+var markdownCellEscaper = strings.NewReplacer(
+	"\\", "\\\\", "|", "\\|", "`", "\\`", "*", "\\*", "_", "\\_", "[", "\\[", "]", "\\]", "<", "&lt;", ">", "&gt;",
+	"\r\n", " ", "\n", " ", "\r", " ")
+
+// IacRow is a misconfiguration found by the Infrastructure as Code scan of Helm charts and Kubernetes manifests, such as a container running as root
+type IacRow struct {
+	Severity string `json:"severity"`
+	// The path of the file, relative to the scanned working directory
+	File string `json:"file"`
+	// The line of the misconfiguration in the file, or 0 if the line is unknown
+	Line    int    `json:"line"`
+	Finding string `json:"finding"`
+	RuleId  string `json:"ruleId"`
+	// The full path of the file, against which the pathIgnores patterns are matched
+	FullPath string `json:"-"`
+}
+
+// FilterIacBySeverity returns the misconfigurations with a severity of MinSeverity and above.
+func (sp *SeverityPolicy) FilterIacBySeverity(iacRows []IacRow) []IacRow {
+	if sp.MinSeverity == "" {
+		return iacRows
+	}
+	var filteredRows []IacRow
+	for _, row := range iacRows {
+		if sp.isShown(row.Severity) {
+			filteredRows = append(filteredRows, row)
+		}
+	}
+	return filteredRows
+}
+
+// HasFailingIacIssues returns true if one of the misconfigurations has a severity of FailSeverityThreshold and above
+func (sp *SeverityPolicy) HasFailingIacIssues(iacRows []IacRow) bool {
+	for _, row := range iacRows {
+		if sp.isFailing(row.Severity) {
+			return true
+		}
+	}
+	return false
+}
+
+// Return the location of the misconfiguration, such as "chart/templates/deployment.yaml:12"
+func (row *IacRow) location() string {
+	if row.Line == 0 {
+		return row.File
+	}
+	return row.File + ":" + strconv.Itoa(row.Line)
+}
+
+// Return the finding of the misconfiguration, escaped for the table cell of the comment
+func (row *IacRow) markdownFinding() string {
+	return markdownCellEscaper.Replace(row.Finding)
+}
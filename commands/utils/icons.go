@@ -19,6 +19,25 @@ func GetSeverityTag(iconName IconName) string {
 	return ""
 }
 
+// GetSeverityBadge returns an emoji representing the severity, suitable for plain text such as pull request titles.
+// If a color is configured for the severity in severityColors, the emoji with the closest color is returned.
+func GetSeverityBadge(severity string, severityColors map[string]string) string {
+	if isCustomSeverityColor(severity, severityColors) {
+		return getClosestColoredBadge(GetSeverityColor(severity, severityColors))
+	}
+	switch strings.ToLower(severity) {
+	case "critical":
+		return criticalSeverityBadge
+	case "high":
+		return highSeverityBadge
+	case "medium":
+		return mediumSeverityBadge
+	case "low":
+		return lowSeverityBadge
+	}
+	return ""
+}
+
 func GetBanner(banner ImageSource) string {
 	return "[" + GetIconTag(banner) + "](https://github.com/jfrog/frogbot#readme)"
 }
@@ -14,6 +14,14 @@ func TestGetSeverityTag(t *testing.T) {
 	assert.Equal(t, "", GetSeverityTag("none"))
 }
 
+func TestGetSeverityBadge(t *testing.T) {
+	assert.Equal(t, "🔴", GetSeverityBadge("Critical", nil))
+	assert.Equal(t, "🟠", GetSeverityBadge("HiGh", nil))
+	assert.Equal(t, "🟡", GetSeverityBadge("meDium", nil))
+	assert.Equal(t, "🟢", GetSeverityBadge("low", nil))
+	assert.Equal(t, "", GetSeverityBadge("none", nil))
+}
+
 func TestGetVulnerabilitiesBanners(t *testing.T) {
 	assert.Equal(t, "[![](https://raw.githubusercontent.com/jfrog/frogbot/master/resources/noVulnerabilityBanner.png)](https://github.com/jfrog/frogbot#readme)", GetBanner(NoVulnerabilityBannerSource))
 	assert.Equal(t, "[![](https://raw.githubusercontent.com/jfrog/frogbot/master/resources/vulnerabilitiesBanner.png)](https://github.com/jfrog/frogbot#readme)", GetBanner(VulnerabilitiesBannerSource))
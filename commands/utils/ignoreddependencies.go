@@ -0,0 +1,217 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jfrog/gofrog/version"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+)
+
+var (
+	// Matches a single semver constraint, such as ">=1.2.0", "~1.2.3", "^0.7" or "1.x"
+	versionConstraintRegex = regexp.MustCompile(`^(>=|<=|!=|>|<|=|~|\^)?v?([0-9A-Za-z*.+-]+)$`)
+	versionOperatorRegex   = regexp.MustCompile(`^(>=|<=|!=|>|<|=|~|\^)$`)
+)
+
+// IgnoredDependency is a dependency whose issues are all suppressed, optionally only in the versions which match a semver range,
+// such as "lodash", "lodash <4.17.21" or "ua-parser-js >=0.7.29 <0.8.0 || 1.0.x"
+type IgnoredDependency struct {
+	// The ignoredDependencies entry
+	Entry string
+	Name  string
+	// Alternative sets of constraints, separated by "||" in the entry. The version matches if it satisfies all the constraints of one of the sets.
+	// If empty, all the versions of the dependency are ignored.
+	constraintsSets [][]versionConstraint
+}
+
+type versionConstraint struct {
+	// One of =, !=, >, >=, < and <=
+	operator string
+	version  string
+}
+
+// SuppressedIssue is an issue omitted from the scan results by an ignoredDependencies entry or by an inline ignore. It's kept in the JSON results for auditability.
+type SuppressedIssue struct {
+	formats.VulnerabilityOrViolationRow
+	IgnoredDependency string        `json:"ignoredDependency"`
+	InlineIgnore      *InlineIgnore `json:"inlineIgnore,omitempty"`
+}
+
+func ParseIgnoredDependency(entry string) (*IgnoredDependency, error) {
+	fields := strings.Fields(entry)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf(errInvalidIgnoredDependency, entry)
+	}
+	ignoredDependency := &IgnoredDependency{Entry: entry, Name: fields[0]}
+	if len(fields) == 1 {
+		return ignoredDependency, nil
+	}
+	versionRange, err := parseVersionRange(strings.Join(fields[1:], " "))
+	if err != nil {
+		return nil, fmt.Errorf(errInvalidIgnoredDependency, entry)
+	}
+	ignoredDependency.constraintsSets = versionRange
+	return ignoredDependency, nil
+}
+
+// Parse the constraints separated by spaces or commas, such as ">= 1.2.0, <2". The operator may be separated from its version by spaces.
+func parseVersionConstraints(versionRange string) (constraints []versionConstraint, err error) {
+	tokens := strings.Fields(strings.ReplaceAll(versionRange, ",", " "))
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty version range")
+	}
+	for index := 0; index < len(tokens); index++ {
+		token := tokens[index]
+		if versionOperatorRegex.MatchString(token) && index+1 < len(tokens) {
+			index++
+			token += tokens[index]
+		}
+		parsed, err := parseVersionConstraint(token)
+		if err != nil {
+			return nil, err
+		}
+		constraints = append(constraints, parsed...)
+	}
+	return constraints, nil
+}
+
+// Parse a single constraint. The tilde, caret and wildcard constraints are converted to their lower and upper bounds.
+func parseVersionConstraint(constraint string) ([]versionConstraint, error) {
+	match := versionConstraintRegex.FindStringSubmatch(constraint)
+	if match == nil {
+		return nil, fmt.Errorf("invalid version constraint '%s'", constraint)
+	}
+	operator, constraintVersion := match[1], match[2]
+	parts, wildcard, err := parseVersionParts(constraintVersion)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case wildcard:
+		if operator != "" && operator != "=" {
+			return nil, fmt.Errorf("invalid version constraint '%s'", constraint)
+		}
+		if len(parts) == 0 {
+			// Matches all the versions
+			return []versionConstraint{}, nil
+		}
+		return versionBounds(parts, len(parts)-1), nil
+	case operator == "~":
+		// ~1.2.3 and ~1.2 allow patch-level changes, and ~1 allows minor-level changes
+		if len(parts) == 1 {
+			return versionBounds(parts, 0), nil
+		}
+		return versionBounds(parts, 1), nil
+	case operator == "^":
+		// ^1.2.3 allows changes which don't modify the left-most non-zero part
+		bumpIndex := 0
+		for bumpIndex < len(parts)-1 && parts[bumpIndex] == 0 {
+			bumpIndex++
+		}
+		return versionBounds(parts, bumpIndex), nil
+	case operator == "":
+		operator = "="
+	}
+	return []versionConstraint{{operator: operator, version: constraintVersion}}, nil
+}
+
+// Return the numeric parts of the version up to its first wildcard part, and whether it has a wildcard, such as 1.2 and true for 1.2.x
+func parseVersionParts(constraintVersion string) (parts []int, wildcard bool, err error) {
+	// Ignore the pre-release and the build metadata of the bounds
+	numericVersion := strings.SplitN(strings.SplitN(constraintVersion, "-", 2)[0], "+", 2)[0]
+	for _, part := range strings.Split(numericVersion, ".") {
+		if part == "x" || part == "X" || part == "*" {
+			return parts, true, nil
+		}
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid version '%s'", constraintVersion)
+		}
+		parts = append(parts, number)
+	}
+	return parts, false, nil
+}
+
+// Return the constraints of the versions from the given version, up to the version in which the part at bumpIndex is incremented
+func versionBounds(parts []int, bumpIndex int) []versionConstraint {
+	upperParts := append([]int{}, parts[:bumpIndex+1]...)
+	upperParts[bumpIndex]++
+	return []versionConstraint{
+		{operator: ">=", version: joinVersionParts(parts)},
+		{operator: "<", version: joinVersionParts(upperParts)},
+	}
+}
+
+func joinVersionParts(parts []int) string {
+	var stringParts []string
+	for _, part := range parts {
+		stringParts = append(stringParts, strconv.Itoa(part))
+	}
+	return strings.Join(stringParts, ".")
+}
+
+func (vc *versionConstraint) isSatisfiedBy(dependencyVersion string) bool {
+	// Compare returns 1 if the constraint version is greater than the dependency version
+	comparison := -version.NewVersion(dependencyVersion).Compare(vc.version)
+	switch vc.operator {
+	case ">":
+		return comparison > 0
+	case ">=":
+		return comparison >= 0
+	case "<":
+		return comparison < 0
+	case "<=":
+		return comparison <= 0
+	case "!=":
+		return comparison != 0
+	default:
+		return comparison == 0
+	}
+}
+
+func (id *IgnoredDependency) matches(row *formats.VulnerabilityOrViolationRow) bool {
+	if !strings.EqualFold(id.Name, row.ImpactedDependencyName) {
+		return false
+	}
+	if len(id.constraintsSets) == 0 {
+		return true
+	}
+	return versionRange(id.constraintsSets).contains(row.ImpactedDependencyVersion)
+}
+
+func validateIgnoredDependencies(entries []string) error {
+	for _, entry := range entries {
+		if _, err := ParseIgnoredDependency(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FilterIgnoredDependencies removes the issues of the dependencies ignored by the project, and returns them separately, along with the entries which ignored them
+func (p *Project) FilterIgnoredDependencies(vulnerabilitiesRows []formats.VulnerabilityOrViolationRow) (filteredRows []formats.VulnerabilityOrViolationRow, suppressedIssues []SuppressedIssue) {
+	var ignoredDependencies []IgnoredDependency
+	// Invalid entries are rejected when the config is loaded, and are skipped here
+	for _, entry := range p.IgnoredDependencies {
+		if ignoredDependency, err := ParseIgnoredDependency(entry); err == nil {
+			ignoredDependencies = append(ignoredDependencies, *ignoredDependency)
+		}
+	}
+	if len(ignoredDependencies) == 0 {
+		return vulnerabilitiesRows, nil
+	}
+rowsLoop:
+	for i := range vulnerabilitiesRows {
+		for j := range ignoredDependencies {
+			if ignoredDependencies[j].matches(&vulnerabilitiesRows[i]) {
+				suppressedIssues = append(suppressedIssues, SuppressedIssue{VulnerabilityOrViolationRow: vulnerabilitiesRows[i], IgnoredDependency: ignoredDependencies[j].Entry})
+				continue rowsLoop
+			}
+		}
+		filteredRows = append(filteredRows, vulnerabilitiesRows[i])
+	}
+	return
+}
@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIgnoredDependency(t *testing.T) {
+	ignoredDependency, err := ParseIgnoredDependency("@babel/core")
+	assert.NoError(t, err)
+	assert.Equal(t, IgnoredDependency{Entry: "@babel/core", Name: "@babel/core"}, *ignoredDependency)
+
+	ignoredDependency, err = ParseIgnoredDependency("lodash >= 4.0.0, <4.17.21 || 5.x")
+	assert.NoError(t, err)
+	assert.Equal(t, "lodash", ignoredDependency.Name)
+	assert.Equal(t, [][]versionConstraint{
+		{{operator: ">=", version: "4.0.0"}, {operator: "<", version: "4.17.21"}},
+		{{operator: ">=", version: "5"}, {operator: "<", version: "6"}},
+	}, ignoredDependency.constraintsSets)
+
+	for _, entry := range []string{"", "lodash >=", "lodash <4.17.21 ||", "lodash >1.x", "lodash 1.a.0", "lodash =>1.0.0"} {
+		_, err = ParseIgnoredDependency(entry)
+		assert.Error(t, err, entry)
+	}
+}
+
+func TestIgnoredDependencyMatches(t *testing.T) {
+	testCases := []struct {
+		entry    string
+		version  string
+		expected bool
+	}{
+		{entry: "lodash", version: "4.17.15", expected: true},
+		{entry: "lodash <4.17.21", version: "4.17.15", expected: true},
+		{entry: "lodash <4.17.21", version: "4.17.21", expected: false},
+		{entry: "lodash >=4.0.0 <4.17.21", version: "3.10.1", expected: false},
+		{entry: "lodash 4.17.15", version: "4.17.15", expected: true},
+		{entry: "lodash !=4.17.15", version: "4.17.15", expected: false},
+		{entry: "lodash ~4.17.0", version: "4.17.20", expected: true},
+		{entry: "lodash ~4.17.0", version: "4.18.0", expected: false},
+		{entry: "lodash ^4.1.0", version: "4.17.20", expected: true},
+		{entry: "lodash ^4.1.0", version: "5.0.0", expected: false},
+		{entry: "lodash ^0.7.2", version: "0.7.29", expected: true},
+		{entry: "lodash ^0.7.2", version: "0.8.0", expected: false},
+		{entry: "lodash 4.x", version: "4.17.20", expected: true},
+		{entry: "lodash *", version: "1.0.0", expected: true},
+		{entry: "lodash <3 || >=4.17.0 <4.17.21", version: "4.17.15", expected: true},
+		{entry: "lodash <3 || >=4.17.0 <4.17.21", version: "3.10.1", expected: false},
+		// Go versions are prefixed by v
+		{entry: "lodash <v4.17.21", version: "v4.17.15", expected: true},
+		// The name must match
+		{entry: "lodash.merge", version: "4.17.15", expected: false},
+	}
+	for _, testCase := range testCases {
+		ignoredDependency, err := ParseIgnoredDependency(testCase.entry)
+		if assert.NoError(t, err, testCase.entry) {
+			row := &formats.VulnerabilityOrViolationRow{ImpactedDependencyName: "lodash", ImpactedDependencyVersion: testCase.version}
+			assert.Equal(t, testCase.expected, ignoredDependency.matches(row), testCase.entry+" with "+testCase.version)
+		}
+	}
+}
+
+func TestFilterIgnoredDependencies(t *testing.T) {
+	rows := []formats.VulnerabilityOrViolationRow{
+		{ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "4.17.15", IssueId: "XRAY-1"},
+		{ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "0.0.8", IssueId: "XRAY-2"},
+		{ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "1.2.5", IssueId: "XRAY-3"},
+	}
+	project := Project{IgnoredDependencies: []string{"lodash", "minimist <1.0.0"}}
+	filteredRows, suppressedIssues := project.FilterIgnoredDependencies(rows)
+	assert.Equal(t, []formats.VulnerabilityOrViolationRow{rows[2]}, filteredRows)
+	assert.Equal(t, []SuppressedIssue{
+		{VulnerabilityOrViolationRow: rows[0], IgnoredDependency: "lodash"},
+		{VulnerabilityOrViolationRow: rows[1], IgnoredDependency: "minimist <1.0.0"},
+	}, suppressedIssues)
+
+	filteredRows, suppressedIssues = (&Project{}).FilterIgnoredDependencies(rows)
+	assert.Equal(t, rows, filteredRows)
+	assert.Nil(t, suppressedIssues)
+}
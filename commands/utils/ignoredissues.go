@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+)
+
+const (
+	ignoredIssueDateLayout         = "2006-01-02"
+	ignoredIssueUntilKeyword       = "until"
+	defaultIgnoreExpiryWarningDays = 14
+)
+
+// IgnoredIssue is an issue excluded from the scan results, such as "CVE-2022-24450" or "CVE-2022-24450 until 2024-06-01"
+type IgnoredIssue struct {
+	// A CVE ID or an Xray issue ID
+	Id string
+	// The last day the issue is ignored. If zero, the issue is ignored without an expiry date.
+	Until time.Time
+}
+
+func ParseIgnoredIssue(entry string) (*IgnoredIssue, error) {
+	fields := strings.Fields(entry)
+	switch {
+	case len(fields) == 1:
+		return &IgnoredIssue{Id: fields[0]}, nil
+	case len(fields) == 3 && strings.EqualFold(fields[1], ignoredIssueUntilKeyword):
+		until, err := time.Parse(ignoredIssueDateLayout, fields[2])
+		if err != nil {
+			return nil, fmt.Errorf(errInvalidIgnoredIssue, entry)
+		}
+		return &IgnoredIssue{Id: fields[0], Until: until}, nil
+	default:
+		return nil, fmt.Errorf(errInvalidIgnoredIssue, entry)
+	}
+}
+
+// The issue is ignored through the end of its expiry date
+func (ii *IgnoredIssue) isActive(now time.Time) bool {
+	return ii.Until.IsZero() || now.Before(ii.Until.AddDate(0, 0, 1))
+}
+
+func (ii *IgnoredIssue) expiresWithin(now time.Time, days int) bool {
+	return !ii.Until.IsZero() && ii.isActive(now) && now.AddDate(0, 0, days).After(ii.Until)
+}
+
+func (ii *IgnoredIssue) matches(row *formats.VulnerabilityOrViolationRow) bool {
+	if strings.EqualFold(ii.Id, row.IssueId) {
+		return true
+	}
+	for _, cve := range row.Cves {
+		if strings.EqualFold(ii.Id, cve.Id) {
+			return true
+		}
+	}
+	return false
+}
+
+func validateIgnoredIssues(entries []string) error {
+	for _, entry := range entries {
+		if _, err := ParseIgnoredIssue(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Parse the ignored issues of the project. Invalid entries are rejected when the config is loaded, and are skipped here.
+func (p *Project) getIgnoredIssues() (ignoredIssues []IgnoredIssue) {
+	for _, entry := range p.IgnoredIssues {
+		if ignoredIssue, err := ParseIgnoredIssue(entry); err == nil {
+			ignoredIssues = append(ignoredIssues, *ignoredIssue)
+		}
+	}
+	return
+}
+
+// FilterIgnoredIssues removes the issues ignored by the project. Issues whose ignore entry has expired are kept.
+func (p *Project) FilterIgnoredIssues(vulnerabilitiesRows []formats.VulnerabilityOrViolationRow, now time.Time) []formats.VulnerabilityOrViolationRow {
+	ignoredIssues := p.getIgnoredIssues()
+	if len(ignoredIssues) == 0 {
+		return vulnerabilitiesRows
+	}
+	var filteredRows []formats.VulnerabilityOrViolationRow
+	for i := range vulnerabilitiesRows {
+		if !isIgnored(&vulnerabilitiesRows[i], ignoredIssues, now) {
+			filteredRows = append(filteredRows, vulnerabilitiesRows[i])
+		}
+	}
+	return filteredRows
+}
+
+func isIgnored(row *formats.VulnerabilityOrViolationRow, ignoredIssues []IgnoredIssue, now time.Time) bool {
+	for i := range ignoredIssues {
+		if ignoredIssues[i].isActive(now) && ignoredIssues[i].matches(row) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetExpiringIgnoredIssues returns the ignored issues of the project, which expire within the given number of days.
+// If days is zero, the default warning window of 14 days is used.
+func (p *Project) GetExpiringIgnoredIssues(now time.Time, days int) (expiringIssues []IgnoredIssue) {
+	if days == 0 {
+		days = defaultIgnoreExpiryWarningDays
+	}
+	for _, ignoredIssue := range p.getIgnoredIssues() {
+		if ignoredIssue.expiresWithin(now, days) {
+			expiringIssues = append(expiringIssues, ignoredIssue)
+		}
+	}
+	return
+}
+
+// GetIgnoredIssuesExpiryNote lists the ignored issues, which will be reported again after their expiry date
+func GetIgnoredIssuesExpiryNote(expiringIssues []IgnoredIssue) string {
+	if len(expiringIssues) == 0 {
+		return ""
+	}
+	sort.Slice(expiringIssues, func(i, j int) bool {
+		return expiringIssues[i].Until.Before(expiringIssues[j].Until)
+	})
+	var note strings.Builder
+	for _, ignoredIssue := range expiringIssues {
+		note.WriteString(fmt.Sprintf("- **%s** is ignored until %s\n", ignoredIssue.Id, ignoredIssue.Until.Format(ignoredIssueDateLayout)))
+	}
+	return "\n\n" + ignoredIssuesExpiryTitle + "\n\n" + note.String()
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+var ignoredIssuesTestNow = time.Date(2024, 5, 20, 12, 0, 0, 0, time.UTC)
+
+func TestParseIgnoredIssue(t *testing.T) {
+	ignoredIssue, err := ParseIgnoredIssue("CVE-2022-24450")
+	assert.NoError(t, err)
+	assert.Equal(t, IgnoredIssue{Id: "CVE-2022-24450"}, *ignoredIssue)
+
+	ignoredIssue, err = ParseIgnoredIssue(" XRAY-123  until 2024-06-01 ")
+	assert.NoError(t, err)
+	assert.Equal(t, IgnoredIssue{Id: "XRAY-123", Until: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}, *ignoredIssue)
+
+	for _, entry := range []string{"", "CVE-2022-24450 2024-06-01", "CVE-2022-24450 until 01/06/2024", "CVE-2022-24450 until"} {
+		_, err = ParseIgnoredIssue(entry)
+		assert.Error(t, err, entry)
+	}
+}
+
+func TestFilterIgnoredIssues(t *testing.T) {
+	rows := []formats.VulnerabilityOrViolationRow{
+		{IssueId: "XRAY-1", Cves: []formats.CveRow{{Id: "CVE-2022-24450"}}},
+		{IssueId: "XRAY-2", Cves: []formats.CveRow{{Id: "CVE-2021-1111"}}},
+		{IssueId: "XRAY-3"},
+		{IssueId: "XRAY-4"},
+	}
+	project := Project{IgnoredIssues: []string{"cve-2022-24450", "CVE-2021-1111 until 2024-05-19", "XRAY-3 until 2024-05-20"}}
+	filteredRows := project.FilterIgnoredIssues(rows, ignoredIssuesTestNow)
+	// The expired entry doesn't ignore XRAY-2, and XRAY-3 is ignored through the end of its expiry date
+	if assert.Len(t, filteredRows, 2) {
+		assert.Equal(t, "XRAY-2", filteredRows[0].IssueId)
+		assert.Equal(t, "XRAY-4", filteredRows[1].IssueId)
+	}
+	assert.Len(t, (&Project{}).FilterIgnoredIssues(rows, ignoredIssuesTestNow), 4)
+}
+
+func TestGetExpiringIgnoredIssues(t *testing.T) {
+	project := Project{IgnoredIssues: []string{"CVE-1", "CVE-2 until 2024-05-19", "CVE-3 until 2024-05-30", "CVE-4 until 2024-07-01"}}
+	expiringIssues := project.GetExpiringIgnoredIssues(ignoredIssuesTestNow, 0)
+	if assert.Len(t, expiringIssues, 1) {
+		assert.Equal(t, "CVE-3", expiringIssues[0].Id)
+	}
+	assert.Len(t, project.GetExpiringIgnoredIssues(ignoredIssuesTestNow, 60), 2)
+}
+
+func TestGetIgnoredIssuesExpiryNote(t *testing.T) {
+	assert.Empty(t, GetIgnoredIssuesExpiryNote(nil))
+	expiringIssues := []IgnoredIssue{
+		{Id: "CVE-2", Until: time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC)},
+		{Id: "CVE-1", Until: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	assert.Equal(t, "\n\n"+ignoredIssuesExpiryTitle+"\n\n- **CVE-1** is ignored until 2024-06-01\n- **CVE-2** is ignored until 2024-06-02\n", GetIgnoredIssuesExpiryNote(expiringIssues))
+}
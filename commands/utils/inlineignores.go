@@ -0,0 +1,415 @@
+package utils
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const inlineIgnoreKeyword = "frogbot:ignore"
+
+// InlineIgnore is an issue of a dependency, ignored by an annotation in a comment of the manifest which declares the dependency,
+// such as "requests==2.25.0 # frogbot:ignore CVE-2023-32681 the proxy credentials aren't used".
+// An expiry date may follow the issue ID, such as "// frogbot:ignore CVE-2022-24450 until 2024-06-01 fixed in the next release".
+type InlineIgnore struct {
+	// A CVE ID or an Xray issue ID
+	Id string `json:"id"`
+	// The last day the issue is ignored, if the annotation has an expiry date
+	Until  string `json:"until,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	// The dependency declared on the annotated line of the manifest, or on the line which follows the annotation
+	Dependency string `json:"dependency"`
+	// The path of the manifest relative to the root of the repository, and the line of the annotation
+	File         string `json:"file"`
+	Line         int    `json:"line"`
+	ignoredIssue IgnoredIssue
+	// The name of the module declared by the manifest, if the manifest declares it, such as the module of go.mod
+	module string
+}
+
+// The location of the annotation, such as "requirements.txt:3"
+func (ii *InlineIgnore) Location() string {
+	return fmt.Sprintf("%s:%d", ii.File, ii.Line)
+}
+
+// The comment syntax of a manifest. The annotation must start a comment, so an annotation mentioned within the text of a comment is skipped.
+type commentSyntax struct {
+	// Matches the start of a comment, which precedes the annotation
+	start *regexp.Regexp
+	// The text which ends the comment, if the comment doesn't end with the line
+	suffix string
+}
+
+var (
+	hashCommentSyntax    = commentSyntax{start: regexp.MustCompile(`#\s*$`)}
+	slashesCommentSyntax = commentSyntax{start: regexp.MustCompile(`//\s*$`)}
+	xmlCommentSyntax     = commentSyntax{start: regexp.MustCompile(`<!--\s*$`), suffix: "-->"}
+	// JSON has no comments, so the annotations of package.json are the values of its "//" keys, which npm ignores
+	jsonCommentSyntax = commentSyntax{start: regexp.MustCompile(`"//"\s*:\s*"$`), suffix: `"`}
+)
+
+var (
+	// A TOML key, such as requests = "^2.25" of Pipfile and of the poetry section of pyproject.toml
+	tomlKeyDependencyRegex = regexp.MustCompile(`^\s*["']?([A-Za-z0-9][\w.-]*)["']?\s*=[^=]`)
+	// A quoted requirement, such as "requests>=2.25" of setup.py and of the dependencies of pyproject.toml
+	quotedRequirementRegex = regexp.MustCompile(`["']([A-Za-z0-9][\w.-]*)\s*(?:\[[^\]]*])?\s*(?:[<>=!~;@ ]|["'])`)
+	// A requirement of a requirements file, such as requests==2.25.0
+	requirementRegex    = regexp.MustCompile(`^\s*([A-Za-z0-9][\w.-]*)`)
+	goModRequireRegex   = regexp.MustCompile(`^\s*(?:require\s+)?([^\s()]+)\s+v\S+`)
+	gradleCoordinates   = regexp.MustCompile(`['"]([^'":\s]+):([^'":\s]+)(?::[^'"]*)?['"]`)
+	nugetPackageIdRegex = regexp.MustCompile(`(?i)(?:Include|id)\s*=\s*"([^"]+)"`)
+	jsonKeyRegex        = regexp.MustCompile(`^\s*"([^"]+)"\s*:`)
+	xmlDependencyStart  = regexp.MustCompile(`<(dependency|plugin)>`)
+	xmlGroupIdRegex     = regexp.MustCompile(`<groupId>\s*([^<\s]+)\s*</groupId>`)
+	xmlArtifactIdRegex  = regexp.MustCompile(`<artifactId>\s*([^<\s]+)\s*</artifactId>`)
+)
+
+// A manifest in which inline ignores are read
+type inlineIgnoreManifest struct {
+	syntax commentSyntax
+	// Return the dependency declared by the annotated line of the manifest, or by the lines which follow it
+	dependency func(lines []string, index int, code string) string
+	// Return the name of the module declared by the manifest, or an empty string if the manifest doesn't declare it
+	module func(content []byte) string
+}
+
+var (
+	pythonManifest  = inlineIgnoreManifest{syntax: hashCommentSyntax, dependency: lineDependency(hashCommentSyntax, getPythonDependency)}
+	goModManifest   = inlineIgnoreManifest{syntax: slashesCommentSyntax, dependency: lineDependency(slashesCommentSyntax, getGoDependency), module: getGoModule}
+	gradleManifest  = inlineIgnoreManifest{syntax: slashesCommentSyntax, dependency: lineDependency(slashesCommentSyntax, getGradleDependency)}
+	nugetManifest   = inlineIgnoreManifest{syntax: xmlCommentSyntax, dependency: lineDependency(xmlCommentSyntax, getNugetDependency)}
+	mavenManifest   = inlineIgnoreManifest{syntax: xmlCommentSyntax, dependency: getMavenDependency, module: getMavenModule}
+	npmManifest     = inlineIgnoreManifest{syntax: jsonCommentSyntax, dependency: lineDependency(jsonCommentSyntax, getNpmDependency), module: getNpmModule}
+	requirementFile = inlineIgnoreManifest{syntax: hashCommentSyntax, dependency: lineDependency(hashCommentSyntax, getRequirementDependency)}
+)
+
+// The manifests, by their file names
+var inlineIgnoreManifests = map[string]inlineIgnoreManifest{
+	"package.json":     npmManifest,
+	"pom.xml":          mavenManifest,
+	"packages.config":  nugetManifest,
+	"go.mod":           goModManifest,
+	"build.gradle":     gradleManifest,
+	"build.gradle.kts": gradleManifest,
+	"requirements.txt": requirementFile,
+	"setup.py":         pythonManifest,
+	"Pipfile":          pythonManifest,
+	"pyproject.toml":   pythonManifest,
+}
+
+// The manifests, by their extensions
+var inlineIgnoreManifestExtensions = map[string]inlineIgnoreManifest{
+	".csproj": nugetManifest,
+}
+
+// GetInlineIgnores reads the inline ignores of the manifests in the working dirs, and of the pip requirements file of the project.
+// The annotations which can't be parsed, or which aren't next to a dependency, are logged and skipped, so the issues they were meant to ignore are reported.
+func (p *Project) GetInlineIgnores(workDirs []string, baseWd string) (inlineIgnores []InlineIgnore) {
+	for _, dir := range workDirs {
+		manifests := make(map[string]inlineIgnoreManifest)
+		for fileName, manifest := range inlineIgnoreManifests {
+			manifests[fileName] = manifest
+		}
+		if p.PipRequirementsFile != "" {
+			manifests[p.PipRequirementsFile] = requirementFile
+		}
+		if dirEntries, err := os.ReadDir(dir); err == nil {
+			for _, dirEntry := range dirEntries {
+				if manifest, exists := inlineIgnoreManifestExtensions[filepath.Ext(dirEntry.Name())]; exists && !dirEntry.IsDir() {
+					manifests[dirEntry.Name()] = manifest
+				}
+			}
+		}
+		for fileName, manifest := range manifests {
+			manifestPath := filepath.Join(dir, fileName)
+			content, err := os.ReadFile(manifestPath) // #nosec G304
+			if err != nil {
+				if !errors.Is(err, os.ErrNotExist) {
+					log.Debug("couldn't read the inline ignores of", manifestPath+":", err.Error())
+				}
+				continue
+			}
+			relativePath, err := filepath.Rel(baseWd, manifestPath)
+			if err != nil {
+				relativePath = manifestPath
+			}
+			inlineIgnores = append(inlineIgnores, parseInlineIgnores(content, manifest, filepath.ToSlash(relativePath))...)
+		}
+	}
+	return
+}
+
+func parseInlineIgnores(content []byte, manifest inlineIgnoreManifest, file string) (inlineIgnores []InlineIgnore) {
+	var module string
+	if manifest.module != nil {
+		module = manifest.module(content)
+	}
+	suffix := manifest.syntax.suffix
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	for index, line := range lines {
+		keywordIndex := strings.Index(line, inlineIgnoreKeyword)
+		if keywordIndex == -1 {
+			continue
+		}
+		commentStart := manifest.syntax.start.FindStringIndex(line[:keywordIndex])
+		if commentStart == nil {
+			continue
+		}
+		annotation := line[keywordIndex+len(inlineIgnoreKeyword):]
+		endsComment := suffix != "" && strings.HasPrefix(annotation, suffix)
+		if annotation != "" && !unicode.IsSpace(rune(annotation[0])) && !endsComment {
+			// Another word which starts with the keyword, such as frogbot:ignored
+			continue
+		}
+		if suffix != "" {
+			if suffixIndex := strings.Index(annotation, suffix); suffixIndex != -1 {
+				annotation = annotation[:suffixIndex]
+			}
+		}
+		inlineIgnore, err := parseInlineIgnore(annotation)
+		if err == nil {
+			if inlineIgnore.Dependency = manifest.dependency(lines, index, line[:commentStart[0]]); inlineIgnore.Dependency == "" {
+				err = errors.New(errInlineIgnoreNoDependency)
+			}
+		}
+		if err != nil {
+			log.Warn(fmt.Sprintf("skipping the inline ignore in %s:%d: %s", file, index+1, err.Error()))
+			continue
+		}
+		inlineIgnore.File = file
+		inlineIgnore.Line = index + 1
+		inlineIgnore.module = module
+		inlineIgnores = append(inlineIgnores, *inlineIgnore)
+	}
+	return
+}
+
+// Parse the text which follows the keyword of the annotation: an issue ID, optionally followed by an expiry date, and the reason
+func parseInlineIgnore(annotation string) (*InlineIgnore, error) {
+	fields := strings.Fields(annotation)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf(errInvalidInlineIgnore, strings.TrimSpace(inlineIgnoreKeyword+annotation))
+	}
+	issueFields := fields[:1]
+	if len(fields) > 1 && strings.EqualFold(fields[1], ignoredIssueUntilKeyword) {
+		if len(fields) == 2 {
+			return nil, fmt.Errorf(errInvalidInlineIgnore, strings.TrimSpace(inlineIgnoreKeyword+annotation))
+		}
+		issueFields = fields[:3]
+	}
+	ignoredIssue, err := ParseIgnoredIssue(strings.Join(issueFields, " "))
+	if err != nil {
+		return nil, fmt.Errorf(errInvalidInlineIgnore, strings.TrimSpace(inlineIgnoreKeyword+annotation))
+	}
+	inlineIgnore := &InlineIgnore{Id: ignoredIssue.Id, Reason: strings.Join(fields[len(issueFields):], " "), ignoredIssue: *ignoredIssue}
+	if !ignoredIssue.Until.IsZero() {
+		inlineIgnore.Until = ignoredIssue.Until.Format(ignoredIssueDateLayout)
+	}
+	return inlineIgnore, nil
+}
+
+// Return the dependency of a manifest which declares a dependency per line. The annotation applies to the dependency of its own line,
+// or to the dependency of the next line, if the annotation is on a line of its own. The comment lines in between are skipped.
+func lineDependency(syntax commentSyntax, getDependency func(line string) string) func(lines []string, index int, code string) string {
+	commentLine := regexp.MustCompile(`^\s*` + strings.TrimSuffix(syntax.start.String(), `\s*$`))
+	return func(lines []string, index int, code string) string {
+		if strings.TrimSpace(code) != "" {
+			return getDependency(code)
+		}
+		for _, line := range lines[index+1:] {
+			if strings.TrimSpace(line) == "" || commentLine.MatchString(line) {
+				continue
+			}
+			return getDependency(line)
+		}
+		return ""
+	}
+}
+
+func getRequirementDependency(line string) string {
+	if strings.HasPrefix(strings.TrimSpace(line), "-") {
+		// An option of the requirements file, such as -r or --index-url
+		return ""
+	}
+	return getFirstSubmatch(requirementRegex, line)
+}
+
+func getPythonDependency(line string) string {
+	if dependency := getFirstSubmatch(tomlKeyDependencyRegex, line); dependency != "" {
+		return dependency
+	}
+	return getFirstSubmatch(quotedRequirementRegex, line)
+}
+
+func getGoDependency(line string) string {
+	return getFirstSubmatch(goModRequireRegex, line)
+}
+
+func getGradleDependency(line string) string {
+	if match := gradleCoordinates.FindStringSubmatch(line); match != nil {
+		return match[1] + ":" + match[2]
+	}
+	return ""
+}
+
+func getNugetDependency(line string) string {
+	return getFirstSubmatch(nugetPackageIdRegex, line)
+}
+
+func getNpmDependency(line string) string {
+	if dependency := getFirstSubmatch(jsonKeyRegex, line); dependency != "//" {
+		return dependency
+	}
+	return ""
+}
+
+// Return the groupId:artifactId of the dependency or plugin element of pom.xml, which contains the annotation or follows it
+func getMavenDependency(lines []string, index int, _ string) string {
+	start := -1
+	for i := index; i >= 0; i-- {
+		if strings.Contains(lines[i], "</dependency>") || strings.Contains(lines[i], "</plugin>") {
+			break
+		}
+		if xmlDependencyStart.MatchString(lines[i]) {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		// The annotation precedes the element
+		for i := index + 1; i < len(lines) && start == -1; i++ {
+			trimmed := strings.TrimSpace(lines[i])
+			switch {
+			case xmlDependencyStart.MatchString(trimmed):
+				start = i
+			case trimmed != "" && !strings.HasPrefix(trimmed, "<!--"):
+				return ""
+			}
+		}
+	}
+	if start == -1 {
+		return ""
+	}
+	var groupId, artifactId string
+	for i := start; i < len(lines); i++ {
+		if groupId == "" {
+			groupId = getFirstSubmatch(xmlGroupIdRegex, lines[i])
+		}
+		if artifactId == "" {
+			artifactId = getFirstSubmatch(xmlArtifactIdRegex, lines[i])
+		}
+		if strings.Contains(lines[i], "</dependency>") || strings.Contains(lines[i], "</plugin>") {
+			break
+		}
+	}
+	if artifactId == "" {
+		return ""
+	}
+	if groupId == "" {
+		// The default groupId of the Maven plugins
+		groupId = "org.apache.maven.plugins"
+	}
+	return groupId + ":" + artifactId
+}
+
+func getGoModule(content []byte) string {
+	for _, line := range strings.Split(string(content), "\n") {
+		if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`)
+		}
+	}
+	return ""
+}
+
+func getNpmModule(content []byte) string {
+	var packageJson struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(content, &packageJson); err != nil {
+		return ""
+	}
+	return packageJson.Name
+}
+
+func getMavenModule(content []byte) string {
+	var pom struct {
+		GroupId    string `xml:"groupId"`
+		ArtifactId string `xml:"artifactId"`
+		Parent     struct {
+			GroupId string `xml:"groupId"`
+		} `xml:"parent"`
+	}
+	if err := xml.Unmarshal(content, &pom); err != nil || pom.ArtifactId == "" {
+		return ""
+	}
+	groupId := pom.GroupId
+	if groupId == "" {
+		groupId = pom.Parent.GroupId
+	}
+	return groupId + ":" + pom.ArtifactId
+}
+
+func getFirstSubmatch(regex *regexp.Regexp, text string) string {
+	if match := regex.FindStringSubmatch(text); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// Compare the dependency names, regardless of their case and of the separators which the package managers treat as equal, such as requests_toolbelt and requests-toolbelt
+func isSameDependency(name, otherName string) bool {
+	normalize := strings.NewReplacer("_", "-", ".", "-").Replace
+	return normalize(strings.ToLower(name)) == normalize(strings.ToLower(otherName))
+}
+
+// The annotation matches the issue, if the issue is of the annotated dependency, or is introduced through it.
+// If the manifest declares its module, the issue must also be found in the module, so that an annotation of one working dir doesn't ignore the issues of other working dirs.
+func (ii *InlineIgnore) matches(row *formats.VulnerabilityOrViolationRow) bool {
+	if !ii.ignoredIssue.matches(row) {
+		return false
+	}
+	if len(row.ImpactPaths) == 0 {
+		return ii.module == "" && isSameDependency(row.ImpactedDependencyName, ii.Dependency)
+	}
+	for _, impactPath := range row.ImpactPaths {
+		if len(impactPath) == 0 || (ii.module != "" && !isSameDependency(impactPath[0].Name, ii.module)) {
+			continue
+		}
+		for _, component := range impactPath[1:] {
+			if isSameDependency(component.Name, ii.Dependency) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FilterInlineIgnoredIssues removes the issues ignored by the inline ignores, and returns them separately, along with the annotations which ignored them.
+// Issues whose annotation has expired are kept.
+func FilterInlineIgnoredIssues(vulnerabilitiesRows []formats.VulnerabilityOrViolationRow, inlineIgnores []InlineIgnore, now time.Time) (filteredRows []formats.VulnerabilityOrViolationRow, suppressedIssues []SuppressedIssue) {
+	if len(inlineIgnores) == 0 {
+		return vulnerabilitiesRows, nil
+	}
+rowsLoop:
+	for i := range vulnerabilitiesRows {
+		for j := range inlineIgnores {
+			if inlineIgnores[j].ignoredIssue.isActive(now) && inlineIgnores[j].matches(&vulnerabilitiesRows[i]) {
+				inlineIgnore := inlineIgnores[j]
+				suppressedIssues = append(suppressedIssues, SuppressedIssue{VulnerabilityOrViolationRow: vulnerabilitiesRows[i], InlineIgnore: &inlineIgnore})
+				continue rowsLoop
+			}
+		}
+		filteredRows = append(filteredRows, vulnerabilitiesRows[i])
+	}
+	return
+}
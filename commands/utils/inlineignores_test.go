@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInlineIgnore(t *testing.T) {
+	inlineIgnore, err := parseInlineIgnore(" CVE-2022-24450 not exploitable in our usage")
+	assert.NoError(t, err)
+	assert.Equal(t, "CVE-2022-24450", inlineIgnore.Id)
+	assert.Equal(t, "not exploitable in our usage", inlineIgnore.Reason)
+	assert.Empty(t, inlineIgnore.Until)
+
+	inlineIgnore, err = parseInlineIgnore(" XRAY-1234 until 2024-06-01 fixed in the next release")
+	assert.NoError(t, err)
+	assert.Equal(t, "XRAY-1234", inlineIgnore.Id)
+	assert.Equal(t, "2024-06-01", inlineIgnore.Until)
+	assert.Equal(t, "fixed in the next release", inlineIgnore.Reason)
+
+	inlineIgnore, err = parseInlineIgnore(" CVE-2022-24450")
+	assert.NoError(t, err)
+	assert.Empty(t, inlineIgnore.Reason)
+
+	for _, annotation := range []string{"", " ", " CVE-2022-24450 until", " CVE-2022-24450 until tomorrow"} {
+		_, err = parseInlineIgnore(annotation)
+		assert.EqualError(t, err, fmt.Sprintf(errInvalidInlineIgnore, strings.TrimSpace("frogbot:ignore"+annotation)), annotation)
+	}
+}
+
+func TestParseInlineIgnores(t *testing.T) {
+	testCases := []struct {
+		manifest inlineIgnoreManifest
+		content  string
+		module   string
+		// The annotated dependencies of CVE-2022-0001 and CVE-2022-0002
+		dependencies []string
+	}{
+		{manifest: requirementFile, content: "# frogbot:ignore CVE-2022-0001 first reason\n\n# Pinned by the platform team\nrequests==2.25.0\nurllib3[secure]>=1.26 # frogbot:ignore CVE-2022-0002 second reason\n# see frogbot:ignore CVE-2022-0003 in the docs\n# frogbot:ignored CVE-2022-0004",
+			dependencies: []string{"requests", "urllib3"}},
+		{manifest: pythonManifest, content: "[packages]\n# frogbot:ignore CVE-2022-0001 first reason\nrequests = \"==2.25.0\"\ndependencies = [\n  \"urllib3>=1.26\", # frogbot:ignore CVE-2022-0002 second reason\n]",
+			dependencies: []string{"requests", "urllib3"}},
+		{manifest: goModManifest, content: "module github.com/jfrog/frogbot\n\nrequire (\n\t// frogbot:ignore CVE-2022-0001 first reason\n\tgithub.com/nats-io/nats-server/v2 v2.7.0\n)\nrequire golang.org/x/net v0.7.0 // frogbot:ignore CVE-2022-0002 second reason\n// https://example.com/frogbot:ignore CVE-2022-0003",
+			module: "github.com/jfrog/frogbot", dependencies: []string{"github.com/nats-io/nats-server/v2", "golang.org/x/net"}},
+		{manifest: gradleManifest, content: "dependencies {\n    // frogbot:ignore CVE-2022-0001 first reason\n    implementation 'com.fasterxml.jackson.core:jackson-databind:2.13.0'\n    implementation(\"org.yaml:snakeyaml:1.30\") // frogbot:ignore CVE-2022-0002 second reason\n}",
+			dependencies: []string{"com.fasterxml.jackson.core:jackson-databind", "org.yaml:snakeyaml"}},
+		{manifest: mavenManifest, content: "<project>\n  <groupId>org.jfrog</groupId>\n  <artifactId>frogbot</artifactId>\n  <dependencies>\n    <!-- frogbot:ignore CVE-2022-0001 first reason -->\n    <dependency>\n      <groupId>com.fasterxml.jackson.core</groupId>\n      <artifactId>jackson-databind</artifactId> <!--frogbot:ignore CVE-2022-0002 second reason-->\n    </dependency>\n    <!-- frogbot:ignored CVE-2022-0003 -->\n  </dependencies>\n</project>",
+			module: "org.jfrog:frogbot", dependencies: []string{"com.fasterxml.jackson.core:jackson-databind", "com.fasterxml.jackson.core:jackson-databind"}},
+		{manifest: nugetManifest, content: "<ItemGroup>\n  <!-- frogbot:ignore CVE-2022-0001 first reason -->\n  <PackageReference Include=\"Newtonsoft.Json\" Version=\"12.0.1\" />\n  <PackageReference Include=\"System.Text.Json\" Version=\"5.0.0\" /> <!-- frogbot:ignore CVE-2022-0002 second reason -->\n</ItemGroup>",
+			dependencies: []string{"Newtonsoft.Json", "System.Text.Json"}},
+		{manifest: npmManifest, content: "{\n  \"name\": \"frogbot\",\n  \"dependencies\": {\n    \"//\": \"frogbot:ignore CVE-2022-0001 first reason\",\n    \"lodash\": \"4.17.20\",\n    \"//\":\"frogbot:ignore CVE-2022-0002 second reason\",\n    \"minimist\": \"1.2.5\"\n  },\n  \"description\": \"frogbot:ignore CVE-2022-0003\"\n}",
+			module: "frogbot", dependencies: []string{"lodash", "minimist"}},
+	}
+	for _, testCase := range testCases {
+		inlineIgnores := parseInlineIgnores([]byte(testCase.content), testCase.manifest, "manifest")
+		if assert.Len(t, inlineIgnores, 2, testCase.content) {
+			assert.Equal(t, "CVE-2022-0001", inlineIgnores[0].Id)
+			assert.Equal(t, "first reason", inlineIgnores[0].Reason)
+			assert.Equal(t, "CVE-2022-0002", inlineIgnores[1].Id)
+			assert.Equal(t, "second reason", inlineIgnores[1].Reason)
+			assert.Equal(t, testCase.dependencies, []string{inlineIgnores[0].Dependency, inlineIgnores[1].Dependency})
+			assert.Equal(t, testCase.module, inlineIgnores[0].module)
+			assert.Equal(t, "manifest:"+fmt.Sprint(inlineIgnores[1].Line), inlineIgnores[1].Location())
+		}
+	}
+
+	// An invalid annotation, or an annotation which isn't next to a dependency, is skipped
+	assert.Empty(t, parseInlineIgnores([]byte("# frogbot:ignore\nlodash # frogbot:ignore CVE-2022-0001 until never\n--index-url https://example.com # frogbot:ignore CVE-2022-0002\n# frogbot:ignore CVE-2022-0003"), requirementFile, "requirements.txt"))
+	assert.Empty(t, parseInlineIgnores([]byte("<project>\n  <!-- frogbot:ignore CVE-2022-0001 -->\n  <name>frogbot</name>\n</project>"), mavenManifest, "pom.xml"))
+}
+
+func TestGetInlineIgnores(t *testing.T) {
+	baseWd := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(baseWd, "api"), 0700))
+	assert.NoError(t, os.WriteFile(filepath.Join(baseWd, "api", "requirements-dev.txt"), []byte("pytest==7.0.0\nrequests==2.25.0 # frogbot:ignore CVE-2023-32681 no proxies\n"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(baseWd, "api", "setup.py"), []byte("install_requires=[\n    \"urllib3>=1.26\",  # frogbot:ignore CVE-2022-0001\n]\n"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(baseWd, "Service.csproj"), []byte("<PackageReference Include=\"Newtonsoft.Json\" Version=\"12.0.1\" /> <!-- frogbot:ignore XRAY-1 legacy service -->\n"), 0600))
+
+	project := &Project{PipRequirementsFile: "requirements-dev.txt"}
+	inlineIgnores := project.GetInlineIgnores([]string{baseWd, filepath.Join(baseWd, "api")}, baseWd)
+	var locations []string
+	for _, inlineIgnore := range inlineIgnores {
+		locations = append(locations, inlineIgnore.Id+" "+inlineIgnore.Dependency+" "+inlineIgnore.Location())
+	}
+	assert.ElementsMatch(t, []string{"XRAY-1 Newtonsoft.Json Service.csproj:1", "CVE-2023-32681 requests api/requirements-dev.txt:2", "CVE-2022-0001 urllib3 api/setup.py:2"}, locations)
+}
+
+func TestFilterInlineIgnoredIssues(t *testing.T) {
+	root := formats.ComponentRow{Name: "frogbot"}
+	otherRoot := formats.ComponentRow{Name: "other-module"}
+	lodash := formats.ComponentRow{Name: "lodash"}
+	minimist := formats.ComponentRow{Name: "minimist"}
+	mkdirp := formats.ComponentRow{Name: "mkdirp"}
+	rows := []formats.VulnerabilityOrViolationRow{
+		{IssueId: "XRAY-1", ImpactedDependencyName: "lodash", Cves: []formats.CveRow{{Id: "CVE-2022-0001"}}, ImpactPaths: [][]formats.ComponentRow{{root, lodash}}},
+		// An issue introduced through the annotated dependency is ignored
+		{IssueId: "XRAY-2", ImpactedDependencyName: "minimist", Cves: []formats.CveRow{{Id: "CVE-2022-0002"}}, ImpactPaths: [][]formats.ComponentRow{{root, mkdirp, minimist}}},
+		// The same issue of another module isn't ignored
+		{IssueId: "XRAY-1", ImpactedDependencyName: "lodash", Cves: []formats.CveRow{{Id: "CVE-2022-0001"}}, ImpactPaths: [][]formats.ComponentRow{{otherRoot, lodash}}},
+		// The annotated issue of another dependency isn't ignored
+		{IssueId: "XRAY-3", ImpactedDependencyName: "minimist", ImpactPaths: [][]formats.ComponentRow{{root, minimist}}},
+		// The annotation has expired
+		{IssueId: "XRAY-4", ImpactedDependencyName: "lodash", ImpactPaths: [][]formats.ComponentRow{{root, lodash}}},
+	}
+	filteredRows, _ := FilterInlineIgnoredIssues(rows, nil, time.Now())
+	assert.Equal(t, rows, filteredRows)
+
+	newInlineIgnore := func(annotation, dependency string) InlineIgnore {
+		inlineIgnore, err := parseInlineIgnore(annotation)
+		assert.NoError(t, err)
+		inlineIgnore.Dependency = dependency
+		inlineIgnore.module = "frogbot"
+		return *inlineIgnore
+	}
+	inlineIgnores := []InlineIgnore{
+		newInlineIgnore(" cve-2022-0001 not exploitable", "lodash"),
+		newInlineIgnore(" CVE-2022-0002", "mkdirp"),
+		newInlineIgnore(" XRAY-3", "lodash"),
+		newInlineIgnore(" XRAY-4 until 2024-06-01", "lodash"),
+	}
+	filteredRows, suppressedIssues := FilterInlineIgnoredIssues(rows, inlineIgnores, time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, rows[2:], filteredRows)
+	if assert.Len(t, suppressedIssues, 2) {
+		assert.Equal(t, rows[0], suppressedIssues[0].VulnerabilityOrViolationRow)
+		assert.Equal(t, "not exploitable", suppressedIssues[0].InlineIgnore.Reason)
+		assert.Equal(t, "mkdirp", suppressedIssues[1].InlineIgnore.Dependency)
+		assert.Empty(t, suppressedIssues[1].IgnoredDependency)
+	}
+
+	// Without the module of the manifest, the issues of the annotated dependency are ignored in all the modules
+	withoutModule := newInlineIgnore(" CVE-2022-0001", "lodash")
+	withoutModule.module = ""
+	filteredRows, _ = FilterInlineIgnoredIssues(rows, []InlineIgnore{withoutModule}, time.Now())
+	assert.Equal(t, []formats.VulnerabilityOrViolationRow{rows[1], rows[3], rows[4]}, filteredRows)
+}
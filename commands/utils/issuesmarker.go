@@ -0,0 +1,170 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+)
+
+// The issues marker is a markdown comment, which is hidden by all the supported git providers.
+// It holds the hash of the issues found by the scan, and the commit in which this set of issues was first found.
+const issuesMarkerPrefix = "[//]: # (frogbot-issues "
+
+var issuesMarkerRegex = regexp.MustCompile(regexp.QuoteMeta(issuesMarkerPrefix) + `([0-9a-f]+) ?([0-9a-f]*)\)`)
+
+// The tier marker identifies the comment of a severity tier, when the comments are split by severity.
+// It holds the name of the tier, and the hash of the issues of the tier.
+const tierMarkerPrefix = "[//]: # (frogbot-tier "
+
+var tierMarkerRegex = regexp.MustCompile(regexp.QuoteMeta(tierMarkerPrefix) + `([a-z-]+) ([0-9a-f]+)\)`)
+
+// The run lock marker identifies the comment added by a Frogbot run when it starts scanning a commit, so that concurrent runs on the same commit are skipped.
+// It holds the scanned commit, and a random ID of the run.
+const runLockMarkerPrefix = "[//]: # (frogbot-run-lock "
+
+var runLockMarkerRegex = regexp.MustCompile(regexp.QuoteMeta(runLockMarkerPrefix) + `([0-9a-f]+) ([0-9a-f]+)\)`)
+
+// The content marker holds the hash of the rendered results comment, so that an unchanged comment isn't posted again when commentOnlyOnChange is set
+const contentMarkerPrefix = "[//]: # (frogbot-content "
+
+var contentMarkerRegex = regexp.MustCompile(regexp.QuoteMeta(contentMarkerPrefix) + `([0-9a-f]+)\)`)
+
+// The thread marker holds the keys of the issues reported by a comment of the results thread, when threadedUpdates is set,
+// so that the next reply to the thread states the issues resolved and found since the previous comment
+const threadMarkerPrefix = "[//]: # (frogbot-thread "
+
+var threadMarkerRegex = regexp.MustCompile(regexp.QuoteMeta(threadMarkerPrefix) + `([0-9a-f,]*)\)`)
+
+// The hidden markers at the end of a results comment, which are kept when the comment is truncated
+var trailingMarkersRegex = regexp.MustCompile(`(?:\s*` + regexp.QuoteMeta("[//]: # (frogbot-") + `(?:issues|content|thread) [0-9a-f ,]*\))+$`)
+
+// The bot header marker precedes the header line with the botName, which is added to the beginning of the comments,
+// since the git providers don't allow setting the author of a comment added through their APIs.
+const botHeaderMarker = "[//]: # (frogbot-bot-header)"
+
+// GetIssuesMarker returns the hidden issues marker to append to the pull request comment
+func GetIssuesMarker(issuesHash, commitSha string) string {
+	return fmt.Sprintf("\n\n%s%s %s)", issuesMarkerPrefix, issuesHash, commitSha)
+}
+
+// ParseIssuesMarker extracts the issues hash and the commit SHA from a pull request comment
+func ParseIssuesMarker(comment string) (issuesHash, commitSha string, found bool) {
+	match := issuesMarkerRegex.FindStringSubmatch(comment)
+	if match == nil {
+		return "", "", false
+	}
+	return match[1], match[2], true
+}
+
+// GetTierMarker returns the hidden marker to append to the comment of the severity tier
+func GetTierMarker(tier, issuesHash string) string {
+	return fmt.Sprintf("\n\n%s%s %s)", tierMarkerPrefix, tier, issuesHash)
+}
+
+// ParseTierMarker extracts the name of the severity tier and the hash of its issues from a pull request comment
+func ParseTierMarker(comment string) (tier, issuesHash string, found bool) {
+	match := tierMarkerRegex.FindStringSubmatch(comment)
+	if match == nil {
+		return "", "", false
+	}
+	return match[1], match[2], true
+}
+
+// GetRunLockMarker returns the hidden marker to append to the run lock comment
+func GetRunLockMarker(commitSha, runId string) string {
+	return fmt.Sprintf("\n\n%s%s %s)", runLockMarkerPrefix, commitSha, runId)
+}
+
+// ParseRunLockMarker extracts the scanned commit and the ID of the run from a run lock comment
+func ParseRunLockMarker(comment string) (commitSha, runId string, found bool) {
+	match := runLockMarkerRegex.FindStringSubmatch(comment)
+	if match == nil {
+		return "", "", false
+	}
+	return match[1], match[2], true
+}
+
+// GetContentMarker returns the hidden marker with the hash of the findings of the comment, to append to the pull request comment
+func GetContentMarker(contentHash string) string {
+	return fmt.Sprintf("\n\n%s%s)", contentMarkerPrefix, contentHash)
+}
+
+// ParseContentMarker extracts the hash of the findings from a pull request comment
+func ParseContentMarker(comment string) (contentHash string, found bool) {
+	match := contentMarkerRegex.FindStringSubmatch(comment)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// GetThreadMarker returns the hidden marker with the keys of the reported issues, to append to the comments of the results thread
+func GetThreadMarker(issueKeys []string) string {
+	return fmt.Sprintf("\n\n%s%s)", threadMarkerPrefix, strings.Join(issueKeys, ","))
+}
+
+// ParseThreadMarker extracts the keys of the reported issues from a comment of the results thread
+func ParseThreadMarker(comment string) (issueKeys []string, found bool) {
+	match := threadMarkerRegex.FindStringSubmatch(comment)
+	if match == nil {
+		return nil, false
+	}
+	if match[1] == "" {
+		return []string{}, true
+	}
+	return strings.Split(match[1], ","), true
+}
+
+// GetBotHeader returns the header line with the botName to add to the beginning of the pull request comments, or an empty string if no botName is set
+func GetBotHeader(botName string) string {
+	if botName == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s\n\n**%s**\n\n", botHeaderMarker, botName)
+}
+
+// TrimBotHeader removes the header line with the botName from the beginning of a pull request comment
+func TrimBotHeader(comment string) string {
+	if !strings.HasPrefix(comment, botHeaderMarker+"\n\n") {
+		return comment
+	}
+	header := strings.TrimPrefix(comment, botHeaderMarker+"\n\n")
+	if headerEnd := strings.Index(header, "\n\n"); headerEnd >= 0 {
+		return header[headerEnd+2:]
+	}
+	return comment
+}
+
+func isIssuesMarkerComment(comment string) bool {
+	return strings.Contains(comment, issuesMarkerPrefix) || strings.Contains(comment, tierMarkerPrefix) || strings.Contains(comment, contentMarkerPrefix) || strings.Contains(comment, threadMarkerPrefix)
+}
+
+// GetIssuesHash returns a hash which identifies the set of issues and misconfigurations, regardless of their order
+func GetIssuesHash(vulnerabilitiesRows []formats.VulnerabilityOrViolationRow, iacRows []IacRow) (string, error) {
+	issueIds := make([]string, 0, len(vulnerabilitiesRows)+len(iacRows))
+	for _, row := range vulnerabilitiesRows {
+		issueIds = append(issueIds, row.ImpactedDependencyName+":"+row.ImpactedDependencyVersion+":"+row.IssueId)
+	}
+	for _, row := range iacRows {
+		issueIds = append(issueIds, row.location()+":"+row.RuleId)
+	}
+	sort.Strings(issueIds)
+	return Md5Hash(issueIds...)
+}
+
+// GetHeadCommitSha returns the SHA of the checked out commit in the given directory, or an empty string if the directory isn't a git repository
+func GetHeadCommitSha(dir string) string {
+	repository, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return ""
+	}
+	head, err := repository.Head()
+	if err != nil {
+		return ""
+	}
+	return head.Hash().String()
+}
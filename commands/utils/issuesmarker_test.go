@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIssuesMarker(t *testing.T) {
+	issuesHash, commitSha, found := ParseIssuesMarker("message" + GetIssuesMarker("0123abcd", "4567ef"))
+	assert.True(t, found)
+	assert.Equal(t, "0123abcd", issuesHash)
+	assert.Equal(t, "4567ef", commitSha)
+
+	issuesHash, commitSha, found = ParseIssuesMarker("message" + GetIssuesMarker("0123abcd", ""))
+	assert.True(t, found)
+	assert.Equal(t, "0123abcd", issuesHash)
+	assert.Empty(t, commitSha)
+
+	_, _, found = ParseIssuesMarker("message")
+	assert.False(t, found)
+}
+
+func TestParseRunLockMarker(t *testing.T) {
+	commitSha, runId, found := ParseRunLockMarker("message" + GetRunLockMarker("0123abcd", "4567ef"))
+	assert.True(t, found)
+	assert.Equal(t, "0123abcd", commitSha)
+	assert.Equal(t, "4567ef", runId)
+
+	_, _, found = ParseRunLockMarker("message" + GetIssuesMarker("0123abcd", "4567ef"))
+	assert.False(t, found)
+	// The run lock comment isn't a results comment
+	assert.False(t, (&StandardOutput{}).IsFrogbotResultComment("message"+GetRunLockMarker("0123abcd", "4567ef")))
+}
+
+func TestGetIssuesHash(t *testing.T) {
+	rowA := formats.VulnerabilityOrViolationRow{IssueId: "XRAY-1", ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "1.2.5"}
+	rowB := formats.VulnerabilityOrViolationRow{IssueId: "XRAY-2", ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "4.17.20"}
+	hash, err := GetIssuesHash([]formats.VulnerabilityOrViolationRow{rowA, rowB}, nil)
+	assert.NoError(t, err)
+
+	// The order of the issues doesn't matter
+	reversedHash, err := GetIssuesHash([]formats.VulnerabilityOrViolationRow{rowB, rowA}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, hash, reversedHash)
+
+	otherHash, err := GetIssuesHash([]formats.VulnerabilityOrViolationRow{rowA}, nil)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, otherHash)
+}
+
+func TestContentMarker(t *testing.T) {
+	marker := GetContentMarker("0123abcd")
+	contentHash, found := ParseContentMarker("message" + marker)
+	assert.True(t, found)
+	assert.Equal(t, "0123abcd", contentHash)
+
+	_, found = ParseContentMarker("message" + GetIssuesMarker("0123abcd", "4567ef"))
+	assert.False(t, found)
+	// The comments with a content marker are results comments
+	assert.True(t, (&StandardOutput{}).IsFrogbotResultComment("message"+marker))
+}
+
+func TestBotHeader(t *testing.T) {
+	assert.Empty(t, GetBotHeader(""))
+	comment := GetBotHeader("Security Bot") + GetSimplifiedTitle(NoVulnerabilityBannerSource) + "message"
+	assert.Equal(t, GetSimplifiedTitle(NoVulnerabilityBannerSource)+"message", TrimBotHeader(comment))
+	// The results comments are still identified after the header
+	assert.True(t, (&SimplifiedOutput{}).IsFrogbotResultComment(comment))
+	assert.True(t, (&StandardOutput{}).IsFrogbotResultComment(GetBotHeader("Security Bot")+GetBanner(NoVulnerabilityBannerSource)))
+	// Comments without the header are returned as is
+	assert.Equal(t, "message", TrimBotHeader("message"))
+}
+
+func TestIsFrogbotResultCommentWithIssuesMarker(t *testing.T) {
+	comment := "🐸 Frogbot: 1 issues, unchanged since abc123" + GetIssuesMarker("0123abcd", "abc123")
+	assert.True(t, (&StandardOutput{}).IsFrogbotResultComment(comment))
+	assert.True(t, (&SimplifiedOutput{}).IsFrogbotResultComment(comment))
+}
+
+func TestThreadMarker(t *testing.T) {
+	marker := GetThreadMarker([]string{"0a1b2c3d", "4e5f6a7b"})
+	issueKeys, found := ParseThreadMarker("message" + marker)
+	assert.True(t, found)
+	assert.Equal(t, []string{"0a1b2c3d", "4e5f6a7b"}, issueKeys)
+	assert.True(t, isIssuesMarkerComment("message"+marker))
+
+	// A thread without issues
+	issueKeys, found = ParseThreadMarker(GetThreadMarker(nil))
+	assert.True(t, found)
+	assert.Empty(t, issueKeys)
+
+	_, found = ParseThreadMarker("message")
+	assert.False(t, found)
+}
@@ -0,0 +1,176 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	overrideAssignmentSymbol = "="
+	overridePathSeparator    = "."
+	errInvalidOverride       = "the override '%s' is invalid. Overrides are expected in the key=value format, such as scan.minSeverity=High"
+	errUnknownOverrideKey    = "the key '%s' of the override '%s' doesn't match any param of the frogbot-config file"
+	errAmbiguousOverrideKey  = "the key '%s' of the override '%s' matches several params: %s. Use the full path of the param instead"
+	errInvalidOverrideValue  = "the value '%s' of the override '%s' is invalid: %s"
+)
+
+// The overrides of the params set using the --set flag, such as "scan.minSeverity=High"
+var configOverrides []string
+
+func SetConfigOverrides(overrides []string) {
+	configOverrides = overrides
+}
+
+// A param of the frogbot-config file, and its path in the file
+type paramField struct {
+	path  string
+	value reflect.Value
+}
+
+// applyConfigOverrides sets the params overridden using the --set flag.
+// The key of each override is the path of the param in the frogbot-config file, such as "scan.minSeverity" or "severityColors.Critical".
+// A param whose name is unique in the file may be set by its name only, such as "minSeverity".
+func (p *Params) applyConfigOverrides(overrides []string) error {
+	for _, override := range overrides {
+		key, value, found := strings.Cut(override, overrideAssignmentSymbol)
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !found || key == "" {
+			return fmt.Errorf(errInvalidOverride, override)
+		}
+		if err := p.applyConfigOverride(override, key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Params) applyConfigOverride(override, key, value string) error {
+	fields := listParamFields(reflect.ValueOf(p).Elem(), "")
+	field, err := findParamField(fields, override, key)
+	if err == nil {
+		if err = setParamValue(field.value, value); err != nil {
+			return fmt.Errorf(errInvalidOverrideValue, value, override, err.Error())
+		}
+		return nil
+	}
+	// The key may be an entry of a map param, such as severityColors.Critical
+	mapKey, entryKey, found := cutLast(key, overridePathSeparator)
+	if !found {
+		return err
+	}
+	mapField, mapErr := findParamField(fields, override, mapKey)
+	if mapErr != nil || mapField.value.Kind() != reflect.Map || mapField.value.Type().Key().Kind() != reflect.String {
+		return err
+	}
+	if err = setMapEntry(mapField.value, entryKey, value); err != nil {
+		return fmt.Errorf(errInvalidOverrideValue, value, override, err.Error())
+	}
+	return nil
+}
+
+// List the params of the given struct and of its sections, by their yaml names. The lists and the maps of structs, such as the projects, aren't listed.
+func listParamFields(value reflect.Value, prefix string) (fields []paramField) {
+	for index := 0; index < value.NumField(); index++ {
+		structField := value.Type().Field(index)
+		name, inline := parseYamlTag(structField.Tag.Get("yaml"))
+		// The fields without a yaml name aren't params of the file, such as the Git token which is read from the environment
+		if !structField.IsExported() || (name == "" && !inline) || name == "-" {
+			continue
+		}
+		fieldValue := value.Field(index)
+		if inline {
+			fields = append(fields, listParamFields(fieldValue, prefix)...)
+			continue
+		}
+		path := prefix + name
+		fields = append(fields, paramField{path: path, value: fieldValue})
+		if fieldValue.Kind() == reflect.Struct {
+			fields = append(fields, listParamFields(fieldValue, path+overridePathSeparator)...)
+		}
+	}
+	return
+}
+
+func parseYamlTag(tag string) (name string, inline bool) {
+	name, options, _ := strings.Cut(tag, ",")
+	return name, strings.Contains(options, "inline")
+}
+
+// Find the param by its full path, or by its name if no param has this path
+func findParamField(fields []paramField, override, key string) (*paramField, error) {
+	var matches []paramField
+	for _, field := range fields {
+		if strings.EqualFold(field.path, key) {
+			return &field, nil
+		}
+		if _, name, _ := cutLast(field.path, overridePathSeparator); strings.EqualFold(name, key) {
+			matches = append(matches, field)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf(errUnknownOverrideKey, key, override)
+	case 1:
+		return &matches[0], nil
+	default:
+		var paths []string
+		for _, match := range matches {
+			paths = append(paths, match.path)
+		}
+		return nil, fmt.Errorf(errAmbiguousOverrideKey, key, override, strings.Join(paths, ", "))
+	}
+}
+
+// Set the value of the param. Strings are set as is, and lists of strings may be comma separated.
+// Other values, such as booleans, numbers and maps, are parsed as YAML.
+func setParamValue(field reflect.Value, value string) error {
+	switch {
+	case field.Kind() == reflect.Struct:
+		return fmt.Errorf("the param is a section, so only its params can be set")
+	case field.Kind() == reflect.String:
+		field.SetString(value)
+		return nil
+	case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String && !strings.HasPrefix(value, "["):
+		list := reflect.MakeSlice(field.Type(), 0, 0)
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = reflect.Append(list, reflect.ValueOf(item).Convert(field.Type().Elem()))
+			}
+		}
+		field.Set(list)
+		return nil
+	}
+	parsedValue := reflect.New(field.Type())
+	if err := yaml.Unmarshal([]byte(value), parsedValue.Interface()); err != nil {
+		return err
+	}
+	field.Set(parsedValue.Elem())
+	return nil
+}
+
+// Set an entry of a map param. The map is copied, since it may be shared with the config of other repositories through the defaults.
+func setMapEntry(mapField reflect.Value, entryKey, value string) error {
+	entryValue := reflect.New(mapField.Type().Elem()).Elem()
+	if err := setParamValue(entryValue, value); err != nil {
+		return err
+	}
+	newMap := reflect.MakeMap(mapField.Type())
+	iterator := mapField.MapRange()
+	for iterator.Next() {
+		newMap.SetMapIndex(iterator.Key(), iterator.Value())
+	}
+	newMap.SetMapIndex(reflect.ValueOf(entryKey).Convert(mapField.Type().Key()), entryValue)
+	mapField.Set(newMap)
+	return nil
+}
+
+func cutLast(text, separator string) (before, after string, found bool) {
+	index := strings.LastIndex(text, separator)
+	if index < 0 {
+		return "", text, false
+	}
+	return text[:index], text[index+len(separator):], true
+}
@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestApplyConfigOverrides(t *testing.T) {
+	params := Params{
+		Scan:           Scan{SeverityPolicy: SeverityPolicy{MinSeverity: "Low"}},
+		SeverityColors: map[string]string{"High": "#F4900C"},
+	}
+	overrides := []string{
+		"minSeverity=High",
+		"scan.failSeverityThreshold = Critical",
+		"jfrogPlatform.watches=watch-1, watch-2",
+		"fixPRBranches=[main, release/*]",
+		"maxCommentLength=1000",
+		"failOnScanError=false",
+		"GitLabApprovalGate=true",
+		"severityColors.Critical=#DD2E44",
+	}
+	assert.NoError(t, params.applyConfigOverrides(overrides))
+	assert.Equal(t, SeverityPolicy{MinSeverity: "High", FailSeverityThreshold: "Critical"}, params.SeverityPolicy)
+	assert.Equal(t, []string{"watch-1", "watch-2"}, params.Watches)
+	assert.Equal(t, []string{"main", "release/*"}, params.FixPRBranches)
+	assert.Equal(t, 1000, params.MaxCommentLength)
+	assert.False(t, params.ShouldFailOnScanError())
+	assert.True(t, params.GitLabApprovalGate)
+	assert.Equal(t, map[string]string{"High": "#F4900C", "Critical": "#DD2E44"}, params.SeverityColors)
+}
+
+func TestApplyConfigOverridesErrors(t *testing.T) {
+	testCases := []struct {
+		override    string
+		expectedErr string
+	}{
+		{override: "minSeverity", expectedErr: fmt.Sprintf(errInvalidOverride, "minSeverity")},
+		{override: "=High", expectedErr: fmt.Sprintf(errInvalidOverride, "=High")},
+		{override: "maxSeverity=High", expectedErr: fmt.Sprintf(errUnknownOverrideKey, "maxSeverity", "maxSeverity=High")},
+		// The fields which aren't params of the file can't be overridden
+		{override: "git.token=secret", expectedErr: fmt.Sprintf(errUnknownOverrideKey, "git.token", "git.token=secret")},
+		{override: "scan=High", expectedErr: fmt.Sprintf(errInvalidOverrideValue, "High", "scan=High", "the param is a section, so only its params can be set")},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.override, func(t *testing.T) {
+			params := Params{}
+			assert.EqualError(t, params.applyConfigOverrides([]string{testCase.override}), testCase.expectedErr)
+		})
+	}
+	params := Params{}
+	assert.ErrorContains(t, params.applyConfigOverrides([]string{"maxCommentLength=long"}), "the value 'long' of the override 'maxCommentLength=long' is invalid")
+}
+
+func TestNewConfigAggregatorOverrides(t *testing.T) {
+	configContent := `
+- defaults:
+    severityColors:
+      High: "#F4900C"
+- params:
+    git:
+      repoName: frogbot
+    scan:
+      minSeverity: Low
+      projects:
+        - workingDirs: [payments]
+- params:
+    git:
+      repoName: frogbot-docs
+`
+	var configData FrogbotConfigAggregator
+	assert.NoError(t, yaml.Unmarshal([]byte(configContent), &configData))
+	defer SetConfigOverrides(nil)
+
+	SetConfigOverrides([]string{"minSeverity=High", "git.branches=dev", "severityColors.Critical=#DD2E44"})
+	configAggregator, err := NewConfigAggregator(&configData, Git{}, &config.ServerDetails{}, true)
+	assert.NoError(t, err)
+	for _, repoConfig := range configAggregator {
+		assert.Equal(t, "High", repoConfig.MinSeverity)
+		// The projects inherit the overridden params of the repository
+		assert.Equal(t, "High", repoConfig.Projects[0].MinSeverity)
+		assert.Equal(t, []string{"dev"}, repoConfig.Git.Branches)
+		assert.Equal(t, map[string]string{"High": "#F4900C", "Critical": "#DD2E44"}, repoConfig.SeverityColors)
+	}
+	// The defaults shared by the repositories are kept unchanged
+	assert.Equal(t, map[string]string{"High": "#F4900C"}, configData[0].Defaults.SeverityColors)
+
+	// The overridden params are validated like the params of the file
+	SetConfigOverrides([]string{"reportTarget=slack"})
+	_, err = NewConfigAggregator(&configData, Git{}, &config.ServerDetails{}, true)
+	assert.Error(t, err)
+}
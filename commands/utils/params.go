@@ -7,14 +7,21 @@ import (
 	"github.com/jfrog/froggit-go/vcsclient"
 	"github.com/jfrog/froggit-go/vcsutils"
 	coreconfig "github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/pkg/errors"
+	"golang.org/x/net/http/httpguts"
 	"gopkg.in/yaml.v3"
 	"net/http"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"text/template"
 )
 
 const (
@@ -31,14 +38,449 @@ type FrogbotConfigAggregator []FrogbotRepoConfig
 
 type FrogbotRepoConfig struct {
 	Params `yaml:"params,omitempty"`
+	// Defaults are global parameters, applied to all the repositories in the config file that don't override them.
+	// A config file may contain a single "defaults" entry, which doesn't represent a repository.
+	Defaults *Params `yaml:"defaults,omitempty"`
 	OutputWriter
 	Server coreconfig.ServerDetails
+	// The parsed template of the commentTemplatePath param, or nil if no comment template is configured
+	CommentTemplate *template.Template `yaml:"-"`
+	// The head commit of the scanned pull request, if it's known without the checked out code, such as when the pull request is downloaded
+	HeadCommitSha string `yaml:"-"`
+	// The yaml paths of the params set in the config file, such as "scan.scanSecrets". A param set to false or 0 is kept over the defaults.
+	setParams map[string]bool
+}
+
+// setConfigParamsPaths records the params set in each entry of the config file content, from which configData was unmarshalled
+func setConfigParamsPaths(content []byte, configData FrogbotConfigAggregator) error {
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return err
+	}
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.SequenceNode {
+		return nil
+	}
+	for index, entryNode := range document.Content[0].Content {
+		if index >= len(configData) {
+			break
+		}
+		if entryNode.Kind == yaml.AliasNode {
+			entryNode = entryNode.Alias
+		}
+		configData[index].setParams = map[string]bool{}
+		for i := 0; i+1 < len(entryNode.Content); i += 2 {
+			if entryNode.Content[i].Value == "params" {
+				addYamlKeyPaths(entryNode.Content[i+1], "", configData[index].setParams)
+			}
+		}
+	}
+	return nil
+}
+
+// addYamlKeyPaths adds the dot separated paths of the keys of the given mapping node, which aren't set to null, to paths.
+func addYamlKeyPaths(node *yaml.Node, prefix string, paths map[string]bool) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Tag == "!!merge" {
+			// The keys of the merged mappings ("<<: *anchor") are set at the same level
+			mergedNodes := []*yaml.Node{value}
+			if value.Kind == yaml.SequenceNode {
+				mergedNodes = value.Content
+			}
+			for _, mergedNode := range mergedNodes {
+				addYamlKeyPaths(mergedNode, prefix, paths)
+			}
+			continue
+		}
+		if value.Tag == "!!null" {
+			continue
+		}
+		keyPath := joinYamlPath(prefix, key.Value)
+		paths[keyPath] = true
+		addYamlKeyPaths(value, keyPath, paths)
+	}
+}
+
+func joinYamlPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// mergeParamsDefaults fills the params which aren't set in the repository config with the given defaults
+func (frc *FrogbotRepoConfig) mergeParamsDefaults(defaults *Params) {
+	if defaults != nil {
+		mergeDefaultsAt(reflect.ValueOf(&frc.Params).Elem(), reflect.ValueOf(defaults).Elem(), "", frc.setParams)
+	}
 }
 
 type Params struct {
 	Scan          `yaml:"scan,omitempty"`
 	Git           `yaml:"git,omitempty"`
 	JFrogPlatform `yaml:"jfrogPlatform,omitempty"`
+	// Proxy is the URL of the proxy server, used by both the VCS and the Xray clients.
+	Proxy string `yaml:"proxy,omitempty"`
+	// When scanning GitLab merge requests, approve the merge request if the scan is clean, and remove the approval otherwise
+	GitLabApprovalGate bool `yaml:"gitLabApprovalGate,omitempty"`
+	// The base directory of the temp directories created during the scan
+	TempDir string `yaml:"tempDir,omitempty"`
+	// The path of a PEM file with the CA certificates of the Git provider and the JFrog Platform, in addition to the system CA certificates
+	CaCertPath string `yaml:"caCertPath,omitempty"`
+	// Headers added to the requests sent to the Git provider and to Xray, such as the headers required by an API gateway
+	CustomHeaders map[string]string `yaml:"customHeaders,omitempty"`
+	// The maximum length of the pull request comments, of at least 1000 characters. If zero, the limit of the git provider is used.
+	MaxCommentLength int `yaml:"maxCommentLength,omitempty"`
+	// Where the results of repository scans are reported: pr-comment (the default) or issue
+	ReportTarget string `yaml:"reportTarget,omitempty"`
+	// The version chosen for fix pull requests, out of the fixed versions of each dependency: minimal (the default), minor or latest
+	UpgradeStrategy string `yaml:"upgradeStrategy,omitempty"`
+	// Glob patterns of the branches for which fix pull requests are created, such as "main" and "release/*". If empty, fix pull requests are created for all the branches.
+	FixPRBranches []string `yaml:"fixPRBranches,omitempty"`
+	// When scanning multiple repositories, a failure in this repository is logged and the scan continues to the next repository.
+	// If nil, defaults to true.
+	ContinueOnError *bool `yaml:"continueOnError,omitempty"`
+	// The maximal number of repositories handled at the same time, when scanning multiple repositories. Their downloads and Git provider requests overlap,
+	// while their audits, which change the working directory of the process, run one at a time. If 0, the repositories are handled one after the other.
+	// The number of workers is shared by all the repositories, so it's read from the first one.
+	MaxRepoWorkers int `yaml:"maxRepoWorkers,omitempty"`
+	// When scanning multiple repositories, the directory to which a summary report of the issues found in all the repositories is written, in markdown and JSON.
+	// A single report is written for the whole run, into the directory set for the first repository.
+	OrgSummaryDir string `yaml:"orgSummaryDir,omitempty"`
+	// When scanning multiple repositories, the name of a repository of the same owner, in which an issue with the summary report is created and updated. Supported only on GitHub.
+	// A single issue is updated for the whole run, in the repository set for the first repository of the config, under the owner of that repository.
+	OrgSummaryIssueRepo string `yaml:"orgSummaryIssueRepo,omitempty"`
+	// Fail the Frogbot task if the Xray scan itself fails, rather than if it found issues.
+	// If nil, defaults to true.
+	FailOnScanError *bool `yaml:"failOnScanError,omitempty"`
+	// Named profiles, which override the severity policy and fail behavior when selected using the --profile flag
+	Profiles map[string]Profile `yaml:"profiles,omitempty"`
+	// Maps the severities to the hex colors of their icons and badges, such as "Critical: '#DD2E44'". Severities which aren't set keep their default colors.
+	SeverityColors map[string]string `yaml:"severityColors,omitempty"`
+	// Report and fail only on vulnerabilities with a known exploit, according to the JFrog research information of Xray
+	OnlyWithExploits bool `yaml:"onlyWithExploits,omitempty"`
+	// The path of a Go template file, which creates the pull request comment out of the scan results instead of the default comment
+	CommentTemplatePath string `yaml:"commentTemplatePath,omitempty"`
+	// When scanning a pull request, skip the run if another Frogbot run is already scanning the same commit, to avoid duplicate comments
+	PreventDuplicateRuns bool `yaml:"preventDuplicateRuns,omitempty"`
+	// Patterns of the paths, relative to the root of the repository, whose working dirs' issues don't fail the scan, such as "examples/" and "testdata/"
+	PathIgnores []string `yaml:"pathIgnores,omitempty"`
+	// Don't scan the working dirs which match the pathIgnores patterns, rather than reporting their issues without failing the scan
+	HidePathIgnoredIssues bool `yaml:"hidePathIgnoredIssues,omitempty"`
+	// The name shown in a header line at the beginning of the pull request comments, to distinguish Frogbot from other bots
+	BotName string `yaml:"botName,omitempty"`
+	// When scanning a pull request, list the direct dependencies specified with version ranges, such as "^1.2.3", in an advisory section of the comment, which doesn't fail the scan
+	WarnUnpinned bool `yaml:"warnUnpinned,omitempty"`
+	// The issues requested from Xray: vulnerabilities, violations of the policies of the watches or the JFrog project, or both.
+	// If empty, the violations are requested if watches or a JFrog project key are configured, and the vulnerabilities otherwise.
+	ScanMode string `yaml:"scanMode,omitempty"`
+	// Post the results comment only if its findings differ from the findings of the newest results comment, rather than on every scan
+	CommentOnlyOnChange bool `yaml:"commentOnlyOnChange,omitempty"`
+	// The command executed after the pull request scan completes, with the path of the scan results JSON file in the JF_SCAN_RESULTS_PATH environment variable
+	PostScanCommand string `yaml:"postScanCommand,omitempty"`
+	// The order of the issues sections of the pull request comment: security, iac and secrets. The sections which aren't listed are hidden.
+	// If empty, the security section is followed by the iac and secrets sections.
+	SectionOrder []string `yaml:"sectionOrder,omitempty"`
+	// The Xray URLs scanned against, in order, if the Xray of the JFrog Platform can't be reached, such as during a maintenance window
+	XrayFailoverUrls []string `yaml:"xrayFailoverUrls,omitempty"`
+	// When scanning a pull request, comment on the line added by the pull request to a manifest, which adds or updates the direct dependency through which each new issue was introduced
+	AnnotateDiff bool `yaml:"annotateDiff,omitempty"`
+	// When the frogbot-config file of the scanned pull request is merged using the --config-from-repo flag, allow it to relax the gating of the pull request,
+	// such as by raising the failSeverityThreshold or ignoring issues. Only the external configuration may set it.
+	RepoConfigCanRelaxGating bool `yaml:"repoConfigCanRelaxGating,omitempty"`
+	// Show in the pull request comment how long ago each new vulnerability was added to the Xray vulnerabilities database.
+	// It's an additional request to Xray, so it's enabled only if set, or if failOnVulnsOlderThanDays is set.
+	ShowVulnerabilitiesAge bool `yaml:"showVulnerabilitiesAge,omitempty"`
+	// Fail the pull request scan if a new vulnerability was added to the Xray vulnerabilities database more than the given number of days ago.
+	// If zero, the age of the vulnerabilities doesn't fail the scan.
+	FailOnVulnsOlderThanDays int `yaml:"failOnVulnsOlderThanDays,omitempty"`
+	// The name of the commit status set on the head commit of the scanned pull requests, such as "frogbot". The status is pending while the pull request is scanned,
+	// and is then set to success or failure, according to whether the issues found fail the scan. If empty, no commit status is set.
+	CommitStatusContext string `yaml:"commitStatusContext,omitempty"`
+	// How the fixes are grouped into fix pull requests: per-dependency (the default) opens a pull request for each vulnerable dependency,
+	// per-ecosystem opens a pull request for the fixes of each package manager, and all opens a single pull request with all the fixes.
+	FixPRGrouping string `yaml:"fixPRGrouping,omitempty"`
+	// The style of the pull request comment: full (the default) adds the issues tables, and status adds a single line with the scan result, the counts of the issues and a link to the CI run
+	CommentStyle string `yaml:"commentStyle,omitempty"`
+	// Allow fix pull requests and remediation commands to upgrade to pre-release fix versions, such as 2.0.0-rc.1 or 1.4-SNAPSHOT.
+	// If false, the pre-release fix versions are skipped, unless all the fix versions are pre-release versions.
+	AllowPrerelease bool `yaml:"allowPrerelease,omitempty"`
+	// Scan the manifests of the git submodules of the repository, declared in its .gitmodules file, as additional projects.
+	// The submodules which aren't checked out, such as the submodules of a downloaded repository archive, are cloned.
+	ScanSubmodules bool `yaml:"scanSubmodules,omitempty"`
+	// The maximal number of fix versions shown in the issues table, starting from the minimal version which fixes the issue. If zero, 3 fix versions are shown.
+	// If negative, all the fix versions are shown. The JSON results always include all the fix versions.
+	MaxFixedVersions int `yaml:"maxFixedVersions,omitempty"`
+	// The scan results the pull request is compared against, to find its new issues. Either target-branch, which scans the target branch, or scan-history,
+	// which compares against the last scan of the source branch which reported no issues, persisted in the scan history. If empty, target-branch is used.
+	NewIssuesBaseline string `yaml:"newIssuesBaseline,omitempty"`
+	// The directory of the local scan history, used by the scan-history baseline. If empty, the scan history is kept under the user cache directory.
+	ScanHistoryDir string `yaml:"scanHistoryDir,omitempty"`
+	// The maximal number of open pull requests scanned by the pull-requests-digest command, from the newest. If 0, up to 20 pull requests are scanned.
+	DigestMaxPullRequests int `yaml:"digestMaxPullRequests,omitempty"`
+	// Scan draft pull requests. By default, draft pull requests are skipped without a comment, since they're a work in progress.
+	ScanDrafts bool `yaml:"scanDrafts,omitempty"`
+	// Write the pull request scan results to the GitHub Actions step summary, which is shown in the summary of the workflow run.
+	// Either with-comment, which adds the pull request comment too, or instead-of-comment, which skips the comment. If empty, no step summary is written.
+	WriteStepSummary string `yaml:"writeStepSummary,omitempty"`
+	// Scan each dir of the repository which includes a dependency manifest, such as package.json or go.mod, as a working dir of the projects with no workingDirs
+	AutoDetectWorkingDirs bool `yaml:"autoDetectWorkingDirs,omitempty"`
+	// Patterns of the dirs skipped by autoDetectWorkingDirs, relative to the root of the repository, in the syntax of the pathIgnores patterns, such as "examples/"
+	AutoDetectExcludes []string `yaml:"autoDetectExcludes,omitempty"`
+	// The reviewers requested on the fix pull requests whose severity isn't in reviewersBySeverity. On GitHub, teams are set as org/team.
+	FixPRReviewers []string `yaml:"fixPRReviewers,omitempty"`
+	// The reviewers requested on the fix pull requests by their severity, which is the highest severity fixed by the pull request, such as Critical: [my-org/security]
+	ReviewersBySeverity map[string][]string `yaml:"reviewersBySeverity,omitempty"`
+	// Vulnerabilities whose highest CVSS score is below minCvss don't fail the scan, in addition to the severity policy
+	CvssPolicy `yaml:",inline"`
+	// Don't open a fix pull request for a dependency whose fix pull request was closed without being merged within the given number of days. If zero, the declined fixes are opened again.
+	FixPRCooldownDays int `yaml:"fixPRCooldownDays,omitempty"`
+	// When scanning a pull request, compare the dependency trees of the source and the target branches, and scan with Xray only the dependencies removed by the pull request
+	// in the target branch, rather than fully scanning it. If the trees of either branch can't be built, both branches are fully scanned.
+	DeltaScan bool `yaml:"deltaScan,omitempty"`
+	// Skip the pull request comment, and log the scan results instead, if the scanned pull request is no longer open, such as when a CI event arrives after the pull request was merged.
+	// If nil, defaults to true.
+	SkipClosedPRs *bool `yaml:"skipClosedPRs,omitempty"`
+	// The severity the issues with an unknown or empty Xray severity are sorted and gated as, such as High, or ignore, which drops them from the results.
+	// If empty, the unknown severity is its own tier, below Low.
+	UnknownSeverityAs string `yaml:"unknownSeverityAs,omitempty"`
+	// The OpenTelemetry collector endpoint of the OTLP/HTTP protocol, such as http://otel-collector:4318, to which the pull request scan results are exported as a trace,
+	// with a span for the scan and a span for each finding. If empty, the results aren't exported.
+	OtelEndpoint string `yaml:"otelEndpoint,omitempty"`
+	// The headers sent with the traces to the OpenTelemetry endpoint, such as the API key of the collector. These headers are sent to the endpoint only,
+	// and the custom headers aren't sent to it.
+	OtelHeaders map[string]string `yaml:"otelHeaders,omitempty"`
+	// The number of attempts to download a repository archive, if the download fails due to a transient error, such as a 5xx response or a timeout.
+	// If zero, 3 attempts are made. All the repositories in the config file must use the same number of attempts.
+	RepoDownloadAttempts int `yaml:"repoDownloadAttempts,omitempty"`
+	// If true, once the results comment was added to the merge request, the next scans reply to its thread with the issues resolved and found since the previous comment,
+	// rather than adding a new results comment. Supported on GitLab.
+	ThreadedUpdates bool `yaml:"threadedUpdates,omitempty"`
+	// The User-Agent header of the requests sent to the Git provider, except for GitLab, and to Xray. If empty, a user agent with the Frogbot version and the repository is used.
+	// All the repositories in the config file must use the same user agent.
+	UserAgent string `yaml:"userAgent,omitempty"`
+	// A message added to the pull request comment when the scan fails, explaining the next steps, such as whom to contact to request an exception.
+	// It isn't added to the comments of the scans which pass.
+	FailureComment string `yaml:"failureComment,omitempty"`
+	// If a working dir of a project can't be scanned, such as due to a failed install command, the working dirs which can be scanned are reported,
+	// and the comment notes the working dirs which couldn't be scanned. The scan then fails, unless failOnPartialScan or failOnScanError is set to false.
+	FailOnPartialScan *bool `yaml:"failOnPartialScan,omitempty"`
+	// If true, the issues table of the pull request comment is rendered with fewer columns, for the narrow displays of mobile review apps.
+	// The severity and the CVE are shown in a single column, and the direct dependencies columns are dropped.
+	CompactTable bool `yaml:"compactTable,omitempty"`
+	// If true, the Xray graph scan results are cached by the hash of the scanned dependency graph, so that scanning an unchanged graph, such as the graph of the
+	// target branch shared by many pull requests, returns the cached results instead of sending a graph scan to Xray.
+	ScanCache bool `yaml:"scanCache,omitempty"`
+	// The directory of the scan cache. If empty, the frogbot/scan-cache directory under the user cache directory is used.
+	ScanCacheDir string `yaml:"scanCacheDir,omitempty"`
+	// The number of hours the cached results are used for. If zero, the results are cached for 24 hours.
+	ScanCacheTtlHours int `yaml:"scanCacheTtlHours,omitempty"`
+	// The maximal number of graph scan results kept in the cache. If zero, 1000 results are kept. The oldest results are removed first.
+	ScanCacheMaxEntries int `yaml:"scanCacheMaxEntries,omitempty"`
+	// If true, the issues annotated by "frogbot:ignore <issue ID> <reason>" comments in the manifests of the working dirs are ignored, and reported with their reasons
+	InlineIgnores bool `yaml:"inlineIgnores,omitempty"`
+}
+
+func (p *Params) ShouldContinueOnError() bool {
+	return p.ContinueOnError == nil || *p.ContinueOnError
+}
+
+func (p *Params) ShouldFailOnScanError() bool {
+	return p.FailOnScanError == nil || *p.FailOnScanError
+}
+
+func (p *Params) ShouldFailOnPartialScan() bool {
+	return p.FailOnPartialScan == nil || *p.FailOnPartialScan
+}
+
+func (p *Params) ShouldSkipClosedPRs() bool {
+	return p.SkipClosedPRs == nil || *p.SkipClosedPRs
+}
+
+// Return the number of repositories to handle at the same time, which is at least 1
+func (fca FrogbotConfigAggregator) getMaxRepoWorkers() int {
+	if len(fca) == 0 || fca[0].MaxRepoWorkers < 1 {
+		return 1
+	}
+	return fca[0].MaxRepoWorkers
+}
+
+func (p *Params) validateReportTarget() error {
+	switch p.ReportTarget {
+	case "", PullRequestCommentReportTarget, IssueReportTarget:
+		return nil
+	case DiscussionReportTarget:
+		return errors.New(errDiscussionReportTarget)
+	default:
+		return fmt.Errorf(errInvalidReportTarget, p.ReportTarget)
+	}
+}
+
+func (p *Params) validateUpgradeStrategy() error {
+	switch p.UpgradeStrategy {
+	case "", MinimalUpgradeStrategy, MinorUpgradeStrategy, LatestUpgradeStrategy:
+		return nil
+	default:
+		return fmt.Errorf(errInvalidUpgradeStrategy, p.UpgradeStrategy)
+	}
+}
+
+func (p *Params) validateFixPRGrouping() error {
+	switch p.FixPRGrouping {
+	case "", PerDependencyFixPRGrouping, PerEcosystemFixPRGrouping, AllFixPRGrouping:
+		return nil
+	default:
+		return fmt.Errorf(errInvalidFixPRGrouping, p.FixPRGrouping)
+	}
+}
+
+func (p *Params) validateCommentStyle() error {
+	switch p.CommentStyle {
+	case "", FullCommentStyle, StatusCommentStyle:
+		return nil
+	default:
+		return fmt.Errorf(errInvalidCommentStyle, p.CommentStyle)
+	}
+}
+
+func (p *Params) validateUnknownSeverityAs() error {
+	if p.UnknownSeverityAs != "" && p.UnknownSeverityAs != IgnoreUnknownSeverity && GetSeverityNumValue(p.UnknownSeverityAs) == 0 {
+		return fmt.Errorf(errInvalidUnknownSeverityAs, p.UnknownSeverityAs)
+	}
+	return nil
+}
+
+func (p *Params) validateWriteStepSummary() error {
+	switch p.WriteStepSummary {
+	case "", WithCommentStepSummary, InsteadOfCommentStepSummary:
+		return nil
+	default:
+		return fmt.Errorf(errInvalidWriteStepSummary, p.WriteStepSummary)
+	}
+}
+
+func (p *Params) validateNewIssuesBaseline() error {
+	switch p.NewIssuesBaseline {
+	case "", TargetBranchBaseline, ScanHistoryBaseline:
+		return nil
+	default:
+		return fmt.Errorf(errInvalidNewIssuesBaseline, p.NewIssuesBaseline)
+	}
+}
+
+func (p *Params) validateScanMode() error {
+	switch p.ScanMode {
+	case "", VulnerabilitiesScanMode:
+		return nil
+	case ViolationsScanMode, BothScanMode:
+		if len(p.Watches) > 0 || p.JFrogProjectKey != "" {
+			return nil
+		}
+		// Without watches in the repository, each project must set its own watches
+		for _, project := range p.Projects {
+			if len(project.Watches) == 0 {
+				return fmt.Errorf(errScanModeWithoutPolicy, p.ScanMode)
+			}
+		}
+		if len(p.Projects) == 0 {
+			return fmt.Errorf(errScanModeWithoutPolicy, p.ScanMode)
+		}
+		return nil
+	default:
+		return fmt.Errorf(errInvalidScanMode, p.ScanMode)
+	}
+}
+
+func (p *Params) validateXrayFailoverUrls() error {
+	for _, xrayUrl := range p.XrayFailoverUrls {
+		parsedUrl, err := url.Parse(xrayUrl)
+		if err != nil || (parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https") || parsedUrl.Host == "" {
+			return fmt.Errorf(errInvalidXrayFailoverUrl, xrayUrl)
+		}
+	}
+	return nil
+}
+
+func (p *Params) validateFailOnVulnsOlderThanDays() error {
+	if p.FailOnVulnsOlderThanDays < 0 {
+		return fmt.Errorf(errInvalidVulnsAgeDays, p.FailOnVulnsOlderThanDays)
+	}
+	return nil
+}
+
+func (p *Params) validateOtelEndpoint() error {
+	if p.OtelEndpoint == "" {
+		return nil
+	}
+	parsedUrl, err := url.Parse(p.OtelEndpoint)
+	if err != nil || (parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https") || parsedUrl.Host == "" {
+		return fmt.Errorf(errInvalidOtelEndpoint, p.OtelEndpoint)
+	}
+	return nil
+}
+
+func (p *Params) validateOtelHeaders() error {
+	for name, value := range p.OtelHeaders {
+		if !httpguts.ValidHeaderFieldName(name) || !httpguts.ValidHeaderFieldValue(value) {
+			return fmt.Errorf(errInvalidOtelHeader, name)
+		}
+	}
+	return nil
+}
+
+// DefaultSectionOrder is the order of the sections of the pull request comment, if sectionOrder isn't set
+var DefaultSectionOrder = []string{SecuritySection, IacSection, SecretsSection}
+
+func (p *Params) validateSectionOrder() error {
+	listed := make(map[string]bool)
+	for _, section := range p.SectionOrder {
+		switch section {
+		case SecuritySection, IacSection, SecretsSection:
+		default:
+			return fmt.Errorf(errInvalidSection, section)
+		}
+		if listed[section] {
+			return fmt.Errorf(errDuplicateSection, section)
+		}
+		listed[section] = true
+	}
+	return nil
+}
+
+// GetSectionOrder returns the sections of the pull request comment in the configured order, or in the default order if sectionOrder isn't set
+func (p *Params) GetSectionOrder() []string {
+	if len(p.SectionOrder) == 0 {
+		return DefaultSectionOrder
+	}
+	return p.SectionOrder
+}
+
+func (p *Params) validateFixPRBranches() error {
+	for _, pattern := range p.FixPRBranches {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf(errInvalidFixPRBranches, pattern)
+		}
+	}
+	return nil
+}
+
+// IsFixPRBranch returns true if fix pull requests should be created for the given branch, according to the fixPRBranches patterns
+func (p *Params) IsFixPRBranch(branch string) bool {
+	if len(p.FixPRBranches) == 0 {
+		return true
+	}
+	for _, pattern := range p.FixPRBranches {
+		if matched, _ := path.Match(pattern, branch); matched {
+			return true
+		}
+	}
+	return false
 }
 
 type Project struct {
@@ -46,14 +488,194 @@ type Project struct {
 	PipRequirementsFile string   `yaml:"pipRequirementsFile,omitempty"`
 	WorkingDirs         []string `yaml:"workingDirs,omitempty"`
 	UseWrapper          bool     `yaml:"useWrapper,omitempty"`
-	InstallCommandName  string
-	InstallCommandArgs  []string
+	// Xray Watches of this project. If empty, the watches of the repository are used.
+	Watches []string `yaml:"watches,omitempty"`
+	// Issues ignored in this project, in addition to the issues ignored in the scan section
+	IgnoredIssues []string `yaml:"ignoredIssues,omitempty"`
+	// The maximal number of modules scanned in a single Xray graph scan. If zero, the batch size of the scan section is used.
+	ScanBatchSize int `yaml:"scanBatchSize,omitempty"`
+	// Scan the Helm charts and Kubernetes manifests in the working directories for misconfigurations, using JFrog Advanced Security
+	ScanIaC bool `yaml:"scanIaC,omitempty"`
+	// The ecosystems scanned in this project, such as go and npm. If empty, all the detected ecosystems are scanned.
+	Ecosystems []string `yaml:"ecosystems,omitempty"`
+	// The source of the direct dependencies of the issues: manifest, for the dependencies declared in the manifests, or graph, for the children of the roots of the dependency graph.
+	// If empty, the children of the roots of the dependency graph are used.
+	DirectDependencies string `yaml:"directDependencies,omitempty"`
+	// The severity policy of this project. Unset values are inherited from the scan section.
+	SeverityPolicy `yaml:",inline"`
+	// The severity policies of the ecosystems of the scan section
+	EcosystemPolicies  map[string]SeverityPolicy `yaml:"-"`
+	InstallCommandName string
+	InstallCommandArgs []string
+	// True if the working dirs of this project match the pathIgnores patterns, so its issues don't fail the scan
+	PathIgnored bool `yaml:"-"`
+	// The pathIgnores patterns of the repository, which are matched against the dirs of the modules and the files in which the issues of this project are found
+	PathIgnores []string `yaml:"-"`
+	// The Xray failover URLs of the repository
+	XrayFailoverUrls []string `yaml:"-"`
+	// The dependencies ignored in the scan section
+	IgnoredDependencies []string `yaml:"-"`
+	// The git submodule scanned by this project, if it was added by scanSubmodules
+	Submodule *Submodule `yaml:"-"`
+	// True if the working dirs of this project are detected by the dependency manifests in the repository, since it has no workingDirs
+	AutoDetectWorkingDirs bool `yaml:"-"`
+	// The patterns of the dirs skipped by the working dirs detection
+	AutoDetectExcludes []string `yaml:"-"`
+	// The cache of the Xray graph scan results, or nil if scanCache isn't set
+	ScanCache *ScanCache `yaml:"-"`
+	// True if the issues annotated by inline ignores in the manifests of the working dirs are ignored
+	InlineIgnores bool `yaml:"-"`
+}
+
+// expandProjects configures each project as an independent scan unit, which inherits the unset Xray watches, scan batch size and severity policy from the repository.
+// The ignored issues of the repository are added to the ignored issues of each project, and the ignored dependencies apply to all the projects.
+func (p *Params) expandProjects() error {
+	if err := p.validateSeverities(); err != nil {
+		return err
+	}
+	if err := p.validateEcosystemPolicies(); err != nil {
+		return err
+	}
+	if err := validateIgnoredIssues(p.IgnoredIssues); err != nil {
+		return err
+	}
+	if err := validateIgnoredDependencies(p.IgnoredDependencies); err != nil {
+		return err
+	}
+	scanCache, err := NewScanCache(p)
+	if err != nil {
+		return err
+	}
+	for index := range p.Projects {
+		project := &p.Projects[index]
+		if err := project.validateSeverities(); err != nil {
+			return err
+		}
+		if err := validateIgnoredIssues(project.IgnoredIssues); err != nil {
+			return err
+		}
+		if err := project.validateEcosystems(); err != nil {
+			return err
+		}
+		if err := project.validateDirectDependencies(); err != nil {
+			return err
+		}
+		// Copy the ignored issues, to avoid sharing the underlying array of the config data
+		project.IgnoredIssues = append(append([]string{}, project.IgnoredIssues...), p.IgnoredIssues...)
+		if len(project.Watches) == 0 {
+			project.Watches = p.Watches
+		}
+		if project.ScanBatchSize == 0 {
+			project.ScanBatchSize = p.ScanBatchSize
+		}
+		mergeDefaults(reflect.ValueOf(&project.SeverityPolicy).Elem(), reflect.ValueOf(p.SeverityPolicy))
+		project.EcosystemPolicies = p.EcosystemPolicies
+		project.XrayFailoverUrls = p.XrayFailoverUrls
+		project.IgnoredDependencies = p.IgnoredDependencies
+		project.ScanCache = scanCache
+		project.InlineIgnores = p.InlineIgnores
+		if p.AutoDetectWorkingDirs && len(project.WorkingDirs) == 0 {
+			project.AutoDetectWorkingDirs = true
+			project.AutoDetectExcludes = p.AutoDetectExcludes
+		}
+	}
+	p.splitPathIgnoredProjects()
+	return nil
+}
+
+func (p *Project) validateEcosystems() error {
+	for _, ecosystem := range p.Ecosystems {
+		if !isSupportedEcosystem(ecosystem) {
+			return fmt.Errorf(errInvalidEcosystem, ecosystem, formatSupportedEcosystems())
+		}
+	}
+	return nil
+}
+
+func (p *Project) validateDirectDependencies() error {
+	switch p.DirectDependencies {
+	case "", ManifestDirectDependencies, GraphDirectDependencies:
+		return nil
+	}
+	return fmt.Errorf(errInvalidDirectDependencies, p.DirectDependencies)
+}
+
+// The ecosystems whose dependencies are audited, sorted by name. Docker images aren't scanned by the audit.
+func getSupportedEcosystems() (ecosystems []string) {
+	for _, tech := range coreutils.GetAllTechnologiesList() {
+		if tech != coreutils.Docker {
+			ecosystems = append(ecosystems, tech.ToString())
+		}
+	}
+	sort.Strings(ecosystems)
+	return
+}
+
+func isSupportedEcosystem(ecosystem string) bool {
+	for _, supportedEcosystem := range getSupportedEcosystems() {
+		if ecosystem == supportedEcosystem {
+			return true
+		}
+	}
+	return false
+}
+
+// The list of the supported ecosystems in the errors of invalid ecosystems, such as "dotnet, go and maven"
+func formatSupportedEcosystems() string {
+	ecosystems := getSupportedEcosystems()
+	if len(ecosystems) < 2 {
+		return strings.Join(ecosystems, "")
+	}
+	return strings.Join(ecosystems[:len(ecosystems)-1], ", ") + " and " + ecosystems[len(ecosystems)-1]
+}
+
+// IsEcosystemScanned returns true if the ecosystem of the technology is scanned in this project
+func (p *Project) IsEcosystemScanned(tech coreutils.Technology) bool {
+	if len(p.Ecosystems) == 0 {
+		return true
+	}
+	for _, ecosystem := range p.Ecosystems {
+		if ecosystem == tech.ToString() {
+			return true
+		}
+	}
+	return false
 }
 
 type Scan struct {
-	IncludeAllVulnerabilities bool      `yaml:"includeAllVulnerabilities,omitempty"`
-	FailOnSecurityIssues      *bool     `yaml:"failOnSecurityIssues,omitempty"`
-	Projects                  []Project `yaml:"projects,omitempty"`
+	IncludeAllVulnerabilities     bool  `yaml:"includeAllVulnerabilities,omitempty"`
+	FailOnSecurityIssues          *bool `yaml:"failOnSecurityIssues,omitempty"`
+	PullRequestTitleSeverityBadge bool  `yaml:"pullRequestTitleSeverityBadge,omitempty"`
+	SummarizeUnchangedResults     bool  `yaml:"summarizeUnchangedResults,omitempty"`
+	// Add links to the Xray scans to the pull request comment, or the scan IDs if Xray doesn't return links
+	ShowXrayScanLink bool `yaml:"showXrayScanLink,omitempty"`
+	// Add the command which upgrades the impacted dependency to its fix version, such as "go get pkg@v1.2.3", for each fixable issue in the pull request comment
+	ShowRemediationCommands bool `yaml:"showRemediationCommands,omitempty"`
+	// Show the upgrade of the direct dependency which fixes each transitive npm issue, such as "upgrade A to 2.0.0 to get B 1.2.3", in the pull request comment
+	ShowFixChains bool `yaml:"showFixChains,omitempty"`
+	// The comment added to pull requests with no issues, instead of the default comment.
+	// The ${COMMIT_SHA} and ${TIMESTAMP} placeholders are replaced with the scanned commit and the time of the scan.
+	CleanScanMessage string `yaml:"cleanScanMessage,omitempty"`
+	// Don't add a comment to pull requests with no issues
+	SuppressCleanComment bool `yaml:"suppressCleanComment,omitempty"`
+	// Scan the lines added by pull requests for secrets, such as access tokens and private keys
+	ScanSecrets bool `yaml:"scanSecrets,omitempty"`
+	// When scanning a pull request, scan only the modules whose manifests were changed by the pull request, if their dependencies can be resolved separately
+	ScanChangedOnly bool `yaml:"scanChangedOnly,omitempty"`
+	// Post the High and Critical issues and the Low and Medium issues in two separate comments, with the Low and Medium issues collapsed
+	SplitCommentsBySeverity bool `yaml:"splitCommentsBySeverity,omitempty"`
+	// The maximal number of modules of the same technology scanned in a single Xray graph scan. If zero or one, each module is scanned separately.
+	ScanBatchSize int `yaml:"scanBatchSize,omitempty"`
+	// CVE IDs or Xray issue IDs excluded from the results, optionally with an expiry date, such as "CVE-2022-24450 until 2024-06-01"
+	IgnoredIssues []string `yaml:"ignoredIssues,omitempty"`
+	// Dependencies whose issues are all excluded from the results, optionally only in the versions matching a semver range, such as "lodash <4.17.21"
+	IgnoredDependencies []string `yaml:"ignoredDependencies,omitempty"`
+	// The number of days before the expiry of an ignored issue, in which a warning is added to the pull request comment. If zero, defaults to 14.
+	IgnoreExpiryWarningDays int `yaml:"ignoreExpiryWarningDays,omitempty"`
+	SeverityPolicy          `yaml:",inline"`
+	// The severity policies of the issues of specific ecosystems, such as npm and go, in all the projects. Unset values are inherited from the severity policy of each project.
+	EcosystemPolicies map[string]SeverityPolicy `yaml:"ecosystemPolicies,omitempty"`
+	Projects          []Project                 `yaml:"projects,omitempty"`
 }
 
 type JFrogPlatform struct {
@@ -71,13 +693,48 @@ type Git struct {
 	ApiEndpoint   string
 	Username      string
 	PullRequestID int
+	// If set, Frogbot authenticates as a GitHub App, and Token holds the current installation access token of the app
+	GitHubApp *GitHubAppAuth `yaml:"-"`
+}
+
+// NewVcsClient creates a client of the git provider, with the provider details of the given git params.
+// When authenticating as a GitHub App, the client uses the current installation access token of the app, which is refreshed if it's about to expire.
+func NewVcsClient(gitParams *Git) (vcsclient.VcsClient, error) {
+	if gitParams.GitHubApp != nil {
+		token, err := gitParams.GitHubApp.Token()
+		if err != nil {
+			return nil, err
+		}
+		gitParams.Token = token
+	}
+	return vcsclient.
+		NewClientBuilder(gitParams.GitProvider).
+		ApiEndpoint(gitParams.ApiEndpoint).
+		Token(gitParams.Token).
+		Project(gitParams.GitProject).
+		Logger(log.GetLogger()).
+		Username(gitParams.Username).
+		Build()
 }
 
 func GetParamsAndClient() (configAggregator FrogbotConfigAggregator, server *coreconfig.ServerDetails, client vcsclient.VcsClient, err error) {
+	// The CA certificates and the proxy must be configured before sending any request
+	for _, configure := range getEnvTransportSteps() {
+		if err = configure(); err != nil {
+			return nil, nil, nil, err
+		}
+	}
 	server, gitParams, err := extractEnvParams()
 	if err != nil {
 		return nil, nil, nil, err
 	}
+	ConfigureClientsHosts(&gitParams, server)
+	if err = configureLocalConfigTransport(); err != nil {
+		return nil, nil, nil, err
+	}
+	if err = ConfigureUserAgent(getTrimmedEnv(UserAgentEnv), gitParams.RepoOwner, gitParams.RepoName); err != nil {
+		return nil, nil, nil, err
+	}
 	defer func() {
 		e := SanitizeEnv()
 		if err == nil {
@@ -87,96 +744,446 @@ func GetParamsAndClient() (configAggregator FrogbotConfigAggregator, server *cor
 		}
 	}()
 
-	client, err = vcsclient.
-		NewClientBuilder(gitParams.GitProvider).
-		ApiEndpoint(gitParams.ApiEndpoint).
-		Token(gitParams.Token).
-		Project(gitParams.GitProject).
-		Logger(log.GetLogger()).
-		Username(gitParams.Username).
-		Build()
+	client, err = NewVcsClient(&gitParams)
 	if err != nil {
 		return nil, nil, nil, err
 	}
+	configAggregator, err = loadConfigAggregator(client, gitParams, server)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return configAggregator, server, client, nil
+}
 
+// DoctorParams are the params loaded by the doctor command. Unlike GetParamsAndClient, which stops at the first error,
+// every step is run, unless the params it depends on couldn't be loaded, so that all the problems are reported at once.
+type DoctorParams struct {
+	// Nil if the JFrog Platform params couldn't be loaded
+	Server *coreconfig.ServerDetails
+	// Nil if the Git provider params couldn't be loaded
+	Client           vcsclient.VcsClient
+	ConfigAggregator FrogbotConfigAggregator
+	// The errors of configuring the CA certificates, the proxy, the custom headers, the temp directory and the user agent from the environment
+	TransportErrs []error
+	ServerErr     error
+	GitErr        error
+	// The error of loading the frogbot-config file, which isn't loaded if the Git provider params couldn't be loaded
+	ConfigErr error
+}
+
+func GetDoctorParams() (params DoctorParams) {
+	for _, configure := range getEnvTransportSteps() {
+		if err := configure(); err != nil {
+			params.TransportErrs = append(params.TransportErrs, err)
+		}
+	}
+	defer func() {
+		if err := SanitizeEnv(); err != nil {
+			log.Warn(err.Error())
+		}
+	}()
+	server, err := extractJFrogParamsFromEnv()
+	if params.ServerErr = err; err == nil {
+		params.Server = &server
+	}
+	gitParams, err := extractGitParamsFromEnv()
+	if params.GitErr = err; err != nil {
+		ConfigureClientsHosts(nil, params.Server)
+		return
+	}
+	ConfigureClientsHosts(&gitParams, params.Server)
+	if err = configureLocalConfigTransport(); err != nil {
+		params.TransportErrs = append(params.TransportErrs, err)
+	}
+	if err = ConfigureUserAgent(getTrimmedEnv(UserAgentEnv), gitParams.RepoOwner, gitParams.RepoName); err != nil {
+		params.TransportErrs = append(params.TransportErrs, err)
+	}
+	if params.Client, params.GitErr = NewVcsClient(&gitParams); params.GitErr != nil {
+		params.Client = nil
+		return
+	}
+	configServer := params.Server
+	if configServer == nil {
+		configServer = &coreconfig.ServerDetails{}
+	}
+	params.ConfigAggregator, params.ConfigErr = loadConfigAggregator(params.Client, gitParams, configServer)
+	return
+}
+
+// The steps which configure the transport from the environment variables, before any request is sent
+func getEnvTransportSteps() []func() error {
+	return []func() error{
+		func() error { return ConfigureCaCert(getTrimmedEnv(CaCertPathEnv)) },
+		func() error { return ConfigureProxy(getTrimmedEnv(ProxyEnv)) },
+		configureCustomHeadersFromEnv,
+		func() error { return ConfigureTempDir(getTrimmedEnv(TempDirEnv)) },
+		configureRepoDownloadAttemptsFromEnv,
+	}
+}
+
+// Load the frogbot-config file, or generate the config from the environment variables if the file is missing,
+// and configure the transport settings of the file, such as its proxy, CA certificates and custom headers
+func loadConfigAggregator(client vcsclient.VcsClient, gitParams Git, server *coreconfig.ServerDetails) (FrogbotConfigAggregator, error) {
 	configData, err := getFrogbotConfig(client)
 	// If the error is due to missing configuration, try to generate an environment variable-based config aggregator.
 	if _, missingConfigErr := err.(*ErrMissingConfig); missingConfigErr {
 		log.Debug("getFrogbotConfig failed with:", err.Error())
 		// If no config file is used, the repo name must be set as a part of the envs.
 		if gitParams.RepoName == "" {
-			return nil, nil, nil, &ErrMissingEnv{GitRepoEnv}
+			return nil, &ErrMissingEnv{GitRepoEnv}
+		}
+		configData, err = generateConfigAggregatorFromEnv(&gitParams, server)
+		if err != nil {
+			return nil, err
+		}
+		return *configData, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	configAggregator, err := NewConfigAggregator(configData, gitParams, server, true)
+	if err != nil {
+		return nil, err
+	}
+	proxy, err := getConfiguredProxy(configAggregator)
+	if err != nil {
+		return nil, err
+	}
+	if proxy != "" {
+		if err = ConfigureProxy(proxy); err != nil {
+			return nil, err
+		}
+	}
+	caCertPath, err := getConfiguredCaCert(configAggregator)
+	if err != nil {
+		return nil, err
+	}
+	if err = ConfigureCaCert(caCertPath); err != nil {
+		return nil, err
+	}
+	customHeaders, err := getConfiguredCustomHeaders(configAggregator)
+	if err != nil {
+		return nil, err
+	}
+	if err = ConfigureCustomHeaders(customHeaders); err != nil {
+		return nil, err
+	}
+	repoDownloadAttempts, err := getConfiguredRepoDownloadAttempts(configAggregator)
+	if err != nil {
+		return nil, err
+	}
+	if repoDownloadAttempts != 0 {
+		ConfigureRepoDownloadAttempts(repoDownloadAttempts)
+	}
+	userAgent, err := getConfiguredUserAgent(configAggregator)
+	if err != nil {
+		return nil, err
+	}
+	if userAgent == "" {
+		// The user agent set in the environment is kept, if the config file doesn't set it
+		userAgent = getTrimmedEnv(UserAgentEnv)
+	}
+	repoOwner, repoName := getUserAgentRepository(configAggregator, &gitParams)
+	if err = ConfigureUserAgent(userAgent, repoOwner, repoName); err != nil {
+		return nil, err
+	}
+	tempDir, err := getConfiguredTempDir(configAggregator)
+	if err != nil {
+		return nil, err
+	}
+	return configAggregator, ConfigureTempDir(tempDir)
+}
+
+// GetParamsWithoutVcs returns the configuration for commands that scan the local file system only, such as the local directory scan.
+// The configuration is generated from the environment variables, and the Git provider environment variables are not required.
+func GetParamsWithoutVcs() (configAggregator FrogbotConfigAggregator, server *coreconfig.ServerDetails, err error) {
+	if err = ConfigureCaCert(getTrimmedEnv(CaCertPathEnv)); err != nil {
+		return nil, nil, err
+	}
+	if err = ConfigureProxy(getTrimmedEnv(ProxyEnv)); err != nil {
+		return nil, nil, err
+	}
+	if err = configureCustomHeadersFromEnv(); err != nil {
+		return nil, nil, err
+	}
+	if err = ConfigureUserAgent(getTrimmedEnv(UserAgentEnv), "", ""); err != nil {
+		return nil, nil, err
+	}
+	if err = ConfigureTempDir(getTrimmedEnv(TempDirEnv)); err != nil {
+		return nil, nil, err
+	}
+	jfrogServer, err := extractJFrogParamsFromEnv()
+	if err != nil {
+		return nil, nil, err
+	}
+	ConfigureClientsHosts(nil, &jfrogServer)
+	defer func() {
+		e := SanitizeEnv()
+		if err == nil {
+			err = e
+		} else if e != nil {
+			err = fmt.Errorf("%s\n%s", err.Error(), e.Error())
+		}
+	}()
+	configData, err := generateConfigAggregatorFromEnv(&Git{}, &jfrogServer)
+	if err != nil {
+		return nil, nil, err
+	}
+	return *configData, &jfrogServer, nil
+}
+
+// The CA certificates and the custom headers of the frogbot-config file may be required by the request which downloads the file itself,
+// and the Xray client reads the CA certificates only once, on its first TLS connection. They're therefore configured before any request is sent,
+// if the file exists in the file system, such as when the repository is checked out by the CI job.
+// An invalid or missing file is reported when the config is loaded.
+func configureLocalConfigTransport() error {
+	localConfigPaths := configPaths
+	if len(localConfigPaths) == 0 {
+		localConfigPaths = []string{osFrogbotConfigPath}
+	}
+	configData, err := ReadConfigFromFileSystem(localConfigPaths...)
+	if err != nil || configData == nil {
+		return nil
+	}
+	repositories, defaults, err := splitGlobalDefaults(*configData)
+	if err != nil {
+		return nil
+	}
+	for index := range repositories {
+		repositories[index].mergeParamsDefaults(defaults)
+	}
+	caCertPath, err := getConfiguredCaCert(repositories)
+	if err != nil {
+		return err
+	}
+	if err = ConfigureCaCert(caCertPath); err != nil {
+		return err
+	}
+	customHeaders, err := getConfiguredCustomHeaders(repositories)
+	if err != nil {
+		return err
+	}
+	return ConfigureCustomHeaders(customHeaders)
+}
+
+// The getFrogbotConfig method retrieves the frogbot-config.yml file.
+// The frogbot-config.yml is read from the target repository.
+// It is possible that reading from the target repository might fail if either the JF_GIT_REPO or the JF_GIT_OWNER env vars are missing, or if the REST API returned a different status code than 200.
+// If reading from the target fails, it reads from the current working directory instead.
+func getFrogbotConfig(client vcsclient.VcsClient) (configData *FrogbotConfigAggregator, err error) {
+	// The config files set using the --config flag are read from the file system only, and are required to exist
+	if len(configPaths) > 0 {
+		if configData, err = ReadConfigFromFileSystem(configPaths...); err != nil {
+			return nil, fmt.Errorf("failed to read the frogbot-config files set using the --config flag: %s", err.Error())
+		}
+		return configData, nil
+	}
+	var targetConfigContent []byte
+	targetConfigContent, err = downloadConfigFromTarget(client)
+	_, missingConfigErr := err.(*ErrMissingConfig)
+	if err != nil && !missingConfigErr {
+		return nil, err
+	}
+	if targetConfigContent != nil {
+		if err = yaml.Unmarshal(targetConfigContent, &configData); err != nil {
+			return nil, err
+		}
+		if configData != nil {
+			if err = setConfigParamsPaths(targetConfigContent, *configData); err != nil {
+				return nil, err
+			}
+		}
+	}
+	// Read the config from the current working dir, if reading from the target branch failed
+	if targetConfigContent == nil && err == nil {
+		configData, err = ReadConfigFromFileSystem(osFrogbotConfigPath)
+	}
+
+	return configData, err
+}
+
+func NewConfigAggregator(configData *FrogbotConfigAggregator, gitParams Git, server *coreconfig.ServerDetails, failOnSecurityIssues bool) (FrogbotConfigAggregator, error) {
+	var newConfigAggregator FrogbotConfigAggregator
+	repositories, defaults, err := splitGlobalDefaults(*configData)
+	if err != nil {
+		return nil, err
+	}
+	for _, config := range repositories {
+		config.mergeParamsDefaults(defaults)
+		// The overrides are applied before the validations and before the projects inherit the params of the repository
+		if err = config.applyConfigOverrides(configOverrides); err != nil {
+			return nil, err
+		}
+		// In case the projects property in the frogbot-config.yml file is missing, we generate an empty one to work on the default projects settings.
+		if config.Projects == nil {
+			config.Projects = []Project{{WorkingDirs: []string{RootDir}}}
+		} else {
+			// Copy the projects, to keep the config data unchanged when the projects are expanded
+			config.Projects = append([]Project{}, config.Projects...)
+		}
+		for projectIndex, project := range config.Projects {
+			SetProjectInstallCommand(project.InstallCommand, &config.Projects[projectIndex])
+		}
+		if config.RepoName == "" {
+			return nil, errors.New(errMissingRepoName)
+		}
+		gitParams.RepoName = config.RepoName
+		if gitParams.GitProvider == vcsutils.GitLab {
+			gitParams.RepoName = normalizeGitLabNamespace(config.RepoName)
+		}
+		if config.Branches != nil {
+			gitParams.Branches = config.Branches
+		}
+		if config.FailOnSecurityIssues == nil {
+			config.FailOnSecurityIssues = &failOnSecurityIssues
+		}
+		if err = config.validateReportTarget(); err != nil {
+			return nil, err
+		}
+		if err = config.validateUpgradeStrategy(); err != nil {
+			return nil, err
+		}
+		if err = config.validateFixPRGrouping(); err != nil {
+			return nil, err
+		}
+		if err = config.validateCommentStyle(); err != nil {
+			return nil, err
+		}
+		if err = config.validateNewIssuesBaseline(); err != nil {
+			return nil, err
+		}
+		if err = config.validateUnknownSeverityAs(); err != nil {
+			return nil, err
+		}
+		if err = config.validateWriteStepSummary(); err != nil {
+			return nil, err
+		}
+		if err = config.validateXrayFailoverUrls(); err != nil {
+			return nil, err
+		}
+		if err = config.validateOtelEndpoint(); err != nil {
+			return nil, err
+		}
+		if err = config.validateOtelHeaders(); err != nil {
+			return nil, err
+		}
+		if err = config.validateFailOnVulnsOlderThanDays(); err != nil {
+			return nil, err
+		}
+		if err = config.validateRepoDownloadAttempts(); err != nil {
+			return nil, err
+		}
+		if err = config.validateScanCache(); err != nil {
+			return nil, err
+		}
+		if err = config.validateMaxCommentLength(); err != nil {
+			return nil, err
+		}
+		if err = config.validateSectionOrder(); err != nil {
+			return nil, err
+		}
+		if err = config.validateScanMode(); err != nil {
+			return nil, err
+		}
+		if err = config.validateFixPRBranches(); err != nil {
+			return nil, err
+		}
+		if err = config.validatePathIgnores(); err != nil {
+			return nil, err
+		}
+		if err = config.validateAutoDetectExcludes(); err != nil {
+			return nil, err
+		}
+		if err = config.validateSeverityColors(); err != nil {
+			return nil, err
 		}
-		configData, err = generateConfigAggregatorFromEnv(&gitParams, server)
+		if config.ReviewersBySeverity, err = config.getTitledReviewersBySeverity(); err != nil {
+			return nil, err
+		}
+		if err = config.validateMinCvss(); err != nil {
+			return nil, err
+		}
+		commentTemplate, err := config.loadCommentTemplate()
 		if err != nil {
-			return nil, nil, nil, err
+			return nil, err
 		}
-		return *configData, server, client, err
-	}
-	if err != nil {
-		return nil, nil, nil, err
-	}
-
-	configAggregator, err = NewConfigAggregator(configData, gitParams, server, true)
-	if err != nil {
-		return nil, nil, nil, err
+		if err = config.expandProjects(); err != nil {
+			return nil, err
+		}
+		if err = config.applyProfile(getSelectedProfile()); err != nil {
+			return nil, err
+		}
+		config.Git = gitParams
+		newConfigAggregator = append(newConfigAggregator, FrogbotRepoConfig{
+			OutputWriter:    GetCompatibleOutputWriter(gitParams.GitProvider, config.SeverityColors, config.MaxFixedVersions, config.CompactTable),
+			Server:          *server,
+			Params:          config.Params,
+			CommentTemplate: commentTemplate,
+		})
 	}
-
-	return configAggregator, server, client, err
+	return newConfigAggregator, nil
 }
 
-// The getFrogbotConfig method retrieves the frogbot-config.yml file.
-// The frogbot-config.yml is read from the target repository.
-// It is possible that reading from the target repository might fail if either the JF_GIT_REPO or the JF_GIT_OWNER env vars are missing, or if the REST API returned a different status code than 200.
-// If reading from the target fails, it reads from the current working directory instead.
-func getFrogbotConfig(client vcsclient.VcsClient) (configData *FrogbotConfigAggregator, err error) {
-	var targetConfigContent []byte
-	targetConfigContent, err = downloadConfigFromTarget(client)
-	_, missingConfigErr := err.(*ErrMissingConfig)
-	if err != nil && !missingConfigErr {
-		return nil, err
-	}
-	if targetConfigContent != nil {
-		if err = yaml.Unmarshal(targetConfigContent, &configData); err != nil {
-			return nil, err
+// splitGlobalDefaults separates the "defaults" entry of the config file from the repositories entries.
+func splitGlobalDefaults(configData FrogbotConfigAggregator) (repositories FrogbotConfigAggregator, defaults *Params, err error) {
+	for _, config := range configData {
+		if config.Defaults == nil {
+			repositories = append(repositories, config)
+			continue
 		}
+		if defaults != nil {
+			return nil, nil, errors.New(errMultipleDefaults)
+		}
+		defaults = config.Defaults
 	}
-	// Read the config from the current working dir, if reading from the target branch failed
-	if targetConfigContent == nil && err == nil {
-		configData, err = ReadConfigFromFileSystem(osFrogbotConfigPath)
-	}
+	return
+}
 
-	return configData, err
+// mergeDefaults recursively fills the unset fields of target with the values of defaults. The override semantics are:
+// Scalars - the repository value is kept unless it is the zero value (for example, an empty string).
+// Pointers - the repository value is kept unless it is nil. That is why booleans which default to true, such as failOnSecurityIssues, are pointers.
+// Slices and maps - the repository value replaces the default entirely, unless it is empty. Slices are never concatenated.
+func mergeDefaults(target, defaults reflect.Value) {
+	mergeDefaultsAt(target, defaults, "", nil)
 }
 
-func NewConfigAggregator(configData *FrogbotConfigAggregator, gitParams Git, server *coreconfig.ServerDetails, failOnSecurityIssues bool) (FrogbotConfigAggregator, error) {
-	var newConfigAggregator FrogbotConfigAggregator
-	for _, config := range *configData {
-		// In case the projects property in the frogbot-config.yml file is missing, we generate an empty one to work on the default projects settings.
-		if config.Projects == nil {
-			config.Projects = []Project{{WorkingDirs: []string{RootDir}}}
-		}
-		for projectIndex, project := range config.Projects {
-			SetProjectInstallCommand(project.InstallCommand, &config.Projects[projectIndex])
+// mergeDefaultsAt is mergeDefaults, except that the scalars and pointers at the yaml paths in setPaths are kept even if they are zero,
+// so that a repository which sets a param to false or 0 in the config file overrides its default.
+func mergeDefaultsAt(target, defaults reflect.Value, path string, setPaths map[string]bool) {
+	switch target.Kind() {
+	case reflect.Struct:
+		for i := 0; i < target.NumField(); i++ {
+			if field := target.Type().Field(i); field.IsExported() {
+				mergeDefaultsAt(target.Field(i), defaults.Field(i), getYamlFieldPath(path, field), setPaths)
+			}
 		}
-		if config.RepoName == "" {
-			return nil, errors.New("repo name is missing from the frogbot-config file")
+	case reflect.Slice:
+		if target.Len() == 0 && defaults.Len() > 0 {
+			// Copy the slice, to avoid sharing the defaults' underlying array between repositories
+			target.Set(reflect.AppendSlice(reflect.MakeSlice(defaults.Type(), 0, defaults.Len()), defaults))
 		}
-		gitParams.RepoName = config.RepoName
-		if config.Branches != nil {
-			gitParams.Branches = config.Branches
+	case reflect.Map:
+		if target.Len() == 0 && defaults.Len() > 0 {
+			target.Set(defaults)
 		}
-		if config.FailOnSecurityIssues == nil {
-			config.FailOnSecurityIssues = &failOnSecurityIssues
+	default:
+		if target.IsZero() && !setPaths[path] {
+			target.Set(defaults)
 		}
-		config.Git = gitParams
-		newConfigAggregator = append(newConfigAggregator, FrogbotRepoConfig{
-			OutputWriter: GetCompatibleOutputWriter(gitParams.GitProvider),
-			Server:       *server,
-			Params:       config.Params,
-		})
 	}
-	return newConfigAggregator, nil
+}
+
+// Return the yaml path of the given struct field, under the yaml path of its struct
+func getYamlFieldPath(structPath string, field reflect.StructField) string {
+	name, options, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if options == "inline" {
+		return structPath
+	}
+	if name == "" {
+		name = strings.ToLower(field.Name)
+	}
+	return joinYamlPath(structPath, name)
 }
 
 func extractJFrogParamsFromEnv() (coreconfig.ServerDetails, error) {
@@ -196,12 +1203,19 @@ func extractJFrogParamsFromEnv() (coreconfig.ServerDetails, error) {
 		server.ArtifactoryUrl = url + "/artifactory/"
 	}
 
-	password := getTrimmedEnv(JFrogPasswordEnv)
+	password, err := getSecretEnv(JFrogPasswordEnv)
+	if err != nil {
+		return coreconfig.ServerDetails{}, err
+	}
+	accessToken, err := getSecretEnv(JFrogTokenEnv)
+	if err != nil {
+		return coreconfig.ServerDetails{}, err
+	}
 	user := getTrimmedEnv(JFrogUserEnv)
 	if password != "" && user != "" {
 		server.User = user
 		server.Password = password
-	} else if accessToken := getTrimmedEnv(JFrogTokenEnv); accessToken != "" {
+	} else if accessToken != "" {
 		server.AccessToken = accessToken
 	} else {
 		return coreconfig.ServerDetails{}, fmt.Errorf("%s and %s or %s environment variables are missing", JFrogUserEnv, JFrogPasswordEnv, JFrogTokenEnv)
@@ -220,11 +1234,26 @@ func extractGitParamsFromEnv() (Git, error) {
 	if err = readParamFromEnv(GitRepoOwnerEnv, &gitParams.RepoOwner); err != nil {
 		return Git{}, err
 	}
-	if err = readParamFromEnv(GitTokenEnv, &gitParams.Token); err != nil {
+	if gitParams.GitProvider == vcsutils.GitLab {
+		gitParams.RepoOwner = normalizeGitLabNamespace(gitParams.RepoOwner)
+	}
+	if gitParams.Token, err = getSecretEnv(GitTokenEnv); err != nil {
 		return Git{}, err
 	}
+	if gitParams.Token == "" && gitParams.GitProvider == vcsutils.GitHub {
+		if err = gitParams.setGitHubAppToken(); err != nil {
+			return Git{}, err
+		}
+	}
+	if gitParams.Token == "" {
+		return Git{}, &ErrMissingEnv{GitTokenEnv}
+	}
 	// Username is only mandatory for Bitbucket server on the scan-and-fix-repos command.
 	_ = readParamFromEnv(GitUsernameEnv, &gitParams.Username)
+	if gitParams.GitHubApp != nil && gitParams.Username == "" {
+		// The installation access tokens of GitHub Apps are used in git operations with this username
+		gitParams.Username = gitHubAppUsername
+	}
 	// Repo name validation will be performed later, this env is mandatory in case there is no config file.
 	_ = readParamFromEnv(GitRepoEnv, &gitParams.RepoName)
 	if err := readParamFromEnv(GitProjectEnv, &gitParams.GitProject); err != nil && gitParams.GitProvider == vcsutils.AzureRepos {
@@ -240,6 +1269,15 @@ func extractGitParamsFromEnv() (Git, error) {
 	return gitParams, err
 }
 
+// GitLab projects may be nested in subgroups, so the namespace of the project is a path such as "group/subgroup".
+// The namespace is encoded by the GitLab client, so a namespace which is already encoded, or which has leading or trailing slashes, is normalized to a plain path.
+func normalizeGitLabNamespace(namespace string) string {
+	if unescaped, err := url.PathUnescape(namespace); err == nil {
+		namespace = unescaped
+	}
+	return strings.Trim(namespace, "/")
+}
+
 func readParamFromEnv(envKey string, paramValue *string) error {
 	*paramValue = getTrimmedEnv(envKey)
 	if *paramValue == "" {
@@ -252,6 +1290,57 @@ func getTrimmedEnv(envKey string) string {
 	return strings.TrimSpace(os.Getenv(envKey))
 }
 
+// getSecretEnv returns the value of a secret environment variable, such as JF_ACCESS_TOKEN.
+// If the variable isn't set, the secret is read from the file in the variable with the _FILE suffix, such as JF_ACCESS_TOKEN_FILE.
+// This allows passing the secrets as Docker and Kubernetes secret files, rather than as environment variables.
+// Return the OpenTelemetry headers set in JF_OTEL_HEADERS, or in OTEL_EXPORTER_OTLP_HEADERS if it isn't set.
+// Like the OpenTelemetry SDKs, the names and the values of the headers are URL decoded.
+func getOtelHeadersFromEnv() (map[string]string, error) {
+	envKey := OtelHeadersEnv
+	envValue, err := getSecretEnv(envKey)
+	if err != nil {
+		return nil, err
+	}
+	if envValue == "" {
+		envKey = OtelExporterHeadersEnv
+		if envValue = getTrimmedEnv(envKey); envValue == "" {
+			return nil, nil
+		}
+	}
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(envValue, ",") {
+		name, value, found := strings.Cut(pair, "=")
+		if found {
+			name, err = url.PathUnescape(strings.TrimSpace(name))
+		}
+		if found && err == nil {
+			value, err = url.PathUnescape(strings.TrimSpace(value))
+		}
+		if !found || err != nil {
+			return nil, fmt.Errorf(errInvalidOtelHeadersEnv, envKey, redactCustomHeadersEnv(envValue))
+		}
+		headers[name] = value
+	}
+	return headers, nil
+}
+
+func getSecretEnv(envKey string) (string, error) {
+	secret := getTrimmedEnv(envKey)
+	secretFileEnv := envKey + secretFileEnvSuffix
+	secretFile := getTrimmedEnv(secretFileEnv)
+	if secretFile == "" {
+		return secret, nil
+	}
+	if secret != "" {
+		return "", fmt.Errorf(errSecretEnvAndFile, envKey, secretFileEnv)
+	}
+	content, err := os.ReadFile(secretFile)
+	if err != nil {
+		return "", fmt.Errorf(errReadSecretFile, secretFileEnv, err.Error())
+	}
+	return strings.TrimSpace(string(content)), nil
+}
+
 func extractVcsProviderFromEnv() (vcsutils.VcsProvider, error) {
 	vcsProvider := getTrimmedEnv(GitProvider)
 	switch vcsProvider {
@@ -292,10 +1381,34 @@ func extractEnvParams() (*coreconfig.ServerDetails, Git, error) {
 	return &server, gitParams, err
 }
 
-// ReadConfigFromFileSystem looks for .frogbot/frogbot-config.yml from the given path. The path is relatively from the root.
+// ReadConfigFromFileSystem reads the frogbot-config files of the given paths, and merges their repositories in the order of the paths.
+// Each path may be a file, a directory of .yml and .yaml files, or a glob pattern, such as .frogbot/*.yml.
+// A repository configured in more than one file is configured by the last file only.
+func ReadConfigFromFileSystem(configPaths ...string) (config *FrogbotConfigAggregator, err error) {
+	configFiles, err := expandConfigPaths(configPaths)
+	if err != nil {
+		return nil, err
+	}
+	if len(configFiles) == 1 {
+		return readConfigFile(configFiles[0])
+	}
+	var merged FrogbotConfigAggregator
+	for _, configFile := range configFiles {
+		fileConfig, err := readConfigFile(configFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %s", configFile, err.Error())
+		}
+		if fileConfig != nil {
+			merged = mergeConfigFiles(merged, *fileConfig, configFile)
+		}
+	}
+	return &merged, nil
+}
+
+// readConfigFile looks for .frogbot/frogbot-config.yml from the given path. The path is relatively from the root.
 // If the config file is not found in the relative path, it will search in parent dirs.
-func ReadConfigFromFileSystem(configRelativePath string) (config *FrogbotConfigAggregator, err error) {
-	log.Debug("Reading config from file system. Looking for", osFrogbotConfigPath)
+func readConfigFile(configRelativePath string) (config *FrogbotConfigAggregator, err error) {
+	log.Debug("Reading config from file system. Looking for", configRelativePath)
 	fullConfigDirPath, err := filepath.Abs(configRelativePath)
 	if err != nil {
 		return nil, err
@@ -320,19 +1433,28 @@ func ReadConfigFromFileSystem(configRelativePath string) (config *FrogbotConfigA
 		return nil, err
 	}
 
-	return config, yaml.Unmarshal(configFile, &config)
+	if err = yaml.Unmarshal(configFile, &config); err != nil || config == nil {
+		return config, err
+	}
+	return config, setConfigParamsPaths(configFile, *config)
 }
 
 func extractProjectParamsFromEnv(project *Project) error {
+	autoDetectWorkingDirs, err := getBoolEnv(AutoDetectWorkingDirsEnv, false)
+	if err != nil {
+		return err
+	}
 	workingDir := getTrimmedEnv(WorkingDirectoryEnv)
-	if workingDir == "" {
+	if workingDir == "" && !autoDetectWorkingDirs {
 		workingDir = RootDir
 	}
-	project.WorkingDirs = []string{workingDir}
+	// With no working dir, the working dirs are detected by the dependency manifests in the repository
+	if workingDir != "" {
+		project.WorkingDirs = []string{workingDir}
+	}
 	project.PipRequirementsFile = getTrimmedEnv(RequirementsFileEnv)
 	installCommand := getTrimmedEnv(InstallCommandEnv)
 	SetProjectInstallCommand(installCommand, project)
-	var err error
 	if project.UseWrapper, err = getBoolEnv(UseWrapperEnv, true); err != nil {
 		return err
 	}
@@ -356,7 +1478,169 @@ func extractRepoParamsFromEnv(repo *FrogbotRepoConfig) error {
 		return err
 	}
 	failOnSecurityIssues, err := getBoolEnv(FailOnSecurityIssuesEnv, true)
+	if err != nil {
+		return err
+	}
 	repo.FailOnSecurityIssues = &failOnSecurityIssues
+	if repo.PullRequestTitleSeverityBadge, err = getBoolEnv(PullRequestTitleBadgeEnv, false); err != nil {
+		return err
+	}
+	if repo.SummarizeUnchangedResults, err = getBoolEnv(SummarizeUnchangedResultsEnv, false); err != nil {
+		return err
+	}
+	if repo.ShowXrayScanLink, err = getBoolEnv(ShowXrayScanLinkEnv, false); err != nil {
+		return err
+	}
+	if repo.ShowRemediationCommands, err = getBoolEnv(ShowRemediationCommandsEnv, false); err != nil {
+		return err
+	}
+	if repo.ShowFixChains, err = getBoolEnv(ShowFixChainsEnv, false); err != nil {
+		return err
+	}
+	if repo.SuppressCleanComment, err = getBoolEnv(SuppressCleanCommentEnv, false); err != nil {
+		return err
+	}
+	repo.CleanScanMessage = getTrimmedEnv(CleanScanMessageEnv)
+	if repo.ScanSecrets, err = getBoolEnv(ScanSecretsEnv, false); err != nil {
+		return err
+	}
+	if repo.ScanChangedOnly, err = getBoolEnv(ScanChangedOnlyEnv, false); err != nil {
+		return err
+	}
+	if repo.SplitCommentsBySeverity, err = getBoolEnv(SplitCommentsBySeverityEnv, false); err != nil {
+		return err
+	}
+	if repo.GitLabApprovalGate, err = getBoolEnv(GitLabApprovalGateEnv, false); err != nil {
+		return err
+	}
+	if repo.OnlyWithExploits, err = getBoolEnv(OnlyWithExploitsEnv, false); err != nil {
+		return err
+	}
+	repo.CommentTemplatePath = getTrimmedEnv(CommentTemplatePathEnv)
+	if repo.PreventDuplicateRuns, err = getBoolEnv(PreventDuplicateRunsEnv, false); err != nil {
+		return err
+	}
+	repo.MinSeverity = getTrimmedEnv(MinSeverityEnv)
+	repo.FailSeverityThreshold = getTrimmedEnv(FailSeverityThresholdEnv)
+	repo.ReportTarget = getTrimmedEnv(ReportTargetEnv)
+	repo.UpgradeStrategy = getTrimmedEnv(UpgradeStrategyEnv)
+	repo.ScanMode = getTrimmedEnv(ScanModeEnv)
+	if repo.CommentOnlyOnChange, err = getBoolEnv(CommentOnlyOnChangeEnv, false); err != nil {
+		return err
+	}
+	repo.PostScanCommand = getTrimmedEnv(PostScanCommandEnv)
+	if sectionOrder := getTrimmedEnv(SectionOrderEnv); sectionOrder != "" {
+		repo.SectionOrder = strings.Split(strings.ReplaceAll(sectionOrder, " ", ""), ",")
+	}
+	if xrayFailoverUrls := getTrimmedEnv(XrayFailoverUrlsEnv); xrayFailoverUrls != "" {
+		repo.XrayFailoverUrls = strings.Split(strings.ReplaceAll(xrayFailoverUrls, " ", ""), ",")
+	}
+	if repo.AnnotateDiff, err = getBoolEnv(AnnotateDiffEnv, false); err != nil {
+		return err
+	}
+	if repo.RepoConfigCanRelaxGating, err = getBoolEnv(RepoConfigCanRelaxGatingEnv, false); err != nil {
+		return err
+	}
+	repo.CommitStatusContext = getTrimmedEnv(CommitStatusContextEnv)
+	repo.FixPRGrouping = getTrimmedEnv(FixPRGroupingEnv)
+	repo.CommentStyle = getTrimmedEnv(CommentStyleEnv)
+	if repo.AllowPrerelease, err = getBoolEnv(AllowPrereleaseEnv, false); err != nil {
+		return err
+	}
+	if repo.ScanSubmodules, err = getBoolEnv(ScanSubmodulesEnv, false); err != nil {
+		return err
+	}
+	repo.NewIssuesBaseline = getTrimmedEnv(NewIssuesBaselineEnv)
+	repo.ScanHistoryDir = getTrimmedEnv(ScanHistoryDirEnv)
+	if repo.ScanDrafts, err = getBoolEnv(ScanDraftsEnv, false); err != nil {
+		return err
+	}
+	repo.WriteStepSummary = getTrimmedEnv(WriteStepSummaryEnv)
+	if repo.AutoDetectWorkingDirs, err = getBoolEnv(AutoDetectWorkingDirsEnv, false); err != nil {
+		return err
+	}
+	if autoDetectExcludes := getTrimmedEnv(AutoDetectExcludesEnv); autoDetectExcludes != "" {
+		repo.AutoDetectExcludes = strings.Split(strings.ReplaceAll(autoDetectExcludes, " ", ""), ",")
+	}
+	if fixPRReviewers := getTrimmedEnv(FixPRReviewersEnv); fixPRReviewers != "" {
+		repo.FixPRReviewers = strings.Split(strings.ReplaceAll(fixPRReviewers, " ", ""), ",")
+	}
+	if reviewersBySeverity := getTrimmedEnv(ReviewersBySeverityEnv); reviewersBySeverity != "" {
+		if repo.ReviewersBySeverity, err = parseReviewersBySeverity(ReviewersBySeverityEnv, reviewersBySeverity); err != nil {
+			return err
+		}
+	}
+	if minCvss := getTrimmedEnv(MinCvssEnv); minCvss != "" {
+		if repo.MinCvss, err = strconv.ParseFloat(minCvss, 64); err != nil {
+			return fmt.Errorf("the value of the %s environment is expected to be a CVSS score between 0 and 10. The value received however is %s", MinCvssEnv, minCvss)
+		}
+	}
+	if repo.HideBelowMinCvss, err = getBoolEnv(HideBelowMinCvssEnv, false); err != nil {
+		return err
+	}
+	if repo.ExcludeUnscoredCvss, err = getBoolEnv(ExcludeUnscoredCvssEnv, false); err != nil {
+		return err
+	}
+	if fixPRCooldownDays := getTrimmedEnv(FixPRCooldownDaysEnv); fixPRCooldownDays != "" {
+		if repo.FixPRCooldownDays, err = strconv.Atoi(fixPRCooldownDays); err != nil || repo.FixPRCooldownDays < 0 {
+			return fmt.Errorf("the value of the %s environment is expected to be a non-negative number of days. The value received however is %s", FixPRCooldownDaysEnv, fixPRCooldownDays)
+		}
+	}
+	if repo.ShowVulnerabilitiesAge, err = getBoolEnv(ShowVulnerabilitiesAgeEnv, false); err != nil {
+		return err
+	}
+	if failOnVulnsOlderThanDays := getTrimmedEnv(FailOnVulnsOlderThanDaysEnv); failOnVulnsOlderThanDays != "" {
+		if repo.FailOnVulnsOlderThanDays, err = strconv.Atoi(failOnVulnsOlderThanDays); err != nil || repo.FailOnVulnsOlderThanDays < 0 {
+			return fmt.Errorf("the value of the %s environment is expected to be a non-negative number of days. The value received however is %s", FailOnVulnsOlderThanDaysEnv, failOnVulnsOlderThanDays)
+		}
+	}
+	if repo.DeltaScan, err = getBoolEnv(DeltaScanEnv, false); err != nil {
+		return err
+	}
+	skipClosedPRs, err := getBoolEnv(SkipClosedPRsEnv, true)
+	if err != nil {
+		return err
+	}
+	repo.SkipClosedPRs = &skipClosedPRs
+	repo.UnknownSeverityAs = getTrimmedEnv(UnknownSeverityAsEnv)
+	repo.OtelEndpoint = getTrimmedEnv(OtelEndpointEnv)
+	if repo.OtelHeaders, err = getOtelHeadersFromEnv(); err != nil {
+		return err
+	}
+	if repo.ThreadedUpdates, err = getBoolEnv(ThreadedUpdatesEnv, false); err != nil {
+		return err
+	}
+	repo.FailureComment = getTrimmedEnv(FailureCommentEnv)
+	failOnPartialScan, err := getBoolEnv(FailOnPartialScanEnv, true)
+	if err != nil {
+		return err
+	}
+	repo.FailOnPartialScan = &failOnPartialScan
+	if repo.CompactTable, err = getBoolEnv(CompactTableEnv, false); err != nil {
+		return err
+	}
+	if repo.ScanCache, err = getBoolEnv(ScanCacheEnv, false); err != nil {
+		return err
+	}
+	repo.ScanCacheDir = getTrimmedEnv(ScanCacheDirEnv)
+	if scanCacheTtlHours := getTrimmedEnv(ScanCacheTtlHoursEnv); scanCacheTtlHours != "" {
+		if repo.ScanCacheTtlHours, err = strconv.Atoi(scanCacheTtlHours); err != nil || repo.ScanCacheTtlHours < 0 {
+			return fmt.Errorf("the value of the %s environment is expected to be a non-negative number of hours. The value received however is %s", ScanCacheTtlHoursEnv, scanCacheTtlHours)
+		}
+	}
+	if scanCacheMaxEntries := getTrimmedEnv(ScanCacheMaxEntriesEnv); scanCacheMaxEntries != "" {
+		if repo.ScanCacheMaxEntries, err = strconv.Atoi(scanCacheMaxEntries); err != nil || repo.ScanCacheMaxEntries < 0 {
+			return fmt.Errorf("the value of the %s environment is expected to be a non-negative number of entries. The value received however is %s", ScanCacheMaxEntriesEnv, scanCacheMaxEntries)
+		}
+	}
+	if repo.InlineIgnores, err = getBoolEnv(InlineIgnoresEnv, false); err != nil {
+		return err
+	}
+	failOnScanError, err := getBoolEnv(FailOnScanErrorEnv, true)
+	if err != nil {
+		return err
+	}
+	repo.FailOnScanError = &failOnScanError
 	// Non-mandatory Xray context params
 	var watches string
 	_ = readParamFromEnv(jfrogWatchesEnv, &watches)
@@ -366,6 +1650,24 @@ func extractRepoParamsFromEnv(repo *FrogbotRepoConfig) error {
 		repo.Watches = strings.Split(watches, WatchesDelimiter)
 	}
 	_ = readParamFromEnv(jfrogProjectEnv, &repo.JFrogProjectKey)
+	if fixPRBranches := getTrimmedEnv(FixPRBranchesEnv); fixPRBranches != "" {
+		repo.FixPRBranches = strings.Split(strings.ReplaceAll(fixPRBranches, " ", ""), ",")
+	}
+	if pathIgnores := getTrimmedEnv(PathIgnoresEnv); pathIgnores != "" {
+		repo.PathIgnores = strings.Split(strings.ReplaceAll(pathIgnores, " ", ""), ",")
+	}
+	if repo.HidePathIgnoredIssues, err = getBoolEnv(HidePathIgnoredIssuesEnv, false); err != nil {
+		return err
+	}
+	_ = readParamFromEnv(BotNameEnv, &repo.BotName)
+	if repo.WarnUnpinned, err = getBoolEnv(WarnUnpinnedEnv, false); err != nil {
+		return err
+	}
+	if severityColors := getTrimmedEnv(SeverityColorsEnv); severityColors != "" {
+		if repo.SeverityColors, err = parseSeverityColors(SeverityColorsEnv, severityColors); err != nil {
+			return err
+		}
+	}
 	return err
 }
 
@@ -394,7 +1696,80 @@ func generateConfigAggregatorFromEnv(gitParams *Git, server *coreconfig.ServerDe
 		return nil, err
 	}
 	repo.Projects = append(repo.Projects, project)
-	repo.OutputWriter = GetCompatibleOutputWriter(gitParams.GitProvider)
+	if err := repo.applyConfigOverrides(configOverrides); err != nil {
+		return nil, err
+	}
+	if err := repo.validateReportTarget(); err != nil {
+		return nil, err
+	}
+	if err := repo.validateUpgradeStrategy(); err != nil {
+		return nil, err
+	}
+	if err := repo.validateFixPRGrouping(); err != nil {
+		return nil, err
+	}
+	if err := repo.validateCommentStyle(); err != nil {
+		return nil, err
+	}
+	if err := repo.validateNewIssuesBaseline(); err != nil {
+		return nil, err
+	}
+	if err := repo.validateUnknownSeverityAs(); err != nil {
+		return nil, err
+	}
+	if err := repo.validateWriteStepSummary(); err != nil {
+		return nil, err
+	}
+	if err := repo.validateXrayFailoverUrls(); err != nil {
+		return nil, err
+	}
+	if err := repo.validateOtelEndpoint(); err != nil {
+		return nil, err
+	}
+	if err := repo.validateOtelHeaders(); err != nil {
+		return nil, err
+	}
+	if err := repo.validateFailOnVulnsOlderThanDays(); err != nil {
+		return nil, err
+	}
+	if err := repo.validateSectionOrder(); err != nil {
+		return nil, err
+	}
+	if err := repo.validateScanMode(); err != nil {
+		return nil, err
+	}
+	if err := repo.validateFixPRBranches(); err != nil {
+		return nil, err
+	}
+	if err := repo.validatePathIgnores(); err != nil {
+		return nil, err
+	}
+	if err := repo.validateAutoDetectExcludes(); err != nil {
+		return nil, err
+	}
+	if err := repo.validateSeverityColors(); err != nil {
+		return nil, err
+	}
+	reviewersBySeverity, err := repo.getTitledReviewersBySeverity()
+	if err != nil {
+		return nil, err
+	}
+	repo.ReviewersBySeverity = reviewersBySeverity
+	if err := repo.validateMinCvss(); err != nil {
+		return nil, err
+	}
+	commentTemplate, err := repo.loadCommentTemplate()
+	if err != nil {
+		return nil, err
+	}
+	repo.CommentTemplate = commentTemplate
+	if err := repo.expandProjects(); err != nil {
+		return nil, err
+	}
+	if err := repo.applyProfile(getSelectedProfile()); err != nil {
+		return nil, err
+	}
+	repo.OutputWriter = GetCompatibleOutputWriter(gitParams.GitProvider, repo.SeverityColors, repo.MaxFixedVersions, repo.CompactTable)
 	return &FrogbotConfigAggregator{repo}, nil
 }
 
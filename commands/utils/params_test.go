@@ -1,16 +1,23 @@
 package utils
 
 import (
+	"fmt"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"os"
 	"path/filepath"
 	"strconv"
 	"testing"
 
 	"github.com/jfrog/froggit-go/vcsutils"
 	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
 )
 
-var configParamsTestFile = filepath.Join("..", "testdata", "config", "frogbot-config-test-params.yml")
+var (
+	configParamsTestFile   = filepath.Join("..", "testdata", "config", "frogbot-config-test-params.yml")
+	configDefaultsTestFile = filepath.Join("..", "testdata", "config", "frogbot-config-defaults.yml")
+)
 
 func TestExtractParamsFromEnvError(t *testing.T) {
 	SetEnvAndAssert(t, map[string]string{
@@ -75,6 +82,64 @@ func TestExtractParamsFromEnvToken(t *testing.T) {
 	extractAndAssertParamsFromEnv(t, true, false)
 }
 
+func TestExtractParamsFromEnvSecretFiles(t *testing.T) {
+	secretsDir := t.TempDir()
+	jfrogTokenFile := filepath.Join(secretsDir, "jfrog-token")
+	gitTokenFile := filepath.Join(secretsDir, "git-token")
+	// Secret files usually end with a new line
+	assert.NoError(t, os.WriteFile(jfrogTokenFile, []byte("token\n"), 0600))
+	assert.NoError(t, os.WriteFile(gitTokenFile, []byte("123456789\n"), 0600))
+	SetEnvAndAssert(t, map[string]string{
+		JFrogUrlEnv:                         "http://127.0.0.1:8081",
+		JFrogUserEnv:                        "",
+		JFrogPasswordEnv:                    "",
+		JFrogTokenEnv + secretFileEnvSuffix: jfrogTokenFile,
+		GitProvider:                         string(BitbucketServer),
+		GitRepoOwnerEnv:                     "jfrog",
+		GitRepoEnv:                          "frogbot",
+		GitTokenEnv + secretFileEnvSuffix:   gitTokenFile,
+		GitBaseBranchEnv:                    "dev",
+		GitPullRequestIDEnv:                 "1",
+	})
+	extractAndAssertParamsFromEnv(t, true, false)
+}
+
+func TestGetSecretEnvErrors(t *testing.T) {
+	defer func() {
+		assert.NoError(t, SanitizeEnv())
+	}()
+	missingFile := filepath.Join(t.TempDir(), "missing")
+	SetEnvAndAssert(t, map[string]string{JFrogTokenEnv + secretFileEnvSuffix: missingFile})
+	_, err := getSecretEnv(JFrogTokenEnv)
+	assert.ErrorContains(t, err, "couldn't read the file set in the JF_ACCESS_TOKEN_FILE environment variable")
+
+	SetEnvAndAssert(t, map[string]string{JFrogTokenEnv: "token"})
+	_, err = getSecretEnv(JFrogTokenEnv)
+	assert.EqualError(t, err, "only one of the JF_ACCESS_TOKEN and JF_ACCESS_TOKEN_FILE environment variables may be set")
+}
+
+func TestGetDoctorParams(t *testing.T) {
+	missingFile := filepath.Join(t.TempDir(), "missing.pem")
+	SetEnvAndAssert(t, map[string]string{
+		CaCertPathEnv:    missingFile,
+		JFrogUrlEnv:      "",
+		JFrogUserEnv:     "",
+		JFrogPasswordEnv: "",
+		JFrogTokenEnv:    "",
+		GitProvider:      "",
+	})
+	// All the steps are run, even though the first step failed
+	params := GetDoctorParams()
+	if assert.Len(t, params.TransportErrs, 1) {
+		assert.ErrorContains(t, params.TransportErrs[0], missingFile)
+	}
+	assert.EqualError(t, params.ServerErr, "JF_URL or JF_XRAY_URL and JF_ARTIFACTORY_URL environment variables are missing")
+	assert.Nil(t, params.Server)
+	assert.Error(t, params.GitErr)
+	assert.Nil(t, params.Client)
+	AssertSanitizedEnv(t)
+}
+
 func TestExtractVcsProviderFromEnv(t *testing.T) {
 	_, err := extractVcsProviderFromEnv()
 	assert.Error(t, err)
@@ -125,6 +190,31 @@ func TestExtractGitParamsFromEnvErrors(t *testing.T) {
 	assert.True(t, ok)
 }
 
+func TestExtractGitParamsFromEnvGitLabSubgroup(t *testing.T) {
+	defer func() {
+		assert.NoError(t, SanitizeEnv())
+	}()
+
+	// An encoded namespace is normalized, as the GitLab client encodes the project path by itself
+	SetEnvAndAssert(t, map[string]string{GitProvider: "gitlab", GitRepoOwnerEnv: "jfrog%2Fsecurity/", GitTokenEnv: "123456"})
+	gitParams, err := extractGitParamsFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, "jfrog/security", gitParams.RepoOwner)
+
+	configAggregator, err := NewConfigAggregator(&FrogbotConfigAggregator{{Params: Params{Git: Git{RepoName: "/frogbot/"}}}}, gitParams, &config.ServerDetails{}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "frogbot", configAggregator[0].RepoName)
+}
+
+func TestNormalizeGitLabNamespace(t *testing.T) {
+	assert.Equal(t, "jfrog", normalizeGitLabNamespace("jfrog"))
+	assert.Equal(t, "jfrog/security/frogbot", normalizeGitLabNamespace("jfrog/security/frogbot"))
+	assert.Equal(t, "jfrog/security", normalizeGitLabNamespace("jfrog%2Fsecurity"))
+	assert.Equal(t, "jfrog/security", normalizeGitLabNamespace("/jfrog/security/"))
+	// An invalid escape sequence is kept as is
+	assert.Equal(t, "jfrog%2", normalizeGitLabNamespace("jfrog%2"))
+}
+
 func TestExtractAndAssertRepoParams(t *testing.T) {
 	SetEnvAndAssert(t, map[string]string{
 		JFrogUrlEnv:         "http://127.0.0.1:8081",
@@ -288,6 +378,29 @@ func TestGenerateConfigAggregatorFromEnv(t *testing.T) {
 	assert.Equal(t, []string{"i"}, project.InstallCommandArgs)
 }
 
+func TestGetParamsWithoutVcs(t *testing.T) {
+	SetEnvAndAssert(t, map[string]string{
+		JFrogUrlEnv:             "http://127.0.0.1:8081",
+		JFrogTokenEnv:           "token",
+		WorkingDirectoryEnv:     "a/b",
+		FailOnSecurityIssuesEnv: "false",
+	})
+	configAggregator, server, err := GetParamsWithoutVcs()
+	assert.NoError(t, err)
+	AssertSanitizedEnv(t)
+	assert.Equal(t, "http://127.0.0.1:8081/xray/", server.XrayUrl)
+	assert.Len(t, configAggregator, 1)
+	repo := configAggregator[0]
+	assert.Equal(t, "token", repo.Server.AccessToken)
+	assert.Empty(t, repo.RepoName)
+	assert.False(t, *repo.FailOnSecurityIssues)
+	assert.Equal(t, []string{"a/b"}, repo.Projects[0].WorkingDirs)
+
+	// The JFrog platform details are mandatory
+	_, _, err = GetParamsWithoutVcs()
+	assert.Error(t, err)
+}
+
 func TestExtractProjectParamsFromEnv(t *testing.T) {
 	params := Project{}
 	defer func() {
@@ -313,3 +426,328 @@ func TestExtractProjectParamsFromEnv(t *testing.T) {
 	assert.Equal(t, "nuget", params.InstallCommandName)
 	assert.Equal(t, []string{"restore"}, params.InstallCommandArgs)
 }
+
+func TestNewConfigAggregatorWithDefaults(t *testing.T) {
+	configFile, err := ReadConfigFromFileSystem(configDefaultsTestFile)
+	assert.NoError(t, err)
+	gitParams := Git{GitProvider: vcsutils.GitHub, RepoOwner: "jfrog", Token: "123456789"}
+	configAggregator, err := NewConfigAggregator(configFile, gitParams, &config.ServerDetails{}, true)
+	assert.NoError(t, err)
+	// The defaults entry doesn't represent a repository
+	assert.Len(t, configAggregator, 2)
+
+	// The first repository inherits all the defaults
+	repo := configAggregator[0]
+	assert.Equal(t, "frogbot", repo.RepoName)
+	assert.Equal(t, []string{"master"}, repo.Branches)
+	assert.False(t, *repo.FailOnSecurityIssues)
+	assert.Equal(t, []string{"watch-1", "watch-2"}, repo.Watches)
+	assert.Len(t, repo.Projects, 1)
+	assert.Equal(t, "npm", repo.Projects[0].InstallCommandName)
+	assert.Equal(t, []string{"a/b"}, repo.Projects[0].WorkingDirs)
+	assert.False(t, repo.ShouldContinueOnError())
+
+	// The second repository overrides the scalars and replaces the slices
+	repo = configAggregator[1]
+	assert.Equal(t, "frogbot-dev", repo.RepoName)
+	assert.Equal(t, []string{"dev"}, repo.Branches)
+	assert.True(t, *repo.FailOnSecurityIssues)
+	assert.Equal(t, "proj", repo.JFrogProjectKey)
+	assert.Equal(t, []string{"watch-3"}, repo.Watches)
+	assert.Equal(t, "npm", repo.Projects[0].InstallCommandName)
+	assert.True(t, repo.ShouldContinueOnError())
+}
+
+func TestNewConfigAggregatorProjects(t *testing.T) {
+	configContent := `
+- params:
+    git:
+      repoName: monorepo
+    scan:
+      minSeverity: Medium
+      failSeverityThreshold: High
+      scanBatchSize: 10
+      ignoredIssues: [CVE-2022-24450]
+      ignoredDependencies: ["lodash <4.17.21"]
+      projects:
+        - workingDirs: [payments]
+          watches: [payments-watch]
+          failSeverityThreshold: Low
+          scanBatchSize: 5
+          ignoredIssues: ["XRAY-1 until 2024-06-01"]
+        - workingDirs: [docs-site]
+          minSeverity: Critical
+    jfrogPlatform:
+      watches: [default-watch]
+`
+	var configData FrogbotConfigAggregator
+	assert.NoError(t, yaml.Unmarshal([]byte(configContent), &configData))
+	configAggregator, err := NewConfigAggregator(&configData, Git{}, &config.ServerDetails{}, true)
+	assert.NoError(t, err)
+	projects := configAggregator[0].Projects
+	if assert.Len(t, projects, 2) {
+		// Each project inherits the unset values from the repository
+		assert.Equal(t, []string{"payments-watch"}, projects[0].Watches)
+		assert.Equal(t, SeverityPolicy{MinSeverity: "Medium", FailSeverityThreshold: "Low"}, projects[0].SeverityPolicy)
+		assert.Equal(t, 5, projects[0].ScanBatchSize)
+		assert.Equal(t, []string{"default-watch"}, projects[1].Watches)
+		assert.Equal(t, 10, projects[1].ScanBatchSize)
+		assert.Equal(t, []string{"XRAY-1 until 2024-06-01", "CVE-2022-24450"}, projects[0].IgnoredIssues)
+		assert.Equal(t, []string{"CVE-2022-24450"}, projects[1].IgnoredIssues)
+		assert.Equal(t, []string{"lodash <4.17.21"}, projects[1].IgnoredDependencies)
+		assert.Equal(t, SeverityPolicy{MinSeverity: "Critical", FailSeverityThreshold: "High"}, projects[1].SeverityPolicy)
+	}
+
+	// Invalid project severities are rejected
+	configData[0].Projects[1].MinSeverity = "Severe"
+	_, err = NewConfigAggregator(&configData, Git{}, &config.ServerDetails{}, true)
+	assert.EqualError(t, err, fmt.Sprintf(errInvalidSeverity, "Severe", "minSeverity"))
+}
+
+func TestNewConfigAggregatorDefaultsExplicitZeroValues(t *testing.T) {
+	configContent := `
+- defaults:
+    scan:
+      scanSecrets: true
+      includeAllVulnerabilities: true
+      scanBatchSize: 10
+- params:
+    git:
+      repoName: frogbot
+    scan:
+      scanSecrets: false
+      includeAllVulnerabilities: ~
+      scanBatchSize: 0
+- params:
+    git:
+      repoName: frogbot-dev
+`
+	var configData FrogbotConfigAggregator
+	assert.NoError(t, yaml.Unmarshal([]byte(configContent), &configData))
+	assert.NoError(t, setConfigParamsPaths([]byte(configContent), configData))
+	configAggregator, err := NewConfigAggregator(&configData, Git{}, &config.ServerDetails{}, true)
+	assert.NoError(t, err)
+	if assert.Len(t, configAggregator, 2) {
+		// A param explicitly set to false or 0 overrides its default, while a param set to null inherits it
+		assert.False(t, configAggregator[0].ScanSecrets)
+		assert.True(t, configAggregator[0].IncludeAllVulnerabilities)
+		assert.Zero(t, configAggregator[0].ScanBatchSize)
+		assert.True(t, configAggregator[1].ScanSecrets)
+		assert.True(t, configAggregator[1].IncludeAllVulnerabilities)
+		assert.Equal(t, 10, configAggregator[1].ScanBatchSize)
+	}
+}
+
+func TestNewConfigAggregatorMultipleDefaults(t *testing.T) {
+	configData := FrogbotConfigAggregator{{Defaults: &Params{}}, {Defaults: &Params{}}}
+	_, err := NewConfigAggregator(&configData, Git{}, &config.ServerDetails{}, true)
+	assert.EqualError(t, err, errMultipleDefaults)
+}
+
+func TestValidateReportTarget(t *testing.T) {
+	for _, reportTarget := range []string{"", PullRequestCommentReportTarget, IssueReportTarget} {
+		params := Params{ReportTarget: reportTarget}
+		assert.NoError(t, params.validateReportTarget())
+	}
+	params := Params{ReportTarget: DiscussionReportTarget}
+	assert.EqualError(t, params.validateReportTarget(), errDiscussionReportTarget)
+	params.ReportTarget = "email"
+	assert.EqualError(t, params.validateReportTarget(), "the report target 'email' is invalid. The supported report targets are pr-comment and issue")
+}
+
+func TestValidateScanMode(t *testing.T) {
+	for _, scanMode := range []string{"", VulnerabilitiesScanMode} {
+		params := Params{ScanMode: scanMode}
+		assert.NoError(t, params.validateScanMode())
+	}
+	params := Params{ScanMode: "licenses"}
+	assert.EqualError(t, params.validateScanMode(), "the scan mode 'licenses' is invalid. The supported scan modes are vulnerabilities, violations and both")
+
+	// The violations are found by the policies of the watches or the JFrog project
+	for _, scanMode := range []string{ViolationsScanMode, BothScanMode} {
+		params = Params{ScanMode: scanMode}
+		assert.EqualError(t, params.validateScanMode(), fmt.Sprintf(errScanModeWithoutPolicy, scanMode))
+		params.Watches = []string{"watch-1"}
+		assert.NoError(t, params.validateScanMode())
+		params = Params{ScanMode: scanMode, JFrogPlatform: JFrogPlatform{JFrogProjectKey: "frogbot"}}
+		assert.NoError(t, params.validateScanMode())
+		params = Params{ScanMode: scanMode, Scan: Scan{Projects: []Project{{Watches: []string{"watch-1"}}, {}}}}
+		assert.EqualError(t, params.validateScanMode(), fmt.Sprintf(errScanModeWithoutPolicy, scanMode))
+		params.Projects[1].Watches = []string{"watch-2"}
+		assert.NoError(t, params.validateScanMode())
+	}
+}
+
+func TestValidateXrayFailoverUrls(t *testing.T) {
+	params := Params{XrayFailoverUrls: []string{"https://dr.jfrog.example.com/xray", "http://xray.example.com:8082/"}}
+	assert.NoError(t, params.validateXrayFailoverUrls())
+	// The validation doesn't change the URLs
+	assert.Equal(t, []string{"https://dr.jfrog.example.com/xray", "http://xray.example.com:8082/"}, params.XrayFailoverUrls)
+	for _, xrayUrl := range []string{"dr.jfrog.example.com/xray", "ftp://xray.example.com", "https://"} {
+		params.XrayFailoverUrls = []string{xrayUrl}
+		assert.EqualError(t, params.validateXrayFailoverUrls(), fmt.Sprintf(errInvalidXrayFailoverUrl, xrayUrl))
+	}
+}
+
+func TestValidateOtelEndpoint(t *testing.T) {
+	for _, endpoint := range []string{"", "http://otel-collector:4318", "https://otel.example.com/v1/traces"} {
+		params := Params{OtelEndpoint: endpoint}
+		assert.NoError(t, params.validateOtelEndpoint())
+	}
+	for _, endpoint := range []string{"otel-collector:4318", "grpc://otel-collector:4317"} {
+		params := Params{OtelEndpoint: endpoint}
+		assert.EqualError(t, params.validateOtelEndpoint(), fmt.Sprintf(errInvalidOtelEndpoint, endpoint))
+	}
+}
+
+func TestGetOtelHeadersFromEnv(t *testing.T) {
+	t.Setenv(OtelHeadersEnv, "")
+	t.Setenv(OtelExporterHeadersEnv, "")
+	headers, err := getOtelHeadersFromEnv()
+	assert.NoError(t, err)
+	assert.Nil(t, headers)
+
+	// The standard OpenTelemetry variable is used if JF_OTEL_HEADERS isn't set, and its values are URL decoded
+	t.Setenv(OtelExporterHeadersEnv, "api-key=12%2C34, X-Team = security")
+	headers, err = getOtelHeadersFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"api-key": "12,34", "X-Team": "security"}, headers)
+
+	t.Setenv(OtelHeadersEnv, "Authorization=Bearer%20token")
+	headers, err = getOtelHeadersFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Authorization": "Bearer token"}, headers)
+
+	t.Setenv(OtelHeadersEnv, "Authorization")
+	_, err = getOtelHeadersFromEnv()
+	assert.EqualError(t, err, fmt.Sprintf(errInvalidOtelHeadersEnv, OtelHeadersEnv, "Authorization"))
+}
+
+func TestValidateOtelHeaders(t *testing.T) {
+	params := Params{OtelHeaders: map[string]string{"Api-Key": "1234"}}
+	assert.NoError(t, params.validateOtelHeaders())
+	params.OtelHeaders = map[string]string{"Api Key": "1234"}
+	assert.EqualError(t, params.validateOtelHeaders(), fmt.Sprintf(errInvalidOtelHeader, "Api Key"))
+}
+
+func TestValidateSectionOrder(t *testing.T) {
+	params := Params{}
+	assert.NoError(t, params.validateSectionOrder())
+	assert.Equal(t, DefaultSectionOrder, params.GetSectionOrder())
+	params.SectionOrder = []string{SecretsSection, SecuritySection}
+	assert.NoError(t, params.validateSectionOrder())
+	assert.Equal(t, []string{SecretsSection, SecuritySection}, params.GetSectionOrder())
+	params.SectionOrder = []string{SecuritySection, "licenses"}
+	assert.EqualError(t, params.validateSectionOrder(), "the section 'licenses' set in sectionOrder is invalid. The supported sections are security, iac and secrets")
+	params.SectionOrder = []string{IacSection, SecuritySection, IacSection}
+	assert.EqualError(t, params.validateSectionOrder(), fmt.Sprintf(errDuplicateSection, IacSection))
+}
+
+func TestValidateEcosystems(t *testing.T) {
+	project := Project{}
+	assert.NoError(t, project.validateEcosystems())
+	project.Ecosystems = []string{"go", "npm", "pipenv"}
+	assert.NoError(t, project.validateEcosystems())
+	project.Ecosystems = []string{"go", "docker"}
+	assert.EqualError(t, project.validateEcosystems(), "the ecosystem 'docker' is invalid. The supported ecosystems are dotnet, go, gradle, maven, npm, nuget, pip, pipenv, poetry and yarn")
+
+	params := Params{Scan: Scan{Projects: []Project{{Ecosystems: []string{"Go"}}}}}
+	assert.EqualError(t, params.expandProjects(), fmt.Sprintf(errInvalidEcosystem, "Go", formatSupportedEcosystems()))
+}
+
+func TestValidateDirectDependencies(t *testing.T) {
+	for _, directDependencies := range []string{"", ManifestDirectDependencies, GraphDirectDependencies} {
+		project := Project{DirectDependencies: directDependencies}
+		assert.NoError(t, project.validateDirectDependencies())
+	}
+	params := Params{Scan: Scan{Projects: []Project{{DirectDependencies: "root"}}}}
+	assert.EqualError(t, params.expandProjects(), fmt.Sprintf(errInvalidDirectDependencies, "root"))
+}
+
+func TestIsEcosystemScanned(t *testing.T) {
+	// All the ecosystems are scanned by default
+	project := Project{}
+	assert.True(t, project.IsEcosystemScanned(coreutils.Maven))
+	project.Ecosystems = []string{"go", "npm"}
+	assert.True(t, project.IsEcosystemScanned(coreutils.Go))
+	assert.True(t, project.IsEcosystemScanned(coreutils.Npm))
+	assert.False(t, project.IsEcosystemScanned(coreutils.Yarn))
+}
+
+func TestValidateUpgradeStrategy(t *testing.T) {
+	for _, upgradeStrategy := range []string{"", MinimalUpgradeStrategy, MinorUpgradeStrategy, LatestUpgradeStrategy} {
+		params := Params{UpgradeStrategy: upgradeStrategy}
+		assert.NoError(t, params.validateUpgradeStrategy())
+	}
+	params := Params{UpgradeStrategy: "major"}
+	assert.EqualError(t, params.validateUpgradeStrategy(), "the upgrade strategy 'major' is invalid. The supported upgrade strategies are minimal, minor and latest")
+}
+
+func TestValidateCommentStyle(t *testing.T) {
+	for _, commentStyle := range []string{"", FullCommentStyle, StatusCommentStyle} {
+		params := Params{CommentStyle: commentStyle}
+		assert.NoError(t, params.validateCommentStyle())
+	}
+	params := Params{CommentStyle: "compact"}
+	assert.EqualError(t, params.validateCommentStyle(), "the comment style 'compact' is invalid. The supported comment styles are full and status")
+}
+
+func TestValidateNewIssuesBaseline(t *testing.T) {
+	for _, baseline := range []string{"", TargetBranchBaseline, ScanHistoryBaseline} {
+		params := Params{NewIssuesBaseline: baseline}
+		assert.NoError(t, params.validateNewIssuesBaseline())
+	}
+	params := Params{NewIssuesBaseline: "last-commit"}
+	assert.EqualError(t, params.validateNewIssuesBaseline(), "the new issues baseline 'last-commit' is invalid. The supported baselines are target-branch and scan-history")
+}
+
+func TestValidateWriteStepSummary(t *testing.T) {
+	for _, mode := range []string{"", WithCommentStepSummary, InsteadOfCommentStepSummary} {
+		params := Params{WriteStepSummary: mode}
+		assert.NoError(t, params.validateWriteStepSummary())
+	}
+	params := Params{WriteStepSummary: "true"}
+	assert.EqualError(t, params.validateWriteStepSummary(), "the step summary mode 'true' is invalid. The supported modes are with-comment and instead-of-comment")
+}
+
+func TestValidateFixPRGrouping(t *testing.T) {
+	for _, grouping := range []string{"", PerDependencyFixPRGrouping, PerEcosystemFixPRGrouping, AllFixPRGrouping} {
+		params := Params{FixPRGrouping: grouping}
+		assert.NoError(t, params.validateFixPRGrouping())
+	}
+	params := Params{FixPRGrouping: "per-project"}
+	assert.EqualError(t, params.validateFixPRGrouping(), "the fix pull requests grouping 'per-project' is invalid. The supported groupings are per-dependency, per-ecosystem and all")
+}
+
+func TestIsFixPRBranch(t *testing.T) {
+	params := Params{}
+	assert.True(t, params.IsFixPRBranch("feature/login"))
+
+	params.FixPRBranches = []string{"main", "release/*"}
+	assert.NoError(t, params.validateFixPRBranches())
+	assert.True(t, params.IsFixPRBranch("main"))
+	assert.True(t, params.IsFixPRBranch("release/1.0"))
+	assert.False(t, params.IsFixPRBranch("release/1.0/hotfix"))
+	assert.False(t, params.IsFixPRBranch("feature/login"))
+	assert.False(t, params.IsFixPRBranch("maintenance"))
+
+	params.FixPRBranches = []string{"release/[1-"}
+	assert.EqualError(t, params.validateFixPRBranches(), "the fixPRBranches pattern 'release/[1-' is invalid")
+}
+
+func TestExtractVulnerabilitiesAgeFromEnv(t *testing.T) {
+	t.Setenv(ShowVulnerabilitiesAgeEnv, "true")
+	t.Setenv(FailOnVulnsOlderThanDaysEnv, "365")
+	repo := FrogbotRepoConfig{}
+	assert.NoError(t, extractRepoParamsFromEnv(&repo))
+	assert.True(t, repo.ShowVulnerabilitiesAge)
+	assert.Equal(t, 365, repo.FailOnVulnsOlderThanDays)
+
+	for _, value := range []string{"-1", "a year"} {
+		t.Setenv(FailOnVulnsOlderThanDaysEnv, value)
+		assert.EqualError(t, extractRepoParamsFromEnv(&FrogbotRepoConfig{}), fmt.Sprintf("the value of the %s environment is expected to be a non-negative number of days. The value received however is %s", FailOnVulnsOlderThanDaysEnv, value))
+	}
+
+	params := Params{FailOnVulnsOlderThanDays: -1}
+	assert.EqualError(t, params.validateFailOnVulnsOlderThanDays(), fmt.Sprintf(errInvalidVulnsAgeDays, -1))
+}
@@ -0,0 +1,187 @@
+package utils
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+func (p *Params) validatePathIgnores() error {
+	return validatePathPatterns(p.PathIgnores, errInvalidPathIgnores)
+}
+
+func (p *Params) validateAutoDetectExcludes() error {
+	return validatePathPatterns(p.AutoDetectExcludes, errInvalidAutoDetectExcludes)
+}
+
+func validatePathPatterns(patterns []string, errFormat string) error {
+	for _, pattern := range patterns {
+		for _, segment := range strings.Split(strings.Trim(pattern, "/"), "/") {
+			if _, err := path.Match(segment, ""); err != nil || segment == "" {
+				return fmt.Errorf(errFormat, pattern)
+			}
+		}
+	}
+	return nil
+}
+
+// IsPathIgnored returns true if the path, relative to the root of the repository, matches one of the pathIgnores patterns.
+// Like in .gitignore files, a pattern with no slash except a trailing one, such as "testdata/", matches a dir at any level.
+// Other patterns, such as "examples/*/demo", match from the root of the repository. A pattern matches the dirs under a matching dir too.
+func (p *Params) IsPathIgnored(relativePath string) bool {
+	return isPathIgnored(relativePath, p.PathIgnores)
+}
+
+// IsPathIgnored returns true if the working dirs of the project match the pathIgnores patterns, or if the path, relative to the root of the repository,
+// such as the path of a misconfigured file or of the dir of a module, matches one of them.
+func (p *Project) IsPathIgnored(relativePath string) bool {
+	return p.PathIgnored || isPathIgnored(relativePath, p.PathIgnores)
+}
+
+func isPathIgnored(relativePath string, patterns []string) bool {
+	relativePath = filepath.ToSlash(filepath.Clean(relativePath))
+	if relativePath == RootDir {
+		return false
+	}
+	return matchesPathPatterns(relativePath, patterns)
+}
+
+// IsIssuePathIgnored returns true if all the impact paths of the issue start in modules whose dirs match the pathIgnores patterns.
+// modulesDirs - The dirs of the modules, relative to the root of the repository, by the module names, as returned by GetModulesDirs.
+func (p *Project) IsIssuePathIgnored(row *formats.VulnerabilityOrViolationRow, modulesDirs map[string]string) bool {
+	if p.PathIgnored {
+		return true
+	}
+	for _, impactPath := range row.ImpactPaths {
+		if len(impactPath) == 0 {
+			return false
+		}
+		moduleDir, found := modulesDirs[impactPath[0].Name]
+		if !found || !p.IsPathIgnored(moduleDir) {
+			return false
+		}
+	}
+	return len(row.ImpactPaths) > 0
+}
+
+// GetModulesDirs returns the dirs of the modules declared by the manifests in the working dirs and in the dirs under them, such as the go.mod, package.json
+// and pom.xml files, by the module names. The dirs are relative to baseWd. The hidden dirs and the dirs of installed dependencies are skipped,
+// and the files which can't be read are logged and skipped.
+func GetModulesDirs(workDirs []string, baseWd string) map[string]string {
+	modulesDirs := make(map[string]string)
+	for _, workDir := range workDirs {
+		err := filepath.WalkDir(workDir, func(filePath string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				log.Debug("couldn't read", filePath+":", err.Error())
+				return nil
+			}
+			if entry.IsDir() {
+				if filePath != workDir && (strings.HasPrefix(entry.Name(), ".") || skippedWorkingDirsNames[entry.Name()]) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			manifest, exists := inlineIgnoreManifests[entry.Name()]
+			if !exists || manifest.module == nil {
+				return nil
+			}
+			content, err := os.ReadFile(filePath) // #nosec G304
+			if err != nil {
+				log.Debug("couldn't read", filePath+":", err.Error())
+				return nil
+			}
+			module := manifest.module(content)
+			if _, exists = modulesDirs[module]; module == "" || exists {
+				return nil
+			}
+			if relativeDir, err := filepath.Rel(baseWd, filepath.Dir(filePath)); err == nil {
+				modulesDirs[module] = filepath.ToSlash(relativeDir)
+			}
+			return nil
+		})
+		if err != nil {
+			log.Debug("couldn't find the modules of", workDir+":", err.Error())
+		}
+	}
+	return modulesDirs
+}
+
+func matchesPathPatterns(relativePath string, patterns []string) bool {
+	pathSegments := strings.Split(relativePath, "/")
+	for _, pattern := range patterns {
+		if matchesPathIgnore(pathSegments, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesPathIgnore(pathSegments []string, pattern string) bool {
+	trimmedPattern := strings.Trim(pattern, "/")
+	patternSegments := strings.Split(trimmedPattern, "/")
+	anchored := strings.HasPrefix(pattern, "/") || len(patternSegments) > 1
+	for start := 0; start+len(patternSegments) <= len(pathSegments); start++ {
+		if matchesSegments(pathSegments[start:start+len(patternSegments)], patternSegments) {
+			return true
+		}
+		if anchored {
+			break
+		}
+	}
+	return false
+}
+
+func matchesSegments(pathSegments, patternSegments []string) bool {
+	for index, patternSegment := range patternSegments {
+		if matched, _ := path.Match(patternSegment, pathSegments[index]); !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// splitPathIgnoredProjects moves the working dirs which match the pathIgnores patterns to separate projects, whose issues don't fail the scan.
+// If hidePathIgnoredIssues is set, these working dirs aren't scanned at all.
+func (p *Params) splitPathIgnoredProjects() {
+	if len(p.PathIgnores) == 0 {
+		return
+	}
+	var projects []Project
+	for _, project := range p.Projects {
+		var scannedDirs, ignoredDirs []string
+		for _, workingDir := range project.WorkingDirs {
+			if p.IsPathIgnored(workingDir) {
+				ignoredDirs = append(ignoredDirs, workingDir)
+			} else {
+				scannedDirs = append(scannedDirs, workingDir)
+			}
+		}
+		if len(ignoredDirs) == 0 {
+			// The issues of the modules and of the files which match the patterns don't fail the scan either
+			project.PathIgnores = p.PathIgnores
+			projects = append(projects, project)
+			continue
+		}
+		if len(scannedDirs) > 0 {
+			gatingProject := project
+			gatingProject.PathIgnores = p.PathIgnores
+			gatingProject.WorkingDirs = scannedDirs
+			projects = append(projects, gatingProject)
+		}
+		if p.HidePathIgnoredIssues {
+			log.Info("The working directories", strings.Join(ignoredDirs, ", "), "match the pathIgnores patterns, and won't be scanned")
+			continue
+		}
+		ignoredProject := project
+		ignoredProject.WorkingDirs = ignoredDirs
+		ignoredProject.PathIgnored = true
+		projects = append(projects, ignoredProject)
+	}
+	p.Projects = projects
+}
@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestIsPathIgnored(t *testing.T) {
+	params := Params{PathIgnores: []string{"examples/", "testdata", "/docs/*/demo", "tools/gen*"}}
+	testCases := []struct {
+		path    string
+		ignored bool
+	}{
+		{path: ".", ignored: false},
+		{path: "examples", ignored: true},
+		{path: "examples/demo", ignored: true},
+		{path: "services/examples", ignored: true},
+		{path: "commands/testdata/npm", ignored: true},
+		{path: "docs/guide/demo", ignored: true},
+		{path: "docs/demo", ignored: false},
+		{path: "services/docs/guide/demo", ignored: false},
+		{path: "tools/generator", ignored: true},
+		{path: "tools/lint", ignored: false},
+		{path: "services/api", ignored: false},
+		{path: "example", ignored: false},
+	}
+	for _, test := range testCases {
+		t.Run(test.path, func(t *testing.T) {
+			assert.Equal(t, test.ignored, params.IsPathIgnored(test.path))
+		})
+	}
+}
+
+func TestValidatePathIgnores(t *testing.T) {
+	params := Params{PathIgnores: []string{"examples/", "**/testdata"}}
+	assert.NoError(t, params.validatePathIgnores())
+	params.PathIgnores = []string{"examples/[a-"}
+	assert.EqualError(t, params.validatePathIgnores(), "the pathIgnores pattern 'examples/[a-' is invalid")
+	params.PathIgnores = []string{"/"}
+	assert.EqualError(t, params.validatePathIgnores(), "the pathIgnores pattern '/' is invalid")
+}
+
+func TestNewConfigAggregatorPathIgnores(t *testing.T) {
+	configContent := `
+- params:
+    git:
+      repoName: frogbot
+    pathIgnores: [examples/]
+    scan:
+      projects:
+        - workingDirs: [services/api, examples/demo]
+        - workingDirs: [examples/sdk]
+        - workingDirs: [.]
+`
+	var configData FrogbotConfigAggregator
+	assert.NoError(t, yaml.Unmarshal([]byte(configContent), &configData))
+	configAggregator, err := NewConfigAggregator(&configData, Git{}, &config.ServerDetails{}, true)
+	assert.NoError(t, err)
+	projects := configAggregator[0].Projects
+	assert.Len(t, projects, 4)
+	assert.Equal(t, []string{"services/api"}, projects[0].WorkingDirs)
+	assert.False(t, projects[0].PathIgnored)
+	assert.Equal(t, []string{"examples/demo"}, projects[1].WorkingDirs)
+	assert.True(t, projects[1].PathIgnored)
+	assert.Equal(t, []string{"examples/sdk"}, projects[2].WorkingDirs)
+	assert.True(t, projects[2].PathIgnored)
+	// The root working dir isn't split, and its issues are matched against the patterns by the dirs of their modules and files
+	assert.Equal(t, []string{RootDir}, projects[3].WorkingDirs)
+	assert.False(t, projects[3].PathIgnored)
+	assert.Equal(t, []string{"examples/"}, projects[3].PathIgnores)
+	assert.True(t, projects[3].IsPathIgnored("examples/chart/templates/deployment.yaml"))
+	assert.False(t, projects[3].IsPathIgnored("chart/templates/deployment.yaml"))
+
+	// The working dirs of the ignored paths aren't scanned, if their issues are hidden
+	configData[0].HidePathIgnoredIssues = true
+	configAggregator, err = NewConfigAggregator(&configData, Git{}, &config.ServerDetails{}, true)
+	assert.NoError(t, err)
+	projects = configAggregator[0].Projects
+	assert.Len(t, projects, 2)
+	assert.Equal(t, []string{"services/api"}, projects[0].WorkingDirs)
+	assert.Equal(t, []string{RootDir}, projects[1].WorkingDirs)
+}
+
+func TestGetModulesDirs(t *testing.T) {
+	baseWd := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(baseWd, "examples", "demo"), 0700))
+	assert.NoError(t, os.MkdirAll(filepath.Join(baseWd, "node_modules", "lodash"), 0700))
+	assert.NoError(t, os.WriteFile(filepath.Join(baseWd, "go.mod"), []byte("module github.com/jfrog/frogbot\n"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(baseWd, "examples", "demo", "package.json"), []byte(`{"name": "demo"}`), 0600))
+	// The installed dependencies aren't modules of the repository
+	assert.NoError(t, os.WriteFile(filepath.Join(baseWd, "node_modules", "lodash", "package.json"), []byte(`{"name": "lodash"}`), 0600))
+
+	modulesDirs := GetModulesDirs([]string{baseWd}, baseWd)
+	assert.Equal(t, map[string]string{"github.com/jfrog/frogbot": RootDir, "demo": "examples/demo"}, modulesDirs)
+
+	project := &Project{PathIgnores: []string{"examples/"}}
+	newRow := func(modules ...string) *formats.VulnerabilityOrViolationRow {
+		row := &formats.VulnerabilityOrViolationRow{}
+		for _, module := range modules {
+			row.ImpactPaths = append(row.ImpactPaths, []formats.ComponentRow{{Name: module}, {Name: "lodash"}})
+		}
+		return row
+	}
+	assert.True(t, project.IsIssuePathIgnored(newRow("demo"), modulesDirs))
+	// An issue which is also found in a module outside the ignored paths, or in an unknown module, fails the scan
+	assert.False(t, project.IsIssuePathIgnored(newRow("demo", "github.com/jfrog/frogbot"), modulesDirs))
+	assert.False(t, project.IsIssuePathIgnored(newRow("other"), modulesDirs))
+	assert.False(t, project.IsIssuePathIgnored(newRow(), modulesDirs))
+	assert.True(t, (&Project{PathIgnored: true}).IsIssuePathIgnored(newRow(), nil))
+}
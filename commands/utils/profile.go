@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The name of the profile selected using the --profile flag
+var selectedProfile string
+
+func SetProfile(profile string) {
+	selectedProfile = profile
+}
+
+// Return the profile selected using the --profile flag, or using the JF_PROFILE environment variable
+func getSelectedProfile() string {
+	if selectedProfile != "" {
+		return selectedProfile
+	}
+	return getTrimmedEnv(ProfileEnv)
+}
+
+// Profile is a named set of overrides of the severity policy and fail behavior, such as 'strict' for pull requests gating and 'lenient' for nightly reporting
+type Profile struct {
+	SeverityPolicy       `yaml:",inline"`
+	FailOnSecurityIssues *bool `yaml:"failOnSecurityIssues,omitempty"`
+	FailOnScanError      *bool `yaml:"failOnScanError,omitempty"`
+}
+
+// applyProfile merges the given profile over the parameters of the repository and of all its projects.
+// The values which aren't set in the profile are kept.
+func (p *Params) applyProfile(profileName string) error {
+	if profileName == "" {
+		return nil
+	}
+	profile, exists := p.Profiles[profileName]
+	if !exists {
+		return fmt.Errorf(errUnknownProfile, profileName)
+	}
+	if err := profile.validateSeverities(); err != nil {
+		return err
+	}
+	log.Info("Applying the", profileName, "profile")
+	p.SeverityPolicy = profile.mergeSeverityPolicy(p.SeverityPolicy)
+	p.EcosystemPolicies = profile.mergeEcosystemPolicies(p.EcosystemPolicies)
+	for index := range p.Projects {
+		p.Projects[index].SeverityPolicy = profile.mergeSeverityPolicy(p.Projects[index].SeverityPolicy)
+		p.Projects[index].EcosystemPolicies = p.EcosystemPolicies
+	}
+	if profile.FailOnSecurityIssues != nil {
+		p.FailOnSecurityIssues = profile.FailOnSecurityIssues
+	}
+	if profile.FailOnScanError != nil {
+		p.FailOnScanError = profile.FailOnScanError
+	}
+	return nil
+}
+
+func (profile *Profile) mergeSeverityPolicy(severityPolicy SeverityPolicy) SeverityPolicy {
+	merged := profile.SeverityPolicy
+	mergeDefaults(reflect.ValueOf(&merged).Elem(), reflect.ValueOf(severityPolicy))
+	return merged
+}
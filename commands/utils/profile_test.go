@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestNewConfigAggregatorProfiles(t *testing.T) {
+	configContent := `
+- params:
+    git:
+      repoName: frogbot
+    scan:
+      minSeverity: Low
+      failSeverityThreshold: High
+      projects:
+        - workingDirs: [payments]
+          failSeverityThreshold: Medium
+    profiles:
+      strict:
+        failSeverityThreshold: Low
+        failOnScanError: true
+      lenient:
+        minSeverity: High
+        failOnSecurityIssues: false
+        failOnScanError: false
+`
+	var configData FrogbotConfigAggregator
+	assert.NoError(t, yaml.Unmarshal([]byte(configContent), &configData))
+	defer SetProfile("")
+
+	// Without a selected profile, the base configuration is used
+	configAggregator, err := NewConfigAggregator(&configData, Git{}, &config.ServerDetails{}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, SeverityPolicy{MinSeverity: "Low", FailSeverityThreshold: "High"}, configAggregator[0].SeverityPolicy)
+	assert.True(t, configAggregator[0].ShouldFailOnScanError())
+
+	SetProfile("strict")
+	configAggregator, err = NewConfigAggregator(&configData, Git{}, &config.ServerDetails{}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, SeverityPolicy{MinSeverity: "Low", FailSeverityThreshold: "Low"}, configAggregator[0].SeverityPolicy)
+	assert.Equal(t, SeverityPolicy{MinSeverity: "Low", FailSeverityThreshold: "Low"}, configAggregator[0].Projects[0].SeverityPolicy)
+	assert.True(t, *configAggregator[0].FailOnSecurityIssues)
+
+	SetProfile("lenient")
+	configAggregator, err = NewConfigAggregator(&configData, Git{}, &config.ServerDetails{}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, SeverityPolicy{MinSeverity: "High", FailSeverityThreshold: "High"}, configAggregator[0].SeverityPolicy)
+	assert.Equal(t, SeverityPolicy{MinSeverity: "High", FailSeverityThreshold: "Medium"}, configAggregator[0].Projects[0].SeverityPolicy)
+	assert.False(t, *configAggregator[0].FailOnSecurityIssues)
+	assert.False(t, configAggregator[0].ShouldFailOnScanError())
+
+	SetProfile("nightly")
+	_, err = NewConfigAggregator(&configData, Git{}, &config.ServerDetails{}, true)
+	assert.EqualError(t, err, fmt.Sprintf(errUnknownProfile, "nightly"))
+}
+
+func TestGetSelectedProfile(t *testing.T) {
+	defer SetProfile("")
+	t.Setenv(ProfileEnv, "lenient")
+	assert.Equal(t, "lenient", getSelectedProfile())
+	// The flag overrides the environment variable
+	SetProfile("strict")
+	assert.Equal(t, "strict", getSelectedProfile())
+}
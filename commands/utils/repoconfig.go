@@ -0,0 +1,209 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"gopkg.in/yaml.v3"
+)
+
+// True if the scan section of the frogbot-config file of the scanned pull request is merged into the configuration, as set using the --config-from-repo flag
+var configFromRepo bool
+
+func SetConfigFromRepo(fromRepo bool) {
+	configFromRepo = fromRepo
+}
+
+func IsConfigFromRepo() bool {
+	return configFromRepo
+}
+
+// ApplyRepoConfig merges the scan section of the frogbot-config file in the given dir, which is the source branch of the scanned pull request, over the scan params.
+// The other sections, such as the git and jfrogPlatform sections, are taken from the external configuration only.
+// Unless repoConfigCanRelaxGating is set in the external configuration, the repository config can't relax the gating of the pull request,
+// so that pull request authors can't weaken the gate of their own pull requests.
+func (p *Params) ApplyRepoConfig(dir string) error {
+	content, err := os.ReadFile(filepath.Join(dir, osFrogbotConfigPath))
+	if os.IsNotExist(err) {
+		log.Info("The scanned pull request doesn't include", osFrogbotConfigPath+". Using the external configuration only")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var repoConfigData FrogbotConfigAggregator
+	if err = yaml.Unmarshal(content, &repoConfigData); err != nil {
+		return fmt.Errorf(errInvalidRepoConfig, err.Error())
+	}
+	if err = setConfigParamsPaths(content, repoConfigData); err != nil {
+		return fmt.Errorf(errInvalidRepoConfig, err.Error())
+	}
+	repoScan, err := getRepoConfigScan(repoConfigData, p.RepoName)
+	if err != nil {
+		return err
+	}
+	log.Info("Merging the scan section of the", osFrogbotConfigPath, "file of the scanned pull request")
+	return p.mergeRepoScan(repoScan)
+}
+
+// Return the scan section of the repository in the repository config, merged with its defaults.
+// The repository is chosen by its name, or is the only repository of the file. If the file has no matching repository, the defaults are used.
+func getRepoConfigScan(repoConfigData FrogbotConfigAggregator, repoName string) (Scan, error) {
+	repositories, defaults, err := splitGlobalDefaults(repoConfigData)
+	if err != nil {
+		return Scan{}, err
+	}
+	var repoConfig FrogbotRepoConfig
+	for _, repository := range repositories {
+		if repository.RepoName == repoName || len(repositories) == 1 {
+			repoConfig = repository
+			break
+		}
+	}
+	repoConfig.mergeParamsDefaults(defaults)
+	return repoConfig.Scan, nil
+}
+
+// mergeRepoScan sets the scan params of the repository config over the scan params of the external configuration.
+// The unset params are kept. If the repository config sets projects and repoConfigCanRelaxGating is set, they replace the projects of the external configuration,
+// and are expanded like the projects of the external configuration. Otherwise, the severity policy of the repository config applies to the external projects,
+// and the projects of the repository config may only tighten the external projects which scan the same working dirs.
+func (p *Params) mergeRepoScan(repoScan Scan) error {
+	if err := repoScan.validateSeverities(); err != nil {
+		return err
+	}
+	if err := repoScan.validateEcosystemPolicies(); err != nil {
+		return err
+	}
+	if err := validateIgnoredIssues(repoScan.IgnoredIssues); err != nil {
+		return err
+	}
+	if err := validateIgnoredDependencies(repoScan.IgnoredDependencies); err != nil {
+		return err
+	}
+	external := p.Scan
+	merged := repoScan
+	baseline := external
+	baseline.Projects = nil
+	mergeDefaults(reflect.ValueOf(&merged).Elem(), reflect.ValueOf(baseline))
+	// Copy the projects, to keep the config data unchanged when the projects are expanded
+	merged.Projects = append([]Project(nil), repoScan.Projects...)
+	if !p.RepoConfigCanRelaxGating {
+		tightenRepoScanGating(&merged, &external)
+	}
+	p.Scan = merged
+	if p.RepoConfigCanRelaxGating && len(p.Projects) > 0 {
+		for index := range p.Projects {
+			SetProjectInstallCommand(p.Projects[index].InstallCommand, &p.Projects[index])
+		}
+		return p.expandProjects()
+	}
+	if len(repoScan.Projects) > 0 && !p.RepoConfigCanRelaxGating {
+		log.Warn("The projects of the repository config don't replace the external projects, since repoConfigCanRelaxGating isn't set. " +
+			"Only their stricter severities and scanIaC apply to the external projects with the same working dirs")
+	}
+	p.Projects = append([]Project{}, external.Projects...)
+	for index := range p.Projects {
+		project := &p.Projects[index]
+		project.MinSeverity = mergeProjectSeverity(project.MinSeverity, repoScan.MinSeverity, p.RepoConfigCanRelaxGating)
+		project.FailSeverityThreshold = mergeProjectSeverity(project.FailSeverityThreshold, repoScan.FailSeverityThreshold, p.RepoConfigCanRelaxGating)
+		if repoProject := findProjectByWorkingDirs(repoScan.Projects, project.WorkingDirs); repoProject != nil {
+			project.MinSeverity = mergeProjectSeverity(project.MinSeverity, repoProject.MinSeverity, false)
+			project.FailSeverityThreshold = mergeProjectSeverity(project.FailSeverityThreshold, repoProject.FailSeverityThreshold, false)
+			project.ScanIaC = project.ScanIaC || repoProject.ScanIaC
+		}
+		project.EcosystemPolicies = p.EcosystemPolicies
+		if p.RepoConfigCanRelaxGating {
+			project.IgnoredIssues = append(append([]string{}, project.IgnoredIssues...), repoScan.IgnoredIssues...)
+		}
+		project.IgnoredDependencies = p.IgnoredDependencies
+	}
+	return nil
+}
+
+// Return the project which scans the same working dirs, or nil if there's no such project. A project without working dirs scans the root dir.
+func findProjectByWorkingDirs(projects []Project, workingDirs []string) *Project {
+	normalize := func(dirs []string) []string {
+		if len(dirs) == 0 {
+			return []string{RootDir}
+		}
+		normalized := make([]string, 0, len(dirs))
+		for _, dir := range dirs {
+			normalized = append(normalized, filepath.ToSlash(filepath.Clean(dir)))
+		}
+		sort.Strings(normalized)
+		return normalized
+	}
+	expected := normalize(workingDirs)
+	for index := range projects {
+		if reflect.DeepEqual(normalize(projects[index].WorkingDirs), expected) {
+			return &projects[index]
+		}
+	}
+	return nil
+}
+
+// Keep the gating of the external configuration wherever the repository config relaxes it:
+// the fail behavior, the severity policy, the ignored issues and dependencies and the scan of the changed modules only.
+// The policies of the ecosystems of the repository config are ignored, since an ecosystem policy may relax the policy of the projects.
+// The projects of the repository config are merged into the external projects by mergeRepoScan, since they could narrow the scanned working dirs or change the install commands.
+func tightenRepoScanGating(merged, external *Scan) {
+	if external.FailOnSecurityIssues != nil && *external.FailOnSecurityIssues && merged.FailOnSecurityIssues != nil && !*merged.FailOnSecurityIssues {
+		log.Warn("failOnSecurityIssues is relaxed by the repository config. Keeping the external value, since repoConfigCanRelaxGating isn't set")
+		merged.FailOnSecurityIssues = external.FailOnSecurityIssues
+	}
+	merged.MinSeverity = stricterSeverity("minSeverity", merged.MinSeverity, external.MinSeverity)
+	merged.FailSeverityThreshold = stricterSeverity("failSeverityThreshold", merged.FailSeverityThreshold, external.FailSeverityThreshold)
+	merged.IgnoredIssues = keepExternalEntries("ignored issue", merged.IgnoredIssues, external.IgnoredIssues)
+	merged.IgnoredDependencies = keepExternalEntries("ignored dependency", merged.IgnoredDependencies, external.IgnoredDependencies)
+	if len(merged.EcosystemPolicies) > 0 && !reflect.DeepEqual(merged.EcosystemPolicies, external.EcosystemPolicies) {
+		log.Warn("The ecosystemPolicies of the repository config are ignored, since repoConfigCanRelaxGating isn't set")
+		merged.EcosystemPolicies = external.EcosystemPolicies
+	}
+	if merged.ScanChangedOnly && !external.ScanChangedOnly {
+		log.Warn("scanChangedOnly is relaxed by the repository config. Keeping the external value, since repoConfigCanRelaxGating isn't set")
+		merged.ScanChangedOnly = false
+	}
+}
+
+// Return the ignored issues or dependencies of the repository config which are also ignored by the external configuration
+func keepExternalEntries(entryType string, entries, externalEntries []string) (keptEntries []string) {
+	external := make(map[string]bool)
+	for _, entry := range externalEntries {
+		external[entry] = true
+	}
+	for _, entry := range entries {
+		if external[entry] {
+			keptEntries = append(keptEntries, entry)
+			continue
+		}
+		log.Warn("The "+entryType, "'"+entry+"'", "added by the repository config is ignored, since repoConfigCanRelaxGating isn't set")
+	}
+	return
+}
+
+// Return the stricter out of the severity set in the repository config and the external severity. A lower severity is stricter,
+// and an unset severity, which shows and fails on all the issues, is the strictest.
+func stricterSeverity(paramName, severity, externalSeverity string) string {
+	if GetSeverityNumValue(severity) > GetSeverityNumValue(externalSeverity) {
+		log.Warn(paramName, "is relaxed by the repository config. Keeping the external value, since repoConfigCanRelaxGating isn't set")
+		return externalSeverity
+	}
+	return severity
+}
+
+// Apply the severity of the scan section of the repository config to a project of the external configuration.
+// If the gating can't be relaxed, the stricter severity is used.
+func mergeProjectSeverity(projectSeverity, repoSeverity string, canRelaxGating bool) string {
+	if repoSeverity == "" {
+		return projectSeverity
+	}
+	if canRelaxGating || GetSeverityNumValue(repoSeverity) < GetSeverityNumValue(projectSeverity) {
+		return repoSeverity
+	}
+	return projectSeverity
+}
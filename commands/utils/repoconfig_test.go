@@ -0,0 +1,162 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func createRepoConfigTestParams() Params {
+	failOnSecurityIssues := true
+	return Params{
+		Git: Git{RepoName: "frogbot"},
+		Scan: Scan{
+			FailOnSecurityIssues: &failOnSecurityIssues,
+			IgnoredIssues:        []string{"CVE-2022-24450"},
+			SeverityPolicy:       SeverityPolicy{MinSeverity: "Medium", FailSeverityThreshold: "High"},
+			Projects:             []Project{{WorkingDirs: []string{"."}, SeverityPolicy: SeverityPolicy{MinSeverity: "Medium", FailSeverityThreshold: "High"}}},
+		},
+	}
+}
+
+func writeRepoConfig(t *testing.T, content string) string {
+	dir, err := fileutils.CreateTempDir()
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, fileutils.RemoveTempDir(dir))
+	})
+	if content != "" {
+		assert.NoError(t, os.MkdirAll(filepath.Join(dir, frogbotConfigDir), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, osFrogbotConfigPath), []byte(content), 0644))
+	}
+	return dir
+}
+
+func TestApplyRepoConfigTightensGating(t *testing.T) {
+	dir := writeRepoConfig(t, `
+- params:
+    git:
+      repoName: frogbot
+    scan:
+      failOnSecurityIssues: false
+      minSeverity: Low
+      failSeverityThreshold: Critical
+      ignoredIssues: [CVE-2022-24450, CVE-2023-1234]
+      scanChangedOnly: true
+      showRemediationCommands: true
+      ecosystemPolicies:
+        npm:
+          failSeverityThreshold: Critical
+`)
+	params := createRepoConfigTestParams()
+	assert.NoError(t, params.ApplyRepoConfig(dir))
+	assert.Empty(t, params.EcosystemPolicies)
+	// The params which don't affect the gating are merged, and the relaxing params keep the external values
+	assert.True(t, params.ShowRemediationCommands)
+	assert.True(t, *params.FailOnSecurityIssues)
+	assert.Equal(t, SeverityPolicy{MinSeverity: "Low", FailSeverityThreshold: "High"}, params.SeverityPolicy)
+	assert.Equal(t, []string{"CVE-2022-24450"}, params.IgnoredIssues)
+	assert.False(t, params.ScanChangedOnly)
+	if assert.Len(t, params.Projects, 1) {
+		assert.Equal(t, SeverityPolicy{MinSeverity: "Low", FailSeverityThreshold: "High"}, params.Projects[0].SeverityPolicy)
+	}
+}
+
+func TestApplyRepoConfigRelaxesGating(t *testing.T) {
+	dir := writeRepoConfig(t, `
+- params:
+    scan:
+      failOnSecurityIssues: false
+      failSeverityThreshold: Critical
+      ignoredIssues: [CVE-2023-1234]
+`)
+	params := createRepoConfigTestParams()
+	params.RepoConfigCanRelaxGating = true
+	assert.NoError(t, params.ApplyRepoConfig(dir))
+	assert.False(t, *params.FailOnSecurityIssues)
+	assert.Equal(t, SeverityPolicy{MinSeverity: "Medium", FailSeverityThreshold: "Critical"}, params.SeverityPolicy)
+	assert.Equal(t, []string{"CVE-2023-1234"}, params.IgnoredIssues)
+	if assert.Len(t, params.Projects, 1) {
+		assert.Equal(t, "Critical", params.Projects[0].FailSeverityThreshold)
+		assert.Equal(t, []string{"CVE-2023-1234"}, params.Projects[0].IgnoredIssues)
+	}
+}
+
+func TestApplyRepoConfigProjects(t *testing.T) {
+	dir := writeRepoConfig(t, `
+- params:
+    git:
+      repoName: frogbot
+    scan:
+      projects:
+        - workingDirs: [api]
+          installCommand: npm ci
+          failSeverityThreshold: Critical
+          ignoredIssues: [CVE-2023-1234]
+          ecosystems: [npm]
+`)
+	params := createRepoConfigTestParams()
+	params.RepoConfigCanRelaxGating = true
+	params.IgnoredIssues = nil
+	assert.NoError(t, params.ApplyRepoConfig(dir))
+	// The projects of the repository config replace the external projects, and are expanded with the external scan params
+	if assert.Len(t, params.Projects, 1) {
+		project := params.Projects[0]
+		assert.Equal(t, []string{"api"}, project.WorkingDirs)
+		assert.Equal(t, "npm", project.InstallCommandName)
+		assert.Equal(t, []string{"ci"}, project.InstallCommandArgs)
+		assert.Equal(t, SeverityPolicy{MinSeverity: "Medium", FailSeverityThreshold: "Critical"}, project.SeverityPolicy)
+		assert.Equal(t, []string{"CVE-2023-1234"}, project.IgnoredIssues)
+		assert.Equal(t, []string{"npm"}, project.Ecosystems)
+	}
+}
+
+func TestApplyRepoConfigProjectsTightenGating(t *testing.T) {
+	dir := writeRepoConfig(t, `
+- params:
+    git:
+      repoName: frogbot
+    scan:
+      projects:
+        - workingDirs: [api]
+          installCommand: npm ci
+        - workingDirs: ["./"]
+          pipRequirementsFile: requirements-dev.txt
+          minSeverity: Low
+          failSeverityThreshold: Critical
+          scanIaC: true
+          ignoredIssues: [CVE-2023-1234]
+          ecosystems: [npm]
+`)
+	params := createRepoConfigTestParams()
+	assert.NoError(t, params.ApplyRepoConfig(dir))
+	// The external projects are kept, and the project of the repository config with the same working dirs may only tighten their gating
+	if assert.Len(t, params.Projects, 1) {
+		project := params.Projects[0]
+		assert.Equal(t, []string{"."}, project.WorkingDirs)
+		assert.Empty(t, project.InstallCommandName)
+		assert.Empty(t, project.PipRequirementsFile)
+		assert.Equal(t, SeverityPolicy{MinSeverity: "Low", FailSeverityThreshold: "High"}, project.SeverityPolicy)
+		assert.True(t, project.ScanIaC)
+		assert.Empty(t, project.IgnoredIssues)
+		assert.Empty(t, project.Ecosystems)
+	}
+}
+
+func TestApplyRepoConfigMissingOrInvalid(t *testing.T) {
+	// Without a repository config, the external configuration is used as is
+	params := createRepoConfigTestParams()
+	assert.NoError(t, params.ApplyRepoConfig(writeRepoConfig(t, "")))
+	assert.Equal(t, createRepoConfigTestParams(), params)
+
+	assert.Error(t, params.ApplyRepoConfig(writeRepoConfig(t, "- params: [")))
+	assert.EqualError(t, params.ApplyRepoConfig(writeRepoConfig(t, `
+- params:
+    scan:
+      minSeverity: Severe
+`)), fmt.Sprintf(errInvalidSeverity, "Severe", "minSeverity"))
+}
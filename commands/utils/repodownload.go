@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/xanzy/go-gitlab"
+)
+
+const (
+	defaultRepoDownloadAttempts = 3
+
+	errMultipleRepoDownloadAttempts = "all the repositories in the frogbot-config file must use the same number of repository download attempts"
+	errInvalidRepoDownloadAttempts  = "the number of repository download attempts %d is invalid. A positive number of attempts is expected"
+)
+
+// The status of the responses of the git clients which return the response status as part of the error message, such as "server response: 503 Service Unavailable"
+var responseStatusErrorPattern = regexp.MustCompile(`^server response: (\d{3})`)
+
+// The number of attempts to download a repository archive. If zero, defaultRepoDownloadAttempts attempts are made.
+var repoDownloadAttempts int
+
+// The delay before the second attempt to download a repository archive, which is doubled after each failed attempt
+var repoDownloadRetryDelay = 2 * time.Second
+
+// ConfigureRepoDownloadAttempts sets the number of attempts to download a repository archive, if the download fails due to a transient error.
+// attempts - The number of attempts. If zero, the default number of attempts is used.
+func ConfigureRepoDownloadAttempts(attempts int) {
+	repoDownloadAttempts = attempts
+}
+
+func configureRepoDownloadAttemptsFromEnv() error {
+	envValue := getTrimmedEnv(RepoDownloadAttemptsEnv)
+	if envValue == "" {
+		return nil
+	}
+	attempts, err := strconv.Atoi(envValue)
+	if err != nil || attempts < 1 {
+		return fmt.Errorf("the value of the %s environment is expected to be a positive number of attempts. The value received however is %s", RepoDownloadAttemptsEnv, envValue)
+	}
+	ConfigureRepoDownloadAttempts(attempts)
+	return nil
+}
+
+func (p *Params) validateRepoDownloadAttempts() error {
+	if p.RepoDownloadAttempts < 0 {
+		return fmt.Errorf(errInvalidRepoDownloadAttempts, p.RepoDownloadAttempts)
+	}
+	return nil
+}
+
+func getConfiguredRepoDownloadAttempts(configAggregator FrogbotConfigAggregator) (attempts int, err error) {
+	for _, repo := range configAggregator {
+		if repo.RepoDownloadAttempts == 0 {
+			continue
+		}
+		if attempts != 0 && attempts != repo.RepoDownloadAttempts {
+			return 0, errors.New(errMultipleRepoDownloadAttempts)
+		}
+		attempts = repo.RepoDownloadAttempts
+	}
+	return
+}
+
+// downloadRepository downloads and extracts the archive of the branch to wd. If the download fails due to a transient error, such as a 5xx response,
+// a timeout or an archive truncated by a dropped connection, the download is retried with an exponential backoff. Other errors, such as a 404 or a 401 response,
+// or an archive which isn't a tar.gz archive at all, are returned without a retry.
+func downloadRepository(client vcsclient.VcsClient, branch string, git *Git, wd string) error {
+	attempts := repoDownloadAttempts
+	if attempts < 1 {
+		attempts = defaultRepoDownloadAttempts
+	}
+	delay := repoDownloadRetryDelay
+	for attempt := 1; ; attempt++ {
+		retryable, err := downloadRepositoryAttempt(client, branch, git, wd)
+		if err == nil || !retryable || attempt >= attempts {
+			return err
+		}
+		log.Warn(fmt.Sprintf("Attempt %d of %d to download repository %s/%s, branch %s failed: %s\nRetrying in %s", attempt, attempts, git.RepoOwner, git.RepoName, branch, err.Error(), delay))
+		time.Sleep(delay)
+		delay *= 2
+		// The files extracted by the failed attempt are removed, so that the next attempt extracts the archive to an empty directory
+		if err = removeDirContent(wd); err != nil {
+			return err
+		}
+	}
+}
+
+// Download and extract the archive once, and validate the extracted archive. Returns whether the failure may be transient.
+func downloadRepositoryAttempt(client vcsclient.VcsClient, branch string, git *Git, wd string) (retryable bool, err error) {
+	err = client.DownloadRepository(context.Background(), git.RepoOwner, git.RepoName, branch, wd)
+	if err == nil {
+		return false, validateDownloadedRepo(wd, branch, git)
+	}
+	if isInvalidArchiveError(err) {
+		// An archive truncated by a dropped connection is retried, while an archive which isn't a tar.gz archive, such as an HTML page, won't be fixed by a retry
+		return errors.Is(err, io.ErrUnexpectedEOF) || err.Error() == io.ErrUnexpectedEOF.Error(), fmt.Errorf(errInvalidRepoArchive, git.RepoOwner, git.RepoName, branch, err.Error())
+	}
+	return isRetryableDownloadError(err), err
+}
+
+// The timeouts, the connection errors, and the 5xx, 408 and 429 responses are retryable
+func isRetryableDownloadError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	statusCode := getErrorStatusCode(err)
+	return statusCode >= http.StatusInternalServerError || statusCode == http.StatusRequestTimeout || statusCode == http.StatusTooManyRequests
+}
+
+// Return the status of the response of the git provider which failed the request, or 0 if the status is unknown
+func getErrorStatusCode(err error) int {
+	var gitHubErr *github.ErrorResponse
+	if errors.As(err, &gitHubErr) && gitHubErr.Response != nil {
+		return gitHubErr.Response.StatusCode
+	}
+	var gitLabErr *gitlab.ErrorResponse
+	if errors.As(err, &gitLabErr) && gitLabErr.Response != nil {
+		return gitLabErr.Response.StatusCode
+	}
+	if match := responseStatusErrorPattern.FindStringSubmatch(err.Error()); match != nil {
+		statusCode, _ := strconv.Atoi(match[1])
+		return statusCode
+	}
+	return 0
+}
+
+func removeDirContent(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err = os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-github/v45/github"
+	"github.com/jfrog/frogbot/commands/testdata"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/xanzy/go-gitlab"
+)
+
+func setRepoDownloadRetries(t *testing.T, attempts int) {
+	previousAttempts, previousDelay := repoDownloadAttempts, repoDownloadRetryDelay
+	ConfigureRepoDownloadAttempts(attempts)
+	repoDownloadRetryDelay = 0
+	t.Cleanup(func() {
+		repoDownloadAttempts, repoDownloadRetryDelay = previousAttempts, previousDelay
+	})
+}
+
+func TestDownloadRepoToTempDirRetry(t *testing.T) {
+	setRepoDownloadRetries(t, 0)
+	git := &Git{RepoOwner: "jfrog", RepoName: "frogbot"}
+	archive := createTestArchive(t, map[string]string{"go.mod": "module github.com/jfrog/frogbot\n"})
+	client := testdata.NewMockVcsClient(gomock.NewController(t))
+	gomock.InOrder(
+		client.EXPECT().DownloadRepository(context.Background(), "jfrog", "frogbot", "main", gomock.Any()).Return(errors.New("server response: 503 Service Unavailable")),
+		// A truncated archive, whose partially extracted files are removed before the next attempt
+		client.EXPECT().DownloadRepository(context.Background(), "jfrog", "frogbot", "main", gomock.Any()).DoAndReturn(
+			func(_ context.Context, _, _, _, localPath string) error {
+				return vcsutils.Untar(localPath, bytes.NewReader(archive[:len(archive)/2]), true)
+			}),
+		client.EXPECT().DownloadRepository(context.Background(), "jfrog", "frogbot", "main", gomock.Any()).DoAndReturn(
+			func(_ context.Context, _, _, _, localPath string) error {
+				return vcsutils.Untar(localPath, bytes.NewReader(archive), true)
+			}),
+	)
+	wd, cleanup, err := DownloadRepoToTempDir(client, "main", git)
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(wd, "go.mod"))
+	assert.NoError(t, cleanup())
+}
+
+func TestDownloadRepoToTempDirRetriesExhausted(t *testing.T) {
+	setRepoDownloadRetries(t, 2)
+	client := testdata.NewMockVcsClient(gomock.NewController(t))
+	client.EXPECT().DownloadRepository(context.Background(), "jfrog", "frogbot", "main", gomock.Any()).Return(errors.New("server response: 502 Bad Gateway")).Times(2)
+	_, _, err := DownloadRepoToTempDir(client, "main", &Git{RepoOwner: "jfrog", RepoName: "frogbot"})
+	assert.EqualError(t, err, "server response: 502 Bad Gateway")
+}
+
+func TestDownloadRepoToTempDirFatalError(t *testing.T) {
+	setRepoDownloadRetries(t, 5)
+	// A 404 response isn't retried
+	client := testdata.NewMockVcsClient(gomock.NewController(t))
+	client.EXPECT().DownloadRepository(context.Background(), "jfrog", "frogbot", "main", gomock.Any()).Return(errors.New("server response: 404 Not Found")).Times(1)
+	_, _, err := DownloadRepoToTempDir(client, "main", &Git{RepoOwner: "jfrog", RepoName: "frogbot"})
+	assert.EqualError(t, err, "server response: 404 Not Found")
+}
+
+func TestIsRetryableDownloadError(t *testing.T) {
+	testRequest := &http.Request{Method: http.MethodGet, URL: &url.URL{Scheme: "https", Host: "api.github.com"}}
+	testCases := []struct {
+		err       error
+		retryable bool
+	}{
+		{err: errors.New("server response: 500 Internal Server Error"), retryable: true},
+		{err: errors.New("server response: 429 Too Many Requests"), retryable: true},
+		{err: errors.New("server response: 401 Unauthorized"), retryable: false},
+		{err: &github.ErrorResponse{Response: &http.Response{Request: testRequest, StatusCode: http.StatusServiceUnavailable}}, retryable: true},
+		{err: &github.ErrorResponse{Response: &http.Response{Request: testRequest, StatusCode: http.StatusNotFound}}, retryable: false},
+		{err: fmt.Errorf("download failed: %w", &gitlab.ErrorResponse{Response: &http.Response{Request: testRequest, StatusCode: http.StatusGatewayTimeout}}), retryable: true},
+		{err: &gitlab.ErrorResponse{Response: &http.Response{Request: testRequest, StatusCode: http.StatusUnauthorized}}, retryable: false},
+		{err: &url.Error{Op: "Get", URL: "https://github.com", Err: context.DeadlineExceeded}, retryable: true},
+		{err: errors.New("the repository doesn't exist"), retryable: false},
+	}
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.retryable, isRetryableDownloadError(testCase.err), testCase.err.Error())
+	}
+}
+
+func TestGetConfiguredRepoDownloadAttempts(t *testing.T) {
+	attempts, err := getConfiguredRepoDownloadAttempts(FrogbotConfigAggregator{{}, {Params: Params{RepoDownloadAttempts: 5}}})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, attempts)
+
+	_, err = getConfiguredRepoDownloadAttempts(FrogbotConfigAggregator{{Params: Params{RepoDownloadAttempts: 5}}, {Params: Params{RepoDownloadAttempts: 2}}})
+	assert.EqualError(t, err, errMultipleRepoDownloadAttempts)
+
+	params := Params{RepoDownloadAttempts: -1}
+	assert.EqualError(t, params.validateRepoDownloadAttempts(), fmt.Sprintf(errInvalidRepoDownloadAttempts, -1))
+}
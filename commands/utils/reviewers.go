@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	reviewersBySeverityParam     = "reviewersBySeverity"
+	reviewersBySeverityDelimiter = ";"
+	reviewersAssignmentSymbol    = "="
+	reviewersDelimiter           = ","
+)
+
+// Validate the reviewersBySeverity param, and return it with its severities set to their titles, so that they're matched regardless of their case
+func (p *Params) getTitledReviewersBySeverity() (map[string][]string, error) {
+	if len(p.ReviewersBySeverity) == 0 {
+		return p.ReviewersBySeverity, nil
+	}
+	reviewersBySeverity := make(map[string][]string, len(p.ReviewersBySeverity))
+	for severity, reviewers := range p.ReviewersBySeverity {
+		if err := validateSeverity(reviewersBySeverityParam, severity); err != nil {
+			return nil, err
+		}
+		reviewersBySeverity[getSeverityTitle(severity)] = reviewers
+	}
+	return reviewersBySeverity, nil
+}
+
+// Parse the reviewers by severity environment variable, such as "Critical=my-org/security,alice;Low=bob"
+func parseReviewersBySeverity(envKey, envValue string) (map[string][]string, error) {
+	reviewersBySeverity := make(map[string][]string)
+	for _, pair := range strings.Split(envValue, reviewersBySeverityDelimiter) {
+		severity, reviewers, found := strings.Cut(pair, reviewersAssignmentSymbol)
+		if !found || strings.TrimSpace(reviewers) == "" {
+			return nil, fmt.Errorf(errInvalidSeverityReviewers, envKey, envValue)
+		}
+		reviewersBySeverity[strings.TrimSpace(severity)] = strings.Split(strings.ReplaceAll(reviewers, " ", ""), reviewersDelimiter)
+	}
+	return reviewersBySeverity, nil
+}
+
+// GetFixPRReviewers returns the reviewers of a fix pull request with the given severity, or the default fix pull requests reviewers if none are set for the severity
+func (p *Params) GetFixPRReviewers(severity string) []string {
+	if reviewers := p.ReviewersBySeverity[getSeverityTitle(severity)]; len(reviewers) > 0 {
+		return reviewers
+	}
+	return p.FixPRReviewers
+}
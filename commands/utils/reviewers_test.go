@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTitledReviewersBySeverity(t *testing.T) {
+	params := Params{ReviewersBySeverity: map[string][]string{"critical": {"my-org/security"}, "Low": {"bob"}}}
+	reviewersBySeverity, err := params.getTitledReviewersBySeverity()
+	assert.NoError(t, err)
+	// The severities are matched regardless of their case
+	assert.Equal(t, map[string][]string{"Critical": {"my-org/security"}, "Low": {"bob"}}, reviewersBySeverity)
+	// The params aren't modified
+	assert.Contains(t, params.ReviewersBySeverity, "critical")
+
+	params.ReviewersBySeverity = map[string][]string{"Severe": {"bob"}}
+	_, err = params.getTitledReviewersBySeverity()
+	assert.EqualError(t, err, fmt.Sprintf(errInvalidSeverity, "Severe", reviewersBySeverityParam))
+}
+
+func TestParseReviewersBySeverity(t *testing.T) {
+	reviewersBySeverity, err := parseReviewersBySeverity(ReviewersBySeverityEnv, "Critical=my-org/security, alice;Low=bob")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"Critical": {"my-org/security", "alice"}, "Low": {"bob"}}, reviewersBySeverity)
+
+	for _, envValue := range []string{"Critical", "Critical=", "Critical=alice;"} {
+		_, err = parseReviewersBySeverity(ReviewersBySeverityEnv, envValue)
+		assert.EqualError(t, err, fmt.Sprintf(errInvalidSeverityReviewers, ReviewersBySeverityEnv, envValue))
+	}
+}
+
+func TestGetFixPRReviewers(t *testing.T) {
+	params := Params{
+		ReviewersBySeverity: map[string][]string{"Critical": {"my-org/security"}},
+		FixPRReviewers:      []string{"my-org/app-team"},
+	}
+	assert.Equal(t, []string{"my-org/security"}, params.GetFixPRReviewers("Critical"))
+	// The default reviewers are requested for the other severities
+	assert.Equal(t, []string{"my-org/app-team"}, params.GetFixPRReviewers("Low"))
+	assert.Empty(t, (&Params{}).GetFixPRReviewers("Critical"))
+}
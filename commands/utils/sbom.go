@@ -0,0 +1,240 @@
+package utils
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+)
+
+const (
+	securityViolationType = "security"
+	nvdUrlPrefix          = "https://nvd.nist.gov/vuln/detail/"
+)
+
+// Xray package types to Package URL types
+var purlTypes = map[string]string{
+	"gav":   "maven",
+	"npm":   "npm",
+	"go":    "golang",
+	"pypi":  "pypi",
+	"pip":   "pypi",
+	"nuget": "nuget",
+}
+
+// sbomBuilder aggregates the components, dependencies and vulnerabilities of the scan results.
+// The scan results include the vulnerable components and their impact paths only, and therefore the dependency graph is partial.
+type sbomBuilder struct {
+	rootRefs        map[string]bool
+	components      map[string]cdx.Component
+	dependencies    map[string]map[string]bool
+	vulnerabilities map[string]*cdx.Vulnerability
+}
+
+// ExportCycloneDxSbom writes the dependency graph found during the scan, annotated with its vulnerabilities, as a CycloneDX 1.4 JSON SBOM.
+func ExportCycloneDxSbom(scanResults []services.ScanResponse, outputPath string) (err error) {
+	log.Info("Writing the CycloneDX SBOM to", outputPath)
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		e := file.Close()
+		if err == nil {
+			err = e
+		}
+	}()
+	encoder := cdx.NewBOMEncoder(file, cdx.BOMFileFormatJSON)
+	encoder.SetPretty(true)
+	return encoder.Encode(CreateCycloneDxSbom(scanResults))
+}
+
+// CreateCycloneDxSbom converts the scan results to a CycloneDX 1.4 BOM
+func CreateCycloneDxSbom(scanResults []services.ScanResponse) *cdx.BOM {
+	builder := sbomBuilder{
+		rootRefs:        map[string]bool{},
+		components:      map[string]cdx.Component{},
+		dependencies:    map[string]map[string]bool{},
+		vulnerabilities: map[string]*cdx.Vulnerability{},
+	}
+	for _, scanResult := range scanResults {
+		for _, vulnerability := range scanResult.Vulnerabilities {
+			builder.addIssue(vulnerability.IssueId, vulnerability.Summary, vulnerability.Severity, vulnerability.Cves, vulnerability.Components)
+		}
+		for _, violation := range scanResult.Violations {
+			if violation.ViolationType == securityViolationType {
+				builder.addIssue(violation.IssueId, violation.Summary, violation.Severity, violation.Cves, violation.Components)
+			}
+		}
+	}
+	return builder.build()
+}
+
+func (sb *sbomBuilder) addIssue(issueId, summary, severity string, cves []services.Cve, components map[string]services.Component) {
+	var affects []cdx.Affects
+	for componentId, component := range components {
+		sb.addComponent(componentId)
+		affects = append(affects, cdx.Affects{Ref: componentId})
+		for _, impactPath := range component.ImpactPaths {
+			sb.addImpactPath(impactPath)
+		}
+	}
+	sort.Slice(affects, func(i, j int) bool {
+		return affects[i].Ref < affects[j].Ref
+	})
+
+	// Each CVE is a separate vulnerability. Issues without CVEs are identified by the Xray issue ID.
+	var ids []string
+	for _, cve := range cves {
+		if cve.Id != "" {
+			ids = append(ids, cve.Id)
+		}
+	}
+	if len(ids) == 0 {
+		ids = append(ids, issueId)
+	}
+	for _, id := range ids {
+		if existing, exists := sb.vulnerabilities[id]; exists {
+			// The same vulnerability may be reported in several scans
+			*existing.Affects = mergeAffects(*existing.Affects, affects)
+			continue
+		}
+		// Each vulnerability has its own copy of the affected components, since they're merged separately
+		vulnerabilityAffects := append([]cdx.Affects{}, affects...)
+		vulnerability := &cdx.Vulnerability{
+			BOMRef:      id,
+			ID:          id,
+			Description: summary,
+			Affects:     &vulnerabilityAffects,
+		}
+		if strings.HasPrefix(id, "CVE-") {
+			vulnerability.Source = &cdx.Source{Name: "NVD", URL: nvdUrlPrefix + id}
+		}
+		if issueId != "" && issueId != id {
+			vulnerability.References = &[]cdx.VulnerabilityReference{{ID: issueId, Source: &cdx.Source{Name: "JFrog Xray"}}}
+		}
+		ratings := getVulnerabilityRatings(id, severity, cves)
+		vulnerability.Ratings = &ratings
+		sb.vulnerabilities[id] = vulnerability
+	}
+}
+
+// The first node in the impact path is the scanned project, followed by the chain of dependencies to the vulnerable component.
+func (sb *sbomBuilder) addImpactPath(impactPath []services.ImpactPathNode) {
+	for index, node := range impactPath {
+		sb.addComponent(node.ComponentId)
+		if index == 0 {
+			sb.rootRefs[node.ComponentId] = true
+			continue
+		}
+		parent := impactPath[index-1].ComponentId
+		if sb.dependencies[parent] == nil {
+			sb.dependencies[parent] = map[string]bool{}
+		}
+		sb.dependencies[parent][node.ComponentId] = true
+	}
+}
+
+func (sb *sbomBuilder) addComponent(componentId string) {
+	if _, exists := sb.components[componentId]; exists {
+		return
+	}
+	name, version, _ := xrayutils.SplitComponentId(componentId)
+	sb.components[componentId] = cdx.Component{
+		BOMRef:     componentId,
+		Type:       cdx.ComponentTypeLibrary,
+		Name:       name,
+		Version:    version,
+		PackageURL: getPackageUrl(componentId, name, version),
+	}
+}
+
+func (sb *sbomBuilder) build() *cdx.BOM {
+	bom := cdx.NewBOM()
+	bom.Metadata = &cdx.Metadata{Tools: &[]cdx.Tool{{Vendor: "JFrog", Name: "Frogbot"}}}
+
+	// A single scanned project is set as the BOM subject
+	var components []cdx.Component
+	for _, ref := range sortedKeys(sb.components) {
+		component := sb.components[ref]
+		if len(sb.rootRefs) == 1 && sb.rootRefs[ref] {
+			component.Type = cdx.ComponentTypeApplication
+			bom.Metadata.Component = &component
+			continue
+		}
+		components = append(components, component)
+	}
+	bom.Components = &components
+
+	var dependencies []cdx.Dependency
+	for _, ref := range sortedKeys(sb.dependencies) {
+		dependsOn := sortedKeys(sb.dependencies[ref])
+		dependencies = append(dependencies, cdx.Dependency{Ref: ref, Dependencies: &dependsOn})
+	}
+	bom.Dependencies = &dependencies
+
+	var vulnerabilities []cdx.Vulnerability
+	for _, id := range sortedKeys(sb.vulnerabilities) {
+		vulnerabilities = append(vulnerabilities, *sb.vulnerabilities[id])
+	}
+	bom.Vulnerabilities = &vulnerabilities
+	return bom
+}
+
+func getVulnerabilityRatings(id, severity string, cves []services.Cve) []cdx.VulnerabilityRating {
+	ratings := []cdx.VulnerabilityRating{{Source: &cdx.Source{Name: "JFrog Xray"}, Severity: cdx.Severity(strings.ToLower(severity))}}
+	for _, cve := range cves {
+		if cve.Id != id {
+			continue
+		}
+		if score, err := strconv.ParseFloat(cve.CvssV3Score, 64); err == nil {
+			ratings = append(ratings, cdx.VulnerabilityRating{Score: &score, Method: cdx.ScoringMethodCVSSv3, Vector: cve.CvssV3Vector})
+		}
+		if score, err := strconv.ParseFloat(cve.CvssV2Score, 64); err == nil {
+			ratings = append(ratings, cdx.VulnerabilityRating{Score: &score, Method: cdx.ScoringMethodCVSSv2, Vector: cve.CvssV2Vector})
+		}
+	}
+	return ratings
+}
+
+// Return the Package URL of the component, or an empty string if the package type isn't supported
+func getPackageUrl(componentId, name, version string) string {
+	purlType, exists := purlTypes[strings.Split(componentId, "://")[0]]
+	if !exists {
+		return ""
+	}
+	// Maven group IDs and Go module paths are used as the Package URL namespace
+	name = strings.Replace(name, ":", "/", 1)
+	purl := "pkg:" + purlType + "/" + name
+	if version != "" {
+		purl += "@" + version
+	}
+	return purl
+}
+
+func mergeAffects(existing, added []cdx.Affects) []cdx.Affects {
+	refs := map[string]bool{}
+	for _, affects := range existing {
+		refs[affects.Ref] = true
+	}
+	for _, affects := range added {
+		if !refs[affects.Ref] {
+			existing = append(existing, affects)
+		}
+	}
+	return existing
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+)
+
+var sbomTestScanResults = []services.ScanResponse{{
+	Vulnerabilities: []services.Vulnerability{{
+		IssueId:  "XRAY-1",
+		Summary:  "Prototype pollution",
+		Severity: "High",
+		Cves:     []services.Cve{{Id: "CVE-2022-1111", CvssV3Score: "7.5", CvssV3Vector: "CVSS:3.1/AV:N"}},
+		Components: map[string]services.Component{"npm://minimist:1.2.5": {
+			ImpactPaths: [][]services.ImpactPathNode{{{ComponentId: "npm://root:1.0.0"}, {ComponentId: "npm://mkdirp:0.5.5"}, {ComponentId: "npm://minimist:1.2.5"}}},
+		}},
+	}},
+	Violations: []services.Violation{{
+		IssueId:       "XRAY-2",
+		Severity:      "Low",
+		ViolationType: "security",
+		Components: map[string]services.Component{"gav://org.example:lib:2.0": {
+			ImpactPaths: [][]services.ImpactPathNode{{{ComponentId: "npm://root:1.0.0"}, {ComponentId: "gav://org.example:lib:2.0"}}},
+		}},
+	}, {
+		IssueId:       "XRAY-3",
+		ViolationType: "license",
+		Components:    map[string]services.Component{"npm://other:1.0.0": {}},
+	}},
+}}
+
+func TestCreateCycloneDxSbom(t *testing.T) {
+	bom := CreateCycloneDxSbom(sbomTestScanResults)
+	assert.Equal(t, cdx.SpecVersion1_4, bom.SpecVersion)
+
+	// The single root of the impact paths is the BOM subject
+	assert.Equal(t, "npm://root:1.0.0", bom.Metadata.Component.BOMRef)
+	assert.Equal(t, cdx.ComponentTypeApplication, bom.Metadata.Component.Type)
+
+	// License violations are ignored
+	components := *bom.Components
+	if assert.Len(t, components, 3) {
+		assert.Equal(t, "gav://org.example:lib:2.0", components[0].BOMRef)
+		assert.Equal(t, "pkg:maven/org.example/lib@2.0", components[0].PackageURL)
+		assert.Equal(t, "npm://minimist:1.2.5", components[1].BOMRef)
+		assert.Equal(t, "pkg:npm/minimist@1.2.5", components[1].PackageURL)
+		assert.Equal(t, "npm://mkdirp:0.5.5", components[2].BOMRef)
+	}
+
+	dependencies := *bom.Dependencies
+	if assert.Len(t, dependencies, 2) {
+		assert.Equal(t, "npm://mkdirp:0.5.5", dependencies[0].Ref)
+		assert.Equal(t, []string{"npm://minimist:1.2.5"}, *dependencies[0].Dependencies)
+		assert.Equal(t, "npm://root:1.0.0", dependencies[1].Ref)
+		assert.Equal(t, []string{"gav://org.example:lib:2.0", "npm://mkdirp:0.5.5"}, *dependencies[1].Dependencies)
+	}
+
+	vulnerabilities := *bom.Vulnerabilities
+	if assert.Len(t, vulnerabilities, 2) {
+		assert.Equal(t, "CVE-2022-1111", vulnerabilities[0].ID)
+		assert.Equal(t, nvdUrlPrefix+"CVE-2022-1111", vulnerabilities[0].Source.URL)
+		assert.Equal(t, "XRAY-1", (*vulnerabilities[0].References)[0].ID)
+		assert.Equal(t, []cdx.Affects{{Ref: "npm://minimist:1.2.5"}}, *vulnerabilities[0].Affects)
+		ratings := *vulnerabilities[0].Ratings
+		if assert.Len(t, ratings, 2) {
+			assert.Equal(t, cdx.SeverityHigh, ratings[0].Severity)
+			assert.Equal(t, 7.5, *ratings[1].Score)
+			assert.Equal(t, cdx.ScoringMethodCVSSv3, ratings[1].Method)
+		}
+		// Issues without CVEs are identified by the Xray issue ID
+		assert.Equal(t, "XRAY-2", vulnerabilities[1].ID)
+		assert.Nil(t, vulnerabilities[1].Source)
+		assert.Equal(t, cdx.SeverityLow, (*vulnerabilities[1].Ratings)[0].Severity)
+	}
+}
+
+func TestCreateCycloneDxSbomMergedAffects(t *testing.T) {
+	component := func(componentId string) map[string]services.Component {
+		return map[string]services.Component{componentId: {ImpactPaths: [][]services.ImpactPathNode{{{ComponentId: "npm://root:1.0.0"}, {ComponentId: componentId}}}}}
+	}
+	scanResults := []services.ScanResponse{{
+		Vulnerabilities: []services.Vulnerability{
+			{IssueId: "XRAY-1", Cves: []services.Cve{{Id: "CVE-2022-1111"}, {Id: "CVE-2022-2222"}}, Components: component("npm://minimist:1.2.5")},
+			// The same CVE, reported for another component
+			{IssueId: "XRAY-2", Cves: []services.Cve{{Id: "CVE-2022-1111"}}, Components: component("npm://lodash:4.17.20")},
+		},
+	}}
+	vulnerabilities := *CreateCycloneDxSbom(scanResults).Vulnerabilities
+	if assert.Len(t, vulnerabilities, 2) {
+		assert.Equal(t, []cdx.Affects{{Ref: "npm://minimist:1.2.5"}, {Ref: "npm://lodash:4.17.20"}}, *vulnerabilities[0].Affects)
+		// Merging the components of one CVE doesn't change the components of the other CVEs of the issue
+		assert.Equal(t, "CVE-2022-2222", vulnerabilities[1].ID)
+		assert.Equal(t, []cdx.Affects{{Ref: "npm://minimist:1.2.5"}}, *vulnerabilities[1].Affects)
+	}
+}
+
+func TestExportCycloneDxSbom(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "sbom.json")
+	assert.NoError(t, ExportCycloneDxSbom(sbomTestScanResults, outputPath))
+
+	file, err := os.Open(outputPath)
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, file.Close())
+	}()
+	var bom cdx.BOM
+	assert.NoError(t, cdx.NewBOMDecoder(file, cdx.BOMFileFormatJSON).Decode(&bom))
+	assert.Equal(t, "CycloneDX", bom.BOMFormat)
+	assert.Len(t, *bom.Vulnerabilities, 2)
+}
+
+func TestGetPackageUrl(t *testing.T) {
+	assert.Equal(t, "pkg:golang/github.com/jfrog/gofrog@v1.2.5", getPackageUrl("go://github.com/jfrog/gofrog:v1.2.5", "github.com/jfrog/gofrog", "v1.2.5"))
+	assert.Equal(t, "pkg:pypi/requests@2.0.0", getPackageUrl("pypi://requests:2.0.0", "requests", "2.0.0"))
+	assert.Empty(t, getPackageUrl("unknown://pkg:1.0.0", "pkg", "1.0.0"))
+}
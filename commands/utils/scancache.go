@@ -0,0 +1,189 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	coreconfig "github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+)
+
+const (
+	defaultScanCacheTtlHours   = 24
+	defaultScanCacheMaxEntries = 1000
+	scanCacheEntrySuffix       = ".json"
+)
+
+// ScanCache keeps the Xray graph scan results in a directory, as a JSON file per graph scan request, which is named by the hash of the request.
+// The files are written to a temp file first and renamed, so the cache may be shared by scans which run in parallel.
+type ScanCache struct {
+	Dir        string
+	Ttl        time.Duration
+	MaxEntries int
+}
+
+// NewScanCache returns the scan cache configured in the params, or nil if scanCache isn't set
+func NewScanCache(params *Params) (*ScanCache, error) {
+	if !params.ScanCache {
+		return nil, nil
+	}
+	cacheDir := params.ScanCacheDir
+	if cacheDir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("couldn't find the default directory of the scan cache, set it in scanCacheDir instead: %s", err.Error())
+		}
+		cacheDir = filepath.Join(userCacheDir, "frogbot", "scan-cache")
+	}
+	ttlHours := params.ScanCacheTtlHours
+	if ttlHours == 0 {
+		ttlHours = defaultScanCacheTtlHours
+	}
+	maxEntries := params.ScanCacheMaxEntries
+	if maxEntries == 0 {
+		maxEntries = defaultScanCacheMaxEntries
+	}
+	return &ScanCache{Dir: cacheDir, Ttl: time.Duration(ttlHours) * time.Hour, MaxEntries: maxEntries}, nil
+}
+
+func (p *Params) validateScanCache() error {
+	if p.ScanCacheTtlHours < 0 {
+		return fmt.Errorf(errInvalidScanCacheTtlHours, p.ScanCacheTtlHours)
+	}
+	if p.ScanCacheMaxEntries < 0 {
+		return fmt.Errorf(errInvalidCacheMaxEntries, p.ScanCacheMaxEntries)
+	}
+	return nil
+}
+
+// GetScanCacheKey returns the hash of the graph scan request, which includes the dependency graph, the watches, the JFrog project and the scan mode of the request,
+// and the identity of the server: its URLs and the user, or the access token, whose credentials scan the graph. Since the results, such as the violations, depend on the permissions
+// of the user, the results cached for one user aren't returned to another user, even in a cache directory shared by several repositories.
+func GetScanCacheKey(server *coreconfig.ServerDetails, xrayScanParams services.XrayGraphScanParams) (string, error) {
+	request, err := json.Marshal(xrayScanParams)
+	if err != nil {
+		return "", err
+	}
+	// The user of an access token isn't always known, such as for reference tokens, so the token itself identifies the user, as part of the hashed key
+	identity := server.User
+	if identity == "" {
+		identity = server.AccessToken
+	}
+	hash := sha256.New()
+	hash.Write([]byte(server.Url + "\n" + server.XrayUrl + "\n" + identity + "\n"))
+	hash.Write(request)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Get returns the cached results of the key, or nil if the results aren't cached or expired.
+// The cache is an optimization only, so a cache entry which can't be read is treated as a missing entry.
+func (sc *ScanCache) Get(key string) *services.ScanResponse {
+	entryPath := sc.entryPath(key)
+	info, err := os.Stat(entryPath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Debug("couldn't read the scan cache:", err.Error())
+		}
+		return nil
+	}
+	if time.Since(info.ModTime()) > sc.Ttl {
+		return nil
+	}
+	content, err := os.ReadFile(entryPath) // #nosec G304
+	if err != nil {
+		log.Debug("couldn't read the scan cache:", err.Error())
+		return nil
+	}
+	response := &services.ScanResponse{}
+	if err = json.Unmarshal(content, response); err != nil {
+		log.Debug("the scan cache entry", entryPath, "is invalid:", err.Error())
+		return nil
+	}
+	return response
+}
+
+// Add caches the results of the key, and removes the expired entries and the oldest entries beyond the maximal number of entries.
+// A failure to write the cache is logged, since the scan itself succeeded.
+func (sc *ScanCache) Add(key string, response *services.ScanResponse) {
+	if err := sc.add(key, response); err != nil {
+		log.Warn("couldn't add the scan results to the scan cache:", err.Error())
+		return
+	}
+	if err := sc.prune(); err != nil {
+		log.Warn("couldn't remove the old entries of the scan cache:", err.Error())
+	}
+}
+
+func (sc *ScanCache) add(key string, response *services.ScanResponse) error {
+	content, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(sc.Dir, 0700); err != nil {
+		return err
+	}
+	tempFile, err := os.CreateTemp(sc.Dir, "entry-*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tempFile.Write(content)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tempFile.Name(), sc.entryPath(key))
+	}
+	if err != nil {
+		_ = os.Remove(tempFile.Name())
+	}
+	return err
+}
+
+func (sc *ScanCache) prune() error {
+	dirEntries, err := os.ReadDir(sc.Dir)
+	if err != nil {
+		return err
+	}
+	type cacheEntry struct {
+		path    string
+		modTime time.Time
+	}
+	var entries []cacheEntry
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() || !strings.HasSuffix(dirEntry.Name(), scanCacheEntrySuffix) {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			// The entry may have been removed by a scan which runs in parallel
+			continue
+		}
+		entries = append(entries, cacheEntry{path: filepath.Join(sc.Dir, dirEntry.Name()), modTime: info.ModTime()})
+	}
+	// Sort from the newest entry to the oldest
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].modTime.After(entries[j].modTime)
+	})
+	for index, entry := range entries {
+		if index < sc.MaxEntries && time.Since(entry.modTime) <= sc.Ttl {
+			continue
+		}
+		if err = os.Remove(entry.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sc *ScanCache) entryPath(key string) string {
+	return filepath.Join(sc.Dir, key+scanCacheEntrySuffix)
+}
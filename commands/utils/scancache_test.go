@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewScanCache(t *testing.T) {
+	scanCache, err := NewScanCache(&Params{ScanCacheDir: t.TempDir()})
+	assert.NoError(t, err)
+	assert.Nil(t, scanCache)
+
+	cacheDir := t.TempDir()
+	scanCache, err = NewScanCache(&Params{ScanCache: true, ScanCacheDir: cacheDir})
+	assert.NoError(t, err)
+	assert.Equal(t, &ScanCache{Dir: cacheDir, Ttl: 24 * time.Hour, MaxEntries: 1000}, scanCache)
+
+	scanCache, err = NewScanCache(&Params{ScanCache: true, ScanCacheDir: cacheDir, ScanCacheTtlHours: 2, ScanCacheMaxEntries: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, &ScanCache{Dir: cacheDir, Ttl: 2 * time.Hour, MaxEntries: 10}, scanCache)
+
+	params := Params{ScanCacheTtlHours: -1}
+	assert.EqualError(t, params.validateScanCache(), fmt.Sprintf(errInvalidScanCacheTtlHours, -1))
+	params = Params{ScanCacheMaxEntries: -5}
+	assert.EqualError(t, params.validateScanCache(), fmt.Sprintf(errInvalidCacheMaxEntries, -5))
+}
+
+func TestGetScanCacheKey(t *testing.T) {
+	server := &config.ServerDetails{Url: "https://jfrog.example.com/", XrayUrl: "https://jfrog.example.com/xray/", User: "frogbot"}
+	graph := &services.GraphNode{Id: "npm://frogbot:1.0.0", Nodes: []*services.GraphNode{{Id: "npm://lodash:4.17.20"}}}
+	key, err := GetScanCacheKey(server, services.XrayGraphScanParams{Graph: graph, Watches: []string{"watch-1"}})
+	assert.NoError(t, err)
+	sameKey, err := GetScanCacheKey(server, services.XrayGraphScanParams{Graph: &services.GraphNode{Id: "npm://frogbot:1.0.0", Nodes: []*services.GraphNode{{Id: "npm://lodash:4.17.20"}}}, Watches: []string{"watch-1"}})
+	assert.NoError(t, err)
+	assert.Equal(t, key, sameKey)
+
+	// A change to the graph, to the watches, to the URLs of the server or to its user changes the key
+	for _, testCase := range []struct {
+		server *config.ServerDetails
+		params services.XrayGraphScanParams
+	}{
+		{server: server, params: services.XrayGraphScanParams{Graph: &services.GraphNode{Id: "npm://frogbot:1.0.0", Nodes: []*services.GraphNode{{Id: "npm://lodash:4.17.21"}}}, Watches: []string{"watch-1"}}},
+		{server: server, params: services.XrayGraphScanParams{Graph: graph, Watches: []string{"watch-2"}}},
+		{server: &config.ServerDetails{Url: "https://jfrog.example.com/", XrayUrl: "https://dr.jfrog.example.com/xray/", User: "frogbot"}, params: services.XrayGraphScanParams{Graph: graph, Watches: []string{"watch-1"}}},
+		{server: &config.ServerDetails{Url: "https://other.example.com/", XrayUrl: "https://jfrog.example.com/xray/", User: "frogbot"}, params: services.XrayGraphScanParams{Graph: graph, Watches: []string{"watch-1"}}},
+		{server: &config.ServerDetails{Url: "https://jfrog.example.com/", XrayUrl: "https://jfrog.example.com/xray/", User: "other-user"}, params: services.XrayGraphScanParams{Graph: graph, Watches: []string{"watch-1"}}},
+		{server: &config.ServerDetails{Url: "https://jfrog.example.com/", XrayUrl: "https://jfrog.example.com/xray/", AccessToken: "token"}, params: services.XrayGraphScanParams{Graph: graph, Watches: []string{"watch-1"}}},
+	} {
+		otherKey, err := GetScanCacheKey(testCase.server, testCase.params)
+		assert.NoError(t, err)
+		assert.NotEqual(t, key, otherKey)
+	}
+
+	// Without a user, the access token identifies the credentials
+	tokenServer := &config.ServerDetails{Url: "https://jfrog.example.com/", XrayUrl: "https://jfrog.example.com/xray/", AccessToken: "token"}
+	tokenKey, err := GetScanCacheKey(tokenServer, services.XrayGraphScanParams{Graph: graph, Watches: []string{"watch-1"}})
+	assert.NoError(t, err)
+	tokenServer.AccessToken = "other-token"
+	otherTokenKey, err := GetScanCacheKey(tokenServer, services.XrayGraphScanParams{Graph: graph, Watches: []string{"watch-1"}})
+	assert.NoError(t, err)
+	assert.NotEqual(t, tokenKey, otherTokenKey)
+}
+
+func TestScanCache(t *testing.T) {
+	scanCache := &ScanCache{Dir: filepath.Join(t.TempDir(), "scan-cache"), Ttl: time.Hour, MaxEntries: 2}
+	assert.Nil(t, scanCache.Get("first"))
+
+	response := &services.ScanResponse{ScanId: "1", Vulnerabilities: []services.Vulnerability{{IssueId: "XRAY-1", Severity: "High"}}}
+	scanCache.Add("first", response)
+	assert.Equal(t, response, scanCache.Get("first"))
+
+	// The expired entries aren't used
+	expired := time.Now().Add(-2 * time.Hour)
+	assert.NoError(t, os.Chtimes(scanCache.entryPath("first"), expired, expired))
+	assert.Nil(t, scanCache.Get("first"))
+
+	// Adding an entry removes the expired entries, and the oldest entries beyond the maximal number of entries
+	scanCache.Add("second", &services.ScanResponse{ScanId: "2"})
+	assert.NoFileExists(t, scanCache.entryPath("first"))
+	older := time.Now().Add(-time.Minute)
+	assert.NoError(t, os.Chtimes(scanCache.entryPath("second"), older, older))
+	scanCache.Add("third", &services.ScanResponse{ScanId: "3"})
+	scanCache.Add("fourth", &services.ScanResponse{ScanId: "4"})
+	assert.Nil(t, scanCache.Get("second"))
+	assert.Equal(t, "3", scanCache.Get("third").ScanId)
+	assert.Equal(t, "4", scanCache.Get("fourth").ScanId)
+
+	// An invalid entry is treated as a missing entry
+	assert.NoError(t, os.WriteFile(scanCache.entryPath("fourth"), []byte("{"), 0600))
+	assert.Nil(t, scanCache.Get("fourth"))
+}
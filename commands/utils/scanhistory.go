@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jfrog/jfrog-client-go/xray/services"
+)
+
+// ScanHistoryStorage persists the last clean scan of each branch, which the scan-history baseline compares the pull requests against.
+// The local file storage is the only backend for now. Other backends, such as a remote repository, may implement this interface.
+type ScanHistoryStorage interface {
+	// Load returns the scan stored under the key, or nil if no scan is stored under it
+	Load(key string) (*ScanHistoryEntry, error)
+	Save(key string, entry *ScanHistoryEntry) error
+}
+
+// ScanHistoryEntry is a scan which reported no issues
+type ScanHistoryEntry struct {
+	CommitSha string    `json:"commitSha,omitempty"`
+	ScannedAt time.Time `json:"scannedAt"`
+	// The Xray scan results of each project, by the working dirs of the project
+	Projects map[string][]services.ScanResponse `json:"projects"`
+}
+
+// NewScanHistoryStorage returns the storage of the scan history configured in the params
+func NewScanHistoryStorage(params *Params) (ScanHistoryStorage, error) {
+	historyDir := params.ScanHistoryDir
+	if historyDir == "" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("couldn't find the default directory of the scan history, set it in scanHistoryDir instead: %s", err.Error())
+		}
+		historyDir = filepath.Join(cacheDir, "frogbot", "scan-history")
+	}
+	return &LocalScanHistoryStorage{Dir: historyDir}, nil
+}
+
+// GetScanHistoryKey returns the key of the scans of the branch in the scan history
+func GetScanHistoryKey(git *Git, branch string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", git.GitProvider.String(), git.RepoOwner, git.RepoName, branch)
+}
+
+// LocalScanHistoryStorage keeps each entry of the scan history in a JSON file in the directory
+type LocalScanHistoryStorage struct {
+	Dir string
+}
+
+func (ls *LocalScanHistoryStorage) Load(key string) (*ScanHistoryEntry, error) {
+	content, err := os.ReadFile(ls.entryPath(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	entry := &ScanHistoryEntry{}
+	if err = json.Unmarshal(content, entry); err != nil {
+		return nil, fmt.Errorf("the scan history file of %s is invalid: %s", key, err.Error())
+	}
+	return entry, nil
+}
+
+func (ls *LocalScanHistoryStorage) Save(key string, entry *ScanHistoryEntry) error {
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(ls.Dir, 0700); err != nil {
+		return err
+	}
+	// Write to a temp file first, so that a concurrent scan doesn't read a partially written entry
+	tempFile, err := os.CreateTemp(ls.Dir, "entry-*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tempFile.Write(content)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tempFile.Name(), ls.entryPath(key))
+	}
+	if err != nil {
+		_ = os.Remove(tempFile.Name())
+	}
+	return err
+}
+
+// The key may include slashes and other characters which aren't valid in file names, so it's escaped
+func (ls *LocalScanHistoryStorage) entryPath(key string) string {
+	return filepath.Join(ls.Dir, url.QueryEscape(key)+".json")
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalScanHistoryStorage(t *testing.T) {
+	storage, err := NewScanHistoryStorage(&Params{ScanHistoryDir: filepath.Join(t.TempDir(), "history")})
+	assert.NoError(t, err)
+	key := GetScanHistoryKey(&Git{GitProvider: vcsutils.GitHub, RepoOwner: "jfrog", RepoName: "frogbot"}, "feature/upgrade")
+	assert.Equal(t, "GitHub/jfrog/frogbot/feature/upgrade", key)
+
+	entry, err := storage.Load(key)
+	assert.NoError(t, err)
+	assert.Nil(t, entry)
+
+	savedEntry := &ScanHistoryEntry{
+		CommitSha: "abc123",
+		ScannedAt: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		Projects:  map[string][]services.ScanResponse{".": {{ScanId: "scan-1"}}},
+	}
+	assert.NoError(t, storage.Save(key, savedEntry))
+	entry, err = storage.Load(key)
+	assert.NoError(t, err)
+	assert.Equal(t, savedEntry, entry)
+
+	// The entries of the other branches aren't affected
+	otherKey := GetScanHistoryKey(&Git{GitProvider: vcsutils.GitHub, RepoOwner: "jfrog", RepoName: "frogbot"}, "feature")
+	entry, err = storage.Load(otherKey)
+	assert.NoError(t, err)
+	assert.Nil(t, entry)
+
+	// A corrupted entry
+	localStorage := storage.(*LocalScanHistoryStorage)
+	assert.NoError(t, os.WriteFile(localStorage.entryPath(otherKey), []byte("{"), 0600))
+	_, err = storage.Load(otherKey)
+	assert.Error(t, err)
+}
@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+)
+
+// The Xray severities, from the lowest to the highest
+var severityTitles = []string{"Low", "Medium", "High", "Critical"}
+
+// GetSeverityNumValue returns the rank of the severity, starting from 1 for Low, or 0 if the severity is unknown
+func GetSeverityNumValue(severity string) int {
+	for index, title := range severityTitles {
+		if strings.EqualFold(title, severity) {
+			return index + 1
+		}
+	}
+	return 0
+}
+
+func getSeverityTitle(severity string) string {
+	if numValue := GetSeverityNumValue(severity); numValue > 0 {
+		return severityTitles[numValue-1]
+	}
+	return severity
+}
+
+func validateSeverity(paramName, severity string) error {
+	if severity != "" && GetSeverityNumValue(severity) == 0 {
+		return fmt.Errorf(errInvalidSeverity, severity, paramName)
+	}
+	return nil
+}
+
+// SeverityPolicy determines which issues are displayed, and which issues fail the scan
+type SeverityPolicy struct {
+	// Issues with a lower severity are omitted from the results
+	MinSeverity string `yaml:"minSeverity,omitempty"`
+	// Fail only if an issue with this severity or higher is found
+	FailSeverityThreshold string `yaml:"failSeverityThreshold,omitempty"`
+}
+
+func (sp *SeverityPolicy) validateSeverities() error {
+	if err := validateSeverity("minSeverity", sp.MinSeverity); err != nil {
+		return err
+	}
+	return validateSeverity("failSeverityThreshold", sp.FailSeverityThreshold)
+}
+
+// Issues with a severity of MinSeverity and above are shown. The unknown severity is the lowest tier, below Low, so its issues are shown only if MinSeverity isn't set.
+func (sp *SeverityPolicy) isShown(severity string) bool {
+	minNumValue := GetSeverityNumValue(sp.MinSeverity)
+	return minNumValue == 0 || GetSeverityNumValue(severity) >= minNumValue
+}
+
+// Issues with a severity of FailSeverityThreshold and above fail the scan. Issues with an unknown severity fail only if FailSeverityThreshold isn't set.
+func (sp *SeverityPolicy) isFailing(severity string) bool {
+	thresholdNumValue := GetSeverityNumValue(sp.FailSeverityThreshold)
+	return thresholdNumValue == 0 || GetSeverityNumValue(severity) >= thresholdNumValue
+}
+
+// FilterBySeverity returns the issues with a severity of MinSeverity and above.
+func (sp *SeverityPolicy) FilterBySeverity(vulnerabilitiesRows []formats.VulnerabilityOrViolationRow) []formats.VulnerabilityOrViolationRow {
+	if sp.MinSeverity == "" {
+		return vulnerabilitiesRows
+	}
+	var filteredRows []formats.VulnerabilityOrViolationRow
+	for _, row := range vulnerabilitiesRows {
+		if sp.isShown(row.Severity) {
+			filteredRows = append(filteredRows, row)
+		}
+	}
+	return filteredRows
+}
+
+// HasFailingIssues returns true if one of the issues has a severity of FailSeverityThreshold and above, or if FailSeverityThreshold isn't set and issues were found.
+func (sp *SeverityPolicy) HasFailingIssues(vulnerabilitiesRows []formats.VulnerabilityOrViolationRow) bool {
+	for _, row := range vulnerabilitiesRows {
+		if sp.isFailing(row.Severity) {
+			return true
+		}
+	}
+	return false
+}
+
+// MapUnknownSeverity returns the issues with the severity of unknownSeverityAs set on the issues whose severity is unknown or empty, so that they are sorted,
+// filtered and gated like the issues of that severity. If unknownSeverityAs is ignore, the issues with an unknown severity are dropped instead.
+// If unknownSeverityAs is empty, the issues are returned unchanged, and the unknown severity is the lowest tier.
+func MapUnknownSeverity(vulnerabilitiesRows []formats.VulnerabilityOrViolationRow, unknownSeverityAs string) []formats.VulnerabilityOrViolationRow {
+	if unknownSeverityAs == "" {
+		return vulnerabilitiesRows
+	}
+	mappedNumValue := GetSeverityNumValue(unknownSeverityAs)
+	var mappedRows []formats.VulnerabilityOrViolationRow
+	for _, row := range vulnerabilitiesRows {
+		if GetSeverityNumValue(row.Severity) == 0 {
+			if unknownSeverityAs == IgnoreUnknownSeverity {
+				continue
+			}
+			row.Severity = severityTitles[mappedNumValue-1]
+			row.SeverityNumValue = mappedNumValue
+		}
+		mappedRows = append(mappedRows, row)
+	}
+	return mappedRows
+}
+
+// ShouldFail returns true if Frogbot is configured to fail on security issues, and failing issues were found
+func (s *Scan) ShouldFail(failingIssuesFound bool) bool {
+	return s.FailOnSecurityIssues != nil && *s.FailOnSecurityIssues && failingIssuesFound
+}
+
+// GetSeverityPolicyNote describes the configured severity gating policy, or returns an empty string if no severity policy is configured
+func (s *Scan) GetSeverityPolicyNote() string {
+	var policies []string
+	if s.MinSeverity != "" {
+		policies = append(policies, fmt.Sprintf(showingSeverityPolicy, getSeverityTitle(s.MinSeverity)))
+	}
+	if s.FailSeverityThreshold != "" {
+		if s.FailOnSecurityIssues != nil && !*s.FailOnSecurityIssues {
+			policies = append(policies, notFailingPolicy)
+		} else {
+			policies = append(policies, fmt.Sprintf(failingSeverityPolicy, getSeverityTitle(s.FailSeverityThreshold)))
+		}
+	}
+	for _, project := range s.Projects {
+		if project.SeverityPolicy != s.SeverityPolicy {
+			policies = append(policies, projectsPolicy)
+			break
+		}
+	}
+	if len(s.EcosystemPolicies) > 0 {
+		policies = append(policies, ecosystemsPolicy)
+	}
+	if len(policies) == 0 {
+		return ""
+	}
+	return "\n\n**Policy:** " + strings.Join(policies, ". ") + "."
+}
@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/stretchr/testify/assert"
+)
+
+var severityTestRows = []formats.VulnerabilityOrViolationRow{
+	{Severity: "Low", IssueId: "XRAY-1"},
+	{Severity: "Medium", IssueId: "XRAY-2"},
+	{Severity: "High", IssueId: "XRAY-3"},
+	{Severity: "Unknown", IssueId: "XRAY-4"},
+}
+
+func TestGetSeverityNumValue(t *testing.T) {
+	assert.Equal(t, 1, GetSeverityNumValue("Low"))
+	assert.Equal(t, 3, GetSeverityNumValue("high"))
+	assert.Equal(t, 4, GetSeverityNumValue("CRITICAL"))
+	assert.Equal(t, 0, GetSeverityNumValue("Unknown"))
+}
+
+func TestValidateSeverities(t *testing.T) {
+	policy := SeverityPolicy{MinSeverity: "medium", FailSeverityThreshold: "High"}
+	assert.NoError(t, policy.validateSeverities())
+	policy.FailSeverityThreshold = "Severe"
+	assert.EqualError(t, policy.validateSeverities(), "the severity 'Severe' set in failSeverityThreshold is invalid. The supported severities are Low, Medium, High and Critical")
+}
+
+func TestFilterBySeverity(t *testing.T) {
+	policy := SeverityPolicy{}
+	assert.Len(t, policy.FilterBySeverity(severityTestRows), 4)
+
+	// The unknown severity is below Low, so its issues are omitted
+	policy.MinSeverity = "Medium"
+	filteredRows := policy.FilterBySeverity(severityTestRows)
+	if assert.Len(t, filteredRows, 2) {
+		assert.Equal(t, "XRAY-2", filteredRows[0].IssueId)
+		assert.Equal(t, "XRAY-3", filteredRows[1].IssueId)
+	}
+	policy.MinSeverity = "Low"
+	assert.Len(t, policy.FilterBySeverity(severityTestRows), 3)
+}
+
+func TestHasFailingIssues(t *testing.T) {
+	policy := SeverityPolicy{}
+	assert.True(t, policy.HasFailingIssues(severityTestRows[:1]))
+	assert.False(t, policy.HasFailingIssues(nil))
+
+	policy.FailSeverityThreshold = "High"
+	assert.False(t, policy.HasFailingIssues(severityTestRows[:2]))
+	assert.True(t, policy.HasFailingIssues(severityTestRows[:3]))
+	// Issues with an unknown severity fail only if no threshold is set
+	assert.False(t, policy.HasFailingIssues(severityTestRows[3:]))
+	policy.FailSeverityThreshold = ""
+	assert.True(t, policy.HasFailingIssues(severityTestRows[3:]))
+}
+
+func TestMapUnknownSeverity(t *testing.T) {
+	rows := []formats.VulnerabilityOrViolationRow{
+		{Severity: "High", SeverityNumValue: 3, IssueId: "XRAY-1"},
+		{Severity: "Unknown", IssueId: "XRAY-2"},
+		{IssueId: "XRAY-3"},
+	}
+	assert.Equal(t, rows, MapUnknownSeverity(rows, ""))
+
+	mappedRows := MapUnknownSeverity(rows, "critical")
+	if assert.Len(t, mappedRows, 3) {
+		assert.Equal(t, formats.VulnerabilityOrViolationRow{Severity: "High", SeverityNumValue: 3, IssueId: "XRAY-1"}, mappedRows[0])
+		assert.Equal(t, formats.VulnerabilityOrViolationRow{Severity: "Critical", SeverityNumValue: 4, IssueId: "XRAY-2"}, mappedRows[1])
+		assert.Equal(t, formats.VulnerabilityOrViolationRow{Severity: "Critical", SeverityNumValue: 4, IssueId: "XRAY-3"}, mappedRows[2])
+	}
+	// The original rows are kept unchanged
+	assert.Equal(t, "Unknown", rows[1].Severity)
+
+	mappedRows = MapUnknownSeverity(rows, IgnoreUnknownSeverity)
+	if assert.Len(t, mappedRows, 1) {
+		assert.Equal(t, "XRAY-1", mappedRows[0].IssueId)
+	}
+}
+
+func TestValidateUnknownSeverityAs(t *testing.T) {
+	for _, value := range []string{"", "Low", "high", IgnoreUnknownSeverity} {
+		params := Params{UnknownSeverityAs: value}
+		assert.NoError(t, params.validateUnknownSeverityAs())
+	}
+	params := Params{UnknownSeverityAs: "Unknown"}
+	assert.EqualError(t, params.validateUnknownSeverityAs(), "the value 'Unknown' of unknownSeverityAs is invalid. The supported values are Low, Medium, High, Critical and ignore")
+}
+
+func TestShouldFail(t *testing.T) {
+	failOnSecurityIssues := true
+	scan := Scan{FailOnSecurityIssues: &failOnSecurityIssues}
+	assert.True(t, scan.ShouldFail(true))
+	assert.False(t, scan.ShouldFail(false))
+
+	failOnSecurityIssues = false
+	assert.False(t, scan.ShouldFail(true))
+}
+
+func TestGetSeverityPolicyNote(t *testing.T) {
+	failOnSecurityIssues := true
+	scan := Scan{FailOnSecurityIssues: &failOnSecurityIssues}
+	assert.Empty(t, scan.GetSeverityPolicyNote())
+
+	scan.FailSeverityThreshold = "high"
+	assert.Equal(t, "\n\n**Policy:** Failing on High and above.", scan.GetSeverityPolicyNote())
+
+	scan.MinSeverity = "Medium"
+	assert.Equal(t, "\n\n**Policy:** Showing issues of Medium severity and above. Failing on High and above.", scan.GetSeverityPolicyNote())
+
+	failOnSecurityIssues = false
+	assert.Equal(t, "\n\n**Policy:** Showing issues of Medium severity and above. Not failing on issues.", scan.GetSeverityPolicyNote())
+
+	// A project overrides the repository policy
+	scan.Projects = []Project{{SeverityPolicy: scan.SeverityPolicy}, {SeverityPolicy: SeverityPolicy{MinSeverity: "Low"}}}
+	assert.Equal(t, "\n\n**Policy:** Showing issues of Medium severity and above. Not failing on issues. Some projects in this repository use a different policy.", scan.GetSeverityPolicyNote())
+
+	scan.Projects = nil
+	scan.EcosystemPolicies = map[string]SeverityPolicy{"npm": {FailSeverityThreshold: "Medium"}}
+	assert.Equal(t, "\n\n**Policy:** Showing issues of Medium severity and above. Not failing on issues. Some ecosystems use a different policy.", scan.GetSeverityPolicyNote())
+}
+
+func TestIacSeverityPolicy(t *testing.T) {
+	iacRows := []IacRow{{Severity: "Low", RuleId: "rule-1"}, {Severity: "High", RuleId: "rule-2"}}
+	policy := SeverityPolicy{MinSeverity: "Medium", FailSeverityThreshold: "Critical"}
+	filteredRows := policy.FilterIacBySeverity(iacRows)
+	if assert.Len(t, filteredRows, 1) {
+		assert.Equal(t, "rule-2", filteredRows[0].RuleId)
+	}
+	assert.False(t, policy.HasFailingIacIssues(filteredRows))
+	policy.FailSeverityThreshold = "High"
+	assert.True(t, policy.HasFailingIacIssues(filteredRows))
+}
@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	severityColorsParam           = "severityColors"
+	severityColorsDelimiter       = ","
+	severityColorAssignmentSymbol = "="
+	errInvalidSeverityColor       = "the color '%s' set for the %s severity in severityColors is invalid. The colors are expected to be hex colors, such as #DD2E44"
+	errInvalidSeverityColorsEnv   = "the value of the %s environment is expected to be a comma separated list of severity=color pairs, such as Critical=#DD2E44,High=#F4900C. The value received however is %s"
+)
+
+var hexColorPattern = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// The default colors of the severities, which are the colors of their severity badges
+var defaultSeverityColors = map[string]string{
+	"Critical": "#DD2E44",
+	"High":     "#F4900C",
+	"Medium":   "#FDCB58",
+	"Low":      "#78B159",
+}
+
+type coloredBadge struct {
+	badge   string
+	hexCode string
+}
+
+// The emojis, out of which the severity badge closest to the color of the severity is chosen
+var coloredBadges = []coloredBadge{
+	{badge: criticalSeverityBadge, hexCode: "#DD2E44"},
+	{badge: highSeverityBadge, hexCode: "#F4900C"},
+	{badge: mediumSeverityBadge, hexCode: "#FDCB58"},
+	{badge: lowSeverityBadge, hexCode: "#78B159"},
+	{badge: "🔵", hexCode: "#55ACEE"},
+	{badge: "🟣", hexCode: "#AA8ED6"},
+	{badge: "🟤", hexCode: "#C1694F"},
+	{badge: "⚫", hexCode: "#31373D"},
+	{badge: "⚪", hexCode: "#E6E7E8"},
+}
+
+// Validate the severityColors param, and set its severities to their titles, so that they're matched regardless of their case
+func (p *Params) validateSeverityColors() error {
+	if len(p.SeverityColors) == 0 {
+		return nil
+	}
+	severityColors := make(map[string]string, len(p.SeverityColors))
+	for severity, color := range p.SeverityColors {
+		if err := validateSeverity(severityColorsParam, severity); err != nil {
+			return err
+		}
+		if !hexColorPattern.MatchString(color) {
+			return fmt.Errorf(errInvalidSeverityColor, color, severity)
+		}
+		severityColors[getSeverityTitle(severity)] = color
+	}
+	p.SeverityColors = severityColors
+	return nil
+}
+
+// Parse the severity colors environment variable, such as "Critical=#DD2E44,High=#F4900C"
+func parseSeverityColors(envKey, envValue string) (map[string]string, error) {
+	severityColors := make(map[string]string)
+	for _, pair := range strings.Split(envValue, severityColorsDelimiter) {
+		severity, color, found := strings.Cut(pair, severityColorAssignmentSymbol)
+		if !found {
+			return nil, fmt.Errorf(errInvalidSeverityColorsEnv, envKey, envValue)
+		}
+		severityColors[strings.TrimSpace(severity)] = strings.TrimSpace(color)
+	}
+	return severityColors, nil
+}
+
+// GetSeverityColor returns the configured color of the severity, or its default color if not configured.
+// An empty string is returned for unknown severities.
+func GetSeverityColor(severity string, severityColors map[string]string) string {
+	title := getSeverityTitle(severity)
+	if color, exists := severityColors[title]; exists {
+		return color
+	}
+	return defaultSeverityColors[title]
+}
+
+// Return true if the color of the severity is configured to a color other than its default color
+func isCustomSeverityColor(severity string, severityColors map[string]string) bool {
+	color, exists := severityColors[getSeverityTitle(severity)]
+	return exists && !strings.EqualFold(color, defaultSeverityColors[getSeverityTitle(severity)])
+}
+
+// Return the emoji badge with the closest color to the given hex color
+func getClosestColoredBadge(color string) string {
+	red, green, blue := parseHexColor(color)
+	closestBadge, minDistance := "", -1
+	for _, badge := range coloredBadges {
+		badgeRed, badgeGreen, badgeBlue := parseHexColor(badge.hexCode)
+		distance := square(badgeRed-red) + square(badgeGreen-green) + square(badgeBlue-blue)
+		if minDistance < 0 || distance < minDistance {
+			closestBadge, minDistance = badge.badge, distance
+		}
+	}
+	return closestBadge
+}
+
+// Parse a validated hex color, in the #RGB or #RRGGBB formats
+func parseHexColor(color string) (red, green, blue int) {
+	hexCode := strings.TrimPrefix(color, "#")
+	if len(hexCode) == 3 {
+		hexCode = string([]byte{hexCode[0], hexCode[0], hexCode[1], hexCode[1], hexCode[2], hexCode[2]})
+	}
+	value, err := strconv.ParseUint(hexCode, 16, 32)
+	if err != nil {
+		return 0, 0, 0
+	}
+	return int(value >> 16 & 0xFF), int(value >> 8 & 0xFF), int(value & 0xFF)
+}
+
+func square(value int) int {
+	return value * value
+}
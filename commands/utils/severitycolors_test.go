@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSeverityColors(t *testing.T) {
+	params := Params{SeverityColors: map[string]string{"critical": "#0072B2", "Low": "#FFF"}}
+	assert.NoError(t, params.validateSeverityColors())
+	// The severities are matched regardless of their case
+	assert.Equal(t, map[string]string{"Critical": "#0072B2", "Low": "#FFF"}, params.SeverityColors)
+
+	params.SeverityColors = map[string]string{"Severe": "#0072B2"}
+	assert.EqualError(t, params.validateSeverityColors(), fmt.Sprintf(errInvalidSeverity, "Severe", severityColorsParam))
+	for _, color := range []string{"0072B2", "#0072B", "#GGGGGG", "blue"} {
+		params.SeverityColors = map[string]string{"High": color}
+		assert.EqualError(t, params.validateSeverityColors(), fmt.Sprintf(errInvalidSeverityColor, color, "High"))
+	}
+}
+
+func TestParseSeverityColors(t *testing.T) {
+	severityColors, err := parseSeverityColors(SeverityColorsEnv, "Critical=#0072B2, High = #E69F00")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Critical": "#0072B2", "High": "#E69F00"}, severityColors)
+
+	_, err = parseSeverityColors(SeverityColorsEnv, "Critical:#0072B2")
+	assert.EqualError(t, err, fmt.Sprintf(errInvalidSeverityColorsEnv, SeverityColorsEnv, "Critical:#0072B2"))
+}
+
+func TestGetSeverityColor(t *testing.T) {
+	severityColors := map[string]string{"Critical": "#0072B2"}
+	assert.Equal(t, "#0072B2", GetSeverityColor("critical", severityColors))
+	assert.Equal(t, defaultSeverityColors["High"], GetSeverityColor("High", severityColors))
+	assert.Empty(t, GetSeverityColor("Unknown", severityColors))
+}
+
+func TestGetSeverityBadgeWithColors(t *testing.T) {
+	// A color-blind-friendly palette
+	severityColors := map[string]string{"Critical": "#0072B2", "High": "#E69F00", "Medium": "#000", "Low": defaultSeverityColors["Low"]}
+	assert.Equal(t, "🔵", GetSeverityBadge("Critical", severityColors))
+	assert.Equal(t, "🟠", GetSeverityBadge("High", severityColors))
+	assert.Equal(t, "⚫", GetSeverityBadge("Medium", severityColors))
+	assert.Equal(t, "🟢", GetSeverityBadge("Low", severityColors))
+	assert.Equal(t, "", GetSeverityBadge("none", severityColors))
+}
+
+func TestStandardOutputSeverityColors(t *testing.T) {
+	so := &StandardOutput{SeverityColors: map[string]string{"Critical": "#0072B2", "Low": defaultSeverityColors["Low"]}}
+	// Only the severities with custom colors are shown by their badges
+	assert.Equal(t, "🔵<br>", so.severityTag("Critical"))
+	assert.Equal(t, GetSeverityTag("High"), so.severityTag("High"))
+	assert.Equal(t, GetSeverityTag("Low"), so.severityTag("Low"))
+	legend := so.SeverityLegend()
+	assert.Contains(t, legend, "🔵 Critical")
+	assert.Contains(t, legend, GetIconTag(highSeveritySource)+" High")
+}
@@ -6,12 +6,16 @@ import (
 	"strings"
 )
 
-type SimplifiedOutput struct{}
+type SimplifiedOutput struct {
+	// The maximal number of fix versions shown in a row. If zero, the default of 3 fix versions is used.
+	MaxFixedVersions int
+	// If true, the issues table is rendered with fewer columns, for narrow displays
+	CompactTable bool
+}
 
 func (smo *SimplifiedOutput) TableRow(vulnerability formats.VulnerabilityOrViolationRow) string {
-	var cveId string
-	if len(vulnerability.Cves) > 0 {
-		cveId = vulnerability.Cves[0].Id
+	if smo.CompactTable {
+		return smo.compactTableRow(vulnerability)
 	}
 	var directDependencies strings.Builder
 	if len(vulnerability.Components) > 0 {
@@ -24,8 +28,24 @@ func (smo *SimplifiedOutput) TableRow(vulnerability formats.VulnerabilityOrViola
 		strings.TrimSuffix(directDependencies.String(), ", "),
 		vulnerability.ImpactedDependencyName,
 		vulnerability.ImpactedDependencyVersion,
-		strings.Join(vulnerability.FixedVersions, " "),
-		cveId)
+		getFixedVersionsCell(vulnerability, smo.MaxFixedVersions, " "),
+		getCveIdCell(vulnerability, " "))
+}
+
+// The severity and the CVE share a single column, and the direct dependencies column is dropped
+func (smo *SimplifiedOutput) compactTableRow(vulnerability formats.VulnerabilityOrViolationRow) string {
+	return fmt.Sprintf("\n| %s | %s:%s | %s |",
+		strings.TrimSpace(vulnerability.Severity+" "+getCveIdCell(vulnerability, " ")),
+		vulnerability.ImpactedDependencyName,
+		vulnerability.ImpactedDependencyVersion,
+		getFixedVersionsCell(vulnerability, smo.MaxFixedVersions, " "))
+}
+
+func (smo *SimplifiedOutput) IacTableRow(iacRow IacRow) string {
+	return fmt.Sprintf("\n| %s | %s | %s |",
+		iacRow.Severity,
+		iacRow.location(),
+		iacRow.markdownFinding())
 }
 
 func (smo *SimplifiedOutput) NoVulnerabilitiesTitle() string {
@@ -37,9 +57,27 @@ func (smo *SimplifiedOutput) VulnerabiltiesTitle() string {
 }
 
 func (smo *SimplifiedOutput) TableHeader() string {
+	if smo.CompactTable {
+		return simplifiedCompactTableHeader
+	}
 	return simplifiedTableHeader
 }
 
+func (smo *SimplifiedOutput) IacTableHeader() string {
+	return simplifiedIacTableHeader
+}
+
+// The simplified output doesn't support HTML, so the section is always expanded
+func (smo *SimplifiedOutput) CollapsibleSection(summary, content string) string {
+	return fmt.Sprintf("\n\n**%s**\n%s", summary, content)
+}
+
+// The simplified output displays the severity as text, and therefore no legend is needed
+func (smo *SimplifiedOutput) SeverityLegend() string {
+	return ""
+}
+
 func (smo *SimplifiedOutput) IsFrogbotResultComment(comment string) bool {
-	return strings.HasPrefix(comment, GetSimplifiedTitle(NoVulnerabilityBannerSource)) || strings.HasPrefix(comment, GetSimplifiedTitle(VulnerabilitiesBannerSource))
+	comment = TrimBotHeader(comment)
+	return strings.HasPrefix(comment, GetSimplifiedTitle(NoVulnerabilityBannerSource)) || strings.HasPrefix(comment, GetSimplifiedTitle(VulnerabilitiesBannerSource)) || isIssuesMarkerComment(comment)
 }
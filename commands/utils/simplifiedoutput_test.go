@@ -60,6 +60,16 @@ func TestSimplifiedOutput_TableRow(t *testing.T) {
 			},
 			expectedOutput: "\n| Critical |  | impacted_dep | 4.0.0 | 5.0.0 6.0.0 | CVE-2022-0002 |",
 		},
+		{
+			name: "More fix versions than shown",
+			vulnerability: formats.VulnerabilityOrViolationRow{
+				Severity:                  "Medium",
+				ImpactedDependencyName:    "impacted_dep",
+				ImpactedDependencyVersion: "1.0.0",
+				FixedVersions:             []string{"3.0.0", "2.0.0", "1.1.0", "1.0.1", "1.0.5"},
+			},
+			expectedOutput: "\n| Medium |  | impacted_dep | 1.0.0 | 1.0.1 3.0.0 2.0.0 +2 more |  |",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -71,6 +81,22 @@ func TestSimplifiedOutput_TableRow(t *testing.T) {
 	}
 }
 
+func TestSimplifiedOutput_CompactTable(t *testing.T) {
+	smo := &SimplifiedOutput{CompactTable: true}
+	assert.Equal(t, "\n| SEVERITY / CVE | IMPACTED DEPENDENCY | FIXED VERSIONS |\n:--: | -- | :--:", smo.TableHeader())
+	vulnerability := formats.VulnerabilityOrViolationRow{
+		Severity:                  "Critical",
+		Components:                []formats.ComponentRow{{Name: "dep1", Version: "1.0.0"}},
+		ImpactedDependencyName:    "impacted",
+		ImpactedDependencyVersion: "3.0.0",
+		FixedVersions:             []string{"4.0.0"},
+		Cves:                      []formats.CveRow{{Id: "CVE-1"}},
+	}
+	assert.Equal(t, "\n| Critical CVE-1 | impacted:3.0.0 | 4.0.0 |", smo.TableRow(vulnerability))
+	vulnerability.Cves = nil
+	assert.Equal(t, "\n| Critical | impacted:3.0.0 | 4.0.0 |", smo.TableRow(vulnerability))
+}
+
 func TestSimplifiedOutput_IsFrogbotResultComment(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -102,3 +128,9 @@ func TestSimplifiedOutput_IsFrogbotResultComment(t *testing.T) {
 		})
 	}
 }
+
+func TestSimplifiedOutput_IacTableRow(t *testing.T) {
+	smo := &SimplifiedOutput{}
+	row := smo.IacTableRow(IacRow{Severity: "Medium", File: "k8s/service.yaml", Finding: "Service exposes a NodePort"})
+	assert.Equal(t, "\n| Medium | k8s/service.yaml | Service exposes a NodePort |", row)
+}
@@ -6,14 +6,19 @@ import (
 	"strings"
 )
 
-type StandardOutput struct{}
+type StandardOutput struct {
+	// The configured colors of the severities. Severities with a custom color are shown by the emoji badge with the closest color, instead of their icon.
+	SeverityColors map[string]string
+	// The maximal number of fix versions shown in a row. If zero, the default of 3 fix versions is used.
+	MaxFixedVersions int
+	// If true, the issues table is rendered with fewer columns, for narrow displays
+	CompactTable bool
+}
 
 func (so *StandardOutput) TableRow(vulnerability formats.VulnerabilityOrViolationRow) string {
-	var cveId string
-	if len(vulnerability.Cves) > 0 {
-		cveId = vulnerability.Cves[0].Id
+	if so.CompactTable {
+		return so.compactTableRow(vulnerability)
 	}
-
 	var directDependencies, directDependenciesVersions strings.Builder
 	if len(vulnerability.Components) > 0 {
 		for _, dependency := range vulnerability.Components {
@@ -23,14 +28,35 @@ func (so *StandardOutput) TableRow(vulnerability formats.VulnerabilityOrViolatio
 	}
 
 	return fmt.Sprintf("\n| %s%8s | %s | %s | %s | %s | %s | %s ",
-		GetSeverityTag(IconName(vulnerability.Severity)),
+		so.severityTag(vulnerability.Severity),
 		vulnerability.Severity,
 		strings.TrimSuffix(directDependencies.String(), "<br>"),
 		strings.TrimSuffix(directDependenciesVersions.String(), "<br>"),
 		vulnerability.ImpactedDependencyName,
 		vulnerability.ImpactedDependencyVersion,
-		strings.Join(vulnerability.FixedVersions, "<br>"),
-		cveId)
+		getFixedVersionsCell(vulnerability, so.MaxFixedVersions, "<br>"),
+		getCveIdCell(vulnerability, "<br>"))
+}
+
+// The severity and the CVE share a single column, and the direct dependencies columns are dropped
+func (so *StandardOutput) compactTableRow(vulnerability formats.VulnerabilityOrViolationRow) string {
+	severityAndCve := so.severityTag(vulnerability.Severity) + vulnerability.Severity
+	if cveIdCell := getCveIdCell(vulnerability, "<br>"); cveIdCell != "" {
+		severityAndCve += "<br>" + cveIdCell
+	}
+	return fmt.Sprintf("\n| %s | %s:%s | %s ",
+		severityAndCve,
+		vulnerability.ImpactedDependencyName,
+		vulnerability.ImpactedDependencyVersion,
+		getFixedVersionsCell(vulnerability, so.MaxFixedVersions, "<br>"))
+}
+
+func (so *StandardOutput) IacTableRow(iacRow IacRow) string {
+	return fmt.Sprintf("\n| %s%8s | %s | %s ",
+		so.severityTag(iacRow.Severity),
+		iacRow.Severity,
+		iacRow.location(),
+		iacRow.markdownFinding())
 }
 
 func (so *StandardOutput) NoVulnerabilitiesTitle() string {
@@ -42,9 +68,39 @@ func (so *StandardOutput) VulnerabiltiesTitle() string {
 }
 
 func (so *StandardOutput) TableHeader() string {
+	if so.CompactTable {
+		return compactTableHeader
+	}
 	return tableHeader
 }
 
+func (so *StandardOutput) IacTableHeader() string {
+	return iacTableHeader
+}
+
+func (so *StandardOutput) CollapsibleSection(summary, content string) string {
+	return fmt.Sprintf("\n\n<details>\n<summary>%s</summary>\n%s\n\n</details>", summary, content)
+}
+
+func (so *StandardOutput) SeverityLegend() string {
+	return fmt.Sprintf(severityLegend, so.severityIcon("Critical", criticalSeveritySource), so.severityIcon("High", highSeveritySource),
+		so.severityIcon("Medium", mediumSeveritySource), so.severityIcon("Low", lowSeveritySource))
+}
+
+func (so *StandardOutput) severityTag(severity string) string {
+	if isCustomSeverityColor(severity, so.SeverityColors) {
+		return GetSeverityBadge(severity, so.SeverityColors) + "<br>"
+	}
+	return GetSeverityTag(IconName(severity))
+}
+
+func (so *StandardOutput) severityIcon(severity string, iconSource ImageSource) string {
+	if isCustomSeverityColor(severity, so.SeverityColors) {
+		return GetSeverityBadge(severity, so.SeverityColors)
+	}
+	return GetIconTag(iconSource)
+}
+
 func (so *StandardOutput) IsFrogbotResultComment(comment string) bool {
-	return strings.Contains(comment, GetIconTag(NoVulnerabilityBannerSource)) || strings.Contains(comment, GetIconTag(VulnerabilitiesBannerSource))
+	return strings.Contains(comment, GetIconTag(NoVulnerabilityBannerSource)) || strings.Contains(comment, GetIconTag(VulnerabilitiesBannerSource)) || isIssuesMarkerComment(comment)
 }
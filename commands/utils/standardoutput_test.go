@@ -81,6 +81,23 @@ func TestStandardOutput_TableRow(t *testing.T) {
 	}
 }
 
+func TestStandardOutput_CompactTable(t *testing.T) {
+	so := &StandardOutput{CompactTable: true}
+	assert.Equal(t, "\n| SEVERITY / CVE | IMPACTED DEPENDENCY | FIXED VERSIONS\n:--: | -- | :--:", so.TableHeader())
+	vulnerability := formats.VulnerabilityOrViolationRow{
+		Severity:                  "High",
+		Components:                []formats.ComponentRow{{Name: "dep1", Version: "1.0.0"}},
+		ImpactedDependencyName:    "impacted",
+		ImpactedDependencyVersion: "3.0.0",
+		FixedVersions:             []string{"4.0.0", "5.0.0"},
+		Cves:                      []formats.CveRow{{Id: "CVE-1"}, {Id: "CVE-2"}},
+	}
+	// The direct dependencies columns are dropped
+	assert.Equal(t, "\n| ![](https://raw.githubusercontent.com/jfrog/frogbot/master/resources/highSeverity.png)<br>High<br>CVE-1 | impacted:3.0.0 | 4.0.0<br>5.0.0 ", so.TableRow(vulnerability))
+	vulnerability.Cves = nil
+	assert.Equal(t, "\n| ![](https://raw.githubusercontent.com/jfrog/frogbot/master/resources/highSeverity.png)<br>High | impacted:3.0.0 | 4.0.0<br>5.0.0 ", so.TableRow(vulnerability))
+}
+
 func TestStandardOutput_IsFrogbotResultComment(t *testing.T) {
 	so := &StandardOutput{}
 
@@ -107,3 +124,20 @@ func TestStandardOutput_IsFrogbotResultComment(t *testing.T) {
 		assert.Equal(t, test.expected, result)
 	}
 }
+
+func TestStandardOutput_SeverityLegend(t *testing.T) {
+	so := &StandardOutput{}
+	legend := so.SeverityLegend()
+	assert.Contains(t, legend, GetIconTag(criticalSeveritySource)+" Critical")
+	assert.Contains(t, legend, GetIconTag(lowSeveritySource)+" Low")
+}
+
+func TestStandardOutput_IacTableRow(t *testing.T) {
+	so := &StandardOutput{}
+	row := so.IacTableRow(IacRow{Severity: "High", File: "chart/templates/deployment.yaml", Line: 12, Finding: "Container is running as root"})
+	assert.Equal(t, "\n| "+GetSeverityTag(IconName("High"))+"    High | chart/templates/deployment.yaml:12 | Container is running as root ", row)
+
+	// The Markdown of the finding is escaped, so that it doesn't break the table
+	row = so.IacTableRow(IacRow{Severity: "High", File: "values.yaml", Finding: "Set *runAsUser* | `securityContext`\nto <non-root>"})
+	assert.Equal(t, "\n| "+GetSeverityTag(IconName("High"))+"    High | values.yaml | Set \\*runAsUser\\* \\| \\`securityContext\\` to &lt;non-root&gt; ", row)
+}
@@ -0,0 +1,258 @@
+package utils
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const gitmodulesFile = ".gitmodules"
+
+// Matches the section headers of the .gitmodules file, such as [submodule "vendor/lib"]
+var gitmodulesSectionRegex = regexp.MustCompile(`^\[\s*submodule\s+"(.+)"\s*]$`)
+
+// Submodule is a git submodule, declared in the .gitmodules file of the repository
+type Submodule struct {
+	Name string
+	// The path of the submodule, relative to the root of the repository
+	Path string
+	// The clone URL of the submodule. Relative URLs are resolved against the remote URL of the repository.
+	Url string
+	// The branch tracked by the submodule. If empty, the default branch of the submodule is cloned.
+	Branch string
+	// The commit of the submodule recorded in the repository. If empty, the head of the branch is cloned.
+	Commit string
+}
+
+// GetSubmodules returns the submodules declared in the .gitmodules file of the repository in repoDir.
+// If the repository has no .gitmodules file, an empty list is returned.
+func GetSubmodules(repoDir string) ([]Submodule, error) {
+	content, err := os.ReadFile(filepath.Join(repoDir, gitmodulesFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var submodules []Submodule
+	for _, submodule := range parseGitmodules(string(content)) {
+		if _, err = submodule.getDir(repoDir); err != nil {
+			log.Warn(err.Error())
+			continue
+		}
+		submodules = append(submodules, submodule)
+	}
+	for index := range submodules {
+		submodule := &submodules[index]
+		if !isRelativeSubmoduleUrl(submodule.Url) {
+			continue
+		}
+		remoteUrl, err := getOriginUrl(repoDir)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't resolve the relative URL '%s' of the '%s' submodule: %s", submodule.Url, submodule.Name, err.Error())
+		}
+		submodule.Url = resolveSubmoduleUrl(remoteUrl, submodule.Url)
+	}
+	for index := range submodules {
+		submodules[index].Commit = ResolveSubmoduleCommit(repoDir, "HEAD", submodules[index].Path)
+	}
+	return submodules, nil
+}
+
+// ResolveSubmoduleCommit returns the commit of the submodule recorded in the given revision of the repository in repoDir.
+// The revision may be HEAD, or a branch which exists locally or in the origin remote.
+// If the repository in repoDir isn't a git repository, or the revision or the submodule can't be found, an empty string is returned.
+func ResolveSubmoduleCommit(repoDir, revision, submodulePath string) string {
+	repository, err := git.PlainOpenWithOptions(repoDir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return ""
+	}
+	for _, candidate := range []string{revision, "refs/remotes/" + git.DefaultRemoteName + "/" + revision} {
+		hash, err := repository.ResolveRevision(plumbing.Revision(candidate))
+		if err != nil {
+			continue
+		}
+		commit, err := repository.CommitObject(*hash)
+		if err != nil {
+			return ""
+		}
+		tree, err := commit.Tree()
+		if err != nil {
+			return ""
+		}
+		entry, err := tree.FindEntry(submodulePath)
+		if err != nil || entry.Mode != filemode.Submodule {
+			return ""
+		}
+		return entry.Hash.String()
+	}
+	return ""
+}
+
+// Parse the content of a .gitmodules file. The submodules without a path or a URL are skipped.
+func parseGitmodules(content string) (submodules []Submodule) {
+	var current *Submodule
+	addCurrent := func() {
+		if current != nil && current.Path != "" && current.Url != "" {
+			submodules = append(submodules, *current)
+		}
+	}
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			addCurrent()
+			current = nil
+			if match := gitmodulesSectionRegex.FindStringSubmatch(line); match != nil {
+				current = &Submodule{Name: match[1]}
+			}
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if current == nil || !found {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "path":
+			current.Path = path.Clean(value)
+		case "url":
+			current.Url = value
+		case "branch":
+			current.Branch = value
+		}
+	}
+	addCurrent()
+	return
+}
+
+func isRelativeSubmoduleUrl(submoduleUrl string) bool {
+	return strings.HasPrefix(submoduleUrl, "./") || strings.HasPrefix(submoduleUrl, "../")
+}
+
+// Resolve a relative submodule URL, such as ../lib.git, against the remote URL of the repository, as git does
+func resolveSubmoduleUrl(remoteUrl, relativeUrl string) string {
+	remoteUrl = strings.TrimSuffix(remoteUrl, "/")
+	if parsedUrl, err := url.Parse(remoteUrl); err == nil && parsedUrl.Scheme != "" && parsedUrl.Host != "" {
+		parsedUrl.Path = path.Join(parsedUrl.Path, relativeUrl)
+		return parsedUrl.String()
+	}
+	// An scp-like remote URL, such as git@github.com:jfrog/frogbot.git
+	if host, repoPath, found := strings.Cut(remoteUrl, ":"); found && !strings.Contains(host, "/") {
+		return host + ":" + strings.TrimPrefix(path.Join("/", repoPath, relativeUrl), "/")
+	}
+	return path.Join(remoteUrl, relativeUrl)
+}
+
+func getOriginUrl(repoDir string) (string, error) {
+	repository, err := git.PlainOpenWithOptions(repoDir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return "", err
+	}
+	remote, err := repository.Remote(git.DefaultRemoteName)
+	if err != nil {
+		return "", err
+	}
+	if len(remote.Config().URLs) == 0 {
+		return "", errors.New("failed to find git remote URL")
+	}
+	return remote.Config().URLs[0], nil
+}
+
+// CloneIfMissing clones the submodule into its path under repoDir, unless it's already checked out there.
+// The repository archives downloaded from the git providers include empty directories in place of the submodules.
+// The token of the git provider is sent only to the host of the git provider, since the URL is read from the .gitmodules file of the scanned branch.
+func (s *Submodule) CloneIfMissing(repoDir string, gitParams *Git) error {
+	submoduleDir, err := s.getDir(repoDir)
+	if err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(submoduleDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if len(entries) > 0 {
+		return nil
+	}
+	log.Info(fmt.Sprintf("Cloning the '%s' submodule from %s to %s", s.Name, s.Url, submoduleDir))
+	cloneOptions := &git.CloneOptions{URL: s.Url}
+	if s.Branch != "" {
+		cloneOptions.ReferenceName = getFullBranchName(s.Branch)
+	}
+	if s.Commit == "" {
+		// Only the head of the branch is checked out
+		cloneOptions.Depth = 1
+		cloneOptions.SingleBranch = true
+	}
+	if gitParams != nil && gitParams.Token != "" {
+		if isGitProviderUrl(s.Url, gitParams) {
+			cloneOptions.Auth = toBasicAuth(gitParams.Token, gitParams.Username)
+		} else {
+			log.Debug(fmt.Sprintf("The URL of the '%s' submodule isn't hosted by the git provider. Cloning it without the token of the git provider", s.Name))
+		}
+	}
+	repository, err := git.PlainClone(submoduleDir, false, cloneOptions)
+	if err != nil {
+		return fmt.Errorf("'git clone %s' of the '%s' submodule failed with error: %s", s.Url, s.Name, err.Error())
+	}
+	if s.Commit == "" {
+		return nil
+	}
+	worktree, err := repository.Worktree()
+	if err != nil {
+		return err
+	}
+	if err = worktree.Checkout(&git.CheckoutOptions{Hash: plumbing.NewHash(s.Commit)}); err != nil {
+		return fmt.Errorf("'git checkout %s' of the '%s' submodule failed with error: %s", s.Commit, s.Name, err.Error())
+	}
+	return nil
+}
+
+// Return the directory of the submodule under repoDir. Paths which lead outside of the repository, such as ../lib, are rejected.
+func (s *Submodule) getDir(repoDir string) (string, error) {
+	submoduleDir := filepath.Join(repoDir, filepath.FromSlash(s.Path))
+	relativePath, err := filepath.Rel(repoDir, submoduleDir)
+	if err != nil || relativePath == "." || relativePath == ".." || strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) || filepath.IsAbs(s.Path) {
+		return "", fmt.Errorf(errInvalidSubmodulePath, s.Path, s.Name)
+	}
+	return submoduleDir, nil
+}
+
+// The default API endpoints of the git providers, whose hosts differ from the hosts of the clone URLs
+var defaultGitProviderHosts = map[vcsutils.VcsProvider]string{
+	vcsutils.GitHub:     "github.com",
+	vcsutils.GitLab:     "gitlab.com",
+	vcsutils.AzureRepos: "dev.azure.com",
+}
+
+// Return true if the submodule URL is an http(s) URL of the host of the git provider. The API host of GitHub, api.github.com, serves its clone URLs on github.com.
+func isGitProviderUrl(submoduleUrl string, gitParams *Git) bool {
+	parsedUrl, err := url.Parse(submoduleUrl)
+	if err != nil || (parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https") {
+		return false
+	}
+	providerHost := defaultGitProviderHosts[gitParams.GitProvider]
+	if gitParams.ApiEndpoint != "" {
+		apiUrl, err := url.Parse(gitParams.ApiEndpoint)
+		if err != nil || apiUrl.Host == "" {
+			return false
+		}
+		providerHost = strings.TrimPrefix(apiUrl.Host, "api.")
+	}
+	return providerHost != "" && strings.EqualFold(parsedUrl.Host, providerHost)
+}
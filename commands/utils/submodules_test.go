@@ -0,0 +1,189 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGitmodules(t *testing.T) {
+	content := `# Vendored libraries
+[submodule "lib"]
+	path = vendor/lib
+	url = https://github.com/jfrog/lib.git
+[submodule "tools"]
+	path = tools/
+	url = ../tools.git
+	branch = dev
+[submodule "no-url"]
+	path = no-url
+[core]
+	path = not-a-submodule
+`
+	assert.Equal(t, []Submodule{
+		{Name: "lib", Path: "vendor/lib", Url: "https://github.com/jfrog/lib.git"},
+		{Name: "tools", Path: "tools", Url: "../tools.git", Branch: "dev"},
+	}, parseGitmodules(content))
+}
+
+func TestResolveSubmoduleUrl(t *testing.T) {
+	assert.Equal(t, "https://github.com/jfrog/tools.git", resolveSubmoduleUrl("https://github.com/jfrog/frogbot.git", "../tools.git"))
+	assert.Equal(t, "https://github.com/jfrog/frogbot/tools.git", resolveSubmoduleUrl("https://github.com/jfrog/frogbot/", "./tools.git"))
+	assert.Equal(t, "git@github.com:jfrog/tools.git", resolveSubmoduleUrl("git@github.com:jfrog/frogbot.git", "../tools.git"))
+	assert.Equal(t, "/repos/tools", resolveSubmoduleUrl("/repos/frogbot", "../tools"))
+}
+
+func TestGetSubmodules(t *testing.T) {
+	dir := t.TempDir()
+	libCommit := createSubmoduleRepo(t, filepath.Join(dir, "lib"))
+	repoDir := filepath.Join(dir, "repo")
+	repository, err := git.PlainInit(repoDir, false)
+	assert.NoError(t, err)
+	_, err = repository.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{"https://github.com/jfrog/repo.git"}})
+	assert.NoError(t, err)
+	gitmodules := "[submodule \"lib\"]\n\tpath = lib\n\turl = ../lib.git\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(repoDir, gitmodulesFile), []byte(gitmodules), 0644))
+	commitGitlink(t, repository, gitmodules, "lib", libCommit)
+
+	submodules, err := GetSubmodules(repoDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []Submodule{{Name: "lib", Path: "lib", Url: "https://github.com/jfrog/lib.git", Commit: libCommit.String()}}, submodules)
+
+	// The commit in a branch of the origin remote
+	assert.NoError(t, repository.Storer.SetReference(plumbing.NewHashReference("refs/remotes/origin/main", mustResolve(t, repository, "HEAD"))))
+	assert.Equal(t, libCommit.String(), ResolveSubmoduleCommit(repoDir, "main", "lib"))
+	assert.Empty(t, ResolveSubmoduleCommit(repoDir, "missing-branch", "lib"))
+	assert.Empty(t, ResolveSubmoduleCommit(repoDir, "HEAD", gitmodulesFile))
+
+	// A repository without submodules
+	submodules, err = GetSubmodules(t.TempDir())
+	assert.NoError(t, err)
+	assert.Empty(t, submodules)
+}
+
+func TestCloneIfMissing(t *testing.T) {
+	dir := t.TempDir()
+	libDir := filepath.Join(dir, "lib")
+	firstCommit := createSubmoduleRepo(t, libDir)
+	libRepository, err := git.PlainOpen(libDir)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(libDir, "package.json"), []byte(`{"version": "2.0.0"}`), 0644))
+	commitAll(t, libRepository)
+
+	// The recorded commit of the submodule is checked out into the empty submodule dir of the downloaded repository
+	repoDir := filepath.Join(dir, "repo")
+	assert.NoError(t, os.MkdirAll(filepath.Join(repoDir, "vendor", "lib"), 0755))
+	submodule := &Submodule{Name: "lib", Path: "vendor/lib", Url: libDir, Commit: firstCommit.String()}
+	assert.NoError(t, submodule.CloneIfMissing(repoDir, &Git{}))
+	content, err := os.ReadFile(filepath.Join(repoDir, "vendor", "lib", "package.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"version": "1.0.0"}`, string(content))
+
+	// A checked out submodule isn't cloned again
+	submodule.Url = filepath.Join(dir, "missing")
+	assert.NoError(t, submodule.CloneIfMissing(repoDir, &Git{}))
+
+	// Without a recorded commit, the head of the submodule is cloned
+	submodule = &Submodule{Name: "lib", Path: "lib", Url: libDir}
+	assert.NoError(t, submodule.CloneIfMissing(repoDir, &Git{}))
+	content, err = os.ReadFile(filepath.Join(repoDir, "lib", "package.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"version": "2.0.0"}`, string(content))
+
+	submodule = &Submodule{Name: "missing", Path: "missing", Url: filepath.Join(dir, "missing")}
+	assert.Error(t, submodule.CloneIfMissing(repoDir, &Git{}))
+
+	// Paths outside of the repository are rejected
+	for _, submodulePath := range []string{"..", "../lib", "vendor/../../lib", ".", "/tmp/lib"} {
+		submodule = &Submodule{Name: "escape", Path: submodulePath, Url: libDir}
+		assert.EqualError(t, submodule.CloneIfMissing(repoDir, &Git{}), fmt.Sprintf(errInvalidSubmodulePath, submodulePath, "escape"), submodulePath)
+	}
+	assert.NoDirExists(t, filepath.Join(dir, "lib", "lib"))
+}
+
+func TestIsGitProviderUrl(t *testing.T) {
+	gitHub := &Git{GitProvider: vcsutils.GitHub}
+	assert.True(t, isGitProviderUrl("https://github.com/jfrog/lib.git", gitHub))
+	assert.True(t, isGitProviderUrl("https://GitHub.com/jfrog/lib.git", &Git{GitProvider: vcsutils.GitHub, ApiEndpoint: "https://api.github.com"}))
+	assert.False(t, isGitProviderUrl("https://attacker.example.com/jfrog/lib.git", gitHub))
+	assert.False(t, isGitProviderUrl("https://github.com.attacker.example.com/lib.git", gitHub))
+	assert.False(t, isGitProviderUrl("git@github.com:jfrog/lib.git", gitHub))
+	assert.False(t, isGitProviderUrl("file:///tmp/lib", gitHub))
+
+	gitHubEnterprise := &Git{GitProvider: vcsutils.GitHub, ApiEndpoint: "https://github.example.com/api/v3"}
+	assert.True(t, isGitProviderUrl("https://github.example.com/team/lib.git", gitHubEnterprise))
+	assert.False(t, isGitProviderUrl("https://github.com/team/lib.git", gitHubEnterprise))
+
+	bitbucketServer := &Git{GitProvider: vcsutils.BitbucketServer, ApiEndpoint: "https://bitbucket.example.com:7990"}
+	assert.True(t, isGitProviderUrl("https://bitbucket.example.com:7990/scm/team/lib.git", bitbucketServer))
+	assert.False(t, isGitProviderUrl("https://bitbucket.example.com/scm/team/lib.git", bitbucketServer))
+	assert.False(t, isGitProviderUrl("https://bitbucket.example.com:7990/scm/team/lib.git", &Git{GitProvider: vcsutils.BitbucketServer}))
+}
+
+// Create a repository with a single package.json file, and return its commit
+func createSubmoduleRepo(t *testing.T, repoDir string) plumbing.Hash {
+	repository, err := git.PlainInit(repoDir, false)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(repoDir, "package.json"), []byte(`{"version": "1.0.0"}`), 0644))
+	return commitAll(t, repository)
+}
+
+func commitAll(t *testing.T, repository *git.Repository) plumbing.Hash {
+	worktree, err := repository.Worktree()
+	assert.NoError(t, err)
+	assert.NoError(t, worktree.AddWithOptions(&git.AddOptions{All: true}))
+	hash, err := worktree.Commit("commit", &git.CommitOptions{Author: testSignature()})
+	assert.NoError(t, err)
+	return hash
+}
+
+// Commit a tree with the .gitmodules file and the gitlink of the submodule to the current branch
+func commitGitlink(t *testing.T, repository *git.Repository, gitmodules, submodulePath string, submoduleCommit plumbing.Hash) {
+	blob := repository.Storer.NewEncodedObject()
+	blob.SetType(plumbing.BlobObject)
+	writer, err := blob.Writer()
+	assert.NoError(t, err)
+	_, err = writer.Write([]byte(gitmodules))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	blobHash, err := repository.Storer.SetEncodedObject(blob)
+	assert.NoError(t, err)
+
+	tree := &object.Tree{Entries: []object.TreeEntry{
+		{Name: gitmodulesFile, Mode: filemode.Regular, Hash: blobHash},
+		{Name: submodulePath, Mode: filemode.Submodule, Hash: submoduleCommit},
+	}}
+	treeObject := repository.Storer.NewEncodedObject()
+	assert.NoError(t, tree.Encode(treeObject))
+	treeHash, err := repository.Storer.SetEncodedObject(treeObject)
+	assert.NoError(t, err)
+
+	commit := &object.Commit{Author: *testSignature(), Committer: *testSignature(), Message: "add submodule", TreeHash: treeHash}
+	commitObject := repository.Storer.NewEncodedObject()
+	assert.NoError(t, commit.Encode(commitObject))
+	commitHash, err := repository.Storer.SetEncodedObject(commitObject)
+	assert.NoError(t, err)
+	head, err := repository.Storer.Reference(plumbing.HEAD)
+	assert.NoError(t, err)
+	assert.NoError(t, repository.Storer.SetReference(plumbing.NewHashReference(head.Target(), commitHash)))
+}
+
+func mustResolve(t *testing.T, repository *git.Repository, revision string) plumbing.Hash {
+	hash, err := repository.ResolveRevision(plumbing.Revision(revision))
+	assert.NoError(t, err)
+	return *hash
+}
+
+func testSignature() *object.Signature {
+	return &object.Signature{Name: "JFrog-Frogbot", Email: "eco-system+frogbot@jfrog.com", When: time.Now()}
+}
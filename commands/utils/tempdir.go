@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// When true, the temp directories are not removed, to allow troubleshooting
+var keepTempDirs bool
+
+func SetKeepTempDirs(keep bool) {
+	keepTempDirs = keep
+}
+
+// ConfigureTempDir sets the base directory of all the temp directories created by Frogbot and by the scan.
+// tempDir - The base directory. If empty, the system default temp directory is used.
+func ConfigureTempDir(tempDir string) error {
+	if tempDir == "" {
+		return nil
+	}
+	tempDir, err := filepath.Abs(tempDir)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(tempDir, 0700); err != nil {
+		return err
+	}
+	log.Debug("Using temp directory base:", tempDir)
+	fileutils.SetTempDirBase(tempDir)
+	return nil
+}
+
+// CreateTempDir creates a new temp directory under the configured temp directory base.
+func CreateTempDir() (string, error) {
+	return fileutils.CreateTempDir()
+}
+
+// RemoveTempDir removes a temp directory created by CreateTempDir, unless Frogbot is configured to keep the temp directories.
+func RemoveTempDir(dirPath string) error {
+	if keepTempDirs {
+		log.Info("Keeping the temp directory for troubleshooting:", dirPath)
+		return nil
+	}
+	return fileutils.RemoveTempDir(dirPath)
+}
+
+// getConfiguredTempDir returns the temp directory set in the frogbot-config file. All the repositories must use the same temp directory.
+func getConfiguredTempDir(configAggregator FrogbotConfigAggregator) (tempDir string, err error) {
+	for _, repo := range configAggregator {
+		if repo.TempDir == "" {
+			continue
+		}
+		if tempDir != "" && tempDir != repo.TempDir {
+			return "", errors.New(errMultipleTempDirs)
+		}
+		tempDir = repo.TempDir
+	}
+	return
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigureTempDir(t *testing.T) {
+	defer fileutils.SetTempDirBase(os.TempDir())
+	tempDirBase := filepath.Join(t.TempDir(), "frogbot-tmp")
+	assert.NoError(t, ConfigureTempDir(tempDirBase))
+
+	tempDir, err := CreateTempDir()
+	assert.NoError(t, err)
+	assert.Equal(t, tempDirBase, filepath.Dir(tempDir))
+	assert.NoError(t, RemoveTempDir(tempDir))
+	assert.NoDirExists(t, tempDir)
+}
+
+func TestRemoveTempDirKeepTemp(t *testing.T) {
+	SetKeepTempDirs(true)
+	defer SetKeepTempDirs(false)
+	tempDir, err := CreateTempDir()
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, fileutils.RemoveTempDir(tempDir))
+	}()
+	assert.NoError(t, RemoveTempDir(tempDir))
+	assert.DirExists(t, tempDir)
+}
+
+func TestGetConfiguredTempDir(t *testing.T) {
+	configAggregator := FrogbotConfigAggregator{{}, {Params: Params{TempDir: "/tmp/frogbot"}}}
+	tempDir, err := getConfiguredTempDir(configAggregator)
+	assert.NoError(t, err)
+	assert.Equal(t, "/tmp/frogbot", tempDir)
+
+	configAggregator = append(configAggregator, FrogbotRepoConfig{Params: Params{TempDir: "/tmp/other"}})
+	_, err = getConfiguredTempDir(configAggregator)
+	assert.EqualError(t, err, errMultipleTempDirs)
+}
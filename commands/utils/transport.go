@@ -0,0 +1,458 @@
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jfrog/froggit-go/vcsutils"
+	coreconfig "github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/auth"
+	clientconfig "github.com/jfrog/jfrog-client-go/config"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/jfrog/jfrog-client-go/utils/io/httputils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray"
+	"golang.org/x/net/http/httpguts"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// The VCS clients send their requests using http.DefaultTransport, while the Xray client creates its own transport,
+// which reads the proxy environment variables only once - on its first request.
+// ConfigureProxy should therefore be called before any request is sent, and again after the config file is loaded.
+// proxy - The proxy URL to use for all outgoing requests. If empty, the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables are used.
+func ConfigureProxy(proxy string) error {
+	if proxy != "" {
+		proxyUrl, err := parseProxyUrl(proxy)
+		if err != nil {
+			return err
+		}
+		// The explicit proxy overrides the environment, also for the Xray client
+		for _, proxyEnv := range []string{httpProxyEnv, httpsProxyEnv} {
+			if err = os.Setenv(proxyEnv, proxy); err != nil {
+				return err
+			}
+		}
+		log.Debug("Using proxy:", proxyUrl.Redacted())
+	}
+	transport, ok := getDefaultTransport()
+	if !ok {
+		return nil
+	}
+	proxyFunc := httpproxy.FromEnvironment().ProxyFunc()
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+	return nil
+}
+
+func parseProxyUrl(proxy string) (*url.URL, error) {
+	proxyUrl, err := url.Parse(proxy)
+	if err != nil || proxyUrl.Scheme == "" || proxyUrl.Host == "" {
+		return nil, fmt.Errorf(errInvalidProxy, proxy)
+	}
+	return proxyUrl, nil
+}
+
+// getConfiguredProxy returns the proxy set in the frogbot-config file. All the repositories must use the same proxy.
+func getConfiguredProxy(configAggregator FrogbotConfigAggregator) (proxy string, err error) {
+	for _, repo := range configAggregator {
+		if repo.Proxy == "" {
+			continue
+		}
+		if proxy != "" && proxy != repo.Proxy {
+			return "", errors.New(errMultipleProxies)
+		}
+		proxy = repo.Proxy
+	}
+	return
+}
+
+// ConfigureCaCert trusts the CA certificates of the given bundle, in addition to the system CA certificates,
+// for servers which use certificates signed by an internal CA, such as on-prem Git providers and JFrog Platform installations.
+// The GitHub, Bitbucket and Azure Repos clients use the bundle through http.DefaultTransport. The GitLab and Xray clients create their own transports, which use the system certificates.
+// On Linux, these are read once, when they're first used, and include the SSL_CERT_FILE file, which is set to the bundle. ConfigureCaCert must therefore be called
+// before any request is sent, and before any other call to x509.SystemCertPool, for these clients to trust the bundle.
+// caCertPath - The path of a PEM file with one or more CA certificates. If empty, only the system CA certificates are used.
+func ConfigureCaCert(caCertPath string) error {
+	if caCertPath == "" {
+		return nil
+	}
+	caCerts, err := os.ReadFile(caCertPath)
+	if err != nil {
+		return fmt.Errorf(errReadCaCert, caCertPath, err.Error())
+	}
+	if os.Getenv(sslCertFileEnv) == "" {
+		if err = os.Setenv(sslCertFileEnv, caCertPath); err != nil {
+			return err
+		}
+	}
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil || rootCAs == nil {
+		rootCAs = x509.NewCertPool()
+	}
+	if !rootCAs.AppendCertsFromPEM(caCerts) {
+		return fmt.Errorf(errInvalidCaCert, caCertPath)
+	}
+	transport, ok := getDefaultTransport()
+	if !ok {
+		return nil
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if transport.TLSClientConfig != nil {
+		tlsConfig = transport.TLSClientConfig.Clone()
+	}
+	tlsConfig.RootCAs = rootCAs
+	transport.TLSClientConfig = tlsConfig
+	log.Debug("Using the CA certificates of:", caCertPath)
+	return nil
+}
+
+// getConfiguredCaCert returns the CA bundle set in the frogbot-config file. All the repositories must use the same CA bundle.
+func getConfiguredCaCert(configAggregator FrogbotConfigAggregator) (caCertPath string, err error) {
+	for _, repo := range configAggregator {
+		if repo.CaCertPath == "" {
+			continue
+		}
+		if caCertPath != "" && caCertPath != repo.CaCertPath {
+			return "", errors.New(errMultipleCaCerts)
+		}
+		caCertPath = repo.CaCertPath
+	}
+	return
+}
+
+// The value logged instead of the values of the sensitive headers
+const redactedValue = "***"
+
+// ConfigureCustomHeaders adds the headers to the requests sent to the Git provider and to Xray, such as the headers required by an API gateway.
+// The GitHub, Bitbucket and Azure Repos clients send their requests using http.DefaultTransport, so it's wrapped by a transport which adds the headers
+// to the requests sent to the hosts configured by ConfigureClientsHosts only. The GitLab client creates its own transport, so it doesn't send the headers.
+// The Xray client creates its own transport, so the headers are added only to the requests of the Xray clients created by NewXrayServiceManager.
+// The Xray clients created by the audit of jfrog-cli-core don't send them.
+// The values of sensitive headers, such as tokens, are redacted from the logs.
+// headers - The headers to add, by their names. If empty, the previously configured headers are kept.
+func ConfigureCustomHeaders(headers map[string]string) error {
+	if len(headers) == 0 {
+		return nil
+	}
+	if err := validateCustomHeaders(headers); err != nil {
+		return err
+	}
+	headersTransport := getCustomHeadersTransport()
+	headersTransport.headers = headers
+	http.DefaultTransport = &headersTransport
+	log.Debug("Using the custom headers:", formatCustomHeaders(headers))
+	return nil
+}
+
+// The API hosts of the git providers, used if the API endpoint isn't set
+var defaultGitProviderApiHosts = map[vcsutils.VcsProvider]string{
+	vcsutils.GitHub:         "api.github.com",
+	vcsutils.GitLab:         "gitlab.com",
+	vcsutils.BitbucketCloud: "api.bitbucket.org",
+	vcsutils.AzureRepos:     "dev.azure.com",
+}
+
+// ConfigureClientsHosts sets the hosts of the Git provider API and of the JFrog Platform, which are the only hosts sent the custom headers,
+// so that the headers, which may include credentials of a gateway, aren't sent to other servers, such as package registries and webhooks.
+// gitParams - The git params, or nil if the Git provider isn't used.
+func ConfigureClientsHosts(gitParams *Git, server *coreconfig.ServerDetails) {
+	var hosts []string
+	if gitParams != nil {
+		apiEndpoint := gitParams.ApiEndpoint
+		if apiEndpoint == "" {
+			apiEndpoint = "https://" + defaultGitProviderApiHosts[gitParams.GitProvider]
+		}
+		hosts = appendUrlHost(hosts, apiEndpoint)
+	}
+	if server != nil {
+		for _, serverUrl := range []string{server.Url, server.XrayUrl, server.ArtifactoryUrl} {
+			hosts = appendUrlHost(hosts, serverUrl)
+		}
+	}
+	headersTransport := getCustomHeadersTransport()
+	headersTransport.hosts = hosts
+	http.DefaultTransport = &headersTransport
+}
+
+// configureGitHubAppTransport refreshes the tokens of the GitHub App in the requests sent using http.DefaultTransport,
+// which include the requests of the VCS clients, of the GitHub API clients and of git
+func configureGitHubAppTransport(gitHubApp *GitHubAppAuth) {
+	headersTransport := getCustomHeadersTransport()
+	headersTransport.gitHubApp = gitHubApp
+	http.DefaultTransport = &headersTransport
+}
+
+func appendUrlHost(hosts []string, rawUrl string) []string {
+	parsedUrl, err := url.Parse(rawUrl)
+	if err != nil || parsedUrl.Hostname() == "" {
+		return hosts
+	}
+	return append(hosts, parsedUrl.Hostname())
+}
+
+// customHeadersTransport adds the custom headers to the requests sent to its hosts, unless they're already set by the client itself, such as the Authorization header.
+// The user agent, if set, overrides the user agent of the client in the requests sent to its hosts. If Frogbot authenticates as a GitHub App, the expired tokens of the app are refreshed.
+type customHeadersTransport struct {
+	base      http.RoundTripper
+	headers   map[string]string
+	hosts     []string
+	userAgent string
+	gitHubApp *GitHubAppAuth
+}
+
+func (ht *customHeadersTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the request it receives
+	req = req.Clone(req.Context())
+	if ht.isClientHost(req.URL) {
+		for name, value := range ht.headers {
+			if req.Header.Get(name) == "" {
+				req.Header.Set(name, value)
+			}
+		}
+		if ht.userAgent != "" {
+			req.Header.Set(userAgentHeader, ht.userAgent)
+		}
+	}
+	if ht.gitHubApp != nil {
+		if err := ht.gitHubApp.refreshRequestToken(req); err != nil {
+			return nil, err
+		}
+	}
+	return ht.base.RoundTrip(req)
+}
+
+func (ht *customHeadersTransport) isClientHost(requestUrl *url.URL) bool {
+	for _, host := range ht.hosts {
+		if strings.EqualFold(requestUrl.Hostname(), host) {
+			return true
+		}
+	}
+	return false
+}
+
+// Return a copy of the custom headers transport of http.DefaultTransport, or a transport without headers which wraps http.DefaultTransport
+func getCustomHeadersTransport() customHeadersTransport {
+	if headersTransport, ok := http.DefaultTransport.(*customHeadersTransport); ok {
+		return *headersTransport
+	}
+	return customHeadersTransport{base: http.DefaultTransport}
+}
+
+// NewExternalHttpClient returns a client for the requests sent to servers other than the Git provider and the JFrog Platform, such as package registries.
+// It uses the proxy and the CA certificates configured for http.DefaultTransport, but not its custom headers, user agent and GitHub App tokens.
+func NewExternalHttpClient(timeout time.Duration) *http.Client {
+	roundTripper := http.DefaultTransport
+	if headersTransport, ok := roundTripper.(*customHeadersTransport); ok {
+		roundTripper = headersTransport.base
+	}
+	return &http.Client{Transport: roundTripper, Timeout: timeout}
+}
+
+// Return the transport of http.DefaultTransport, which may be wrapped by the custom headers transport
+func getDefaultTransport() (*http.Transport, bool) {
+	roundTripper := http.DefaultTransport
+	if headersTransport, ok := roundTripper.(*customHeadersTransport); ok {
+		roundTripper = headersTransport.base
+	}
+	transport, ok := roundTripper.(*http.Transport)
+	return transport, ok
+}
+
+func validateCustomHeaders(headers map[string]string) error {
+	for name, value := range headers {
+		if !httpguts.ValidHeaderFieldName(name) || !httpguts.ValidHeaderFieldValue(value) {
+			return fmt.Errorf(errInvalidCustomHeader, name)
+		}
+	}
+	return nil
+}
+
+// Configure the custom headers set in the JF_CUSTOM_HEADERS environment variable, if it's set
+func configureCustomHeadersFromEnv() error {
+	envValue := getTrimmedEnv(CustomHeadersEnv)
+	if envValue == "" {
+		return nil
+	}
+	headers, err := parseCustomHeaders(CustomHeadersEnv, envValue)
+	if err != nil {
+		return err
+	}
+	return ConfigureCustomHeaders(headers)
+}
+
+func parseCustomHeaders(envKey, envValue string) (map[string]string, error) {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(envValue, ",") {
+		name, value, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf(errInvalidCustomHeadersEnv, envKey, redactCustomHeadersEnv(envValue))
+		}
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
+
+// Format the headers for the logs, sorted by their names, with the values of the sensitive headers redacted
+func formatCustomHeaders(headers map[string]string) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	formatted := make([]string, 0, len(names))
+	for _, name := range names {
+		formatted = append(formatted, name+": "+redactHeaderValue(name, headers[name]))
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// The headers whose names include one of these words hold credentials, so their values aren't logged
+var sensitiveHeaderWords = []string{"authorization", "token", "secret", "key", "password", "cookie", "credential", "session"}
+
+func redactHeaderValue(name, value string) string {
+	lowerName := strings.ToLower(name)
+	for _, word := range sensitiveHeaderWords {
+		if strings.Contains(lowerName, word) {
+			return redactedValue
+		}
+	}
+	return value
+}
+
+// The value of the environment variable may include sensitive headers, so only the header names are kept in the error
+func redactCustomHeadersEnv(envValue string) string {
+	pairs := strings.Split(envValue, ",")
+	for i, pair := range pairs {
+		if name, value, found := strings.Cut(pair, "="); found {
+			pairs[i] = name + "=" + redactHeaderValue(strings.TrimSpace(name), value)
+		}
+	}
+	return strings.Join(pairs, ",")
+}
+
+// getConfiguredCustomHeaders returns the custom headers set in the frogbot-config file. All the repositories must use the same headers.
+func getConfiguredCustomHeaders(configAggregator FrogbotConfigAggregator) (headers map[string]string, err error) {
+	for _, repo := range configAggregator {
+		if len(repo.CustomHeaders) == 0 {
+			continue
+		}
+		if headers != nil && !equalHeaders(headers, repo.CustomHeaders) {
+			return nil, errors.New(errMultipleCustomHeaders)
+		}
+		headers = repo.CustomHeaders
+	}
+	return
+}
+
+func equalHeaders(headers, otherHeaders map[string]string) bool {
+	if len(headers) != len(otherHeaders) {
+		return false
+	}
+	for name, value := range headers {
+		if otherValue, exists := otherHeaders[name]; !exists || otherValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// FrogbotVersion is the version of the running Frogbot, which is included in the default user agent
+var FrogbotVersion = "0.0.0"
+
+const userAgentHeader = "User-Agent"
+
+// ConfigureUserAgent sets the User-Agent header of the requests sent to the Git provider and to Xray, so that the server admins can identify the Frogbot requests.
+// The GitHub, Bitbucket and Azure Repos clients send their requests using http.DefaultTransport, so it's wrapped by a transport which sets the header,
+// like the custom headers, in the requests sent to the hosts configured by ConfigureClientsHosts only, since the default user agent includes the repository.
+// The GitLab client creates its own transport, so its requests keep the user agent of the GitLab client.
+// The Xray clients, including the clients created by the audit of jfrog-cli-core, send the user agent of jfrog-client-go, which is replaced.
+// userAgent - The User-Agent header. If empty, a user agent with the Frogbot version and the repository, such as "frogbot/2.8.0 (jfrog/frogbot)", is used.
+// repoOwner, repoName - The scanned repository, which may be empty if it's unknown, such as when several repositories are scanned.
+func ConfigureUserAgent(userAgent, repoOwner, repoName string) error {
+	if userAgent == "" {
+		userAgent = getDefaultUserAgent(repoOwner, repoName)
+	} else if err := validateUserAgent(userAgent); err != nil {
+		return err
+	}
+	headersTransport := getCustomHeadersTransport()
+	headersTransport.userAgent = userAgent
+	http.DefaultTransport = &headersTransport
+	clientutils.SetUserAgent(userAgent)
+	log.Debug("Using the user agent:", userAgent)
+	return nil
+}
+
+func getDefaultUserAgent(repoOwner, repoName string) string {
+	userAgent := "frogbot/" + FrogbotVersion
+	switch {
+	case repoOwner != "" && repoName != "":
+		userAgent += fmt.Sprintf(" (%s/%s)", repoOwner, repoName)
+	case repoOwner != "":
+		userAgent += fmt.Sprintf(" (%s)", repoOwner)
+	}
+	return userAgent
+}
+
+func validateUserAgent(userAgent string) error {
+	if !httpguts.ValidHeaderFieldValue(userAgent) {
+		return fmt.Errorf(errInvalidUserAgent, userAgent)
+	}
+	return nil
+}
+
+// getConfiguredUserAgent returns the user agent set in the frogbot-config file. All the repositories must use the same user agent.
+func getConfiguredUserAgent(configAggregator FrogbotConfigAggregator) (userAgent string, err error) {
+	for _, repo := range configAggregator {
+		if repo.UserAgent == "" {
+			continue
+		}
+		if userAgent != "" && userAgent != repo.UserAgent {
+			return "", errors.New(errMultipleUserAgents)
+		}
+		userAgent = repo.UserAgent
+	}
+	return
+}
+
+// Return the repository of the default user agent. If the config file includes several repositories, only their owner is included.
+func getUserAgentRepository(configAggregator FrogbotConfigAggregator, git *Git) (repoOwner, repoName string) {
+	if len(configAggregator) == 1 {
+		return configAggregator[0].RepoOwner, configAggregator[0].RepoName
+	}
+	return git.RepoOwner, ""
+}
+
+// NewXrayServiceManager creates an Xray client like xraycommands.CreateXrayServiceManager, which also sends the custom headers
+func NewXrayServiceManager(server *coreconfig.ServerDetails) (*xray.XrayServicesManager, error) {
+	xrayDetails, err := server.CreateXrayAuthConfig()
+	if err != nil {
+		return nil, err
+	}
+	if headersTransport, ok := http.DefaultTransport.(*customHeadersTransport); ok {
+		xrayDetails.AppendPreRequestFunction(func(_ *auth.CommonConfigFields, httpClientDetails *httputils.HttpClientDetails) error {
+			if httpClientDetails.Headers == nil {
+				httpClientDetails.Headers = make(map[string]string)
+			}
+			for name, value := range headersTransport.headers {
+				if _, exists := httpClientDetails.Headers[name]; !exists {
+					httpClientDetails.Headers[name] = value
+				}
+			}
+			return nil
+		})
+	}
+	serviceConfig, err := clientconfig.NewConfigBuilder().SetServiceDetails(xrayDetails).Build()
+	if err != nil {
+		return nil, err
+	}
+	return xray.New(serviceConfig)
+}
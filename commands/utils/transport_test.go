@@ -0,0 +1,351 @@
+package utils
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
+	coreconfig "github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	clientutils "github.com/jfrog/jfrog-client-go/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigureProxy(t *testing.T) {
+	var proxiedRequests []string
+	proxyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Requests sent through a proxy contain the full target URL
+		proxiedRequests = append(proxiedRequests, r.URL.String())
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxyServer.Close()
+	defer restoreProxy(t)()
+
+	assert.NoError(t, ConfigureProxy(proxyServer.URL))
+	assert.Equal(t, proxyServer.URL, os.Getenv(httpsProxyEnv))
+
+	// Send a request using the default transport
+	response, err := http.Get("http://frogbot.test/resource")
+	assert.NoError(t, err)
+	assert.NoError(t, response.Body.Close())
+
+	// Send a request using a VCS client
+	client, err := vcsclient.NewClientBuilder(vcsutils.GitHub).ApiEndpoint("http://frogbot.test/api").Token("123456").Build()
+	assert.NoError(t, err)
+	assert.NoError(t, client.TestConnection(context.Background()))
+
+	assert.Len(t, proxiedRequests, 2)
+	assert.Equal(t, "http://frogbot.test/resource", proxiedRequests[0])
+	assert.Contains(t, proxiedRequests[1], "http://frogbot.test/api")
+}
+
+func TestConfigureProxyInvalidUrl(t *testing.T) {
+	defer restoreProxy(t)()
+	assert.EqualError(t, ConfigureProxy("proxy.example.com"), "the proxy URL 'proxy.example.com' is invalid. A URL such as http://proxy.example.com:8080 is expected")
+}
+
+func TestGetConfiguredProxy(t *testing.T) {
+	configAggregator := FrogbotConfigAggregator{{}, {Params: Params{Proxy: "http://proxy:8080"}}, {Params: Params{Proxy: "http://proxy:8080"}}}
+	proxy, err := getConfiguredProxy(configAggregator)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://proxy:8080", proxy)
+
+	configAggregator = append(configAggregator, FrogbotRepoConfig{Params: Params{Proxy: "http://other-proxy:8080"}})
+	_, err = getConfiguredProxy(configAggregator)
+	assert.EqualError(t, err, errMultipleProxies)
+}
+
+// Return a callback that restores the proxy environment variables and the default transport's proxy
+func restoreProxy(t *testing.T) func() {
+	httpProxy, httpsProxy := os.Getenv(httpProxyEnv), os.Getenv(httpsProxyEnv)
+	transport, _ := getDefaultTransport()
+	proxyFunc := transport.Proxy
+	return func() {
+		assert.NoError(t, os.Setenv(httpProxyEnv, httpProxy))
+		assert.NoError(t, os.Setenv(httpsProxyEnv, httpsProxy))
+		transport.Proxy = proxyFunc
+	}
+}
+
+func TestConfigureCaCert(t *testing.T) {
+	// The test server uses a self-signed certificate
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	defer restoreCaCert(t)()
+
+	_, err := http.Get(server.URL)
+	assert.Error(t, err)
+
+	caCertPath := filepath.Join(t.TempDir(), "ca.pem")
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	assert.NoError(t, os.WriteFile(caCertPath, caCert, 0600))
+	assert.NoError(t, ConfigureCaCert(caCertPath))
+
+	// Send a request using the default transport
+	response, err := http.Get(server.URL)
+	assert.NoError(t, err)
+	assert.NoError(t, response.Body.Close())
+
+	// Send a request using a VCS client
+	client, err := vcsclient.NewClientBuilder(vcsutils.GitHub).ApiEndpoint(server.URL).Token("123456").Build()
+	assert.NoError(t, err)
+	assert.NoError(t, client.TestConnection(context.Background()))
+}
+
+func TestConfigureCaCertInvalidFile(t *testing.T) {
+	defer restoreCaCert(t)()
+	assert.NoError(t, ConfigureCaCert(""))
+
+	caCertPath := filepath.Join(t.TempDir(), "ca.pem")
+	assert.Error(t, ConfigureCaCert(caCertPath))
+	assert.NoError(t, os.WriteFile(caCertPath, []byte("not a certificate"), 0600))
+	assert.EqualError(t, ConfigureCaCert(caCertPath), "the CA certificates file '"+caCertPath+"' doesn't include any PEM encoded certificate")
+}
+
+func TestGetConfiguredCaCert(t *testing.T) {
+	configAggregator := FrogbotConfigAggregator{{}, {Params: Params{CaCertPath: "ca.pem"}}}
+	caCertPath, err := getConfiguredCaCert(configAggregator)
+	assert.NoError(t, err)
+	assert.Equal(t, "ca.pem", caCertPath)
+
+	configAggregator = append(configAggregator, FrogbotRepoConfig{Params: Params{CaCertPath: "other-ca.pem"}})
+	_, err = getConfiguredCaCert(configAggregator)
+	assert.EqualError(t, err, errMultipleCaCerts)
+}
+
+// Return a callback that restores the SSL_CERT_FILE environment variable and the default transport's TLS config
+func restoreCaCert(t *testing.T) func() {
+	sslCertFile, sslCertFileExists := os.LookupEnv(sslCertFileEnv)
+	transport, _ := getDefaultTransport()
+	tlsConfig := transport.TLSClientConfig
+	return func() {
+		if sslCertFileExists {
+			assert.NoError(t, os.Setenv(sslCertFileEnv, sslCertFile))
+		} else {
+			assert.NoError(t, os.Unsetenv(sslCertFileEnv))
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+}
+
+func TestConfigureCustomHeaders(t *testing.T) {
+	var receivedHeaders []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedHeaders = append(receivedHeaders, r.Header.Clone())
+		if r.URL.Path == "/xray/api/v1/system/version" {
+			_, err := w.Write([]byte(`{"xray_version": "3.70.0", "xray_revision": "1"}`))
+			assert.NoError(t, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	defer restoreCustomHeaders()()
+
+	ConfigureClientsHosts(&Git{GitProvider: vcsutils.GitHub, ApiEndpoint: server.URL}, &coreconfig.ServerDetails{XrayUrl: server.URL + "/xray/"})
+	assert.NoError(t, ConfigureCustomHeaders(map[string]string{"X-Org-Id": "1234", "Authorization": "Bearer gateway"}))
+	// Configuring the headers again replaces the previous headers, instead of wrapping the transport twice
+	assert.NoError(t, ConfigureCustomHeaders(map[string]string{"X-Org-Id": "5678", "Authorization": "Bearer gateway"}))
+
+	// Send a request using a VCS client
+	client, err := vcsclient.NewClientBuilder(vcsutils.GitHub).ApiEndpoint(server.URL).Token("123456").Build()
+	assert.NoError(t, err)
+	assert.NoError(t, client.TestConnection(context.Background()))
+
+	// Send a request using an Xray client
+	xrayManager, err := NewXrayServiceManager(&coreconfig.ServerDetails{XrayUrl: server.URL + "/xray/"})
+	assert.NoError(t, err)
+	xrayVersion, err := xrayManager.GetVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, "3.70.0", xrayVersion)
+
+	// Send a request to another host, such as a package registry
+	response, err := http.Get(strings.Replace(server.URL, "127.0.0.1", "localhost", 1))
+	assert.NoError(t, err)
+	assert.NoError(t, response.Body.Close())
+
+	if assert.Len(t, receivedHeaders, 3) {
+		for _, headers := range receivedHeaders[:2] {
+			assert.Equal(t, "5678", headers.Get("X-Org-Id"))
+		}
+		// The headers set by the clients themselves aren't overridden
+		assert.Equal(t, "Bearer 123456", receivedHeaders[0].Get("Authorization"))
+		assert.Equal(t, "Bearer gateway", receivedHeaders[1].Get("Authorization"))
+		// The headers aren't sent to other hosts
+		assert.Empty(t, receivedHeaders[2].Get("X-Org-Id"))
+		assert.Empty(t, receivedHeaders[2].Get("Authorization"))
+	}
+
+	// The proxy and the CA certificates are still configured on the wrapped transport
+	_, ok := getDefaultTransport()
+	assert.True(t, ok)
+	assert.EqualError(t, ConfigureCustomHeaders(map[string]string{"X Org": "1234"}), "the custom header 'X Org' is invalid. A valid HTTP header name and value are expected")
+}
+
+func TestConfigureCustomHeadersFromEnv(t *testing.T) {
+	defer restoreCustomHeaders()()
+	t.Setenv(CustomHeadersEnv, "X-Org-Id=1234, X-Api-Key=secret=value")
+	assert.NoError(t, configureCustomHeadersFromEnv())
+	headersTransport, ok := http.DefaultTransport.(*customHeadersTransport)
+	if assert.True(t, ok) {
+		assert.Equal(t, map[string]string{"X-Org-Id": "1234", "X-Api-Key": "secret=value"}, headersTransport.headers)
+	}
+
+	// The values of the sensitive headers aren't included in the error
+	t.Setenv(CustomHeadersEnv, "X-Api-Key=secret,X-Org-Id")
+	assert.EqualError(t, configureCustomHeadersFromEnv(), fmt.Sprintf(errInvalidCustomHeadersEnv, CustomHeadersEnv, "X-Api-Key=***,X-Org-Id"))
+}
+
+func TestConfigureClientsHosts(t *testing.T) {
+	defer restoreCustomHeaders()()
+	ConfigureClientsHosts(&Git{GitProvider: vcsutils.GitHub}, &coreconfig.ServerDetails{Url: "https://acme.jfrog.io/", XrayUrl: "https://acme.jfrog.io/xray/", ArtifactoryUrl: "https://artifactory.acme.com:8082/artifactory/"})
+	headersTransport := getCustomHeadersTransport()
+	assert.Equal(t, []string{"api.github.com", "acme.jfrog.io", "acme.jfrog.io", "artifactory.acme.com"}, headersTransport.hosts)
+
+	ConfigureClientsHosts(&Git{GitProvider: vcsutils.BitbucketServer, ApiEndpoint: "https://Bitbucket.acme.com/rest"}, nil)
+	headersTransport = getCustomHeadersTransport()
+	assert.Equal(t, []string{"Bitbucket.acme.com"}, headersTransport.hosts)
+	assert.True(t, headersTransport.isClientHost(&url.URL{Scheme: "https", Host: "bitbucket.acme.com:443"}))
+	assert.False(t, headersTransport.isClientHost(&url.URL{Scheme: "https", Host: "registry.npmjs.org"}))
+
+	// The hosts of a Bitbucket Server without an API endpoint are unknown
+	ConfigureClientsHosts(&Git{GitProvider: vcsutils.BitbucketServer}, nil)
+	assert.Empty(t, getCustomHeadersTransport().hosts)
+}
+
+func TestConfigureLocalConfigTransport(t *testing.T) {
+	defer restoreCustomHeaders()()
+	defer restoreCaCert(t)()
+	defer SetConfigPaths(nil)
+	configDir := t.TempDir()
+	configPath := filepath.Join(configDir, "frogbot-config.yml")
+	assert.NoError(t, os.WriteFile(configPath, []byte("- defaults:\n    customHeaders:\n      X-Org-Id: \"1234\"\n- params:\n    git:\n      repoName: frogbot\n"), 0600))
+	SetConfigPaths([]string{configPath})
+	assert.NoError(t, configureLocalConfigTransport())
+	assert.Equal(t, map[string]string{"X-Org-Id": "1234"}, getCustomHeadersTransport().headers)
+
+	// The CA certificates are configured too
+	caCertPath := filepath.Join(configDir, "ca.pem")
+	assert.NoError(t, os.WriteFile(caCertPath, []byte("not a certificate"), 0600))
+	assert.NoError(t, os.WriteFile(configPath, []byte("- params:\n    git:\n      repoName: frogbot\n    caCertPath: "+caCertPath+"\n"), 0600))
+	assert.EqualError(t, configureLocalConfigTransport(), fmt.Sprintf(errInvalidCaCert, caCertPath))
+
+	// A missing file is reported when the config is loaded
+	SetConfigPaths([]string{filepath.Join(t.TempDir(), "frogbot-config.yml")})
+	assert.NoError(t, configureLocalConfigTransport())
+}
+
+func TestFormatCustomHeaders(t *testing.T) {
+	headers := map[string]string{"X-Org-Id": "1234", "Authorization": "Basic dXNlcjpwYXNz", "X-Gateway-Token": "abc", "Cookie": "session=1"}
+	assert.Equal(t, "Authorization: ***, Cookie: ***, X-Gateway-Token: ***, X-Org-Id: 1234", formatCustomHeaders(headers))
+}
+
+func TestGetConfiguredCustomHeaders(t *testing.T) {
+	configAggregator := FrogbotConfigAggregator{{}, {Params: Params{CustomHeaders: map[string]string{"X-Org-Id": "1234"}}}, {Params: Params{CustomHeaders: map[string]string{"X-Org-Id": "1234"}}}}
+	headers, err := getConfiguredCustomHeaders(configAggregator)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"X-Org-Id": "1234"}, headers)
+
+	configAggregator = append(configAggregator, FrogbotRepoConfig{Params: Params{CustomHeaders: map[string]string{"X-Org-Id": "5678"}}})
+	_, err = getConfiguredCustomHeaders(configAggregator)
+	assert.EqualError(t, err, errMultipleCustomHeaders)
+}
+
+func TestConfigureUserAgent(t *testing.T) {
+	var receivedUserAgents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedUserAgents = append(receivedUserAgents, r.Header.Get("User-Agent"))
+		if r.URL.Path == "/xray/api/v1/system/version" {
+			_, err := w.Write([]byte(`{"xray_version": "3.70.0", "xray_revision": "1"}`))
+			assert.NoError(t, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	defer restoreCustomHeaders()()
+	defer restoreUserAgent()()
+	sendRequests := func() {
+		receivedUserAgents = nil
+		client, err := vcsclient.NewClientBuilder(vcsutils.GitHub).ApiEndpoint(server.URL).Token("123456").Build()
+		assert.NoError(t, err)
+		assert.NoError(t, client.TestConnection(context.Background()))
+		xrayManager, err := NewXrayServiceManager(&coreconfig.ServerDetails{XrayUrl: server.URL + "/xray/"})
+		assert.NoError(t, err)
+		_, err = xrayManager.GetVersion()
+		assert.NoError(t, err)
+	}
+
+	// The default user agent includes the Frogbot version and the repository
+	assert.NoError(t, ConfigureUserAgent("", "jfrog", "frogbot"))
+	ConfigureClientsHosts(&Git{GitProvider: vcsutils.GitHub, ApiEndpoint: server.URL}, nil)
+	sendRequests()
+	assert.Equal(t, []string{"frogbot/" + FrogbotVersion + " (jfrog/frogbot)", "frogbot/" + FrogbotVersion + " (jfrog/frogbot)"}, receivedUserAgents)
+
+	// The user agent isn't sent to other hosts, such as package registries
+	receivedUserAgents = nil
+	otherHostUrl := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	response, err := http.Get(otherHostUrl)
+	assert.NoError(t, err)
+	assert.NoError(t, response.Body.Close())
+	if assert.Len(t, receivedUserAgents, 1) {
+		assert.NotContains(t, receivedUserAgents[0], "frogbot")
+	}
+
+	// The custom headers are kept when the user agent is configured, and vice versa
+	assert.NoError(t, ConfigureCustomHeaders(map[string]string{"X-Org-Id": "1234"}))
+	assert.NoError(t, ConfigureUserAgent("acme-frogbot", "jfrog", "frogbot"))
+	headersTransport, ok := http.DefaultTransport.(*customHeadersTransport)
+	if assert.True(t, ok) {
+		assert.Equal(t, map[string]string{"X-Org-Id": "1234"}, headersTransport.headers)
+	}
+	sendRequests()
+	assert.Equal(t, []string{"acme-frogbot", "acme-frogbot"}, receivedUserAgents)
+	_, ok = getDefaultTransport()
+	assert.True(t, ok)
+
+	assert.EqualError(t, ConfigureUserAgent("acme\nfrogbot", "", ""), fmt.Sprintf(errInvalidUserAgent, "acme\nfrogbot"))
+}
+
+func TestGetDefaultUserAgent(t *testing.T) {
+	assert.Equal(t, "frogbot/"+FrogbotVersion+" (jfrog/frogbot)", getDefaultUserAgent("jfrog", "frogbot"))
+	assert.Equal(t, "frogbot/"+FrogbotVersion+" (jfrog)", getDefaultUserAgent("jfrog", ""))
+	assert.Equal(t, "frogbot/"+FrogbotVersion, getDefaultUserAgent("", ""))
+}
+
+func TestGetConfiguredUserAgent(t *testing.T) {
+	configAggregator := FrogbotConfigAggregator{{}, {Params: Params{UserAgent: "acme-frogbot"}}}
+	userAgent, err := getConfiguredUserAgent(configAggregator)
+	assert.NoError(t, err)
+	assert.Equal(t, "acme-frogbot", userAgent)
+
+	configAggregator = append(configAggregator, FrogbotRepoConfig{Params: Params{UserAgent: "other-frogbot"}})
+	_, err = getConfiguredUserAgent(configAggregator)
+	assert.EqualError(t, err, errMultipleUserAgents)
+}
+
+// Return a callback that restores the user agent of the Xray clients
+func restoreUserAgent() func() {
+	userAgent := clientutils.GetUserAgent()
+	return func() {
+		clientutils.SetUserAgent(userAgent)
+	}
+}
+
+// Return a callback that restores the default transport, without the custom headers
+func restoreCustomHeaders() func() {
+	defaultTransport := http.DefaultTransport
+	return func() {
+		http.DefaultTransport = defaultTransport
+	}
+}
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"archive/tar"
+	"compress/gzip"
 	"context"
 	"crypto"
 	"encoding/hex"
@@ -13,11 +15,13 @@ import (
 	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
 	xrayutils "github.com/jfrog/jfrog-cli-core/v2/xray/utils"
 	"github.com/jfrog/jfrog-client-go/artifactory/usage"
-	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/jfrog/jfrog-client-go/xray/services"
+	"io"
 	"os"
 	"strings"
+	"sync"
+	"time"
 )
 
 const RootDir = "."
@@ -45,6 +49,10 @@ type OutputWriter interface {
 	NoVulnerabilitiesTitle() string
 	VulnerabiltiesTitle() string
 	TableHeader() string
+	IacTableRow(iacRow IacRow) string
+	IacTableHeader() string
+	CollapsibleSection(summary, content string) string
+	SeverityLegend() string
 	IsFrogbotResultComment(comment string) bool
 }
 
@@ -59,6 +67,25 @@ func Chdir(dir string) (cbk func() error, err error) {
 	return func() error { return os.Chdir(wd) }, err
 }
 
+// The working directory is shared by all the goroutines of the process.
+// When scanning repositories in parallel, only one repository at a time may change it.
+var workingDirMutex sync.Mutex
+
+// ChdirExclusively changes the working directory like Chdir, and holds the working directory until the callback is called.
+// Use it to change to the directory of a repository, which may be scanned in parallel to other repositories.
+func ChdirExclusively(dir string) (cbk func() error, err error) {
+	workingDirMutex.Lock()
+	restoreDir, err := Chdir(dir)
+	if err != nil {
+		workingDirMutex.Unlock()
+		return nil, err
+	}
+	return func() error {
+		defer workingDirMutex.Unlock()
+		return restoreDir()
+	}, nil
+}
+
 func ReportUsage(commandName string, serverDetails *config.ServerDetails, usageReportSent chan<- error) {
 	var err error
 	defer func() {
@@ -114,22 +141,51 @@ func UploadScanToGitProvider(scanResults []services.ScanResponse, repo *FrogbotR
 }
 
 func DownloadRepoToTempDir(client vcsclient.VcsClient, branch string, git *Git) (wd string, cleanup func() error, err error) {
-	wd, err = fileutils.CreateTempDir()
+	wd, err = CreateTempDir()
 	if err != nil {
 		return
 	}
 	cleanup = func() error {
-		return fileutils.RemoveTempDir(wd)
+		return RemoveTempDir(wd)
 	}
 	log.Debug("Created temp working directory: ", wd)
 	log.Debug(fmt.Sprintf("Downloading %s/%s , branch: %s to: %s", git.RepoOwner, git.RepoName, branch, wd))
-	if err = client.DownloadRepository(context.Background(), git.RepoOwner, git.RepoName, branch, wd); err != nil {
+	if err = downloadRepository(client, branch, git, wd); err != nil {
+		// The callers don't clean up on error
+		if e := cleanup(); e != nil {
+			log.Warn(e)
+		}
 		return
 	}
 	log.Debug("Repository download completed")
 	return
 }
 
+// The git clients extract the archive while downloading it, so an archive which isn't a valid tar.gz archive, such as an empty body or an HTML page,
+// fails the extraction with a gzip or tar error
+func isInvalidArchiveError(err error) bool {
+	if errors.Is(err, gzip.ErrHeader) || errors.Is(err, gzip.ErrChecksum) || errors.Is(err, tar.ErrHeader) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	// Some git clients wrap the errors of the extraction as strings
+	errMessage := err.Error()
+	return strings.HasPrefix(errMessage, "gzip: ") || strings.HasPrefix(errMessage, "archive/tar: ") || errMessage == io.EOF.Error() || errMessage == io.ErrUnexpectedEOF.Error()
+}
+
+// Return an error if the extracted archive holds no files, except for the .git dir created by the git client
+func validateDownloadedRepo(wd, branch string, git *Git) error {
+	entries, err := os.ReadDir(wd)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Name() != ".git" {
+			return nil
+		}
+	}
+	return fmt.Errorf(errEmptyRepoArchive, git.RepoOwner, git.RepoName, branch)
+}
+
 func ValidateSingleRepoConfiguration(configAggregator *FrogbotConfigAggregator) error {
 	// Multi repository configuration is supported only in the scanpullrequests and scanandfixrepos commands.
 	if len(*configAggregator) > 1 {
@@ -138,6 +194,96 @@ func ValidateSingleRepoConfiguration(configAggregator *FrogbotConfigAggregator)
 	return nil
 }
 
+// RunOnRepositories runs runFunc on all the repositories in the config aggregator.
+// Up to maxRepoWorkers repositories are handled at the same time, each with its own VCS client, while their audits are serialized by ChdirExclusively.
+// By default, the repositories are scanned one after the other, using the given client.
+// A repository failure is logged and the run continues to the next repository, unless continueOnError is set to false for the failed repository.
+// The returned error includes the errors of all the failed repositories.
+func RunOnRepositories(configAggregator FrogbotConfigAggregator, client vcsclient.VcsClient, runFunc func(repoConfig *FrogbotRepoConfig, client vcsclient.VcsClient) error) error {
+	workers := configAggregator.getMaxRepoWorkers()
+	if workers > 1 {
+		log.Info(fmt.Sprintf("Scanning %d repositories with up to %d workers. The downloads and the Git provider requests of the repositories run in parallel, while their audits run one at a time", len(configAggregator), workers))
+	}
+	startTime := time.Now()
+	repoErrors := make([]error, len(configAggregator))
+	scannedRepos := make([]bool, len(configAggregator))
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	nextRepo, stopped := 0, false
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				mutex.Lock()
+				if stopped || nextRepo == len(configAggregator) {
+					mutex.Unlock()
+					return
+				}
+				repoIndex := nextRepo
+				nextRepo++
+				scannedRepos[repoIndex] = true
+				mutex.Unlock()
+
+				repoConfig := &configAggregator[repoIndex]
+				err := runOnRepository(repoConfig, client, workers > 1, runFunc)
+				if err == nil {
+					continue
+				}
+				repoErrors[repoIndex] = err
+				if !repoConfig.ShouldContinueOnError() {
+					log.Error("Repository", repoConfig.RepoName, "failed, and continueOnError is set to false. Skipping the remaining repositories")
+					mutex.Lock()
+					stopped = true
+					mutex.Unlock()
+					continue
+				}
+				log.Error("Repository", repoConfig.RepoName, "failed. Continuing to the next repository:", err.Error())
+			}
+		}()
+	}
+	wg.Wait()
+	return aggregateRepositoriesErrors(configAggregator, repoErrors, scannedRepos, time.Since(startTime))
+}
+
+func runOnRepository(repoConfig *FrogbotRepoConfig, client vcsclient.VcsClient, isolatedClient bool,
+	runFunc func(repoConfig *FrogbotRepoConfig, client vcsclient.VcsClient) error) (err error) {
+	// The installation access token of the GitHub App used by the shared client may expire while the repositories are scanned
+	if isolatedClient || repoConfig.GitHubApp != nil {
+		if client, err = NewVcsClient(&repoConfig.Git); err != nil {
+			return err
+		}
+	}
+	return runFunc(repoConfig, client)
+}
+
+// Log a report of all the repositories, and return the errors of the failed repositories in the order of the config aggregator
+func aggregateRepositoriesErrors(configAggregator FrogbotConfigAggregator, repoErrors []error, scannedRepos []bool, duration time.Duration) error {
+	var errList strings.Builder
+	var failedRepos []string
+	skippedReposCount := 0
+	for repoIndex, err := range repoErrors {
+		if !scannedRepos[repoIndex] {
+			skippedReposCount++
+			continue
+		}
+		if err != nil {
+			failedRepos = append(failedRepos, configAggregator[repoIndex].RepoName)
+			errList.WriteString(fmt.Sprintf(errRepositoryFailed, configAggregator[repoIndex].RepoName, err.Error()))
+		}
+	}
+	if len(configAggregator) > 1 {
+		scannedReposCount := len(configAggregator) - skippedReposCount
+		log.Info(fmt.Sprintf("Scanned %d repositories in %s: %d succeeded, %d failed, %d skipped",
+			scannedReposCount, duration.Round(time.Second), scannedReposCount-len(failedRepos), len(failedRepos), skippedReposCount))
+	}
+	if len(failedRepos) == 0 {
+		return nil
+	}
+	log.Error(fmt.Sprintf("Frogbot failed on %d out of %d repositories: %s", len(failedRepos), len(configAggregator), strings.Join(failedRepos, ", ")))
+	return errors.New(errList.String())
+}
+
 // GetRelativeWd receive a base working directory along with a full path containing the base working directory, and the relative part is returned without the base prefix.
 func GetRelativeWd(fullPathWd, baseWd string) string {
 	fullPathWd = strings.TrimSuffix(fullPathWd, string(os.PathSeparator))
@@ -148,9 +294,86 @@ func GetRelativeWd(fullPathWd, baseWd string) string {
 	return strings.TrimPrefix(fullPathWd, baseWd+string(os.PathSeparator))
 }
 
-func GetCompatibleOutputWriter(provider vcsutils.VcsProvider) OutputWriter {
+// TruncatePullRequestTitle cuts the title to the maximum pull request title length allowed by the git provider
+func TruncatePullRequestTitle(title string, provider vcsutils.VcsProvider) string {
+	maxLength := gitHubPullRequestTitleMaxLength
+	switch provider {
+	case vcsutils.GitLab:
+		maxLength = gitLabPullRequestTitleMaxLength
+	case vcsutils.BitbucketServer:
+		maxLength = bitbucketServerPullRequestTitleMaxLength
+	case vcsutils.AzureRepos:
+		maxLength = azureReposPullRequestTitleMaxLength
+	}
+	titleRunes := []rune(title)
+	if len(titleRunes) <= maxLength {
+		return title
+	}
+	return string(titleRunes[:maxLength-len(truncationSuffix)]) + truncationSuffix
+}
+
+// GetMaxCommentLength returns the maximum pull request comment length allowed by the git provider, unless overridden by maxCommentLength
+func GetMaxCommentLength(provider vcsutils.VcsProvider, maxCommentLength int) int {
+	if maxCommentLength > 0 {
+		return maxCommentLength
+	}
+	switch provider {
+	case vcsutils.GitLab:
+		return gitLabCommentMaxLength
+	case vcsutils.BitbucketServer:
+		return bitbucketServerCommentMaxLength
+	case vcsutils.AzureRepos:
+		return azureReposCommentMaxLength
+	}
+	return gitHubCommentMaxLength
+}
+
+// The comments must fit the truncation note and the trailing markers, so a maxCommentLength below the minimal length is rejected
+func (p *Params) validateMaxCommentLength() error {
+	if p.MaxCommentLength < 0 || (p.MaxCommentLength > 0 && p.MaxCommentLength < minCommentLength) {
+		return fmt.Errorf(errInvalidMaxCommentLength, p.MaxCommentLength, minCommentLength)
+	}
+	return nil
+}
+
+// TruncateComment cuts the comment to maxLength characters, including a note about the truncation.
+// The comment is cut at the end of a line, to avoid breaking a table row. The hidden issues marker at the end of the comment is kept.
+func TruncateComment(comment string, maxLength int) string {
+	if len([]rune(comment)) <= maxLength {
+		return comment
+	}
+	var marker string
+	if location := trailingMarkersRegex.FindStringIndex(comment); location != nil {
+		comment, marker = comment[:location[0]], "\n\n"+strings.TrimLeft(comment[location[0]:], "\n")
+	}
+	note := fmt.Sprintf(truncatedCommentNote, maxLength)
+	bodyLength := maxLength - len([]rune(note)) - len([]rune(marker))
+	if bodyLength <= 0 {
+		return truncateRunes(comment+marker, maxLength)
+	}
+	body := truncateRunes(comment, bodyLength)
+	if lastNewline := strings.LastIndex(body, "\n"); lastNewline > 0 {
+		body = body[:lastNewline]
+	}
+	return body + note + marker
+}
+
+// Return the first length characters of the text, or the whole text if it's shorter
+func truncateRunes(text string, length int) string {
+	runes := []rune(text)
+	if length < 0 {
+		length = 0
+	}
+	if length > len(runes) {
+		length = len(runes)
+	}
+	return string(runes[:length])
+}
+
+// The simplified output shows the severities as text, so the severity colors apply to the standard output only
+func GetCompatibleOutputWriter(provider vcsutils.VcsProvider, severityColors map[string]string, maxFixedVersions int, compactTable bool) OutputWriter {
 	if provider == vcsutils.BitbucketServer {
-		return &SimplifiedOutput{}
+		return &SimplifiedOutput{MaxFixedVersions: maxFixedVersions, CompactTable: compactTable}
 	}
-	return &StandardOutput{}
+	return &StandardOutput{SeverityColors: severityColors, MaxFixedVersions: maxFixedVersions, CompactTable: compactTable}
 }
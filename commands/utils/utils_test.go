@@ -1,14 +1,25 @@
 package utils
 
 import (
+	"archive/tar"
 	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 
+	"github.com/golang/mock/gomock"
+	"github.com/jfrog/frogbot/commands/testdata"
+	"github.com/jfrog/froggit-go/vcsclient"
+	"github.com/jfrog/froggit-go/vcsutils"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/config"
 	"github.com/stretchr/testify/assert"
 )
@@ -136,3 +147,202 @@ func TestGetRelativeWd(t *testing.T) {
 	fullPath += string(os.PathSeparator)
 	assert.Equal(t, "", GetRelativeWd(fullPath, baseWd))
 }
+
+func TestTruncatePullRequestTitle(t *testing.T) {
+	assert.Equal(t, "short title", TruncatePullRequestTitle("short title", vcsutils.GitHub))
+	longTitle := strings.Repeat("🐸", 300)
+	assert.Len(t, []rune(TruncatePullRequestTitle(longTitle, vcsutils.GitHub)), 256)
+	assert.Len(t, []rune(TruncatePullRequestTitle(longTitle, vcsutils.GitLab)), 255)
+	assert.Len(t, []rune(TruncatePullRequestTitle(longTitle, vcsutils.BitbucketServer)), 255)
+	assert.Equal(t, longTitle, TruncatePullRequestTitle(longTitle, vcsutils.AzureRepos))
+	assert.True(t, strings.HasSuffix(TruncatePullRequestTitle(longTitle, vcsutils.GitHub), "..."))
+}
+
+func TestGetMaxCommentLength(t *testing.T) {
+	assert.Equal(t, 65536, GetMaxCommentLength(vcsutils.GitHub, 0))
+	assert.Equal(t, 1000000, GetMaxCommentLength(vcsutils.GitLab, 0))
+	assert.Equal(t, 32768, GetMaxCommentLength(vcsutils.BitbucketServer, 0))
+	assert.Equal(t, 150000, GetMaxCommentLength(vcsutils.AzureRepos, 0))
+	assert.Equal(t, 1000, GetMaxCommentLength(vcsutils.GitHub, 1000))
+}
+
+func TestValidateMaxCommentLength(t *testing.T) {
+	for _, maxCommentLength := range []int{0, minCommentLength, 32000} {
+		params := Params{MaxCommentLength: maxCommentLength}
+		assert.NoError(t, params.validateMaxCommentLength())
+	}
+	for _, maxCommentLength := range []int{-1, 1, minCommentLength - 1} {
+		params := Params{MaxCommentLength: maxCommentLength}
+		assert.EqualError(t, params.validateMaxCommentLength(), fmt.Sprintf(errInvalidMaxCommentLength, maxCommentLength, minCommentLength))
+	}
+}
+
+func TestTruncateComment(t *testing.T) {
+	row := "\n| 🐸 | row |"
+	comment := "title" + strings.Repeat(row, 100)
+	commentLength := len([]rune(comment))
+
+	// Comments at the limit aren't truncated
+	assert.Equal(t, comment, TruncateComment(comment, commentLength))
+
+	// Longer comments are cut at the end of a line, and the note is added
+	note := fmt.Sprintf(truncatedCommentNote, commentLength-1)
+	truncated := TruncateComment(comment, commentLength-1)
+	assert.LessOrEqual(t, len([]rune(truncated)), commentLength-1)
+	assert.True(t, strings.HasSuffix(truncated, "| row |"+note))
+
+	// The issues marker is kept
+	marker := GetIssuesMarker("abc123", "def456")
+	truncated = TruncateComment(comment+marker, 500)
+	assert.LessOrEqual(t, len([]rune(truncated)), 500)
+	assert.True(t, strings.HasSuffix(truncated, fmt.Sprintf(truncatedCommentNote, 500)+marker))
+	hash, sha, found := ParseIssuesMarker(truncated)
+	assert.True(t, found)
+	assert.Equal(t, "abc123", hash)
+	assert.Equal(t, "def456", sha)
+
+	// All the trailing markers are kept
+	truncated = TruncateComment(comment+marker+GetThreadMarker([]string{"0a1b2c3d"}), 500)
+	assert.LessOrEqual(t, len([]rune(truncated)), 500)
+	_, _, found = ParseIssuesMarker(truncated)
+	assert.True(t, found)
+	issueKeys, found := ParseThreadMarker(truncated)
+	assert.True(t, found)
+	assert.Equal(t, []string{"0a1b2c3d"}, issueKeys)
+
+	// Limits which don't fit the note and the markers cut the comment, and don't panic
+	for _, maxLength := range []int{0, 1, 10, 100} {
+		truncated = TruncateComment(comment+marker, maxLength)
+		assert.Len(t, []rune(truncated), maxLength)
+	}
+	assert.Empty(t, TruncateComment(comment, -1))
+}
+
+func TestRunOnRepositories(t *testing.T) {
+	stopOnError := false
+	configAggregator := FrogbotConfigAggregator{
+		{Params: Params{Git: Git{RepoName: "repo-1"}}},
+		{Params: Params{Git: Git{RepoName: "repo-2"}}},
+		{Params: Params{Git: Git{RepoName: "repo-3"}, ContinueOnError: &stopOnError}},
+		{Params: Params{Git: Git{RepoName: "repo-4"}}},
+	}
+	var scannedRepos []string
+	failingRepos := map[string]bool{}
+	runFunc := func(repoConfig *FrogbotRepoConfig, _ vcsclient.VcsClient) error {
+		scannedRepos = append(scannedRepos, repoConfig.RepoName)
+		if failingRepos[repoConfig.RepoName] {
+			return errors.New("scan failed")
+		}
+		return nil
+	}
+
+	// No failures
+	assert.NoError(t, RunOnRepositories(configAggregator, nil, runFunc))
+	assert.Equal(t, []string{"repo-1", "repo-2", "repo-3", "repo-4"}, scannedRepos)
+
+	// A failure in a repository doesn't stop the run by default
+	scannedRepos = nil
+	failingRepos = map[string]bool{"repo-1": true, "repo-2": true}
+	err := RunOnRepositories(configAggregator, nil, runFunc)
+	assert.EqualError(t, err, fmt.Sprintf(errRepositoryFailed, "repo-1", "scan failed")+fmt.Sprintf(errRepositoryFailed, "repo-2", "scan failed"))
+	assert.Equal(t, []string{"repo-1", "repo-2", "repo-3", "repo-4"}, scannedRepos)
+
+	// A failure in a repository which doesn't continue on error stops the run
+	scannedRepos = nil
+	failingRepos = map[string]bool{"repo-3": true}
+	err = RunOnRepositories(configAggregator, nil, runFunc)
+	assert.EqualError(t, err, fmt.Sprintf(errRepositoryFailed, "repo-3", "scan failed"))
+	assert.Equal(t, []string{"repo-1", "repo-2", "repo-3"}, scannedRepos)
+}
+
+func TestRunOnRepositoriesInParallel(t *testing.T) {
+	var configAggregator FrogbotConfigAggregator
+	for i := 1; i <= 6; i++ {
+		git := Git{GitProvider: vcsutils.GitHub, Token: "123456", RepoName: fmt.Sprintf("repo-%d", i)}
+		configAggregator = append(configAggregator, FrogbotRepoConfig{Params: Params{Git: git, MaxRepoWorkers: 3}})
+	}
+	var mutex sync.Mutex
+	var scannedRepos []string
+	clients := map[vcsclient.VcsClient]bool{}
+	running, maxRunning := 0, 0
+	runFunc := func(repoConfig *FrogbotRepoConfig, client vcsclient.VcsClient) error {
+		mutex.Lock()
+		scannedRepos = append(scannedRepos, repoConfig.RepoName)
+		clients[client] = true
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mutex.Unlock()
+		// Hold the working directory, like the scan of a downloaded repository
+		restoreDir, err := ChdirExclusively(t.TempDir())
+		assert.NoError(t, err)
+		assert.NoError(t, restoreDir())
+		mutex.Lock()
+		running--
+		mutex.Unlock()
+		if repoConfig.RepoName == "repo-2" || repoConfig.RepoName == "repo-5" {
+			return errors.New("scan failed")
+		}
+		return nil
+	}
+
+	err := RunOnRepositories(configAggregator, nil, runFunc)
+	// The errors are reported in the order of the repositories
+	assert.EqualError(t, err, fmt.Sprintf(errRepositoryFailed, "repo-2", "scan failed")+fmt.Sprintf(errRepositoryFailed, "repo-5", "scan failed"))
+	sort.Strings(scannedRepos)
+	assert.Equal(t, []string{"repo-1", "repo-2", "repo-3", "repo-4", "repo-5", "repo-6"}, scannedRepos)
+	assert.LessOrEqual(t, maxRunning, 3)
+	// Each repository gets its own client
+	assert.Len(t, clients, 6)
+}
+
+func TestGetMaxRepoWorkers(t *testing.T) {
+	assert.Equal(t, 1, FrogbotConfigAggregator{}.getMaxRepoWorkers())
+	assert.Equal(t, 1, FrogbotConfigAggregator{{Params: Params{MaxRepoWorkers: -1}}}.getMaxRepoWorkers())
+	assert.Equal(t, 4, FrogbotConfigAggregator{{Params: Params{MaxRepoWorkers: 4}}}.getMaxRepoWorkers())
+}
+
+// Create a mock client which extracts the body as the repository archive, like the git clients do while downloading it
+func mockDownloadRepository(t *testing.T, body []byte) *testdata.MockVcsClient {
+	client := testdata.NewMockVcsClient(gomock.NewController(t))
+	client.EXPECT().DownloadRepository(context.Background(), "jfrog", "frogbot", "main", gomock.Any()).DoAndReturn(
+		func(_ context.Context, _, _, _, localPath string) error {
+			return vcsutils.Untar(localPath, bytes.NewReader(body), true)
+		})
+	return client
+}
+
+func createTestArchive(t *testing.T, files map[string]string) []byte {
+	var archive bytes.Buffer
+	gzipWriter := gzip.NewWriter(&archive)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range files {
+		assert.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "frogbot-main/" + name, Mode: 0600, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tarWriter.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tarWriter.Close())
+	assert.NoError(t, gzipWriter.Close())
+	return archive.Bytes()
+}
+
+func TestDownloadRepoToTempDirInvalidArchive(t *testing.T) {
+	git := &Git{RepoOwner: "jfrog", RepoName: "frogbot"}
+	// An HTML page returned due to an authentication error
+	_, _, err := DownloadRepoToTempDir(mockDownloadRepository(t, []byte("<html><body>Sign in to continue</body></html>")), "main", git)
+	assert.EqualError(t, err, fmt.Sprintf(errInvalidRepoArchive, "jfrog", "frogbot", "main", "gzip: invalid header"))
+
+	// An empty body
+	_, _, err = DownloadRepoToTempDir(mockDownloadRepository(t, nil), "main", git)
+	assert.EqualError(t, err, fmt.Sprintf(errInvalidRepoArchive, "jfrog", "frogbot", "main", "EOF"))
+
+	// A valid archive with no files
+	_, _, err = DownloadRepoToTempDir(mockDownloadRepository(t, createTestArchive(t, nil)), "main", git)
+	assert.EqualError(t, err, fmt.Sprintf(errEmptyRepoArchive, "jfrog", "frogbot", "main"))
+
+	wd, cleanup, err := DownloadRepoToTempDir(mockDownloadRepository(t, createTestArchive(t, map[string]string{"go.mod": "module github.com/jfrog/frogbot\n"})), "main", git)
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(wd, "go.mod"))
+	assert.NoError(t, cleanup())
+}
@@ -0,0 +1,289 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	coreconfig "github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Matches the line number prefix of the yaml errors, such as "line 5: field foo not found in type utils.Params"
+var yamlErrorLineRegex = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// ConfigValidationError is a single error found in the frogbot-config file
+type ConfigValidationError struct {
+	// The path of the invalid file, if more than one file is validated
+	File string
+	// The line of the invalid value in the file, or 0 if the line is unknown
+	Line    int
+	Message string
+}
+
+func (e ConfigValidationError) String() string {
+	message := e.Message
+	if e.Line != 0 {
+		message = fmt.Sprintf("line %d: %s", e.Line, message)
+	}
+	if e.File != "" {
+		message = fmt.Sprintf("%s: %s", e.File, message)
+	}
+	return message
+}
+
+// An error of a single parameter, located by its path in the yaml document
+type paramError struct {
+	path []any
+	err  error
+}
+
+// ValidateConfigFile runs the parsing and the validation Frogbot runs on the frogbot-config files before a scan, and returns all the errors found.
+// configPaths - The paths of the frogbot-config files, directories or glob patterns, as accepted by the --config flag. If empty, .frogbot/frogbot-config.yml is used.
+// Each file is validated separately, and the merged config is validated to include a single defaults entry.
+func ValidateConfigFile(configPaths ...string) ([]ConfigValidationError, error) {
+	if len(configPaths) == 0 {
+		configPaths = []string{osFrogbotConfigPath}
+	}
+	configFiles, err := expandConfigPaths(configPaths)
+	if err != nil {
+		return nil, err
+	}
+	var validationErrors []ConfigValidationError
+	defaultsFiles := 0
+	for _, configFile := range configFiles {
+		content, err := os.ReadFile(configFile)
+		if err != nil {
+			return nil, err
+		}
+		fileErrors := validateConfigContent(content)
+		if len(configFiles) > 1 {
+			for i := range fileErrors {
+				fileErrors[i].File = configFile
+			}
+		}
+		validationErrors = append(validationErrors, fileErrors...)
+		if hasDefaultsEntry(content) {
+			defaultsFiles++
+		}
+	}
+	if defaultsFiles > 1 {
+		validationErrors = append(validationErrors, ConfigValidationError{Message: errMultipleDefaultsFiles})
+	}
+	return validationErrors, nil
+}
+
+func hasDefaultsEntry(content []byte) bool {
+	var configData FrogbotConfigAggregator
+	if err := yaml.Unmarshal(content, &configData); err != nil {
+		return false
+	}
+	for _, entry := range configData {
+		if entry.Defaults != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func validateConfigContent(content []byte) []ConfigValidationError {
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return yamlErrorToValidationErrors(err)
+	}
+	var configData FrogbotConfigAggregator
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&configData); err != nil {
+		if errors.Is(err, io.EOF) {
+			return []ConfigValidationError{{Message: errEmptyConfig}}
+		}
+		return yamlErrorToValidationErrors(err)
+	}
+
+	var validationErrors []ConfigValidationError
+	addErrors := func(entryIndex int, section string, paramErrors []paramError) {
+		for _, paramError := range paramErrors {
+			path := append([]any{entryIndex, section}, paramError.path...)
+			validationErrors = append(validationErrors, ConfigValidationError{Line: findLine(&document, path), Message: paramError.err.Error()})
+		}
+	}
+	defaultsFound := false
+	for index := range configData {
+		entry := &configData[index]
+		if entry.Defaults != nil {
+			if defaultsFound {
+				addErrors(index, "defaults", []paramError{{err: errors.New(errMultipleDefaults)}})
+			}
+			defaultsFound = true
+			addErrors(index, "defaults", entry.Defaults.validate())
+			continue
+		}
+		paramErrors := entry.validate()
+		if entry.RepoName == "" {
+			paramErrors = append(paramErrors, paramError{path: []any{"git"}, err: errors.New(errMissingRepoName)})
+		}
+		addErrors(index, "params", paramErrors)
+	}
+	if len(validationErrors) > 0 {
+		return validationErrors
+	}
+
+	// Run the validations of the merged configuration, such as the validation of the selected profile
+	configAggregator, err := NewConfigAggregator(&configData, Git{}, &coreconfig.ServerDetails{}, true)
+	if err == nil {
+		if _, err = getConfiguredProxy(configAggregator); err == nil {
+			if _, err = getConfiguredCaCert(configAggregator); err == nil {
+				_, err = getConfiguredTempDir(configAggregator)
+			}
+		}
+	}
+	if err != nil {
+		validationErrors = append(validationErrors, ConfigValidationError{Message: err.Error()})
+	}
+	return validationErrors
+}
+
+// validate returns all the invalid parameters, with their paths relative to the params section
+func (p *Params) validate() (paramErrors []paramError) {
+	addError := func(err error, path ...any) {
+		if err != nil {
+			paramErrors = append(paramErrors, paramError{path: path, err: err})
+		}
+	}
+	if p.Proxy != "" {
+		_, err := parseProxyUrl(p.Proxy)
+		addError(err, "proxy")
+	}
+	addError(validateCustomHeaders(p.CustomHeaders), "customHeaders")
+	addError(validateUserAgent(p.UserAgent), "userAgent")
+	addError(p.validateReportTarget(), "reportTarget")
+	addError(p.validateUpgradeStrategy(), "upgradeStrategy")
+	addError(p.validateFixPRGrouping(), "fixPRGrouping")
+	addError(p.validateCommentStyle(), "commentStyle")
+	addError(p.validateNewIssuesBaseline(), "newIssuesBaseline")
+	addError(p.validateUnknownSeverityAs(), "unknownSeverityAs")
+	addError(p.validateWriteStepSummary(), "writeStepSummary")
+	addError(p.validateScanMode(), "scanMode")
+	addError(p.validateSectionOrder(), "sectionOrder")
+	addError(p.validateXrayFailoverUrls(), "xrayFailoverUrls")
+	addError(p.validateOtelEndpoint(), "otelEndpoint")
+	addError(p.validateOtelHeaders(), "otelHeaders")
+	addError(p.validateFailOnVulnsOlderThanDays(), "failOnVulnsOlderThanDays")
+	addError(p.validateRepoDownloadAttempts(), "repoDownloadAttempts")
+	addError(p.validateScanCache(), "scanCache")
+	addError(p.validateMaxCommentLength(), "maxCommentLength")
+	addError(p.validateFixPRBranches(), "fixPRBranches")
+	addError(p.validatePathIgnores(), "pathIgnores")
+	addError(p.validateAutoDetectExcludes(), "autoDetectExcludes")
+	addError(p.validateSeverityColors(), "severityColors")
+	_, err := p.getTitledReviewersBySeverity()
+	addError(err, "reviewersBySeverity")
+	addError(p.validateMinCvss(), "minCvss")
+	_, err = p.loadCommentTemplate()
+	addError(err, "commentTemplatePath")
+	for _, paramError := range p.SeverityPolicy.validate() {
+		addError(paramError.err, append([]any{"scan"}, paramError.path...)...)
+	}
+	for ecosystem, policy := range p.EcosystemPolicies {
+		if !isSupportedEcosystem(ecosystem) {
+			addError(fmt.Errorf(errInvalidEcosystemPolicy, ecosystem, formatSupportedEcosystems()), "scan", "ecosystemPolicies", ecosystem)
+		}
+		for _, paramError := range policy.validate() {
+			addError(paramError.err, append([]any{"scan", "ecosystemPolicies", ecosystem}, paramError.path...)...)
+		}
+	}
+	for index, entry := range p.IgnoredIssues {
+		_, err := ParseIgnoredIssue(entry)
+		addError(err, "scan", "ignoredIssues", index)
+	}
+	for index, entry := range p.IgnoredDependencies {
+		_, err := ParseIgnoredDependency(entry)
+		addError(err, "scan", "ignoredDependencies", index)
+	}
+	for index := range p.Projects {
+		for _, paramError := range p.Projects[index].SeverityPolicy.validate() {
+			addError(paramError.err, append([]any{"scan", "projects", index}, paramError.path...)...)
+		}
+		for entryIndex, entry := range p.Projects[index].IgnoredIssues {
+			_, err := ParseIgnoredIssue(entry)
+			addError(err, "scan", "projects", index, "ignoredIssues", entryIndex)
+		}
+		addError(p.Projects[index].validateEcosystems(), "scan", "projects", index, "ecosystems")
+		addError(p.Projects[index].validateDirectDependencies(), "scan", "projects", index, "directDependencies")
+	}
+	for name, profile := range p.Profiles {
+		for _, paramError := range profile.SeverityPolicy.validate() {
+			addError(paramError.err, append([]any{"profiles", name}, paramError.path...)...)
+		}
+	}
+	return
+}
+
+func (sp *SeverityPolicy) validate() (paramErrors []paramError) {
+	if err := validateSeverity("minSeverity", sp.MinSeverity); err != nil {
+		paramErrors = append(paramErrors, paramError{path: []any{"minSeverity"}, err: err})
+	}
+	if err := validateSeverity("failSeverityThreshold", sp.FailSeverityThreshold); err != nil {
+		paramErrors = append(paramErrors, paramError{path: []any{"failSeverityThreshold"}, err: err})
+	}
+	return
+}
+
+// Split the yaml parsing errors, which may include several errors with their line numbers
+func yamlErrorToValidationErrors(err error) (validationErrors []ConfigValidationError) {
+	var messages []string
+	var typeError *yaml.TypeError
+	if errors.As(err, &typeError) {
+		messages = typeError.Errors
+	} else {
+		messages = []string{err.Error()}
+	}
+	for _, message := range messages {
+		validationError := ConfigValidationError{Message: message}
+		if match := yamlErrorLineRegex.FindStringSubmatch(strings.TrimSpace(message)); match != nil {
+			validationError.Line, _ = strconv.Atoi(match[1])
+			validationError.Message = match[2]
+		}
+		validationErrors = append(validationErrors, validationError)
+	}
+	return
+}
+
+// findLine returns the line of the deepest node found on the given path of sequence indexes and mapping keys
+func findLine(document *yaml.Node, path []any) int {
+	node := document
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	line := node.Line
+	for _, step := range path {
+		var next *yaml.Node
+		switch key := step.(type) {
+		case int:
+			if node.Kind == yaml.SequenceNode && key < len(node.Content) {
+				next = node.Content[key]
+				line = next.Line
+			}
+		case string:
+			for i := 0; node.Kind == yaml.MappingNode && i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == key {
+					// The line of the key, since the value may be a nested block
+					line = node.Content[i].Line
+					next = node.Content[i+1]
+					break
+				}
+			}
+		}
+		if next == nil {
+			break
+		}
+		node = next
+	}
+	return line
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateConfigContent(t *testing.T) {
+	configContent := `
+- defaults:
+    scan:
+      minSeverity: Severe
+- params:
+    git:
+      branches: [master]
+    scan:
+      failSeverityThreshold: Hgh
+      projects:
+        - workingDirs: [payments]
+          minSeverity: Lowest
+      ecosystemPolicies:
+        cargo:
+          failSeverityThreshold: High
+        npm:
+          failSeverityThreshold: Med
+    proxy: proxy.example.com
+    upgradeStrategy: major
+    failOnVulnsOlderThanDays: -30
+`
+	validationErrors := validateConfigContent([]byte(configContent))
+	assert.ElementsMatch(t, []ConfigValidationError{
+		{Line: 4, Message: "the severity 'Severe' set in minSeverity is invalid. The supported severities are Low, Medium, High and Critical"},
+		{Line: 18, Message: "the proxy URL 'proxy.example.com' is invalid. A URL such as http://proxy.example.com:8080 is expected"},
+		{Line: 19, Message: "the upgrade strategy 'major' is invalid. The supported upgrade strategies are minimal, minor and latest"},
+		{Line: 20, Message: "the failOnVulnsOlderThanDays value of -30 days is invalid. A non-negative number of days is expected"},
+		{Line: 14, Message: "the ecosystem 'cargo' of ecosystemPolicies is invalid. The supported ecosystems are dotnet, go, gradle, maven, npm, nuget, pip, pipenv, poetry and yarn"},
+		{Line: 17, Message: "the severity 'Med' set in failSeverityThreshold is invalid. The supported severities are Low, Medium, High and Critical"},
+		{Line: 9, Message: "the severity 'Hgh' set in failSeverityThreshold is invalid. The supported severities are Low, Medium, High and Critical"},
+		{Line: 12, Message: "the severity 'Lowest' set in minSeverity is invalid. The supported severities are Low, Medium, High and Critical"},
+		{Line: 6, Message: errMissingRepoName},
+	}, validationErrors)
+}
+
+func TestValidateConfigContentParsing(t *testing.T) {
+	// All the unknown fields are reported
+	validationErrors := validateConfigContent([]byte(`
+- params:
+    git:
+      repoName: frogbot
+      repoNames: [frogbot]
+    scan:
+      failOnSecurityIssue: true
+`))
+	if assert.Len(t, validationErrors, 2) {
+		assert.Equal(t, 5, validationErrors[0].Line)
+		assert.Contains(t, validationErrors[0].Message, "repoNames")
+		assert.Equal(t, 7, validationErrors[1].Line)
+		assert.Contains(t, validationErrors[1].Message, "failOnSecurityIssue")
+	}
+
+	validationErrors = validateConfigContent([]byte("- params:\n  git: [\n"))
+	if assert.Len(t, validationErrors, 1) {
+		assert.NotZero(t, validationErrors[0].Line)
+	}
+
+	assert.Equal(t, []ConfigValidationError{{Message: errEmptyConfig}}, validateConfigContent([]byte("")))
+}
+
+func TestValidateConfigContentMergedConfig(t *testing.T) {
+	configContent := `
+- params:
+    git:
+      repoName: frogbot
+    tempDir: /tmp/a
+- params:
+    git:
+      repoName: frogbot-2
+    tempDir: /tmp/b
+`
+	assert.Equal(t, []ConfigValidationError{{Message: errMultipleTempDirs}}, validateConfigContent([]byte(configContent)))
+}
+
+func TestValidateConfigFile(t *testing.T) {
+	validationErrors, err := ValidateConfigFile(filepath.Join("..", "testdata", "config", "frogbot-config-test-params.yml"))
+	assert.NoError(t, err)
+	assert.Empty(t, validationErrors)
+
+	_, err = ValidateConfigFile(filepath.Join(t.TempDir(), "frogbot-config.yml"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestConfigValidationErrorString(t *testing.T) {
+	assert.Equal(t, "line 3: invalid value", ConfigValidationError{Line: 3, Message: "invalid value"}.String())
+	assert.Equal(t, "invalid value", ConfigValidationError{Message: "invalid value"}.String())
+	assert.Equal(t, "a.yml: line 3: invalid value", ConfigValidationError{File: "a.yml", Line: 3, Message: "invalid value"}.String())
+}
@@ -0,0 +1,46 @@
+package utils
+
+import "strings"
+
+// versionRange is a semver range, such as ">=0.7.29 <0.8.0 || 1.0.x", with alternative sets of constraints separated by "||".
+// A version is in the range if it satisfies all the constraints of one of the sets.
+type versionRange [][]versionConstraint
+
+func parseVersionRange(semverRange string) (versionRange, error) {
+	var parsedRange versionRange
+	for _, alternative := range strings.Split(semverRange, "||") {
+		constraints, err := parseVersionConstraints(alternative)
+		if err != nil {
+			return nil, err
+		}
+		parsedRange = append(parsedRange, constraints)
+	}
+	return parsedRange, nil
+}
+
+func (vr versionRange) contains(dependencyVersion string) bool {
+	dependencyVersion = strings.TrimPrefix(dependencyVersion, "v")
+	for _, constraints := range vr {
+		satisfied := true
+		for index := range constraints {
+			if !constraints[index].isSatisfiedBy(dependencyVersion) {
+				satisfied = false
+				break
+			}
+		}
+		if satisfied {
+			return true
+		}
+	}
+	return false
+}
+
+// IsVersionInRange returns true if the version is in the semver range, such as ">=0.7.29 <0.8.0 || 1.0.x".
+// An error is returned if the range isn't a semver range, such as a git URL or a dist tag.
+func IsVersionInRange(dependencyVersion, semverRange string) (bool, error) {
+	parsedRange, err := parseVersionRange(semverRange)
+	if err != nil {
+		return false, err
+	}
+	return parsedRange.contains(dependencyVersion), nil
+}
@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsVersionInRange(t *testing.T) {
+	for _, dependencyVersion := range []string{"1.2.0", "1.9.9", "v3.0.5"} {
+		inRange, err := IsVersionInRange(dependencyVersion, "^1.2.0 || >=3.0.0 <3.1.0")
+		assert.NoError(t, err)
+		assert.True(t, inRange, dependencyVersion)
+	}
+	for _, dependencyVersion := range []string{"1.1.9", "2.0.0", "3.1.0"} {
+		inRange, err := IsVersionInRange(dependencyVersion, "^1.2.0 || >=3.0.0 <3.1.0")
+		assert.NoError(t, err)
+		assert.False(t, inRange, dependencyVersion)
+	}
+	_, err := IsVersionInRange("1.2.6", "git+https://github.com/minimistjs/minimist.git")
+	assert.Error(t, err)
+}
@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const (
+	gitHubSignatureHeader = "X-Hub-Signature-256"
+	gitHubSignaturePrefix = "sha256="
+	gitLabTokenHeader     = "X-Gitlab-Token"
+	// The size limit of the payloads of the webhook events, which is the limit of GitHub. Larger payloads are rejected before they are read in full.
+	maxWebhookPayloadSize = 25 << 20
+)
+
+var errWebhookPayloadTooLarge = fmt.Errorf("the payload of the webhook event exceeds %d bytes", maxWebhookPayloadSize)
+
+// The secret of the webhooks which trigger Frogbot, as set in the JF_WEBHOOK_SECRET environment variable, or in the file of JF_WEBHOOK_SECRET_FILE
+func getWebhookSecret() (string, error) {
+	secret, err := getSecretEnv(WebhookSecretEnv)
+	if err != nil {
+		return "", err
+	}
+	if secret == "" {
+		return "", &ErrMissingEnv{VariableName: WebhookSecretEnv}
+	}
+	return secret, nil
+}
+
+// VerifyWebhookRequest verifies that the webhook event was sent by the git provider, before it is processed, and returns the payload of the event.
+// GitHub events are signed with an HMAC SHA-256 of the payload in the X-Hub-Signature-256 header, and GitLab events hold the secret in the X-Gitlab-Token header.
+// Unsigned events, and events whose payload exceeds 25 MB, are rejected too. The body of the request is restored, so that the event can be parsed after the verification.
+func VerifyWebhookRequest(provider vcsutils.VcsProvider, secret string, request *http.Request) ([]byte, error) {
+	if secret == "" {
+		return nil, errors.New("the webhook secret isn't configured, so the webhook events can't be verified")
+	}
+	var payload []byte
+	if request.Body != nil {
+		var err error
+		if payload, err = io.ReadAll(io.LimitReader(request.Body, maxWebhookPayloadSize+1)); err != nil {
+			return nil, err
+		}
+		if len(payload) > maxWebhookPayloadSize {
+			return nil, errWebhookPayloadTooLarge
+		}
+		request.Body = io.NopCloser(bytes.NewReader(payload))
+	}
+	switch provider {
+	case vcsutils.GitHub:
+		if err := verifyGitHubSignature(secret, request.Header.Get(gitHubSignatureHeader), payload); err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case vcsutils.GitLab:
+		token := request.Header.Get(gitLabTokenHeader)
+		if token == "" {
+			return nil, fmt.Errorf("the %s header is missing", gitLabTokenHeader)
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+			return nil, fmt.Errorf("the %s header doesn't match the webhook secret", gitLabTokenHeader)
+		}
+		return payload, nil
+	default:
+		return nil, fmt.Errorf("the verification of webhook events isn't supported for %s", provider.String())
+	}
+}
+
+func verifyGitHubSignature(secret, signature string, payload []byte) error {
+	if signature == "" {
+		return fmt.Errorf("the %s header is missing", gitHubSignatureHeader)
+	}
+	actualSignature, err := hex.DecodeString(strings.TrimPrefix(signature, gitHubSignaturePrefix))
+	if err != nil || !strings.HasPrefix(signature, gitHubSignaturePrefix) {
+		return fmt.Errorf("the %s header is invalid", gitHubSignatureHeader)
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	if !hmac.Equal(actualSignature, mac.Sum(nil)) {
+		return fmt.Errorf("the %s header doesn't match the payload", gitHubSignatureHeader)
+	}
+	return nil
+}
+
+// NewWebhookVerificationHandler returns a handler which passes the webhook events to the next handler only if they are verified with the secret of JF_WEBHOOK_SECRET.
+// The other events are rejected with 401 Unauthorized, or with 413 Request Entity Too Large if their payload exceeds the size limit.
+func NewWebhookVerificationHandler(provider vcsutils.VcsProvider, next http.Handler) (http.Handler, error) {
+	secret, err := getWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if _, err := VerifyWebhookRequest(provider, secret, request); err != nil {
+			log.Warn("rejected a webhook event:", err.Error())
+			status := http.StatusUnauthorized
+			if errors.Is(err, errWebhookPayloadTooLarge) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			http.Error(writer, http.StatusText(status), status)
+			return
+		}
+		next.ServeHTTP(writer, request)
+	}), nil
+}
@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jfrog/froggit-go/vcsutils"
+	"github.com/stretchr/testify/assert"
+)
+
+const testWebhookPayload = `{"action": "opened"}`
+
+func createSignedGitHubRequest(secret string) *http.Request {
+	request := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(testWebhookPayload))
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(testWebhookPayload))
+	request.Header.Set(gitHubSignatureHeader, gitHubSignaturePrefix+hex.EncodeToString(mac.Sum(nil)))
+	return request
+}
+
+func TestVerifyGitHubWebhookRequest(t *testing.T) {
+	request := createSignedGitHubRequest("secret")
+	payload, err := VerifyWebhookRequest(vcsutils.GitHub, "secret", request)
+	assert.NoError(t, err)
+	assert.Equal(t, testWebhookPayload, string(payload))
+	// The body is restored, so that the event can be parsed after the verification
+	body, err := io.ReadAll(request.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, testWebhookPayload, string(body))
+
+	_, err = VerifyWebhookRequest(vcsutils.GitHub, "other-secret", createSignedGitHubRequest("secret"))
+	assert.EqualError(t, err, "the X-Hub-Signature-256 header doesn't match the payload")
+
+	unsignedRequest := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(testWebhookPayload))
+	_, err = VerifyWebhookRequest(vcsutils.GitHub, "secret", unsignedRequest)
+	assert.EqualError(t, err, "the X-Hub-Signature-256 header is missing")
+
+	invalidRequest := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(testWebhookPayload))
+	invalidRequest.Header.Set(gitHubSignatureHeader, "sha1=1234")
+	_, err = VerifyWebhookRequest(vcsutils.GitHub, "secret", invalidRequest)
+	assert.EqualError(t, err, "the X-Hub-Signature-256 header is invalid")
+}
+
+func TestVerifyGitLabWebhookRequest(t *testing.T) {
+	request := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(testWebhookPayload))
+	request.Header.Set(gitLabTokenHeader, "secret")
+	payload, err := VerifyWebhookRequest(vcsutils.GitLab, "secret", request)
+	assert.NoError(t, err)
+	assert.Equal(t, testWebhookPayload, string(payload))
+
+	_, err = VerifyWebhookRequest(vcsutils.GitLab, "other-secret", request)
+	assert.EqualError(t, err, "the X-Gitlab-Token header doesn't match the webhook secret")
+
+	_, err = VerifyWebhookRequest(vcsutils.GitLab, "secret", httptest.NewRequest(http.MethodPost, "/webhook", nil))
+	assert.EqualError(t, err, "the X-Gitlab-Token header is missing")
+
+	// The events can't be verified without the secret
+	_, err = VerifyWebhookRequest(vcsutils.GitLab, "", request)
+	assert.Error(t, err)
+	_, err = VerifyWebhookRequest(vcsutils.BitbucketServer, "secret", request)
+	assert.Error(t, err)
+}
+
+func TestVerifyWebhookRequestPayloadSize(t *testing.T) {
+	request := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(strings.Repeat("a", maxWebhookPayloadSize+1)))
+	request.Header.Set(gitLabTokenHeader, "secret")
+	_, err := VerifyWebhookRequest(vcsutils.GitLab, "secret", request)
+	assert.ErrorIs(t, err, errWebhookPayloadTooLarge)
+}
+
+func TestWebhookVerificationHandler(t *testing.T) {
+	nextHandler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {})
+	t.Setenv(WebhookSecretEnv, "")
+	_, err := NewWebhookVerificationHandler(vcsutils.GitHub, nextHandler)
+	assert.EqualError(t, err, "'JF_WEBHOOK_SECRET' environment variable is missing")
+
+	t.Setenv(WebhookSecretEnv, "secret")
+	handled := false
+	handler, err := NewWebhookVerificationHandler(vcsutils.GitHub, http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		handled = true
+		writer.WriteHeader(http.StatusAccepted)
+	}))
+	assert.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, createSignedGitHubRequest("secret"))
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+	assert.True(t, handled)
+
+	// Spoofed events are rejected before they are processed
+	handled = false
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, createSignedGitHubRequest("spoofed"))
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.False(t, handled)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(strings.Repeat("a", maxWebhookPayloadSize+1))))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+	assert.False(t, handled)
+}
+
+func TestGetWebhookSecret(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	assert.NoError(t, os.WriteFile(secretFile, []byte("file-secret\n"), 0600))
+	t.Setenv(WebhookSecretEnv, "")
+	t.Setenv(WebhookSecretEnv+secretFileEnvSuffix, secretFile)
+	secret, err := getWebhookSecret()
+	assert.NoError(t, err)
+	assert.Equal(t, "file-secret", secret)
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+// The dirs which hold installed or vendored dependencies, rather than modules of the repository
+var skippedWorkingDirsNames = map[string]bool{"node_modules": true, "vendor": true}
+
+// DetectWorkingDirs walks the repository, and returns the dirs which include a dependency manifest, such as package.json or go.mod, relative to the repository.
+// The hidden dirs, the dirs of installed dependencies and the dirs which match the exclude patterns are skipped, together with the dirs under them.
+// The modules of Maven and Gradle builds are audited by the build in their parent dir, so they aren't returned separately.
+func DetectWorkingDirs(repoDir string, excludes []string) ([]string, error) {
+	var workingDirs, buildDirs []string
+	err := filepath.WalkDir(repoDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == repoDir {
+				return err
+			}
+			// An unreadable dir, such as a dir without permissions, doesn't fail the detection of the other dirs
+			log.Warn("couldn't read", path, "while detecting the working directories, so it's skipped:", err.Error())
+			return filepath.SkipDir
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		relativePath, err := filepath.Rel(repoDir, path)
+		if err != nil {
+			return err
+		}
+		relativePath = filepath.ToSlash(relativePath)
+		if relativePath != RootDir && (strings.HasPrefix(entry.Name(), ".") || skippedWorkingDirsNames[entry.Name()] || matchesPathPatterns(relativePath, excludes)) {
+			return filepath.SkipDir
+		}
+		detected, err := coreutils.DetectTechnologies(path, false, false)
+		if err != nil {
+			log.Warn("couldn't detect the technologies of", path+", so it's skipped:", err.Error())
+			return filepath.SkipDir
+		}
+		if len(detected) == 0 {
+			return nil
+		}
+		if isBuildModule(detected, relativePath, buildDirs) {
+			return nil
+		}
+		if detected[coreutils.Maven] || detected[coreutils.Gradle] {
+			buildDirs = append(buildDirs, relativePath)
+		}
+		workingDirs = append(workingDirs, relativePath)
+		return nil
+	})
+	return workingDirs, err
+}
+
+// Return true if all the technologies of the dir are Maven or Gradle, and it's under a dir with a Maven or Gradle build
+func isBuildModule(detected map[coreutils.Technology]bool, relativePath string, buildDirs []string) bool {
+	for tech := range detected {
+		if tech != coreutils.Maven && tech != coreutils.Gradle {
+			return false
+		}
+	}
+	for _, buildDir := range buildDirs {
+		if buildDir == RootDir || strings.HasPrefix(relativePath, buildDir+"/") {
+			return true
+		}
+	}
+	return false
+}
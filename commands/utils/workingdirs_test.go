@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createWorkingDirsTestRepo(t *testing.T, files ...string) string {
+	repoDir := t.TempDir()
+	for _, file := range files {
+		fullPath := filepath.Join(repoDir, filepath.FromSlash(file))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(fullPath), 0755))
+		assert.NoError(t, os.WriteFile(fullPath, []byte{}, 0644))
+	}
+	return repoDir
+}
+
+func TestDetectWorkingDirs(t *testing.T) {
+	repoDir := createWorkingDirsTestRepo(t,
+		"README.md",
+		"services/api/go.mod",
+		"services/web/package.json",
+		"services/web/node_modules/lodash/package.json",
+		"java/pom.xml",
+		// A module of the Maven build of its parent dir
+		"java/core/pom.xml",
+		// Not a module of the Maven build
+		"java/ui/package.json",
+		"examples/demo/requirements.txt",
+		".github/actions/setup/package.json",
+		"vendor/github.com/pkg/go.mod",
+	)
+	workingDirs, err := DetectWorkingDirs(repoDir, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"examples/demo", "java", "java/ui", "services/api", "services/web"}, workingDirs)
+
+	workingDirs, err = DetectWorkingDirs(repoDir, []string{"examples/", "services/w*"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"java", "java/ui", "services/api"}, workingDirs)
+
+	// The root of the repository is a working dir too
+	repoDir = createWorkingDirsTestRepo(t, "go.mod", "tools/go.mod")
+	workingDirs, err = DetectWorkingDirs(repoDir, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{RootDir, "tools"}, workingDirs)
+}
+
+func TestDetectWorkingDirsUnreadableDir(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("the permissions of the dirs don't apply to root")
+	}
+	repoDir := createWorkingDirsTestRepo(t, "api/go.mod", "secret/go.mod", "web/package.json")
+	assert.NoError(t, os.Chmod(filepath.Join(repoDir, "secret"), 0))
+	defer func() {
+		assert.NoError(t, os.Chmod(filepath.Join(repoDir, "secret"), 0755))
+	}()
+	// The unreadable dir is skipped, and the other dirs are still detected
+	workingDirs, err := DetectWorkingDirs(repoDir, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"api", "web"}, workingDirs)
+}
+
+func TestValidateAutoDetectExcludes(t *testing.T) {
+	params := Params{AutoDetectExcludes: []string{"examples/", "tools/*/legacy"}}
+	assert.NoError(t, params.validateAutoDetectExcludes())
+	params.AutoDetectExcludes = []string{"examples/[a-"}
+	assert.EqualError(t, params.validateAutoDetectExcludes(), "the autoDetectExcludes pattern 'examples/[a-' is invalid")
+}
+
+func TestExpandProjectsAutoDetectWorkingDirs(t *testing.T) {
+	params := Params{
+		Scan:                  Scan{Projects: []Project{{}, {WorkingDirs: []string{"a"}}}},
+		AutoDetectWorkingDirs: true,
+		AutoDetectExcludes:    []string{"examples/"},
+	}
+	assert.NoError(t, params.expandProjects())
+	// Explicit working dirs take precedence
+	assert.True(t, params.Projects[0].AutoDetectWorkingDirs)
+	assert.Equal(t, []string{"examples/"}, params.Projects[0].AutoDetectExcludes)
+	assert.False(t, params.Projects[1].AutoDetectWorkingDirs)
+}
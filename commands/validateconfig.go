@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+)
+
+const errInvalidConfig = "found %d errors in the frogbot-config file"
+
+type ValidateConfigCmd struct {
+	// The paths of the frogbot-config files, directories or glob patterns. If empty, .frogbot/frogbot-config.yml is used.
+	ConfigPaths []string
+}
+
+// Run validates the frogbot-config file and logs all the errors found.
+// Unlike the scan commands, it requires neither a VCS client nor the JFrog Platform details.
+func (cmd ValidateConfigCmd) Run() error {
+	validationErrors, err := utils.ValidateConfigFile(cmd.ConfigPaths...)
+	if err != nil {
+		return err
+	}
+	for _, validationError := range validationErrors {
+		log.Error(validationError.String())
+	}
+	if len(validationErrors) > 0 {
+		return fmt.Errorf(errInvalidConfig, len(validationErrors))
+	}
+	log.Info("The frogbot-config file is valid")
+	return nil
+}
@@ -0,0 +1,203 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	coreconfig "github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	servicesutils "github.com/jfrog/jfrog-client-go/artifactory/services/utils"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+)
+
+const (
+	vulnerabilitiesAgeTitle = "#### ⏳ Vulnerabilities age"
+	vulnerabilitiesAgeFail  = "❌ %d of the new vulnerabilities were added to the Xray vulnerabilities database more than %d days ago, which fails the scan."
+	// The Xray API which returns the issues of components by their IDs, with the dates the issues were created in the Xray database
+	componentSummaryApi = "api/v1/summary/component"
+)
+
+type componentSummaryParams struct {
+	ComponentDetails []componentDetails `json:"component_details"`
+}
+
+type componentDetails struct {
+	ComponentId string `json:"component_id"`
+}
+
+// The graph scan results don't include the dates of the issues, so they're read from the component summary of each vulnerable component.
+// The component summary doesn't include the dates the CVEs were published, so the date of an issue is the date it was created in the Xray vulnerabilities database.
+// It's usually close to the date the vulnerability was disclosed, but it's later for the vulnerabilities which were added to the database after their disclosure.
+// Return the dates by the Xray issue IDs and by the CVE IDs.
+func getIssuesPublishedDates(server *coreconfig.ServerDetails, componentIds []string) (map[string]time.Time, error) {
+	if len(componentIds) == 0 {
+		return nil, nil
+	}
+	xrayManager, err := utils.NewXrayServiceManager(server)
+	if err != nil {
+		return nil, err
+	}
+	params := componentSummaryParams{}
+	for _, componentId := range componentIds {
+		params.ComponentDetails = append(params.ComponentDetails, componentDetails{ComponentId: componentId})
+	}
+	requestBody, err := json.Marshal(params)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	xrayDetails := xrayManager.Config().GetServiceDetails()
+	httpDetails := xrayDetails.CreateHttpClientDetails()
+	servicesutils.SetContentType("application/json", &httpDetails.Headers)
+	resp, body, err := xrayManager.Client().SendPost(xrayDetails.GetUrl()+componentSummaryApi, requestBody, &httpDetails)
+	if err != nil {
+		return nil, err
+	}
+	if err = errorutils.CheckResponseStatusWithBody(resp, body, http.StatusOK); err != nil {
+		return nil, err
+	}
+	var response services.ArtifactSummaryResponse
+	if err = json.Unmarshal(body, &response); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	return parsePublishedDates(response), nil
+}
+
+func parsePublishedDates(response services.ArtifactSummaryResponse) map[string]time.Time {
+	publishedDates := make(map[string]time.Time)
+	for _, artifact := range response.Artifacts {
+		for _, issue := range artifact.Issues {
+			published, err := time.Parse(time.RFC3339, issue.Created)
+			if err != nil {
+				continue
+			}
+			publishedDates[issue.IssueId] = published
+			for _, cve := range issue.Cves {
+				if cve.Id != "" {
+					publishedDates[cve.Id] = published
+				}
+			}
+		}
+	}
+	return publishedDates
+}
+
+// Return the sorted IDs of the components in the scans, which are impacted by the given issues
+func getImpactedComponentIds(scans []services.ScanResponse, vulnerabilitiesRows []formats.VulnerabilityOrViolationRow) []string {
+	issueIds := make(map[string]bool)
+	for _, row := range vulnerabilitiesRows {
+		issueIds[row.IssueId] = true
+	}
+	found := make(map[string]bool)
+	var componentIds []string
+	addComponents := func(issueId string, components map[string]services.Component) {
+		if !issueIds[issueId] {
+			return
+		}
+		for componentId := range components {
+			if !found[componentId] {
+				found[componentId] = true
+				componentIds = append(componentIds, componentId)
+			}
+		}
+	}
+	for _, scan := range scans {
+		for _, vulnerability := range scan.Vulnerabilities {
+			addComponents(vulnerability.IssueId, vulnerability.Components)
+		}
+		for _, violation := range scan.Violations {
+			addComponents(violation.IssueId, violation.Components)
+		}
+	}
+	sort.Strings(componentIds)
+	return componentIds
+}
+
+// Return the date the issue was created in the Xray database, by its Xray issue ID or by one of its CVEs
+func getPublishedDate(row formats.VulnerabilityOrViolationRow, publishedDates map[string]time.Time) (time.Time, bool) {
+	if published, exists := publishedDates[row.IssueId]; exists {
+		return published, true
+	}
+	for _, cve := range row.Cves {
+		if published, exists := publishedDates[cve.Id]; exists {
+			return published, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func getAgeInDays(published, now time.Time) int {
+	if now.Before(published) {
+		return 0
+	}
+	return int(now.Sub(published).Hours() / 24)
+}
+
+func formatAge(days int) string {
+	switch days {
+	case 0:
+		return "added to Xray today"
+	case 1:
+		return "added to Xray 1 day ago"
+	default:
+		return fmt.Sprintf("added to Xray %d days ago", days)
+	}
+}
+
+// Return the number of issues of the projects which aren't path ignored, which were created in the Xray database more than maxDays ago
+func countOlderVulnerabilities(vulnerabilitiesRows []formats.VulnerabilityOrViolationRow, publishedDates map[string]time.Time, pathIgnoredIssues map[string]bool, maxDays int, now time.Time) (count int) {
+	for _, row := range vulnerabilitiesRows {
+		if pathIgnoredIssues[getUniqueID(row)] {
+			continue
+		}
+		if published, exists := getPublishedDate(row, publishedDates); exists && getAgeInDays(published, now) > maxDays {
+			count++
+		}
+	}
+	return
+}
+
+// Create a note which shows how long ago each new vulnerability was added to the Xray database, and whether the vulnerabilities which are too old fail the scan
+func createVulnerabilitiesAgeNote(vulnerabilitiesRows []formats.VulnerabilityOrViolationRow, publishedDates map[string]time.Time, olderVulnerabilitiesCount, maxDays int, now time.Time) string {
+	var notes strings.Builder
+	for _, row := range vulnerabilitiesRows {
+		published, exists := getPublishedDate(row, publishedDates)
+		if !exists {
+			continue
+		}
+		notes.WriteString(fmt.Sprintf("- **%s %s** (%s): %s\n", row.ImpactedDependencyName, row.ImpactedDependencyVersion, getIssueDisplayId(row), formatAge(getAgeInDays(published, now))))
+	}
+	if notes.Len() == 0 {
+		return ""
+	}
+	note := "\n\n" + vulnerabilitiesAgeTitle + "\n\n"
+	if olderVulnerabilitiesCount > 0 {
+		note += fmt.Sprintf(vulnerabilitiesAgeFail, olderVulnerabilitiesCount, maxDays) + "\n\n"
+	}
+	return note + notes.String()
+}
+
+// addVulnerabilitiesAge reads the dates the new issues were created in the Xray database, and fails the scan if configured and an issue is older than failOnVulnsOlderThanDays.
+// The age is read only if showVulnerabilitiesAge or failOnVulnsOlderThanDays is set. It's an addition to the comment, so a failure to read the dates is logged rather than failing the scan.
+func (results *auditResults) addVulnerabilitiesAge(repoConfig *utils.FrogbotRepoConfig, now time.Time) {
+	if !repoConfig.ShowVulnerabilitiesAge && repoConfig.FailOnVulnsOlderThanDays == 0 {
+		return
+	}
+	componentIds := getImpactedComponentIds(results.scanResults, results.vulnerabilitiesRows)
+	publishedDates, err := getIssuesPublishedDates(&repoConfig.Server, componentIds)
+	if err != nil {
+		log.Warn("couldn't read the dates the issues were added to Xray:", err.Error())
+		return
+	}
+	results.publishedDates = publishedDates
+	if repoConfig.FailOnVulnsOlderThanDays > 0 {
+		results.olderVulnerabilitiesCount = countOlderVulnerabilities(results.vulnerabilitiesRows, publishedDates, results.pathIgnoredIssues, repoConfig.FailOnVulnsOlderThanDays, now)
+		results.failingIssuesFound = results.failingIssuesFound || results.olderVulnerabilitiesCount > 0
+	}
+}
@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jfrog/frogbot/commands/utils"
+	coreconfig "github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-cli-core/v2/xray/formats"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetIssuesPublishedDates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/xray/"+componentSummaryApi, r.URL.Path)
+		var params componentSummaryParams
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&params))
+		assert.Equal(t, []componentDetails{{ComponentId: "npm://lodash:4.17.15"}}, params.ComponentDetails)
+		_, err := fmt.Fprint(w, `{"artifacts": [{"issues": [
+			{"issue_id": "XRAY-140562", "created": "2021-02-15T11:06:40Z", "cves": [{"cve": "CVE-2021-23337"}]},
+			{"issue_id": "XRAY-1", "created": "unknown"}
+		]}]}`)
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	publishedDates, err := getIssuesPublishedDates(&coreconfig.ServerDetails{XrayUrl: server.URL + "/xray/"}, []string{"npm://lodash:4.17.15"})
+	assert.NoError(t, err)
+	published := time.Date(2021, 2, 15, 11, 6, 40, 0, time.UTC)
+	assert.Equal(t, map[string]time.Time{"XRAY-140562": published, "CVE-2021-23337": published}, publishedDates)
+
+	// No request is sent without components
+	publishedDates, err = getIssuesPublishedDates(&coreconfig.ServerDetails{XrayUrl: server.URL + "/xray/"}, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, publishedDates)
+}
+
+func TestGetImpactedComponentIds(t *testing.T) {
+	scans := []services.ScanResponse{{
+		Vulnerabilities: []services.Vulnerability{
+			{IssueId: "XRAY-1", Components: map[string]services.Component{"npm://lodash:4.17.15": {}, "npm://minimist:0.0.8": {}}},
+			{IssueId: "XRAY-2", Components: map[string]services.Component{"npm://axios:0.21.0": {}}},
+		},
+		Violations: []services.Violation{{IssueId: "XRAY-3", Components: map[string]services.Component{"npm://lodash:4.17.15": {}}}},
+	}}
+	rows := []formats.VulnerabilityOrViolationRow{{IssueId: "XRAY-1"}, {IssueId: "XRAY-3"}}
+	assert.Equal(t, []string{"npm://lodash:4.17.15", "npm://minimist:0.0.8"}, getImpactedComponentIds(scans, rows))
+}
+
+func TestCreateVulnerabilitiesAgeNote(t *testing.T) {
+	now := time.Date(2023, 4, 3, 12, 0, 0, 0, time.UTC)
+	rows := []formats.VulnerabilityOrViolationRow{
+		{ImpactedDependencyName: "lodash", ImpactedDependencyVersion: "4.17.15", IssueId: "XRAY-1", Cves: []formats.CveRow{{Id: "CVE-2021-23337"}}},
+		{ImpactedDependencyName: "minimist", ImpactedDependencyVersion: "0.0.8", IssueId: "XRAY-2"},
+		{ImpactedDependencyName: "axios", ImpactedDependencyVersion: "0.21.0", IssueId: "XRAY-3"},
+		{ImpactedDependencyName: "json5", ImpactedDependencyVersion: "1.0.1", IssueId: "XRAY-4"},
+	}
+	publishedDates := map[string]time.Time{
+		"CVE-2021-23337": now.AddDate(0, 0, -412),
+		"XRAY-2":         now.Add(-time.Hour),
+		"XRAY-3":         now.AddDate(0, 0, -1),
+	}
+	// The issues of path ignored projects don't fail the scan
+	assert.Equal(t, 1, countOlderVulnerabilities(rows, publishedDates, nil, 365, now))
+	assert.Equal(t, 0, countOlderVulnerabilities(rows, publishedDates, map[string]bool{getUniqueID(rows[0]): true}, 365, now))
+
+	expected := "\n\n" + vulnerabilitiesAgeTitle + "\n\n" +
+		"❌ 1 of the new vulnerabilities were added to the Xray vulnerabilities database more than 365 days ago, which fails the scan.\n\n" +
+		"- **lodash 4.17.15** (CVE-2021-23337): added to Xray 412 days ago\n" +
+		"- **minimist 0.0.8** (XRAY-2): added to Xray today\n" +
+		"- **axios 0.21.0** (XRAY-3): added to Xray 1 day ago\n"
+	assert.Equal(t, expected, createVulnerabilitiesAgeNote(rows, publishedDates, 1, 365, now))
+	assert.Empty(t, createVulnerabilitiesAgeNote(rows, nil, 0, 0, now))
+}
+
+func TestAddVulnerabilitiesAgeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	// A failure to read the dates doesn't fail the scan
+	results := &auditResults{
+		vulnerabilitiesRows: []formats.VulnerabilityOrViolationRow{{IssueId: "XRAY-1"}},
+		scanResults:         []services.ScanResponse{{Vulnerabilities: []services.Vulnerability{{IssueId: "XRAY-1", Components: map[string]services.Component{"npm://lodash:4.17.15": {}}}}}},
+	}
+	repoConfig := &utils.FrogbotRepoConfig{Server: coreconfig.ServerDetails{XrayUrl: server.URL + "/xray/"}, Params: utils.Params{FailOnVulnsOlderThanDays: 1}}
+	results.addVulnerabilitiesAge(repoConfig, time.Now())
+	assert.Nil(t, results.publishedDates)
+	assert.False(t, results.failingIssuesFound)
+}
+
+func TestAddVulnerabilitiesAgeDisabled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Xray shouldn't be requested, since the vulnerabilities age isn't enabled")
+	}))
+	defer server.Close()
+
+	results := &auditResults{
+		vulnerabilitiesRows: []formats.VulnerabilityOrViolationRow{{IssueId: "XRAY-1"}},
+		scanResults:         []services.ScanResponse{{Vulnerabilities: []services.Vulnerability{{IssueId: "XRAY-1", Components: map[string]services.Component{"npm://lodash:4.17.15": {}}}}}},
+	}
+	results.addVulnerabilitiesAge(&utils.FrogbotRepoConfig{Server: coreconfig.ServerDetails{XrayUrl: server.URL + "/xray/"}}, time.Now())
+	assert.Nil(t, results.publishedDates)
+}
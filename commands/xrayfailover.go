@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+
+	coreconfig "github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+)
+
+// The status of an Xray response in the errors of the audit, as formatted by jfrog-client-go, such as "server response: 503 Service Unavailable".
+// The errors of the audit are partly joined into a single message, so the status code is read from the message.
+var xrayResponseStatusRegex = regexp.MustCompile(`server response: (\d{3}) `)
+
+// The status codes of the responses of the proxies and the load balancers in front of an Xray which can't be reached
+var xrayUnavailableStatusCodes = map[int]bool{
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+	http.StatusGatewayTimeout:     true,
+}
+
+type xrayAuditFunc func(server *coreconfig.ServerDetails) ([]services.ScanResponse, bool, error)
+
+// auditWithFailover runs the audit against the Xray of the server, and retries it against the Xray failover URLs, in order, if Xray can't be reached.
+// Other errors, such as a failure to build the dependency trees, are returned without a retry.
+func auditWithFailover(server *coreconfig.ServerDetails, failoverUrls []string, audit xrayAuditFunc) (results []services.ScanResponse, isMultipleRoot bool, err error) {
+	results, isMultipleRoot, err = audit(server)
+	for _, failoverUrl := range failoverUrls {
+		if err == nil || !isXrayConnectivityError(err) {
+			break
+		}
+		log.Warn("couldn't reach the Xray at", server.XrayUrl+":", err.Error())
+		log.Info("Retrying the scan against the Xray at", failoverUrl)
+		failoverServer := *server
+		// Like the Xray URL of the server, the failover URL ends with a slash
+		failoverServer.XrayUrl = strings.TrimSuffix(failoverUrl, "/") + "/"
+		server = &failoverServer
+		results, isMultipleRoot, err = audit(server)
+	}
+	if err == nil && len(failoverUrls) > 0 {
+		log.Info("The Xray scan was served by the Xray at", server.XrayUrl)
+	}
+	return
+}
+
+// An error is a connectivity error if it's a network error, such as a refused connection, a DNS lookup failure or a timeout,
+// or if Xray responded with a status code which means it's unavailable
+func isXrayConnectivityError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	for _, match := range xrayResponseStatusRegex.FindAllStringSubmatch(err.Error(), -1) {
+		if statusCode, convErr := strconv.Atoi(match[1]); convErr == nil && xrayUnavailableStatusCodes[statusCode] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+
+	coreconfig "github.com/jfrog/jfrog-cli-core/v2/utils/config"
+	"github.com/jfrog/jfrog-client-go/xray/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsXrayConnectivityError(t *testing.T) {
+	assert.True(t, isXrayConnectivityError(&net.OpError{Op: "dial", Err: errors.New("refused")}))
+	assert.True(t, isXrayConnectivityError(fmt.Errorf("scan failed: %w", &net.DNSError{Err: "no such host", Name: "xray.example.com"})))
+	assert.True(t, isXrayConnectivityError(fmt.Errorf("scan failed: %w", syscall.ECONNREFUSED)))
+	assert.True(t, isXrayConnectivityError(errors.New("'npm' audit command in /tmp/wd failed:\nserver response: 503 Service Unavailable")))
+	assert.False(t, isXrayConnectivityError(errors.New("could not determine the package manager / build tool used by this project")))
+	assert.False(t, isXrayConnectivityError(errors.New("server response: 401 Unauthorized")))
+	// The descriptions of connectivity errors in other errors aren't connectivity errors
+	assert.False(t, isXrayConnectivityError(errors.New("the dependency 'connection refused' wasn't found")))
+	assert.False(t, isXrayConnectivityError(errors.New("server response: 400 Bad Request\n{\"message\": \"upstream 503 Service Unavailable\"}")))
+}
+
+func TestAuditWithFailover(t *testing.T) {
+	server := &coreconfig.ServerDetails{XrayUrl: "https://primary.example.com/xray/", AccessToken: "token"}
+	failoverUrls := []string{"https://secondary.example.com/xray/", "https://tertiary.example.com/xray"}
+	var auditedUrls []string
+	audit := func(unavailableUrls ...string) xrayAuditFunc {
+		return func(server *coreconfig.ServerDetails) ([]services.ScanResponse, bool, error) {
+			auditedUrls = append(auditedUrls, server.XrayUrl)
+			// The failover instances are scanned with the same credentials
+			assert.Equal(t, "token", server.AccessToken)
+			for _, unavailableUrl := range unavailableUrls {
+				if server.XrayUrl == unavailableUrl {
+					return nil, false, &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+				}
+			}
+			return []services.ScanResponse{{ScanId: server.XrayUrl}}, true, nil
+		}
+	}
+
+	// The scan is retried against the failover instances, in order, until Xray is reached
+	results, isMultipleRoot, err := auditWithFailover(server, failoverUrls, audit(server.XrayUrl, failoverUrls[0]))
+	assert.NoError(t, err)
+	assert.True(t, isMultipleRoot)
+	// A trailing slash is added to the failover URLs, like the Xray URL of the server
+	assert.Equal(t, []services.ScanResponse{{ScanId: "https://tertiary.example.com/xray/"}}, results)
+	assert.Equal(t, []string{server.XrayUrl, failoverUrls[0], "https://tertiary.example.com/xray/"}, auditedUrls)
+	// The server of the repository isn't changed
+	assert.Equal(t, "https://primary.example.com/xray/", server.XrayUrl)
+
+	// The failover instances aren't scanned if the primary Xray serves the scan
+	auditedUrls = nil
+	_, _, err = auditWithFailover(server, failoverUrls, audit())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{server.XrayUrl}, auditedUrls)
+
+	// The error of the last instance is returned if none are reachable
+	_, _, err = auditWithFailover(server, failoverUrls, audit(server.XrayUrl, failoverUrls[0], "https://tertiary.example.com/xray/"))
+	assert.ErrorContains(t, err, "connection refused")
+
+	// Other errors aren't retried
+	auditedUrls = nil
+	_, _, err = auditWithFailover(server, failoverUrls, func(server *coreconfig.ServerDetails) ([]services.ScanResponse, bool, error) {
+		auditedUrls = append(auditedUrls, server.XrayUrl)
+		return nil, false, errors.New("could not determine the package manager / build tool used by this project")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, []string{server.XrayUrl}, auditedUrls)
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 
 	"github.com/jfrog/frogbot/commands"
+	"github.com/jfrog/frogbot/commands/utils"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/coreutils"
 	"github.com/jfrog/jfrog-cli-core/v2/utils/log"
 	clitool "github.com/urfave/cli/v2"
@@ -17,6 +18,7 @@ func main() {
 }
 
 func ExecMain() error {
+	utils.FrogbotVersion = frogbotVersion
 	app := clitool.App{
 		Name:     "Frogbot",
 		Usage:    "See https://github.com/jfrog/frogbot for usage instructions.",
@@ -46,6 +46,7 @@ func TestBadFrogbotSchemas(t *testing.T) {
 		{"no-git", "git is required"},
 		{"no-repo", "repoName is required"},
 		{"empty-repo", "Expected: string, given: null"},
+		{"defaults-repo-name", "Additional property repoName is not allowed"},
 	}
 	for _, testCase := range testCases {
 		validateSchema(t, schemaLoader, filepath.Join("testdata", testCase.testName+".yml"), testCase.errorString)